@@ -0,0 +1,176 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/kraklabs/cie/internal/errors"
+	"github.com/kraklabs/cie/internal/ui"
+	"github.com/kraklabs/cie/pkg/ingestion"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// runAPIDiff executes the 'api-diff' CLI command, comparing the exported Go
+// API at a release ref with HEAD and failing with ExitBreakingChange when a
+// change can break code compiling against the release.
+//
+// Every Go file of the changed packages is read from git and parsed at both
+// refs, so embedded types declared in unchanged files are resolved; nothing
+// is checked out and the index is not needed.
+//
+// Examples:
+//
+//	cie api-diff v1.4.0
+//	cie api-diff v1.4.0 --path pkg/client
+//	cie --json api-diff v1.4.0 --head release/2.0
+func runAPIDiff(args []string, configPath string, globals GlobalFlags) {
+	fs := flag.NewFlagSet("api-diff", flag.ExitOnError)
+	head := fs.String("head", "HEAD", "Ref to compare with the base ref")
+	pathPrefix := fs.String("path", "", "Only compare packages under this directory")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: cie api-diff <ref> [options]
+
+Description:
+  Compare the exported Go API of the packages changed between a release ref
+  and HEAD, and fail when a change can break code compiling against the
+  release. Internal packages, main packages and tests are not compared.
+
+  Breaking:
+    Exported function, method, type, field, variable or constant removed
+    Parameter, result or type parameter types changed
+    Method receiver changed from value to pointer
+    Struct field type changed
+    Method added to an interface other packages can implement
+    Interface method, embedded interface or type set changed
+
+  Compatible:
+    Exported symbol or struct field added
+    Method added to a sealed interface (one with unexported methods)
+    Parameter renamed, receiver changed from pointer to value
+    Method moved to an embedded type with the same signature
+
+Exit codes:
+  0  No breaking change
+  7  Breaking changes found
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  cie api-diff v1.4.0
+  cie api-diff v1.4.0 --path pkg/client
+  cie --json api-diff v1.4.0 --head release/2.0
+
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	base := fs.Arg(0)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		errors.FatalError(errors.NewInternalError(
+			"Cannot determine working directory",
+			err.Error(),
+			"Run cie api-diff from inside the git repository",
+			err,
+		), globals.JSON)
+	}
+	git, err := tools.NewGitExecutor(cwd)
+	if err != nil {
+		errors.FatalError(errors.NewInputError(
+			"Git repository not detected",
+			err.Error(),
+			"Run cie api-diff from inside the git repository",
+		), globals.JSON)
+	}
+
+	report, err := ingestion.DiffAPI(context.Background(), git, base, *head, *pathPrefix)
+	if err != nil {
+		errors.FatalError(errors.NewInputError(
+			"Cannot compare the refs",
+			err.Error(),
+			"Pass refs git knows, such as a release tag, branch or commit",
+		), globals.JSON)
+	}
+
+	if globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	} else {
+		printAPIDiff(report)
+	}
+
+	if report.Breaking > 0 {
+		errors.FatalError(errors.NewBreakingChangeError(
+			fmt.Sprintf("%d breaking API changes since %s", report.Breaking, base),
+			"Exported functions, methods, types or fields changed incompatibly",
+			"Restore compatibility, or release a new major version if the break is intended",
+		), globals.JSON)
+	}
+}
+
+// printAPIDiff prints the breaking then the compatible API changes.
+func printAPIDiff(report *ingestion.APIDiffReport) {
+	fmt.Printf("%s %s..%s, %d files parsed\n", ui.Label("API diff:"), report.Base, report.Head, report.FilesParsed)
+	if len(report.Changes) == 0 {
+		ui.Success("No exported API change.")
+		return
+	}
+
+	for _, section := range []struct {
+		title    string
+		breaking bool
+		count    int
+	}{
+		{"Breaking", true, report.Breaking},
+		{"Compatible", false, report.Compatible},
+	} {
+		if section.count == 0 {
+			continue
+		}
+		ui.Header(fmt.Sprintf("%s (%d)", section.title, section.count))
+		for _, c := range report.Changes {
+			if c.Breaking != section.breaking {
+				continue
+			}
+			fmt.Printf("  %-40s %s %s\n", c.Package+"."+c.Symbol, c.Kind, ui.DimText(fmt.Sprintf("%s:%d", c.FilePath, c.Line)))
+			fmt.Printf("    %s\n", c.Detail)
+			if c.Before != "" && c.After != "" {
+				fmt.Printf("    - %s\n    + %s\n", c.Before, c.After)
+			}
+		}
+	}
+}
@@ -42,7 +42,7 @@ const bashCompletionTemplate = `#!/bin/bash
 
 _cie_completion() {
     local cur prev commands
-    commands="init index status query reset install-hook completion"
+    commands="init index status query coverage dead-code history impact diff api-diff reset install-hook completion"
 
     # Current word being completed
     cur="${COMP_WORDS[COMP_CWORD]}"
@@ -65,7 +65,7 @@ _cie_completion() {
     case "${cmd}" in
         index)
             if [[ ${cur} == -* ]] ; then
-                COMPREPLY=( $(compgen -W "--full --force-full-reindex --embed-workers --debug --metrics-addr" -- ${cur}) )
+                COMPREPLY=( $(compgen -W "--full --force-full-reindex --embed-workers --debug --metrics-addr --ref" -- ${cur}) )
             fi
             ;;
         status)
@@ -73,7 +73,47 @@ _cie_completion() {
             ;;
         query)
             if [[ ${cur} == -* ]] ; then
-                COMPREPLY=( $(compgen -W "--timeout --limit" -- ${cur}) )
+                COMPREPLY=( $(compgen -W "--timeout --limit --as-of" -- ${cur}) )
+            fi
+            ;;
+        coverage)
+            if [ $COMP_CWORD -eq 2 ]; then
+                COMPREPLY=( $(compgen -W "import" -- ${cur}) )
+            elif [[ ${cur} == -* ]] ; then
+                COMPREPLY=( $(compgen -W "--format" -- ${cur}) )
+            elif [[ ${prev} == --format ]] ; then
+                COMPREPLY=( $(compgen -W "go cobertura lcov" -- ${cur}) )
+            else
+                COMPREPLY=( $(compgen -f -- ${cur}) )
+            fi
+            ;;
+        dead-code)
+            if [[ ${prev} == --min-confidence ]] ; then
+                COMPREPLY=( $(compgen -W "high medium low" -- ${cur}) )
+            elif [[ ${cur} == -* ]] ; then
+                COMPREPLY=( $(compgen -W "--path --roots --min-confidence --limit" -- ${cur}) )
+            fi
+            ;;
+        history)
+            if [[ ${cur} == -* ]] ; then
+                COMPREPLY=( $(compgen -W "--since --max-commits --max-changeset --min-co-changes" -- ${cur}) )
+            fi
+            ;;
+        impact)
+            if [[ ${cur} == -* ]] ; then
+                COMPREPLY=( $(compgen -W "--diff --depth --limit" -- ${cur}) )
+            else
+                COMPREPLY=( $(compgen -W "$(git for-each-ref --format='%(refname:short)' 2>/dev/null)" -- ${cur}) )
+            fi
+            ;;
+        diff)
+            COMPREPLY=( $(compgen -W "$(git for-each-ref --format='%(refname:short)' 2>/dev/null)" -- ${cur}) )
+            ;;
+        api-diff)
+            if [[ ${cur} == -* ]] ; then
+                COMPREPLY=( $(compgen -W "--head --path" -- ${cur}) )
+            else
+                COMPREPLY=( $(compgen -W "$(git for-each-ref --format='%(refname:short)' 2>/dev/null)" -- ${cur}) )
             fi
             ;;
         reset)
@@ -120,6 +160,12 @@ _cie() {
         'index:Index the current repository'
         'status:Show project status'
         'query:Execute CozoScript query'
+        'coverage:Import a test coverage profile'
+        'dead-code:List functions and types no entry point reaches'
+        'history:Import git churn and change coupling'
+        'impact:Report what a diff could break'
+        'diff:Summarize a diff by function'
+        'api-diff:Fail on breaking exported Go API changes'
         'reset:Reset local project data'
         'install-hook:Install git post-commit hook'
         'completion:Generate shell completion script'
@@ -148,7 +194,8 @@ _cie() {
                         '--force-full-reindex[Force full re-index (ignore incremental)]' \
                         '--embed-workers[Number of embedding workers]:workers:' \
                         '--debug[Enable debug logging]' \
-                        '--metrics-addr[Prometheus metrics address]:address:'
+                        '--metrics-addr[Prometheus metrics address]:address:' \
+                        '--ref[Build a snapshot of a commit]:git ref:'
                     ;;
                 status)
                     # No command-specific flags (uses global --json)
@@ -157,8 +204,46 @@ _cie() {
                     _arguments \
                         '--timeout[Query timeout duration]:duration:' \
                         '--limit[Add :limit to query]:limit:' \
+                        '--as-of[Query the snapshot of a commit]:git ref:' \
                         '1:cozoscript query:'
                     ;;
+                coverage)
+                    _arguments \
+                        '--format[Profile format]:format:(go cobertura lcov)' \
+                        '1:subcommand:(import)' \
+                        '2:profile:_files'
+                    ;;
+                dead-code)
+                    _arguments \
+                        '--path[Directory to report on]:directory:_files -/' \
+                        '--roots[Reachability roots]:roots:' \
+                        '--min-confidence[Lowest confidence to list]:confidence:(high medium low)' \
+                        '--limit[Maximum entries to list]:limit:'
+                    ;;
+                history)
+                    _arguments \
+                        '--since[Only walk commits after this date]:date:' \
+                        '--max-commits[Maximum commits to walk]:count:' \
+                        '--max-changeset[Skip larger commits for coupling]:count:' \
+                        '--min-co-changes[Minimum shared commits per pair]:count:'
+                    ;;
+                impact)
+                    _arguments \
+                        '--diff[Read a unified diff from a file]:file:_files' \
+                        '--depth[Caller levels to follow]:depth:' \
+                        '--limit[Maximum affected functions to list]:limit:' \
+                        '*:ref:'
+                    ;;
+                diff)
+                    _arguments \
+                        '*:ref:'
+                    ;;
+                api-diff)
+                    _arguments \
+                        '--head[Ref to compare with the base ref]:ref:' \
+                        '--path[Only compare packages under this directory]:directory:_files -/' \
+                        '1:ref:'
+                    ;;
                 reset)
                     _arguments \
                         '--yes[Skip confirmation prompt]'
@@ -196,6 +281,12 @@ complete -c cie -f -n "__fish_use_subcommand" -a "init" -d "Create .cie/project.
 complete -c cie -f -n "__fish_use_subcommand" -a "index" -d "Index the current repository"
 complete -c cie -f -n "__fish_use_subcommand" -a "status" -d "Show project status"
 complete -c cie -f -n "__fish_use_subcommand" -a "query" -d "Execute CozoScript query"
+complete -c cie -f -n "__fish_use_subcommand" -a "coverage" -d "Import a test coverage profile"
+complete -c cie -f -n "__fish_use_subcommand" -a "dead-code" -d "List functions and types no entry point reaches"
+complete -c cie -f -n "__fish_use_subcommand" -a "history" -d "Import git churn and change coupling"
+complete -c cie -f -n "__fish_use_subcommand" -a "impact" -d "Report what a diff could break"
+complete -c cie -f -n "__fish_use_subcommand" -a "diff" -d "Summarize a diff by function"
+complete -c cie -f -n "__fish_use_subcommand" -a "api-diff" -d "Fail on breaking exported Go API changes"
 complete -c cie -f -n "__fish_use_subcommand" -a "reset" -d "Reset local project data (destructive!)"
 complete -c cie -f -n "__fish_use_subcommand" -a "install-hook" -d "Install git post-commit hook"
 complete -c cie -f -n "__fish_use_subcommand" -a "completion" -d "Generate shell completion script"
@@ -215,6 +306,7 @@ complete -c cie -n "__fish_seen_subcommand_from index" -l force-full-reindex -d
 complete -c cie -n "__fish_seen_subcommand_from index" -l embed-workers -d "Number of embedding workers" -r
 complete -c cie -n "__fish_seen_subcommand_from index" -l debug -d "Enable debug logging"
 complete -c cie -n "__fish_seen_subcommand_from index" -l metrics-addr -d "Prometheus metrics address" -r
+complete -c cie -n "__fish_seen_subcommand_from index" -l ref -d "Build a snapshot of a commit" -r
 
 # status command flags
 # (uses global --json flag)
@@ -222,6 +314,32 @@ complete -c cie -n "__fish_seen_subcommand_from index" -l metrics-addr -d "Prome
 # query command flags
 complete -c cie -n "__fish_seen_subcommand_from query" -l timeout -d "Query timeout duration" -r
 complete -c cie -n "__fish_seen_subcommand_from query" -l limit -d "Add :limit to query" -r
+complete -c cie -n "__fish_seen_subcommand_from query" -l as-of -d "Query the snapshot of a commit" -r
+
+# coverage command arguments and flags
+complete -c cie -n "__fish_seen_subcommand_from coverage; and not __fish_seen_subcommand_from import" -f -a "import" -d "Import a coverage profile"
+complete -c cie -n "__fish_seen_subcommand_from coverage" -l format -d "Profile format" -x -a "go cobertura lcov"
+
+# dead-code command flags
+complete -c cie -n "__fish_seen_subcommand_from dead-code" -l path -d "Directory to report on" -r
+complete -c cie -n "__fish_seen_subcommand_from dead-code" -l roots -d "Reachability roots" -r
+complete -c cie -n "__fish_seen_subcommand_from dead-code" -l min-confidence -d "Lowest confidence to list" -x -a "high medium low"
+complete -c cie -n "__fish_seen_subcommand_from dead-code" -l limit -d "Maximum entries to list" -r
+
+# history command flags
+complete -c cie -n "__fish_seen_subcommand_from history" -l since -d "Only walk commits after this date" -r
+complete -c cie -n "__fish_seen_subcommand_from history" -l max-commits -d "Maximum commits to walk" -r
+complete -c cie -n "__fish_seen_subcommand_from history" -l max-changeset -d "Skip larger commits for coupling" -r
+complete -c cie -n "__fish_seen_subcommand_from history" -l min-co-changes -d "Minimum shared commits per pair" -r
+
+# impact command flags
+complete -c cie -n "__fish_seen_subcommand_from impact" -l diff -d "Read a unified diff from a file" -r -F
+complete -c cie -n "__fish_seen_subcommand_from impact" -l depth -d "Caller levels to follow" -r
+complete -c cie -n "__fish_seen_subcommand_from impact" -l limit -d "Maximum affected functions to list" -r
+
+# api-diff command flags
+complete -c cie -n "__fish_seen_subcommand_from api-diff" -l head -d "Ref to compare with the base ref" -r
+complete -c cie -n "__fish_seen_subcommand_from api-diff" -l path -d "Only compare packages under this directory" -r
 
 # reset command flags
 complete -c cie -n "__fish_seen_subcommand_from reset" -l yes -d "Skip confirmation prompt"
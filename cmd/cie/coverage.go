@@ -0,0 +1,245 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/kraklabs/cie/internal/errors"
+	"github.com/kraklabs/cie/internal/ui"
+	"github.com/kraklabs/cie/pkg/ingestion"
+	"github.com/kraklabs/cie/pkg/storage"
+)
+
+// CoverageImportOutput represents the result of a coverage import for JSON output.
+type CoverageImportOutput struct {
+	Profile        string   `json:"profile"`
+	Format         string   `json:"format"`
+	FilesMatched   int      `json:"files_matched"`
+	FilesUnmatched []string `json:"files_unmatched"`
+	Functions      int      `json:"functions"`
+	Covered        int      `json:"covered"`
+	Total          int      `json:"total"`
+	Percent        float64  `json:"percent"`
+}
+
+// runCoverage executes the 'coverage' CLI command.
+//
+// Subcommands:
+//   - import: Map a coverage profile onto the indexed functions
+//
+// Examples:
+//
+//	cie coverage import cover.out
+//	cie coverage import coverage.xml --format cobertura
+//	cie coverage import lcov.info --json
+func runCoverage(args []string, configPath string, globals GlobalFlags) {
+	if len(args) == 0 || args[0] != "import" {
+		fmt.Fprintf(os.Stderr, `Usage: cie coverage import <profile> [options]
+
+Description:
+  Import a test coverage profile and map it onto the indexed functions,
+  so MCP tools can report per-function coverage.
+
+For details: cie coverage import --help
+`)
+		os.Exit(1)
+	}
+	runCoverageImport(args[1:], configPath, globals)
+}
+
+// runCoverageImport executes 'cie coverage import', mapping the covered and
+// uncovered lines of a coverage profile onto cie_function line ranges.
+//
+// The previous import is replaced. Coverage of a file is dropped when the
+// file is re-indexed, since its line numbers may have changed.
+//
+// Command-specific flags:
+//   - --format: Profile format (go, cobertura, lcov); detected when omitted
+func runCoverageImport(args []string, configPath string, globals GlobalFlags) {
+	fs := flag.NewFlagSet("coverage import", flag.ExitOnError)
+	format := fs.String("format", "", "Profile format: go, cobertura or lcov (default: detected)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: cie coverage import <profile> [options]
+
+Description:
+  Map a test coverage profile onto the indexed functions. Each function
+  gets its covered and total statement counts and its uncovered line
+  ranges, shown by cie_find_function, cie_get_function_code and
+  cie_coverage_gaps.
+
+  Supported formats:
+    go         go test -coverprofile=cover.out
+    cobertura  coverage.py (coverage xml), Istanbul cobertura reporter
+    lcov       lcov tracefiles (Istanbul, c8, genhtml)
+
+  Profile paths are matched to indexed files by suffix, so Go import paths
+  and absolute CI paths resolve to repository-relative files.
+
+  Each import replaces the previous one. Re-indexing a file drops its
+  coverage: import a fresh profile after the code changes.
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  go test -coverprofile=cover.out ./... && cie coverage import cover.out
+  coverage xml && cie coverage import coverage.xml
+  npx jest --coverage && cie coverage import coverage/lcov.info
+
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		errors.FatalError(errors.NewInputError(
+			"Profile argument required",
+			"No coverage profile provided",
+			"Provide a profile: cie coverage import cover.out",
+		), globals.JSON)
+	}
+	profilePath := fs.Arg(0)
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		errors.FatalError(err, globals.JSON)
+	}
+
+	data, err := os.ReadFile(profilePath) //nolint:gosec // G304: path is provided by the user on the command line
+	if err != nil {
+		errors.FatalError(errors.NewNotFoundError(
+			fmt.Sprintf("Cannot read coverage profile %s", profilePath),
+			err.Error(),
+			"Check the path, or generate the profile first (e.g., go test -coverprofile=cover.out ./...)",
+		), globals.JSON)
+	}
+	profile, err := ingestion.ParseCoverageProfile(data, *format)
+	if err != nil {
+		errors.FatalError(errors.NewInputError(
+			"Invalid coverage profile",
+			err.Error(),
+			"Pass --format go|cobertura|lcov if the format is not detected",
+		), globals.JSON)
+	}
+
+	dataDir, err := projectDataDir(cfg, configPath)
+	if err != nil {
+		errors.FatalError(err, globals.JSON)
+	}
+	if _, err := os.Stat(dataDir); os.IsNotExist(err) {
+		errors.FatalError(errors.NewDatabaseError(
+			fmt.Sprintf("Project '%s' not indexed yet", cfg.ProjectID),
+			"The CIE database does not exist for this project",
+			"Run 'cie index' to index the repository first",
+			err,
+		), globals.JSON)
+	}
+
+	backend, err := storage.NewEmbeddedBackend(storage.EmbeddedConfig{
+		DataDir:   dataDir,
+		Engine:    "rocksdb",
+		ProjectID: cfg.ProjectID,
+	})
+	if err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"Cannot open CIE database",
+			"The database file may be corrupted or locked by another process",
+			"Stop other CIE processes (MCP server, watch) and try again",
+			err,
+		), globals.JSON)
+	}
+	defer func() { _ = backend.Close() }()
+
+	// Indexes created before coverage support lack the cie_coverage relation.
+	if err := backend.EnsureSchema(); err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"Cannot update CIE database schema",
+			err.Error(),
+			"Run 'cie reset --yes' and 'cie index' to rebuild the index",
+			err,
+		), globals.JSON)
+	}
+
+	result, err := ingestion.ImportCoverage(context.Background(), backend, profile, filepath.Base(profilePath), time.Now())
+	if err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"Coverage import failed",
+			err.Error(),
+			"Check that the index is not corrupted with 'cie status'",
+			err,
+		), globals.JSON)
+	}
+
+	out := CoverageImportOutput{
+		Profile:        profilePath,
+		Format:         result.Format,
+		FilesMatched:   result.FilesMatched,
+		FilesUnmatched: result.FilesUnmatched,
+		Functions:      result.Functions,
+		Covered:        result.Covered,
+		Total:          result.Total,
+	}
+	if result.Total > 0 {
+		out.Percent = float64(result.Covered) * 100 / float64(result.Total)
+	}
+
+	if globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(out)
+		return
+	}
+	printCoverageImport(out, globals)
+}
+
+// printCoverageImport prints the result of a coverage import as formatted text.
+func printCoverageImport(out CoverageImportOutput, globals GlobalFlags) {
+	if out.FilesMatched == 0 {
+		ui.Warningf("No file of %s matches an indexed file.", out.Profile)
+		ui.Info("Run 'cie coverage import' from the repository the profile was generated for, after 'cie index'.")
+		return
+	}
+	ui.Successf("Imported %s coverage from %s", out.Format, out.Profile)
+	fmt.Printf("  Files:      %s matched", ui.CountText(out.FilesMatched))
+	if len(out.FilesUnmatched) > 0 {
+		fmt.Printf(", %s not indexed", ui.CountText(len(out.FilesUnmatched)))
+	}
+	fmt.Println()
+	fmt.Printf("  Functions:  %s\n", ui.CountText(out.Functions))
+	fmt.Printf("  Coverage:   %.1f%% (%d/%d statements)\n", out.Percent, out.Covered, out.Total)
+
+	if globals.Verbose >= 1 {
+		for _, file := range out.FilesUnmatched {
+			fmt.Printf("  %s\n", ui.DimText("not indexed: "+file))
+		}
+	}
+}
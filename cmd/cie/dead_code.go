@@ -0,0 +1,194 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/kraklabs/cie/internal/errors"
+	"github.com/kraklabs/cie/internal/ui"
+	"github.com/kraklabs/cie/pkg/storage"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// runDeadCode executes the 'dead-code' CLI command, listing the functions and
+// types that no reachability root reaches.
+//
+// Command-specific flags:
+//   - --path: Directory to report on (reachability covers the whole index)
+//   - --roots: Reachability roots (main, init, tests, exported, handlers, custom roles)
+//   - --min-confidence: Lowest confidence listed (high, medium, low)
+//   - --limit: Maximum entries listed (default: 100)
+//
+// Examples:
+//
+//	cie dead-code
+//	cie dead-code --path internal --min-confidence high
+//	cie dead-code --roots main,tests,jobs --json
+func runDeadCode(args []string, configPath string, globals GlobalFlags) {
+	fs := flag.NewFlagSet("dead-code", flag.ExitOnError)
+	path := fs.String("path", "", "Directory to report on (default: whole project)")
+	roots := fs.StringSlice("roots", nil, "Reachability roots: main, init, tests, exported, handlers or custom roles (default: all built-in roots)")
+	minConfidence := fs.String("min-confidence", tools.DeadCodeLow, "Lowest confidence to list: high, medium or low")
+	limit := fs.Int("limit", 100, "Maximum entries to list")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: cie dead-code [options]
+
+Description:
+  List the functions and types that no reachability root reaches through
+  calls, function references and interface dispatch.
+
+  Roots:
+    main      Entry points: Go/Rust main, Python __main__, JS/TS index/app/server files
+    init      Go init functions, Python dunder methods, JS/TS constructors
+    tests     Functions in test files and indexed tests
+    exported  Exported Go API of library packages, Python __init__.py functions
+    handlers  HTTP handlers, route registration, GraphQL resolvers
+
+  Custom roles from .cie/project.yaml (roles.custom) can be used as roots.
+
+  Confidence:
+    high      Unexported Go functions and types
+    medium    Exported Go names outside library packages, private Python/JS names
+    low       Other Python/JS/TS functions, methods of types implementing an
+              interface that no reachable code uses
+
+  Code used through reflection, dynamic imports, templates or configuration
+  is not seen: check entries before deleting them.
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  cie dead-code
+  cie dead-code --path internal --min-confidence high
+  cie dead-code --roots main,tests,jobs --json
+
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		errors.FatalError(err, globals.JSON)
+	}
+
+	dataDir, err := projectDataDir(cfg, configPath)
+	if err != nil {
+		errors.FatalError(err, globals.JSON)
+	}
+	if _, err := os.Stat(dataDir); os.IsNotExist(err) {
+		errors.FatalError(errors.NewDatabaseError(
+			fmt.Sprintf("Project '%s' not indexed yet", cfg.ProjectID),
+			"The CIE database does not exist for this project",
+			"Run 'cie index' to index the repository first",
+			err,
+		), globals.JSON)
+	}
+
+	backend, err := storage.NewEmbeddedBackend(storage.EmbeddedConfig{
+		DataDir:   dataDir,
+		Engine:    "rocksdb",
+		ProjectID: cfg.ProjectID,
+	})
+	if err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"Cannot open CIE database",
+			"The database file may be corrupted or locked by another process",
+			"Stop other CIE processes (MCP server, watch) and try again",
+			err,
+		), globals.JSON)
+	}
+	defer func() { _ = backend.Close() }()
+
+	report, err := tools.AnalyzeDeadCode(context.Background(), tools.NewEmbeddedQuerier(backend), tools.FindDeadCodeArgs{
+		Path:          *path,
+		Roots:         *roots,
+		CustomRoles:   toolRolePatterns(cfg.Roles.Custom),
+		MinConfidence: *minConfidence,
+		Limit:         *limit,
+	})
+	if err != nil {
+		errors.FatalError(errors.NewInputError(
+			"Dead code analysis failed",
+			err.Error(),
+			"Check --roots and --min-confidence, or re-index with 'cie index' if reference data is missing",
+		), globals.JSON)
+	}
+
+	if globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+		return
+	}
+	printDeadCode(report)
+}
+
+// printDeadCode prints a dead code report grouped by confidence and file.
+func printDeadCode(report *tools.DeadCodeReport) {
+	fmt.Printf("%s %s\n", ui.Label("Roots:"), strings.Join(report.Roots, ", "))
+	if len(report.Entries) == 0 {
+		ui.Success("No unreachable function or type found.")
+		return
+	}
+	ui.Warningf("%d unreachable functions and %d unreachable types", report.Functions, report.Types)
+
+	lastConfidence, lastFile := "", ""
+	for _, e := range report.Entries {
+		if e.Confidence != lastConfidence {
+			ui.Header(strings.ToUpper(e.Confidence[:1]) + e.Confidence[1:] + " confidence")
+			lastConfidence, lastFile = e.Confidence, ""
+		}
+		if e.FilePath != lastFile {
+			ui.SubHeader(e.FilePath)
+			lastFile = e.FilePath
+		}
+		fmt.Printf("  %-40s %s\n", fmt.Sprintf("%s (%s, line %d)", e.Name, e.Kind, e.Line), ui.DimText(e.Reason))
+	}
+	if report.Truncated {
+		fmt.Println()
+		ui.Infof("Showing the first %d entries: narrow with --path or --min-confidence, or raise --limit.", len(report.Entries))
+	}
+}
+
+// toolRolePatterns converts the custom roles of the project config to the
+// role patterns of the tools package.
+func toolRolePatterns(roles map[string]RolePattern) map[string]tools.RolePattern {
+	if len(roles) == 0 {
+		return nil
+	}
+	out := make(map[string]tools.RolePattern, len(roles))
+	for name, role := range roles {
+		out[name] = tools.RolePattern(role)
+	}
+	return out
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/kraklabs/cie/internal/errors"
+	"github.com/kraklabs/cie/internal/ui"
+	"github.com/kraklabs/cie/pkg/ingestion"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// runDiff executes the 'diff' CLI command, summarizing the changes between
+// two git refs function by function: added, removed, renamed, moved, with a
+// new signature or a new body, and the calls added and removed.
+//
+// Both versions of the changed files are read from git and parsed; nothing
+// is checked out and the index is not needed.
+//
+// Examples:
+//
+//	cie diff main HEAD
+//	cie diff v1.2.0 v1.3.0 --json
+//	cie diff main
+func runDiff(args []string, configPath string, globals GlobalFlags) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: cie diff <base> [head] [options]
+
+Description:
+  Summarize the changes between two git refs by function instead of by line.
+  The files changed between the refs are parsed at both refs and their
+  functions compared:
+
+    added              New function
+    removed            Function no longer present
+    renamed            Same body under another name (by body hash)
+    moved              Same name and body in another file
+    signature changed  Parameters, results or receiver changed
+    body changed       Same signature, new body
+
+  Calls added and removed are listed by caller and callee name. Without
+  head, base is compared with the working tree.
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  cie diff main HEAD
+  cie diff v1.2.0 v1.3.0 --json
+  cie diff main
+
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	refs := fs.Args()
+	if len(refs) == 0 || len(refs) > 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	base, head := refs[0], ""
+	if len(refs) > 1 {
+		head = refs[1]
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		errors.FatalError(errors.NewInternalError(
+			"Cannot determine working directory",
+			err.Error(),
+			"Run cie diff from inside the git repository",
+			err,
+		), globals.JSON)
+	}
+	git, err := tools.NewGitExecutor(cwd)
+	if err != nil {
+		errors.FatalError(errors.NewInputError(
+			"Git repository not detected",
+			err.Error(),
+			"Run cie diff from inside the git repository",
+		), globals.JSON)
+	}
+
+	report, err := ingestion.DiffSymbols(context.Background(), git, base, head)
+	if err != nil {
+		errors.FatalError(errors.NewInputError(
+			"Cannot compare the refs",
+			err.Error(),
+			"Pass refs git knows, such as a branch, tag or commit",
+		), globals.JSON)
+	}
+
+	if globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+		return
+	}
+	printDiff(report)
+}
+
+// printDiff prints a semantic diff report grouped by kind of change.
+func printDiff(report *tools.SemanticDiffReport) {
+	head := report.Head
+	if head == "" {
+		head = "working tree"
+	}
+	fmt.Printf("%s %s..%s, %d files changed, %d parsed\n", ui.Label("Diff:"), report.Base, head, report.FilesChanged, report.FilesParsed)
+	if len(report.Changes) == 0 && len(report.AddedCalls) == 0 && len(report.RemovedCalls) == 0 {
+		ui.Info("No function changed.")
+		return
+	}
+
+	sections := []struct{ kind, title string }{
+		{tools.SymbolAdded, "Added"},
+		{tools.SymbolRemoved, "Removed"},
+		{tools.SymbolRenamed, "Renamed"},
+		{tools.SymbolMoved, "Moved"},
+		{tools.SymbolSignatureChanged, "Signature changed"},
+		{tools.SymbolBodyChanged, "Body changed"},
+	}
+	for _, section := range sections {
+		if report.Count(section.kind) == 0 {
+			continue
+		}
+		ui.Header(fmt.Sprintf("%s (%d)", section.title, report.Count(section.kind)))
+		for _, c := range report.Changes {
+			if c.Kind != section.kind {
+				continue
+			}
+			location := fmt.Sprintf("%s:%d", c.FilePath, c.Line)
+			switch c.Kind {
+			case tools.SymbolRenamed:
+				fmt.Printf("  %-40s %s\n", c.OldName+" → "+c.Name, ui.DimText(location))
+			case tools.SymbolMoved:
+				fmt.Printf("  %-40s %s\n", c.Name, ui.DimText(c.OldFilePath+" → "+location))
+			case tools.SymbolSignatureChanged:
+				fmt.Printf("  %-40s %s\n", c.Name, ui.DimText(location))
+				fmt.Printf("    - %s\n    + %s\n", c.OldSignature, c.Signature)
+			default:
+				fmt.Printf("  %-40s %s\n", c.Name, ui.DimText(location))
+			}
+		}
+	}
+	printCallEdges("Calls added", report.AddedCalls)
+	printCallEdges("Calls removed", report.RemovedCalls)
+}
+
+// printCallEdges prints call edges as caller → callee.
+func printCallEdges(title string, edges []tools.CallEdgeChange) {
+	if len(edges) == 0 {
+		return
+	}
+	ui.Header(fmt.Sprintf("%s (%d)", title, len(edges)))
+	for _, e := range edges {
+		fmt.Printf("  %-40s %s\n", e.Caller+" → "+e.Callee, ui.DimText(fmt.Sprintf("%s:%d", e.FilePath, e.Line)))
+	}
+}
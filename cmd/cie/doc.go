@@ -55,6 +55,12 @@
 //	index          Index the current repository for code intelligence
 //	status         Show project status (files, functions, types indexed)
 //	query          Execute CozoScript queries on the indexed codebase
+//	coverage       Import test coverage profiles onto indexed functions
+//	dead-code      List functions and types no reachability root reaches
+//	history        Import per-function churn and change coupling from git
+//	impact         Report what a diff could break: endpoints, services, tests
+//	diff           Summarize the changes between two refs by function
+//	api-diff       Classify exported Go API changes as breaking or compatible
 //	reset          Reset local project data (destructive operation)
 //	install-hook   Install git post-commit hook for automatic re-indexing
 //
@@ -76,12 +82,28 @@
 //	cie_find_function        Find functions by name
 //	cie_find_callers         Find what calls a function
 //	cie_find_callees         Find what a function calls
+//	cie_find_references      Find every reference to a function or type
 //	cie_analyze              Answer architectural questions
 //	cie_list_endpoints       List HTTP/REST endpoints
+//	cie_find_table_usage     Find functions that read/write a SQL table
+//	cie_list_graphql_operations List GraphQL queries/mutations and resolvers
+//	cie_deployment_topology  Show what deploys a binary, its env vars and Services
 //	cie_trace_path           Trace call paths from entry points
+//	cie_trace_error          Trace where an error is created and propagated
+//	cie_find_tests           Find the tests exercising a function
+//	cie_find_untested        Find exported functions no test reaches
+//	cie_coverage_gaps        Least-covered functions on a call path
+//	cie_find_dead_code       Find functions and types nothing reaches
+//	cie_hotspots             Rank functions by complexity and fan-in
+//	cie_change_coupling      Functions that usually change together
+//	cie_impact               Endpoints, services and tests a diff affects
+//	cie_semantic_diff        Functions added, renamed, changed between refs
 //	cie_find_type            Find types, interfaces, structs
+//	cie_find_variable        Find package-level variables and constants
 //	cie_find_implementations Find interface implementations
 //	cie_directory_summary    Summarize directory structure
+//	cie_package_graph        Package coupling metrics and import cycles
+//	cie_concurrency_map      Goroutines, channels and lock order of Go code
 //	cie_verify_absence       Verify patterns don't exist (security audits)
 //	... and more
 //
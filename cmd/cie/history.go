@@ -0,0 +1,223 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/kraklabs/cie/internal/errors"
+	"github.com/kraklabs/cie/internal/ui"
+	"github.com/kraklabs/cie/pkg/ingestion"
+	"github.com/kraklabs/cie/pkg/storage"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// HistoryOutput represents the result of a history import for JSON output.
+type HistoryOutput struct {
+	Commits      int    `json:"commits"`
+	LargeCommits int    `json:"large_commits"`
+	Oldest       string `json:"oldest,omitempty"`
+	Newest       string `json:"newest,omitempty"`
+	LinesAdded   int    `json:"lines_added"`
+	LinesDeleted int    `json:"lines_deleted"`
+	Functions    int    `json:"functions"`
+	Pairs        int    `json:"pairs"`
+}
+
+// runHistory executes the 'history' CLI command, walking the git history and
+// storing per-function churn and change coupling.
+//
+// The previous import is replaced. Churn of a file is dropped when the file
+// is re-indexed, since its function IDs follow its line numbers.
+//
+// Command-specific flags:
+//   - --since: Only walk commits after this date
+//   - --max-commits: Maximum commits walked (default: 1000)
+//   - --max-changeset: Commits changing more functions are left out of coupling (default: 50)
+//   - --min-co-changes: Shared commits needed to store a pair (default: 2)
+//
+// Examples:
+//
+//	cie history
+//	cie history --since "6 months ago"
+//	cie history --max-commits 5000 --json
+func runHistory(args []string, configPath string, globals GlobalFlags) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	since := fs.String("since", "", `Only walk commits after this date (e.g., "2025-01-01", "6 months ago")`)
+	maxCommits := fs.Int("max-commits", ingestion.DefaultHistoryMaxCommits, "Maximum commits to walk, the most recent first")
+	maxChangeset := fs.Int("max-changeset", ingestion.DefaultHistoryMaxChangeset, "Leave commits changing more functions out of change coupling")
+	minCoChanges := fs.Int("min-co-changes", ingestion.DefaultHistoryMinCoChanges, "Commits two functions must share to be stored as coupled")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: cie history [options]
+
+Description:
+  Walk the git history of the repository (git log --numstat) and map the
+  lines changed by each commit onto the indexed functions. Each function
+  gets its commit count, author count and lines changed, and functions
+  changed in the same commits are stored as coupled pairs.
+
+  Used by cie_change_coupling ("what else changes when this changes") and
+  cie_hotspots (sort_by=churn ranks by churn x complexity).
+
+  Lines of older commits are carried through newer commits and renames to
+  the current line numbers. Merge commits are skipped, and commits changing
+  more than --max-changeset functions (bulk renames, reformats) count for
+  churn but not for coupling.
+
+  Each run replaces the previous one. Re-indexing a file drops its churn:
+  run cie history again after indexing.
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  cie history
+  cie history --since "6 months ago"
+  cie history --max-commits 5000 --json
+
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		errors.FatalError(err, globals.JSON)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		errors.FatalError(errors.NewInternalError(
+			"Cannot determine current directory",
+			err.Error(),
+			"Run cie history from the repository root",
+			err,
+		), globals.JSON)
+	}
+	git, err := tools.NewGitExecutor(cwd)
+	if err != nil {
+		errors.FatalError(errors.NewInputError(
+			"Git history unavailable",
+			err.Error(),
+			"Run cie history from inside the git repository that was indexed",
+		), globals.JSON)
+	}
+
+	dataDir, err := projectDataDir(cfg, configPath)
+	if err != nil {
+		errors.FatalError(err, globals.JSON)
+	}
+	if _, err := os.Stat(dataDir); os.IsNotExist(err) {
+		errors.FatalError(errors.NewDatabaseError(
+			fmt.Sprintf("Project '%s' not indexed yet", cfg.ProjectID),
+			"The CIE database does not exist for this project",
+			"Run 'cie index' to index the repository first",
+			err,
+		), globals.JSON)
+	}
+
+	backend, err := storage.NewEmbeddedBackend(storage.EmbeddedConfig{
+		DataDir:   dataDir,
+		Engine:    "rocksdb",
+		ProjectID: cfg.ProjectID,
+	})
+	if err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"Cannot open CIE database",
+			"The database file may be corrupted or locked by another process",
+			"Stop other CIE processes (MCP server, watch) and try again",
+			err,
+		), globals.JSON)
+	}
+	defer func() { _ = backend.Close() }()
+
+	// Indexes created before history support lack the churn relations.
+	if err := backend.EnsureSchema(); err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"Cannot update CIE database schema",
+			err.Error(),
+			"Run 'cie reset --yes' and 'cie index' to rebuild the index",
+			err,
+		), globals.JSON)
+	}
+
+	analysis, err := ingestion.ImportHistory(context.Background(), backend, git, ingestion.HistoryOptions{
+		Since:        *since,
+		MaxCommits:   *maxCommits,
+		MaxChangeset: *maxChangeset,
+		MinCoChanges: *minCoChanges,
+	})
+	if err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"History import failed",
+			err.Error(),
+			"Check that --since is a date git understands and that the index is not corrupted ('cie status')",
+			err,
+		), globals.JSON)
+	}
+
+	out := HistoryOutput{
+		Commits:      analysis.Commits,
+		LargeCommits: analysis.LargeCommits,
+		LinesAdded:   analysis.LinesAdded,
+		LinesDeleted: analysis.LinesDeleted,
+		Functions:    len(analysis.Churn),
+		Pairs:        len(analysis.Coupling) / 2,
+	}
+	if analysis.Commits > 0 {
+		out.Oldest = time.Unix(analysis.Oldest, 0).UTC().Format("2006-01-02")
+		out.Newest = time.Unix(analysis.Newest, 0).UTC().Format("2006-01-02")
+	}
+
+	if globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(out)
+		return
+	}
+	printHistory(out)
+}
+
+// printHistory prints the result of a history import as formatted text.
+func printHistory(out HistoryOutput) {
+	if out.Commits == 0 {
+		ui.Warning("No commit found in the range walked.")
+		ui.Info("Widen the range with --since or --max-commits.")
+		return
+	}
+	ui.Successf("Imported git history from %s to %s", out.Oldest, out.Newest)
+	fmt.Printf("  Commits:    %s (+%d/-%d lines)", ui.CountText(out.Commits), out.LinesAdded, out.LinesDeleted)
+	if out.LargeCommits > 0 {
+		fmt.Printf(", %s left out of coupling", ui.CountText(out.LargeCommits))
+	}
+	fmt.Println()
+	fmt.Printf("  Functions:  %s changed\n", ui.CountText(out.Functions))
+	fmt.Printf("  Coupling:   %s pairs\n", ui.CountText(out.Pairs))
+}
@@ -0,0 +1,294 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/kraklabs/cie/internal/errors"
+	"github.com/kraklabs/cie/internal/ui"
+	"github.com/kraklabs/cie/pkg/ingestion"
+	"github.com/kraklabs/cie/pkg/storage"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// runImpact executes the 'impact' CLI command, reporting what a change could
+// break: the functions and types it changes, and the endpoints, gRPC methods,
+// entry points, tests and functions depending on them.
+//
+// The change is the diff between two git refs, between a ref and the working
+// tree, or a unified diff read from a file or stdin.
+//
+// Command-specific flags:
+//   - --diff: Read a unified diff from a file ("-" for stdin) instead of git refs
+//   - --depth: Caller levels followed from the changed symbols (default: 5)
+//   - --limit: Maximum affected functions listed (default: 50)
+//
+// Examples:
+//
+//	cie impact main
+//	cie impact origin/main HEAD
+//	git diff main | cie impact --diff -
+func runImpact(args []string, configPath string, globals GlobalFlags) {
+	fs := flag.NewFlagSet("impact", flag.ExitOnError)
+	diffFile := fs.String("diff", "", `Read a unified diff from this file ("-" for stdin) instead of comparing git refs`)
+	depth := fs.Int("depth", 5, "Caller levels to follow from the changed symbols (at most 10)")
+	limit := fs.Int("limit", 50, "Maximum affected functions to list")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: cie impact <base> [head] [options]
+       cie impact --diff <file|-> [options]
+
+Description:
+  Report what a change could break. The changed lines are mapped onto the
+  indexed functions and types, and their transitive callers are followed
+  through the call graph, interface implementations and type references.
+
+  Reported, ranked by distance from the change:
+    - HTTP endpoints whose handler or route registration is affected
+    - gRPC methods whose definition or implementation is affected
+    - Entry points (main functions, server and index files)
+    - Tests reaching the change, with the commands running them
+    - Every affected function, with what it reaches the change through
+
+  Without head, base is compared with the working tree. The index must
+  match the new side of the diff: check out head and run 'cie index' first.
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  cie impact main
+  cie impact origin/main HEAD --depth 3
+  git diff main | cie impact --diff -
+  cie impact --diff change.patch --json
+
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	refs := fs.Args()
+	if (*diffFile == "") == (len(refs) == 0) || len(refs) > 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	base, head := "", ""
+	if len(refs) > 0 {
+		base = refs[0]
+	}
+	if len(refs) > 1 {
+		head = refs[1]
+	}
+
+	diff := ""
+	if *diffFile != "" {
+		var data []byte
+		var err error
+		if *diffFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(*diffFile)
+		}
+		if err != nil {
+			errors.FatalError(errors.NewInputError(
+				"Cannot read diff",
+				err.Error(),
+				"Pass a unified diff file (git diff > change.patch) or - to read stdin",
+			), globals.JSON)
+		}
+		diff = string(data)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		errors.FatalError(err, globals.JSON)
+	}
+
+	var git tools.GitRunner
+	if cwd, err := os.Getwd(); err == nil {
+		if executor, err := tools.NewGitExecutor(cwd); err == nil {
+			git = executor
+		}
+	}
+	ctx := context.Background()
+	changes, headSHA, err := impactChanges(ctx, git, diff, base, head)
+	if err != nil {
+		errors.FatalError(errors.NewInputError(
+			"Cannot compute the diff",
+			err.Error(),
+			"Run cie impact inside the git repository with refs git knows, or pass --diff",
+		), globals.JSON)
+	}
+
+	dataDir, err := projectDataDir(cfg, configPath)
+	if err != nil {
+		errors.FatalError(err, globals.JSON)
+	}
+	if _, err := os.Stat(dataDir); os.IsNotExist(err) {
+		errors.FatalError(errors.NewDatabaseError(
+			fmt.Sprintf("Project '%s' not indexed yet", cfg.ProjectID),
+			"The CIE database does not exist for this project",
+			"Run 'cie index' to index the repository first",
+			err,
+		), globals.JSON)
+	}
+
+	backend, err := storage.NewEmbeddedBackend(storage.EmbeddedConfig{
+		DataDir:   dataDir,
+		Engine:    "rocksdb",
+		ProjectID: cfg.ProjectID,
+	})
+	if err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"Cannot open CIE database",
+			"The database file may be corrupted or locked by another process",
+			"Stop other CIE processes (MCP server, watch) and try again",
+			err,
+		), globals.JSON)
+	}
+	defer func() { _ = backend.Close() }()
+
+	report, err := tools.AnalyzeImpact(ctx, tools.NewEmbeddedQuerier(backend), tools.ImpactArgs{
+		Changes:  changes,
+		HeadSHA:  headSHA,
+		MaxDepth: *depth,
+		Limit:    *limit,
+	})
+	if err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"Impact analysis failed",
+			err.Error(),
+			"Re-index with 'cie index' if the index predates call or type reference data",
+			err,
+		), globals.JSON)
+	}
+
+	if globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+		return
+	}
+	printImpact(report)
+}
+
+// impactChanges returns the files changed by a raw unified diff, or by the
+// diff between two git refs along with the commit head resolves to. An
+// empty head compares base with the working tree.
+func impactChanges(ctx context.Context, git tools.GitRunner, diff, base, head string) ([]tools.ChangedFile, string, error) {
+	if diff != "" {
+		return ingestion.ParseDiff(diff), "", nil
+	}
+	if git == nil {
+		return nil, "", fmt.Errorf("git repository not detected: pass a unified diff instead of refs")
+	}
+	changes, err := ingestion.DiffRefs(ctx, git, base, head)
+	if err != nil {
+		return nil, "", err
+	}
+	headSHA := ""
+	if head != "" {
+		out, err := git.Run(ctx, "rev-parse", "--verify", "--quiet", head+"^{commit}")
+		if err != nil {
+			return nil, "", fmt.Errorf("unknown ref %q: %w", head, err)
+		}
+		headSHA = strings.TrimSpace(out)
+	}
+	return changes, headSHA, nil
+}
+
+// printImpact prints an impact report, each section ranked by distance.
+func printImpact(report *tools.ImpactReport) {
+	if report.Warning != "" {
+		ui.Warning(report.Warning)
+	}
+	fmt.Printf("%s %d files, %d symbols changed, %s functions affected within %d caller levels\n",
+		ui.Label("Change:"), report.FilesChanged, len(report.Changed), ui.CountText(report.AffectedTotal), report.Depth)
+	if len(report.Changed) == 0 {
+		ui.Info("No indexed function or type is changed by the diff.")
+	}
+
+	if len(report.Changed) > 0 {
+		ui.Header("Changed")
+		for _, s := range report.Changed {
+			fmt.Printf("  %-40s %s\n", s.Name, ui.DimText(fmt.Sprintf("%s %s:%d", s.Kind, s.FilePath, s.Line)))
+		}
+	}
+	if len(report.Endpoints) > 0 {
+		ui.Header("HTTP endpoints")
+		for _, ep := range report.Endpoints {
+			fmt.Printf("  [%d] %-7s %-30s %s\n", ep.Distance, ep.Method, ep.Path, ui.DimText(fmt.Sprintf("%s %s:%d", ep.Handler, ep.FilePath, ep.Line)))
+		}
+	}
+	if len(report.Services) > 0 {
+		ui.Header("gRPC methods")
+		for _, rpc := range report.Services {
+			handler := rpc.Handler
+			if handler == "" {
+				handler = "definition changed"
+			}
+			fmt.Printf("  [%d] %-37s %s\n", rpc.Distance, rpc.Service+"."+rpc.Method, ui.DimText(fmt.Sprintf("%s %s:%d", handler, rpc.FilePath, rpc.Line)))
+		}
+	}
+	if len(report.EntryPoints) > 0 {
+		ui.Header("Entry points")
+		for _, s := range report.EntryPoints {
+			fmt.Printf("  [%d] %-36s %s\n", s.Distance, s.Name, ui.DimText(fmt.Sprintf("%s:%d", s.FilePath, s.Line)))
+		}
+	}
+	if len(report.Tests) > 0 {
+		ui.Header(fmt.Sprintf("Tests (%d)", len(report.Tests)))
+		for _, t := range report.Tests {
+			fmt.Printf("  [%d] %-36s %s\n", t.Distance, t.Name, ui.DimText(fmt.Sprintf("%s:%d", t.FilePath, t.Line)))
+		}
+		if len(report.TestCommands) > 0 {
+			ui.SubHeader("Run")
+			for _, cmd := range report.TestCommands {
+				fmt.Printf("  %s\n", cmd)
+			}
+		}
+	}
+	if len(report.Affected) > 0 {
+		ui.Header("Affected functions")
+		for _, s := range report.Affected {
+			fmt.Printf("  [%d] %-36s %s\n", s.Distance, s.Name, ui.DimText(fmt.Sprintf("%s:%d, %s", s.FilePath, s.Line, s.Via)))
+		}
+		if report.AffectedCapped {
+			fmt.Println()
+			ui.Infof("Showing the %d closest of %d affected functions: raise --limit to see more.", len(report.Affected), report.AffectedTotal)
+		}
+	}
+	if len(report.Unmapped) > 0 {
+		ui.Header("Files with no indexed symbol changed")
+		for _, f := range report.Unmapped {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+}
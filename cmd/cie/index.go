@@ -53,12 +53,14 @@ import (
 //   - --embed-workers: Number of parallel embedding workers (default: 8)
 //   - --debug: Enable debug logging (default: false)
 //   - --metrics-addr: HTTP address for Prometheus metrics (default: disabled)
+//   - --ref: Build a snapshot of a commit next to the HEAD index (default: none)
 //
 // Examples:
 //
 //	cie index                  Incremental index (only changed files)
 //	cie index --full           Force full reindex
 //	cie index --embed-workers 16  Use 16 parallel workers for embeddings
+//	cie index --ref v2.3.0     Snapshot the index as of tag v2.3.0
 func runIndex(args []string, configPath string, globals GlobalFlags) {
 	// Check if we should delegate to remote server
 	baseURL := os.Getenv("CIE_BASE_URL")
@@ -73,6 +75,7 @@ func runIndex(args []string, configPath string, globals GlobalFlags) {
 	embedWorkers := fs.Int("embed-workers", 8, "Number of parallel embedding workers")
 	debug := fs.Bool("debug", false, "Enable debug logging")
 	metricsAddr := fs.String("metrics-addr", "", "HTTP listen address for Prometheus metrics (empty to disable)")
+	ref := fs.String("ref", "", "Build a snapshot of this commit next to the HEAD index, without checking it out")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Usage: cie index [options]
@@ -89,6 +92,12 @@ Description:
   Indexed data is stored in the configured local data directory
   (default: ~/.cie/data/<project_id>/).
 
+  With --ref, the index of a past commit is built as a snapshot next to
+  the HEAD index, reading the commit's files from git without checking it
+  out. Rows of files unchanged between snapshots are stored once. Query a
+  snapshot with 'cie query --as-of <ref>' or the as_of parameter of the
+  MCP tools. Snapshots keep no embeddings, coverage or git history.
+
 Options:
 `)
 		fs.PrintDefaults()
@@ -109,6 +118,9 @@ Examples:
   # Enable debug logging and expose metrics
   cie index --debug --metrics-addr :9090
 
+  # Snapshot the index as of a release, to query the call graph back then
+  cie index --ref v2.3.0
+
 Notes:
   Indexing may take several minutes for large repositories. Progress
   indicators will show files processed and errors encountered.
@@ -119,6 +131,13 @@ Notes:
 	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
 	}
+	if *ref != "" && (*full || *forceFullReindex) {
+		errors.FatalError(errors.NewInputError(
+			"--ref cannot be combined with --full or --force-full-reindex",
+			"A snapshot is built next to the HEAD index and does not rebuild it",
+			"Run 'cie index --full' and 'cie index --ref <ref>' separately",
+		), globals.JSON)
+	}
 
 	// Load configuration
 	cfg, err := LoadConfig(configPath)
@@ -188,6 +207,11 @@ Notes:
 		), false)
 	}
 
+	if *ref != "" {
+		runSnapshotIndex(ctx, logger, cfg, cwd, dataDir, *embedWorkers, *ref, globals)
+		return
+	}
+
 	// Map embedding provider
 	embeddingProvider := mapEmbeddingProvider(cfg.Embedding.Provider)
 
@@ -265,23 +289,8 @@ func runLocalIndex(ctx context.Context, logger *slog.Logger, cfg *Config, config
 	}
 
 	// Set up progress reporting
-	progressCfg := NewProgressConfig(globals)
-	var currentBar *progressbar.ProgressBar
-	var currentPhase string
-
-	pipeline.SetProgressCallback(func(current, total int64, phase string) {
-		// Create new bar when phase changes
-		if phase != currentPhase {
-			if currentBar != nil {
-				_ = currentBar.Finish()
-			}
-			currentPhase = phase
-			currentBar = NewProgressBar(progressCfg, total, phaseDescription(phase))
-		}
-		if currentBar != nil {
-			_ = currentBar.Set64(current)
-		}
-	})
+	onProgress, finishProgress := newProgressReporter(globals)
+	pipeline.SetProgressCallback(onProgress)
 
 	logger.Info("indexing.starting",
 		"mode", "local",
@@ -293,9 +302,7 @@ func runLocalIndex(ctx context.Context, logger *slog.Logger, cfg *Config, config
 	result, err := pipeline.Run(ctx)
 
 	// Clean up progress bar
-	if currentBar != nil {
-		_ = currentBar.Finish()
-	}
+	finishProgress()
 
 	if err != nil {
 		errors.FatalError(errors.NewDatabaseError(
@@ -309,6 +316,34 @@ func runLocalIndex(ctx context.Context, logger *slog.Logger, cfg *Config, config
 	printResult(result, cfg, configPath)
 }
 
+// newProgressReporter returns a pipeline progress callback showing one
+// progress bar per phase, and a function finishing the last bar.
+func newProgressReporter(globals GlobalFlags) (ingestion.ProgressCallback, func()) {
+	progressCfg := NewProgressConfig(globals)
+	var currentBar *progressbar.ProgressBar
+	var currentPhase string
+
+	onProgress := func(current, total int64, phase string) {
+		// Create new bar when phase changes
+		if phase != currentPhase {
+			if currentBar != nil {
+				_ = currentBar.Finish()
+			}
+			currentPhase = phase
+			currentBar = NewProgressBar(progressCfg, total, phaseDescription(phase))
+		}
+		if currentBar != nil {
+			_ = currentBar.Set64(current)
+		}
+	}
+	finish := func() {
+		if currentBar != nil {
+			_ = currentBar.Finish()
+		}
+	}
+	return onProgress, finish
+}
+
 // phaseDescription returns a human-readable description for each pipeline phase.
 func phaseDescription(phase string) string {
 	switch phase {
@@ -318,6 +353,8 @@ func phaseDescription(phase string) string {
 		return "Generating embeddings"
 	case "embedding_types":
 		return "Embedding types"
+	case "embedding_docs":
+		return "Embedding docs"
 	case "writing":
 		return "Writing to database"
 	default:
@@ -451,7 +488,15 @@ func warnLegacyDataDir(cfg *Config, currentDataDir string) {
 func runRemoteIndex(baseURL string, args []string) {
 	fs := flag.NewFlagSet("index", flag.ExitOnError)
 	full := fs.Bool("full", false, "Force full reindex")
+	ref := fs.String("ref", "", "Build a snapshot of this commit")
 	_ = fs.Parse(args)
+	if *ref != "" {
+		errors.FatalError(errors.NewInputError(
+			"Snapshots are not supported by the remote server",
+			"cie index --ref builds snapshots in the local embedded database",
+			"Unset CIE_BASE_URL and edge_cache to index snapshots locally",
+		), false)
+	}
 
 	// Build request payload
 	payload := map[string]any{
@@ -25,6 +25,12 @@
 //	cie index                     Index the current repository
 //	cie status [--json]           Show project status
 //	cie query <script> [--json]   Execute CozoScript query
+//	cie coverage import <file>    Import a test coverage profile
+//	cie dead-code [--json]        List code no entry point reaches
+//	cie history [--since <date>]  Import git churn and change coupling
+//	cie impact <base> [head]      Report what a change could break
+//	cie diff <base> [head]        Summarize a change function by function
+//	cie api-diff <ref>            Fail on breaking exported Go API changes
 //	cie --mcp                     Start as MCP server (JSON-RPC over stdio)
 package main
 
@@ -90,6 +96,12 @@ func logError(globals GlobalFlags, format string, args ...interface{}) { //nolin
 //   - index: Index the current repository
 //   - status: Show project status
 //   - query: Execute CozoScript query
+//   - coverage: Import test coverage profiles
+//   - dead-code: List unreachable functions and types
+//   - history: Import per-function churn and change coupling from git
+//   - impact: Report the endpoints, services, entry points and tests a diff affects
+//   - diff: Summarize the changes between two refs by function and call edge
+//   - api-diff: Classify exported Go API changes since a ref as breaking or compatible
 //   - reset: Reset local project data (destructive!)
 //   - install-hook: Install git post-commit hook for auto-indexing
 func main() {
@@ -127,6 +139,12 @@ Commands:
   status        Show project status
   config        Show current configuration
   query         Execute CozoScript query
+  coverage      Import a test coverage profile (go, cobertura, lcov)
+  dead-code     List functions and types no entry point reaches
+  history       Import git churn and change coupling per function
+  impact        Report what a diff could break (endpoints, tests, callers)
+  diff          Summarize a diff by function (added, renamed, signature...)
+  api-diff      Fail on breaking exported Go API changes since a ref
   serve         Start local HTTP server for MCP tools
   reset         Reset local project data (destructive!)
   install-hook  Install git post-commit hook for auto-indexing
@@ -149,6 +167,12 @@ Examples:
   cie status --json                  Output as JSON (for MCP)
   cie config --json                  Show configuration as JSON
   cie query "?[name] := *cie_function{name}"
+  cie coverage import cover.out      Map go test coverage onto functions
+  cie dead-code --path internal      List code nothing reaches
+  cie history --since "6 months ago" Map recent git history onto functions
+  cie impact origin/main HEAD        What the branch could break
+  cie diff main HEAD                 Functions the branch changes
+  cie api-diff v1.4.0                Breaking API changes since v1.4.0
   cie completion bash                Generate bash completion script
   cie --mcp                          Start as MCP server
 
@@ -233,6 +257,18 @@ For detailed command help: cie <command> --help
 		runConfig(cmdArgs, *configPath, globals)
 	case "query":
 		runQuery(cmdArgs, *configPath, globals)
+	case "coverage":
+		runCoverage(cmdArgs, *configPath, globals)
+	case "dead-code":
+		runDeadCode(cmdArgs, *configPath, globals)
+	case "history":
+		runHistory(cmdArgs, *configPath, globals)
+	case "impact":
+		runImpact(cmdArgs, *configPath, globals)
+	case "diff":
+		runDiff(cmdArgs, *configPath, globals)
+	case "api-diff":
+		runAPIDiff(cmdArgs, *configPath, globals)
 	case "reset":
 		runReset(cmdArgs, *configPath, globals)
 	case "install-hook":
@@ -29,6 +29,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -58,25 +59,43 @@ All CIE tool queries MUST be in English. The keyword boost algorithm matches que
 |------|-----------|---------|
 | Find exact text like '.GET(', 'r.POST(' | cie_grep | text=".GET(" |
 | List HTTP/REST endpoints | cie_list_endpoints | path_pattern="apps/gateway" |
+| Which functions read/write a DB table | cie_find_table_usage | table="orders" |
+| List GraphQL queries/mutations | cie_list_graphql_operations | operation="mutation" |
+| What deploys a binary, its env, its Service | cie_deployment_topology | target="cmd/worker" |
 | Trace call path to a function | cie_trace_path | target="RegisterRoutes" |
+| Where an error is created and propagated | cie_trace_error | query="failed to resolve project: not found" |
+| Which tests to run after editing a function | cie_find_tests | function="Store.Get" |
+| Exported functions no test reaches | cie_find_untested | path="internal/store" |
+| Least-covered code on a call path | cie_coverage_gaps | function="Server.HandleOrder" |
+| Functions and types nothing reaches | cie_find_dead_code | path="internal", min_confidence="high" |
+| Complex, heavily-called functions | cie_hotspots | path="pkg", sort_by="score" |
+| Complex, frequently-changed functions | cie_hotspots | sort_by="churn" |
 | Semantic/meaning-based search | cie_semantic_search | query="authentication logic" |
 | Architectural questions | cie_analyze | question="What are the entry points?" |
 | Find function by name | cie_find_function | name="BuildRouter" |
 | What calls a function? | cie_find_callers | function_name="HandleAuth" |
 | What does a function call? | cie_find_callees | function_name="HandleAuth" |
+| Every reference to a type or function | cie_find_references | name="tools.QueryResult" |
 | Get function source code | cie_get_function_code | function_name="BuildRouter" |
 | Find interface implementations | cie_find_implementations | interface_name="Repository" |
 | Find type/interface/struct | cie_find_type | name="UserService" |
+| Where is a global var/const defined | cie_find_variable | name="DefaultTimeout" |
 | Explore directory structure | cie_directory_summary | path="internal/cie" |
+| Package coupling and import cycles | cie_package_graph | path="pkg/ingestion" |
+| Goroutines, channels and lock order | cie_concurrency_map | function="Pool.Start" |
 | Check index health | cie_index_status | (no args = check entire index) |
 | Reindex project (no IDE restart) | cie_reindex | force_full=false for incremental |
 | Function git commit history | cie_function_history | function_name="HandleAuth" |
 | Find when code was introduced | cie_find_introduction | code_snippet="jwt.Generate()" |
 | Function code ownership/blame | cie_blame_function | function_name="Parse" |
+| What changes together with a function? | cie_change_coupling | function="TracePath" |
+| What could this change break? | cie_impact | base="main", head="HEAD" |
+| What does this change do, by function? | cie_semantic_diff | base="main", head="HEAD" |
 | Find functions by param/return type | cie_find_by_signature | param_type="Querier" |
 | Verify patterns do NOT exist | cie_verify_absence | patterns=["api_key","secret"] |
 | List gRPC services & RPCs | cie_list_services | path_pattern="api/proto" |
 | Raw CozoScript query | cie_raw_query | (call cie_schema first) |
+| Call graph at a past release | cie_find_callers | function_name="HandleAuth", as_of="v2.3.0" |
 
 ## Recommended Workflow
 
@@ -96,6 +115,7 @@ Follow this progression for most code exploration tasks:
 - Code patterns: text=".GET(", text="func main", text="import"
 - Multi-pattern batch search: texts=["access_token", "refresh_token", "secret"]
 - Scoping: path="internal/cie", exclude_pattern="_test[.]go"
+- Also searches package-level variable and constant declarations
 
 **cie_search_text** — Regex-capable search within indexed functions. Slower than cie_grep but supports regex. Use for:
 - Complex patterns: pattern="(?i)handler.*error"
@@ -114,6 +134,7 @@ Follow this progression for most code exploration tasks:
 - path_pattern: Scope to directory (e.g., "apps/gateway")
 - exclude_paths: Remove noise (e.g., "metrics|telemetry|dlq")
 - min_similarity: Set threshold (0.7 = high confidence only)
+- include_docs: Also return matching sections of README, ADRs and docs/*.md (explains the why behind the code)
 - Confidence indicators in results: 🟢 High (≥75%), 🟡 Medium (50-75%), 🔴 Low (<50%)
 
 **cie_analyze** — Architectural Q&A with LLM narrative. Use for high-level questions that span multiple functions. Combines semantic search with keyword boosting and generates a narrative answer. Use for:
@@ -124,40 +145,66 @@ Follow this progression for most code exploration tasks:
 
 ### Code Navigation Tools
 
-**cie_find_function** — Find functions by name. Handles Go receiver syntax (searching "Batch" finds "Batcher.Batch"). Use exact_match=true for precise lookups, include_code=true to get source inline. If no functions match, suggests cie_find_type when the name matches a type.
+**cie_find_function** — Find functions by name. Handles Go receiver syntax (searching "Batch" finds "Batcher.Batch"). Use exact_match=true for precise lookups, include_code=true to get source inline. Results include each function's doc comment, and its line coverage when a profile was imported with cie coverage import. If no functions match, suggests cie_find_type when the name matches a type.
 
-**cie_get_function_code** — Get full source code of a function. Always use full_code=true for long functions — without it, output may be truncated.
+**cie_get_function_code** — Get full source code of a function, with its line coverage and uncovered lines when a profile was imported. Always use full_code=true for long functions — without it, output may be truncated.
 
-**cie_find_callers** — Who calls this function? Excludes test files. Set include_indirect=true for transitive callers (callers of callers, up to 3 levels deep).
+**cie_find_callers** — Who calls this function? Excludes test files. Set include_indirect=true for transitive callers (callers of callers, up to 3 levels deep). Set include_refs=true to also list functions that register it as a callback or handler (Go).
+
+**cie_find_references** — Every reference to a function or type, grouped by kind: calls, type uses (params, returns, fields, literals, conversions, assertions), embeds and imports. Includes tests. Use before renaming or changing a type; qualify with the package (tools.QueryResult) to disambiguate.
 
 **cie_find_callees** — What does this function call? Excludes test files. Shows all outgoing dependencies. Resolves method calls through both interface-typed and concrete-typed struct fields (e.g., b.db.Run() where db is *CozoDB). Also resolves calls through interface-typed function parameters. Set include_indirect=true for transitive callees (callees of callees, up to 3 levels deep).
 
 **cie_get_call_graph** — Combined view: both callers and callees in one call.
 
-**cie_trace_path** — Trace execution path from entry point to target function. Auto-detects entry points (main for Go, index exports for JS/TS, __main__ for Python). Use source parameter to trace between arbitrary functions. Increase max_depth for deeply nested targets. Resolves calls through concrete struct fields and interface parameters with fan-out reduction. Shows callsite line numbers (e.g., [called at store.go:63]) so you know exactly where in the caller each call happens. Annotates interface dispatch edges with [via interface X]. Use include_code=true to embed function source inline (eliminates separate cie_get_function_code calls). Use include_types=true to embed interface/struct definitions inline at hops where they appear (eliminates separate cie_find_type calls).
+**cie_trace_path** — Trace execution path from entry point to target function. Auto-detects entry points (main for Go, index exports for JS/TS, __main__ for Python). Use source parameter to trace between arbitrary functions. Increase max_depth for deeply nested targets. Resolves calls through concrete struct fields and interface parameters with fan-out reduction. Shows callsite line numbers (e.g., [called at store.go:63]) so you know exactly where in the caller each call happens. Annotates interface dispatch edges with [via interface X]. Use include_code=true to embed function source inline (eliminates separate cie_get_function_code calls). Use include_types=true to embed interface/struct definitions inline at hops where they appear (eliminates separate cie_find_type calls). Use include_refs=true to follow callbacks and handler registrations (Go), annotated with [referenced as X].
+
+**cie_trace_error** — Where an error comes from: the sentinels, error types and functions that create, wrap, return or raise it (Go errors.New/fmt.Errorf/var ErrX, Python raise, JS/TS throw), and the callers each origin propagates it through, with the message each caller wraps it with. Pass the message a user reported (e.g., "failed to resolve project: not found") or a sentinel name.
+
+**cie_find_tests** — Which tests exercise a function: Go Test/Benchmark/Fuzz/Example functions, t.Run subtests and testify suite methods, pytest functions and methods, Jest/Vitest it/test cases, reaching it directly or through up to max_depth callers. Ends with the commands to run just those tests. Use after an edit to pick the tests to run.
+
+**cie_find_untested** — Exported functions under a path that no indexed test reaches through the call graph. Functions called only through interfaces, reflection or callbacks may be listed although tested.
+
+**cie_coverage_gaps** — The least-covered functions on the call paths from a function (optionally to a target), with their coverage percentage and uncovered lines. Needs a profile imported with cie coverage import (go test -coverprofile, coverage.py XML, lcov). Use to find untested code an edit or a bug report goes through.
+
+**cie_find_dead_code** — Functions and types that no root reaches through calls, function references and interface dispatch. Roots are main, init, tests, exported API of library packages, handlers and custom roles from the project config. Entries are rated high, medium or low confidence; check low-confidence entries (dynamic languages, exported names) before deleting.
+
+**cie_hotspots** — Functions ranked by cyclomatic complexity combined with fan-in (distinct callers), with max nesting depth, parameter count, LOC and comment ratio computed from the AST at indexing. Use for tech-debt triage: complex functions many others depend on are the riskiest to change and the best refactoring candidates. After cie history, sort_by="churn" ranks by commits × complexity.
 
 ### Type & Interface Tools
 
 **cie_find_type** — Find types, structs, interfaces, classes by name. Filter by kind: "struct", "interface", "class", "type_alias". Use include_code=true to see the type's source code (interface methods, struct fields) without a separate file read.
 
-**cie_find_implementations** — Find concrete types that implement an interface. Works for Go (struct method matching) and TypeScript (implements keyword). Resolves embedded interfaces (e.g., ReadWriter embedding Reader+Writer) and common stdlib interfaces.
+**cie_find_variable** — Find package-level variables and constants by name: Go var/const, Python module-level assignments, exported JS/TS const/let. Shows kind, declared type, initializer and location. Use it for config defaults and global registries.
+
+**cie_find_implementations** — Find concrete types that implement an interface. Works for Go (struct method matching) and TypeScript (implements keyword). Resolves embedded interfaces (e.g., ReadWriter embedding Reader+Writer) and common stdlib interfaces. For Go constraints with a type set (~int | ~float64), lists the project types in the set.
 
 **cie_find_by_signature** — Find functions by parameter type or return type. Searches function signatures for a given base type name, matching regardless of pointer/slice/package prefix. Useful for discovering which functions accept a specific interface or struct.
 
 ### Architecture Discovery Tools
 
-**cie_directory_summary** — Overview of a directory: files with their main exported functions. Start here when exploring an unfamiliar module.
+**cie_directory_summary** — Overview of a directory: files with their main exported functions and the first sentence of their doc comments. Start here when exploring an unfamiliar module.
+
+**cie_package_graph** — Package dependency graph: fan-in, fan-out and instability per package, and import cycles. With path set to one package, also lists what it imports and what imports it.
+
+**cie_concurrency_map** — Go goroutines, channels and mutexes for a function or package: spawn tree, channel producers and consumers, lock acquisition order and potential lock-order inversions. Use when debugging races, deadlocks and goroutine leaks.
 
 **cie_list_files** — List all indexed files. Filter by language, path, or role. Good for understanding project layout.
 
 **cie_list_functions_in_file** — All functions in a specific file. Useful after finding a file via cie_list_files.
 
-**cie_get_file_summary** — All entities (functions, types, constants) in a file. More detailed than list_functions_in_file.
+**cie_get_file_summary** — All entities (functions, types, constants) in a file, with doc comment summaries. More detailed than list_functions_in_file.
 
-**cie_list_endpoints** — HTTP/REST endpoints from Go frameworks (Gin, Echo, Chi, Fiber, net/http). Returns [Method] [Path] [Handler] [File].
+**cie_list_endpoints** — HTTP/REST endpoints from Go frameworks (Gin, Echo, Chi, Fiber, net/http) and PHP (Laravel, Symfony). Returns [Method] [Path] [Handler] [File].
 
 **cie_list_services** — gRPC service definitions and RPC methods from .proto files.
 
+**cie_find_table_usage** — SQL table schema (columns, indexes, foreign keys from .sql files) and the functions that read or write it, detected from SQL literals in database calls and ORM table selectors. Without table, lists all tables with reader/writer counts.
+
+**cie_list_graphql_operations** — GraphQL queries, mutations and subscriptions from .graphql/.graphqls SDL files, with the gqlgen or Apollo functions resolving them. Use type_name to list the fields of an object type instead.
+
+**cie_deployment_topology** — How code is deployed, from Dockerfiles, docker-compose files, Kubernetes manifests and Terraform. With target (e.g., "cmd/worker"), shows the units that run it, their env vars and the Services exposing it. Without target, lists all deployable units.
+
 ### Git History Tools
 
 **cie_function_history** — Git commit history for a specific function. Use since="2024-01-01" to filter by date. Use path_pattern to disambiguate functions with the same name in different files.
@@ -166,6 +213,12 @@ Follow this progression for most code exploration tasks:
 
 **cie_blame_function** — Code ownership breakdown by author. Shows who wrote what percentage. Use show_lines=true for line-by-line detail.
 
+**cie_change_coupling** — Functions that usually change in the same commits as a function, with how often and whether a call links them. Needs git history imported with cie history. Use before editing a function to find what else probably needs to change, and to spot hidden coupling the call graph does not show.
+
+**cie_impact** — What a change could break: maps the hunks of a diff (two git refs, or a raw unified diff) onto functions and types, follows their callers through calls, interfaces and type references, and lists the affected HTTP endpoints, gRPC methods, entry points and tests ranked by distance. Use before merging or when reviewing a pull request. The index must match the new side of the diff.
+
+**cie_semantic_diff** — Structural summary of the changes between two git refs: functions added, removed, renamed or moved (matched by body hash), with a changed signature or only a changed body, and the call edges added and removed. Both refs are parsed from git, so the index does not need to match either. Use when reviewing a pull request instead of reading the line diff.
+
 ### Database Tools
 
 **cie_schema** — Get the CIE database schema, tables, fields, and example queries. Call this FIRST before using cie_raw_query.
@@ -182,6 +235,7 @@ Several tools share these parameters:
 - **exclude_pattern**: Regex to exclude files. Use [.] instead of \. for literal dots (e.g., "_test[.]go" not "_test\.go"). Combine with | for multiple patterns: "_test[.]go|[.]pb[.]go".
 - **role**: Filter by code role. Values: "source" (excludes tests/generated), "test", "generated", "any". Default is usually "source".
 - **limit**: Cap the number of results. Increase if you need more context; decrease for faster responses.
+- **as_of**: Query the index snapshot of a past commit (SHA or ref, e.g. "v2.3.0") instead of HEAD. Snapshots are built with 'cie index --ref <ref>'. Supported by the structural tools; semantic search, coverage and git history tools always read HEAD, and cie_raw_query rejects as_of queries on their relations.
 
 ## Common Mistakes to Avoid
 
@@ -304,6 +358,7 @@ type mcpServer struct {
 	embeddingModel string
 	customRoles    map[string]RolePattern // Custom role patterns from config
 	gitExecutor    tools.GitRunner        // Git executor for history tools (may be nil)
+	snapshot       *tools.Snapshot        // Snapshot client reads for as_of calls, nil for HEAD
 	// Для embedded: реиндекс и конфиг
 	backend    *storage.EmbeddedBackend
 	cfg        *Config
@@ -573,7 +628,7 @@ func serveMCPLoop(server *mcpServer) {
 }
 
 func (s *mcpServer) getTools() []mcpTool {
-	return []mcpTool{
+	return withAsOfParam([]mcpTool{
 		{
 			Name:        "cie_index_status",
 			Description: "Check the indexing status for a path. Shows how many files and functions are indexed, and warns if the index appears incomplete. Use this FIRST when searches return no results to verify the path is indexed. When reindex is running, also shows in_progress and elapsed time. Use file_path to check if a single file is indexed (diagnostics).",
@@ -651,7 +706,7 @@ func (s *mcpServer) getTools() []mcpTool {
 		},
 		{
 			Name:        "cie_find_function",
-			Description: "Find functions by name. Handles Go receiver syntax (e.g., searching 'Batch' finds 'Batcher.Batch'). Returns function details including signature, location, and code.",
+			Description: "Find functions by name. Handles Go receiver syntax (e.g., searching 'Batch' finds 'Batcher.Batch'). Returns function details including signature, location, and code, and line coverage when a coverage profile was imported.",
 			InputSchema: map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -688,6 +743,11 @@ func (s *mcpServer) getTools() []mcpTool {
 						"description": "If true, include transitive callers (callers of callers, up to 3 levels deep). Default: false",
 						"default":     false,
 					},
+					"include_refs": map[string]any{
+						"type":        "boolean",
+						"description": "If true, also include functions that pass, assign or return it as a function value (callbacks, handler registration such as r.GET(path, h.ListUsers)). Go only. Default: false",
+						"default":     false,
+					},
 				},
 				"required": []string{"function_name"},
 			},
@@ -711,6 +771,29 @@ func (s *mcpServer) getTools() []mcpTool {
 				"required": []string{"function_name"},
 			},
 		},
+		{
+			Name:        "cie_find_references",
+			Description: "List every reference to a function or type, grouped by kind: calls, type uses (parameters, return values, struct fields, composite literals, conversions, type assertions), embeds, and imports of the defining package. Includes test files. Use this before renaming or changing a type such as tools.QueryResult to see its full impact. Type uses are recorded for Go.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name": map[string]any{
+						"type":        "string",
+						"description": "Function, method or type name (e.g., 'QueryResult', 'Parser.ParseFile', or package-qualified 'tools.QueryResult')",
+					},
+					"path_pattern": map[string]any{
+						"type":        "string",
+						"description": "Optional regex to filter the files containing references (e.g., '^pkg/')",
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum references listed per kind (default: 50)",
+						"default":     50,
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
 		{
 			Name:        "cie_find_type",
 			Description: "Find types, interfaces, classes, or structs by name or pattern. Works across all languages: Go (struct/interface), Python (class), TypeScript (interface/class). Use this to find architectural definitions.",
@@ -745,6 +828,40 @@ func (s *mcpServer) getTools() []mcpTool {
 				"required": []string{"name"},
 			},
 		},
+		{
+			Name:        "cie_find_variable",
+			Description: "Find package-level variables and constants by name or pattern: Go var/const declarations, Python module-level assignments and exported JavaScript/TypeScript const/let/var. Returns kind, declared type, initializer snippet and location. Use this to answer 'where is DefaultTimeout defined' or 'what global registries exist'.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name": map[string]any{
+						"type":        "string",
+						"description": "Variable or constant name to search for (e.g., 'DefaultTimeout', 'Registry')",
+					},
+					"kind": map[string]any{
+						"type":        "string",
+						"enum":        []string{"any", "var", "const", "let"},
+						"description": "Filter by declaration kind: 'var', 'const', 'let', or 'any' (default)",
+						"default":     "any",
+					},
+					"path_pattern": map[string]any{
+						"type":        "string",
+						"description": "Optional regex pattern to filter file paths",
+					},
+					"include_code": map[string]any{
+						"type":        "boolean",
+						"description": "If true, include the full declaration source (the value is otherwise truncated)",
+						"default":     false,
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum results (default: 20)",
+						"default":     20,
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
 		{
 			Name:        "cie_list_files",
 			Description: "List files in the indexed codebase. Can filter by language, path pattern, or role.",
@@ -790,7 +907,7 @@ func (s *mcpServer) getTools() []mcpTool {
 		},
 		{
 			Name:        "cie_get_function_code",
-			Description: "Get the full source code of a specific function by name. Returns the complete function implementation.",
+			Description: "Get the full source code of a specific function by name. Returns the complete function implementation, with its line coverage and uncovered lines when a coverage profile was imported.",
 			InputSchema: map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -896,6 +1013,11 @@ func (s *mcpServer) getTools() []mcpTool {
 						"type":        "number",
 						"description": "Minimum similarity threshold (0.0-1.0, e.g., 0.5 = 50%). Only return results above this similarity score.",
 					},
+					"include_docs": map[string]any{
+						"type":        "boolean",
+						"description": "Also return matching Markdown doc sections (README, ADRs, docs/*.md) after the code results (default: false)",
+						"default":     false,
+					},
 					"limit": map[string]any{
 						"type":        "integer",
 						"description": "Maximum number of results (default: 10, max: 50)",
@@ -931,7 +1053,7 @@ func (s *mcpServer) getTools() []mcpTool {
 		},
 		{
 			Name:        "cie_grep",
-			Description: "Ultra-fast literal text search (like grep). Searches for EXACT text - no regex. Supports multi-pattern search via 'texts' array for batch searches (reduces API calls). Perfect for searching code patterns like '.GET(', '->', '::new', 'import'.",
+			Description: "Ultra-fast literal text search (like grep). Searches for EXACT text - no regex. Searches function bodies and package-level variable/constant declarations. Supports multi-pattern search via 'texts' array for batch searches (reduces API calls). Perfect for searching code patterns like '.GET(', '->', '::new', 'import'.",
 			InputSchema: map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -1042,9 +1164,46 @@ func (s *mcpServer) getTools() []mcpTool {
 				"required": []string{"path"},
 			},
 		},
+		{
+			Name:        "cie_package_graph",
+			Description: "Report the package dependency graph built from imports: for each package (directory of source files) its file count, fan-in (packages importing it), fan-out (packages it imports) and instability (fan-out / (fan-in + fan-out)), plus the import cycles between packages with one concrete cycle path each. Use path to focus on a subtree; when path is exactly one package, its imports and importers are listed too.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path": map[string]any{
+						"type":        "string",
+						"description": "Optional: package directory to report on, including subpackages (e.g., 'pkg/ingestion'). Omit for the whole repository.",
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum packages to list (default: 50)",
+						"default":     50,
+					},
+				},
+				"required": []string{},
+			},
+		},
+		{
+			Name:        "cie_concurrency_map",
+			Description: "Map the Go concurrency of a function or package: the spawn tree of goroutines started with go (and for a function, the goroutines they start in turn), the functions sending to (producers) and receiving from (consumers) each channel, the order in which each function acquires mutexes, and potential lock-order inversions where two functions take the same pair of mutexes in opposite orders. Channel and mutex fields are matched by type (Pool.jobs, Pool.mu) across the repository. Lock order follows source lines, so inversions are candidates to review. Use for debugging races, deadlocks and goroutine leaks.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"function": map[string]any{
+						"type":        "string",
+						"description": "Function or method to map (e.g., 'Pool.Start', 'runWorkers')",
+					},
+					"path": map[string]any{
+						"type":        "string",
+						"description": "Package directory to map, including subpackages (e.g., 'internal/worker'). Used when function is not set.",
+					},
+				},
+				"required": []string{},
+			},
+		},
 		{
 			Name:        "cie_list_endpoints",
-			Description: "List HTTP/REST endpoints defined in the codebase. Detects route definitions from common Go frameworks (Gin, Echo, Chi, Fiber, net/http). Returns a table of [Method] [Path] [Handler] [File]. Perfect for understanding API structure in gateway/server code.",
+			Description: "List HTTP/REST endpoints defined in the codebase. Detects route definitions from common Go frameworks (Gin, Echo, Chi, Fiber, net/http) and PHP frameworks (Laravel Route::get, Symfony #[Route]). Returns a table of [Method] [Path] [Handler] [File]. Perfect for understanding API structure in gateway/server code.",
 			InputSchema: map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -1070,9 +1229,101 @@ func (s *mcpServer) getTools() []mcpTool {
 				"required": []string{},
 			},
 		},
+		{
+			Name:        "cie_find_table_usage",
+			Description: "Find which functions read or write a SQL table. Shows the table schema (columns, indexes, foreign keys) from indexed .sql migrations/schema dumps, then the accessing functions grouped by Writes and Reads with file:line. Accesses are detected from SQL string literals passed to database calls (database/sql, sqlx, DB-API, node-postgres, Laravel DB) and ORM table selectors (GORM, Laravel, knex). Omit table to list all known tables with reader/writer counts.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"table": map[string]any{
+						"type":        "string",
+						"description": "Table name (case-insensitive, schema prefix ignored, e.g., 'orders', 'public.orders'). Omit to list all tables.",
+					},
+					"access": map[string]any{
+						"type":        "string",
+						"enum":        []string{"read", "write", ""},
+						"description": "Optional: only show reads or writes",
+					},
+					"path_pattern": map[string]any{
+						"type":        "string",
+						"description": "Optional: filter accessing functions by file path regex (e.g., 'internal/store')",
+					},
+					"include_tests": map[string]any{
+						"type":        "boolean",
+						"description": "Include functions in test files (default: false)",
+						"default":     false,
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum functions per access kind (default: 50)",
+						"default":     50,
+					},
+				},
+				"required": []string{},
+			},
+		},
+		{
+			Name:        "cie_list_graphql_operations",
+			Description: "List GraphQL operations (queries, mutations, subscriptions) declared in .graphql/.graphqls SDL files, with arguments, return type and the resolver functions bound to each field. Resolvers are linked from gqlgen resolver methods (queryResolver.Orders) and Apollo resolver maps ({ Query: { orders } }). Use type_name to list the fields of an object type and their field resolvers.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"operation": map[string]any{
+						"type":        "string",
+						"enum":        []string{"query", "mutation", "subscription", ""},
+						"description": "Optional: only show one operation kind",
+					},
+					"type_name": map[string]any{
+						"type":        "string",
+						"description": "Optional: list the fields of this type instead of root operations (e.g., 'Order')",
+					},
+					"name_filter": map[string]any{
+						"type":        "string",
+						"description": "Optional: filter by field name (case-insensitive substring, e.g., 'order')",
+					},
+					"path_pattern": map[string]any{
+						"type":        "string",
+						"description": "Optional: filter by SDL file path regex (e.g., 'services/orders')",
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum operations to return (default: 100)",
+						"default":     100,
+					},
+				},
+				"required": []string{},
+			},
+		},
+		{
+			Name:        "cie_deployment_topology",
+			Description: "Answer how code is deployed: which Dockerfiles, docker-compose services, Kubernetes workloads and Terraform resources run a Go binary (cmd/*), which environment variables they receive (literals, secrets, config maps) and which Kubernetes Service or published port exposes it. Dockerfile entrypoints are traced through multi-stage builds to the go build package, and workloads are linked to Dockerfiles through the image they run. Omit target to list all deployable units.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"target": map[string]any{
+						"type":        "string",
+						"description": "Main package path (e.g., 'cmd/worker'), binary name, service/resource name or image name. Omit to list all units.",
+					},
+					"kind": map[string]any{
+						"type":        "string",
+						"description": "Optional, list mode: filter by kind ('dockerfile', 'compose_service', 'k8s_deployment', 'k8s_service', 'terraform_resource', ...)",
+					},
+					"path_pattern": map[string]any{
+						"type":        "string",
+						"description": "Optional, list mode: filter by file path regex (e.g., 'deploy/')",
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum units to list (default: 100)",
+						"default":     100,
+					},
+				},
+				"required": []string{},
+			},
+		},
 		{
 			Name:        "cie_find_implementations",
-			Description: "Find types that implement a given interface. For Go: finds structs with methods matching the interface. For TypeScript: finds classes with 'implements InterfaceName'. Useful for understanding interface usage and finding concrete implementations.",
+			Description: "Find types that implement a given interface. For Go: finds structs with methods matching the interface. For Go constraints (~int | ~float64): finds types in the type set. For TypeScript: finds classes with 'implements InterfaceName'. Useful for understanding interface usage and finding concrete implementations.",
 			InputSchema: map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -1177,10 +1428,155 @@ func (s *mcpServer) getTools() []mcpTool {
 						"description": "Maximum lines per type definition when include_types=true (default: 15).",
 						"default":     15,
 					},
+					"include_refs": map[string]any{
+						"type":        "boolean",
+						"description": "If true, also follow function references: a function passed as a callback or registered as a handler is treated as reached from the function that references it. Annotated with [referenced as argument|assignment|return]. Go only. Default: false",
+						"default":     false,
+					},
 				},
 				"required": []string{"target"},
 			},
 		},
+		{
+			Name:        "cie_trace_error",
+			Description: "Trace where an error comes from and how it propagates. Given an error message (or a fragment of it) or a sentinel, error type or exception name, returns the declarations (var ErrNotFound = errors.New(...), types with an Error() method), the origin functions that create, wrap, return or raise it (Go errors.New, fmt.Errorf with %w, sentinel returns, custom error types; Python raise; JS/TS throw), and for each origin the chain of callers it propagates through via the call graph, annotated with the message each caller wraps it with. A message such as 'failed to resolve project: not found' is split on ': ' so each wrapping layer is matched. Leave out values interpolated at runtime.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{
+						"type":        "string",
+						"description": "Error message or fragment (e.g., 'failed to resolve project: not found'), or a sentinel, error type or exception name (e.g., 'ErrNotFound', 'ValueError')",
+					},
+					"max_depth": map[string]any{
+						"type":        "integer",
+						"description": "Caller levels to follow from each origin (default: 4, max: 8)",
+						"default":     4,
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			Name:        "cie_find_tests",
+			Description: "Find the tests exercising a function: test functions, subtests and test cases that call it directly or through a chain of callers. Recognizes Go Test/Benchmark/Fuzz/Example functions, t.Run subtests and testify suite methods, pytest test functions and Test* class methods, and Jest/Vitest it/test cases inside describe blocks. Results are grouped by file with the call chain from each test, followed by the commands running only those tests (go test -run, pytest node IDs, jest/vitest file). Use after editing a function to know which tests to run.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"function": map[string]any{
+						"type":        "string",
+						"description": "Function or method name (e.g., 'Resolve', 'Store.Get')",
+					},
+					"max_depth": map[string]any{
+						"type":        "integer",
+						"description": "Caller levels to follow from the function to the tests (default: 4, max: 8)",
+						"default":     4,
+					},
+				},
+				"required": []string{"function"},
+			},
+		},
+		{
+			Name:        "cie_find_untested",
+			Description: "List exported functions that no indexed test reaches through the call graph, grouped by file. Exported means capitalized in Go and not underscore-prefixed in Python and JS/TS. Test files, generated code and anonymous functions are excluded. Functions called only through interfaces, reflection or callbacks may be listed although a test runs them.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path": map[string]any{
+						"type":        "string",
+						"description": "Directory or file path prefix to restrict the search to (e.g., 'internal/store'). Default: whole project",
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum functions to list (default: 100)",
+						"default":     100,
+					},
+				},
+			},
+		},
+		{
+			Name:        "cie_coverage_gaps",
+			Description: "List the least-covered functions on the call paths starting at a function, and optionally ending at a target: every function the start reaches through the call graph within max_depth levels (and that reaches the target), sorted by line coverage, with covered/total statements and uncovered line ranges. Coverage comes from a profile imported with 'cie coverage import' (go test -coverprofile, coverage.py XML, lcov).",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"function": map[string]any{
+						"type":        "string",
+						"description": "Function the call path starts from (e.g., 'main', 'Server.HandleOrder')",
+					},
+					"target": map[string]any{
+						"type":        "string",
+						"description": "Optional function the call path ends at: only functions between function and target are listed",
+					},
+					"max_depth": map[string]any{
+						"type":        "integer",
+						"description": "Call levels to follow (default: 4, max: 8)",
+						"default":     4,
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum functions to list (default: 20)",
+						"default":     20,
+					},
+				},
+				"required": []string{"function"},
+			},
+		},
+		{
+			Name:        "cie_find_dead_code",
+			Description: "List functions and types that no reachability root reaches, grouped by confidence and file. Reachability follows calls and function references from the roots; methods of types implementing an interface are reached when reachable code uses the interface (cie_implements), so interface dispatch is not reported. Roots: main (entry points), init (Go init, Python dunder methods, constructors), tests, exported (exported Go API of library packages, Python __init__.py), handlers (HTTP handlers, routers, GraphQL resolvers) and custom roles from the project config. Confidence is high for unexported Go code, medium for exported Go names and private Python/JS names, low for other Python/JS/TS functions and for methods of types implementing an unused interface.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path": map[string]any{
+						"type":        "string",
+						"description": "Directory to report on (e.g., 'internal/store'). Reachability is always computed over the whole project. Default: whole project",
+					},
+					"roots": map[string]any{
+						"type":        "array",
+						"items":       map[string]any{"type": "string"},
+						"description": "Reachability roots: main, init, tests, exported, handlers, or custom role names from .cie/project.yaml. Default: all built-in roots",
+					},
+					"min_confidence": map[string]any{
+						"type":        "string",
+						"description": "Lowest confidence to list: high, medium or low (default: low)",
+						"enum":        []string{"high", "medium", "low"},
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum entries to list (default: 100)",
+						"default":     100,
+					},
+				},
+			},
+		},
+		{
+			Name:        "cie_hotspots",
+			Description: "Rank functions by cyclomatic complexity combined with fan-in (distinct callers in the call graph). Metrics are computed from the syntax tree at indexing for Go, Python, JavaScript, TypeScript and PHP: cyclomatic complexity, max nesting depth, parameter count, non-blank lines and comment ratio. Score = complexity x (1 + log2(1 + fan-in)), so complex functions many others depend on rank first. When git history was imported with cie history, commits and authors are listed and sort_by=churn ranks by commits x complexity. Test files, generated code and anonymous functions are excluded.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path": map[string]any{
+						"type":        "string",
+						"description": "Directory to rank (e.g., 'pkg/tools'). Default: whole project",
+					},
+					"sort_by": map[string]any{
+						"type":        "string",
+						"description": "Ranking key: score (complexity weighted by fan-in), complexity, fan_in, nesting, loc or churn (commits x complexity, needs cie history) (default: score)",
+						"enum":        []string{"score", "complexity", "fan_in", "nesting", "loc", "churn"},
+					},
+					"min_complexity": map[string]any{
+						"type":        "integer",
+						"description": "Only list functions with at least this cyclomatic complexity (default: 1)",
+						"default":     1,
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum functions to list (default: 20)",
+						"default":     20,
+					},
+				},
+			},
+		},
 		{
 			Name:        "cie_function_history",
 			Description: "Get git commit history for a specific function. Tracks changes to the function over time using line-based git history. Useful for understanding when and why a function was modified.",
@@ -1253,7 +1649,84 @@ func (s *mcpServer) getTools() []mcpTool {
 				"required": []string{"function_name"},
 			},
 		},
-	}
+		{
+			Name:        "cie_change_coupling",
+			Description: "List the functions that usually change in the same commits as a function (change coupling), from the git history imported with cie history. Each entry has the commits changing both and the degree: the share of the function's commits that also changed the other. Entries without a call between the two functions reveal hidden coupling (shared data, duplicated logic, protocols). Commits changing many functions at once (bulk renames, reformats) are left out.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"function": map[string]any{
+						"type":        "string",
+						"description": "Function to analyze (e.g., 'TracePath', 'Server.HandleOrder')",
+					},
+					"path_pattern": map[string]any{
+						"type":        "string",
+						"description": "Optional: disambiguate when multiple functions have the same name",
+					},
+					"min_degree": map[string]any{
+						"type":        "number",
+						"description": "Lowest share of the function's commits a coupled function must appear in, from 0 to 1 (default: 0.2)",
+						"default":     0.2,
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum functions to list (default: 15)",
+						"default":     15,
+					},
+				},
+				"required": []string{"function"},
+			},
+		},
+		{
+			Name:        "cie_impact",
+			Description: "Report what a change could break. Takes two git refs (base and head; without head, base is compared with the working tree) or a raw unified diff. Changed hunks are mapped onto the indexed functions and types, and their transitive callers are followed through the call graph, interface implementations and type references. Lists the affected HTTP endpoints, gRPC methods, entry points and tests (with the commands running them), then every affected function, all ranked by distance from the change. The index must match the new side of the diff.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"base": map[string]any{
+						"type":        "string",
+						"description": "Git ref the change starts from (e.g., 'main', 'origin/main', a commit SHA)",
+					},
+					"head": map[string]any{
+						"type":        "string",
+						"description": "Optional: git ref the change ends at (e.g., 'HEAD'). Empty compares base with the working tree",
+					},
+					"diff": map[string]any{
+						"type":        "string",
+						"description": "Raw unified diff to analyze instead of git refs (git diff or diff -u output)",
+					},
+					"max_depth": map[string]any{
+						"type":        "integer",
+						"description": "Caller levels to follow from the changed symbols (default: 5, max: 10)",
+						"default":     5,
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum affected functions to list (default: 50)",
+						"default":     50,
+					},
+				},
+			},
+		},
+		{
+			Name:        "cie_semantic_diff",
+			Description: "Summarize the changes between two git refs by function instead of by line. The changed files are parsed at both refs and their functions compared: added, removed, renamed or moved (same body, matched by body hash), signature changed, or body-only changed. Call edges added and removed are listed by caller and callee name. Nothing is checked out and the index is not used.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"base": map[string]any{
+						"type":        "string",
+						"description": "Git ref the change starts from (e.g., 'main', 'v1.2.0', a commit SHA)",
+					},
+					"head": map[string]any{
+						"type":        "string",
+						"description": "Optional: git ref the change ends at (e.g., 'HEAD'). Empty compares base with the working tree",
+					},
+				},
+				"required": []string{"base"},
+			},
+		},
+	})
 }
 
 // toolHandler is the signature for MCP tool handlers.
@@ -1266,6 +1739,7 @@ var toolHandlers = map[string]toolHandler{
 	"cie_find_function":          handleFindFunction,
 	"cie_find_callers":           handleFindCallers,
 	"cie_find_callees":           handleFindCallees,
+	"cie_find_references":        handleFindReferences,
 	"cie_list_files":             handleListFiles,
 	"cie_raw_query":              handleRawQuery,
 	"cie_get_function_code":      handleGetFunctionCode,
@@ -1276,19 +1750,92 @@ var toolHandlers = map[string]toolHandler{
 	"cie_semantic_search":        handleSemanticSearch,
 	"cie_analyze":                handleAnalyze,
 	"cie_find_type":              handleFindType,
+	"cie_find_variable":          handleFindVariable,
 	"cie_index_status":           handleIndexStatus,
 	"cie_reindex":                handleReindex,
 	"cie_grep":                   handleGrep,
 	"cie_verify_absence":         handleVerifyAbsence,
 	"cie_list_services":          handleListServices,
 	"cie_directory_summary":      handleDirectorySummary,
+	"cie_package_graph":          handlePackageGraph,
+	"cie_concurrency_map":        handleConcurrencyMap,
+	"cie_trace_error":            handleTraceError,
+	"cie_find_tests":             handleFindTests,
+	"cie_find_untested":          handleFindUntested,
+	"cie_coverage_gaps":          handleCoverageGaps,
+	"cie_find_dead_code":         handleFindDeadCode,
+	"cie_hotspots":               handleHotspots,
 	"cie_list_endpoints":         handleListEndpoints,
+	"cie_find_table_usage":       handleFindTableUsage,
 	"cie_find_implementations":   handleFindImplementations,
 	"cie_find_by_signature":      handleFindBySignature,
 	"cie_trace_path":             handleTracePath,
 	"cie_function_history":       handleFunctionHistory,
 	"cie_find_introduction":      handleFindIntroduction,
 	"cie_blame_function":         handleBlameFunction,
+	"cie_change_coupling":        handleChangeCoupling,
+	"cie_impact":                 handleImpact,
+	"cie_semantic_diff":          handleSemanticDiff,
+
+	// GraphQL
+	"cie_list_graphql_operations": handleListGraphQLOperations,
+
+	// Infrastructure
+	"cie_deployment_topology": handleDeploymentTopology,
+}
+
+// asOfTools lists the tools accepting as_of: those reading only relations
+// kept in snapshots, so not embeddings, coverage or git history. The
+// coverage that cie_find_function and cie_get_function_code show for HEAD is
+// left out of their as_of output, and cie_raw_query rejects as_of queries
+// reading relations without history.
+var asOfTools = map[string]bool{
+	"cie_search_text":             true,
+	"cie_find_function":           true,
+	"cie_find_callers":            true,
+	"cie_find_callees":            true,
+	"cie_find_references":         true,
+	"cie_list_files":              true,
+	"cie_raw_query":               true,
+	"cie_get_function_code":       true,
+	"cie_list_functions_in_file":  true,
+	"cie_get_call_graph":          true,
+	"cie_get_file_summary":        true,
+	"cie_find_type":               true,
+	"cie_find_variable":           true,
+	"cie_grep":                    true,
+	"cie_verify_absence":          true,
+	"cie_list_services":           true,
+	"cie_directory_summary":       true,
+	"cie_package_graph":           true,
+	"cie_concurrency_map":         true,
+	"cie_trace_error":             true,
+	"cie_find_tests":              true,
+	"cie_find_untested":           true,
+	"cie_find_dead_code":          true,
+	"cie_list_endpoints":          true,
+	"cie_find_table_usage":        true,
+	"cie_find_implementations":    true,
+	"cie_find_by_signature":       true,
+	"cie_trace_path":              true,
+	"cie_list_graphql_operations": true,
+	"cie_deployment_topology":     true,
+}
+
+// withAsOfParam adds the as_of parameter to the input schema of asOfTools.
+func withAsOfParam(list []mcpTool) []mcpTool {
+	for _, tool := range list {
+		if !asOfTools[tool.Name] {
+			continue
+		}
+		if props, ok := tool.InputSchema["properties"].(map[string]any); ok {
+			props["as_of"] = map[string]any{
+				"type":        "string",
+				"description": "Optional: query the index snapshot of this commit (SHA, SHA prefix or ref such as 'v2.3.0') instead of HEAD. Snapshots are built with 'cie index --ref <ref>'",
+			}
+		}
+	}
+	return list
 }
 
 func (s *mcpServer) handleToolCall(ctx context.Context, params mcpToolCallParams) (*mcpToolResult, error) {
@@ -1300,17 +1847,54 @@ func (s *mcpServer) handleToolCall(ctx context.Context, params mcpToolCallParams
 		}, nil
 	}
 
-	result, err := handler(ctx, s, params.Arguments)
+	server, banner := s, ""
+	if asOf, _ := params.Arguments["as_of"].(string); strings.TrimSpace(asOf) != "" {
+		if !asOfTools[params.Name] {
+			return &mcpToolResult{
+				Content: []mcpContent{{Type: "text", Text: fmt.Sprintf("%s does not support as_of: snapshots keep no embeddings, coverage or git history", params.Name)}},
+				IsError: true,
+			}, nil
+		}
+		snapshot, err := findSnapshot(ctx, s.client, s.gitExecutor, strings.TrimSpace(asOf))
+		if err != nil {
+			return &mcpToolResult{
+				Content: []mcpContent{{Type: "text", Text: err.Error()}},
+				IsError: true,
+			}, nil
+		}
+		server = s.asOf(snapshot)
+		banner = fmt.Sprintf("_As of snapshot %s_\n\n", snapshot.Label())
+	}
+
+	result, err := handler(ctx, server, params.Arguments)
 	if err != nil {
 		return s.formatError(params.Name, err), nil
 	}
 
 	return &mcpToolResult{
-		Content: []mcpContent{{Type: "text", Text: result.Text}},
+		Content: []mcpContent{{Type: "text", Text: banner + result.Text}},
 		IsError: result.IsError,
 	}, nil
 }
 
+// asOf returns a server whose queries read the given snapshot. It has no
+// embedded backend, so it cannot reindex.
+func (s *mcpServer) asOf(snapshot *tools.Snapshot) *mcpServer {
+	return &mcpServer{
+		client:         tools.NewSnapshotQuerier(s.client, snapshot.Seq),
+		snapshot:       snapshot,
+		projectID:      s.projectID,
+		mode:           s.mode,
+		embeddingURL:   s.embeddingURL,
+		embeddingModel: s.embeddingModel,
+		customRoles:    s.customRoles,
+		gitExecutor:    s.gitExecutor,
+		cfg:            s.cfg,
+		configPath:     s.configPath,
+		repoPath:       s.repoPath,
+	}
+}
+
 func handleSchema(ctx context.Context, _ *mcpServer, _ map[string]any) (*tools.ToolResult, error) {
 	return tools.GetSchema(ctx)
 }
@@ -1341,15 +1925,18 @@ func handleFindFunction(ctx context.Context, s *mcpServer, args map[string]any)
 		Name:        name,
 		ExactMatch:  exactMatch,
 		IncludeCode: includeCode,
+		AsOf:        s.snapshot != nil,
 	})
 }
 
 func handleFindCallers(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
 	funcName, _ := args["function_name"].(string)
 	includeIndirect, _ := args["include_indirect"].(bool)
+	includeRefs, _ := args["include_refs"].(bool)
 	return tools.FindCallers(ctx, s.client, tools.FindCallersArgs{
 		FunctionName:    funcName,
 		IncludeIndirect: includeIndirect,
+		IncludeRefs:     includeRefs,
 	})
 }
 
@@ -1362,6 +1949,17 @@ func handleFindCallees(ctx context.Context, s *mcpServer, args map[string]any) (
 	})
 }
 
+func handleFindReferences(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	name, _ := args["name"].(string)
+	pathPattern, _ := args["path_pattern"].(string)
+	limit, _ := getIntArg(args, "limit", 50)
+	return tools.FindReferences(ctx, s.client, tools.FindReferencesArgs{
+		Name:        name,
+		PathPattern: pathPattern,
+		Limit:       limit,
+	})
+}
+
 func handleListFiles(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
 	pathPattern, _ := args["path_pattern"].(string)
 	language, _ := args["language"].(string)
@@ -1380,6 +1978,7 @@ func handleRawQuery(ctx context.Context, s *mcpServer, args map[string]any) (*to
 	script, _ := args["script"].(string)
 	return tools.RawQuery(ctx, s.client, tools.RawQueryArgs{
 		Script: script,
+		AsOf:   s.snapshot != nil,
 	})
 }
 
@@ -1389,6 +1988,7 @@ func handleGetFunctionCode(ctx context.Context, s *mcpServer, args map[string]an
 	return tools.GetFunctionCode(ctx, s.client, tools.GetFunctionCodeArgs{
 		FunctionName: funcName,
 		FullCode:     fullCode,
+		AsOf:         s.snapshot != nil,
 	})
 }
 
@@ -1431,6 +2031,7 @@ func handleSemanticSearch(ctx context.Context, s *mcpServer, args map[string]any
 		excludeAnonymous = v
 	}
 	minSimilarity, _ := getFloatArg(args, "min_similarity", 0)
+	includeDocs, _ := args["include_docs"].(bool)
 
 	return tools.SemanticSearch(ctx, s.client, tools.SemanticSearchArgs{
 		Query:            query,
@@ -1440,6 +2041,7 @@ func handleSemanticSearch(ctx context.Context, s *mcpServer, args map[string]any
 		ExcludePaths:     excludePaths,
 		ExcludeAnonymous: excludeAnonymous,
 		MinSimilarity:    minSimilarity,
+		IncludeDocs:      includeDocs,
 		EmbeddingURL:     s.embeddingURL,
 		EmbeddingModel:   s.embeddingModel,
 	})
@@ -1471,6 +2073,21 @@ func handleFindType(ctx context.Context, s *mcpServer, args map[string]any) (*to
 	})
 }
 
+func handleFindVariable(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	name, _ := args["name"].(string)
+	kind, _ := args["kind"].(string)
+	pathPattern, _ := args["path_pattern"].(string)
+	includeCode, _ := args["include_code"].(bool)
+	limit, _ := getIntArg(args, "limit", 20)
+	return tools.FindVariable(ctx, s.client, tools.FindVariableArgs{
+		Name:        name,
+		Kind:        kind,
+		PathPattern: pathPattern,
+		IncludeCode: includeCode,
+		Limit:       limit,
+	})
+}
+
 func handleIndexStatus(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
 	pathPattern, _ := args["path_pattern"].(string)
 	filePath, _ := args["file_path"].(string)
@@ -1703,6 +2320,90 @@ func handleDirectorySummary(ctx context.Context, s *mcpServer, args map[string]a
 	return tools.DirectorySummary(ctx, s.client, path, maxFuncs)
 }
 
+func handlePackageGraph(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	path, _ := args["path"].(string)
+	limit, _ := getIntArg(args, "limit", 50)
+	return tools.PackageGraph(ctx, s.client, tools.PackageGraphArgs{
+		Path:  path,
+		Limit: limit,
+	})
+}
+
+func handleConcurrencyMap(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	function, _ := args["function"].(string)
+	path, _ := args["path"].(string)
+	return tools.ConcurrencyMap(ctx, s.client, tools.ConcurrencyMapArgs{
+		Function: function,
+		Path:     path,
+	})
+}
+
+func handleTraceError(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	query, _ := args["query"].(string)
+	maxDepth, _ := getIntArg(args, "max_depth", 4)
+	return tools.TraceError(ctx, s.client, tools.TraceErrorArgs{
+		Query:    query,
+		MaxDepth: maxDepth,
+	})
+}
+
+func handleFindTests(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	function, _ := args["function"].(string)
+	maxDepth, _ := getIntArg(args, "max_depth", 4)
+	return tools.FindTests(ctx, s.client, tools.FindTestsArgs{
+		Function: function,
+		MaxDepth: maxDepth,
+	})
+}
+
+func handleFindUntested(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	path, _ := args["path"].(string)
+	limit, _ := getIntArg(args, "limit", 100)
+	return tools.FindUntested(ctx, s.client, tools.FindUntestedArgs{
+		Path:  path,
+		Limit: limit,
+	})
+}
+
+func handleCoverageGaps(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	function, _ := args["function"].(string)
+	target, _ := args["target"].(string)
+	maxDepth, _ := getIntArg(args, "max_depth", 4)
+	limit, _ := getIntArg(args, "limit", 20)
+	return tools.CoverageGaps(ctx, s.client, tools.CoverageGapsArgs{
+		Function: function,
+		Target:   target,
+		MaxDepth: maxDepth,
+		Limit:    limit,
+	})
+}
+
+func handleFindDeadCode(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	path, _ := args["path"].(string)
+	minConfidence, _ := args["min_confidence"].(string)
+	limit, _ := getIntArg(args, "limit", 100)
+	return tools.FindDeadCode(ctx, s.client, tools.FindDeadCodeArgs{
+		Path:          path,
+		Roots:         extractStringArray(args, "roots"),
+		CustomRoles:   toolRolePatterns(s.customRoles),
+		MinConfidence: minConfidence,
+		Limit:         limit,
+	})
+}
+
+func handleHotspots(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	path, _ := args["path"].(string)
+	sortBy, _ := args["sort_by"].(string)
+	minComplexity, _ := getIntArg(args, "min_complexity", 1)
+	limit, _ := getIntArg(args, "limit", 20)
+	return tools.Hotspots(ctx, s.client, tools.HotspotsArgs{
+		Path:          path,
+		SortBy:        sortBy,
+		MinComplexity: minComplexity,
+		Limit:         limit,
+	})
+}
+
 func handleListEndpoints(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
 	pathPattern, _ := args["path_pattern"].(string)
 	pathFilter, _ := args["path_filter"].(string)
@@ -1716,6 +2417,49 @@ func handleListEndpoints(ctx context.Context, s *mcpServer, args map[string]any)
 	})
 }
 
+func handleFindTableUsage(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	table, _ := args["table"].(string)
+	access, _ := args["access"].(string)
+	pathPattern, _ := args["path_pattern"].(string)
+	includeTests, _ := args["include_tests"].(bool)
+	limit, _ := getIntArg(args, "limit", 50)
+	return tools.FindTableUsage(ctx, s.client, tools.FindTableUsageArgs{
+		Table:        table,
+		Access:       access,
+		PathPattern:  pathPattern,
+		IncludeTests: includeTests,
+		Limit:        limit,
+	})
+}
+
+func handleListGraphQLOperations(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	operation, _ := args["operation"].(string)
+	typeName, _ := args["type_name"].(string)
+	nameFilter, _ := args["name_filter"].(string)
+	pathPattern, _ := args["path_pattern"].(string)
+	limit, _ := getIntArg(args, "limit", 100)
+	return tools.ListGraphQLOperations(ctx, s.client, tools.ListGraphQLOperationsArgs{
+		Operation:   operation,
+		TypeName:    typeName,
+		NameFilter:  nameFilter,
+		PathPattern: pathPattern,
+		Limit:       limit,
+	})
+}
+
+func handleDeploymentTopology(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	target, _ := args["target"].(string)
+	kind, _ := args["kind"].(string)
+	pathPattern, _ := args["path_pattern"].(string)
+	limit, _ := getIntArg(args, "limit", 100)
+	return tools.DeploymentTopology(ctx, s.client, tools.DeploymentTopologyArgs{
+		Target:      target,
+		Kind:        kind,
+		PathPattern: pathPattern,
+		Limit:       limit,
+	})
+}
+
 func handleFindImplementations(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
 	interfaceName, _ := args["interface_name"].(string)
 	pathPattern, _ := args["path_pattern"].(string)
@@ -1753,6 +2497,7 @@ func handleTracePath(ctx context.Context, s *mcpServer, args map[string]any) (*t
 	codeLines, _ := getIntArg(args, "code_lines", 10)
 	includeTypes, _ := args["include_types"].(bool)
 	typeLines, _ := getIntArg(args, "type_lines", 15)
+	includeRefs, _ := args["include_refs"].(bool)
 	return tools.TracePath(ctx, s.client, tools.TracePathArgs{
 		Target:       target,
 		Source:       source,
@@ -1764,6 +2509,7 @@ func handleTracePath(ctx context.Context, s *mcpServer, args map[string]any) (*t
 		CodeLines:    codeLines,
 		IncludeTypes: includeTypes,
 		TypeLines:    typeLines,
+		IncludeRefs:  includeRefs,
 	})
 }
 
@@ -1811,6 +2557,56 @@ func handleBlameFunction(ctx context.Context, s *mcpServer, args map[string]any)
 	})
 }
 
+func handleChangeCoupling(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	function, _ := args["function"].(string)
+	pathPattern, _ := args["path_pattern"].(string)
+	minDegree, _ := getFloatArg(args, "min_degree", 0.2)
+	limit, _ := getIntArg(args, "limit", 15)
+	return tools.ChangeCoupling(ctx, s.client, tools.ChangeCouplingArgs{
+		Function:    function,
+		PathPattern: pathPattern,
+		MinDegree:   minDegree,
+		Limit:       limit,
+	})
+}
+
+func handleImpact(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	base, _ := args["base"].(string)
+	head, _ := args["head"].(string)
+	diff, _ := args["diff"].(string)
+	maxDepth, _ := getIntArg(args, "max_depth", 5)
+	limit, _ := getIntArg(args, "limit", 50)
+	if diff == "" && base == "" {
+		return tools.NewError("Error: pass either 'base' (and optionally 'head') or 'diff'"), nil
+	}
+	changes, headSHA, err := impactChanges(ctx, s.gitExecutor, diff, base, head)
+	if err != nil {
+		return tools.NewError(fmt.Sprintf("Cannot compute the diff: %v", err)), nil
+	}
+	return tools.Impact(ctx, s.client, tools.ImpactArgs{
+		Changes:  changes,
+		HeadSHA:  headSHA,
+		MaxDepth: maxDepth,
+		Limit:    limit,
+	})
+}
+
+func handleSemanticDiff(ctx context.Context, s *mcpServer, args map[string]any) (*tools.ToolResult, error) {
+	base, _ := args["base"].(string)
+	head, _ := args["head"].(string)
+	if base == "" {
+		return tools.NewError("Error: 'base' is required"), nil
+	}
+	if s.gitExecutor == nil {
+		return tools.NewError("Git repository not detected: cie_semantic_diff compares git refs"), nil
+	}
+	report, err := ingestion.DiffSymbols(ctx, s.gitExecutor, base, head)
+	if err != nil {
+		return tools.NewError(fmt.Sprintf("Cannot compare the refs: %v", err)), nil
+	}
+	return tools.SemanticDiff(report), nil
+}
+
 // extractStringArray extracts a string array from the arguments map.
 func extractStringArray(args map[string]any, key string) []string {
 	var result []string
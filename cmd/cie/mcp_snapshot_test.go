@@ -0,0 +1,113 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// snapshotQuerier records scripts and answers the snapshot catalog query.
+type snapshotQuerier struct {
+	scripts []string
+}
+
+func (q *snapshotQuerier) Query(_ context.Context, script string) (*tools.QueryResult, error) {
+	q.scripts = append(q.scripts, script)
+	if strings.Contains(script, "*cie_snapshot") {
+		return &tools.QueryResult{
+			Headers: []string{"seq", "sha", "ref", "indexed_at", "files", "functions"},
+			Rows:    [][]any{{float64(2), "0123456789abcdef0123456789abcdef01234567", "v2.3.0", float64(1700000000), float64(10), float64(42)}},
+		}, nil
+	}
+	return &tools.QueryResult{Headers: []string{"path"}, Rows: [][]any{}}, nil
+}
+
+func (q *snapshotQuerier) QueryRaw(ctx context.Context, script string) (map[string]any, error) {
+	_, err := q.Query(ctx, script)
+	return map[string]any{}, err
+}
+
+func TestAsOfToolsHaveParam(t *testing.T) {
+	s := &mcpServer{}
+	schemas := map[string]map[string]any{}
+	for _, tool := range s.getTools() {
+		schemas[tool.Name], _ = tool.InputSchema["properties"].(map[string]any)
+	}
+	for name := range asOfTools {
+		if _, ok := toolHandlers[name]; !ok {
+			t.Errorf("as_of tool %s has no handler", name)
+		}
+		if _, ok := schemas[name]["as_of"]; !ok {
+			t.Errorf("tool %s lacks the as_of parameter", name)
+		}
+	}
+	if _, ok := schemas["cie_semantic_search"]["as_of"]; ok {
+		t.Error("cie_semantic_search should not accept as_of: embeddings have no history")
+	}
+}
+
+func TestHandleToolCall_AsOf(t *testing.T) {
+	ctx := context.Background()
+	client := &snapshotQuerier{}
+	s := &mcpServer{client: client}
+
+	result, err := s.handleToolCall(ctx, mcpToolCallParams{
+		Name:      "cie_list_files",
+		Arguments: map[string]any{"as_of": "v2.3.0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError || !strings.HasPrefix(result.Content[0].Text, "_As of snapshot 0123456789ab (v2.3.0)_") {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	last := client.scripts[len(client.scripts)-1]
+	if !strings.Contains(last, "*cie_hist_file") || !strings.Contains(last, "@ 2 }") {
+		t.Errorf("query not rewritten for the snapshot: %s", last)
+	}
+
+	result, _ = s.handleToolCall(ctx, mcpToolCallParams{
+		Name:      "cie_list_files",
+		Arguments: map[string]any{"as_of": "v9.9.9"},
+	})
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "no snapshot") {
+		t.Errorf("expected unknown snapshot error, got %+v", result)
+	}
+
+	result, _ = s.handleToolCall(ctx, mcpToolCallParams{
+		Name:      "cie_raw_query",
+		Arguments: map[string]any{"script": "?[id] := *cie_coverage{function_id: id}", "as_of": "v2.3.0"},
+	})
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "no history of cie_coverage") {
+		t.Errorf("expected rejected coverage query, got %+v", result)
+	}
+
+	result, _ = s.handleToolCall(ctx, mcpToolCallParams{
+		Name:      "cie_semantic_search",
+		Arguments: map[string]any{"query": "x", "as_of": "v2.3.0"},
+	})
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "does not support as_of") {
+		t.Errorf("expected unsupported as_of error, got %+v", result)
+	}
+}
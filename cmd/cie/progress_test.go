@@ -171,6 +171,7 @@ func TestPhaseDescription(t *testing.T) {
 		{"parsing", "Parsing files"},
 		{"embedding", "Generating embeddings"},
 		{"embedding_types", "Embedding types"},
+		{"embedding_docs", "Embedding docs"},
 		{"writing", "Writing to database"},
 		// Default case returns the input unchanged
 		{"unknown_phase", "unknown_phase"},
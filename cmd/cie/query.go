@@ -34,6 +34,7 @@ import (
 
 	"github.com/kraklabs/cie/internal/errors"
 	"github.com/kraklabs/cie/pkg/storage"
+	"github.com/kraklabs/cie/pkg/tools"
 )
 
 // runQuery executes the 'query' CLI command, running CozoScript queries on the indexed codebase.
@@ -48,12 +49,14 @@ import (
 // Command-specific flags:
 //   - --timeout: Query timeout duration (default: 30s)
 //   - --limit: Add :limit clause to query (default: 0, no limit)
+//   - --as-of: Query the snapshot of a commit built with 'cie index --ref'
 //
 // Examples:
 //
 //	cie query '?[name, file] := *cie_function{ name, file_path: file } :limit 10'
 //	cie query '?[name] := *cie_function{ name }' --json
 //	cie query '?[count(id)] := *cie_function{ id }' --timeout 60s
+//	cie query '?[name] := *cie_function{ name }' --as-of v2.3.0
 func runQuery(args []string, configPath string, globals GlobalFlags) {
 	// 1. Load configuration first to check for EdgeCache
 	cfg, cfgErr := LoadConfig(configPath)
@@ -76,6 +79,7 @@ func runQuery(args []string, configPath string, globals GlobalFlags) {
 	fs := flag.NewFlagSet("query", flag.ExitOnError)
 	timeout := fs.Duration("timeout", 30*time.Second, "Query timeout")
 	limit := fs.Int("limit", 0, "Add :limit to query (0 = no limit)")
+	asOf := fs.String("as-of", "", "Query the snapshot of this commit (SHA or ref) instead of HEAD")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Usage: cie query [options] <cozoscript>
@@ -111,8 +115,14 @@ Examples:
   # Output as JSON for scripting
   cie query "?[name] := *cie_function{ name }" --json | jq '.rows[][0]'
 
+  # Query the call graph as of a snapshot built with 'cie index --ref v2.3.0'
+  cie query "?[caller] := *cie_calls{ caller_id }" --as-of v2.3.0
+
 Notes:
   Query timeout defaults to 30s. Increase with --timeout flag for complex queries.
+  With --as-of, every *cie_<relation>{...} atom reads the snapshot's rows;
+  queries on relations without history (embeddings, coverage, git history)
+  are rejected.
   See docs/tools-reference.md for complete schema and query patterns.
 
 `)
@@ -176,6 +186,25 @@ Notes:
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
 
+	if *asOf != "" {
+		snapshot, err := findSnapshot(ctx, tools.NewEmbeddedQuerier(backend), gitForSnapshots(), *asOf)
+		if err != nil {
+			errors.FatalError(errors.NewInputError(
+				"Unknown snapshot",
+				err.Error(),
+				"List snapshots with 'cie status' or build one with 'cie index --ref <ref>'",
+			), globals.JSON)
+		}
+		if head := tools.HeadRelations(script); len(head) > 0 {
+			errors.FatalError(errors.NewInputError(
+				"Relation not kept in snapshots",
+				fmt.Sprintf("Snapshots keep no history of %s", strings.Join(head, ", ")),
+				"Run the query without --as-of to read HEAD",
+			), globals.JSON)
+		}
+		script = tools.AsOfScript(script, snapshot.Seq)
+	}
+
 	result, err := backend.Query(ctx, script)
 	if err != nil {
 		// Distinguish between syntax errors and execution errors
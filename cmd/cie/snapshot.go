@@ -0,0 +1,131 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kraklabs/cie/internal/errors"
+	"github.com/kraklabs/cie/internal/ui"
+	"github.com/kraklabs/cie/pkg/ingestion"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// runSnapshotIndex builds a snapshot of the index at a commit for
+// 'cie index --ref', next to the HEAD index and without checking the commit
+// out. Snapshots are queried with 'cie query --as-of' and the as_of
+// parameter of the MCP tools.
+func runSnapshotIndex(ctx context.Context, logger *slog.Logger, cfg *Config, repoPath, dataDir string, embedWorkers int, ref string, globals GlobalFlags) {
+	checkpointDir := filepath.Join(ConfigDir(repoPath), "checkpoints")
+	ingestionConfig, _ := BuildIngestionConfig(cfg, repoPath, dataDir, checkpointDir, false, embedWorkers)
+
+	pipeline, err := ingestion.NewLocalPipeline(ingestionConfig, logger)
+	if err != nil {
+		errors.FatalError(errors.NewDatabaseError(
+			"Cannot initialize indexing pipeline",
+			"Failed to open or initialize the database",
+			"Try 'cie reset' to rebuild the database, or close other CIE instances",
+			err,
+		), false)
+	}
+	defer func() { _ = pipeline.Close() }()
+
+	onProgress, finish := newProgressReporter(globals)
+	pipeline.SetProgressCallback(onProgress)
+
+	result, err := pipeline.IndexSnapshot(ctx, ref)
+	finish()
+	if err != nil {
+		if strings.Contains(err.Error(), "unknown commit") || strings.Contains(err.Error(), "invalid git ref") {
+			errors.FatalError(errors.NewInputError(
+				fmt.Sprintf("Cannot resolve %q to a commit", ref),
+				err.Error(),
+				"Pass a commit SHA, tag or branch of this repository",
+			), globals.JSON)
+		}
+		errors.FatalError(errors.NewDatabaseError(
+			"Snapshot indexing failed",
+			"An error occurred while indexing the commit",
+			"Check the error details above. If this persists, try 'cie reset --yes'",
+			err,
+		), false)
+	}
+
+	printSnapshot(result)
+}
+
+// printSnapshot prints the summary of a snapshot build.
+func printSnapshot(result *ingestion.SnapshotResult) {
+	label := tools.Snapshot{SHA: result.SHA, Ref: result.Ref}.Label()
+	fmt.Println()
+	if result.Existing {
+		ui.Infof("Commit %s already has snapshot #%d.", label, result.Seq)
+	} else {
+		ui.Header("Snapshot Complete")
+		fmt.Printf("%s %s\n", ui.Label("Commit:"), label)
+		fmt.Printf("%s #%d\n", ui.Label("Snapshot:"), result.Seq)
+		fmt.Printf("Files Processed: %s\n", ui.CountText(result.Index.FilesProcessed))
+		fmt.Printf("Functions Extracted: %s\n", ui.CountText(result.Index.FunctionsExtracted))
+		fmt.Printf("Rows Written: %s %s\n", ui.CountText(result.Asserted+result.Retracted),
+			ui.DimText(fmt.Sprintf("(%d new or changed, %d removed, %d shared with the previous snapshot)", result.Asserted, result.Retracted, result.Shared)))
+		fmt.Printf("Total: %s\n", ui.DimText(result.TotalDuration.String()))
+	}
+	fmt.Println()
+	fmt.Println("Query it as of this commit:")
+	fmt.Printf("  cie query --as-of %s \"?[name] := *cie_function{name}\"\n", result.Ref)
+	fmt.Printf("  MCP tools: as_of: %q\n", result.Ref)
+}
+
+// findSnapshot returns the snapshot matching asOf: a SHA prefix or the ref
+// the snapshot was built from, or else any ref git resolves to the
+// snapshot's commit. git may be nil.
+func findSnapshot(ctx context.Context, client tools.Querier, git tools.GitRunner, asOf string) (*tools.Snapshot, error) {
+	snapshot, err := tools.FindSnapshot(ctx, client, asOf)
+	if err == nil || git == nil || strings.HasPrefix(asOf, "-") {
+		return snapshot, err
+	}
+	out, gitErr := git.Run(ctx, "rev-parse", "--verify", "--quiet", asOf+"^{commit}")
+	if gitErr != nil {
+		return nil, err
+	}
+	if snapshot, shaErr := tools.FindSnapshot(ctx, client, strings.TrimSpace(out)); shaErr == nil {
+		return snapshot, nil
+	}
+	return nil, err
+}
+
+// gitForSnapshots returns a git executor for resolving as_of refs, nil
+// outside a git repository.
+func gitForSnapshots() tools.GitRunner {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	git, err := tools.NewGitExecutor(cwd)
+	if err != nil {
+		return nil
+	}
+	return git
+}
@@ -32,20 +32,22 @@ import (
 	"github.com/kraklabs/cie/internal/errors"
 	"github.com/kraklabs/cie/internal/ui"
 	"github.com/kraklabs/cie/pkg/storage"
+	"github.com/kraklabs/cie/pkg/tools"
 )
 
 // StatusResult represents the project status for JSON output.
 type StatusResult struct {
-	ProjectID  string    `json:"project_id"`
-	DataDir    string    `json:"data_dir"`
-	Connected  bool      `json:"connected"`
-	Files      int       `json:"files"`
-	Functions  int       `json:"functions"`
-	Types      int       `json:"types"`
-	Embeddings int       `json:"embeddings"`
-	CallEdges  int       `json:"call_edges"`
-	Error      string    `json:"error,omitempty"`
-	Timestamp  time.Time `json:"timestamp"`
+	ProjectID  string           `json:"project_id"`
+	DataDir    string           `json:"data_dir"`
+	Connected  bool             `json:"connected"`
+	Files      int              `json:"files"`
+	Functions  int              `json:"functions"`
+	Types      int              `json:"types"`
+	Embeddings int              `json:"embeddings"`
+	CallEdges  int              `json:"call_edges"`
+	Snapshots  []tools.Snapshot `json:"snapshots,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Timestamp  time.Time        `json:"timestamp"`
 }
 
 // runStatus executes the 'status' CLI command, displaying project index statistics.
@@ -174,6 +176,9 @@ Output Fields:
 	result.Types = queryLocalCount(ctx, backend, "cie_type", "id")
 	result.Embeddings = queryLocalCount(ctx, backend, "cie_function_embedding", "function_id")
 	result.CallEdges = queryLocalCount(ctx, backend, "cie_calls", "id")
+	if snapshots, err := tools.ListSnapshots(ctx, tools.NewEmbeddedQuerier(backend)); err == nil {
+		result.Snapshots = snapshots
+	}
 
 	if globals.JSON {
 		outputStatusJSON(result)
@@ -242,6 +247,15 @@ func printLocalStatus(result *StatusResult) {
 	fmt.Printf("  Embeddings:    %s\n", ui.CountText(result.Embeddings))
 	fmt.Printf("  Call Edges:    %s\n", ui.CountText(result.CallEdges))
 
+	if len(result.Snapshots) > 0 {
+		fmt.Println()
+		ui.SubHeader("Snapshots:")
+		for _, snapshot := range result.Snapshots {
+			fmt.Printf("  %-30s %s functions  %s\n", snapshot.Label(),
+				ui.CountText(snapshot.Functions), ui.DimText(snapshot.IndexedAt.Format(time.RFC3339)))
+		}
+	}
+
 	if result.Error != "" {
 		fmt.Println()
 		ui.Warning(result.Error)
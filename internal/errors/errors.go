@@ -61,6 +61,7 @@
 //   - ExitInput (4): Invalid user input (bad arguments, validation errors)
 //   - ExitPermission (5): Permission denied (file access, etc.)
 //   - ExitNotFound (6): Resource not found (project, file, etc.)
+//   - ExitBreakingChange (7): Breaking changes found (exported API, etc.)
 //   - ExitInternal (10): Internal errors (bugs, panics)
 package errors
 
@@ -96,6 +97,10 @@ const (
 	// ExitNotFound indicates resource not found errors (project, file, etc.).
 	ExitNotFound = 6
 
+	// ExitBreakingChange indicates that a check found breaking changes, such
+	// as an incompatible exported API change (cie api-diff).
+	ExitBreakingChange = 7
+
 	// ExitInternal indicates internal errors (bugs, unexpected panics).
 	// Exit code 10 signals "this is a bug that should be reported".
 	ExitInternal = 10
@@ -282,6 +287,29 @@ func NewNotFoundError(msg, cause, fix string) *UserError {
 	}
 }
 
+// NewBreakingChangeError creates a breaking change error with exit code
+// ExitBreakingChange.
+//
+// Use this when a check completed and found breaking changes, so that CI
+// can fail on them and tell them apart from errors running the check.
+//
+// Example:
+//
+//	return NewBreakingChangeError(
+//	    "3 breaking API changes since v1.4.0",
+//	    "Exported functions, methods, types or fields changed incompatibly",
+//	    "Restore compatibility, or release a new major version if the break is intended",
+//	)
+func NewBreakingChangeError(msg, cause, fix string) *UserError {
+	return &UserError{
+		Message:  msg,
+		Cause:    cause,
+		Fix:      fix,
+		ExitCode: ExitBreakingChange,
+		Err:      nil, // Breaking changes are findings, not failures
+	}
+}
+
 // NewInternalError creates an internal error with exit code ExitInternal.
 //
 // Use this for unexpected errors that indicate bugs in the program, such as
@@ -120,6 +120,7 @@ func TestExitCodes(t *testing.T) {
 		{"ExitInput", ExitInput, 4},
 		{"ExitPermission", ExitPermission, 5},
 		{"ExitNotFound", ExitNotFound, 6},
+		{"ExitBreakingChange", ExitBreakingChange, 7},
 		{"ExitInternal", ExitInternal, 10},
 	}
 
@@ -142,6 +143,7 @@ func TestExitCodes_Uniqueness(t *testing.T) {
 		ExitInput,
 		ExitPermission,
 		ExitNotFound,
+		ExitBreakingChange,
 		ExitInternal,
 	}
 
@@ -245,6 +247,17 @@ func TestConstructors(t *testing.T) {
 			wantExitCode: ExitNotFound,
 			wantHasErr:   false, // Not found errors don't wrap underlying errors
 		},
+		{
+			name: "NewBreakingChangeError",
+			constructor: func() *UserError {
+				return NewBreakingChangeError("msg", "cause", "fix")
+			},
+			wantMessage:  "msg",
+			wantCause:    "cause",
+			wantFix:      "fix",
+			wantExitCode: ExitBreakingChange,
+			wantHasErr:   false,
+		},
 		{
 			name: "NewInternalError",
 			constructor: func() *UserError {
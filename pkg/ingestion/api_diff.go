@@ -0,0 +1,831 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kraklabs/cie/pkg/sigparse"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// Kinds of exported API changes.
+const (
+	APIAdded   = "added"
+	APIRemoved = "removed"
+	APIChanged = "changed"
+)
+
+// APIChange is a change to the exported Go API of a package between two refs.
+type APIChange struct {
+	Package  string `json:"package"`          // Package directory
+	Symbol   string `json:"symbol"`           // "NewClient", "Client.Do", "Config.Timeout"
+	Kind     string `json:"kind"`             // func, method, struct, interface, field, interface method, var, const...
+	Change   string `json:"change"`           // added, removed or changed
+	Breaking bool   `json:"breaking"`         // Code compiling against base may not compile against head
+	Detail   string `json:"detail"`           // What changed and why it breaks or not
+	Before   string `json:"before,omitempty"` // Declaration at base
+	After    string `json:"after,omitempty"`  // Declaration at head
+	FilePath string `json:"file_path"`        // File at head; at base for removals
+	Line     int    `json:"line"`
+}
+
+// APIDiffReport lists the exported API changes of the Go packages changed
+// between two refs.
+type APIDiffReport struct {
+	Base        string      `json:"base"`
+	Head        string      `json:"head"`
+	FilesParsed int         `json:"files_parsed"`
+	Breaking    int         `json:"breaking"`
+	Compatible  int         `json:"compatible"`
+	Changes     []APIChange `json:"changes"`
+}
+
+// apiSymbol is an exported declaration of a package.
+type apiSymbol struct {
+	Kind        string
+	Declaration string            // Signature of functions, definition of other types, type of variables
+	Members     map[string]string // Struct fields or interface methods and embeds, by name
+	Sealed      bool              // Interface with unexported methods: no implementation outside its package
+	Promoted    map[string]string // Struct methods promoted from embedded types: name -> signature without receiver
+	FilePath    string
+	Line        int
+}
+
+// DiffAPI compares the exported Go API of the packages changed between two
+// git refs. Every Go file of those packages is read from git and parsed at
+// both refs, nothing is checked out, so that embedded interfaces and structs
+// declared in unchanged files contribute their methods and fields. Embedded
+// types of other packages of the module are resolved through go.mod.
+// Internal packages, main packages, tests, testdata and vendored code are not
+// public API and are skipped; pathPrefix restricts the comparison to a
+// directory.
+//
+// Changes are classified as breaking when code compiling against base may
+// not compile against head: removed symbols, changed parameter or result
+// types (compared with sigparse), methods added to an interface that other
+// packages can implement, removed or retyped struct fields. Additions and
+// parameter renames are compatible.
+func DiffAPI(ctx context.Context, git tools.GitRunner, base, head, pathPrefix string) (*APIDiffReport, error) {
+	if head == "" {
+		head = "HEAD"
+	}
+	for _, ref := range []string{base, head} {
+		if ref == "" || strings.HasPrefix(ref, "-") {
+			return nil, fmt.Errorf("invalid git ref %q", ref)
+		}
+	}
+	out, err := git.Run(ctx, "diff", "--name-status", "-M", "--no-color", base, head, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make(map[string]bool)
+	for _, p := range parseNameStatus(out) {
+		for _, filePath := range []string{p.OldPath, p.NewPath} {
+			if isPublicGoFile(filePath, pathPrefix) {
+				dirs[path.Dir(filePath)] = true
+			}
+		}
+	}
+	sortedDirs := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sortedDirs = append(sortedDirs, dir)
+	}
+	sort.Strings(sortedDirs)
+
+	parser := NewTreeSitterParser(nil)
+	report := &APIDiffReport{Base: base, Head: head}
+	var apis [2]map[string]*apiSymbol
+	for i, ref := range []string{base, head} {
+		surface := newAPISurface(ctx, git, parser, ref)
+		for _, dir := range sortedDirs {
+			if err := surface.load(dir); err != nil {
+				return nil, err
+			}
+		}
+		apis[i] = surface.exported(dirs)
+		report.FilesParsed += surface.files
+	}
+
+	report.Changes = compareAPI(apis[0], apis[1])
+	for _, c := range report.Changes {
+		if c.Breaking {
+			report.Breaking++
+		} else {
+			report.Compatible++
+		}
+	}
+	return report, nil
+}
+
+// apiSurface parses whole Go packages at a ref and resolves the embedded
+// types of their structs and interfaces.
+type apiSurface struct {
+	ctx    context.Context
+	git    tools.GitRunner
+	parser *TreeSitterParser
+	ref    string
+	module string // Module path from go.mod, "" if unknown
+
+	symbols map[string]*apiSymbol        // Exported symbols by package directory and name
+	types   map[string]*apiSymbol        // Struct and interface types, exported or not, as declared
+	methods map[string]map[string]string // Method signatures by package directory and receiver type
+	imports map[string]map[string]string // Package name -> directory of the module packages each file imports
+	loaded  map[string]bool
+	files   int
+}
+
+func newAPISurface(ctx context.Context, git tools.GitRunner, parser *TreeSitterParser, ref string) *apiSurface {
+	s := &apiSurface{
+		ctx: ctx, git: git, parser: parser, ref: ref,
+		symbols: make(map[string]*apiSymbol),
+		types:   make(map[string]*apiSymbol),
+		methods: make(map[string]map[string]string),
+		imports: make(map[string]map[string]string),
+		loaded:  make(map[string]bool),
+	}
+	if gomod, err := git.Run(ctx, "show", ref+":go.mod"); err == nil {
+		for _, line := range strings.Split(gomod, "\n") {
+			if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+				s.module = strings.Trim(fields[1], `"`)
+				break
+			}
+		}
+	}
+	return s
+}
+
+// load parses the Go files of the package in dir, except tests.
+func (s *apiSurface) load(dir string) error {
+	if s.loaded[dir] {
+		return nil
+	}
+	s.loaded[dir] = true
+
+	args := []string{"ls-tree", "-z", "--name-only", s.ref}
+	if dir != "." {
+		args = append(args, dir+"/")
+	}
+	out, err := s.git.Run(s.ctx, args...)
+	if err != nil {
+		return err
+	}
+	for _, filePath := range strings.Split(out, "\x00") {
+		if !strings.HasSuffix(filePath, ".go") || strings.HasSuffix(filePath, "_test.go") || path.Dir(filePath) != dir {
+			continue
+		}
+		content, err := s.git.Run(s.ctx, "show", s.ref+":"+filePath)
+		if err != nil {
+			return err
+		}
+		result, err := parseRevision(s.parser, filePath, "go", []byte(content))
+		if err != nil {
+			return err
+		}
+		s.files++
+		if result.PackageName == "main" {
+			continue
+		}
+		collectAPI(result, s.symbols)
+
+		for _, t := range result.Types {
+			switch t.Kind {
+			case "struct":
+				members := structFields(t.CodeText)
+				for name, line := range embeddedTypes(t.CodeText) {
+					members["embed:"+name] = line
+				}
+				s.types[dir+"\x00"+t.Name] = &apiSymbol{Kind: t.Kind, Members: members, FilePath: t.FilePath}
+			case "interface":
+				members, sealed := interfaceMembers(t.CodeText)
+				s.types[dir+"\x00"+t.Name] = &apiSymbol{Kind: t.Kind, Members: members, Sealed: sealed, FilePath: t.FilePath}
+			}
+		}
+		for _, fn := range result.Functions {
+			receiver, name, ok := strings.Cut(fn.Name, ".")
+			if !ok || !isExported(name) {
+				continue
+			}
+			if i := strings.Index(receiver, "["); i >= 0 {
+				receiver = receiver[:i]
+			}
+			key := dir + "\x00" + receiver
+			if s.methods[key] == nil {
+				s.methods[key] = make(map[string]string)
+			}
+			s.methods[key][name] = stripReceiver(collapseSpaces(fn.Signature))
+		}
+		if s.module != "" {
+			imports := make(map[string]string)
+			for _, imp := range result.Imports {
+				rel, ok := strings.CutPrefix(imp.ImportPath, s.module+"/")
+				if !ok || imp.Alias == "_" || imp.Alias == "." {
+					continue
+				}
+				name := imp.Alias
+				if name == "" {
+					name = path.Base(imp.ImportPath)
+				}
+				imports[name] = rel
+			}
+			s.imports[filePath] = imports
+		}
+	}
+	return nil
+}
+
+// exported returns the exported symbols of the packages in dirs, with the
+// methods of embedded interfaces and the fields and methods of embedded
+// structs resolved.
+func (s *apiSurface) exported(dirs map[string]bool) map[string]*apiSymbol {
+	api := make(map[string]*apiSymbol)
+	for key, sym := range s.symbols {
+		dir, _, _ := strings.Cut(key, "\x00")
+		if !dirs[dir] {
+			continue
+		}
+		switch sym.Kind {
+		case "interface":
+			sym.Members, sym.Sealed = s.interfaceMethodSet(key, map[string]bool{})
+		case "struct":
+			fields, methods := s.promoted(key, map[string]bool{})
+			for name, typ := range fields {
+				if _, ok := sym.Members[name]; !ok {
+					sym.Members[name] = typ
+				}
+			}
+			sym.Promoted = methods
+		}
+		api[key] = sym
+	}
+	return api
+}
+
+// resolve returns the key of the type an embedded member names, as written
+// in a file of the package in dir, or "" when it is not declared in the
+// module.
+func (s *apiSurface) resolve(dir, filePath, embed string) string {
+	name := strings.TrimLeft(embed, "*")
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	if pkg, typeName, ok := strings.Cut(name, "."); ok {
+		target, known := s.imports[filePath][pkg]
+		if !known || s.load(target) != nil {
+			return ""
+		}
+		dir, name = target, typeName
+	}
+	if s.types[dir+"\x00"+name] == nil {
+		return ""
+	}
+	return dir + "\x00" + name
+}
+
+// interfaceMethodSet returns the methods of an interface, including those of
+// the interfaces it embeds, and whether it is sealed. Embedded interfaces
+// that cannot be resolved (of the standard library or another module) stay
+// embed: members.
+func (s *apiSurface) interfaceMethodSet(key string, visiting map[string]bool) (map[string]string, bool) {
+	typ := s.types[key]
+	dir, _, _ := strings.Cut(key, "\x00")
+	visiting[key] = true
+	defer delete(visiting, key)
+
+	members := make(map[string]string, len(typ.Members))
+	sealed := typ.Sealed
+	var embedded []string
+	for name, member := range typ.Members {
+		if !strings.HasPrefix(name, "embed:") {
+			members[name] = member
+			continue
+		}
+		target := s.resolve(dir, typ.FilePath, member)
+		if target == "" || s.types[target].Kind != "interface" || visiting[target] {
+			members[name] = member
+			continue
+		}
+		embedded = append(embedded, target)
+	}
+	for _, target := range embedded {
+		methods, embeddedSealed := s.interfaceMethodSet(target, visiting)
+		sealed = sealed || embeddedSealed
+		for name, sig := range methods {
+			if _, ok := members[name]; !ok {
+				members[name] = sig
+			}
+		}
+	}
+	return members, sealed
+}
+
+// promoted returns the exported fields and the methods a struct gets from
+// the types it embeds, exported or not. Methods are returned without their
+// receiver.
+func (s *apiSurface) promoted(key string, visiting map[string]bool) (fields, methods map[string]string) {
+	typ := s.types[key]
+	dir, _, _ := strings.Cut(key, "\x00")
+	visiting[key] = true
+	defer delete(visiting, key)
+
+	fields, methods = make(map[string]string), make(map[string]string)
+	for name, member := range typ.Members {
+		if !strings.HasPrefix(name, "embed:") {
+			continue
+		}
+		target := s.resolve(dir, typ.FilePath, member)
+		if target == "" || visiting[target] {
+			continue
+		}
+		embedded := s.types[target]
+		if embedded.Kind == "interface" {
+			set, _ := s.interfaceMethodSet(target, visiting)
+			for method, sig := range set {
+				if !strings.HasPrefix(method, "embed:") && method != "terms" && methods[method] == "" {
+					methods[method] = sig
+				}
+			}
+			continue
+		}
+		for field, fieldType := range embedded.Members {
+			if !strings.HasPrefix(field, "embed:") && isExported(field) && fields[field] == "" {
+				fields[field] = fieldType
+			}
+		}
+		for method, sig := range s.methods[target] {
+			if methods[method] == "" {
+				methods[method] = sig
+			}
+		}
+		deeperFields, deeperMethods := s.promoted(target, visiting)
+		for field, fieldType := range deeperFields {
+			if fields[field] == "" {
+				fields[field] = fieldType
+			}
+		}
+		for method, sig := range deeperMethods {
+			if methods[method] == "" {
+				methods[method] = sig
+			}
+		}
+	}
+	return fields, methods
+}
+
+// stripReceiver returns a method signature without its receiver:
+// "func (c *Client) Close() error" → "func Close() error".
+func stripReceiver(sig string) string {
+	rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(sig), "func"))
+	if !strings.HasPrefix(rest, "(") {
+		return sig
+	}
+	end := strings.Index(rest, ")")
+	if end == -1 {
+		return sig
+	}
+	return "func " + strings.TrimSpace(rest[end+1:])
+}
+
+// isPublicGoFile reports whether a Go file can declare public API: not a
+// test, not under internal/, testdata/ or vendor/, and under pathPrefix.
+func isPublicGoFile(filePath, pathPrefix string) bool {
+	if filePath == "" || !strings.HasSuffix(filePath, ".go") || strings.HasSuffix(filePath, "_test.go") {
+		return false
+	}
+	if prefix := strings.Trim(strings.TrimPrefix(pathPrefix, "./"), "/"); prefix != "" && prefix != "." &&
+		!strings.HasPrefix(filePath, prefix+"/") {
+		return false
+	}
+	for _, dir := range strings.Split(path.Dir(filePath), "/") {
+		if dir == "internal" || dir == "testdata" || dir == "vendor" {
+			return false
+		}
+	}
+	return true
+}
+
+// isExported reports whether a Go identifier is exported.
+func isExported(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+// collectAPI adds the exported functions, methods, types, variables and
+// constants of a parsed Go file, keyed by package directory and name.
+func collectAPI(result *ParseResult, api map[string]*apiSymbol) {
+	dir := path.Dir(result.File.Path)
+	for _, fn := range result.Functions {
+		kind := "func"
+		receiver, name, isMethod := strings.Cut(fn.Name, ".")
+		if isMethod {
+			kind = "method"
+			if i := strings.Index(receiver, "["); i >= 0 {
+				receiver = receiver[:i]
+			}
+			name = receiver + "." + name
+		} else {
+			receiver, name = fn.Name, fn.Name
+		}
+		if !isExported(receiver) || !isExported(fn.Name[strings.LastIndex(fn.Name, ".")+1:]) {
+			continue
+		}
+		api[dir+"\x00"+name] = &apiSymbol{Kind: kind, Declaration: collapseSpaces(fn.Signature), FilePath: fn.FilePath, Line: fn.StartLine}
+	}
+	for _, t := range result.Types {
+		if !isExported(t.Name) {
+			continue
+		}
+		sym := &apiSymbol{Kind: t.Kind, FilePath: t.FilePath, Line: t.StartLine}
+		switch t.Kind {
+		case "struct":
+			sym.Members = structFields(t.CodeText)
+		case "interface":
+			sym.Members, sym.Sealed = interfaceMembers(t.CodeText)
+		default:
+			sym.Declaration = collapseSpaces(strings.TrimPrefix(strings.TrimSpace(t.CodeText), t.Name))
+		}
+		api[dir+"\x00"+t.Name] = sym
+	}
+	for _, v := range result.Variables {
+		if isExported(v.Name) {
+			api[dir+"\x00"+v.Name] = &apiSymbol{Kind: v.Kind, Declaration: v.Type, FilePath: v.FilePath, Line: v.StartLine}
+		}
+	}
+}
+
+// compareAPI returns the changes between the exported symbols at base and
+// at head, sorted by package and symbol.
+func compareAPI(oldAPI, newAPI map[string]*apiSymbol) []APIChange {
+	var changes []APIChange
+	change := func(key string, sym *apiSymbol, kind, c string, breaking bool, detail string) APIChange {
+		pkg, name, _ := strings.Cut(key, "\x00")
+		return APIChange{Package: pkg, Symbol: name, Kind: kind, Change: c, Breaking: breaking, Detail: detail, FilePath: sym.FilePath, Line: sym.Line}
+	}
+
+	for key, old := range oldAPI {
+		if _, ok := newAPI[key]; ok {
+			continue
+		}
+		// Methods go with their removed type
+		if pkg, name, _ := strings.Cut(key, "\x00"); old.Kind == "method" {
+			if _, typeRemoved := oldAPI[pkg+"\x00"+strings.SplitN(name, ".", 2)[0]]; typeRemoved {
+				if _, kept := newAPI[pkg+"\x00"+strings.SplitN(name, ".", 2)[0]]; !kept {
+					continue
+				}
+			}
+		}
+		c := change(key, old, old.Kind, APIRemoved, true, "removed")
+		c.Before = old.Declaration
+		if pkg, name, _ := strings.Cut(key, "\x00"); old.Kind == "method" {
+			typeName, method, _ := strings.Cut(name, ".")
+			if typ := newAPI[pkg+"\x00"+typeName]; typ != nil && typ.Promoted[method] != "" {
+				breaking, detail := compareSignatures(stripReceiver(old.Declaration), typ.Promoted[method])
+				c.Change, c.Breaking = APIChanged, breaking
+				c.Detail = "now promoted from an embedded type"
+				if detail != "" {
+					c.Detail += ": " + detail
+				}
+				c.After = typ.Promoted[method]
+			}
+		}
+		changes = append(changes, c)
+	}
+	for key, sym := range newAPI {
+		old, ok := oldAPI[key]
+		if !ok {
+			c := change(key, sym, sym.Kind, APIAdded, false, "added")
+			c.After = sym.Declaration
+			changes = append(changes, c)
+			continue
+		}
+		if old.Kind != sym.Kind {
+			c := change(key, sym, sym.Kind, APIChanged, true, fmt.Sprintf("kind changed from %s to %s", old.Kind, sym.Kind))
+			c.Before, c.After = old.Declaration, sym.Declaration
+			changes = append(changes, c)
+			continue
+		}
+		switch sym.Kind {
+		case "func", "method":
+			if breaking, detail := compareSignatures(old.Declaration, sym.Declaration); detail != "" {
+				c := change(key, sym, sym.Kind, APIChanged, breaking, detail)
+				c.Before, c.After = old.Declaration, sym.Declaration
+				changes = append(changes, c)
+			}
+		case "struct":
+			changes = append(changes, compareMembers(key, old, sym, "field", change)...)
+		case "interface":
+			changes = append(changes, compareMembers(key, old, sym, "interface method", change)...)
+		default:
+			// Variables and constants without a declared type are not compared
+			inferred := (sym.Kind == "var" || sym.Kind == "const") && (old.Declaration == "" || sym.Declaration == "")
+			if old.Declaration != sym.Declaration && !inferred {
+				c := change(key, sym, sym.Kind, APIChanged, true, "type changed")
+				c.Before, c.After = old.Declaration, sym.Declaration
+				changes = append(changes, c)
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Change < b.Change
+	})
+	return changes
+}
+
+// compareMembers compares the fields of a struct or the methods and
+// embedded types of an interface. Removing or changing a member breaks its
+// users; adding a field is compatible, adding an interface method breaks the
+// implementations outside the package unless the interface is sealed.
+func compareMembers(key string, old, sym *apiSymbol, kind string, change func(string, *apiSymbol, string, string, bool, string) APIChange) []APIChange {
+	var changes []APIChange
+	for name, before := range old.Members {
+		after, ok := sym.Members[name]
+		memberKey := key + "." + strings.TrimPrefix(name, "embed:")
+		memberKind := kind
+		if strings.HasPrefix(name, "embed:") {
+			memberKind = "embedded " + strings.TrimPrefix(kind, "interface ")
+		}
+		switch {
+		case !ok:
+			c := change(memberKey, sym, memberKind, APIRemoved, true, "removed")
+			c.Before = before
+			changes = append(changes, c)
+		case before != after:
+			breaking, detail := true, "type changed"
+			if kind == "interface method" && !strings.HasPrefix(name, "embed:") {
+				// Parameter and result types bind callers and implementers
+				// alike; renaming a parameter breaks neither
+				if breaking, detail = compareSignatures(before, after); detail == "" {
+					continue
+				}
+			}
+			c := change(memberKey, sym, memberKind, APIChanged, breaking, detail)
+			c.Before, c.After = before, after
+			changes = append(changes, c)
+		}
+	}
+	for name, after := range sym.Members {
+		if _, ok := old.Members[name]; ok {
+			continue
+		}
+		memberKind := kind
+		if strings.HasPrefix(name, "embed:") {
+			memberKind = "embedded " + strings.TrimPrefix(kind, "interface ")
+		}
+		breaking, detail := false, "added"
+		if kind == "interface method" {
+			if sym.Sealed {
+				detail = "added to a sealed interface: no implementation outside the package"
+			} else {
+				breaking, detail = true, "added: implementations outside the package no longer satisfy the interface"
+			}
+		}
+		c := change(key+"."+strings.TrimPrefix(name, "embed:"), sym, memberKind, APIAdded, breaking, detail)
+		c.After = after
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// compareSignatures compares two Go function signatures. Parameter and
+// result types are compared as written (sigparse.ParseGoParamTypes and
+// ParseGoResults), parameter names through sigparse.ParseGoParams. It
+// returns an empty detail when the signatures are equivalent.
+func compareSignatures(before, after string) (bool, string) {
+	if before == after {
+		return false, ""
+	}
+	oldParams, newParams := sigparse.ParseGoParamTypes(before), sigparse.ParseGoParamTypes(after)
+	if !slices.Equal(oldParams, newParams) {
+		return true, fmt.Sprintf("parameters changed from (%s) to (%s)", strings.Join(oldParams, ", "), strings.Join(newParams, ", "))
+	}
+	oldResults, newResults := sigparse.ParseGoResults(before), sigparse.ParseGoResults(after)
+	if !slices.Equal(oldResults, newResults) {
+		return true, fmt.Sprintf("results changed from (%s) to (%s)", strings.Join(oldResults, ", "), strings.Join(newResults, ", "))
+	}
+	if oldTP, newTP := goTypeParams(before), goTypeParams(after); oldTP != newTP {
+		return true, fmt.Sprintf("type parameters changed from %q to %q", oldTP, newTP)
+	}
+	oldRecv, newRecv := goReceiverType(before), goReceiverType(after)
+	if strings.HasPrefix(newRecv, "*") && !strings.HasPrefix(oldRecv, "*") {
+		return true, "receiver changed from value to pointer: values of the type no longer have the method"
+	}
+	if oldRecv != newRecv {
+		return false, "receiver changed from pointer to value"
+	}
+	var oldNames, newNames []string
+	for _, p := range sigparse.ParseGoParams(before) {
+		oldNames = append(oldNames, p.Name)
+	}
+	for _, p := range sigparse.ParseGoParams(after) {
+		newNames = append(newNames, p.Name)
+	}
+	if !slices.Equal(oldNames, newNames) {
+		return false, "parameter names changed"
+	}
+	return false, ""
+}
+
+// goReceiverType returns the receiver type of a method signature, "" for a
+// function: "func (s *Store) Get()" → "*Store".
+func goReceiverType(sig string) string {
+	rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(sig), "func"))
+	if !strings.HasPrefix(rest, "(") {
+		return ""
+	}
+	end := strings.Index(rest, ")")
+	if end == -1 {
+		return ""
+	}
+	fields := strings.Fields(rest[1:end])
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// goTypeParams returns the type parameter list of a generic function
+// signature, "" for other functions: "func Map[T, U any](...)" → "[T, U any]".
+func goTypeParams(sig string) string {
+	rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(sig), "func"))
+	if strings.HasPrefix(rest, "(") {
+		if end := strings.Index(rest, ")"); end >= 0 {
+			rest = rest[end+1:]
+		}
+	}
+	open := strings.IndexAny(rest, "[(")
+	if open == -1 || rest[open] != '[' {
+		return ""
+	}
+	depth := 0
+	for i := open; i < len(rest); i++ {
+		switch rest[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return rest[open : i+1]
+			}
+		}
+	}
+	return ""
+}
+
+var (
+	// structFieldPattern matches a named struct field line: "A, B int".
+	structFieldPattern = regexp.MustCompile(`^(\w+(?:\s*,\s*\w+)*)\s+(\S.*)$`)
+
+	// interfaceMethodLinePattern matches an interface method line: "Get(id string) error".
+	interfaceMethodLinePattern = regexp.MustCompile(`^(\w+)\s*\(`)
+
+	// structTagPattern matches a struct tag.
+	structTagPattern = regexp.MustCompile("`[^`]*`")
+)
+
+// structFields returns the exported fields of a struct type by name, with
+// their types. Embedded types are fields named after the type.
+func structFields(codeText string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range typeBodyMembers(codeText) {
+		line = strings.TrimSpace(structTagPattern.ReplaceAllString(line, ""))
+		if m := structFieldPattern.FindStringSubmatch(line); m != nil && !strings.HasPrefix(m[2], ".") {
+			for _, name := range strings.Split(m[1], ",") {
+				if name = strings.TrimSpace(name); isExported(name) {
+					fields[name] = m[2]
+				}
+			}
+			continue
+		}
+		name := strings.TrimLeft(line, "*")
+		if i := strings.Index(name, "["); i >= 0 {
+			name = name[:i]
+		}
+		name = name[strings.LastIndex(name, ".")+1:]
+		if isExported(name) {
+			fields["embed:"+name] = line
+		}
+	}
+	return fields
+}
+
+// embeddedTypes returns the embedded types of a struct type, exported or
+// not, by name.
+func embeddedTypes(codeText string) map[string]string {
+	embeds := make(map[string]string)
+	for _, line := range typeBodyMembers(codeText) {
+		line = strings.TrimSpace(structTagPattern.ReplaceAllString(line, ""))
+		if m := structFieldPattern.FindStringSubmatch(line); m != nil && !strings.HasPrefix(m[2], ".") {
+			continue
+		}
+		name := strings.TrimLeft(line, "*")
+		if i := strings.Index(name, "["); i >= 0 {
+			name = name[:i]
+		}
+		embeds[name[strings.LastIndex(name, ".")+1:]] = line
+	}
+	return embeds
+}
+
+// interfaceMembers returns the methods of an interface type with their
+// signatures, its embedded types ("embed:io.Reader") and type set terms
+// ("terms"). sealed reports an unexported method, which no type outside the
+// package can implement.
+func interfaceMembers(codeText string) (members map[string]string, sealed bool) {
+	members = make(map[string]string)
+	for _, line := range typeBodyMembers(codeText) {
+		if m := interfaceMethodLinePattern.FindStringSubmatch(line); m != nil {
+			if !isExported(m[1]) {
+				sealed = true
+				continue
+			}
+			members[m[1]] = "func " + line
+			continue
+		}
+		if strings.ContainsAny(line, "~|") {
+			members["terms"] = line
+			continue
+		}
+		members["embed:"+line] = line
+	}
+	return members, sealed
+}
+
+// typeBodyMembers returns the declarations between the braces of a struct
+// or interface type, one per field or method, with comments removed and
+// whitespace collapsed. Nested struct and interface literals stay on their
+// member's line.
+func typeBodyMembers(codeText string) []string {
+	open := strings.Index(codeText, "{")
+	close := strings.LastIndex(codeText, "}")
+	if open == -1 || close <= open {
+		return nil
+	}
+	body := blockCommentPattern.ReplaceAllString(codeText[open+1:close], "")
+
+	var members []string
+	var current strings.Builder
+	depth := 0
+	flush := func() {
+		if line := collapseSpaces(current.String()); line != "" {
+			members = append(members, line)
+		}
+		current.Reset()
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		for _, part := range strings.Split(line, ";") {
+			current.WriteString(" " + part)
+			depth += strings.Count(part, "{") - strings.Count(part, "}")
+			if depth <= 0 {
+				depth = 0
+				flush()
+			}
+		}
+	}
+	flush()
+	return members
+}
+
+// blockCommentPattern matches a /* */ comment.
+var blockCommentPattern = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// collapseSpaces trims s and collapses its whitespace runs to single spaces.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,328 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAPI(t *testing.T) {
+	git := treeGitRunner{
+		"diff --name-status -M --no-color v1.0.0 HEAD --": "M\tclient/client.go\nM\tinternal/db/db.go\nM\tclient/client_test.go\nM\tcmd/tool/main.go\n",
+		"show v1.0.0:client/client.go": `package client
+
+type Client struct {
+	Addr, Token string
+	Retries     int ` + "`json:\"retries\"`" + `
+	cache       map[string]string
+}
+
+type Doer interface {
+	Do(req string) (string, error)
+}
+
+type store interface {
+	Get(key string) string
+	seal()
+}
+
+type Store = store
+
+type Sealed interface {
+	Get(key string) string
+	sealed()
+}
+
+const Version = "1"
+
+func New(addr string) *Client {
+	return &Client{Addr: addr}
+}
+
+func (c Client) Send(msg string) error {
+	return nil
+}
+
+func (c *Client) Close(force bool) error {
+	return nil
+}
+
+func Dial(address string, retries int) (*Client, error) {
+	return nil, nil
+}
+
+func Legacy() {}
+`,
+		"ls-tree -z --name-only v1.0.0 client/":   "client/client.go\x00client/client_test.go\x00",
+		"ls-tree -z --name-only HEAD client/":     "client/client.go\x00client/client_test.go\x00",
+		"ls-tree -z --name-only v1.0.0 cmd/tool/": "cmd/tool/main.go\x00",
+		"ls-tree -z --name-only HEAD cmd/tool/":   "cmd/tool/main.go\x00",
+		"show v1.0.0:cmd/tool/main.go":            "package main\n\nfunc Run() {}\n",
+		"show HEAD:cmd/tool/main.go":              "package main\n\nfunc Run(args []string) {}\n",
+		"show HEAD:client/client.go": `package client
+
+type Client struct {
+	Addr    string
+	Token   []byte
+	Timeout int
+	cache   map[string]int
+}
+
+type Doer interface {
+	Do(req string) (string, error)
+	Close() error
+}
+
+type store interface {
+	Get(key string) string
+	seal()
+}
+
+type Store = store
+
+type Sealed interface {
+	Get(key string) string
+	Put(key, value string)
+	sealed()
+}
+
+const Version = "2"
+
+func New(addr string, opts ...Option) *Client {
+	return &Client{Addr: addr}
+}
+
+func (c *Client) Send(msg string) error {
+	return nil
+}
+
+func (c Client) Close(force bool) error {
+	return nil
+}
+
+func Dial(addr string, n int) (*Client, error) {
+	return nil, nil
+}
+
+type Option func(*Client)
+`,
+	}
+
+	report, err := DiffAPI(context.Background(), git, "v1.0.0", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "HEAD", report.Head)
+	assert.Equal(t, 4, report.FilesParsed, "internal and test files are skipped")
+
+	type change struct {
+		symbol, change string
+		breaking       bool
+	}
+	var got []change
+	for _, c := range report.Changes {
+		assert.Equal(t, "client", c.Package)
+		got = append(got, change{c.Symbol, c.Change, c.Breaking})
+	}
+	assert.Equal(t, []change{
+		{"Client.Close", APIChanged, false},
+		{"Client.Retries", APIRemoved, true},
+		{"Client.Send", APIChanged, true},
+		{"Client.Timeout", APIAdded, false},
+		{"Client.Token", APIChanged, true},
+		{"Dial", APIChanged, false},
+		{"Doer.Close", APIAdded, true},
+		{"Legacy", APIRemoved, true},
+		{"New", APIChanged, true},
+		{"Option", APIAdded, false},
+		{"Sealed.Put", APIAdded, false},
+	}, got)
+	assert.Equal(t, 6, report.Breaking)
+	assert.Equal(t, 5, report.Compatible)
+}
+
+func TestDiffAPI_PathPrefix(t *testing.T) {
+	git := treeGitRunner{
+		"diff --name-status -M --no-color v1 v2 --": "D\tapi/api.go\nD\tcli/cli.go\n",
+		"ls-tree -z --name-only v1 api/":            "api/api.go\x00",
+		"ls-tree -z --name-only v2 api/":            "",
+		"show v1:api/api.go":                        "package api\n\nfunc Get() {}\n",
+	}
+	report, err := DiffAPI(context.Background(), git, "v1", "v2", "./api/")
+	require.NoError(t, err)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, "Get", report.Changes[0].Symbol)
+	assert.Equal(t, "func", report.Changes[0].Kind)
+	assert.True(t, report.Changes[0].Breaking)
+}
+
+func TestDiffAPI_EmbeddedTypes(t *testing.T) {
+	// Only store.go changes; the interfaces and structs it embeds are
+	// declared in unchanged files of the same package and of another
+	// package of the module.
+	git := treeGitRunner{
+		"diff --name-status -M --no-color v1 v2 --": "M\tstore/store.go\n",
+		"show v1:go.mod":                   "module example.com/app\n\ngo 1.22\n",
+		"show v2:go.mod":                   "module example.com/app\n\ngo 1.22\n",
+		"ls-tree -z --name-only v1 store/": "store/store.go\x00store/reader.go\x00",
+		"ls-tree -z --name-only v2 store/": "store/store.go\x00store/reader.go\x00",
+		"ls-tree -z --name-only v1 meta/":  "meta/meta.go\x00",
+		"ls-tree -z --name-only v2 meta/":  "meta/meta.go\x00",
+		"show v1:store/store.go": `package store
+
+import "example.com/app/meta"
+
+type Store interface {
+	Reader
+	Put(key string, value []byte) error
+}
+
+type File struct {
+	base
+	meta.Info
+	Path string
+}
+
+func (f *File) Close() error { return nil }
+`,
+		"show v2:store/store.go": `package store
+
+import "example.com/app/meta"
+
+type Store interface {
+	Put(key string, value []byte) error
+	Get(key string) ([]byte, error)
+	Reader
+}
+
+type File struct {
+	base
+	meta.Info
+	Path string
+}
+
+func (b *base) Close() error { return nil }
+`,
+		"show v1:store/reader.go": "package store\n\ntype Reader interface {\n\tGet(key string) ([]byte, error)\n}\n\ntype base struct {\n\tOpened bool\n}\n",
+		"show v2:store/reader.go": "package store\n\ntype Reader interface {\n\tGet(key string) ([]byte, error)\n}\n\ntype base struct {\n\tOpened bool\n}\n",
+		"show v1:meta/meta.go":    "package meta\n\ntype Info struct {\n\tSize int64\n}\n",
+		"show v2:meta/meta.go":    "package meta\n\ntype Info struct {\n\tSize int64\n}\n",
+	}
+
+	report, err := DiffAPI(context.Background(), git, "v1", "v2", "")
+	require.NoError(t, err)
+	assert.Equal(t, 6, report.FilesParsed)
+	require.Len(t, report.Changes, 1, "%+v", report.Changes)
+	c := report.Changes[0]
+	assert.Equal(t, "File.Close", c.Symbol)
+	assert.False(t, c.Breaking)
+	assert.Equal(t, "now promoted from an embedded type", c.Detail)
+	assert.Zero(t, report.Breaking)
+}
+
+func TestCompareAPI_InterfaceMethodSignature(t *testing.T) {
+	store := func(get string) map[string]*apiSymbol {
+		return map[string]*apiSymbol{"store\x00Store": {Kind: "interface", Members: map[string]string{"Get": get}}}
+	}
+	before := store("func Get(key string) ([]byte, error)")
+
+	changes := compareAPI(before, store("func Get(id string) ([]byte, error)"))
+	require.Len(t, changes, 1)
+	assert.Equal(t, "Store.Get", changes[0].Symbol)
+	assert.False(t, changes[0].Breaking, "renaming a parameter breaks neither callers nor implementations")
+	assert.Equal(t, "parameter names changed", changes[0].Detail)
+
+	changes = compareAPI(before, store("func Get(key []byte) ([]byte, error)"))
+	require.Len(t, changes, 1)
+	assert.True(t, changes[0].Breaking)
+
+	assert.Empty(t, compareAPI(before, store("func Get(key string) ( []byte, error )")))
+}
+
+func TestDiffAPI_InvalidRef(t *testing.T) {
+	_, err := DiffAPI(context.Background(), treeGitRunner{}, "--output=/tmp/x", "", "")
+	assert.Error(t, err)
+	_, err = DiffAPI(context.Background(), treeGitRunner{}, "", "HEAD", "")
+	assert.Error(t, err)
+}
+
+func TestCompareSignatures(t *testing.T) {
+	tests := []struct {
+		name, before, after string
+		breaking            bool
+		changed             bool
+	}{
+		{"same", "func F(a int) error", "func F(a int) error", false, false},
+		{"renamed parameter", "func F(a int) error", "func F(b int) error", false, true},
+		{"grouped parameters", "func F(a int, b int)", "func F(a, b int)", false, false},
+		{"added parameter", "func F(a int)", "func F(a int, b string)", true, true},
+		{"variadic", "func F(a int)", "func F(a int, opts ...Option)", true, true},
+		{"result", "func F() error", "func F() (int, error)", true, true},
+		{"type parameters", "func Map[T any](s []T)", "func Map[T comparable](s []T)", true, true},
+		{"value to pointer receiver", "func (c Client) F()", "func (c *Client) F()", true, true},
+		{"pointer to value receiver", "func (c *Client) F()", "func (c Client) F()", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaking, detail := compareSignatures(tt.before, tt.after)
+			assert.Equal(t, tt.breaking, breaking)
+			assert.Equal(t, tt.changed, detail != "", detail)
+		})
+	}
+}
+
+func TestStructFields(t *testing.T) {
+	fields := structFields(`Config struct {
+	// Name is the name.
+	Name, Alias string ` + "`json:\"name\"`" + `
+	Limits struct {
+		Max int
+	}
+	*Base
+	io.Reader
+	private int /* not API */
+}`)
+	assert.Equal(t, map[string]string{
+		"Name":         "string",
+		"Alias":        "string",
+		"Limits":       "struct { Max int }",
+		"embed:Base":   "*Base",
+		"embed:Reader": "io.Reader",
+	}, fields)
+}
+
+func TestInterfaceMembers(t *testing.T) {
+	members, sealed := interfaceMembers(`Number interface {
+	~int | ~float64
+	fmt.Stringer
+	Add(other Number) Number
+}`)
+	assert.False(t, sealed)
+	assert.Equal(t, map[string]string{
+		"terms":              "~int | ~float64",
+		"embed:fmt.Stringer": "fmt.Stringer",
+		"Add":                "func Add(other Number) Number",
+	}, members)
+
+	_, sealed = interfaceMembers("Node interface {\n\tPos() int\n\tnode()\n}")
+	assert.True(t, sealed)
+}
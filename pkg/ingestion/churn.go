@@ -0,0 +1,676 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kraklabs/cie/pkg/storage"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// =============================================================================
+// GIT HISTORY PASS
+// =============================================================================
+//
+// The history pass walks `git log --numstat` with zero-context patches and
+// maps the lines changed by each commit onto the indexed functions. Commits
+// are walked from the newest: the lines of an older commit are carried
+// through the hunks of every newer commit touching the file, and through
+// renames, so they land on the line numbers of the index. Lines deleted
+// since are dropped.
+
+// Defaults of HistoryOptions.
+const (
+	DefaultHistoryMaxCommits   = 1000
+	DefaultHistoryMaxChangeset = 50
+	DefaultHistoryMinCoChanges = 2
+)
+
+// historyBatchSize is how many rows are written per Execute call.
+const historyBatchSize = 1000
+
+// HistoryOptions bounds the history pass.
+type HistoryOptions struct {
+	// Since only walks commits after this date, in any format git log
+	// --since accepts ("2025-01-01", "6 months ago"). Empty walks all.
+	Since string
+
+	// MaxCommits caps the commits walked, the most recent first (default 1000).
+	MaxCommits int
+
+	// MaxChangeset leaves commits changing more functions out of change
+	// coupling, since bulk renames and reformats couple everything (default 50).
+	MaxChangeset int
+
+	// MinCoChanges is how many commits two functions must share for the
+	// pair to be stored (default 2).
+	MinCoChanges int
+}
+
+// withDefaults fills the unset options.
+func (o HistoryOptions) withDefaults() HistoryOptions {
+	if o.MaxCommits <= 0 {
+		o.MaxCommits = DefaultHistoryMaxCommits
+	}
+	if o.MaxChangeset <= 0 {
+		o.MaxChangeset = DefaultHistoryMaxChangeset
+	}
+	if o.MinCoChanges <= 0 {
+		o.MinCoChanges = DefaultHistoryMinCoChanges
+	}
+	return o
+}
+
+// GitCommit is a commit of git log with the files it changed.
+type GitCommit struct {
+	Hash   string
+	Author string // Author email, lowercased
+	Time   int64  // Author time, Unix seconds
+	Files  []GitFileChange
+}
+
+// GitFileChange is a file changed by a commit.
+type GitFileChange struct {
+	OldPath string     // Path before the change; "" when the file is added
+	Path    string     // Path after the change; "" when the file is deleted
+	Added   int        // Lines added (--numstat)
+	Deleted int        // Lines deleted (--numstat)
+	Binary  bool       // Binary file: no line information
+	Hunks   []DiffHunk // Changed line ranges, in file order
+}
+
+// DiffHunk is the range header of a diff hunk: OldLines lines at OldStart
+// replaced by NewLines lines at NewStart. A zero count means the hunk only
+// adds (OldLines) or only deletes (NewLines) lines, after line OldStart or
+// NewStart.
+type DiffHunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+}
+
+// historyLogArgs returns the git log arguments of the history pass: one
+// record per commit, separated by \x1e, with its --numstat lines and its
+// patch without context lines.
+func historyLogArgs(opts HistoryOptions) []string {
+	args := []string{
+		"log", "--no-merges", "-M", "--numstat", "-p", "--unified=0", "--no-color", "--no-ext-diff",
+		"--format=%x1ecommit %H%x1f%aE%x1f%at",
+		fmt.Sprintf("--max-count=%d", opts.MaxCommits),
+	}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	return append(args, "HEAD")
+}
+
+// ParseGitLog parses git log output in the history pass format, newest
+// commit first.
+func ParseGitLog(output string) []GitCommit {
+	var commits []GitCommit
+	for _, record := range strings.Split(output, "\x1e") {
+		lines := strings.Split(record, "\n")
+		header, ok := strings.CutPrefix(lines[0], "commit ")
+		if !ok {
+			continue
+		}
+		fields := strings.Split(header, "\x1f")
+		if len(fields) < 3 {
+			continue
+		}
+		when, _ := strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64)
+		commit := GitCommit{Hash: fields[0], Author: strings.ToLower(fields[1]), Time: when}
+
+		// --numstat lines come first: "added<TAB>deleted<TAB>path", "-" for binary files.
+		stats := make(map[string][2]int)
+		binary := make(map[string]bool)
+		i := 1
+		for ; i < len(lines) && !strings.HasPrefix(lines[i], "diff --git "); i++ {
+			parts := strings.SplitN(lines[i], "\t", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			file := numstatPath(parts[2])
+			if parts[0] == "-" {
+				binary[file] = true
+				continue
+			}
+			added, _ := strconv.Atoi(parts[0])
+			deleted, _ := strconv.Atoi(parts[1])
+			stats[file] = [2]int{added, deleted}
+		}
+
+		for _, f := range parseUnifiedDiff(lines[i:]) {
+			key := f.Path
+			if key == "" {
+				key = f.OldPath
+			}
+			f.Added, f.Deleted = stats[key][0], stats[key][1]
+			f.Binary = f.Binary || binary[key]
+			commit.Files = append(commit.Files, f)
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// numstatPath returns the path after the change of a --numstat path, which
+// shows renames as "old => new" or "dir/{old => new}/file".
+func numstatPath(p string) string {
+	if open := strings.Index(p, "{"); open >= 0 {
+		if end := strings.Index(p[open:], "}"); end >= 0 {
+			end += open
+			if _, to, ok := strings.Cut(p[open+1:end], " => "); ok {
+				return strings.TrimPrefix(path.Clean(p[:open]+to+p[end+1:]), "/")
+			}
+		}
+	}
+	if _, to, ok := strings.Cut(p, " => "); ok {
+		return unquoteGitPath(to)
+	}
+	return unquoteGitPath(p)
+}
+
+// parseUnifiedDiff parses the file sections of a unified diff: git diff
+// output, or plain "--- / +++" diffs. Hunk bodies are read by their line
+// counts, so changed lines looking like headers are not mistaken for them,
+// and hunks with context lines are split into the runs of changed lines
+// they hold, as --unified=0 would report them.
+func parseUnifiedDiff(lines []string) []GitFileChange {
+	var files []GitFileChange
+	var cur *GitFileChange
+	oldLeft, newLeft := 0, 0
+	var run diffRun
+	for _, line := range lines {
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "-"):
+				run.deleted++
+				oldLeft--
+			case strings.HasPrefix(line, "+"):
+				run.added++
+				newLeft--
+			case strings.HasPrefix(line, "\\"):
+				// "\ No newline at end of file"
+			default:
+				cur.Hunks = run.flush(cur.Hunks)
+				run.oldNext++
+				run.newNext++
+				oldLeft--
+				newLeft--
+			}
+			if oldLeft <= 0 && newLeft <= 0 {
+				cur.Hunks = run.flush(cur.Hunks)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, GitFileChange{})
+			cur = &files[len(files)-1]
+			cur.OldPath, cur.Path = diffGitPaths(strings.TrimPrefix(line, "diff --git "))
+		case strings.HasPrefix(line, "--- ") && (cur == nil || len(cur.Hunks) > 0):
+			// Plain diff without a "diff --git" line
+			files = append(files, GitFileChange{OldPath: patchPath(line[4:], "a/")})
+			cur = &files[len(files)-1]
+		case cur == nil:
+		case strings.HasPrefix(line, "--- "):
+			cur.OldPath = patchPath(line[4:], "a/")
+		case strings.HasPrefix(line, "+++ "):
+			cur.Path = patchPath(line[4:], "b/")
+		case strings.HasPrefix(line, "rename from "):
+			cur.OldPath = unquoteGitPath(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			cur.Path = unquoteGitPath(strings.TrimPrefix(line, "rename to "))
+		case strings.HasPrefix(line, "new file mode"):
+			cur.OldPath = ""
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.Path = ""
+		case strings.HasPrefix(line, "Binary files "):
+			cur.Binary = true
+		case strings.HasPrefix(line, "@@ "):
+			if h, ok := parseHunkHeader(line); ok {
+				oldLeft, newLeft = h.OldLines, h.NewLines
+				run = diffRun{oldNext: h.OldStart, newNext: h.NewStart}
+				// An empty side starts after the line its header names
+				if h.OldLines == 0 {
+					run.oldNext++
+				}
+				if h.NewLines == 0 {
+					run.newNext++
+				}
+			}
+		}
+	}
+	return files
+}
+
+// diffRun is a run of changed lines in a hunk body: deleted lines starting
+// at old line oldNext, replaced by added lines starting at new line newNext.
+type diffRun struct {
+	oldNext, newNext int
+	deleted, added   int
+}
+
+// flush appends the run to hunks, if it changed any line, and starts the
+// next run after it.
+func (r *diffRun) flush(hunks []DiffHunk) []DiffHunk {
+	if r.deleted == 0 && r.added == 0 {
+		return hunks
+	}
+	h := DiffHunk{OldStart: r.oldNext, OldLines: r.deleted, NewStart: r.newNext, NewLines: r.added}
+	if r.deleted == 0 {
+		h.OldStart--
+	}
+	if r.added == 0 {
+		h.NewStart--
+	}
+	r.oldNext += r.deleted
+	r.newNext += r.added
+	r.deleted, r.added = 0, 0
+	return append(hunks, h)
+}
+
+// diffGitPaths returns the paths of a "diff --git a/path b/path" line when
+// both are the same, which holds unless the file is renamed; renames are
+// read from the "rename from/to" or "---/+++" lines that follow.
+func diffGitPaths(rest string) (oldPath, newPath string) {
+	if len(rest)%2 == 0 {
+		return "", ""
+	}
+	a, b := rest[:len(rest)/2], rest[len(rest)/2+1:]
+	if !strings.HasPrefix(a, "a/") || !strings.HasPrefix(b, "b/") || a[2:] != b[2:] {
+		return "", ""
+	}
+	return a[2:], b[2:]
+}
+
+// patchPath returns the path of a "---" or "+++" line without its a/ or b/
+// prefix, or "" for /dev/null.
+func patchPath(s, prefix string) string {
+	s, _, _ = strings.Cut(s, "\t") // diff -u appends the file time
+	s = unquoteGitPath(strings.TrimSpace(s))
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// parseHunkHeader parses "@@ -12,3 +12,4 @@ context".
+func parseHunkHeader(line string) (DiffHunk, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return DiffHunk{}, false
+	}
+	oldStart, oldLines, ok := hunkRange(fields[1][1:])
+	if !ok {
+		return DiffHunk{}, false
+	}
+	newStart, newLines, ok := hunkRange(fields[2][1:])
+	if !ok {
+		return DiffHunk{}, false
+	}
+	return DiffHunk{OldStart: oldStart, OldLines: oldLines, NewStart: newStart, NewLines: newLines}, true
+}
+
+// hunkRange parses "12,3" or "12" (one line).
+func hunkRange(s string) (start, count int, ok bool) {
+	startText, countText, hasCount := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
+
+// =============================================================================
+// MAPPING ONTO FUNCTIONS
+// =============================================================================
+
+// lineSpan is an inclusive range of lines.
+type lineSpan struct {
+	Start int
+	End   int
+}
+
+// carrySpans maps line ranges of a file before a commit to the file after
+// it, given the hunks of the commit: lines around hunks shift, lines inside
+// a hunk map to the lines replacing them, deleted lines are dropped.
+func carrySpans(spans []lineSpan, hunks []DiffHunk) []lineSpan {
+	var out []lineSpan
+	for _, s := range spans {
+		cur, delta := s.Start, 0
+		for _, h := range hunks {
+			if cur > s.End {
+				break
+			}
+			if h.OldLines == 0 {
+				// Lines inserted after line OldStart
+				if h.OldStart < cur {
+					delta += h.NewLines
+					continue
+				}
+				if h.OldStart >= s.End {
+					break
+				}
+				out = append(out, lineSpan{cur + delta, h.OldStart + delta})
+				delta += h.NewLines
+				cur = h.OldStart + 1
+				continue
+			}
+			oldEnd := h.OldStart + h.OldLines - 1
+			if oldEnd < cur {
+				delta += h.NewLines - h.OldLines
+				continue
+			}
+			if h.OldStart > s.End {
+				break
+			}
+			if h.OldStart > cur {
+				out = append(out, lineSpan{cur + delta, h.OldStart - 1 + delta})
+			}
+			if h.NewLines > 0 {
+				out = append(out, lineSpan{h.NewStart, h.NewStart + h.NewLines - 1})
+			}
+			delta += h.NewLines - h.OldLines
+			cur = oldEnd + 1
+		}
+		if cur <= s.End {
+			out = append(out, lineSpan{cur + delta, s.End + delta})
+		}
+	}
+	return out
+}
+
+// changedSpan is a range of lines changed by a commit, after the commit. A
+// deletion is the pair of lines around the deleted ones: it only counts for
+// a function holding both, so deleting a whole function does not count for
+// its neighbors.
+type changedSpan struct {
+	lineSpan
+	deletion bool
+}
+
+// changedSpans returns the lines changed by the hunks of a commit.
+func changedSpans(hunks []DiffHunk) []changedSpan {
+	var spans []changedSpan
+	for _, h := range hunks {
+		switch {
+		case h.NewLines > 0:
+			spans = append(spans, changedSpan{lineSpan: lineSpan{h.NewStart, h.NewStart + h.NewLines - 1}})
+		case h.NewStart > 0:
+			spans = append(spans, changedSpan{lineSpan: lineSpan{h.NewStart, h.NewStart + 1}, deletion: true})
+		}
+	}
+	return spans
+}
+
+// linesTouched returns how many lines of fn the pieces of a changed span
+// cover, counting a deletion as one line.
+func linesTouched(fn FunctionEntity, pieces []lineSpan, deletion bool) int {
+	if deletion {
+		first, last := pieces[0].Start, pieces[0].End
+		for _, p := range pieces[1:] {
+			first, last = min(first, p.Start), max(last, p.End)
+		}
+		if first >= fn.StartLine && last <= fn.EndLine {
+			return 1
+		}
+		return 0
+	}
+	n := 0
+	for _, p := range pieces {
+		if lo, hi := max(p.Start, fn.StartLine), min(p.End, fn.EndLine); lo <= hi {
+			n += hi - lo + 1
+		}
+	}
+	return n
+}
+
+// HistoryAnalysis is the churn and change coupling of the functions.
+type HistoryAnalysis struct {
+	Churn        []FunctionChurnEntity
+	Coupling     []ChangeCouplingEntity
+	Commits      int   // Commits walked
+	LargeCommits int   // Commits left out of coupling (more than MaxChangeset functions)
+	LinesAdded   int   // Lines added across commits (--numstat)
+	LinesDeleted int   // Lines deleted across commits (--numstat)
+	Oldest       int64 // Unix time of the oldest commit walked
+	Newest       int64 // Unix time of the newest commit walked
+}
+
+// functionChurn accumulates the churn of a function.
+type functionChurn struct {
+	commits         int
+	couplingCommits int // Commits counted for coupling (at most MaxChangeset functions)
+	lines           int
+	authors         map[string]bool
+	first, last     int64
+}
+
+// AnalyzeHistory maps commits, newest first, onto the functions changed in
+// them and computes per-function churn and change coupling. Anonymous
+// functions are left out: they always change with the function holding them.
+func AnalyzeHistory(commits []GitCommit, functions []FunctionEntity, opts HistoryOptions) *HistoryAnalysis {
+	opts = opts.withDefaults()
+	byFile := make(map[string][]FunctionEntity)
+	filePaths := make(map[string]string)
+	for _, fn := range functions {
+		if strings.Contains(fn.Name, "$") {
+			continue
+		}
+		byFile[fn.FilePath] = append(byFile[fn.FilePath], fn)
+		filePaths[fn.ID] = fn.FilePath
+	}
+
+	// current maps a path, as of the commit being walked, to its path in the
+	// index; "" when the file was deleted or re-created since.
+	current := make(map[string]string)
+	resolve := func(p string) string {
+		if to, ok := current[p]; ok {
+			return to
+		}
+		return p
+	}
+	// newer holds, per indexed path, the hunks of the newer commits walked,
+	// newest first.
+	newer := make(map[string][][]DiffHunk)
+
+	analysis := &HistoryAnalysis{Commits: len(commits)}
+	churn := make(map[string]*functionChurn)
+	pairs := make(map[[2]string]int)
+	for _, c := range commits {
+		if analysis.Newest == 0 {
+			analysis.Newest = c.Time
+		}
+		analysis.Oldest = c.Time
+
+		touched := make(map[string]int)
+		for _, f := range c.Files {
+			analysis.LinesAdded += f.Added
+			analysis.LinesDeleted += f.Deleted
+			if f.Path == "" {
+				// Deleted: older changes belong to a file gone since.
+				current[f.OldPath] = ""
+				continue
+			}
+			target := resolve(f.Path)
+			if fns := byFile[target]; target != "" && !f.Binary && len(fns) > 0 {
+				history := newer[target]
+				for _, span := range changedSpans(f.Hunks) {
+					pieces := []lineSpan{span.lineSpan}
+					for i := len(history) - 1; i >= 0 && len(pieces) > 0; i-- {
+						pieces = carrySpans(pieces, history[i])
+					}
+					if len(pieces) == 0 {
+						continue
+					}
+					for _, fn := range fns {
+						if n := linesTouched(fn, pieces, span.deletion); n > 0 {
+							touched[fn.ID] += n
+						}
+					}
+				}
+				newer[target] = append(history, f.Hunks)
+			}
+			switch {
+			case f.OldPath == "":
+				// Created: an older file with this path is a different file.
+				current[f.Path] = ""
+			case f.OldPath != f.Path:
+				current[f.Path] = ""
+				current[f.OldPath] = target
+			}
+		}
+		if len(touched) == 0 {
+			continue
+		}
+
+		ids := make([]string, 0, len(touched))
+		for id, lines := range touched {
+			ids = append(ids, id)
+			fc := churn[id]
+			if fc == nil {
+				fc = &functionChurn{authors: make(map[string]bool), last: c.Time}
+				churn[id] = fc
+			}
+			fc.commits++
+			fc.lines += lines
+			fc.authors[c.Author] = true
+			fc.first = c.Time
+		}
+		if len(ids) > opts.MaxChangeset {
+			analysis.LargeCommits++
+			continue
+		}
+		sort.Strings(ids)
+		for i, a := range ids {
+			churn[a].couplingCommits++
+			for _, b := range ids[i+1:] {
+				pairs[[2]string{a, b}]++
+			}
+		}
+	}
+
+	for id, fc := range churn {
+		analysis.Churn = append(analysis.Churn, FunctionChurnEntity{
+			FunctionID:   id,
+			FilePath:     filePaths[id],
+			Commits:      fc.commits,
+			Authors:      len(fc.authors),
+			LinesChanged: fc.lines,
+			FirstChanged: fc.first,
+			LastChanged:  fc.last,
+		})
+	}
+	sort.Slice(analysis.Churn, func(i, j int) bool { return analysis.Churn[i].FunctionID < analysis.Churn[j].FunctionID })
+
+	for pair, count := range pairs {
+		if count < opts.MinCoChanges {
+			continue
+		}
+		for _, p := range [][2]string{pair, {pair[1], pair[0]}} {
+			analysis.Coupling = append(analysis.Coupling, ChangeCouplingEntity{
+				FunctionID: p[0],
+				CoupledID:  p[1],
+				FilePath:   filePaths[p[0]],
+				CoChanges:  count,
+				Degree:     float64(count) / float64(churn[p[0]].couplingCommits),
+			})
+		}
+	}
+	sort.Slice(analysis.Coupling, func(i, j int) bool {
+		a, b := analysis.Coupling[i], analysis.Coupling[j]
+		if a.FunctionID != b.FunctionID {
+			return a.FunctionID < b.FunctionID
+		}
+		return a.CoupledID < b.CoupledID
+	})
+	return analysis
+}
+
+// ImportHistory walks the git history of the repository, maps it onto the
+// indexed functions and replaces the contents of cie_function_churn and
+// cie_change_coupling with the result.
+func ImportHistory(ctx context.Context, backend storage.Backend, git tools.GitRunner, opts HistoryOptions) (*HistoryAnalysis, error) {
+	opts = opts.withDefaults()
+	output, err := git.Run(ctx, historyLogArgs(opts)...)
+	if err != nil {
+		return nil, fmt.Errorf("read git history: %w", err)
+	}
+	commits := ParseGitLog(output)
+
+	rows, err := backend.Query(ctx, `?[id, name, file_path, start_line, end_line] := *cie_function { id, name, file_path, start_line, end_line }`)
+	if err != nil {
+		return nil, fmt.Errorf("query indexed functions: %w", err)
+	}
+	functions := make([]FunctionEntity, 0, len(rows.Rows))
+	for _, row := range rows.Rows {
+		functions = append(functions, FunctionEntity{
+			ID:        tools.AnyToString(row[0]),
+			Name:      tools.AnyToString(row[1]),
+			FilePath:  tools.AnyToString(row[2]),
+			StartLine: coverageInt(row[3]),
+			EndLine:   coverageInt(row[4]),
+		})
+	}
+
+	analysis := AnalyzeHistory(commits, functions, opts)
+
+	if err := backend.Execute(ctx, `?[function_id] := *cie_function_churn { function_id } :rm cie_function_churn { function_id }`); err != nil {
+		return nil, fmt.Errorf("clear previous churn: %w", err)
+	}
+	if err := backend.Execute(ctx, `?[function_id, coupled_id] := *cie_change_coupling { function_id, coupled_id } :rm cie_change_coupling { function_id, coupled_id }`); err != nil {
+		return nil, fmt.Errorf("clear previous change coupling: %w", err)
+	}
+	builder := NewDatalogBuilder()
+	if err := executeInBatches(ctx, backend, analysis.Churn, builder.BuildFunctionChurnMutations); err != nil {
+		return nil, fmt.Errorf("write churn: %w", err)
+	}
+	if err := executeInBatches(ctx, backend, analysis.Coupling, builder.BuildChangeCouplingMutations); err != nil {
+		return nil, fmt.Errorf("write change coupling: %w", err)
+	}
+	return analysis, nil
+}
+
+// executeInBatches writes rows historyBatchSize at a time.
+func executeInBatches[T any](ctx context.Context, backend storage.Backend, rows []T, build func([]T) string) error {
+	for start := 0; start < len(rows); start += historyBatchSize {
+		end := min(start+historyBatchSize, len(rows))
+		if err := backend.Execute(ctx, build(rows[start:end])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historyLog is git log output in the history pass format, newest first:
+// c3 inserts an import block above every function, c2 renames
+// old/store.go to store.go while changing Get and Put, c1 changes Get and
+// Del in old/store.go.
+var historyLog = strings.Join([]string{
+	"\x1ecommit c3\x1fAlice@Example.com\x1f300",
+	"",
+	"5\t0\tstore.go",
+	"",
+	"diff --git a/store.go b/store.go",
+	"index 1111111..2222222 100644",
+	"--- a/store.go",
+	"+++ b/store.go",
+	"@@ -2,0 +3,5 @@ package store",
+	"+import (",
+	"+\t\"fmt\"",
+	"+--- a/looks/like/a/header",
+	"+)",
+	"+",
+	"\x1ecommit c2\x1fbob@example.com\x1f200",
+	"",
+	"3\t3\t{old => }/store.go",
+	"-\t-\tlogo.png",
+	"",
+	"diff --git a/old/store.go b/store.go",
+	"similarity index 90%",
+	"rename from old/store.go",
+	"rename to store.go",
+	"index 3333333..1111111 100644",
+	"--- a/old/store.go",
+	"+++ b/store.go",
+	"@@ -6,2 +6,2 @@ func Get() {",
+	"-a",
+	"-b",
+	"+c",
+	"+d",
+	"@@ -18 +18 @@ func Put() {",
+	"--- a/old",
+	"+x",
+	"diff --git a/logo.png b/logo.png",
+	"index 4444444..5555555 100644",
+	"Binary files a/logo.png and b/logo.png differ",
+	"\x1ecommit c1\x1falice@example.com\x1f100",
+	"",
+	"3\t0\told/store.go",
+	"",
+	"diff --git a/old/store.go b/old/store.go",
+	"index 6666666..3333333 100644",
+	"--- a/old/store.go",
+	"+++ b/old/store.go",
+	"@@ -5,0 +6,2 @@",
+	"+e",
+	"+f",
+	"@@ -29,0 +30 @@",
+	"+g",
+	"",
+}, "\n")
+
+func TestParseGitLog(t *testing.T) {
+	commits := ParseGitLog(historyLog)
+	require.Len(t, commits, 3)
+
+	c3 := commits[0]
+	assert.Equal(t, "c3", c3.Hash)
+	assert.Equal(t, "alice@example.com", c3.Author)
+	assert.Equal(t, int64(300), c3.Time)
+	require.Len(t, c3.Files, 1, "changed lines looking like headers are hunk content")
+	assert.Equal(t, GitFileChange{OldPath: "store.go", Path: "store.go", Added: 5, Hunks: []DiffHunk{{2, 0, 3, 5}}}, c3.Files[0])
+
+	c2 := commits[1]
+	require.Len(t, c2.Files, 2)
+	assert.Equal(t, GitFileChange{
+		OldPath: "old/store.go", Path: "store.go", Added: 3, Deleted: 3,
+		Hunks: []DiffHunk{{6, 2, 6, 2}, {18, 1, 18, 1}},
+	}, c2.Files[0])
+	assert.True(t, c2.Files[1].Binary)
+	assert.Equal(t, "logo.png", c2.Files[1].Path)
+}
+
+func TestNumstatPath(t *testing.T) {
+	assert.Equal(t, "pkg/store.go", numstatPath("pkg/store.go"))
+	assert.Equal(t, "new.go", numstatPath("old.go => new.go"))
+	assert.Equal(t, "pkg/new/store.go", numstatPath("pkg/{old => new}/store.go"))
+	assert.Equal(t, "store.go", numstatPath("{old => }/store.go"))
+}
+
+func TestParseUnifiedDiff_Plain(t *testing.T) {
+	diff := "--- a/a.go\t2026-01-01\n+++ b/a.go\t2026-01-02\n@@ -1,3 +1,3 @@\n package a\n-var x = 1\n+var x = 2\n \n--- /dev/null\n+++ b/b.go\n@@ -0,0 +1 @@\n+package b\n"
+	files := parseUnifiedDiff(strings.Split(diff, "\n"))
+	require.Len(t, files, 2)
+	assert.Equal(t, "a.go", files[0].Path)
+	assert.Equal(t, []DiffHunk{{2, 1, 2, 1}}, files[0].Hunks)
+	assert.Equal(t, "", files[1].OldPath)
+	assert.Equal(t, "b.go", files[1].Path)
+}
+
+func TestCarrySpans(t *testing.T) {
+	hunks := []DiffHunk{
+		{OldStart: 2, OldLines: 0, NewStart: 3, NewLines: 5},   // 5 lines inserted after line 2
+		{OldStart: 10, OldLines: 2, NewStart: 15, NewLines: 1}, // lines 10-11 replaced by one line
+		{OldStart: 20, OldLines: 3, NewStart: 23, NewLines: 0}, // lines 20-22 deleted
+	}
+	tests := []struct {
+		name string
+		span lineSpan
+		want []lineSpan
+	}{
+		{"before the hunks", lineSpan{1, 2}, []lineSpan{{1, 2}}},
+		{"shifted by the insertion", lineSpan{4, 6}, []lineSpan{{9, 11}}},
+		{"split by the insertion", lineSpan{2, 3}, []lineSpan{{2, 2}, {8, 8}}},
+		{"replaced lines", lineSpan{9, 11}, []lineSpan{{14, 14}, {15, 15}}},
+		{"deleted lines", lineSpan{20, 22}, nil},
+		{"after the deletion", lineSpan{24, 25}, []lineSpan{{25, 26}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, carrySpans([]lineSpan{tt.span}, hunks))
+		})
+	}
+}
+
+func TestAnalyzeHistory(t *testing.T) {
+	functions := []FunctionEntity{
+		{ID: "get", Name: "Get", FilePath: "store.go", StartLine: 10, EndLine: 20},
+		{ID: "put", Name: "Put", FilePath: "store.go", StartLine: 22, EndLine: 30},
+		{ID: "del", Name: "Del", FilePath: "store.go", StartLine: 32, EndLine: 40},
+		{ID: "anon", Name: "$anon_1", FilePath: "store.go", StartLine: 11, EndLine: 12},
+	}
+	analysis := AnalyzeHistory(ParseGitLog(historyLog), functions, HistoryOptions{MinCoChanges: 1})
+
+	assert.Equal(t, 3, analysis.Commits)
+	assert.Equal(t, 11, analysis.LinesAdded)
+	assert.Equal(t, 3, analysis.LinesDeleted)
+	assert.Equal(t, int64(100), analysis.Oldest)
+	assert.Equal(t, int64(300), analysis.Newest)
+
+	// c1 and c2 lines are carried through the c3 insertion and the rename.
+	assert.Equal(t, []FunctionChurnEntity{
+		{FunctionID: "del", FilePath: "store.go", Commits: 1, Authors: 1, LinesChanged: 1, FirstChanged: 100, LastChanged: 100},
+		{FunctionID: "get", FilePath: "store.go", Commits: 2, Authors: 2, LinesChanged: 4, FirstChanged: 100, LastChanged: 200},
+		{FunctionID: "put", FilePath: "store.go", Commits: 1, Authors: 1, LinesChanged: 1, FirstChanged: 200, LastChanged: 200},
+	}, analysis.Churn)
+
+	assert.Equal(t, []ChangeCouplingEntity{
+		{FunctionID: "del", CoupledID: "get", FilePath: "store.go", CoChanges: 1, Degree: 1},
+		{FunctionID: "get", CoupledID: "del", FilePath: "store.go", CoChanges: 1, Degree: 0.5},
+		{FunctionID: "get", CoupledID: "put", FilePath: "store.go", CoChanges: 1, Degree: 0.5},
+		{FunctionID: "put", CoupledID: "get", FilePath: "store.go", CoChanges: 1, Degree: 1},
+	}, analysis.Coupling)
+
+	// Commits changing more functions than MaxChangeset are left out of coupling.
+	analysis = AnalyzeHistory(ParseGitLog(historyLog), functions, HistoryOptions{MaxChangeset: 1, MinCoChanges: 1})
+	assert.Equal(t, 2, analysis.LargeCommits)
+	assert.Empty(t, analysis.Coupling)
+	assert.Len(t, analysis.Churn, 3)
+}
+
+func TestAnalyzeHistory_DeletedFunction(t *testing.T) {
+	// Deleting lines 5-9, a whole function, only touches a function holding
+	// both lines around them.
+	commits := []GitCommit{{Hash: "c1", Author: "a", Time: 1, Files: []GitFileChange{{
+		OldPath: "a.go", Path: "a.go", Hunks: []DiffHunk{{OldStart: 5, OldLines: 5, NewStart: 4, NewLines: 0}},
+	}}}}
+	functions := []FunctionEntity{
+		{ID: "before", Name: "Before", FilePath: "a.go", StartLine: 1, EndLine: 4},
+		{ID: "after", Name: "After", FilePath: "a.go", StartLine: 5, EndLine: 8},
+	}
+	assert.Empty(t, AnalyzeHistory(commits, functions, HistoryOptions{}).Churn)
+
+	functions = []FunctionEntity{{ID: "outer", Name: "Outer", FilePath: "a.go", StartLine: 1, EndLine: 8}}
+	churn := AnalyzeHistory(commits, functions, HistoryOptions{}).Churn
+	require.Len(t, churn, 1)
+	assert.Equal(t, 1, churn[0].LinesChanged)
+}
+
+func TestAnalyzeHistory_ContextHunks(t *testing.T) {
+	// The same commit as git log -p --unified=0 writes it and as a diff with
+	// three context lines: only line 3, in A, changes; B's first lines are
+	// context.
+	commitLog := func(hunk ...string) string {
+		return strings.Join(append([]string{
+			"\x1ecommit c1\x1falice@example.com\x1f100",
+			"",
+			"1\t1\ta.go",
+			"",
+			"diff --git a/a.go b/a.go",
+			"index 1111111..2222222 100644",
+			"--- a/a.go",
+			"+++ b/a.go",
+		}, hunk...), "\n")
+	}
+	functions := []FunctionEntity{
+		{ID: "a", Name: "A", FilePath: "a.go", StartLine: 1, EndLine: 4},
+		{ID: "b", Name: "B", FilePath: "a.go", StartLine: 6, EndLine: 9},
+	}
+	want := []FunctionChurnEntity{
+		{FunctionID: "a", FilePath: "a.go", Commits: 1, Authors: 1, LinesChanged: 1, FirstChanged: 100, LastChanged: 100},
+	}
+
+	zero := AnalyzeHistory(ParseGitLog(commitLog("@@ -3 +3 @@ func A() {", "-\tx := 1", "+\tx := 2")), functions, HistoryOptions{})
+	assert.Equal(t, want, zero.Churn)
+
+	context := AnalyzeHistory(ParseGitLog(commitLog(
+		"@@ -1,6 +1,6 @@",
+		" func A() {",
+		" \t// x",
+		"-\tx := 1",
+		"+\tx := 2",
+		" }",
+		" ",
+		" func B() {",
+	)), functions, HistoryOptions{})
+	assert.Equal(t, want, context.Churn, "context lines are not counted as changed")
+}
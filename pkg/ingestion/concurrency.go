@@ -0,0 +1,307 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"fmt"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// GO CONCURRENCY OPERATIONS
+// =============================================================================
+
+// goConcurrencyCollector records the goroutine spawns, channel operations and
+// lock acquisitions of the functions of one Go file. walkGoCallExpressionsV2
+// passes it every node of a function body except those of nested function
+// literals, which are walked as their own functions.
+type goConcurrencyCollector struct {
+	content      []byte
+	filePath     string
+	funcNameToID map[string]string
+	anonFuncs    map[uint32]FunctionEntity // Start byte of a func_literal -> its function
+	chanFields   map[string]bool           // Struct fields of the file declared as channels
+
+	functionID string
+	receiver   string            // Receiver name of the current method
+	varTypes   map[string]string // Receiver and parameters of the current function -> type name
+	chanVars   map[string]bool   // Parameters and locals of the current function holding a channel
+	seen       map[string]bool
+	edges      []ConcurrencyEdge
+}
+
+// newGoConcurrencyCollector creates a collector for the functions of ctx.
+// fields are the struct fields declared in the file.
+func newGoConcurrencyCollector(ctx *goFunctionContext, fields []FieldEntity) *goConcurrencyCollector {
+	c := &goConcurrencyCollector{
+		content:      ctx.content,
+		filePath:     ctx.filePath,
+		funcNameToID: ctx.funcNameToID,
+		anonFuncs:    make(map[uint32]FunctionEntity),
+		chanFields:   make(map[string]bool),
+		seen:         make(map[string]bool),
+	}
+	for _, fn := range ctx.functions {
+		if fn.node.Type() == "func_literal" {
+			c.anonFuncs[fn.node.StartByte()] = fn.entity
+		}
+	}
+	for _, f := range fields {
+		if isGoChannelType(f.FieldType) {
+			c.chanFields[f.FieldName] = true
+		}
+	}
+	return c
+}
+
+// enter prepares the collector for the operations of fn: the types of its
+// receiver and parameters, and the names holding channels.
+func (c *goConcurrencyCollector) enter(fn goFunctionWithNode) {
+	c.functionID = fn.entity.ID
+	c.receiver = ""
+	c.varTypes = make(map[string]string)
+	c.chanVars = make(map[string]bool)
+
+	walkGoScopeParams(fn.node, c.content, func(name string, typeNode *sitter.Node, own bool) {
+		c.varTypes[name] = goValueTypeName(typeNode, c.content)
+		if typeNode != nil && typeNode.Type() == "channel_type" {
+			c.chanVars[name] = true
+		}
+	})
+	for node := fn.node; node != nil; node = node.Parent() {
+		if node.Type() == "method_declaration" {
+			if receiver := node.ChildByFieldName("receiver"); receiver != nil && receiver.NamedChildCount() > 0 {
+				if name := receiver.NamedChild(0).ChildByFieldName("name"); name != nil {
+					c.receiver = nodeText(name, c.content)
+				}
+			}
+		}
+		if node.Parent() == nil || node.Type() == "function_declaration" || node.Type() == "method_declaration" {
+			c.collectChanLocals(node)
+			break
+		}
+	}
+}
+
+// collectChanLocals records the variables declared in the outermost enclosing
+// function that hold a channel: ch := make(chan T), var ch chan T.
+func (c *goConcurrencyCollector) collectChanLocals(node *sitter.Node) {
+	switch node.Type() {
+	case "short_var_declaration":
+		left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+		if left != nil && right != nil {
+			for i := 0; i < int(left.NamedChildCount()) && i < int(right.NamedChildCount()); i++ {
+				if isGoMakeChan(right.NamedChild(i), c.content) {
+					c.chanVars[nodeText(left.NamedChild(i), c.content)] = true
+				}
+			}
+		}
+	case "var_spec":
+		typeNode, value := node.ChildByFieldName("type"), node.ChildByFieldName("value")
+		isChan := typeNode != nil && typeNode.Type() == "channel_type"
+		if value != nil && value.NamedChildCount() > 0 && isGoMakeChan(value.NamedChild(0), c.content) {
+			isChan = true
+		}
+		if isChan {
+			for i := 0; i < int(node.ChildCount()); i++ {
+				if node.FieldNameForChild(i) == "name" {
+					c.chanVars[nodeText(node.Child(i), c.content)] = true
+				}
+			}
+		}
+	}
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		c.collectChanLocals(node.NamedChild(i))
+	}
+}
+
+// visit records the concurrency operation performed by node, if any:
+//   - spawn: go s.worker(ctx), go func() { ... }()
+//   - send: s.jobs <- job
+//   - receive: <-s.done, for job := range s.jobs (when s.jobs is a channel)
+//   - lock, rlock, unlock, runlock: s.mu.Lock(), s.mu.RUnlock()
+//
+// Deferred unlocks are not recorded: the lock is held until the function
+// returns. Receives from calls (<-ctx.Done(), <-time.After(d)) are skipped.
+func (c *goConcurrencyCollector) visit(node *sitter.Node) {
+	switch node.Type() {
+	case "go_statement":
+		if node.NamedChildCount() > 0 && node.NamedChild(0).Type() == "call_expression" {
+			c.spawn(node.NamedChild(0))
+		}
+	case "send_statement":
+		c.add("send", c.targetName(node.ChildByFieldName("channel")), "", node)
+	case "unary_expression":
+		if op := node.ChildByFieldName("operator"); op != nil && op.Type() == "<-" {
+			c.add("receive", c.targetName(node.ChildByFieldName("operand")), "", node)
+		}
+	case "range_clause":
+		if right := node.ChildByFieldName("right"); right != nil && c.isChannel(right) {
+			c.add("receive", c.targetName(right), "", node)
+		}
+	case "call_expression":
+		c.lock(node)
+	}
+}
+
+// spawn records the function started by a go statement.
+func (c *goConcurrencyCollector) spawn(call *sitter.Node) {
+	fn := call.ChildByFieldName("function")
+	if fn == nil {
+		return
+	}
+	if fn.Type() == "func_literal" {
+		if anon, ok := c.anonFuncs[fn.StartByte()]; ok {
+			c.add("spawn", anon.Name, anon.ID, call)
+		}
+		return
+	}
+
+	target := nodeText(fn, c.content)
+	simpleName := target
+	if fn.Type() == "selector_expression" {
+		if field := fn.ChildByFieldName("field"); field != nil {
+			simpleName = nodeText(field, c.content)
+		}
+	}
+	c.add("spawn", target, c.funcNameToID[simpleName], call)
+}
+
+// lock records a Lock, RLock, Unlock or RUnlock call without arguments.
+func (c *goConcurrencyCollector) lock(call *sitter.Node) {
+	fn := call.ChildByFieldName("function")
+	args := call.ChildByFieldName("arguments")
+	if fn == nil || fn.Type() != "selector_expression" || (args != nil && args.NamedChildCount() > 0) {
+		return
+	}
+	field := fn.ChildByFieldName("field")
+	if field == nil {
+		return
+	}
+
+	var kind string
+	switch nodeText(field, c.content) {
+	case "Lock":
+		kind = "lock"
+	case "RLock":
+		kind = "rlock"
+	case "Unlock":
+		kind = "unlock"
+	case "RUnlock":
+		kind = "runlock"
+	default:
+		return
+	}
+	if parent := call.Parent(); parent != nil && parent.Type() == "defer_statement" && strings.HasSuffix(kind, "unlock") {
+		return
+	}
+	c.add(kind, c.targetName(fn.ChildByFieldName("operand")), "", call)
+}
+
+// isChannel reports whether a ranged expression is a channel: a parameter or
+// local declared as a channel, or a struct field of the file declared as one.
+func (c *goConcurrencyCollector) isChannel(node *sitter.Node) bool {
+	switch node.Type() {
+	case "identifier":
+		return c.chanVars[nodeText(node, c.content)]
+	case "selector_expression":
+		if field := node.ChildByFieldName("field"); field != nil {
+			return c.chanFields[nodeText(field, c.content)]
+		}
+	}
+	return false
+}
+
+// targetName names a channel or mutex expression. Selector chains rooted at
+// the receiver or a parameter of a named type are named after the type
+// ("s.mu" → "Server.mu") so the same field matches across functions, as is
+// the receiver itself (an embedded sync.Mutex). Other identifiers and
+// selectors are kept as written; other expressions return "".
+func (c *goConcurrencyCollector) targetName(node *sitter.Node) string {
+	if node == nil {
+		return ""
+	}
+	switch node.Type() {
+	case "identifier":
+		name := nodeText(node, c.content)
+		if name == c.receiver && c.varTypes[name] != "" {
+			return c.varTypes[name]
+		}
+		return name
+	case "selector_expression":
+		root := node
+		for root.Type() == "selector_expression" {
+			if root = root.ChildByFieldName("operand"); root == nil {
+				return ""
+			}
+		}
+		if root.Type() != "identifier" {
+			return ""
+		}
+		text := strings.Join(strings.Fields(nodeText(node, c.content)), "")
+		rootName := nodeText(root, c.content)
+		if typeName := c.varTypes[rootName]; typeName != "" {
+			return typeName + strings.TrimPrefix(text, rootName)
+		}
+		return text
+	}
+	return ""
+}
+
+// add records an operation on target once per line.
+func (c *goConcurrencyCollector) add(kind, target, targetID string, node *sitter.Node) {
+	if target == "" {
+		return
+	}
+	line := int(node.StartPoint().Row) + 1
+	key := fmt.Sprintf("%s|%s|%s|%d", c.functionID, kind, target, line)
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.edges = append(c.edges, ConcurrencyEdge{
+		ID:         GenerateConcurrencyID(c.functionID, kind, target, line),
+		FunctionID: c.functionID,
+		Kind:       kind,
+		Target:     target,
+		TargetID:   targetID,
+		FilePath:   c.filePath,
+		Line:       line,
+	})
+}
+
+// isGoMakeChan reports whether node is a make(chan T) call.
+func isGoMakeChan(node *sitter.Node, content []byte) bool {
+	if node.Type() != "call_expression" {
+		return false
+	}
+	fn, args := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+	return fn != nil && nodeText(fn, content) == "make" &&
+		args != nil && args.NamedChildCount() > 0 && args.NamedChild(0).Type() == "channel_type"
+}
+
+// isGoChannelType reports whether a declared type is a channel type:
+// "chan T", "<-chan T" or "chan<- T".
+func isGoChannelType(typeName string) bool {
+	return strings.HasPrefix(typeName, "chan") || strings.HasPrefix(typeName, "<-chan")
+}
@@ -0,0 +1,107 @@
+package ingestion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseConcurrency parses a Go source and returns its concurrency edges as
+// "function kind target [-> spawned] :line".
+func parseConcurrency(t *testing.T, content string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, "worker.go")
+	require.NoError(t, os.WriteFile(fullPath, []byte(content), 0600))
+
+	result, err := NewTreeSitterParser(nil).ParseFile(FileInfo{Path: "worker.go", FullPath: fullPath, Size: int64(len(content)), Language: "go"})
+	require.NoError(t, err)
+
+	names := make(map[string]string)
+	for _, fn := range result.Functions {
+		names[fn.ID] = fn.Name
+	}
+	var got []string
+	for _, e := range result.Concurrency {
+		assert.Equal(t, GenerateConcurrencyID(e.FunctionID, e.Kind, e.Target, e.Line), e.ID)
+		entry := fmt.Sprintf("%s %s %s", names[e.FunctionID], e.Kind, e.Target)
+		if e.TargetID != "" {
+			entry += " -> " + names[e.TargetID]
+		}
+		got = append(got, fmt.Sprintf("%s :%d", entry, e.Line))
+	}
+	return got
+}
+
+// TestGoConcurrency tests goroutine spawns, channel operations and lock
+// acquisitions, with targets named after the receiver or parameter type.
+func TestGoConcurrency(t *testing.T) {
+	got := parseConcurrency(t, `package worker
+
+import (
+	"context"
+	"sync"
+)
+
+type Pool struct {
+	mu    sync.Mutex
+	stats sync.RWMutex
+	jobs  chan Job
+	done  chan struct{}
+}
+
+func (p *Pool) Start(ctx context.Context) {
+	go p.run(ctx)
+	go func() {
+		p.mu.Lock()
+		p.done <- struct{}{}
+		p.mu.Unlock()
+	}()
+}
+
+func (p *Pool) run(ctx context.Context) {
+	for job := range p.jobs {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.stats.RLock()
+		_ = job
+		p.stats.RUnlock()
+	}
+	select {
+	case <-ctx.Done():
+	case <-p.done:
+	}
+}
+
+func Submit(p *Pool, j Job) {
+	p.jobs <- j
+	results := make(chan int)
+	for r := range results {
+		_ = r
+	}
+	for _, x := range []int{1} {
+		_ = x
+	}
+}
+`)
+
+	assert.ElementsMatch(t, []string{
+		"Pool.Start spawn p.run -> Pool.run :16",
+		"Pool.Start spawn $anon_1 -> $anon_1 :17",
+		"$anon_1 lock Pool.mu :18",
+		"$anon_1 send Pool.done :19",
+		"$anon_1 unlock Pool.mu :20",
+		"Pool.run receive Pool.jobs :25",
+		"Pool.run lock Pool.mu :26",
+		"Pool.run rlock Pool.stats :28",
+		"Pool.run runlock Pool.stats :30",
+		"Pool.run receive Pool.done :34",
+		"Submit send Pool.jobs :39",
+		"Submit receive results :41",
+	}, got)
+}
@@ -0,0 +1,437 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kraklabs/cie/pkg/storage"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// =============================================================================
+// COVERAGE PROFILES
+// =============================================================================
+
+// Coverage profile formats accepted by ParseCoverageProfile.
+const (
+	CoverageFormatGo        = "go"        // go test -coverprofile
+	CoverageFormatCobertura = "cobertura" // coverage.py XML (coverage xml), Jest/Istanbul cobertura reporter
+	CoverageFormatLcov      = "lcov"      // lcov tracefiles (genhtml, Istanbul, c8)
+)
+
+// CoverageBlock is a range of lines of a coverage profile, with the number of
+// statements it holds and how many times they ran.
+type CoverageBlock struct {
+	StartLine  int
+	EndLine    int
+	Statements int
+	Count      int
+}
+
+// CoverageProfile holds the blocks of a coverage profile, keyed by file path
+// as written in the profile (an import path for Go, usually an absolute or
+// source-relative path for the others).
+type CoverageProfile struct {
+	Format string
+	Files  map[string][]CoverageBlock
+}
+
+// DetectCoverageFormat guesses the format of a coverage profile from its
+// content, or returns "" if it is not recognized.
+func DetectCoverageFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return CoverageFormatGo
+	case bytes.HasPrefix(trimmed, []byte("<?xml")) || bytes.HasPrefix(trimmed, []byte("<coverage")):
+		return CoverageFormatCobertura
+	case bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")):
+		return CoverageFormatLcov
+	}
+	return ""
+}
+
+// ParseCoverageProfile parses a coverage profile. An empty format is
+// detected from the content.
+func ParseCoverageProfile(data []byte, format string) (*CoverageProfile, error) {
+	if format == "" {
+		format = DetectCoverageFormat(data)
+	}
+	var (
+		files map[string][]CoverageBlock
+		err   error
+	)
+	switch format {
+	case CoverageFormatGo:
+		files, err = parseGoCoverProfile(data)
+	case CoverageFormatCobertura:
+		files, err = parseCoberturaXML(data)
+	case CoverageFormatLcov:
+		files, err = parseLcov(data)
+	case "":
+		return nil, fmt.Errorf("unrecognized coverage profile: expected a go test -coverprofile, Cobertura XML or lcov file")
+	default:
+		return nil, fmt.Errorf("unknown coverage format %q (expected %s, %s or %s)", format, CoverageFormatGo, CoverageFormatCobertura, CoverageFormatLcov)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s coverage: %w", format, err)
+	}
+	return &CoverageProfile{Format: format, Files: files}, nil
+}
+
+// parseGoCoverProfile parses the output of go test -coverprofile:
+//
+//	mode: set
+//	github.com/org/repo/pkg/file.go:12.34,15.2 3 1
+//
+// Blocks reported by several test binaries (go test -coverpkg) are merged by
+// summing their counts.
+func parseGoCoverProfile(data []byte) (map[string][]CoverageBlock, error) {
+	files := make(map[string][]CoverageBlock)
+	seen := make(map[string]int) // "file:start,end" -> index in files[file]
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// file:startLine.startCol,endLine.endCol numStmts count
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: missing file name", lineNo)
+		}
+		file := line[:colon]
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 'start,end statements count'", lineNo)
+		}
+		startPos, endPos, ok := strings.Cut(fields[0], ",")
+		if !ok {
+			return nil, fmt.Errorf("line %d: malformed block %q", lineNo, fields[0])
+		}
+		start, err1 := goCoverLine(startPos)
+		end, err2 := goCoverLine(endPos)
+		stmts, err3 := strconv.Atoi(fields[1])
+		count, err4 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			return nil, fmt.Errorf("line %d: malformed block %q", lineNo, line[colon+1:])
+		}
+
+		key := file + ":" + fields[0]
+		if i, ok := seen[key]; ok {
+			files[file][i].Count += count
+			continue
+		}
+		seen[key] = len(files[file])
+		files[file] = append(files[file], CoverageBlock{StartLine: start, EndLine: end, Statements: stmts, Count: count})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// goCoverLine returns the line of a "line.column" position.
+func goCoverLine(pos string) (int, error) {
+	line, _, _ := strings.Cut(pos, ".")
+	return strconv.Atoi(line)
+}
+
+// coberturaReport is the subset of a Cobertura XML report (coverage.py's
+// coverage xml, Istanbul's cobertura reporter) needed for line coverage.
+type coberturaReport struct {
+	Sources []string `xml:"sources>source"`
+	Classes []struct {
+		Filename string `xml:"filename,attr"`
+		Lines    []struct {
+			Number int `xml:"number,attr"`
+			Hits   int `xml:"hits,attr"`
+		} `xml:"lines>line"`
+	} `xml:"packages>package>classes>class"`
+}
+
+// parseCoberturaXML parses a Cobertura XML report. File names are relative to
+// the first <source> directory, which is joined to them.
+func parseCoberturaXML(data []byte) (map[string][]CoverageBlock, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	source := ""
+	if len(report.Sources) > 0 {
+		source = strings.TrimSpace(report.Sources[0])
+	}
+
+	files := make(map[string][]CoverageBlock)
+	for _, class := range report.Classes {
+		file := class.Filename
+		if source != "" && !path.IsAbs(file) {
+			file = path.Join(source, file)
+		}
+		for _, l := range class.Lines {
+			files[file] = append(files[file], CoverageBlock{StartLine: l.Number, EndLine: l.Number, Statements: 1, Count: l.Hits})
+		}
+	}
+	return files, nil
+}
+
+// parseLcov parses an lcov tracefile: SF: starts a file, DA:line,hits records
+// the execution count of a line and end_of_record closes the file.
+func parseLcov(data []byte) (map[string][]CoverageBlock, error) {
+	files := make(map[string][]CoverageBlock)
+	current := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			current = strings.TrimPrefix(line, "SF:")
+		case line == "end_of_record":
+			current = ""
+		case strings.HasPrefix(line, "DA:"):
+			if current == "" {
+				return nil, fmt.Errorf("line %d: DA record outside of a file", lineNo)
+			}
+			// DA:<line>,<hits>[,<checksum>]
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: malformed DA record %q", lineNo, line)
+			}
+			number, err1 := strconv.Atoi(fields[0])
+			hits, err2 := strconv.Atoi(fields[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("line %d: malformed DA record %q", lineNo, line)
+			}
+			files[current] = append(files[current], CoverageBlock{StartLine: number, EndLine: number, Statements: 1, Count: hits})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// =============================================================================
+// MAPPING ONTO FUNCTIONS
+// =============================================================================
+
+// ResolveCoveragePaths maps the file paths of a profile to indexed file
+// paths. A profile path matches the longest indexed path it ends with on a
+// path boundary, so import paths (github.com/org/repo/pkg/file.go) and
+// absolute CI paths (/home/runner/work/repo/pkg/file.go) both resolve to
+// pkg/file.go. Paths that match no indexed file are left out.
+func ResolveCoveragePaths(profile *CoverageProfile, indexed []string) map[string]string {
+	known := make(map[string]bool, len(indexed))
+	for _, p := range indexed {
+		known[strings.TrimPrefix(path.Clean(strings.ReplaceAll(p, "\\", "/")), "./")] = true
+	}
+
+	resolved := make(map[string]string)
+	for file := range profile.Files {
+		candidate := strings.TrimPrefix(path.Clean(strings.ReplaceAll(file, "\\", "/")), "/")
+		for candidate != "" {
+			if known[candidate] {
+				resolved[file] = candidate
+				break
+			}
+			_, rest, ok := strings.Cut(candidate, "/")
+			if !ok {
+				break
+			}
+			candidate = rest
+		}
+	}
+	return resolved
+}
+
+// MapCoverage maps the blocks of a profile onto the functions of the files
+// they resolve to. A block counts toward every function whose line range
+// contains it, so closures count toward their enclosing function as well.
+// Functions without instrumented statements are left out.
+func MapCoverage(profile *CoverageProfile, resolved map[string]string, functions []FunctionEntity, source string, importedAt time.Time) []CoverageEntity {
+	blocks := make(map[string][]CoverageBlock)
+	for file, fileBlocks := range profile.Files {
+		if indexed, ok := resolved[file]; ok {
+			blocks[indexed] = append(blocks[indexed], fileBlocks...)
+		}
+	}
+
+	var coverage []CoverageEntity
+	for _, fn := range functions {
+		fileBlocks, ok := blocks[fn.FilePath]
+		if !ok {
+			continue
+		}
+		covered, total := 0, 0
+		var uncovered []int
+		for _, b := range fileBlocks {
+			if b.StartLine < fn.StartLine || b.EndLine > fn.EndLine {
+				continue
+			}
+			total += b.Statements
+			if b.Count > 0 {
+				covered += b.Statements
+				continue
+			}
+			for line := b.StartLine; line <= b.EndLine; line++ {
+				uncovered = append(uncovered, line)
+			}
+		}
+		if total == 0 {
+			continue
+		}
+		coverage = append(coverage, CoverageEntity{
+			FunctionID: fn.ID,
+			FilePath:   fn.FilePath,
+			Covered:    covered,
+			Total:      total,
+			Percent:    float64(covered) * 100 / float64(total),
+			Uncovered:  formatLineRanges(uncovered),
+			Source:     source,
+			ImportedAt: importedAt.Unix(),
+		})
+	}
+	return coverage
+}
+
+// formatLineRanges formats line numbers as sorted, merged ranges:
+// "12-14, 20".
+func formatLineRanges(lines []int) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	sort.Ints(lines)
+	var ranges []string
+	start, end := lines[0], lines[0]
+	flush := func() {
+		if start == end {
+			ranges = append(ranges, strconv.Itoa(start))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", start, end))
+		}
+	}
+	for _, line := range lines[1:] {
+		switch {
+		case line <= end:
+		case line == end+1:
+			end = line
+		default:
+			flush()
+			start, end = line, line
+		}
+	}
+	flush()
+	return strings.Join(ranges, ", ")
+}
+
+// CoverageImportResult summarizes a coverage import.
+type CoverageImportResult struct {
+	Format         string   // Profile format
+	FilesMatched   int      // Profile files resolved to indexed files
+	FilesUnmatched []string // Profile files matching no indexed file, sorted
+	Functions      int      // Functions with coverage recorded
+	Covered        int      // Statements covered across those functions
+	Total          int      // Statements instrumented across those functions
+}
+
+// ImportCoverage maps a coverage profile onto the indexed functions and
+// replaces the contents of cie_coverage with the result, so functions no
+// longer in the profile do not keep the coverage of an earlier import.
+func ImportCoverage(ctx context.Context, backend storage.Backend, profile *CoverageProfile, source string, importedAt time.Time) (*CoverageImportResult, error) {
+	files, err := backend.Query(ctx, `?[path] := *cie_file { path }`)
+	if err != nil {
+		return nil, fmt.Errorf("query indexed files: %w", err)
+	}
+	indexed := make([]string, 0, len(files.Rows))
+	for _, row := range files.Rows {
+		indexed = append(indexed, tools.AnyToString(row[0]))
+	}
+	resolved := ResolveCoveragePaths(profile, indexed)
+
+	result := &CoverageImportResult{Format: profile.Format, FilesMatched: len(resolved)}
+	for file := range profile.Files {
+		if _, ok := resolved[file]; !ok {
+			result.FilesUnmatched = append(result.FilesUnmatched, file)
+		}
+	}
+	sort.Strings(result.FilesUnmatched)
+
+	rows, err := backend.Query(ctx, `?[id, file_path, start_line, end_line] := *cie_function { id, file_path, start_line, end_line }`)
+	if err != nil {
+		return nil, fmt.Errorf("query indexed functions: %w", err)
+	}
+	functions := make([]FunctionEntity, 0, len(rows.Rows))
+	for _, row := range rows.Rows {
+		functions = append(functions, FunctionEntity{
+			ID:        tools.AnyToString(row[0]),
+			FilePath:  tools.AnyToString(row[1]),
+			StartLine: coverageInt(row[2]),
+			EndLine:   coverageInt(row[3]),
+		})
+	}
+
+	coverage := MapCoverage(profile, resolved, functions, source, importedAt)
+	for _, c := range coverage {
+		result.Covered += c.Covered
+		result.Total += c.Total
+	}
+	result.Functions = len(coverage)
+
+	if err := backend.Execute(ctx, `?[function_id] := *cie_coverage { function_id } :rm cie_coverage { function_id }`); err != nil {
+		return nil, fmt.Errorf("clear previous coverage: %w", err)
+	}
+	if len(coverage) > 0 {
+		if err := backend.Execute(ctx, NewDatalogBuilder().BuildCoverageMutations(coverage)); err != nil {
+			return nil, fmt.Errorf("write coverage: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// coverageInt converts a numeric query value to int.
+func coverageInt(v any) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int64:
+		return int(n)
+	case int:
+		return n
+	}
+	return 0
+}
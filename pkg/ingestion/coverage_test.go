@@ -0,0 +1,125 @@
+package ingestion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoverageProfile_Go(t *testing.T) {
+	profile := `mode: count
+github.com/org/repo/internal/store/store.go:10.30,12.16 2 3
+github.com/org/repo/internal/store/store.go:12.16,14.3 1 0
+github.com/org/repo/internal/store/store.go:15.2,15.15 1 3
+github.com/org/repo/internal/store/store.go:10.30,12.16 2 1
+`
+	got, err := ParseCoverageProfile([]byte(profile), "")
+	require.NoError(t, err)
+	assert.Equal(t, CoverageFormatGo, got.Format)
+	assert.Equal(t, []CoverageBlock{
+		{StartLine: 10, EndLine: 12, Statements: 2, Count: 4},
+		{StartLine: 12, EndLine: 14, Statements: 1, Count: 0},
+		{StartLine: 15, EndLine: 15, Statements: 1, Count: 3},
+	}, got.Files["github.com/org/repo/internal/store/store.go"])
+
+	_, err = ParseCoverageProfile([]byte("mode: set\nstore.go:10.30 2 1\n"), "")
+	assert.Error(t, err)
+}
+
+func TestParseCoverageProfile_Cobertura(t *testing.T) {
+	profile := `<?xml version="1.0" ?>
+<coverage version="7.4.0" line-rate="0.5">
+	<sources>
+		<source>/home/runner/work/app/app/src</source>
+	</sources>
+	<packages>
+		<package name="billing">
+			<classes>
+				<class name="invoice.py" filename="billing/invoice.py" line-rate="0.5">
+					<lines>
+						<line number="3" hits="1"/>
+						<line number="4" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`
+	got, err := ParseCoverageProfile([]byte(profile), "")
+	require.NoError(t, err)
+	assert.Equal(t, CoverageFormatCobertura, got.Format)
+	assert.Equal(t, []CoverageBlock{
+		{StartLine: 3, EndLine: 3, Statements: 1, Count: 1},
+		{StartLine: 4, EndLine: 4, Statements: 1, Count: 0},
+	}, got.Files["/home/runner/work/app/app/src/billing/invoice.py"])
+}
+
+func TestParseCoverageProfile_Lcov(t *testing.T) {
+	profile := `TN:
+SF:/ci/web/src/users.ts
+FN:3,getUser
+DA:3,5
+DA:4,0,abc123
+end_of_record
+`
+	got, err := ParseCoverageProfile([]byte(profile), "")
+	require.NoError(t, err)
+	assert.Equal(t, CoverageFormatLcov, got.Format)
+	assert.Equal(t, []CoverageBlock{
+		{StartLine: 3, EndLine: 3, Statements: 1, Count: 5},
+		{StartLine: 4, EndLine: 4, Statements: 1, Count: 0},
+	}, got.Files["/ci/web/src/users.ts"])
+
+	_, err = ParseCoverageProfile([]byte("hello"), "")
+	assert.Error(t, err)
+	_, err = ParseCoverageProfile([]byte(profile), "jacoco")
+	assert.Error(t, err)
+}
+
+func TestResolveCoveragePaths(t *testing.T) {
+	profile := &CoverageProfile{Files: map[string][]CoverageBlock{
+		"github.com/org/repo/internal/store/store.go":      nil,
+		"/home/runner/work/app/app/src/billing/invoice.py": nil,
+		"C:\\ci\\web\\src\\users.ts":                       nil,
+		"github.com/org/repo/internal/gone.go":             nil,
+	}}
+	indexed := []string{"internal/store/store.go", "store.go", "src/billing/invoice.py", "web/src/users.ts"}
+
+	assert.Equal(t, map[string]string{
+		"github.com/org/repo/internal/store/store.go":      "internal/store/store.go",
+		"/home/runner/work/app/app/src/billing/invoice.py": "src/billing/invoice.py",
+		"C:\\ci\\web\\src\\users.ts":                       "web/src/users.ts",
+	}, ResolveCoveragePaths(profile, indexed))
+}
+
+func TestMapCoverage(t *testing.T) {
+	profile := &CoverageProfile{Format: CoverageFormatGo, Files: map[string][]CoverageBlock{
+		"github.com/org/repo/store.go": {
+			{StartLine: 10, EndLine: 12, Statements: 2, Count: 4},
+			{StartLine: 12, EndLine: 14, Statements: 1, Count: 0},
+			{StartLine: 16, EndLine: 16, Statements: 1, Count: 0},
+			{StartLine: 17, EndLine: 17, Statements: 1, Count: 1},
+			{StartLine: 30, EndLine: 31, Statements: 2, Count: 0},
+		},
+	}}
+	resolved := map[string]string{"github.com/org/repo/store.go": "store.go"}
+	functions := []FunctionEntity{
+		{ID: "fn:get", Name: "Store.Get", FilePath: "store.go", StartLine: 10, EndLine: 18},
+		{ID: "fn:anon", Name: "$anon_1", FilePath: "store.go", StartLine: 16, EndLine: 17},
+		{ID: "fn:put", Name: "Store.Put", FilePath: "store.go", StartLine: 30, EndLine: 32},
+		{ID: "fn:iface", Name: "Getter.Get", FilePath: "store.go", StartLine: 40, EndLine: 40},
+		{ID: "fn:other", Name: "Other", FilePath: "other.go", StartLine: 10, EndLine: 18},
+	}
+	importedAt := time.Unix(1760000000, 0)
+
+	got := MapCoverage(profile, resolved, functions, "cover.out", importedAt)
+
+	assert.Equal(t, []CoverageEntity{
+		{FunctionID: "fn:get", FilePath: "store.go", Covered: 3, Total: 5, Percent: 60, Uncovered: "12-14, 16", Source: "cover.out", ImportedAt: 1760000000},
+		{FunctionID: "fn:anon", FilePath: "store.go", Covered: 1, Total: 2, Percent: 50, Uncovered: "16", Source: "cover.out", ImportedAt: 1760000000},
+		{FunctionID: "fn:put", FilePath: "store.go", Covered: 0, Total: 2, Percent: 0, Uncovered: "30-31", Source: "cover.out", ImportedAt: 1760000000},
+	}, got)
+}
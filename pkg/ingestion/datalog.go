@@ -29,14 +29,26 @@ import (
 // DatalogBuilder generates Datalog mutation scripts from entities.
 // The generated mutations must match the schema defined in schema.go (v3):
 //   - cie_file: id, path, hash, language, size
-//   - cie_function: id, name, signature, file_path, start_line, end_line, start_col, end_col
+//   - cie_function: id, name, signature, file_path, start_line, end_line, start_col, end_col, doc_comment
 //   - cie_function_code: function_id, code_text
 //   - cie_function_embedding: function_id, embedding
-//   - cie_type: id, name, kind, file_path, start_line, end_line, start_col, end_col
+//   - cie_type: id, name, kind, file_path, start_line, end_line, start_col, end_col, doc_comment
 //   - cie_type_code: type_id, code_text
 //   - cie_type_embedding: type_id, embedding
+//   - cie_variable: id, name, kind, type, value, file_path, code_text, start_line, end_line
 //   - cie_defines: file_id, function_id
 //   - cie_calls: caller_id, callee_id, call_line
+//   - cie_func_ref: id, from_id, to_id, kind, file_path, line
+//   - cie_type_ref: id, from_id, type_id, kind, file_path, line
+//   - cie_type_param: id, owner_id, name, constraint, position, file_path
+//   - cie_instantiation: id, from_id, target_id, target_kind, type_args, file_path, line
+//   - cie_concurrency: id, function_id, kind, target, target_id, file_path, line
+//   - cie_error_site: id, function_id, kind, name, message, file_path, line
+//   - cie_test: id, function_id, name, kind, suite, framework, file_path, line
+//   - cie_function_metrics: function_id, file_path, complexity, max_nesting, params, loc, comment_lines, comment_ratio
+//   - cie_coverage: function_id, file_path, covered, total, percent, uncovered, source, imported_at
+//   - cie_function_churn: function_id, file_path, commits, authors, lines_changed, first_changed, last_changed
+//   - cie_change_coupling: function_id, coupled_id, file_path, co_changes, degree
 type DatalogBuilder struct {
 }
 
@@ -216,7 +228,7 @@ func (db *DatalogBuilder) BuildMutationsWithTypes(
 	// Function entities (v3: split into 3 tables for performance)
 	for _, fn := range functions {
 		// 1. Core metadata (cie_function) - lightweight, ~500 bytes/row
-		buf.WriteString("{ ?[id, name, signature, file_path, start_line, end_line, start_col, end_col] <- [[")
+		buf.WriteString("{ ?[id, name, signature, file_path, start_line, end_line, start_col, end_col, doc_comment] <- [[")
 		buf.WriteString(strings.Join([]string{
 			quoteString(fn.ID),
 			quoteString(fn.Name),
@@ -226,8 +238,9 @@ func (db *DatalogBuilder) BuildMutationsWithTypes(
 			fmt.Sprintf("%d", fn.EndLine),
 			fmt.Sprintf("%d", fn.StartCol),
 			fmt.Sprintf("%d", fn.EndCol),
+			quoteString(fn.DocComment),
 		}, ", "))
-		buf.WriteString("]] :put cie_function { id, name, signature, file_path, start_line, end_line, start_col, end_col } }\n")
+		buf.WriteString("]] :put cie_function { id, name, signature, file_path, start_line, end_line, start_col, end_col, doc_comment } }\n")
 
 		// 2. Code text (cie_function_code) - lazy loaded
 		buf.WriteString("{ ?[function_id, code_text] <- [[")
@@ -253,7 +266,7 @@ func (db *DatalogBuilder) BuildMutationsWithTypes(
 	// Type entities (v3: split into 3 tables for performance)
 	for _, t := range types {
 		// 1. Core metadata (cie_type) - lightweight
-		buf.WriteString("{ ?[id, name, kind, file_path, start_line, end_line, start_col, end_col] <- [[")
+		buf.WriteString("{ ?[id, name, kind, file_path, start_line, end_line, start_col, end_col, doc_comment] <- [[")
 		buf.WriteString(strings.Join([]string{
 			quoteString(t.ID),
 			quoteString(t.Name),
@@ -263,8 +276,9 @@ func (db *DatalogBuilder) BuildMutationsWithTypes(
 			fmt.Sprintf("%d", t.EndLine),
 			fmt.Sprintf("%d", t.StartCol),
 			fmt.Sprintf("%d", t.EndCol),
+			quoteString(t.DocComment),
 		}, ", "))
-		buf.WriteString("]] :put cie_type { id, name, kind, file_path, start_line, end_line, start_col, end_col } }\n")
+		buf.WriteString("]] :put cie_type { id, name, kind, file_path, start_line, end_line, start_col, end_col, doc_comment } }\n")
 
 		// 2. Code text (cie_type_code) - lazy loaded
 		buf.WriteString("{ ?[type_id, code_text] <- [[")
@@ -433,6 +447,527 @@ func (db *DatalogBuilder) BuildFieldAndImplementsMutations(fields []FieldEntity,
 	return buf.String()
 }
 
+// BuildFuncRefMutations generates Datalog :put statements for function ->
+// function reference edges.
+func (db *DatalogBuilder) BuildFuncRefMutations(refs []FuncRefEdge) string {
+	var buf strings.Builder
+
+	for _, r := range refs {
+		buf.WriteString("{ ?[id, from_id, to_id, kind, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(r.ID),
+			quoteString(r.FromID),
+			quoteString(r.ToID),
+			quoteString(r.Kind),
+			quoteString(r.FilePath),
+			fmt.Sprintf("%d", r.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_func_ref { id, from_id, to_id, kind, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildTypeRefMutations generates Datalog :put statements for function/type ->
+// type reference edges.
+func (db *DatalogBuilder) BuildTypeRefMutations(refs []TypeRefEdge) string {
+	var buf strings.Builder
+
+	for _, r := range refs {
+		buf.WriteString("{ ?[id, from_id, type_id, kind, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(r.ID),
+			quoteString(r.FromID),
+			quoteString(r.TypeID),
+			quoteString(r.Kind),
+			quoteString(r.FilePath),
+			fmt.Sprintf("%d", r.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_type_ref { id, from_id, type_id, kind, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildGenericMutations generates Datalog :put statements for type parameters
+// and instantiation edges of generic functions and types.
+func (db *DatalogBuilder) BuildGenericMutations(params []TypeParamEntity, insts []InstantiationEdge) string {
+	var buf strings.Builder
+
+	for _, tp := range params {
+		buf.WriteString("{ ?[id, owner_id, name, constraint, position, file_path] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(tp.ID),
+			quoteString(tp.OwnerID),
+			quoteString(tp.Name),
+			quoteString(tp.Constraint),
+			fmt.Sprintf("%d", tp.Position),
+			quoteString(tp.FilePath),
+		}, ", "))
+		buf.WriteString("]] :put cie_type_param { id, owner_id, name, constraint, position, file_path } }\n")
+	}
+
+	for _, in := range insts {
+		buf.WriteString("{ ?[id, from_id, target_id, target_kind, type_args, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(in.ID),
+			quoteString(in.FromID),
+			quoteString(in.TargetID),
+			quoteString(in.TargetKind),
+			quoteString(in.TypeArgs),
+			quoteString(in.FilePath),
+			fmt.Sprintf("%d", in.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_instantiation { id, from_id, target_id, target_kind, type_args, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildConcurrencyMutations generates Datalog :put statements for goroutine
+// spawns, channel operations and lock acquisitions.
+func (db *DatalogBuilder) BuildConcurrencyMutations(edges []ConcurrencyEdge) string {
+	var buf strings.Builder
+
+	for _, e := range edges {
+		buf.WriteString("{ ?[id, function_id, kind, target, target_id, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(e.ID),
+			quoteString(e.FunctionID),
+			quoteString(e.Kind),
+			quoteString(e.Target),
+			quoteString(e.TargetID),
+			quoteString(e.FilePath),
+			fmt.Sprintf("%d", e.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_concurrency { id, function_id, kind, target, target_id, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildErrorSiteMutations generates Datalog :put statements for error
+// construction, wrapping and raising sites.
+func (db *DatalogBuilder) BuildErrorSiteMutations(sites []ErrorSiteEntity) string {
+	var buf strings.Builder
+
+	for _, e := range sites {
+		buf.WriteString("{ ?[id, function_id, kind, name, message, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(e.ID),
+			quoteString(e.FunctionID),
+			quoteString(e.Kind),
+			quoteString(e.Name),
+			quoteString(e.Message),
+			quoteString(e.FilePath),
+			fmt.Sprintf("%d", e.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_error_site { id, function_id, kind, name, message, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildTestMutations generates Datalog :put statements for tests, subtests
+// and test suites.
+func (db *DatalogBuilder) BuildTestMutations(tests []TestEntity) string {
+	var buf strings.Builder
+
+	for _, t := range tests {
+		buf.WriteString("{ ?[id, function_id, name, kind, suite, framework, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(t.ID),
+			quoteString(t.FunctionID),
+			quoteString(t.Name),
+			quoteString(t.Kind),
+			quoteString(t.Suite),
+			quoteString(t.Framework),
+			quoteString(t.FilePath),
+			fmt.Sprintf("%d", t.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_test { id, function_id, name, kind, suite, framework, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildFunctionMetricsMutations generates Datalog :put statements for
+// function complexity and size metrics.
+func (db *DatalogBuilder) BuildFunctionMetricsMutations(metrics []FunctionMetricsEntity) string {
+	var buf strings.Builder
+
+	for _, m := range metrics {
+		buf.WriteString("{ ?[function_id, file_path, complexity, max_nesting, params, loc, comment_lines, comment_ratio] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(m.FunctionID),
+			quoteString(m.FilePath),
+			fmt.Sprintf("%d", m.Complexity),
+			fmt.Sprintf("%d", m.MaxNesting),
+			fmt.Sprintf("%d", m.Params),
+			fmt.Sprintf("%d", m.LOC),
+			fmt.Sprintf("%d", m.CommentLines),
+			strconv.FormatFloat(m.CommentRatio, 'f', 3, 64),
+		}, ", "))
+		buf.WriteString("]] :put cie_function_metrics { function_id, file_path, complexity, max_nesting, params, loc, comment_lines, comment_ratio } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildCoverageMutations generates Datalog :put statements for function
+// coverage.
+func (db *DatalogBuilder) BuildCoverageMutations(coverage []CoverageEntity) string {
+	var buf strings.Builder
+
+	for _, c := range coverage {
+		buf.WriteString("{ ?[function_id, file_path, covered, total, percent, uncovered, source, imported_at] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(c.FunctionID),
+			quoteString(c.FilePath),
+			fmt.Sprintf("%d", c.Covered),
+			fmt.Sprintf("%d", c.Total),
+			strconv.FormatFloat(c.Percent, 'f', 1, 64),
+			quoteString(c.Uncovered),
+			quoteString(c.Source),
+			fmt.Sprintf("%d", c.ImportedAt),
+		}, ", "))
+		buf.WriteString("]] :put cie_coverage { function_id, file_path, covered, total, percent, uncovered, source, imported_at } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildFunctionChurnMutations generates Datalog :put statements for function
+// churn.
+func (db *DatalogBuilder) BuildFunctionChurnMutations(churn []FunctionChurnEntity) string {
+	var buf strings.Builder
+
+	for _, c := range churn {
+		buf.WriteString("{ ?[function_id, file_path, commits, authors, lines_changed, first_changed, last_changed] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(c.FunctionID),
+			quoteString(c.FilePath),
+			fmt.Sprintf("%d", c.Commits),
+			fmt.Sprintf("%d", c.Authors),
+			fmt.Sprintf("%d", c.LinesChanged),
+			fmt.Sprintf("%d", c.FirstChanged),
+			fmt.Sprintf("%d", c.LastChanged),
+		}, ", "))
+		buf.WriteString("]] :put cie_function_churn { function_id, file_path, commits, authors, lines_changed, first_changed, last_changed } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildChangeCouplingMutations generates Datalog :put statements for change
+// coupling pairs.
+func (db *DatalogBuilder) BuildChangeCouplingMutations(pairs []ChangeCouplingEntity) string {
+	var buf strings.Builder
+
+	for _, p := range pairs {
+		buf.WriteString("{ ?[function_id, coupled_id, file_path, co_changes, degree] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(p.FunctionID),
+			quoteString(p.CoupledID),
+			quoteString(p.FilePath),
+			fmt.Sprintf("%d", p.CoChanges),
+			strconv.FormatFloat(p.Degree, 'f', 3, 64),
+		}, ", "))
+		buf.WriteString("]] :put cie_change_coupling { function_id, coupled_id, file_path, co_changes, degree } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildVariableMutations generates Datalog :put statements for package-level
+// variables and constants.
+func (db *DatalogBuilder) BuildVariableMutations(variables []VariableEntity) string {
+	var buf strings.Builder
+
+	for _, v := range variables {
+		buf.WriteString("{ ?[id, name, kind, type, value, file_path, code_text, start_line, end_line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(v.ID),
+			quoteString(v.Name),
+			quoteString(v.Kind),
+			quoteString(v.Type),
+			quoteString(v.Value),
+			quoteString(v.FilePath),
+			quoteString(v.CodeText),
+			fmt.Sprintf("%d", v.StartLine),
+			fmt.Sprintf("%d", v.EndLine),
+		}, ", "))
+		buf.WriteString("]] :put cie_variable { id, name, kind, type, value, file_path, code_text, start_line, end_line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildSQLMutations generates Datalog :put statements for SQL schema objects
+// and function -> table access edges.
+func (db *DatalogBuilder) BuildSQLMutations(schema SQLSchema, accesses []TableAccessEdge) string {
+	var buf strings.Builder
+
+	for _, t := range schema.Tables {
+		buf.WriteString("{ ?[id, name, file_path, start_line, end_line, code_text] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(t.ID),
+			quoteString(t.Name),
+			quoteString(t.FilePath),
+			fmt.Sprintf("%d", t.StartLine),
+			fmt.Sprintf("%d", t.EndLine),
+			quoteString(t.CodeText),
+		}, ", "))
+		buf.WriteString("]] :put cie_sql_table { id, name, file_path, start_line, end_line, code_text } }\n")
+	}
+
+	for _, c := range schema.Columns {
+		id := GenerateSQLColumnID(c.FilePath, c.TableName, c.Name)
+		buf.WriteString("{ ?[id, table_name, name, data_type, nullable, primary_key, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(id),
+			quoteString(c.TableName),
+			quoteString(c.Name),
+			quoteString(c.DataType),
+			strconv.FormatBool(c.Nullable),
+			strconv.FormatBool(c.PrimaryKey),
+			quoteString(c.FilePath),
+			fmt.Sprintf("%d", c.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_sql_column { id, table_name, name, data_type, nullable, primary_key, file_path, line } }\n")
+	}
+
+	for _, idx := range schema.Indexes {
+		id := GenerateSQLIndexID(idx.FilePath, idx.TableName, idx.Name, idx.Columns)
+		buf.WriteString("{ ?[id, name, table_name, columns, is_unique, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(id),
+			quoteString(idx.Name),
+			quoteString(idx.TableName),
+			quoteString(strings.Join(idx.Columns, ",")),
+			strconv.FormatBool(idx.Unique),
+			quoteString(idx.FilePath),
+			fmt.Sprintf("%d", idx.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_sql_index { id, name, table_name, columns, is_unique, file_path, line } }\n")
+	}
+
+	for _, fk := range schema.ForeignKeys {
+		id := GenerateSQLForeignKeyID(fk.FilePath, fk.TableName, fk.Columns, fk.RefTable)
+		buf.WriteString("{ ?[id, table_name, columns, ref_table, ref_columns, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(id),
+			quoteString(fk.TableName),
+			quoteString(strings.Join(fk.Columns, ",")),
+			quoteString(fk.RefTable),
+			quoteString(strings.Join(fk.RefColumns, ",")),
+			quoteString(fk.FilePath),
+			fmt.Sprintf("%d", fk.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_sql_foreign_key { id, table_name, columns, ref_table, ref_columns, file_path, line } }\n")
+	}
+
+	for _, a := range accesses {
+		id := GenerateTableAccessID(a.FunctionID, a.TableName, a.Access)
+		buf.WriteString("{ ?[id, function_id, table_name, access, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(id),
+			quoteString(a.FunctionID),
+			quoteString(a.TableName),
+			quoteString(a.Access),
+			quoteString(a.FilePath),
+			fmt.Sprintf("%d", a.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_table_access { id, function_id, table_name, access, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildGraphQLMutations generates Datalog mutations for GraphQL SDL fields and
+// field -> resolver edges. Edges without a resolved function are skipped.
+func (db *DatalogBuilder) BuildGraphQLMutations(fields []GraphQLFieldEntity, resolvers []GraphQLResolverEdge) string {
+	var buf strings.Builder
+
+	for _, f := range fields {
+		buf.WriteString("{ ?[id, type_name, name, field_type, arguments, operation, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(f.ID),
+			quoteString(f.TypeName),
+			quoteString(f.Name),
+			quoteString(f.FieldType),
+			quoteString(f.Arguments),
+			quoteString(f.Operation),
+			quoteString(f.FilePath),
+			fmt.Sprintf("%d", f.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_graphql_field { id, type_name, name, field_type, arguments, operation, file_path, line } }\n")
+	}
+
+	for _, r := range resolvers {
+		if r.FunctionID == "" {
+			continue
+		}
+		id := GenerateGraphQLResolverID(r.TypeName, r.FieldName, r.FunctionID)
+		buf.WriteString("{ ?[id, type_name, field_name, function_id, framework, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(id),
+			quoteString(r.TypeName),
+			quoteString(r.FieldName),
+			quoteString(r.FunctionID),
+			quoteString(r.Framework),
+			quoteString(r.FilePath),
+			fmt.Sprintf("%d", r.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_graphql_resolver { id, type_name, field_name, function_id, framework, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildTopologyMutations generates Datalog mutations for infrastructure topology
+// nodes, their env vars and edges. Edges without a resolved target are skipped.
+func (db *DatalogBuilder) BuildTopologyMutations(topology Topology) string {
+	var buf strings.Builder
+
+	for _, n := range topology.Nodes {
+		buf.WriteString("{ ?[id, kind, name, image, command, binary, ports, labels, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(n.ID),
+			quoteString(n.Kind),
+			quoteString(n.Name),
+			quoteString(n.Image),
+			quoteString(n.Command),
+			quoteString(n.Binary),
+			quoteString(n.Ports),
+			quoteString(n.Labels),
+			quoteString(n.FilePath),
+			fmt.Sprintf("%d", n.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_topology_node { id, kind, name, image, command, binary, ports, labels, file_path, line } }\n")
+	}
+
+	for _, e := range topology.Env {
+		id := GenerateTopologyEnvID(e.NodeID, e.Name, e.Value)
+		buf.WriteString("{ ?[id, node_id, name, value, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(id),
+			quoteString(e.NodeID),
+			quoteString(e.Name),
+			quoteString(e.Value),
+			quoteString(e.FilePath),
+			fmt.Sprintf("%d", e.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_topology_env { id, node_id, name, value, file_path, line } }\n")
+	}
+
+	for _, e := range topology.Edges {
+		if e.ToID == "" {
+			continue
+		}
+		id := GenerateTopologyEdgeID(e.FromID, e.ToID, e.Kind)
+		buf.WriteString("{ ?[id, from_id, to_id, kind, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(id),
+			quoteString(e.FromID),
+			quoteString(e.ToID),
+			quoteString(e.Kind),
+			quoteString(e.FilePath),
+			fmt.Sprintf("%d", e.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_topology_edge { id, from_id, to_id, kind, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildDocMutations generates Datalog mutations for Markdown doc chunks, their
+// embeddings and doc -> symbol links. Links without a resolved target are skipped.
+func (db *DatalogBuilder) BuildDocMutations(chunks []DocChunkEntity, links []DocLinkEdge) string {
+	var buf strings.Builder
+
+	for _, c := range chunks {
+		buf.WriteString("{ ?[id, file_path, heading, anchor, level, start_line, end_line, text] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(c.ID),
+			quoteString(c.FilePath),
+			quoteString(c.Heading),
+			quoteString(c.Anchor),
+			fmt.Sprintf("%d", c.Level),
+			fmt.Sprintf("%d", c.StartLine),
+			fmt.Sprintf("%d", c.EndLine),
+			quoteString(c.Text),
+		}, ", "))
+		buf.WriteString("]] :put cie_doc_chunk { id, file_path, heading, anchor, level, start_line, end_line, text } }\n")
+
+		// Skip if embedding is empty (e.g., embedding provider unavailable)
+		if len(c.Embedding) > 0 {
+			buf.WriteString("{ ?[chunk_id, embedding] <- [[")
+			buf.WriteString(strings.Join([]string{
+				quoteString(c.ID),
+				formatFloatArray(c.Embedding),
+			}, ", "))
+			buf.WriteString("]] :put cie_doc_chunk_embedding { chunk_id, embedding } }\n")
+		}
+	}
+
+	for _, l := range links {
+		if l.TargetID == "" {
+			continue
+		}
+		id := GenerateDocLinkID(l.ChunkID, l.TargetID)
+		buf.WriteString("{ ?[id, chunk_id, target_id, target_kind, symbol, file_path, line] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(id),
+			quoteString(l.ChunkID),
+			quoteString(l.TargetID),
+			quoteString(l.TargetKind),
+			quoteString(l.Symbol),
+			quoteString(l.FilePath),
+			fmt.Sprintf("%d", l.Line),
+		}, ", "))
+		buf.WriteString("]] :put cie_doc_link { id, chunk_id, target_id, target_kind, symbol, file_path, line } }\n")
+	}
+
+	return buf.String()
+}
+
+// BuildPackageMutations generates Datalog mutations replacing the package graph.
+// Packages are derived from all indexed files, so existing rows are removed
+// first rather than diffed.
+func (db *DatalogBuilder) BuildPackageMutations(packages []PackageEntity, edges []PackageImportEdge) string {
+	var buf strings.Builder
+
+	buf.WriteString("{ ?[id] := *cie_package_import{id} :rm cie_package_import {id} }\n")
+	buf.WriteString("{ ?[id] := *cie_package{id} :rm cie_package {id} }\n")
+
+	for _, pkg := range packages {
+		buf.WriteString("{ ?[id, path, name, language, file_count, doc] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(pkg.ID),
+			quoteString(pkg.Path),
+			quoteString(pkg.Name),
+			quoteString(pkg.Language),
+			fmt.Sprintf("%d", pkg.FileCount),
+			quoteString(pkg.Doc),
+		}, ", "))
+		buf.WriteString("]] :put cie_package { id, path, name, language, file_count, doc } }\n")
+	}
+
+	for _, e := range edges {
+		buf.WriteString("{ ?[id, from_path, to_path, import_count] <- [[")
+		buf.WriteString(strings.Join([]string{
+			quoteString(e.ID),
+			quoteString(e.FromPath),
+			quoteString(e.ToPath),
+			fmt.Sprintf("%d", e.ImportCount),
+		}, ", "))
+		buf.WriteString("]] :put cie_package_import { id, from_path, to_path, import_count } }\n")
+	}
+
+	return buf.String()
+}
+
 // CountMutations estimates the number of mutations in a Datalog script.
 // This is approximate but useful for batching decisions.
 func CountMutations(script string) int {
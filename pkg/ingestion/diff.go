@@ -0,0 +1,75 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// diffArgs returns the git diff arguments comparing base with head, or with
+// the working tree when head is empty: renames detected, no context lines.
+func diffArgs(base, head string) []string {
+	args := []string{"diff", "-M", "--unified=0", "--no-color", "--no-ext-diff", base}
+	if head != "" {
+		args = append(args, head)
+	}
+	return append(args, "--")
+}
+
+// DiffRefs returns the files changed between two git refs, or between base
+// and the working tree when head is empty, with their changed lines.
+func DiffRefs(ctx context.Context, git tools.GitRunner, base, head string) ([]tools.ChangedFile, error) {
+	for _, ref := range []string{base, head} {
+		if strings.HasPrefix(ref, "-") {
+			return nil, fmt.Errorf("invalid git ref %q", ref)
+		}
+	}
+	if base == "" {
+		return nil, fmt.Errorf("base ref is required")
+	}
+	out, err := git.Run(ctx, diffArgs(base, head)...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(out), nil
+}
+
+// ParseDiff returns the files changed by a unified diff, git diff output or
+// a plain diff -u, with the lines each changes in the new version of the
+// file. Context lines are not counted as changed.
+func ParseDiff(diff string) []tools.ChangedFile {
+	diff = strings.ReplaceAll(diff, "\r\n", "\n")
+	var files []tools.ChangedFile
+	for _, f := range parseUnifiedDiff(strings.Split(diff, "\n")) {
+		if f.Path == "" && f.OldPath == "" {
+			continue
+		}
+		changed := tools.ChangedFile{Path: f.Path, OldPath: f.OldPath, Binary: f.Binary}
+		for _, s := range changedSpans(f.Hunks) {
+			changed.Lines = append(changed.Lines, tools.ChangedLines{Start: s.Start, End: s.End, Deletion: s.deletion})
+		}
+		files = append(files, changed)
+	}
+	return files
+}
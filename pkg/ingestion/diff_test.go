@@ -0,0 +1,86 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+func TestParseDiff(t *testing.T) {
+	diff := "diff --git a/store.go b/store.go\r\n" +
+		"index 1111111..2222222 100644\r\n" +
+		"--- a/store.go\r\n" +
+		"+++ b/store.go\r\n" +
+		"@@ -10,7 +10,7 @@ func (s *Store) Get(id string) {\r\n" +
+		" \tctx := context.Background()\r\n" +
+		"-\tv := s.m[id]\r\n" +
+		"+\tv, ok := s.m[id]\r\n" +
+		"+\tif !ok {\r\n" +
+		" \t\treturn\r\n" +
+		" \t}\r\n" +
+		"-\ts.hits++\r\n" +
+		" \treturn v\r\n" +
+		" }\r\n" +
+		"diff --git a/legacy.go b/legacy.go\r\n" +
+		"deleted file mode 100644\r\n" +
+		"--- a/legacy.go\r\n" +
+		"+++ /dev/null\r\n" +
+		"@@ -1,2 +0,0 @@\r\n" +
+		"-package store\r\n" +
+		"-var legacy = 1\r\n"
+
+	files := ParseDiff(diff)
+	require.Len(t, files, 2)
+	assert.Equal(t, tools.ChangedFile{
+		Path: "store.go", OldPath: "store.go",
+		Lines: []tools.ChangedLines{{Start: 11, End: 12}, {Start: 14, End: 15, Deletion: true}},
+	}, files[0])
+	assert.Equal(t, tools.ChangedFile{OldPath: "legacy.go"}, files[1])
+}
+
+// diffGitRunner returns a fixed diff and records the arguments it ran with.
+type diffGitRunner struct {
+	args [][]string
+}
+
+func (g *diffGitRunner) Run(_ context.Context, args ...string) (string, error) {
+	g.args = append(g.args, args)
+	return "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -3 +3 @@\n-x\n+y\n", nil
+}
+
+func (g *diffGitRunner) RepoPath() string { return "/repo" }
+
+func TestDiffRefs(t *testing.T) {
+	git := &diffGitRunner{}
+	files, err := DiffRefs(context.Background(), git, "main", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, []tools.ChangedFile{{Path: "a.go", OldPath: "a.go", Lines: []tools.ChangedLines{{Start: 3, End: 3}}}}, files)
+	assert.Equal(t, [][]string{{"diff", "-M", "--unified=0", "--no-color", "--no-ext-diff", "main", "HEAD", "--"}}, git.args)
+
+	_, err = DiffRefs(context.Background(), git, "--output=/tmp/x", "")
+	assert.Error(t, err)
+	assert.Len(t, git.args, 1)
+}
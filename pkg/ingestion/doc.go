@@ -43,12 +43,19 @@
 //   - Python (.py)
 //   - TypeScript (.ts, .tsx)
 //   - JavaScript (.js, .jsx)
+//   - PHP (.php) - namespaces, classes, interfaces, traits, `use` imports
+//   - Shell (.sh, .bash, .zsh) - functions, `source` imports, script and cmd/* binary invocations
+//   - SQL (.sql) - tables, columns, indexes, foreign keys (statement-based, no tree-sitter)
+//   - GraphQL (.graphql, .graphqls) - types, fields, root operations, gqlgen/Apollo resolver links
+//   - Dockerfile, compose and Kubernetes YAML, Terraform (.tf) - deployment topology, env vars, cmd/* entrypoints
+//   - Markdown (.md, .markdown) - sections by heading, links to the functions, types and files they mention
 //
 // Additionally, Protocol Buffers (.proto) are supported via regex parsing.
 //
 // Each language parser extracts:
 //   - Functions/methods with signatures and bodies
 //   - Types, interfaces, classes, and structs
+//   - Package-level variables and constants
 //   - Function call relationships
 //   - File and package metadata
 //
@@ -0,0 +1,227 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// DOC COMMENT EXTRACTION
+// =============================================================================
+
+// maxDocCommentLen caps a stored doc comment; long docblocks are truncated.
+const maxDocCommentLen = 2000
+
+// goDirectivePattern matches Go directive comments (//go:generate, //nolint:errcheck),
+// which are not part of the documentation.
+var goDirectivePattern = regexp.MustCompile(`^//(line |extern |export |[a-z0-9]+:[a-z0-9])`)
+
+// jsDocWrappers are the nodes a JSDoc block may precede instead of the
+// function or class itself: `/** ... */ export const f = () => {}`.
+var jsDocWrappers = map[string]bool{
+	"variable_declarator":  true,
+	"lexical_declaration":  true,
+	"variable_declaration": true,
+	"export_statement":     true,
+	"ambient_declaration":  true,
+}
+
+// leadingComments returns the comment nodes directly above node, in source
+// order. A blank line ends the block, and a comment trailing code on the
+// previous line (`x := 1 // note`) is not part of it.
+func leadingComments(node *sitter.Node) []*sitter.Node {
+	var comments []*sitter.Node
+	next := node
+	for prev := node.PrevSibling(); prev != nil && prev.Type() == "comment"; prev = prev.PrevSibling() {
+		if next.StartPoint().Row > lastRow(prev)+1 {
+			break
+		}
+		if before := prev.PrevSibling(); before != nil && lastRow(before) == prev.StartPoint().Row {
+			break
+		}
+		comments = append([]*sitter.Node{prev}, comments...)
+		next = prev
+	}
+	return comments
+}
+
+// lastRow returns the last row holding text of node. Line comments may
+// include their newline and end at column 0 of the next row.
+func lastRow(node *sitter.Node) uint32 {
+	end := node.EndPoint()
+	if end.Column == 0 && end.Row > node.StartPoint().Row {
+		return end.Row - 1
+	}
+	return end.Row
+}
+
+// goDocComment returns the doc comment of a Go declaration: the comment block
+// directly above it, without directives. For a type_spec outside a grouped
+// declaration (`type Foo struct{}`) the comment sits above the type keyword.
+func goDocComment(node *sitter.Node, content []byte) string {
+	if prev := node.PrevSibling(); node.Type() == "type_spec" && prev != nil && prev.Type() == "type" {
+		node = node.Parent()
+	}
+	var lines []string
+	for _, c := range leadingComments(node) {
+		text := strings.TrimRight(nodeText(c, content), "\r\n")
+		if goDirectivePattern.MatchString(text) {
+			continue
+		}
+		lines = append(lines, text)
+	}
+	return cleanDocComment(strings.Join(lines, "\n"))
+}
+
+// jsDocComment returns the JSDoc (`/** ... */`) block documenting a JavaScript
+// or TypeScript function, method or class. Plain `//` and `/* */` comments are
+// not documentation and are ignored.
+func jsDocComment(node *sitter.Node, content []byte) string {
+	return docBlockComment(node, content, jsDocWrappers)
+}
+
+// phpDocComment returns the PHPDoc (`/** ... */`) block documenting a PHP
+// function, method or type.
+func phpDocComment(node *sitter.Node, content []byte) string {
+	return docBlockComment(node, content, nil)
+}
+
+// docBlockComment returns the last `/** ... */` comment directly above node,
+// or above the wrapper nodes enclosing it.
+func docBlockComment(node *sitter.Node, content []byte, wrappers map[string]bool) string {
+	for n := node; n != nil; n = n.Parent() {
+		if comments := leadingComments(n); len(comments) > 0 {
+			if text := nodeText(comments[len(comments)-1], content); strings.HasPrefix(text, "/**") {
+				return cleanDocComment(text)
+			}
+		}
+		if parent := n.Parent(); parent == nil || !wrappers[parent.Type()] {
+			break
+		}
+	}
+	return ""
+}
+
+// pythonDocstring returns the docstring of a function_definition or
+// class_definition: a string literal as the first statement of its body.
+func pythonDocstring(node *sitter.Node, content []byte) string {
+	body := node.ChildByFieldName("body")
+	if body == nil || body.NamedChildCount() == 0 {
+		return ""
+	}
+	stmt := body.NamedChild(0)
+	if stmt.Type() != "expression_statement" || stmt.NamedChildCount() == 0 {
+		return ""
+	}
+	str := stmt.NamedChild(0)
+	if str.Type() != "string" {
+		return ""
+	}
+
+	text := strings.TrimLeft(nodeText(str, content), "rRuUbBfF")
+	for _, quote := range []string{`"""`, `'''`, `"`, `'`} {
+		if len(text) >= 2*len(quote) && strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) {
+			text = text[len(quote) : len(text)-len(quote)]
+			break
+		}
+	}
+	return truncateDocComment(dedentDocLines(strings.Split(text, "\n")))
+}
+
+// cleanDocComment strips comment markers from a comment block — `//`, `/*`,
+// `/**`, `*/` and the leading `*` of docblock lines — and removes the common
+// indentation and surrounding blank lines.
+func cleanDocComment(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(trimmed, "//") {
+			lines = append(lines, strings.TrimPrefix(strings.TrimPrefix(trimmed, "//"), " "))
+			continue
+		}
+		if strings.HasPrefix(trimmed, "/*") {
+			trimmed = strings.TrimLeft(trimmed[1:], "*")
+		} else if strings.HasPrefix(trimmed, "*") && !strings.HasPrefix(trimmed, "*/") {
+			trimmed = strings.TrimPrefix(strings.TrimPrefix(trimmed, "*"), " ")
+		} else {
+			trimmed = line // Keep indentation of undecorated block comment lines
+		}
+		if strings.HasSuffix(trimmed, "*/") {
+			trimmed = strings.TrimRight(strings.TrimSuffix(trimmed, "*/"), " *")
+		}
+		lines = append(lines, trimmed)
+	}
+	return truncateDocComment(dedentDocLines(lines))
+}
+
+// dedentDocLines removes the indentation shared by all non-blank lines after
+// the first (whose indentation is always dropped, like Python's
+// inspect.cleandoc) and the leading and trailing blank lines.
+func dedentDocLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	lines[0] = strings.TrimLeft(lines[0], " \t")
+
+	indent := -1
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i := 1; i < len(lines); i++ {
+		if len(lines[i]) >= indent && indent > 0 {
+			lines[i] = lines[i][indent:]
+		}
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncateDocComment caps a doc comment at maxDocCommentLen bytes on a rune boundary.
+func truncateDocComment(doc string) string {
+	if len(doc) <= maxDocCommentLen {
+		return doc
+	}
+	cut := maxDocCommentLen
+	for cut > 0 && !utf8.RuneStart(doc[cut]) {
+		cut--
+	}
+	return doc[:cut] + "..."
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseDocSource writes content to a temp file and parses it with tree-sitter.
+func parseDocSource(t *testing.T, name, language, content string) *ParseResult {
+	t.Helper()
+
+	tmpFile := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(tmpFile, []byte(content), 0600))
+
+	result, err := NewTreeSitterParser(nil).ParseFile(FileInfo{
+		Path:     name,
+		FullPath: tmpFile,
+		Size:     int64(len(content)),
+		Language: language,
+	})
+	require.NoError(t, err)
+	return result
+}
+
+// docsByName maps function and type names to their doc comments.
+func docsByName(result *ParseResult) map[string]string {
+	docs := make(map[string]string)
+	for _, fn := range result.Functions {
+		docs[fn.Name] = fn.DocComment
+	}
+	for _, typ := range result.Types {
+		docs["type "+typ.Name] = typ.DocComment
+	}
+	return docs
+}
+
+func TestDocComments_Go(t *testing.T) {
+	result := parseDocSource(t, "server.go", "go", `package server
+
+// Server serves the index over HTTP.
+//
+// It is safe for concurrent use.
+type Server struct{}
+
+type (
+	// Handler handles one request.
+	Handler func()
+
+	Option func(*Server)
+)
+
+// NewServer creates a Server.
+//
+//go:noinline
+func NewServer() *Server { return &Server{} }
+
+// Start binds the listener.
+/* Block comments are kept too. */
+func (s *Server) Start() error {
+	run := func() {}
+	run()
+	return nil
+}
+
+// Unrelated comment separated by a blank line.
+
+func Stop() {}
+
+var x = 1 // trailing comment
+func Close() {}
+`)
+	docs := docsByName(result)
+
+	assert.Equal(t, "Server serves the index over HTTP.\n\nIt is safe for concurrent use.", docs["type Server"])
+	assert.Equal(t, "Handler handles one request.", docs["type Handler"])
+	assert.Empty(t, docs["type Option"])
+	assert.Equal(t, "NewServer creates a Server.", docs["NewServer"], "directives are dropped")
+	assert.Equal(t, "Start binds the listener.\nBlock comments are kept too.", docs["Server.Start"])
+	assert.Empty(t, docs["Stop"], "a blank line ends the doc comment")
+	assert.Empty(t, docs["Close"], "trailing comments of the previous line are not docs")
+	for name, doc := range docs {
+		if strings.HasPrefix(name, "$anon_") {
+			assert.Empty(t, doc, "closures have no doc comment")
+		}
+	}
+}
+
+func TestDocComments_Python(t *testing.T) {
+	result := parseDocSource(t, "service.py", "python", `class UserService:
+    """Manages users.
+
+    Backed by the users table.
+    """
+
+    def get(self, user_id):
+        r'''Return the user with the given ID.'''
+        return None
+
+    def delete(self, user_id):
+        # Not a docstring
+        return None
+`)
+	docs := docsByName(result)
+
+	assert.Equal(t, "Manages users.\n\nBacked by the users table.", docs["type UserService"])
+	assert.Equal(t, "Return the user with the given ID.", docs["UserService.get"])
+	assert.Empty(t, docs["UserService.delete"])
+}
+
+func TestDocComments_JavaScript(t *testing.T) {
+	result := parseDocSource(t, "api.js", "javascript", `/**
+ * Fetches a user.
+ * @param {string} id
+ */
+export function fetchUser(id) {}
+
+/** Formats a name. */
+export const formatName = (user) => user.name;
+
+// Plain comments are not JSDoc.
+function helper() {}
+
+/**
+ * Caches responses.
+ */
+class Cache {
+  /** Returns the cached value. */
+  get(key) {}
+}
+`)
+	docs := docsByName(result)
+
+	assert.Equal(t, "Fetches a user.\n@param {string} id", docs["fetchUser"])
+	assert.Equal(t, "Formats a name.", docs["formatName"])
+	assert.Empty(t, docs["helper"])
+	assert.Equal(t, "Caches responses.", docs["type Cache"])
+	assert.Equal(t, "Returns the cached value.", docs["get"])
+}
+
+func TestDocComments_TypeScript(t *testing.T) {
+	result := parseDocSource(t, "types.ts", "typescript", `/** A registered user. */
+export interface User {
+  /** Display name. */
+  displayName(): string;
+}
+
+/** User identifier. */
+type UserID = string;
+`)
+	docs := docsByName(result)
+
+	assert.Equal(t, "A registered user.", docs["type User"])
+	assert.Equal(t, "Display name.", docs["displayName"])
+	assert.Equal(t, "User identifier.", docs["type UserID"])
+}
+
+func TestDocComments_PHP(t *testing.T) {
+	result := parseDocSource(t, "UserController.php", "php", `<?php
+
+/**
+ * Handles user requests.
+ */
+class UserController
+{
+    /**
+     * Shows a user.
+     *
+     * @param int $id
+     */
+    public function show(int $id) {}
+
+    // Not a docblock
+    public function index() {}
+}
+`)
+	docs := docsByName(result)
+
+	assert.Equal(t, "Handles user requests.", docs["type UserController"])
+	assert.Equal(t, "Shows a user.\n\n@param int $id", docs["UserController.show"])
+	assert.Empty(t, docs["UserController.index"])
+}
+
+func TestTruncateDocComment(t *testing.T) {
+	long := strings.Repeat("é", maxDocCommentLen)
+	got := truncateDocComment(long)
+	assert.True(t, strings.HasSuffix(got, "..."))
+	assert.LessOrEqual(t, len(got), maxDocCommentLen+3)
+	assert.True(t, strings.HasPrefix(long, strings.TrimSuffix(got, "...")), "truncated on a rune boundary")
+}
+
+func TestEmbeddingInput(t *testing.T) {
+	assert.Equal(t, "func Stop() {}", embeddingInput("", "func Stop() {}"))
+	assert.Equal(t, "Stop halts the server.\nfunc Stop() {}", embeddingInput("Stop halts the server.", "func Stop() {}"))
+
+	// Python docstrings are already part of the code
+	code := "def get(self):\n    \"\"\"Return the user.\"\"\"\n    return None"
+	assert.Equal(t, code, embeddingInput("Return the user.", code))
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"path"
+	"strings"
+)
+
+// =============================================================================
+// DOC MENTION LINKING
+// =============================================================================
+
+// maxDocLinkTargets is the number of symbols a mention may resolve to before
+// it is considered too ambiguous to link (`main`, `New`, `Close`).
+const maxDocLinkTargets = 3
+
+// docTarget is a function or type a mention can resolve to.
+type docTarget struct {
+	id   string
+	kind string
+	dir  string // Directory of the declaring file
+}
+
+// LinkDocMentions resolves the mentions found in Markdown chunks and returns
+// one edge per chunk and target:
+//   - file mentions: exact repository path, then relative to the doc's
+//     directory, then a unique path suffix ("parser.go" → "pkg/ingestion/parser.go")
+//   - symbol mentions: functions and types by exact name ("Parser.ParseFile"),
+//     then package-qualified ("ingestion.NewParser" → NewParser in a directory
+//     named ingestion), then bare method names ("ParseFile" → "Parser.ParseFile")
+//
+// Mentions matching nothing, or more than maxDocLinkTargets symbols, are dropped.
+func LinkDocMentions(links []DocLinkEdge, files []FileEntity, functions []FunctionEntity, types []TypeEntity) []DocLinkEdge {
+	if len(links) == 0 {
+		return nil
+	}
+
+	filesByPath := make(map[string]string, len(files)) // path -> file ID
+	for _, f := range files {
+		filesByPath[f.Path] = f.ID
+	}
+
+	byName := make(map[string][]docTarget)
+	byMethod := make(map[string][]docTarget) // method name without receiver -> functions
+	for _, fn := range functions {
+		if fn.FilePath == "<external>" {
+			continue // Stubs for external type methods have no code to document
+		}
+		t := docTarget{id: fn.ID, kind: DocTargetFunction, dir: path.Dir(fn.FilePath)}
+		byName[fn.Name] = append(byName[fn.Name], t)
+		if dot := strings.LastIndex(fn.Name, "."); dot >= 0 {
+			byMethod[fn.Name[dot+1:]] = append(byMethod[fn.Name[dot+1:]], t)
+		}
+	}
+	for _, typ := range types {
+		byName[typ.Name] = append(byName[typ.Name], docTarget{id: typ.ID, kind: DocTargetType, dir: path.Dir(typ.FilePath)})
+	}
+
+	var edges []DocLinkEdge
+	seen := make(map[string]bool)
+	add := func(link DocLinkEdge, id, kind string) {
+		key := link.ChunkID + "|" + id
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		link.TargetID = id
+		link.TargetKind = kind
+		edges = append(edges, link)
+	}
+
+	for _, link := range links {
+		if link.TargetKind == DocTargetFile {
+			if id := resolveDocFile(link.Symbol, link.FilePath, filesByPath); id != "" {
+				add(link, id, DocTargetFile)
+			}
+			continue
+		}
+		targets := resolveDocSymbol(link.Symbol, byName, byMethod)
+		if len(targets) > maxDocLinkTargets {
+			continue
+		}
+		for _, t := range targets {
+			add(link, t.id, t.kind)
+		}
+	}
+
+	return edges
+}
+
+// resolveDocFile returns the ID of the file a path mention refers to.
+func resolveDocFile(mention, docPath string, filesByPath map[string]string) string {
+	if id, ok := filesByPath[mention]; ok {
+		return id
+	}
+	if id, ok := filesByPath[path.Join(path.Dir(docPath), mention)]; ok {
+		return id
+	}
+	var match string
+	for p, id := range filesByPath {
+		if strings.HasSuffix(p, "/"+mention) {
+			if match != "" {
+				return ""
+			}
+			match = id
+		}
+	}
+	return match
+}
+
+// resolveDocSymbol returns the functions and types a symbol mention may refer to.
+func resolveDocSymbol(symbol string, byName, byMethod map[string][]docTarget) []docTarget {
+	if targets := byName[symbol]; len(targets) > 0 {
+		return targets
+	}
+
+	// Package-qualified: ingestion.NewParser, ingestion.Parser.ParseFile
+	if pkg, name, ok := strings.Cut(symbol, "."); ok {
+		var targets []docTarget
+		for _, t := range byName[name] {
+			if path.Base(t.dir) == pkg {
+				targets = append(targets, t)
+			}
+		}
+		if len(targets) > 0 {
+			return targets
+		}
+	}
+
+	if !strings.Contains(symbol, ".") {
+		return byMethod[symbol]
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package ingestion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLinkDocMentions tests file, qualified, package-qualified and bare method
+// resolution, and that ambiguous mentions are dropped.
+func TestLinkDocMentions(t *testing.T) {
+	files := []FileEntity{
+		{ID: "file:main", Path: "cmd/cie/main.go"},
+		{ID: "file:datalog", Path: "pkg/ingestion/datalog.go"},
+		{ID: "file:readme", Path: "README.md"},
+	}
+	functions := []FunctionEntity{
+		{ID: "fn:parse", Name: "Parser.ParseFile", FilePath: "pkg/ingestion/parser.go"},
+		{ID: "fn:ts_parse", Name: "TreeSitterParser.ParseFile", FilePath: "pkg/ingestion/parser_treesitter.go"},
+		{ID: "fn:new_builder", Name: "NewDatalogBuilder", FilePath: "pkg/ingestion/datalog.go"},
+		{ID: "fn:new_builder_mock", Name: "NewDatalogBuilder", FilePath: "pkg/mocks/datalog.go"},
+		{ID: "fn:close1", Name: "A.Close", FilePath: "a.go"},
+		{ID: "fn:close2", Name: "B.Close", FilePath: "b.go"},
+		{ID: "fn:close3", Name: "C.Close", FilePath: "c.go"},
+		{ID: "fn:close4", Name: "D.Close", FilePath: "d.go"},
+		{ID: "stub", Name: "sql.DB.Query", FilePath: "<external>"},
+	}
+	types := []TypeEntity{
+		{ID: "type:builder", Name: "DatalogBuilder", FilePath: "pkg/ingestion/datalog.go"},
+	}
+
+	mention := func(chunk, kind, symbol string) DocLinkEdge {
+		return DocLinkEdge{ChunkID: chunk, TargetKind: kind, Symbol: symbol, FilePath: "docs/architecture.md"}
+	}
+	links := []DocLinkEdge{
+		mention("c1", DocTargetFile, "cmd/cie/main.go"),
+		mention("c1", DocTargetFile, "README.md"),
+		mention("c1", DocTargetFile, "datalog.go"),
+		mention("c1", DocTargetFile, "missing.go"),
+		mention("c2", "", "Parser.ParseFile"),
+		mention("c2", "", "ingestion.NewDatalogBuilder"),
+		mention("c2", "", "DatalogBuilder"),
+		mention("c3", "", "ParseFile"),
+		mention("c3", "", "Close"),
+		mention("c3", "", "sql.DB.Query"),
+		mention("c3", "", "Parser.ParseFile"),
+	}
+
+	var got []string
+	for _, e := range LinkDocMentions(links, files, functions, types) {
+		got = append(got, e.ChunkID+" "+e.Symbol+" -> "+e.TargetKind+":"+e.TargetID)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"c1 cmd/cie/main.go -> file:file:main",
+		"c1 README.md -> file:file:readme",
+		"c1 datalog.go -> file:file:datalog",
+		"c2 Parser.ParseFile -> function:fn:parse",
+		"c2 ingestion.NewDatalogBuilder -> function:fn:new_builder",
+		"c2 DatalogBuilder -> type:type:builder",
+		"c3 ParseFile -> function:fn:parse",
+		"c3 ParseFile -> function:fn:ts_parse",
+	}, got, "Ambiguous (Close), external and unknown mentions are dropped; one edge per chunk and target")
+}
@@ -491,7 +491,7 @@ func (eg *EmbeddingGenerator) embedTypesParallel(ctx context.Context, types []Ty
 
 // embedType embeds a single type with retry logic.
 func (eg *EmbeddingGenerator) embedType(ctx context.Context, t TypeEntity) ([]float32, bool, error) {
-	text := t.CodeText
+	text := embeddingInput(t.DocComment, t.CodeText)
 	maxChars := 2000
 	wasTruncated := false
 	if len(text) > maxChars {
@@ -537,13 +537,129 @@ func (eg *EmbeddingGenerator) embedType(ctx context.Context, t TypeEntity) ([]fl
 	return embedding, wasTruncated, err
 }
 
+// EmbedDocChunksResult contains the results of embedding generation for doc chunks.
+type EmbedDocChunksResult struct {
+	// Chunks contains the doc chunks with populated embedding vectors.
+	// Chunks that failed embedding generation will have empty embedding vectors.
+	Chunks []DocChunkEntity
+
+	// ErrorCount is the number of chunks that failed embedding generation.
+	ErrorCount int
+}
+
+// EmbedDocChunks generates embeddings for Markdown doc chunks.
+// The text embedded is the chunk's file path and heading followed by its body,
+// so that a query can match the section a paragraph belongs to.
+// Returns chunks with embeddings (or empty embeddings on error) and error count.
+func (eg *EmbeddingGenerator) EmbedDocChunks(ctx context.Context, chunks []DocChunkEntity) (*EmbedDocChunksResult, error) {
+	results := make([]DocChunkEntity, len(chunks))
+	var errorCount int32
+	var progressCount int64
+	totalChunks := int64(len(chunks))
+
+	workers := eg.workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int, len(chunks))
+	for i := range chunks {
+		jobs <- i
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				c := chunks[i]
+				embedding, err := eg.embedDocChunk(ctx, c)
+				if err != nil {
+					atomic.AddInt32(&errorCount, 1)
+				}
+				c.Embedding = embedding
+				results[i] = c
+				current := atomic.AddInt64(&progressCount, 1)
+				eg.reportProgress(current, totalChunks, "embedding_docs")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if errorCount > 0 {
+		eg.logger.Info("embedding.docs.summary",
+			"total_chunks", len(chunks),
+			"errors", errorCount,
+			"workers", workers,
+		)
+	}
+
+	return &EmbedDocChunksResult{
+		Chunks:     results,
+		ErrorCount: int(errorCount),
+	}, nil
+}
+
+// embedDocChunk embeds a single doc chunk with retry logic.
+func (eg *EmbeddingGenerator) embedDocChunk(ctx context.Context, c DocChunkEntity) ([]float32, error) {
+	text := c.FilePath
+	if c.Heading != "" {
+		text += " > " + c.Heading
+	}
+	text += "\n\n" + c.Text
+	if maxChars := 2000; len(text) > maxChars {
+		text = text[:maxChars]
+	}
+
+	var embedding []float32
+	var err error
+	for attempt := 0; attempt < eg.retry.MaxRetries; attempt++ {
+		embedding, err = eg.provider.Embed(ctx, text)
+		if err == nil {
+			break
+		}
+		if !isRetryableEmbeddingError(err) || attempt == eg.retry.MaxRetries-1 {
+			break
+		}
+		sleep := computeBackoffWithJitter(eg.retry.InitialBackoff, attempt, eg.retry.Multiplier, eg.retry.MaxBackoff)
+		recordEmbedRetry()
+		eg.logger.Warn("embedding.doc.retry", "chunk_id", c.ID, "attempt", attempt+1, "sleep_ms", sleep.Milliseconds(), "err", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(sleep):
+		}
+	}
+
+	if err != nil {
+		eg.logger.Error("embedding.doc.failed",
+			"chunk_id", c.ID,
+			"file_path", c.FilePath,
+			"heading", c.Heading,
+			"error", err,
+		)
+		embedding = []float32{}
+	}
+
+	return embedding, err
+}
+
 // embedFunction embeds a single function with retry logic.
 // Returns embedding, wasTruncated flag, and error.
 func (eg *EmbeddingGenerator) embedFunction(ctx context.Context, fn FunctionEntity) ([]float32, bool, error) {
 	// Truncate code text if too long (embedding models have token limits)
 	// nomic-embed-text has ~8192 token limit, but code tokenizes poorly
 	// (special chars, operators = multiple tokens). Using 2000 chars as safe limit.
-	text := fn.CodeText
+	text := embeddingInput(fn.DocComment, fn.CodeText)
 	maxChars := 2000 // Conservative limit for code (may be ~3000-4000 tokens)
 	wasTruncated := false
 	if len(text) > maxChars {
@@ -593,6 +709,20 @@ func (eg *EmbeddingGenerator) embedFunction(ctx context.Context, fn FunctionEnti
 	return embedding, wasTruncated, err
 }
 
+// embeddingInput returns the text embedded for a function or type: its doc
+// comment followed by its code. Docs already part of the code (Python
+// docstrings) are not repeated.
+func embeddingInput(docComment, codeText string) string {
+	if docComment == "" {
+		return codeText
+	}
+	firstLine, _, _ := strings.Cut(docComment, "\n")
+	if strings.Contains(codeText, firstLine) {
+		return codeText
+	}
+	return docComment + "\n" + codeText
+}
+
 // isRetryableEmbeddingError classifies provider errors: network/timeout and HTTP 5xx/429 are retryable.
 func isRetryableEmbeddingError(err error) bool {
 	if err == nil {
@@ -667,6 +797,10 @@ func randInt63n(n int64) int64 {
 	return randSeed % n
 }
 
+// mockEmbeddingDimensions is the vector size of the mock provider, a common
+// embedding dimension.
+const mockEmbeddingDimensions = 384
+
 // CreateEmbeddingProvider creates an embedding provider based on config.
 // Supported providers:
 //   - "mock": Deterministic mock embeddings for testing (384 dimensions)
@@ -676,7 +810,7 @@ func randInt63n(n int64) int64 {
 func CreateEmbeddingProvider(providerType string, logger *slog.Logger) (EmbeddingProvider, error) {
 	switch providerType {
 	case "mock":
-		return NewMockEmbeddingProvider(384, logger), nil // 384 is a common embedding dimension
+		return NewMockEmbeddingProvider(mockEmbeddingDimensions, logger), nil
 
 	case "nomic":
 		apiKey := os.Getenv("NOMIC_API_KEY")
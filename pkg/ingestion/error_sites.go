@@ -0,0 +1,434 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// ERROR SITES
+// =============================================================================
+
+// errorSiteCollector records the error sites of one file and attributes each
+// to its innermost enclosing function.
+type errorSiteCollector struct {
+	content   []byte
+	filePath  string
+	functions []FunctionEntity
+	seen      map[string]bool
+	sites     []ErrorSiteEntity
+}
+
+func newErrorSiteCollector(content []byte, filePath string, functions []FunctionEntity) *errorSiteCollector {
+	return &errorSiteCollector{
+		content:   content,
+		filePath:  filePath,
+		functions: functions,
+		seen:      make(map[string]bool),
+	}
+}
+
+// walk calls visit for node and all its descendants.
+func (c *errorSiteCollector) walk(node *sitter.Node, visit func(node *sitter.Node)) {
+	if node == nil {
+		return
+	}
+	visit(node)
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		c.walk(node.NamedChild(i), visit)
+	}
+}
+
+// functionAt returns the ID of the innermost function containing node, or ""
+// at file level.
+func (c *errorSiteCollector) functionAt(node *sitter.Node) string {
+	return enclosingFunctionID(c.functions, node)
+}
+
+// enclosingFunctionID returns the ID of the innermost function of functions
+// containing the start of node, or "" if none does.
+func enclosingFunctionID(functions []FunctionEntity, node *sitter.Node) string {
+	line, col := int(node.StartPoint().Row)+1, int(node.StartPoint().Column)+1
+	var best *FunctionEntity
+	for i := range functions {
+		fn := &functions[i]
+		if !positionWithin(line, col, fn.StartLine, fn.StartCol, fn.EndLine, fn.EndCol) {
+			continue
+		}
+		if best == nil || fn.StartLine > best.StartLine || (fn.StartLine == best.StartLine && fn.StartCol > best.StartCol) {
+			best = fn
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.ID
+}
+
+// positionWithin reports whether line:col lies within the given range.
+func positionWithin(line, col, startLine, startCol, endLine, endCol int) bool {
+	if line < startLine || line > endLine {
+		return false
+	}
+	if line == startLine && col < startCol {
+		return false
+	}
+	return line != endLine || col <= endCol
+}
+
+// add records a site once per function, kind, name and line.
+func (c *errorSiteCollector) add(node *sitter.Node, functionID, kind, name, message string) {
+	line := int(node.StartPoint().Row) + 1
+	key := fmt.Sprintf("%s|%s|%s|%d", functionID, kind, name, line)
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.sites = append(c.sites, ErrorSiteEntity{
+		ID:         GenerateErrorSiteID(c.filePath, functionID, kind, name, line),
+		FunctionID: functionID,
+		Kind:       kind,
+		Name:       name,
+		Message:    message,
+		FilePath:   c.filePath,
+		Line:       line,
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Go
+// -----------------------------------------------------------------------------
+
+// extractGoErrorSites extracts the error sites of a Go file:
+//   - new: errors.New("msg")
+//   - errorf: fmt.Errorf("msg %s", x)
+//   - wrap: fmt.Errorf("msg: %w", err), errors.Wrap(err, "msg"); the name is
+//     the wrapped sentinel, if any
+//   - sentinel: var ErrNotFound = errors.New("not found") at package level
+//   - return: return ErrNotFound, return store.ErrNotFound
+//   - custom: &NotFoundError{...}, for types named *Error
+//   - type: func (e *NotFoundError) Error() string, declaring an error type
+func extractGoErrorSites(root *sitter.Node, content []byte, filePath string, functions []FunctionEntity) []ErrorSiteEntity {
+	c := newErrorSiteCollector(content, filePath, functions)
+	c.walk(root, func(node *sitter.Node) {
+		switch node.Type() {
+		case "call_expression":
+			c.goErrorCall(node)
+		case "return_statement":
+			if node.NamedChildCount() == 0 {
+				return
+			}
+			results := node.NamedChild(0)
+			if results.Type() != "expression_list" {
+				c.goReturnedSentinel(results)
+				return
+			}
+			for i := 0; i < int(results.NamedChildCount()); i++ {
+				c.goReturnedSentinel(results.NamedChild(i))
+			}
+		case "composite_literal":
+			typeNode := node.ChildByFieldName("type")
+			if typeNode == nil {
+				return
+			}
+			typeName := nodeText(typeNode, content)
+			if functionID := c.functionAt(node); functionID != "" && strings.HasSuffix(goLastSegment(typeName), "Error") {
+				c.add(node, functionID, "custom", typeName, c.firstGoString(node.ChildByFieldName("body")))
+			}
+		case "method_declaration":
+			c.goErrorMethod(node)
+		}
+	})
+	return c.sites
+}
+
+// goErrorCall records errors.New, fmt.Errorf and errors.Wrap(f) calls. At
+// package level they declare the sentinel they are assigned to.
+func (c *errorSiteCollector) goErrorCall(call *sitter.Node) {
+	fn, args := call.ChildByFieldName("function"), call.ChildByFieldName("arguments")
+	if fn == nil || args == nil {
+		return
+	}
+
+	var kind, name, message string
+	switch nodeText(fn, c.content) {
+	case "errors.New":
+		kind, message = "new", c.goStringArg(args, 0)
+	case "fmt.Errorf":
+		kind, message = "errorf", c.goStringArg(args, 0)
+		if verb := goWrapVerbIndex(message); verb >= 0 {
+			kind, name = "wrap", c.goSentinelArg(args, verb+1)
+		}
+	case "errors.Wrap", "errors.Wrapf":
+		kind, name, message = "wrap", c.goSentinelArg(args, 0), c.goStringArg(args, 1)
+	default:
+		return
+	}
+
+	functionID := c.functionAt(call)
+	if functionID == "" {
+		if name = goAssignedVarName(call, c.content); name == "" {
+			return
+		}
+		kind = "sentinel"
+	}
+	c.add(call, functionID, kind, name, message)
+}
+
+// goReturnedSentinel records a returned sentinel error.
+func (c *errorSiteCollector) goReturnedSentinel(node *sitter.Node) {
+	if !isGoSentinelRef(node, c.content) {
+		return
+	}
+	if functionID := c.functionAt(node); functionID != "" {
+		c.add(node, functionID, "return", nodeText(node, c.content), "")
+	}
+}
+
+// goErrorMethod records the Error() string method of an error type, with the
+// first string of its body as message.
+func (c *errorSiteCollector) goErrorMethod(node *sitter.Node) {
+	name, receiver := node.ChildByFieldName("name"), node.ChildByFieldName("receiver")
+	result, params := node.ChildByFieldName("result"), node.ChildByFieldName("parameters")
+	if name == nil || nodeText(name, c.content) != "Error" || receiver == nil || receiver.NamedChildCount() == 0 ||
+		result == nil || nodeText(result, c.content) != "string" || (params != nil && params.NamedChildCount() > 0) {
+		return
+	}
+	typeName := goValueTypeName(receiver.NamedChild(0).ChildByFieldName("type"), c.content)
+	if functionID := c.functionAt(node); functionID != "" && typeName != "" {
+		c.add(node, functionID, "type", typeName, c.firstGoString(node.ChildByFieldName("body")))
+	}
+}
+
+// goStringArg returns the i-th argument if it is a string literal.
+func (c *errorSiteCollector) goStringArg(args *sitter.Node, i int) string {
+	if i >= int(args.NamedChildCount()) {
+		return ""
+	}
+	s, _ := goStringLiteral(args.NamedChild(i), c.content)
+	return s
+}
+
+// goSentinelArg returns the i-th argument if it names a sentinel error.
+func (c *errorSiteCollector) goSentinelArg(args *sitter.Node, i int) string {
+	if i >= int(args.NamedChildCount()) || !isGoSentinelRef(args.NamedChild(i), c.content) {
+		return ""
+	}
+	return nodeText(args.NamedChild(i), c.content)
+}
+
+// firstGoString returns the first string literal under node.
+func (c *errorSiteCollector) firstGoString(node *sitter.Node) string {
+	var found string
+	c.walk(node, func(n *sitter.Node) {
+		if found == "" {
+			found, _ = goStringLiteral(n, c.content)
+		}
+	})
+	return found
+}
+
+// goStringLiteral returns the value of a Go string literal.
+func goStringLiteral(node *sitter.Node, content []byte) (string, bool) {
+	switch node.Type() {
+	case "interpreted_string_literal":
+		text := nodeText(node, content)
+		if s, err := strconv.Unquote(text); err == nil {
+			return s, true
+		}
+		return strings.Trim(text, `"`), true
+	case "raw_string_literal":
+		return strings.Trim(nodeText(node, content), "`"), true
+	}
+	return "", false
+}
+
+// goAssignedVarName returns the name of the package-level variable a value is
+// assigned to: the name at the same position in its var_spec.
+func goAssignedVarName(value *sitter.Node, content []byte) string {
+	list := value.Parent()
+	if list == nil || list.Type() != "expression_list" {
+		return ""
+	}
+	spec := list.Parent()
+	if spec == nil || spec.Type() != "var_spec" {
+		return ""
+	}
+	index := 0
+	for i := 0; i < int(list.NamedChildCount()); i++ {
+		if list.NamedChild(i).Equal(value) {
+			index = i
+		}
+	}
+	var names []string
+	for i := 0; i < int(spec.ChildCount()); i++ {
+		if spec.FieldNameForChild(i) == "name" {
+			names = append(names, nodeText(spec.Child(i), content))
+		}
+	}
+	if index >= len(names) {
+		return ""
+	}
+	return names[index]
+}
+
+// isGoSentinelRef reports whether node names a sentinel error: ErrNotFound,
+// errClosed or io.EOF-style qualified names such as store.ErrNotFound.
+func isGoSentinelRef(node *sitter.Node, content []byte) bool {
+	switch node.Type() {
+	case "identifier", "selector_expression":
+		return isGoSentinelName(goLastSegment(nodeText(node, content)))
+	}
+	return false
+}
+
+// isGoSentinelName reports whether name follows the Go sentinel error naming
+// convention: Err or err followed by an upper-case letter.
+func isGoSentinelName(name string) bool {
+	if len(name) < 4 || (!strings.HasPrefix(name, "Err") && !strings.HasPrefix(name, "err")) {
+		return false
+	}
+	return name[3] >= 'A' && name[3] <= 'Z'
+}
+
+// goLastSegment returns the part of a qualified name after the last dot,
+// without a leading pointer.
+func goLastSegment(name string) string {
+	name = strings.TrimPrefix(name, "*")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// goWrapVerbIndex returns the index of the %w verb among the verbs of a
+// format string, or -1 if it wraps nothing.
+func goWrapVerbIndex(format string) int {
+	verbs := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) {
+			break
+		}
+		switch format[i] {
+		case '%':
+			continue
+		case 'w':
+			return verbs
+		}
+		verbs++
+	}
+	return -1
+}
+
+// -----------------------------------------------------------------------------
+// Python
+// -----------------------------------------------------------------------------
+
+// extractPythonErrorSites extracts the raise statements of a Python file:
+// raise ValueError("msg"), raise errors.NotFound. Bare re-raises are skipped.
+func extractPythonErrorSites(root *sitter.Node, content []byte, filePath string, functions []FunctionEntity) []ErrorSiteEntity {
+	c := newErrorSiteCollector(content, filePath, functions)
+	c.walk(root, func(node *sitter.Node) {
+		if node.Type() != "raise_statement" || node.NamedChildCount() == 0 {
+			return
+		}
+		exc := node.NamedChild(0)
+		name, message := nodeText(exc, content), ""
+		if exc.Type() == "call" {
+			fn := exc.ChildByFieldName("function")
+			if fn == nil {
+				return
+			}
+			name = nodeText(fn, content)
+			if args := exc.ChildByFieldName("arguments"); args != nil && args.NamedChildCount() > 0 && args.NamedChild(0).Type() == "string" {
+				message = pythonStringValue(nodeText(args.NamedChild(0), content))
+			}
+		} else if exc.Type() != "identifier" && exc.Type() != "attribute" {
+			return
+		}
+		if functionID := c.functionAt(node); functionID != "" {
+			c.add(node, functionID, "raise", name, message)
+		}
+	})
+	return c.sites
+}
+
+// pythonStringValue strips the prefix and quotes of a Python string literal.
+// Interpolations of f-strings are kept as written.
+func pythonStringValue(text string) string {
+	text = strings.TrimLeft(text, "rRbBuUfF")
+	for _, quote := range []string{`"""`, `'''`, `"`, `'`} {
+		if len(text) >= 2*len(quote) && strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) {
+			return text[len(quote) : len(text)-len(quote)]
+		}
+	}
+	return text
+}
+
+// -----------------------------------------------------------------------------
+// JavaScript / TypeScript
+// -----------------------------------------------------------------------------
+
+// extractJSErrorSites extracts the throw statements of a JavaScript or
+// TypeScript file: throw new Error("msg"), throw createError("msg").
+// Rethrown variables are skipped.
+func extractJSErrorSites(root *sitter.Node, content []byte, filePath string, functions []FunctionEntity) []ErrorSiteEntity {
+	c := newErrorSiteCollector(content, filePath, functions)
+	c.walk(root, func(node *sitter.Node) {
+		if node.Type() != "throw_statement" || node.NamedChildCount() == 0 {
+			return
+		}
+		var ctor, args *sitter.Node
+		switch exc := node.NamedChild(0); exc.Type() {
+		case "new_expression":
+			ctor, args = exc.ChildByFieldName("constructor"), exc.ChildByFieldName("arguments")
+		case "call_expression":
+			ctor, args = exc.ChildByFieldName("function"), exc.ChildByFieldName("arguments")
+		}
+		if ctor == nil {
+			return
+		}
+		message := ""
+		if args != nil && args.NamedChildCount() > 0 {
+			switch arg := args.NamedChild(0); arg.Type() {
+			case "string":
+				message = strings.Trim(nodeText(arg, content), `"'`)
+			case "template_string":
+				message = strings.Trim(nodeText(arg, content), "`")
+			}
+		}
+		if functionID := c.functionAt(node); functionID != "" {
+			c.add(node, functionID, "throw", nodeText(ctor, content), message)
+		}
+	})
+	return c.sites
+}
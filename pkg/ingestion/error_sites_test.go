@@ -0,0 +1,145 @@
+package ingestion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseErrorSites parses a source file and returns its error sites as
+// "function kind name "message" :line".
+func parseErrorSites(t *testing.T, path, language, content string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, path)
+	require.NoError(t, os.WriteFile(fullPath, []byte(content), 0600))
+
+	result, err := NewTreeSitterParser(nil).ParseFile(FileInfo{Path: path, FullPath: fullPath, Size: int64(len(content)), Language: language})
+	require.NoError(t, err)
+
+	names := make(map[string]string)
+	for _, fn := range result.Functions {
+		names[fn.ID] = fn.Name
+	}
+	var got []string
+	for _, e := range result.ErrorSites {
+		assert.Equal(t, GenerateErrorSiteID(e.FilePath, e.FunctionID, e.Kind, e.Name, e.Line), e.ID)
+		got = append(got, fmt.Sprintf("%s %s %s %q :%d", names[e.FunctionID], e.Kind, e.Name, e.Message, e.Line))
+	}
+	return got
+}
+
+// TestGoErrorSites tests sentinels, errors.New, fmt.Errorf with and without
+// %w, returned sentinels and custom error types.
+func TestGoErrorSites(t *testing.T) {
+	got := parseErrorSites(t, "store.go", "go", `package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrClosed   = fmt.Errorf("store: %w", ErrNotFound)
+)
+
+type QuotaError struct {
+	Limit int
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("quota of %d exceeded", e.Limit)
+}
+
+func Get(id string) (string, error) {
+	if id == "" {
+		return "", errors.New("empty id")
+	}
+	return "", ErrNotFound
+}
+
+func Resolve(name string) error {
+	if _, err := Get(name); err != nil {
+		return fmt.Errorf("failed to resolve project %q: %w", name, err)
+	}
+	if name == "x" {
+		return fmt.Errorf("load %s: %w", name, ErrClosed)
+	}
+	if name == "y" {
+		return &QuotaError{Limit: 3}
+	}
+	return fmt.Errorf("unknown project %s", name)
+}
+`)
+
+	assert.ElementsMatch(t, []string{
+		` sentinel ErrNotFound "not found" :9`,
+		` sentinel ErrClosed "store: %w" :10`,
+		`QuotaError.Error type QuotaError "quota of %d exceeded" :17`,
+		`Get new  "empty id" :23`,
+		`Get return ErrNotFound "" :25`,
+		`Resolve wrap  "failed to resolve project %q: %w" :30`,
+		`Resolve wrap ErrClosed "load %s: %w" :33`,
+		`Resolve custom QuotaError "" :36`,
+		`Resolve errorf  "unknown project %s" :38`,
+	}, got)
+}
+
+// TestPythonErrorSites tests raised exceptions, with and without a message.
+func TestPythonErrorSites(t *testing.T) {
+	got := parseErrorSites(t, "projects.py", "python", `class ProjectNotFound(Exception):
+    pass
+
+
+class Resolver:
+    def resolve(self, name):
+        if not name:
+            raise ValueError("empty project name")
+        try:
+            return self.load(name)
+        except KeyError as e:
+            raise ProjectNotFound(f"project {name} not found") from e
+        except OSError:
+            raise
+        raise errors.Unavailable
+`)
+
+	assert.ElementsMatch(t, []string{
+		`Resolver.resolve raise ValueError "empty project name" :8`,
+		`Resolver.resolve raise ProjectNotFound "project {name} not found" :12`,
+		`Resolver.resolve raise errors.Unavailable "" :15`,
+	}, got)
+}
+
+// TestJSErrorSites tests thrown errors in JavaScript and TypeScript; rethrown
+// variables are skipped.
+func TestJSErrorSites(t *testing.T) {
+	source := `function loadProject(name) {
+  if (!name) {
+    throw new Error("project name is required");
+  }
+  try {
+    return fetchProject(name);
+  } catch (err) {
+    throw err;
+  }
+}
+
+const resolve = (name) => {
+  throw new NotFoundError(` + "`project ${name} not found`" + `);
+};
+`
+	want := []string{
+		`loadProject throw Error "project name is required" :3`,
+		`resolve throw NotFoundError "project ${name} not found" :13`,
+	}
+
+	assert.ElementsMatch(t, want, parseErrorSites(t, "projects.js", "javascript", source))
+	assert.ElementsMatch(t, want, parseErrorSites(t, "projects.ts", "typescript", source))
+}
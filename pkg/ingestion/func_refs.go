@@ -0,0 +1,434 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// GO FUNCTION REFERENCES
+// =============================================================================
+
+// goFuncRefCollector accumulates the function values referenced by the
+// functions of one file.
+type goFuncRefCollector struct {
+	content  []byte
+	filePath string
+	fromID   string
+	locals   map[string]bool   // Names declared in the current function
+	varTypes map[string]string // Receiver, parameters and composite literals of the current function → type
+	varInits map[string]string // Variables initialized by a call (h := handlers.New()) → function called
+	refs     []UnresolvedFuncRef
+	seen     map[string]bool
+}
+
+// extractGoFuncRefs collects the identifiers and selectors that may name a
+// function used as a value rather than called:
+//   - argument: r.GET("/users", h.ListUsers), sort.Slice(xs, less)
+//   - assignment: handler := h.ListUsers, Handler{Serve: serve}
+//   - return: return h.ListUsers
+//
+// Names declared locally are skipped; ResolveFuncRefs drops the remaining
+// names that do not resolve to a function. Function literals are walked as
+// their own functions.
+func (p *TreeSitterParser) extractGoFuncRefs(ctx *goFunctionContext) []UnresolvedFuncRef {
+	c := &goFuncRefCollector{
+		content:  ctx.content,
+		filePath: ctx.filePath,
+		seen:     make(map[string]bool),
+	}
+
+	for _, fn := range ctx.functions {
+		body := fn.node.ChildByFieldName("body")
+		if body == nil {
+			continue
+		}
+		c.fromID = fn.entity.ID
+		c.locals = make(map[string]bool)
+		c.varTypes = make(map[string]string)
+		c.varInits = make(map[string]string)
+		c.collectVarTypes(fn.node)
+		c.collectLocals(body)
+		c.walk(body)
+	}
+	return c.refs
+}
+
+// collectVarTypes records the types of the receiver and parameters of a
+// function, and of the functions enclosing a function literal, which it can
+// capture. Inner declarations shadow outer ones.
+func (c *goFuncRefCollector) collectVarTypes(fnNode *sitter.Node) {
+	walkGoScopeParams(fnNode, c.content, func(name string, typeNode *sitter.Node, own bool) {
+		c.varTypes[name] = goValueTypeName(typeNode, c.content)
+		if own {
+			c.locals[name] = true
+		}
+	})
+}
+
+// walkGoScopeParams calls visit for the receiver and parameters of fnNode and
+// of the functions enclosing it, outermost first, with their type node. own
+// reports whether the name is declared by fnNode itself.
+func walkGoScopeParams(fnNode *sitter.Node, content []byte, visit func(name string, typeNode *sitter.Node, own bool)) {
+	var chain []*sitter.Node
+	for node := fnNode; node != nil; node = node.Parent() {
+		switch node.Type() {
+		case "function_declaration", "method_declaration", "func_literal":
+			chain = append(chain, node)
+		}
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, field := range []string{"receiver", "parameters"} {
+			list := chain[i].ChildByFieldName(field)
+			if list == nil {
+				continue
+			}
+			for j := 0; j < int(list.NamedChildCount()); j++ {
+				decl := list.NamedChild(j)
+				typeNode := decl.ChildByFieldName("type")
+				for k := 0; k < int(decl.ChildCount()); k++ {
+					if decl.FieldNameForChild(k) == "name" {
+						visit(nodeText(decl.Child(k), content), typeNode, chain[i] == fnNode)
+					}
+				}
+			}
+		}
+	}
+}
+
+// collectLocals records the variables declared in a function body, typing
+// those initialized with a composite literal (h := &Handler{}) and noting
+// those initialized by a function call (h := handlers.New()).
+func (c *goFuncRefCollector) collectLocals(node *sitter.Node) {
+	switch node.Type() {
+	case "func_literal":
+		return
+	case "short_var_declaration", "range_clause":
+		left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+		if left != nil {
+			for i := 0; i < int(left.NamedChildCount()); i++ {
+				name := nodeText(left.NamedChild(i), c.content)
+				c.locals[name] = true
+				if node.Type() == "short_var_declaration" && right != nil && i < int(right.NamedChildCount()) {
+					value := right.NamedChild(i)
+					c.varTypes[name] = goLiteralTypeName(value, c.content)
+					if fn := value.ChildByFieldName("function"); value.Type() == "call_expression" && fn != nil {
+						c.varInits[name] = nodeText(fn, c.content)
+					}
+				}
+			}
+		}
+	case "var_spec", "const_spec":
+		typeName := goValueTypeName(node.ChildByFieldName("type"), c.content)
+		for i := 0; i < int(node.ChildCount()); i++ {
+			if node.FieldNameForChild(i) == "name" {
+				name := nodeText(node.Child(i), c.content)
+				c.locals[name] = true
+				c.varTypes[name] = typeName
+			}
+		}
+	}
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		c.collectLocals(node.NamedChild(i))
+	}
+}
+
+// walk collects the candidate function values under node.
+func (c *goFuncRefCollector) walk(node *sitter.Node) {
+	switch node.Type() {
+	case "func_literal":
+		return // Walked as its own function
+	case "argument_list":
+		c.addAll(node, "argument")
+	case "assignment_statement", "short_var_declaration":
+		if right := node.ChildByFieldName("right"); right != nil {
+			c.addAll(right, "assignment")
+		}
+	case "var_spec":
+		if value := node.ChildByFieldName("value"); value != nil {
+			c.addAll(value, "assignment")
+		}
+	case "keyed_element":
+		if count := int(node.NamedChildCount()); count > 1 {
+			c.add(node.NamedChild(count-1), "assignment")
+		}
+	case "literal_value":
+		c.addAll(node, "assignment")
+	case "return_statement":
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			c.addAll(node.NamedChild(i), "return")
+		}
+	}
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		c.walk(node.NamedChild(i))
+	}
+}
+
+// addAll adds each named child of a list node.
+func (c *goFuncRefCollector) addAll(list *sitter.Node, kind string) {
+	for i := 0; i < int(list.NamedChildCount()); i++ {
+		c.add(list.NamedChild(i), kind)
+	}
+}
+
+// add records an identifier or selector chain (h.ListUsers, s.api.List) unless
+// it names a local variable or a predeclared identifier, or was already seen
+// on the same line.
+func (c *goFuncRefCollector) add(node *sitter.Node, kind string) {
+	if node.Type() == "literal_element" && node.NamedChildCount() == 1 {
+		node = node.NamedChild(0)
+	}
+
+	receiverType, receiverInit := "", ""
+	switch node.Type() {
+	case "identifier":
+		name := nodeText(node, c.content)
+		if c.locals[name] || isGoPredeclared(name) {
+			return
+		}
+	case "selector_expression":
+		root := node
+		for root.Type() == "selector_expression" {
+			root = root.ChildByFieldName("operand")
+			if root == nil {
+				return
+			}
+		}
+		if root.Type() != "identifier" {
+			return
+		}
+		receiverType = c.varTypes[nodeText(root, c.content)]
+		receiverInit = c.varInits[nodeText(root, c.content)]
+	default:
+		return
+	}
+
+	name := nodeText(node, c.content)
+	line := int(node.StartPoint().Row) + 1
+	key := fmt.Sprintf("%s|%s|%s|%d", c.fromID, name, kind, line)
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.refs = append(c.refs, UnresolvedFuncRef{
+		FromID:       c.fromID,
+		Name:         name,
+		ReceiverType: receiverType,
+		ReceiverInit: receiverInit,
+		Kind:         kind,
+		FilePath:     c.filePath,
+		Line:         line,
+	})
+}
+
+// goValueTypeName returns the named type of a declared type, without pointer
+// or package: "*handlers.Handler" → "Handler". Returns "" for other types.
+func goValueTypeName(node *sitter.Node, content []byte) string {
+	if node == nil {
+		return ""
+	}
+	switch node.Type() {
+	case "type_identifier":
+		return nodeText(node, content)
+	case "pointer_type":
+		if node.NamedChildCount() > 0 {
+			return goValueTypeName(node.NamedChild(0), content)
+		}
+	case "qualified_type":
+		return goValueTypeName(node.ChildByFieldName("name"), content)
+	case "generic_type":
+		return goValueTypeName(node.ChildByFieldName("type"), content)
+	}
+	return ""
+}
+
+// goLiteralTypeName returns the type of a composite literal initializer:
+// "Handler" for Handler{} and &Handler{}, else "".
+func goLiteralTypeName(node *sitter.Node, content []byte) string {
+	if node.Type() == "unary_expression" {
+		if operand := node.ChildByFieldName("operand"); operand != nil {
+			node = operand
+		}
+	}
+	if node.Type() != "composite_literal" {
+		return ""
+	}
+	return goValueTypeName(node.ChildByFieldName("type"), content)
+}
+
+// isGoPredeclared reports whether name is a predeclared Go constant, builtin
+// function or type, which never names a repository function.
+func isGoPredeclared(name string) bool {
+	switch name {
+	case "nil", "true", "false", "iota", "_",
+		"append", "cap", "clear", "close", "complex", "copy", "delete", "imag",
+		"len", "make", "max", "min", "new", "panic", "print", "println", "real", "recover":
+		return true
+	}
+	return isGoBuiltinType(name)
+}
+
+// =============================================================================
+// RESOLUTION
+// =============================================================================
+
+// ResolveFuncRefs resolves the function values referenced in Go files:
+//   - "Name" and "pkg.Name" resolve to top-level functions through the package
+//     of the referencing file and its imports (see goSymbolIndex.resolve)
+//   - method values "h.ListUsers" and "s.api.List" resolve to the method of
+//     the receiver's type, following struct fields. A receiver initialized
+//     by a call (h := handlers.New()) has the result type of that function
+//
+// Names that are not functions (variables, constants) produce no edge.
+func ResolveFuncRefs(refs []UnresolvedFuncRef, files []FileEntity, functions []FunctionEntity, fields []FieldEntity, imports []ImportEntity, packageNames map[string]string) []FuncRefEdge {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	index := newGoSymbolIndex(files, imports, packageNames)
+	funcIDs := make(map[string]string)     // dir + "." + name -> function ID
+	methods := make(map[string][]string)   // "Type.Method" -> method IDs
+	funcDirs := make(map[string]string)    // function ID -> dir
+	funcResults := make(map[string]string) // function ID -> named result type
+	for _, fn := range functions {
+		if !strings.HasSuffix(fn.FilePath, ".go") || strings.HasPrefix(fn.Name, "$") {
+			continue
+		}
+		funcDirs[fn.ID] = path.Dir(fn.FilePath)
+		funcResults[fn.ID] = goResultTypeName(fn.Signature)
+		typeName, method, isMethod := strings.Cut(fn.Name, ".")
+		if !isMethod {
+			index.add(funcIDs, fn.FilePath, fn.Name, fn.ID)
+			continue
+		}
+		if i := strings.IndexAny(typeName, "[<"); i > 0 {
+			typeName = typeName[:i]
+		}
+		methods[typeName+"."+method] = append(methods[typeName+"."+method], fn.ID)
+	}
+	fieldTypes := make(map[string]map[string]string) // struct -> field -> type
+	for _, f := range fields {
+		if !strings.HasSuffix(f.FilePath, ".go") {
+			continue
+		}
+		if fieldTypes[f.StructName] == nil {
+			fieldTypes[f.StructName] = make(map[string]string)
+		}
+		fieldTypes[f.StructName][f.FieldName] = f.FieldType
+	}
+
+	// pick returns the candidate declared in dir, else the only candidate.
+	pick := func(candidates []string, dir string) string {
+		for _, id := range candidates {
+			if funcDirs[id] == dir {
+				return id
+			}
+		}
+		if len(candidates) == 1 {
+			return candidates[0]
+		}
+		return ""
+	}
+
+	seen := make(map[string]bool)
+	var edges []FuncRefEdge
+	for _, ref := range refs {
+		var toID string
+		qualifier, rest, dotted := strings.Cut(ref.Name, ".")
+		_, imported := index.qualified[ref.FilePath][qualifier]
+		switch {
+		case !dotted || (imported && !strings.Contains(rest, ".")):
+			toID = index.resolve(funcIDs, ref.FilePath, ref.Name)
+		case ref.ReceiverType != "" || ref.ReceiverInit != "":
+			parts := strings.Split(ref.Name, ".")
+			typeName := ref.ReceiverType
+			if typeName == "" {
+				typeName = funcResults[index.resolve(funcIDs, ref.FilePath, ref.ReceiverInit)]
+			}
+			for _, field := range parts[1 : len(parts)-1] {
+				typeName = fieldTypes[typeName][field]
+			}
+			if typeName != "" {
+				toID = pick(methods[typeName+"."+parts[len(parts)-1]], path.Dir(ref.FilePath))
+			}
+		}
+		if toID == "" || toID == ref.FromID {
+			continue
+		}
+
+		id := GenerateFuncRefID(ref.FromID, toID, ref.Kind, ref.Line)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		edges = append(edges, FuncRefEdge{
+			ID:       id,
+			FromID:   ref.FromID,
+			ToID:     toID,
+			Kind:     ref.Kind,
+			FilePath: ref.FilePath,
+			Line:     ref.Line,
+		})
+	}
+	return edges
+}
+
+// goResultTypeName returns the named type of the first result of a function
+// signature: "func(db *sql.DB) (*Handler, error)" → "Handler".
+func goResultTypeName(signature string) string {
+	open := strings.Index(signature, "(")
+	if open == -1 {
+		return ""
+	}
+	end := strings.Index(signature, ")")
+	for depth, i := 0, open; i < len(signature); i++ {
+		switch signature[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 {
+			end = i
+			break
+		}
+	}
+	result := strings.TrimSpace(signature[end+1:])
+	result = strings.TrimPrefix(result, "(")
+	result, _, _ = strings.Cut(result, ",")
+	result, _, _ = strings.Cut(result, ")")
+	fields := strings.Fields(result)
+	if len(fields) == 0 {
+		return ""
+	}
+	typeName := strings.TrimLeft(fields[len(fields)-1], "*")
+	typeName, _, _ = strings.Cut(typeName, "[")
+	if i := strings.LastIndex(typeName, "."); i >= 0 {
+		typeName = typeName[i+1:]
+	}
+	return typeName
+}
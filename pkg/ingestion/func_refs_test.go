@@ -0,0 +1,149 @@
+package ingestion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resolveFuncRefSources parses Go sources keyed by path and resolves their
+// function references, returned as "from -> to (kind) :line".
+func resolveFuncRefSources(t *testing.T, sources map[string]string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	parser := NewTreeSitterParser(nil)
+	var files []FileEntity
+	var functions []FunctionEntity
+	var fields []FieldEntity
+	var imports []ImportEntity
+	var refs []UnresolvedFuncRef
+	names := make(map[string]string)
+	packageNames := make(map[string]string)
+	for path, content := range sources {
+		fullPath := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), 0600))
+
+		result, err := parser.ParseFile(FileInfo{Path: path, FullPath: fullPath, Size: int64(len(content)), Language: "go"})
+		require.NoError(t, err)
+		files = append(files, result.File)
+		functions = append(functions, result.Functions...)
+		fields = append(fields, result.Fields...)
+		imports = append(imports, result.Imports...)
+		refs = append(refs, result.FuncRefs...)
+		packageNames[path] = result.PackageName
+		for _, fn := range result.Functions {
+			names[fn.ID] = fn.Name
+		}
+	}
+
+	var got []string
+	for _, e := range ResolveFuncRefs(refs, files, functions, fields, imports, packageNames) {
+		assert.Equal(t, GenerateFuncRefID(e.FromID, e.ToID, e.Kind, e.Line), e.ID)
+		got = append(got, names[e.FromID]+" -> "+names[e.ToID]+" ("+e.Kind+") :"+fmt.Sprint(e.Line))
+	}
+	return got
+}
+
+// TestResolveFuncRefs tests handler registration through parameters,
+// receivers, fields and constructors, and callbacks passed, assigned and
+// returned across packages.
+func TestResolveFuncRefs(t *testing.T) {
+	got := resolveFuncRefSources(t, map[string]string{
+		"internal/handlers/users.go": `package handlers
+
+type Handler struct{}
+
+func New() *Handler { return &Handler{} }
+
+func (h *Handler) ListUsers(c Context) {}
+
+func (h *Handler) GetUser(c Context) {}
+
+func ByName(a, b string) bool { return a < b }
+`,
+		"internal/server/server.go": `package server
+
+import (
+	"sort"
+
+	"github.com/acme/app/internal/handlers"
+)
+
+type Router interface {
+	GET(path string, fn func(handlers.Context))
+}
+
+type Server struct {
+	users *handlers.Handler
+}
+
+type Route struct {
+	Handle func(handlers.Context)
+}
+
+func (s *Server) Register(r Router, h *handlers.Handler) {
+	r.GET("/users", h.ListUsers)
+	r.GET("/users/:id", s.users.GetUser)
+	direct := handlers.New()
+	r.GET("/me", direct.GetUser)
+}
+
+func routes() []Route {
+	return []Route{{Handle: handlers.New().ListUsers}, {Handle: fallback}}
+}
+
+func fallback(c handlers.Context) {}
+
+func sortNames(names []string) {
+	less := handlers.ByName
+	sort.Slice(names, func(i, j int) bool { return less(names[i], names[j]) })
+}
+
+func pick() func(handlers.Context) {
+	return fallback
+}
+`,
+	})
+
+	assert.ElementsMatch(t, []string{
+		"Server.Register -> Handler.ListUsers (argument) :22",
+		"Server.Register -> Handler.GetUser (argument) :23",
+		"Server.Register -> Handler.GetUser (argument) :25",
+		"routes -> fallback (assignment) :29",
+		"sortNames -> ByName (assignment) :35",
+		"pick -> fallback (return) :40",
+	}, got)
+}
+
+// TestResolveFuncRefs_Unresolved tests that locals, variables, builtins,
+// external functions and calls produce no edge.
+func TestResolveFuncRefs_Unresolved(t *testing.T) {
+	got := resolveFuncRefSources(t, map[string]string{
+		"main.go": `package main
+
+import "strings"
+
+var limit = 10
+
+func apply(fn func(string) string, s string) string { return fn(s) }
+
+func main() {
+	upper := strings.ToUpper
+	local := func(s string) string { return s }
+	_ = apply(upper, "x")
+	_ = apply(local, "x")
+	_ = apply(strings.TrimSpace, "x")
+	_ = append([]int{}, limit)
+	_ = apply(nil, "x")
+}
+`,
+	})
+
+	assert.Empty(t, got)
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"bytes"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// metricsGrammar lists the node types of a tree-sitter grammar that matter
+// for function metrics.
+type metricsGrammar struct {
+	// functions are the nodes declaring a function, method or closure.
+	functions map[string]bool
+
+	// branches are the nodes adding a decision point: conditionals, loops,
+	// switch cases, catch clauses and conditional expressions.
+	branches map[string]bool
+
+	// nesting are the control structures whose body is one level deeper.
+	nesting map[string]bool
+
+	// logical are the short-circuit operators of binary expressions.
+	logical map[string]bool
+}
+
+// metricsGrammars maps a language to the node types used for its metrics.
+var metricsGrammars = map[string]metricsGrammar{
+	"go": {
+		functions: nodeTypeSet("function_declaration", "method_declaration", "func_literal"),
+		branches: nodeTypeSet("if_statement", "for_statement", "expression_case", "type_case",
+			"communication_case"),
+		nesting: nodeTypeSet("if_statement", "for_statement", "expression_switch_statement",
+			"type_switch_statement", "select_statement"),
+		logical: nodeTypeSet("&&", "||"),
+	},
+	"python": {
+		functions: nodeTypeSet("function_definition"),
+		branches: nodeTypeSet("if_statement", "elif_clause", "for_statement", "while_statement",
+			"except_clause", "conditional_expression", "boolean_operator", "case_clause",
+			"for_in_clause", "if_clause"),
+		nesting: nodeTypeSet("if_statement", "for_statement", "while_statement", "try_statement",
+			"with_statement", "match_statement"),
+	},
+	"javascript": jsMetricsGrammar,
+	"typescript": jsMetricsGrammar,
+	"php": {
+		functions: nodeTypeSet("function_definition", "method_declaration", "anonymous_function",
+			"anonymous_function_creation_expression", "arrow_function"),
+		branches: nodeTypeSet("if_statement", "else_if_clause", "for_statement", "foreach_statement",
+			"while_statement", "do_statement", "case_statement", "catch_clause", "conditional_expression"),
+		nesting: nodeTypeSet("if_statement", "for_statement", "foreach_statement", "while_statement",
+			"do_statement", "switch_statement", "try_statement"),
+		logical: nodeTypeSet("&&", "||", "and", "or", "??"),
+	},
+}
+
+// jsMetricsGrammar is shared by JavaScript and TypeScript.
+var jsMetricsGrammar = metricsGrammar{
+	functions: nodeTypeSet("function_declaration", "generator_function_declaration", "function_expression",
+		"function", "generator_function", "arrow_function", "method_definition"),
+	branches: nodeTypeSet("if_statement", "for_statement", "for_in_statement", "while_statement",
+		"do_statement", "switch_case", "catch_clause", "ternary_expression"),
+	nesting: nodeTypeSet("if_statement", "for_statement", "for_in_statement", "while_statement",
+		"do_statement", "switch_statement", "try_statement"),
+	logical: nodeTypeSet("&&", "||", "??"),
+}
+
+// nodeTypeSet returns a set of node types.
+func nodeTypeSet(types ...string) map[string]bool {
+	set := make(map[string]bool, len(types))
+	for _, t := range types {
+		set[t] = true
+	}
+	return set
+}
+
+// extractFunctionMetrics computes the complexity and size metrics of the
+// functions of a file from its syntax tree:
+//   - complexity: cyclomatic complexity, 1 + the decision points (if, loops,
+//     cases, catch, conditional expressions, && and ||)
+//   - max nesting: the deepest nesting of control structures; else-if chains
+//     stay at the depth of their first if
+//   - params: declared parameters, without the receiver or self/cls
+//   - loc: non-blank lines of the function
+//   - comment lines: lines holding a comment or a Python docstring
+//
+// Nested functions and closures are measured on their own: their branches
+// and nesting do not count toward the enclosing function, their lines do.
+func extractFunctionMetrics(root *sitter.Node, content []byte, language string, functions []FunctionEntity) []FunctionMetricsEntity {
+	grammar, ok := metricsGrammars[language]
+	if !ok || root == nil || len(functions) == 0 {
+		return nil
+	}
+	lines := bytes.Split(content, []byte("\n"))
+
+	var metrics []FunctionMetricsEntity
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		if grammar.functions[node.Type()] {
+			if fn := functionEndingAt(functions, node); fn != nil {
+				metrics = append(metrics, measureFunction(node, content, lines, grammar, fn))
+			}
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i))
+		}
+	}
+	walk(root)
+	return metrics
+}
+
+// functionEndingAt returns the function extracted from node: the innermost
+// function ending where node ends and starting at or before it. Functions
+// assigned to variables start at their declaration, before node.
+func functionEndingAt(functions []FunctionEntity, node *sitter.Node) *FunctionEntity {
+	endLine, endCol := int(node.EndPoint().Row)+1, int(node.EndPoint().Column)+1
+	startLine, startCol := int(node.StartPoint().Row)+1, int(node.StartPoint().Column)+1
+	var best *FunctionEntity
+	for i := range functions {
+		fn := &functions[i]
+		if fn.EndLine != endLine || fn.EndCol != endCol {
+			continue
+		}
+		if fn.StartLine > startLine || (fn.StartLine == startLine && fn.StartCol > startCol) {
+			continue
+		}
+		if best == nil || fn.StartLine > best.StartLine || (fn.StartLine == best.StartLine && fn.StartCol > best.StartCol) {
+			best = fn
+		}
+	}
+	return best
+}
+
+// measureFunction computes the metrics of the function declared by node.
+func measureFunction(node *sitter.Node, content []byte, lines [][]byte, grammar metricsGrammar, fn *FunctionEntity) FunctionMetricsEntity {
+	m := FunctionMetricsEntity{
+		FunctionID: fn.ID,
+		FilePath:   fn.FilePath,
+		Complexity: 1,
+		Params:     countParams(node, content),
+	}
+
+	commentLines := make(map[int]bool)
+	var visit func(n *sitter.Node, depth int, inNested bool)
+	visit = func(n *sitter.Node, depth int, inNested bool) {
+		if n.Type() == "comment" || isDocstring(n) {
+			for line := int(n.StartPoint().Row); line <= int(n.EndPoint().Row); line++ {
+				commentLines[line] = true
+			}
+		}
+		if !inNested {
+			if grammar.branches[n.Type()] {
+				m.Complexity++
+			}
+			if len(grammar.logical) > 0 && n.Type() == "binary_expression" {
+				if op := n.ChildByFieldName("operator"); op != nil && grammar.logical[op.Type()] {
+					m.Complexity++
+				}
+			}
+			if grammar.nesting[n.Type()] && !isElseIf(n) {
+				depth++
+				if depth > m.MaxNesting {
+					m.MaxNesting = depth
+				}
+			}
+		}
+		for i := 0; i < int(n.NamedChildCount()); i++ {
+			child := n.NamedChild(i)
+			visit(child, depth, inNested || grammar.functions[child.Type()])
+		}
+	}
+	visit(node, 0, false)
+
+	for line := int(node.StartPoint().Row); line <= int(node.EndPoint().Row) && line < len(lines); line++ {
+		if len(bytes.TrimSpace(lines[line])) > 0 {
+			m.LOC++
+			if commentLines[line] {
+				m.CommentLines++
+			}
+		}
+	}
+	if m.LOC > 0 {
+		m.CommentRatio = float64(m.CommentLines) / float64(m.LOC)
+	}
+	return m
+}
+
+// isElseIf reports whether node is the if of an else-if, which continues
+// the chain of its parent if rather than nesting inside it.
+func isElseIf(node *sitter.Node) bool {
+	if node.Type() != "if_statement" {
+		return false
+	}
+	parent := node.Parent()
+	return parent != nil && (parent.Type() == "if_statement" || parent.Type() == "else_clause")
+}
+
+// isDocstring reports whether node is a Python docstring: a string
+// statement opening a function body.
+func isDocstring(node *sitter.Node) bool {
+	if node.Type() != "expression_statement" || node.NamedChildCount() != 1 || node.NamedChild(0).Type() != "string" {
+		return false
+	}
+	body := node.Parent()
+	if body == nil || body.Type() != "block" || body.NamedChildCount() == 0 || !body.NamedChild(0).Equal(node) {
+		return false
+	}
+	fn := body.Parent()
+	return fn != nil && fn.Type() == "function_definition"
+}
+
+// countParams counts the declared parameters of a function node. Go
+// declarations naming several parameters (a, b int) count each name; the
+// receiver and a leading self or cls are not parameters.
+func countParams(node *sitter.Node, content []byte) int {
+	params := node.ChildByFieldName("parameters")
+	if params == nil {
+		// Arrow functions with a single unparenthesized parameter: x => x
+		if node.ChildByFieldName("parameter") != nil {
+			return 1
+		}
+		return 0
+	}
+	count := 0
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		switch param.Type() {
+		case "comment", "positional_separator", "keyword_separator":
+			continue
+		case "parameter_declaration":
+			names := 0
+			for j := 0; j < int(param.NamedChildCount()); j++ {
+				if param.NamedChild(j).Type() == "identifier" {
+					names++
+				}
+			}
+			count += max(names, 1)
+			continue
+		case "identifier":
+			if count == 0 && i == 0 {
+				if name := nodeText(param, content); name == "self" || name == "cls" {
+					continue
+				}
+			}
+		}
+		count++
+	}
+	return count
+}
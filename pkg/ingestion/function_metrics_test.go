@@ -0,0 +1,123 @@
+package ingestion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseFunctionMetrics parses a source file and returns the metrics of its
+// functions as "complexity=N nesting=N params=N loc=N comments=N" by name.
+func parseFunctionMetrics(t *testing.T, path, language, content string) map[string]string {
+	t.Helper()
+
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, path)
+	require.NoError(t, os.WriteFile(fullPath, []byte(content), 0600))
+
+	result, err := NewTreeSitterParser(nil).ParseFile(FileInfo{Path: path, FullPath: fullPath, Size: int64(len(content)), Language: language})
+	require.NoError(t, err)
+
+	names := make(map[string]string)
+	for _, fn := range result.Functions {
+		names[fn.ID] = fn.Name
+	}
+	got := make(map[string]string)
+	for _, m := range result.FunctionMetrics {
+		assert.Equal(t, path, m.FilePath)
+		got[names[m.FunctionID]] = fmt.Sprintf("complexity=%d nesting=%d params=%d loc=%d comments=%d",
+			m.Complexity, m.MaxNesting, m.Params, m.LOC, m.CommentLines)
+	}
+	return got
+}
+
+// TestGoFunctionMetrics tests branches, boolean operators, else-if chains,
+// switch cases, grouped parameters and closures measured on their own.
+func TestGoFunctionMetrics(t *testing.T) {
+	got := parseFunctionMetrics(t, "store.go", "go", `package store
+
+// Get returns the value of key.
+func (s *Store) Get(key string, fallback, other int) int {
+	// Look up the cache first.
+	if v, ok := s.cache[key]; ok && v > 0 {
+		return v
+	} else if fallback > 0 {
+		return fallback
+	}
+
+	for _, item := range s.items {
+		switch item.kind {
+		case "a", "b":
+			if item.key == key || item.alias == key {
+				return item.value
+			}
+		case "c":
+			continue
+		default:
+		}
+	}
+	go func() {
+		if s.closed {
+			return
+		}
+	}()
+	return other
+}
+
+func Empty() {}
+`)
+
+	// 1 + if + && + else-if + for + 2 cases + if + || = 9
+	assert.Equal(t, "complexity=9 nesting=3 params=3 loc=25 comments=1", got["Store.Get"])
+	assert.Equal(t, "complexity=2 nesting=1 params=0 loc=5 comments=0", got["$anon_1"])
+	assert.Equal(t, "complexity=1 nesting=0 params=0 loc=1 comments=0", got["Empty"])
+}
+
+// TestPythonFunctionMetrics tests elif chains, boolean operators,
+// comprehensions, docstrings and self.
+func TestPythonFunctionMetrics(t *testing.T) {
+	got := parseFunctionMetrics(t, "orders.py", "python", `class Orders:
+    def total(self, items, discount=0):
+        """Sum the order lines.
+
+        Discounts apply last.
+        """
+        # skip cancelled lines
+        prices = [i.price for i in items if not i.cancelled]
+        if not prices:
+            return 0
+        elif discount and discount > 0:
+            return sum(prices) - discount
+        try:
+            return sum(prices)
+        except ValueError:
+            return 0
+`)
+
+	// 1 + for_in + if_clause + if + elif + and + except = 7
+	assert.Equal(t, "complexity=7 nesting=1 params=2 loc=14 comments=4", got["Orders.total"])
+}
+
+// TestJSFunctionMetrics tests arrow functions assigned to variables,
+// ternaries, nullish coalescing and catch clauses.
+func TestJSFunctionMetrics(t *testing.T) {
+	got := parseFunctionMetrics(t, "load.js", "javascript", `const load = async (path, opts) => {
+  try {
+    const data = await read(path ?? opts.path);
+    return data ? JSON.parse(data) : null;
+  } catch (err) {
+    if (err.code === 'ENOENT') {
+      return null;
+    }
+    throw err;
+  }
+};
+`)
+
+	// 1 + ?? + ternary + catch + if = 5
+	assert.Equal(t, "complexity=5 nesting=2 params=2 loc=11 comments=0", got["load"])
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// GO TYPE PARAMETERS
+// =============================================================================
+
+// extractGoTypeParams extracts the type parameters of the generic functions and
+// types of a Go file. `[K comparable, V any]` yields K and V at positions 0
+// and 1; `[T, U any]` yields T and U, both constrained by any.
+//
+// Methods have no type parameters of their own: the [T] of a receiver such as
+// (l *List[T]) names those of List.
+func (p *TreeSitterParser) extractGoTypeParams(rootNode *sitter.Node, ctx *goFunctionContext, types []TypeEntity) []TypeParamEntity {
+	var params []TypeParamEntity
+	for _, fn := range ctx.functions {
+		if fn.node.Type() != "function_declaration" {
+			continue
+		}
+		params = appendGoTypeParams(params, fn.node.ChildByFieldName("type_parameters"), fn.entity.ID, ctx)
+	}
+
+	typeIDs := make(map[string]bool, len(types))
+	for _, t := range types {
+		typeIDs[t.ID] = true
+	}
+	walkGoTypeSpecs(rootNode, ctx.content, ctx.filePath, typeIDs, func(node *sitter.Node, id string) {
+		params = appendGoTypeParams(params, node.ChildByFieldName("type_parameters"), id, ctx)
+	})
+	return params
+}
+
+// appendGoTypeParams appends the parameters declared in a type_parameter_list.
+func appendGoTypeParams(params []TypeParamEntity, list *sitter.Node, ownerID string, ctx *goFunctionContext) []TypeParamEntity {
+	if list == nil {
+		return params
+	}
+	position := 0
+	for i := 0; i < int(list.NamedChildCount()); i++ {
+		decl := list.NamedChild(i)
+		if decl.Type() != "type_parameter_declaration" {
+			continue
+		}
+		constraint := ""
+		if typeNode := decl.ChildByFieldName("type"); typeNode != nil {
+			constraint = nodeText(typeNode, ctx.content)
+		}
+		for j := 0; j < int(decl.ChildCount()); j++ {
+			if decl.FieldNameForChild(j) != "name" {
+				continue
+			}
+			name := nodeText(decl.Child(j), ctx.content)
+			params = append(params, TypeParamEntity{
+				ID:         GenerateTypeParamID(ownerID, name),
+				OwnerID:    ownerID,
+				Name:       name,
+				Constraint: constraint,
+				Position:   position,
+				FilePath:   ctx.filePath,
+			})
+			position++
+		}
+	}
+	return params
+}
+
+// walkGoTypeSpecs calls fn with every type_spec whose type was extracted as a
+// TypeEntity, and the ID of that entity.
+func walkGoTypeSpecs(node *sitter.Node, content []byte, filePath string, typeIDs map[string]bool, fn func(node *sitter.Node, id string)) {
+	if node.Type() == "type_spec" {
+		if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+			id := GenerateTypeID(filePath, nodeText(nameNode, content),
+				int(node.StartPoint().Row)+1, int(node.EndPoint().Row)+1)
+			if typeIDs[id] {
+				fn(node, id)
+			}
+		}
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		walkGoTypeSpecs(node.NamedChild(i), content, filePath, typeIDs, fn)
+	}
+}
+
+// goTypeArgs returns the type arguments of a type_arguments node without the
+// brackets: "[int, string]" -> "int, string".
+func goTypeArgs(node *sitter.Node, content []byte) string {
+	text := nodeText(node, content)
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "["), "]"))
+}
+
+// =============================================================================
+// INSTANTIATION RESOLUTION
+// =============================================================================
+
+// ResolveInstantiations resolves the generic functions and types instantiated
+// in Go files, through the package of the instantiating file and its imports
+// (see goSymbolIndex.resolve). A name resolves to a top-level function first,
+// then to a type.
+//
+// Instantiations of external generics (slices.Map, atomic.Pointer[T]) produce
+// no edge.
+func ResolveInstantiations(insts []UnresolvedInstantiation, files []FileEntity, functions []FunctionEntity, types []TypeEntity, imports []ImportEntity, packageNames map[string]string) []InstantiationEdge {
+	if len(insts) == 0 {
+		return nil
+	}
+
+	index := newGoSymbolIndex(files, imports, packageNames)
+	funcIDs := make(map[string]string) // dir + "." + name -> function ID
+	for _, fn := range functions {
+		if !strings.Contains(fn.Name, ".") {
+			index.add(funcIDs, fn.FilePath, fn.Name, fn.ID)
+		}
+	}
+	typeIDs := make(map[string]string) // dir + "." + name -> type ID
+	for _, t := range types {
+		index.add(typeIDs, t.FilePath, t.Name, t.ID)
+	}
+
+	seen := make(map[string]bool)
+	var edges []InstantiationEdge
+	for _, inst := range insts {
+		targetKind := "function"
+		targetID := index.resolve(funcIDs, inst.FilePath, inst.Name)
+		if targetID == "" {
+			targetKind = "type"
+			targetID = index.resolve(typeIDs, inst.FilePath, inst.Name)
+		}
+		if targetID == "" {
+			continue
+		}
+
+		id := GenerateInstantiationID(inst.FromID, targetID, inst.TypeArgs, inst.Line)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		edges = append(edges, InstantiationEdge{
+			ID:         id,
+			FromID:     inst.FromID,
+			TargetID:   targetID,
+			TargetKind: targetKind,
+			TypeArgs:   inst.TypeArgs,
+			FilePath:   inst.FilePath,
+			Line:       inst.Line,
+		})
+	}
+	return edges
+}
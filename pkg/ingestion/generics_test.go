@@ -0,0 +1,174 @@
+package ingestion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseGoSources parses Go sources keyed by path.
+func parseGoSources(t *testing.T, sources map[string]string) []*ParseResult {
+	t.Helper()
+
+	dir := t.TempDir()
+	parser := NewTreeSitterParser(nil)
+	var results []*ParseResult
+	for path, content := range sources {
+		fullPath := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), 0600))
+
+		result, err := parser.ParseFile(FileInfo{Path: path, FullPath: fullPath, Size: int64(len(content)), Language: "go"})
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+	return results
+}
+
+// TestGoParser_TypeParams tests that type parameters and their constraints are
+// recorded for generic functions and types, but not for methods.
+func TestGoParser_TypeParams(t *testing.T) {
+	result := parseTestFile(t, "testdata/go/generics.go")
+
+	owners := make(map[string]string)
+	for _, fn := range result.Functions {
+		owners[fn.ID] = fn.Name
+	}
+	for _, typ := range result.Types {
+		owners[typ.ID] = "type " + typ.Name
+	}
+
+	var got []string
+	for _, tp := range result.TypeParams {
+		assert.Equal(t, GenerateTypeParamID(tp.OwnerID, tp.Name), tp.ID)
+		assert.Equal(t, "generics.go", tp.FilePath)
+		got = append(got, fmt.Sprintf("%s %d:%s %s", owners[tp.OwnerID], tp.Position, tp.Name, tp.Constraint))
+	}
+	assert.Equal(t, []string{
+		"Map 0:T any",
+		"Map 1:U any",
+		"type Container 0:T any",
+	}, got)
+}
+
+// TestResolveInstantiations tests instantiations of generic functions and
+// types, within a package and through imports.
+func TestResolveInstantiations(t *testing.T) {
+	results := parseGoSources(t, map[string]string{
+		"pkg/coll/coll.go": `package coll
+
+type Number interface {
+	~int | ~float64
+}
+
+type List[T any] struct {
+	items []T
+}
+
+func (l *List[T]) Len() int { return len(l.items) }
+
+func Map[T, U any](s []T, fn func(T) U) []U { return nil }
+
+func Sum[N Number](s []N) N {
+	var total N
+	return total
+}
+
+func Lengths(s []string) []int {
+	return Map[string, int](s, func(v string) int { return len(v) })
+}
+`,
+		"cmd/app/main.go": `package main
+
+import (
+	"slices"
+
+	"github.com/kraklabs/cie/pkg/coll"
+)
+
+type Registry struct {
+	names coll.List[string]
+}
+
+func run() {
+	l := &coll.List[int]{}
+	_ = coll.Map[int](nil, func(v int) int { return v })
+	_ = coll.Sum[float64](nil)
+	_ = slices.Index[[]int](nil, 1)
+	_ = l
+}
+`,
+	})
+
+	var files []FileEntity
+	var functions []FunctionEntity
+	var types []TypeEntity
+	var imports []ImportEntity
+	var insts []UnresolvedInstantiation
+	packageNames := make(map[string]string)
+	names := make(map[string]string)
+	for _, r := range results {
+		files = append(files, r.File)
+		functions = append(functions, r.Functions...)
+		types = append(types, r.Types...)
+		imports = append(imports, r.Imports...)
+		insts = append(insts, r.Instantiations...)
+		packageNames[r.File.Path] = r.PackageName
+		for _, fn := range r.Functions {
+			names[fn.ID] = fn.Name
+		}
+		for _, typ := range r.Types {
+			names[typ.ID] = "type " + typ.Name
+		}
+	}
+
+	var got []string
+	for _, e := range ResolveInstantiations(insts, files, functions, types, imports, packageNames) {
+		assert.Equal(t, GenerateInstantiationID(e.FromID, e.TargetID, e.TypeArgs, e.Line), e.ID)
+		got = append(got, fmt.Sprintf("%s -> %s [%s] (%s) :%d", names[e.FromID], names[e.TargetID], e.TypeArgs, e.TargetKind, e.Line))
+	}
+	assert.ElementsMatch(t, []string{
+		"Lengths -> Map [string, int] (function) :21",
+		"type Registry -> type List [string] (type) :10",
+		"run -> type List [int] (type) :14",
+		"run -> Map [int] (function) :15",
+		"run -> Sum [float64] (function) :16",
+	}, got)
+}
+
+// TestGoParser_GenericCalls tests that calls with explicit type arguments are
+// recorded in the call graph, including package-qualified ones that parse as
+// conversions to a generic type.
+func TestGoParser_GenericCalls(t *testing.T) {
+	results := parseGoSources(t, map[string]string{
+		"main.go": `package main
+
+import "example.com/coll"
+
+func Map[T, U any](s []T, fn func(T) U) []U { return nil }
+
+func run() {
+	_ = Map[int, string](nil, nil)
+	_ = coll.Filter[int](nil)
+}
+`,
+	})
+	require.Len(t, results, 1)
+	result := results[0]
+
+	ids := make(map[string]string)
+	for _, fn := range result.Functions {
+		ids[fn.Name] = fn.ID
+	}
+	assert.Contains(t, result.Calls, CallsEdge{CallerID: ids["run"], CalleeID: ids["Map"], CallLine: 8})
+
+	var unresolved []string
+	for _, call := range result.UnresolvedCalls {
+		unresolved = append(unresolved, call.CalleeName)
+	}
+	assert.Contains(t, unresolved, "coll.Filter")
+}
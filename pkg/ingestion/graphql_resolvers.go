@@ -0,0 +1,472 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// =============================================================================
+// GRAPHQL RESOLVER LINKING
+// =============================================================================
+
+// GraphQL resolver frameworks stored in cie_graphql_resolver.
+const (
+	GraphQLFrameworkGqlgen = "gqlgen"
+	GraphQLFrameworkApollo = "apollo"
+)
+
+var (
+	// apolloRootKeyPattern finds root type keys inside a resolver map:
+	// { Query: { ... }, Mutation: { ... } }
+	apolloRootKeyPattern = regexp.MustCompile(`(?:^|[{,\s])["']?(?:Query|Mutation|Subscription)["']?\s*:\s*\{`)
+
+	// apolloMapVarPattern finds variables holding a resolver map or a single
+	// type's resolvers: const resolvers: Resolvers = {, export const Query = {
+	apolloMapVarPattern = regexp.MustCompile(`\b(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*(?::\s*[^=;{]+)?=\s*\{`)
+
+	jsIdentRefPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*(?:\.[A-Za-z_$][\w$]*)*$`)
+)
+
+// jsObjectEntry is a top-level "key: value" or "key() {}" entry of a JS object literal.
+type jsObjectEntry struct {
+	key        string
+	keyOffset  int
+	valueStart int // For method shorthand, the start of the entry
+	valueEnd   int
+}
+
+// extractApolloResolvers finds Apollo-style resolver maps in JavaScript or
+// TypeScript source and binds each "Type.field" to the function resolving it.
+//
+// Recognized shapes:
+//   - { Query: { orders: (...) => ..., order(parent, args) {...} }, Order: {...} }
+//   - const resolvers = { ... } (any capitalized key with an object value)
+//   - const Query: QueryResolvers = { orders: listOrders }
+//
+// Inline functions are matched to parsed functions by position. Resolvers
+// referenced by name ("orders: listOrders") are returned with FunctionName set
+// and resolved across files by LinkGraphQLResolvers. Subscription fields bind
+// to their resolve (or subscribe) function.
+func extractApolloResolvers(content, filePath string, functions []FunctionEntity) []GraphQLResolverEdge {
+	if !strings.Contains(content, "Query") && !strings.Contains(content, "Mutation") &&
+		!strings.Contains(content, "Subscription") && !strings.Contains(strings.ToLower(content), "resolvers") {
+		return nil
+	}
+
+	lineStarts := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	// Function start offsets for position matching
+	fnOffsets := make([]int, len(functions))
+	for i, fn := range functions {
+		fnOffsets[i] = -1
+		if fn.StartLine >= 1 && fn.StartLine <= len(lineStarts) {
+			fnOffsets[i] = lineStarts[fn.StartLine-1] + fn.StartCol - 1
+		}
+	}
+	lineOf := func(offset int) int {
+		lo, hi := 0, len(lineStarts)-1
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if lineStarts[mid] <= offset {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		return lo + 1
+	}
+
+	var edges []GraphQLResolverEdge
+	seen := make(map[string]bool)
+
+	bindType := func(typeName string, open int) {
+		close := jsMatchBrace(content, open)
+		for _, entry := range jsObjectEntries(content, open, close) {
+			key := typeName + "." + entry.key
+			if seen[key] || strings.HasPrefix(entry.key, "__") {
+				continue // __resolveType, __isTypeOf are not fields
+			}
+			edge := GraphQLResolverEdge{
+				TypeName:  typeName,
+				FieldName: entry.key,
+				Framework: GraphQLFrameworkApollo,
+				FilePath:  filePath,
+				Line:      lineOf(entry.keyOffset),
+			}
+			start, end := entry.valueStart, entry.valueEnd
+			value := strings.TrimSpace(content[start:end])
+
+			// Subscription field: { subscribe: ..., resolve: ... }
+			if strings.HasPrefix(value, "{") {
+				inner := start + strings.Index(content[start:end], "{")
+				var subscribe, resolve *jsObjectEntry
+				innerEntries := jsObjectEntries(content, inner, jsMatchBrace(content, inner))
+				for i := range innerEntries {
+					switch innerEntries[i].key {
+					case "resolve":
+						resolve = &innerEntries[i]
+					case "subscribe":
+						subscribe = &innerEntries[i]
+					}
+				}
+				switch {
+				case resolve != nil:
+					start, end = resolve.valueStart, resolve.valueEnd
+				case subscribe != nil:
+					start, end = subscribe.valueStart, subscribe.valueEnd
+				default:
+					continue
+				}
+				value = strings.TrimSpace(content[start:end])
+			}
+
+			if jsIdentRefPattern.MatchString(value) {
+				edge.FunctionName = value[strings.LastIndex(value, ".")+1:]
+			} else {
+				// Outermost function starting inside the value (arrow, function
+				// expression, method shorthand, or a wrapped resolver)
+				best := -1
+				for i, off := range fnOffsets {
+					if off >= start && off < end && (best < 0 || off < fnOffsets[best]) {
+						best = i
+					}
+				}
+				if best < 0 {
+					continue
+				}
+				edge.FunctionID = functions[best].ID
+			}
+			seen[key] = true
+			edges = append(edges, edge)
+		}
+	}
+
+	bindMap := func(open int) {
+		close := jsMatchBrace(content, open)
+		for _, entry := range jsObjectEntries(content, open, close) {
+			if !isCapitalized(entry.key) {
+				continue
+			}
+			value := strings.TrimLeft(content[entry.valueStart:entry.valueEnd], " \t\r\n")
+			if strings.HasPrefix(value, "{") {
+				bindType(entry.key, entry.valueEnd-len(value))
+			}
+		}
+	}
+
+	maps := make(map[int]bool)
+	for _, m := range apolloRootKeyPattern.FindAllStringIndex(content, -1) {
+		if open := jsEnclosingBrace(content, m[0]); open >= 0 && !maps[open] {
+			maps[open] = true
+			bindMap(open)
+		}
+	}
+	for _, m := range apolloMapVarPattern.FindAllStringSubmatchIndex(content, -1) {
+		name := content[m[2]:m[3]]
+		open := m[1] - 1
+		switch {
+		case graphQLDefaultRoots[name] != "":
+			bindType(name, open)
+		case strings.HasSuffix(strings.ToLower(name), "resolvers") && !maps[open]:
+			maps[open] = true
+			bindMap(open)
+		}
+	}
+
+	return edges
+}
+
+// LinkGraphQLResolvers returns field -> resolver edges for the whole repository.
+//
+// Apollo edges from extractApolloResolvers are kept; those referencing a
+// function by name are resolved to a function in the same file, or to the only
+// JavaScript/TypeScript function with that name.
+//
+// gqlgen resolvers are methods on "<type>Resolver" receivers (queryResolver.Orders
+// resolves Query.orders). When the SDL declares the type, the method must match
+// one of its fields (ignoring case and underscores). Without the SDL, only
+// Query, Mutation and Subscription resolvers are linked, with the field name
+// derived from the method name.
+func LinkGraphQLResolvers(fields []GraphQLFieldEntity, functions []FunctionEntity, apollo []GraphQLResolverEdge) []GraphQLResolverEdge {
+	typeNames := make(map[string]string)
+	fieldNames := make(map[string]map[string]string)
+	for _, f := range fields {
+		typeNames[strings.ToLower(f.TypeName)] = f.TypeName
+		if fieldNames[f.TypeName] == nil {
+			fieldNames[f.TypeName] = make(map[string]string)
+		}
+		fieldNames[f.TypeName][normalizeGraphQLName(f.Name)] = f.Name
+	}
+
+	var edges []GraphQLResolverEdge
+	seen := make(map[string]bool)
+	add := func(e GraphQLResolverEdge) {
+		key := e.TypeName + "|" + e.FieldName + "|" + e.FunctionID
+		if e.FunctionID == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		edges = append(edges, e)
+	}
+
+	jsByName := make(map[string][]FunctionEntity)
+	for _, fn := range functions {
+		if strings.HasSuffix(fn.FilePath, ".go") {
+			add(gqlgenResolverEdge(fn, typeNames, fieldNames))
+			continue
+		}
+		if lang := detectLanguageFromPath(fn.FilePath); lang == "javascript" || lang == "typescript" {
+			jsByName[fn.Name] = append(jsByName[fn.Name], fn)
+		}
+	}
+
+	for _, e := range apollo {
+		if e.FunctionID == "" && e.FunctionName != "" {
+			candidates := jsByName[e.FunctionName]
+			for _, fn := range candidates {
+				if fn.FilePath == e.FilePath {
+					e.FunctionID = fn.ID
+					break
+				}
+			}
+			if e.FunctionID == "" && len(candidates) == 1 {
+				e.FunctionID = candidates[0].ID
+			}
+		}
+		add(e)
+	}
+
+	return edges
+}
+
+// gqlgenResolverEdge returns the resolver edge for a gqlgen resolver method,
+// or an edge without FunctionID if fn is not one.
+func gqlgenResolverEdge(fn FunctionEntity, typeNames map[string]string, fieldNames map[string]map[string]string) GraphQLResolverEdge {
+	recv, method, ok := strings.Cut(fn.Name, ".")
+	base := strings.TrimSuffix(recv, "Resolver")
+	if !ok || base == recv || base == "" || !isCapitalized(method) {
+		return GraphQLResolverEdge{}
+	}
+
+	typeName, known := typeNames[strings.ToLower(base)]
+	var fieldName string
+	if known {
+		fieldName = fieldNames[typeName][normalizeGraphQLName(method)]
+		if fieldName == "" {
+			return GraphQLResolverEdge{} // helper method, not a field resolver
+		}
+	} else {
+		// Without the SDL, only root resolvers are unambiguous (dnsResolver.Lookup is not GraphQL)
+		typeName = string(unicode.ToUpper(rune(base[0]))) + base[1:]
+		if graphQLDefaultRoots[typeName] == "" {
+			return GraphQLResolverEdge{}
+		}
+		fieldName = goNameToGraphQL(method)
+	}
+
+	return GraphQLResolverEdge{
+		TypeName:   typeName,
+		FieldName:  fieldName,
+		FunctionID: fn.ID,
+		Framework:  GraphQLFrameworkGqlgen,
+		FilePath:   fn.FilePath,
+		Line:       fn.StartLine,
+	}
+}
+
+// normalizeGraphQLName folds case and underscores so that gqlgen's Go method
+// names match SDL field names: "UserID" and "userId", "CreatedAt" and "created_at".
+func normalizeGraphQLName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// goNameToGraphQL guesses the SDL field name for a Go method: "Orders" -> "orders",
+// "ID" -> "id", "UserID" -> "userID".
+func goNameToGraphQL(name string) string {
+	if strings.ToUpper(name) == name {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func isCapitalized(s string) bool {
+	return s != "" && s[0] >= 'A' && s[0] <= 'Z'
+}
+
+// jsObjectEntries splits the JS object literal between content[open] ("{") and
+// content[close] ("}") into its top-level entries. Spread and computed entries
+// are skipped.
+func jsObjectEntries(content string, open, close int) []jsObjectEntry {
+	var entries []jsObjectEntry
+	if open < 0 || close <= open || close >= len(content) {
+		return nil
+	}
+
+	emit := func(start, end int) {
+		text := content[start:end]
+		trimmed := strings.TrimLeft(text, " \t\r\n")
+		entryStart := start + len(text) - len(trimmed)
+		rest := trimmed
+		for _, prefix := range []string{"async ", "get ", "set ", "*"} {
+			rest = strings.TrimLeft(strings.TrimPrefix(rest, prefix), " \t")
+		}
+		if rest == "" || strings.HasPrefix(rest, "...") || strings.HasPrefix(rest, "[") {
+			return
+		}
+
+		key := ""
+		keyLen := 0
+		if rest[0] == '"' || rest[0] == '\'' {
+			if q := strings.IndexByte(rest[1:], rest[0]); q >= 0 {
+				key = rest[1 : q+1]
+				keyLen = q + 2
+			}
+		} else {
+			for keyLen < len(rest) && (rest[keyLen] == '_' || rest[keyLen] == '$' || unicode.IsLetter(rune(rest[keyLen])) || unicode.IsDigit(rune(rest[keyLen]))) {
+				keyLen++
+			}
+			key = rest[:keyLen]
+		}
+		if key == "" {
+			return
+		}
+		keyOffset := end - len(rest)
+		after := strings.TrimLeft(rest[keyLen:], " \t\r\n")
+		switch {
+		case strings.HasPrefix(after, ":"):
+			valueStart := end - len(after) + 1
+			entries = append(entries, jsObjectEntry{key: key, keyOffset: keyOffset, valueStart: valueStart, valueEnd: end})
+		case strings.HasPrefix(after, "(") || strings.HasPrefix(after, "<"):
+			entries = append(entries, jsObjectEntry{key: key, keyOffset: keyOffset, valueStart: entryStart, valueEnd: end})
+		case after == "":
+			// Shorthand property: { orders } references a function named orders
+			entries = append(entries, jsObjectEntry{key: key, keyOffset: keyOffset, valueStart: keyOffset, valueEnd: keyOffset + keyLen})
+		}
+	}
+
+	depth := 0
+	start := open + 1
+	for i := open + 1; i < close; i++ {
+		switch c := content[i]; c {
+		case '"', '\'', '`':
+			i = jsSkipString(content, i)
+		case '/':
+			i = jsSkipComment(content, i)
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				emit(start, i)
+				start = i + 1
+			}
+		}
+	}
+	emit(start, close)
+
+	return entries
+}
+
+// jsMatchBrace returns the offset of the bracket closing content[open], skipping
+// strings and comments, or len(content)-1 if unbalanced.
+func jsMatchBrace(content string, open int) int {
+	depth := 0
+	for i := open; i < len(content); i++ {
+		switch content[i] {
+		case '"', '\'', '`':
+			i = jsSkipString(content, i)
+		case '/':
+			i = jsSkipComment(content, i)
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(content) - 1
+}
+
+// jsEnclosingBrace returns the offset of the "{" opening the object that
+// contains offset, or -1. Strings are not skipped when scanning backwards.
+func jsEnclosingBrace(content string, offset int) int {
+	depth := 0
+	for i := offset; i >= 0; i-- {
+		switch content[i] {
+		case '}':
+			depth++
+		case '{':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// jsSkipString returns the offset of the quote closing the string at content[i].
+func jsSkipString(content string, i int) int {
+	quote := content[i]
+	for j := i + 1; j < len(content); j++ {
+		switch content[j] {
+		case '\\':
+			j++
+		case quote:
+			return j
+		case '\n':
+			if quote != '`' {
+				return j
+			}
+		}
+	}
+	return len(content) - 1
+}
+
+// jsSkipComment returns the last offset of the comment starting at content[i],
+// or i if content[i] does not start a comment.
+func jsSkipComment(content string, i int) int {
+	if i+1 >= len(content) {
+		return i
+	}
+	switch content[i+1] {
+	case '/':
+		if end := strings.IndexByte(content[i:], '\n'); end >= 0 {
+			return i + end - 1
+		}
+		return len(content) - 1
+	case '*':
+		if end := strings.Index(content[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 1
+		}
+		return len(content) - 1
+	}
+	return i
+}
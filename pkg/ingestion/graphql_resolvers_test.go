@@ -0,0 +1,135 @@
+package ingestion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findResolverEdge returns the resolver edge for typeName.fieldName or nil.
+func findResolverEdge(edges []GraphQLResolverEdge, typeName, fieldName string) *GraphQLResolverEdge {
+	for i := range edges {
+		if edges[i].TypeName == typeName && edges[i].FieldName == fieldName {
+			return &edges[i]
+		}
+	}
+	return nil
+}
+
+// functionNameByID returns the name of the function with the given ID.
+func functionNameByID(functions []FunctionEntity, id string) string {
+	for _, fn := range functions {
+		if fn.ID == id {
+			return fn.Name
+		}
+	}
+	return ""
+}
+
+// TestExtractApolloResolvers tests resolver map bindings in a parsed TypeScript file.
+func TestExtractApolloResolvers(t *testing.T) {
+	code, err := os.ReadFile("testdata/typescript/resolvers.ts")
+	require.NoError(t, err)
+	tmpFile := filepath.Join(t.TempDir(), "resolvers.ts")
+	require.NoError(t, os.WriteFile(tmpFile, code, 0644))
+
+	parser := NewTreeSitterParser(nil)
+	result, err := parser.ParseFile(FileInfo{Path: "src/resolvers.ts", FullPath: tmpFile, Size: int64(len(code)), Language: "typescript"})
+	require.NoError(t, err)
+
+	edges := result.GraphQLResolvers
+	fnAt := func(edge *GraphQLResolverEdge) *FunctionEntity {
+		for i := range result.Functions {
+			if result.Functions[i].ID == edge.FunctionID {
+				return &result.Functions[i]
+			}
+		}
+		return nil
+	}
+
+	order := findResolverEdge(edges, "Query", "order")
+	require.NotNil(t, order)
+	assert.Equal(t, GraphQLFrameworkApollo, order.Framework)
+	assert.Equal(t, 8, order.Line)
+	require.NotNil(t, fnAt(order), "Inline arrow should bind to the parsed function")
+	assert.Equal(t, 8, fnAt(order).StartLine)
+
+	orders := findResolverEdge(edges, "Query", "orders")
+	require.NotNil(t, orders)
+	assert.Empty(t, orders.FunctionID)
+	assert.Equal(t, "listOrders", orders.FunctionName, "Referenced resolvers are bound by name")
+
+	search := findResolverEdge(edges, "Query", "search")
+	require.NotNil(t, search, "Method shorthand should bind; braces in comments and strings are skipped")
+	require.NotNil(t, fnAt(search))
+	assert.Equal(t, "search", fnAt(search).Name)
+
+	create := findResolverEdge(edges, "Mutation", "createOrder")
+	require.NotNil(t, create)
+	require.NotNil(t, fnAt(create), "Wrapped resolver should bind to the inner function")
+	assert.Equal(t, 16, fnAt(create).StartLine)
+
+	shipped := findResolverEdge(edges, "Subscription", "orderShipped")
+	require.NotNil(t, shipped, "Subscription fields bind to subscribe")
+	require.NotNil(t, fnAt(shipped))
+	assert.Equal(t, 22, fnAt(shipped).StartLine)
+
+	assert.NotNil(t, findResolverEdge(edges, "Order", "customer"), "Type resolvers in the map should bind")
+	assert.Nil(t, findResolverEdge(edges, "Order", "__resolveType"))
+	assert.Nil(t, findResolverEdge(edges, "Time", "TimeScalar"), "Scalar values are not resolver maps")
+
+	cancel := findResolverEdge(edges, "Mutation", "cancelOrder")
+	require.NotNil(t, cancel, "Root resolver variables should bind")
+	assert.NotEmpty(t, cancel.FunctionID)
+}
+
+// TestExtractApolloResolvers_NoResolverMap tests that ordinary objects are ignored.
+func TestExtractApolloResolvers_NoResolverMap(t *testing.T) {
+	content := `const config = { Database: { connect: () => open() } };`
+	functions := []FunctionEntity{{ID: "fn:1", Name: "$arrow_1", FilePath: "config.js", StartLine: 1, StartCol: 45}}
+
+	assert.Empty(t, extractApolloResolvers(content, "config.js", functions))
+}
+
+// TestLinkGraphQLResolvers tests gqlgen method matching and Apollo name resolution.
+func TestLinkGraphQLResolvers(t *testing.T) {
+	fields := []GraphQLFieldEntity{
+		{TypeName: "Query", Name: "orders"},
+		{TypeName: "Query", Name: "userId"},
+		{TypeName: "Order", Name: "created_at"},
+	}
+	functions := []FunctionEntity{
+		{ID: "go:1", Name: "queryResolver.Orders", FilePath: "graph/schema.resolvers.go", StartLine: 10},
+		{ID: "go:2", Name: "queryResolver.UserID", FilePath: "graph/schema.resolvers.go", StartLine: 20},
+		{ID: "go:3", Name: "orderResolver.CreatedAt", FilePath: "graph/order.resolvers.go", StartLine: 5},
+		{ID: "go:4", Name: "queryResolver.loadUser", FilePath: "graph/schema.resolvers.go", StartLine: 30},
+		{ID: "go:5", Name: "orderResolver.Total", FilePath: "graph/order.resolvers.go", StartLine: 40},
+		{ID: "go:6", Name: "mutationResolver.CreateOrder", FilePath: "graph/schema.resolvers.go", StartLine: 50},
+		{ID: "go:7", Name: "dnsResolver.Lookup", FilePath: "internal/net/dns.go", StartLine: 1},
+		{ID: "go:8", Name: "Resolver.Query", FilePath: "graph/resolver.go", StartLine: 1},
+		{ID: "js:1", Name: "listOrders", FilePath: "src/orders.ts", StartLine: 3},
+		{ID: "js:2", Name: "listUsers", FilePath: "src/users.ts", StartLine: 3},
+		{ID: "js:3", Name: "listUsers", FilePath: "src/admin/users.ts", StartLine: 3},
+	}
+	apollo := []GraphQLResolverEdge{
+		{TypeName: "Query", FieldName: "orders", FunctionName: "listOrders", Framework: GraphQLFrameworkApollo, FilePath: "src/resolvers.ts"},
+		{TypeName: "Query", FieldName: "users", FunctionName: "listUsers", Framework: GraphQLFrameworkApollo, FilePath: "src/resolvers.ts"},
+	}
+
+	edges := LinkGraphQLResolvers(fields, functions, apollo)
+
+	linked := make(map[string]string)
+	for _, e := range edges {
+		linked[e.TypeName+"."+e.FieldName+"/"+e.Framework] = functionNameByID(functions, e.FunctionID)
+	}
+	assert.Equal(t, map[string]string{
+		"Query.orders/gqlgen":         "queryResolver.Orders",
+		"Query.userId/gqlgen":         "queryResolver.UserID",
+		"Order.created_at/gqlgen":     "orderResolver.CreatedAt",
+		"Mutation.createOrder/gqlgen": "mutationResolver.CreateOrder",
+		"Query.orders/apollo":         "listOrders",
+	}, linked, "Helper methods, unknown fields, non-GraphQL resolvers and ambiguous names are not linked")
+}
@@ -13,6 +13,14 @@ var interfaceMethodPattern = regexp.MustCompile(`(?m)^\s*([A-Z][a-zA-Z0-9_]*)\s*
 // Captures lines like "io.Reader", "Writer", or "fmt.Stringer" (bare type on its own line).
 var embeddedInterfacePattern = regexp.MustCompile(`(?m)^\s+(\w+(?:\.\w+)?)\s*$`)
 
+// typeSetTermPattern matches the type set terms of a constraint interface body,
+// e.g. "~int | ~float64", "~string" or "int32 | MyInt".
+var typeSetTermPattern = regexp.MustCompile(`^~?\w+(?:\.\w+)?(?:\s*\|\s*~?\w+(?:\.\w+)?)*$`)
+
+// underlyingTypePattern captures the underlying type of a type definition such
+// as "MyInt int" or "Celsius[T any] float64".
+var underlyingTypePattern = regexp.MustCompile(`^\s*(?:type\s+)?\w+(?:\[[^\]]*\])?\s+=?\s*(\w+)\s*$`)
+
 // stdlibInterfaceMethods maps common Go stdlib interface names to their methods.
 // This allows resolving embedded stdlib interfaces (e.g., io.Reader) that aren't
 // in the project's index. Only includes commonly-used interfaces.
@@ -30,6 +38,10 @@ var stdlibInterfaceMethods = map[string][]string{
 // BuildImplementsIndex determines which concrete types implement which interfaces
 // by matching method sets. A concrete type implements an interface if it has all
 // methods declared by that interface.
+//
+// Constraint interfaces with a type set (~int | ~float64) are satisfied by the
+// types of the set that also have all methods of the interface: a term ~int
+// matches any type defined as int, a term MyInt the type MyInt itself.
 func BuildImplementsIndex(types []TypeEntity, functions []FunctionEntity) []ImplementsEdge {
 	// 1. Collect interfaces and their required methods
 	interfaces := extractInterfaceMethods(types)
@@ -46,6 +58,10 @@ func BuildImplementsIndex(types []TypeEntity, functions []FunctionEntity) []Impl
 	// 4. Match: find concrete types that implement each interface
 	var edges []ImplementsEdge
 	for _, iface := range interfaces {
+		if len(iface.terms) > 0 {
+			edges = append(edges, typeSetImplementations(iface, types, typeMethods, interfaceNames)...)
+			continue
+		}
 		if len(iface.methods) == 0 {
 			continue
 		}
@@ -70,6 +86,76 @@ func BuildImplementsIndex(types []TypeEntity, functions []FunctionEntity) []Impl
 type interfaceInfo struct {
 	name    string
 	methods []string
+	terms   []typeSetTerm // Type set of a constraint interface, nil for method-only interfaces
+}
+
+// typeSetTerm is one term of a constraint's type set: int or ~int.
+type typeSetTerm struct {
+	name  string
+	tilde bool
+}
+
+// typeSetImplementations returns the types satisfying a constraint interface:
+// types in its type set that have all of its methods.
+func typeSetImplementations(iface interfaceInfo, types []TypeEntity, typeMethods map[string]map[string]bool, interfaceNames map[string]bool) []ImplementsEdge {
+	var edges []ImplementsEdge
+	seen := make(map[string]bool)
+	for _, t := range types {
+		if interfaceNames[t.Name] || seen[t.Name] || !inTypeSet(t, iface.terms) {
+			continue
+		}
+		if !hasAllMethods(typeMethods[t.Name], iface.methods) {
+			continue
+		}
+		seen[t.Name] = true
+		edges = append(edges, ImplementsEdge{
+			TypeName:      t.Name,
+			InterfaceName: iface.name,
+			FilePath:      t.FilePath,
+		})
+	}
+	return edges
+}
+
+// inTypeSet reports whether a type belongs to the type set formed by terms.
+func inTypeSet(t TypeEntity, terms []typeSetTerm) bool {
+	underlying := ""
+	if t.Kind == "type_alias" {
+		if m := underlyingTypePattern.FindStringSubmatch(t.CodeText); m != nil {
+			underlying = m[1]
+		}
+	}
+	for _, term := range terms {
+		if term.name == t.Name || (term.tilde && term.name == underlying) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractTypeSetTerms parses the type set terms of a constraint interface from
+// its source: lines such as "~int | ~float64", "~string" or "int | MyInt".
+// A bare name on its own line is an embedded interface unless it is a
+// predeclared type.
+func extractTypeSetTerms(codeText string) []typeSetTerm {
+	var terms []typeSetTerm
+	for _, line := range strings.Split(codeText, "\n") {
+		line = strings.TrimSpace(line)
+		if !typeSetTermPattern.MatchString(line) {
+			continue
+		}
+		if !strings.ContainsAny(line, "~|") && (!isGoBuiltinType(line) || line == "any" || line == "error") {
+			continue
+		}
+		for _, part := range strings.Split(line, "|") {
+			part = strings.TrimSpace(part)
+			terms = append(terms, typeSetTerm{
+				name:  stripPackagePrefix(strings.TrimPrefix(part, "~")),
+				tilde: strings.HasPrefix(part, "~"),
+			})
+		}
+	}
+	return terms
 }
 
 // extractInterfaceMethods extracts method names from interface type definitions.
@@ -107,6 +193,7 @@ func extractInterfaceMethods(types []TypeEntity) []interfaceInfo {
 		result = append(result, interfaceInfo{
 			name:    t.Name,
 			methods: methodList,
+			terms:   extractTypeSetTerms(t.CodeText),
 		})
 	}
 
@@ -156,6 +243,8 @@ func inheritMethods(target map[string]bool, name string, directMethods map[strin
 
 // buildTypeMethodSets builds a map of concrete type → set of method names
 // from function entities with receiver syntax (e.g., "CozoDB.Write").
+// Generic receivers count towards the generic type: "List[T].Get" and
+// "List<T>.get" are methods of List.
 func buildTypeMethodSets(functions []FunctionEntity) map[string]map[string]bool {
 	typeMethods := make(map[string]map[string]bool)
 
@@ -166,6 +255,9 @@ func buildTypeMethodSets(functions []FunctionEntity) map[string]map[string]bool
 		parts := strings.SplitN(fn.Name, ".", 2)
 		typeName := parts[0]
 		methodName := parts[1]
+		if i := strings.IndexAny(typeName, "[<"); i > 0 {
+			typeName = typeName[:i]
+		}
 
 		if typeMethods[typeName] == nil {
 			typeMethods[typeName] = make(map[string]bool)
@@ -188,9 +280,9 @@ func hasAllMethods(methods map[string]bool, required []string) bool {
 
 // typeFilePath finds the file path for a concrete type from its methods.
 func typeFilePath(typeName string, functions []FunctionEntity) string {
-	prefix := typeName + "."
 	for _, fn := range functions {
-		if strings.HasPrefix(fn.Name, prefix) {
+		if strings.HasPrefix(fn.Name, typeName+".") || strings.HasPrefix(fn.Name, typeName+"[") ||
+			strings.HasPrefix(fn.Name, typeName+"<") {
 			return fn.FilePath
 		}
 	}
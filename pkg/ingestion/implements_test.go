@@ -203,3 +203,71 @@ func TestBuildImplementsIndex_MultipleInterfaces(t *testing.T) {
 	assert.True(t, ifaceMap["Writer"])
 	assert.True(t, ifaceMap["Flusher"])
 }
+
+func TestBuildImplementsIndex_GenericReceiver(t *testing.T) {
+	types := []TypeEntity{
+		{
+			Name:     "Getter",
+			Kind:     "interface",
+			CodeText: "Getter interface {\n\tGet() any\n\tLen() int\n}",
+		},
+	}
+	functions := []FunctionEntity{
+		{Name: "List[T].Get", FilePath: "coll/list.go"},
+		{Name: "List[T].Len", FilePath: "coll/list.go"},
+		{Name: "Stack<T>.Get", FilePath: "coll/stack.ts"},
+		{Name: "Stack<T>.Len", FilePath: "coll/stack.ts"},
+	}
+
+	edges := BuildImplementsIndex(types, functions)
+
+	got := make(map[string]string)
+	for _, e := range edges {
+		got[e.TypeName] = e.FilePath
+	}
+	assert.Equal(t, map[string]string{"List": "coll/list.go", "Stack": "coll/stack.ts"}, got)
+}
+
+func TestBuildImplementsIndex_ConstraintTypeSet(t *testing.T) {
+	types := []TypeEntity{
+		{
+			Name:     "Number",
+			Kind:     "interface",
+			CodeText: "Number interface {\n\t~int | ~float64\n}",
+		},
+		{
+			Name:     "Label",
+			Kind:     "interface",
+			CodeText: "Label interface {\n\t~string\n\tString() string\n}",
+		},
+		{Name: "Celsius", Kind: "type_alias", FilePath: "units/temp.go", CodeText: "Celsius float64"},
+		{Name: "Count", Kind: "type_alias", FilePath: "units/count.go", CodeText: "Count int"},
+		{Name: "Name", Kind: "type_alias", FilePath: "units/name.go", CodeText: "Name string"},
+		{Name: "Code", Kind: "type_alias", FilePath: "units/code.go", CodeText: "Code string"},
+		{Name: "Point", Kind: "struct", FilePath: "geo/point.go", CodeText: "Point struct {\n\tX int\n}"},
+	}
+	functions := []FunctionEntity{
+		{Name: "Name.String", FilePath: "units/name.go"},
+	}
+
+	edges := BuildImplementsIndex(types, functions)
+
+	got := make(map[string][]string)
+	for _, e := range edges {
+		got[e.InterfaceName] = append(got[e.InterfaceName], e.TypeName)
+	}
+	assert.ElementsMatch(t, []string{"Celsius", "Count"}, got["Number"])
+	// Code is in the type set but lacks String()
+	assert.Equal(t, []string{"Name"}, got["Label"])
+}
+
+func TestExtractTypeSetTerms(t *testing.T) {
+	code := "Ordered interface {\n\t~int | ~int64 | MyFloat\n\tfmt.Stringer\n\tcomparable\n\tString() string\n}"
+
+	assert.Equal(t, []typeSetTerm{
+		{name: "int", tilde: true},
+		{name: "int64", tilde: true},
+		{name: "MyFloat"},
+	}, extractTypeSetTerms(code))
+	assert.Nil(t, extractTypeSetTerms("Writer interface {\n\tio.Writer\n\tFlush() error\n}"))
+}
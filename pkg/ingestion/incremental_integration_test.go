@@ -27,6 +27,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -680,6 +682,352 @@ func OldFunction() {
 	t.Log("Renamed file test passed!")
 }
 
+// TestIncrementalIndexing_GraphQLResolvers verifies that resolvers are
+// relinked when only the schema or only the resolver file changes.
+func TestIncrementalIndexing_GraphQLResolvers(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "testrepo")
+	runGit(t, "", "init", repoDir)
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	writeFile(t, filepath.Join(repoDir, "schema.graphqls"), "type Query {\n  orders: [String!]!\n}\n")
+	writeFile(t, filepath.Join(repoDir, "graph", "resolver.go"), `package graph
+
+type queryResolver struct{}
+
+func (r *queryResolver) Orders() ([]string, error) { return nil, nil }
+
+func (r *queryResolver) Users() ([]string, error) { return nil, nil }
+`)
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "Initial commit")
+
+	pipeline := newIncrementalTestPipeline(t, repoDir, "test-graphql")
+	ctx := context.Background()
+	resolvers := func() []string {
+		t.Helper()
+		result, err := pipeline.backend.Query(ctx, `?[field, function_id] := *cie_graphql_resolver { type_name: "Query", field_name: field, function_id }`)
+		if err != nil {
+			t.Fatalf("query resolvers: %v", err)
+		}
+		var fields []string
+		for _, row := range result.Rows {
+			field, _ := row[0].(string)
+			id, _ := row[1].(string)
+			exists, err := pipeline.backend.Query(ctx, `?[id] := *cie_function { id }, id = "`+id+`"`)
+			if err != nil || len(exists.Rows) != 1 {
+				t.Errorf("resolver of Query.%s points to a missing function %s", field, id)
+			}
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		return fields
+	}
+
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if got := resolvers(); !reflect.DeepEqual(got, []string{"orders"}) {
+		t.Fatalf("after full run: resolvers = %v, want [orders]", got)
+	}
+
+	// Schema only: orders is removed, users is added
+	writeFile(t, filepath.Join(repoDir, "schema.graphqls"), "type Query {\n  users: [String!]!\n}\n")
+	runGit(t, repoDir, "commit", "-am", "Replace orders with users")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("schema run failed: %v", err)
+	}
+	if got := resolvers(); !reflect.DeepEqual(got, []string{"users"}) {
+		t.Errorf("after schema change: resolvers = %v, want [users]", got)
+	}
+
+	// Resolver only: the methods move, so their IDs change
+	writeFile(t, filepath.Join(repoDir, "graph", "resolver.go"), `package graph
+
+// queryResolver resolves the Query type.
+type queryResolver struct{}
+
+func (r *queryResolver) Users() ([]string, error) { return nil, nil }
+`)
+	runGit(t, repoDir, "commit", "-am", "Drop Orders")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("resolver run failed: %v", err)
+	}
+	if got := resolvers(); !reflect.DeepEqual(got, []string{"users"}) {
+		t.Errorf("after resolver change: resolvers = %v, want [users]", got)
+	}
+}
+
+// TestIncrementalIndexing_DocLinks verifies that doc links follow the code
+// they mention when only the code changes, and that a changed doc links to
+// unchanged code.
+func TestIncrementalIndexing_DocLinks(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "testrepo")
+	runGit(t, "", "init", repoDir)
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	writeFile(t, filepath.Join(repoDir, "docs", "guide.md"), "# Guide\n\nCall `Hello` to greet.\n")
+	writeFile(t, filepath.Join(repoDir, "hello.go"), "package main\n\nfunc Hello() {\n\tprintln(\"hello\")\n}\n")
+	writeFile(t, filepath.Join(repoDir, "utils.go"), "package main\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "Initial commit")
+
+	pipeline := newIncrementalTestPipeline(t, repoDir, "test-doc-links")
+	ctx := context.Background()
+	linked := func() []string {
+		t.Helper()
+		result, err := pipeline.backend.Query(ctx, `?[name] := *cie_doc_link { target_id }, *cie_function { id: target_id, name }`)
+		if err != nil {
+			t.Fatalf("query doc links: %v", err)
+		}
+		var names []string
+		for _, row := range result.Rows {
+			name, _ := row[0].(string)
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if got := linked(); !reflect.DeepEqual(got, []string{"Hello"}) {
+		t.Fatalf("after full run: linked = %v, want [Hello]", got)
+	}
+
+	// Code only: Hello moves, so its ID changes
+	writeFile(t, filepath.Join(repoDir, "hello.go"), "package main\n\n// Hello greets.\nfunc Hello() {\n\tprintln(\"hello\")\n}\n")
+	runGit(t, repoDir, "commit", "-am", "Document Hello")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("code run failed: %v", err)
+	}
+	if got := linked(); !reflect.DeepEqual(got, []string{"Hello"}) {
+		t.Errorf("after code change: linked = %v, want [Hello]", got)
+	}
+
+	// Doc only: the new mention is of unchanged code
+	writeFile(t, filepath.Join(repoDir, "docs", "guide.md"), "# Guide\n\nCall `Hello` to greet and `Add` to sum.\n")
+	runGit(t, repoDir, "commit", "-am", "Mention Add")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("doc run failed: %v", err)
+	}
+	if got := linked(); !reflect.DeepEqual(got, []string{"Add", "Hello"}) {
+		t.Errorf("after doc change: linked = %v, want [Add Hello]", got)
+	}
+}
+
+func TestIncrementalIndexing_TypeRefs(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "testrepo")
+	runGit(t, "", "init", repoDir)
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	writeFile(t, filepath.Join(repoDir, "config.go"), "package main\n\ntype Config struct {\n\tName string\n}\n")
+	writeFile(t, filepath.Join(repoDir, "load.go"), "package main\n\nfunc Load(name string) Config {\n\treturn Config{Name: name}\n}\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "Initial commit")
+
+	pipeline := newIncrementalTestPipeline(t, repoDir, "test-type-refs")
+	ctx := context.Background()
+	referencing := func() []string {
+		t.Helper()
+		result, err := pipeline.backend.Query(ctx, `?[name, kind] := *cie_type_ref { from_id, type_id, kind }, *cie_function { id: from_id, name }, *cie_type { id: type_id, name: "Config" }`)
+		if err != nil {
+			t.Fatalf("query type refs: %v", err)
+		}
+		var refs []string
+		for _, row := range result.Rows {
+			name, _ := row[0].(string)
+			kind, _ := row[1].(string)
+			refs = append(refs, name+" "+kind)
+		}
+		sort.Strings(refs)
+		return refs
+	}
+	want := []string{"Load literal", "Load return"}
+
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if got := referencing(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after full run: refs = %v, want %v", got, want)
+	}
+
+	// The type's file only: Config moves, so its ID changes, and the
+	// references from the unchanged load.go must follow it
+	writeFile(t, filepath.Join(repoDir, "config.go"), "package main\n\n// Config is the configuration.\ntype Config struct {\n\tName string\n}\n")
+	runGit(t, repoDir, "commit", "-am", "Document Config")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("type run failed: %v", err)
+	}
+	if got := referencing(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after type change: refs = %v, want %v", got, want)
+	}
+
+	// The referencing file only: its references resolve to the unchanged type
+	writeFile(t, filepath.Join(repoDir, "load.go"), "package main\n\n// Load builds a Config.\nfunc Load(name string) Config {\n\treturn Config{Name: name}\n}\n")
+	runGit(t, repoDir, "commit", "-am", "Document Load")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("reference run failed: %v", err)
+	}
+	if got := referencing(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after reference change: refs = %v, want %v", got, want)
+	}
+}
+
+func TestIncrementalIndexing_FuncRefs(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "testrepo")
+	runGit(t, "", "init", repoDir)
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	writeFile(t, filepath.Join(repoDir, "handlers.go"), "package main\n\nfunc List() {}\n")
+	writeFile(t, filepath.Join(repoDir, "router.go"), "package main\n\nfunc Register(h func()) {}\n\nfunc Routes() {\n\tRegister(List)\n}\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "Initial commit")
+
+	pipeline := newIncrementalTestPipeline(t, repoDir, "test-func-refs")
+	ctx := context.Background()
+	referenced := func() []string {
+		t.Helper()
+		result, err := pipeline.backend.Query(ctx, `?[from, to] := *cie_func_ref { from_id, to_id }, *cie_function { id: from_id, name: from }, *cie_function { id: to_id, name: to }`)
+		if err != nil {
+			t.Fatalf("query function refs: %v", err)
+		}
+		var refs []string
+		for _, row := range result.Rows {
+			from, _ := row[0].(string)
+			to, _ := row[1].(string)
+			refs = append(refs, from+" -> "+to)
+		}
+		sort.Strings(refs)
+		return refs
+	}
+	want := []string{"Routes -> List"}
+
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if got := referenced(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after full run: refs = %v, want %v", got, want)
+	}
+
+	// The handler's file only: List moves, so its ID changes, and the
+	// reference from the unchanged router.go must follow it
+	writeFile(t, filepath.Join(repoDir, "handlers.go"), "package main\n\n// List lists.\nfunc List() {}\n")
+	runGit(t, repoDir, "commit", "-am", "Document List")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("handler run failed: %v", err)
+	}
+	if got := referenced(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after handler change: refs = %v, want %v", got, want)
+	}
+
+	// The router only: its reference resolves to the unchanged handler
+	writeFile(t, filepath.Join(repoDir, "router.go"), "package main\n\nfunc Register(h func()) {}\n\n// Routes registers the handlers.\nfunc Routes() {\n\tRegister(List)\n}\n")
+	runGit(t, repoDir, "commit", "-am", "Document Routes")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("router run failed: %v", err)
+	}
+	if got := referenced(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after router change: refs = %v, want %v", got, want)
+	}
+}
+
+func TestIncrementalIndexing_Instantiations(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "testrepo")
+	runGit(t, "", "init", repoDir)
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	generics := "package main\n\ntype Stack[T any] struct {\n\titems []T\n}\n\nfunc Keys[K comparable, V any](m map[K]V) []K {\n\treturn nil\n}\n"
+	writeFile(t, filepath.Join(repoDir, "generics.go"), generics)
+	writeFile(t, filepath.Join(repoDir, "use.go"), "package main\n\nfunc Use() []string {\n\tvar s Stack[int]\n\t_ = s\n\treturn Keys[string, int](nil)\n}\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "Initial commit")
+
+	pipeline := newIncrementalTestPipeline(t, repoDir, "test-instantiations")
+	ctx := context.Background()
+	instantiated := func() []string {
+		t.Helper()
+		result, err := pipeline.backend.Query(ctx, `
+			?[target, type_args] := *cie_instantiation { target_id, type_args }, *cie_function { id: target_id, name: target }
+			?[target, type_args] := *cie_instantiation { target_id, type_args }, *cie_type { id: target_id, name: target }`)
+		if err != nil {
+			t.Fatalf("query instantiations: %v", err)
+		}
+		var insts []string
+		for _, row := range result.Rows {
+			target, _ := row[0].(string)
+			typeArgs, _ := row[1].(string)
+			insts = append(insts, target+"["+typeArgs+"]")
+		}
+		sort.Strings(insts)
+		return insts
+	}
+	want := []string{"Keys[string, int]", "Stack[int]"}
+
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if got := instantiated(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after full run: instantiations = %v, want %v", got, want)
+	}
+
+	// The generics' file only: Stack and Keys move, so their IDs change, and
+	// the instantiations in the unchanged use.go must follow them
+	writeFile(t, filepath.Join(repoDir, "generics.go"), "// Generic helpers.\n"+generics)
+	runGit(t, repoDir, "commit", "-am", "Document generics")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("generics run failed: %v", err)
+	}
+	if got := instantiated(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after generics change: instantiations = %v, want %v", got, want)
+	}
+
+	// The instantiating file only: it resolves to the unchanged generics
+	writeFile(t, filepath.Join(repoDir, "use.go"), "package main\n\n// Use uses the generics.\nfunc Use() []string {\n\tvar s Stack[int]\n\t_ = s\n\treturn Keys[string, int](nil)\n}\n")
+	runGit(t, repoDir, "commit", "-am", "Document Use")
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("use run failed: %v", err)
+	}
+	if got := instantiated(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after use change: instantiations = %v, want %v", got, want)
+	}
+}
+
+// newIncrementalTestPipeline creates a pipeline with an in-memory database
+// and git-based incremental indexing for repoDir.
+func newIncrementalTestPipeline(t *testing.T, repoDir, projectID string) *LocalPipeline {
+	t.Helper()
+	cfg := Config{
+		ProjectID:  projectID,
+		RepoSource: RepoSource{Type: "local_path", Value: repoDir},
+		IngestionConfig: IngestionConfig{
+			LocalDataDir:        filepath.Join(t.TempDir(), "data"),
+			LocalEngine:         "mem",
+			EmbeddingProvider:   "mock",
+			EmbeddingDimensions: 384,
+			MaxFileSizeBytes:    1048576,
+			ExcludeGlobs:        []string{".git/**"},
+			UseGitDelta:         true,
+			Concurrency: ConcurrencyConfig{
+				ParseWorkers: 2,
+				EmbedWorkers: 2,
+			},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	pipeline, err := NewLocalPipeline(cfg, logger)
+	if err != nil {
+		t.Fatalf("failed to create pipeline: %v", err)
+	}
+	t.Cleanup(func() { _ = pipeline.Close() })
+	return pipeline
+}
+
 // runGit executes a git command in the specified directory.
 func runGit(t *testing.T, dir string, args ...string) {
 	t.Helper()
@@ -25,8 +25,10 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"path"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -118,16 +120,34 @@ type IngestionResult struct {
 
 // parseFilesResult holds the aggregated results from parallel parsing.
 type parseFilesResult struct {
-	files           []FileEntity
-	functions       []FunctionEntity
-	types           []TypeEntity
-	fields          []FieldEntity
-	defines         []DefinesEdge
-	definesTypes    []DefinesTypeEdge
-	calls           []CallsEdge
-	imports         []ImportEntity
-	unresolvedCalls []UnresolvedCall
-	packageNames    map[string]string
+	files            []FileEntity
+	functions        []FunctionEntity
+	types            []TypeEntity
+	fields           []FieldEntity
+	variables        []VariableEntity
+	defines          []DefinesEdge
+	definesTypes     []DefinesTypeEdge
+	calls            []CallsEdge
+	imports          []ImportEntity
+	implements       []ImplementsEdge
+	sqlSchema        SQLSchema
+	tableAccesses    []TableAccessEdge
+	graphQLFields    []GraphQLFieldEntity
+	graphQLResolvers []GraphQLResolverEdge
+	topology         Topology
+	docChunks        []DocChunkEntity
+	docLinks         []DocLinkEdge
+	unresolvedCalls  []UnresolvedCall
+	funcRefs         []UnresolvedFuncRef
+	typeRefs         []UnresolvedTypeRef
+	typeParams       []TypeParamEntity
+	instantiations   []UnresolvedInstantiation
+	concurrency      []ConcurrencyEdge
+	errorSites       []ErrorSiteEntity
+	tests            []TestEntity
+	functionMetrics  []FunctionMetricsEntity
+	packageNames     map[string]string
+	packageDocs      map[string]string
 }
 
 // NewLocalPipeline creates a new local ingestion pipeline.
@@ -401,11 +421,16 @@ func (p *LocalPipeline) Run(ctx context.Context) (*IngestionResult, error) {
 	allCalls := parseResult.calls
 	allImports := parseResult.imports
 	allUnresolvedCalls := parseResult.unresolvedCalls
+	allSQLSchema := parseResult.sqlSchema
+	allTableAccesses := parseResult.tableAccesses
+	allGraphQLFields := parseResult.graphQLFields
 	packageNames := parseResult.packageNames
 
 	// Step 2b: Build implements index and resolve cross-package calls
 	allFields := parseResult.fields
+	allVariables := parseResult.variables
 	allImplements := BuildImplementsIndex(allTypes, allFunctions)
+	allImplements = append(allImplements, parseResult.implements...)
 
 	p.logger.Info("local.ingestion.interface_dispatch",
 		"fields", len(allFields),
@@ -442,6 +467,30 @@ func (p *LocalPipeline) Run(ctx context.Context) (*IngestionResult, error) {
 		)
 	}
 
+	// Step 2c: Link GraphQL fields to gqlgen/Apollo resolvers
+	allGraphQLResolvers := LinkGraphQLResolvers(allGraphQLFields, allFunctions, parseResult.graphQLResolvers)
+
+	// Step 2d: Link deployment topology to Dockerfiles, workloads and cmd/* main functions
+	allTopology := parseResult.topology
+	allTopology.Edges = LinkTopology(allTopology, allFunctions, packageNames)
+
+	// Step 2e: Link Markdown doc chunks to the functions, types and files they mention
+	allDocChunks := parseResult.docChunks
+	allDocLinks := LinkDocMentions(parseResult.docLinks, allFiles, allFunctions, allTypes)
+
+	// Step 2f: Group files into packages and aggregate imports into package edges
+	allPackages, allPackageImports := BuildPackageGraph(allFiles, allImports, packageNames, parseResult.packageDocs)
+
+	// Step 2g: Resolve type names referenced by functions and types
+	allTypeRefs := ResolveTypeRefs(parseResult.typeRefs, allFiles, allTypes, allImports, packageNames)
+
+	// Step 2h: Resolve the generic functions and types instantiated by functions and types
+	allTypeParams := parseResult.typeParams
+	allInstantiations := ResolveInstantiations(parseResult.instantiations, allFiles, allFunctions, allTypes, allImports, packageNames)
+
+	// Step 2i: Resolve function values passed as callbacks, assigned or returned
+	allFuncRefs := ResolveFuncRefs(parseResult.funcRefs, allFiles, allFunctions, allFields, allImports, packageNames)
+
 	parseErrorRate := 0.0
 	if len(loadResult.Files) > 0 {
 		parseErrorRate = float64(parseErrors) / float64(len(loadResult.Files)) * 100.0
@@ -497,6 +546,27 @@ func (p *LocalPipeline) Run(ctx context.Context) (*IngestionResult, error) {
 		embedDuration += typeEmbedDuration
 	}
 
+	// Step 3c: Generate embeddings for Markdown doc chunks
+	if len(allDocChunks) > 0 {
+		p.logger.Info("local.ingestion.step.generate_doc_embeddings", "run_id", runID, "chunk_count", len(allDocChunks))
+		docEmbedStart := time.Now()
+
+		docEmbedResult, err := p.embeddingGen.EmbedDocChunks(ctx, allDocChunks)
+		if err != nil {
+			return nil, fmt.Errorf("generate doc embeddings: %w", err)
+		}
+		allDocChunks = docEmbedResult.Chunks
+		embeddingErrors += docEmbedResult.ErrorCount
+
+		docEmbedDuration := time.Since(docEmbedStart)
+		p.logger.Info("local.ingestion.embeddings.docs.complete",
+			"count", len(allDocChunks),
+			"errors", docEmbedResult.ErrorCount,
+			"duration_ms", docEmbedDuration.Milliseconds(),
+		)
+		embedDuration += docEmbedDuration
+	}
+
 	// Step 4: Validate entities
 	p.logger.Info("local.ingestion.step.validate_entities")
 	if err := ValidateEntities(allFiles, allFunctions, allDefines, allCalls); err != nil {
@@ -529,6 +599,45 @@ func (p *LocalPipeline) Run(ctx context.Context) (*IngestionResult, error) {
 	fieldImplMutations := p.datalogBuild.BuildFieldAndImplementsMutations(allFields, allImplements)
 	mutations += fieldImplMutations
 
+	// Generate package-level variable mutations
+	mutations += p.datalogBuild.BuildVariableMutations(allVariables)
+
+	// Generate function reference mutations
+	mutations += p.datalogBuild.BuildFuncRefMutations(allFuncRefs)
+
+	// Generate type reference mutations
+	mutations += p.datalogBuild.BuildTypeRefMutations(allTypeRefs)
+
+	// Generate type parameter and instantiation mutations
+	mutations += p.datalogBuild.BuildGenericMutations(allTypeParams, allInstantiations)
+
+	// Generate goroutine, channel and lock mutations
+	mutations += p.datalogBuild.BuildConcurrencyMutations(parseResult.concurrency)
+
+	// Generate error site mutations
+	mutations += p.datalogBuild.BuildErrorSiteMutations(parseResult.errorSites)
+
+	// Generate test mutations
+	mutations += p.datalogBuild.BuildTestMutations(parseResult.tests)
+
+	// Generate function metrics mutations
+	mutations += p.datalogBuild.BuildFunctionMetricsMutations(parseResult.functionMetrics)
+
+	// Generate SQL schema and table access mutations
+	mutations += p.datalogBuild.BuildSQLMutations(allSQLSchema, allTableAccesses)
+
+	// Generate GraphQL field and resolver mutations
+	mutations += p.datalogBuild.BuildGraphQLMutations(allGraphQLFields, allGraphQLResolvers)
+
+	// Generate infrastructure topology mutations
+	mutations += p.datalogBuild.BuildTopologyMutations(allTopology)
+
+	// Generate Markdown doc chunk and doc link mutations
+	mutations += p.datalogBuild.BuildDocMutations(allDocChunks, allDocLinks)
+
+	// Generate package and package import mutations
+	mutations += p.datalogBuild.BuildPackageMutations(allPackages, allPackageImports)
+
 	// Execute mutations
 	if err := p.backend.Execute(ctx, mutations); err != nil {
 		return nil, fmt.Errorf("write to local db: %w", err)
@@ -547,7 +656,13 @@ func (p *LocalPipeline) Run(ctx context.Context) (*IngestionResult, error) {
 
 	entitiesSent := len(allFiles) + len(allFunctions) + len(allTypes) +
 		len(allDefines) + len(allDefinesTypes) + len(allCalls) + len(allImports) +
-		len(allFields) + len(allImplements)
+		len(allFields) + len(allImplements) + len(allVariables) + len(allFuncRefs) + len(allTypeRefs) +
+		len(allTypeParams) + len(allInstantiations) + len(parseResult.concurrency) + len(parseResult.errorSites) + len(parseResult.tests) + len(parseResult.functionMetrics) +
+		allSQLSchema.Len() + len(allTableAccesses) +
+		len(allGraphQLFields) + len(allGraphQLResolvers) +
+		allTopology.Len() +
+		len(allDocChunks) + len(allDocLinks) +
+		len(allPackages) + len(allPackageImports)
 
 	p.logger.Info("local.ingestion.write.complete",
 		"entities_written", entitiesSent,
@@ -607,7 +722,7 @@ func (p *LocalPipeline) Run(ctx context.Context) (*IngestionResult, error) {
 // parseFilesParallel parses files in parallel using a worker pool.
 func (p *LocalPipeline) parseFilesParallel(ctx context.Context, files []FileInfo, numWorkers int) (*parseFilesResult, int) {
 	if len(files) == 0 {
-		return &parseFilesResult{packageNames: make(map[string]string)}, 0
+		return &parseFilesResult{packageNames: make(map[string]string), packageDocs: make(map[string]string)}, 0
 	}
 
 	// For small file sets, use sequential parsing
@@ -695,20 +810,41 @@ func (p *LocalPipeline) parseFilesParallel(ctx context.Context, files []FileInfo
 
 	result := &parseFilesResult{
 		packageNames: packageNames,
+		packageDocs:  make(map[string]string),
 	}
 	for _, pr := range parseResults {
 		if pr == nil {
 			continue
 		}
+		if pr.PackageDoc != "" {
+			result.packageDocs[pr.File.Path] = pr.PackageDoc
+		}
 		result.files = append(result.files, pr.File)
 		result.functions = append(result.functions, pr.Functions...)
 		result.types = append(result.types, pr.Types...)
 		result.fields = append(result.fields, pr.Fields...)
+		result.variables = append(result.variables, pr.Variables...)
 		result.defines = append(result.defines, pr.Defines...)
 		result.definesTypes = append(result.definesTypes, pr.DefinesTypes...)
 		result.calls = append(result.calls, pr.Calls...)
 		result.imports = append(result.imports, pr.Imports...)
+		result.implements = append(result.implements, pr.Implements...)
+		result.sqlSchema.Merge(pr.SQLSchema)
+		result.tableAccesses = append(result.tableAccesses, pr.TableAccesses...)
+		result.graphQLFields = append(result.graphQLFields, pr.GraphQLFields...)
+		result.graphQLResolvers = append(result.graphQLResolvers, pr.GraphQLResolvers...)
+		result.topology.Merge(pr.Topology)
+		result.docChunks = append(result.docChunks, pr.DocChunks...)
+		result.docLinks = append(result.docLinks, pr.DocLinks...)
 		result.unresolvedCalls = append(result.unresolvedCalls, pr.UnresolvedCalls...)
+		result.funcRefs = append(result.funcRefs, pr.FuncRefs...)
+		result.typeRefs = append(result.typeRefs, pr.TypeRefs...)
+		result.typeParams = append(result.typeParams, pr.TypeParams...)
+		result.instantiations = append(result.instantiations, pr.Instantiations...)
+		result.concurrency = append(result.concurrency, pr.Concurrency...)
+		result.errorSites = append(result.errorSites, pr.ErrorSites...)
+		result.tests = append(result.tests, pr.Tests...)
+		result.functionMetrics = append(result.functionMetrics, pr.FunctionMetrics...)
 	}
 
 	return result, int(errorCount)
@@ -718,6 +854,7 @@ func (p *LocalPipeline) parseFilesParallel(ctx context.Context, files []FileInfo
 func (p *LocalPipeline) parseFilesSequential(ctx context.Context, files []FileInfo) (*parseFilesResult, int) {
 	result := &parseFilesResult{
 		packageNames: make(map[string]string),
+		packageDocs:  make(map[string]string),
 	}
 	errorCount := 0
 	totalFiles := int64(len(files))
@@ -742,14 +879,34 @@ func (p *LocalPipeline) parseFilesSequential(ctx context.Context, files []FileIn
 		result.functions = append(result.functions, pr.Functions...)
 		result.types = append(result.types, pr.Types...)
 		result.fields = append(result.fields, pr.Fields...)
+		result.variables = append(result.variables, pr.Variables...)
 		result.defines = append(result.defines, pr.Defines...)
 		result.definesTypes = append(result.definesTypes, pr.DefinesTypes...)
 		result.calls = append(result.calls, pr.Calls...)
 		result.imports = append(result.imports, pr.Imports...)
+		result.implements = append(result.implements, pr.Implements...)
+		result.sqlSchema.Merge(pr.SQLSchema)
+		result.tableAccesses = append(result.tableAccesses, pr.TableAccesses...)
+		result.graphQLFields = append(result.graphQLFields, pr.GraphQLFields...)
+		result.graphQLResolvers = append(result.graphQLResolvers, pr.GraphQLResolvers...)
+		result.topology.Merge(pr.Topology)
+		result.docChunks = append(result.docChunks, pr.DocChunks...)
+		result.docLinks = append(result.docLinks, pr.DocLinks...)
 		result.unresolvedCalls = append(result.unresolvedCalls, pr.UnresolvedCalls...)
+		result.funcRefs = append(result.funcRefs, pr.FuncRefs...)
+		result.typeRefs = append(result.typeRefs, pr.TypeRefs...)
+		result.typeParams = append(result.typeParams, pr.TypeParams...)
+		result.instantiations = append(result.instantiations, pr.Instantiations...)
+		result.concurrency = append(result.concurrency, pr.Concurrency...)
+		result.errorSites = append(result.errorSites, pr.ErrorSites...)
+		result.tests = append(result.tests, pr.Tests...)
+		result.functionMetrics = append(result.functionMetrics, pr.FunctionMetrics...)
 		if pr.PackageName != "" {
 			result.packageNames[fileInfo.Path] = pr.PackageName
 		}
+		if pr.PackageDoc != "" {
+			result.packageDocs[fileInfo.Path] = pr.PackageDoc
+		}
 		// Report progress after successful parse
 		p.reportProgress(int64(i+1), totalFiles, "parsing")
 	}
@@ -764,10 +921,12 @@ func (p *LocalPipeline) Backend() *storage.EmbeddedBackend {
 
 // incrementalContext holds the context for an incremental run.
 type incrementalContext struct {
-	runID     string
-	startTime time.Time
-	headSHA   string
-	delta     *GitDelta
+	runID       string
+	startTime   time.Time
+	headSHA     string
+	delta       *GitDelta
+	docMentions []DocLinkEdge // Mentions in unchanged docs of code in deleted or modified files
+	inboundRefs []inboundRef  // References in unchanged files to code in deleted or modified files
 }
 
 // tryIncrementalRun attempts to run incremental indexing.
@@ -783,12 +942,12 @@ func (p *LocalPipeline) tryIncrementalRun(ctx context.Context, loadResult *LoadR
 	}
 
 	// Process deletions
-	p.processIncrementalDeletions(incCtx.delta)
+	p.processIncrementalDeletions(ctx, incCtx)
 
 	// Get files to process
 	changedFiles := p.getFilesToProcess(incCtx.delta, loadResult.Files)
 	if len(changedFiles) == 0 {
-		return p.handleDeletionsOnly(incCtx, len(incCtx.delta.Deleted))
+		return p.handleDeletionsOnly(ctx, incCtx, len(incCtx.delta.Deleted))
 	}
 
 	// Parse, embed, and write
@@ -936,13 +1095,27 @@ func (p *LocalPipeline) detectHashChanges(loadResult *LoadResult, runID string,
 }
 
 // processIncrementalDeletions deletes entities for removed/modified files.
-func (p *LocalPipeline) processIncrementalDeletions(delta *GitDelta) {
+// It records in incCtx the mentions that unchanged docs made of code in
+// those files and the references unchanged files made to it, whose edges are
+// deleted with it and must be relinked after the write.
+func (p *LocalPipeline) processIncrementalDeletions(ctx context.Context, incCtx *incrementalContext) {
+	delta := incCtx.delta
 	filesToDelete := append([]string{}, delta.Deleted...)
 	filesToDelete = append(filesToDelete, delta.Modified...)
 	for oldPath := range delta.Renamed {
 		filesToDelete = append(filesToDelete, oldPath)
 	}
 
+	var err error
+	incCtx.docMentions, err = p.docMentionsOf(ctx, filesToDelete)
+	if err != nil {
+		p.logger.Warn("local.ingestion.incremental.doc_mentions.error", "err", err)
+	}
+	incCtx.inboundRefs, err = p.inboundRefsOf(ctx, filesToDelete)
+	if err != nil {
+		p.logger.Warn("local.ingestion.incremental.inbound_refs.error", "err", err)
+	}
+
 	dotCie := ""
 	if p.config.IngestionConfig.CheckpointPath != "" {
 		dotCie = filepath.Dir(p.config.IngestionConfig.CheckpointPath)
@@ -957,6 +1130,438 @@ func (p *LocalPipeline) processIncrementalDeletions(delta *GitDelta) {
 	}
 }
 
+// docMentionsOf returns the doc mentions linked to the given files or to the
+// functions and types declared in them, except mentions made in those files.
+func (p *LocalPipeline) docMentionsOf(ctx context.Context, paths []string) ([]DocLinkEdge, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	quoted := make([]string, len(paths))
+	changed := make(map[string]bool, len(paths))
+	for i, filePath := range paths {
+		quoted[i] = quoteString(filePath)
+		changed[filePath] = true
+	}
+	list := "[" + strings.Join(quoted, ", ") + "]"
+	result, err := p.backend.Query(ctx, fmt.Sprintf(`
+		?[chunk_id, target_kind, symbol, file_path, line] := *cie_doc_link { chunk_id, target_id, target_kind, symbol, file_path, line },
+			*cie_function { id: target_id, file_path: target_path }, is_in(target_path, %[1]s)
+		?[chunk_id, target_kind, symbol, file_path, line] := *cie_doc_link { chunk_id, target_id, target_kind, symbol, file_path, line },
+			*cie_type { id: target_id, file_path: target_path }, is_in(target_path, %[1]s)
+		?[chunk_id, target_kind, symbol, file_path, line] := *cie_doc_link { chunk_id, target_id, target_kind, symbol, file_path, line },
+			*cie_file { id: target_id, path: target_path }, is_in(target_path, %[1]s)`, list))
+	if err != nil {
+		return nil, fmt.Errorf("query doc links: %w", err)
+	}
+
+	var mentions []DocLinkEdge
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		m := DocLinkEdge{}
+		m.ChunkID, _ = row[0].(string)
+		m.TargetKind, _ = row[1].(string)
+		m.Symbol, _ = row[2].(string)
+		m.FilePath, _ = row[3].(string)
+		m.Line = rowLine(row[4])
+		if !changed[m.FilePath] {
+			mentions = append(mentions, m)
+		}
+	}
+	return mentions, nil
+}
+
+// relinkDocMentions resolves doc mentions against all indexed files, functions
+// and types, and writes their links. An incremental run parses only changed
+// files, which neither hold every symbol a changed doc mentions nor the docs
+// mentioning changed code.
+func (p *LocalPipeline) relinkDocMentions(ctx context.Context, mentions []DocLinkEdge) ([]DocLinkEdge, error) {
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+	fileRows, err := p.backend.Query(ctx, `?[id, path] := *cie_file { id, path }`)
+	if err != nil {
+		return nil, fmt.Errorf("query files: %w", err)
+	}
+	fnRows, err := p.backend.Query(ctx, `?[id, name, file_path] := *cie_function { id, name, file_path }`)
+	if err != nil {
+		return nil, fmt.Errorf("query functions: %w", err)
+	}
+	typeRows, err := p.backend.Query(ctx, `?[id, name, file_path] := *cie_type { id, name, file_path }`)
+	if err != nil {
+		return nil, fmt.Errorf("query types: %w", err)
+	}
+
+	var files []FileEntity
+	for _, row := range fileRows.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		f := FileEntity{}
+		f.ID, _ = row[0].(string)
+		f.Path, _ = row[1].(string)
+		files = append(files, f)
+	}
+	var functions []FunctionEntity
+	for _, row := range fnRows.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		fn := FunctionEntity{}
+		fn.ID, _ = row[0].(string)
+		fn.Name, _ = row[1].(string)
+		fn.FilePath, _ = row[2].(string)
+		functions = append(functions, fn)
+	}
+	var types []TypeEntity
+	for _, row := range typeRows.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		typ := TypeEntity{}
+		typ.ID, _ = row[0].(string)
+		typ.Name, _ = row[1].(string)
+		typ.FilePath, _ = row[2].(string)
+		types = append(types, typ)
+	}
+
+	for i := range mentions {
+		mentions[i].TargetID = ""
+	}
+	links := LinkDocMentions(mentions, files, functions, types)
+	if err := p.backend.Execute(ctx, p.datalogBuild.BuildDocMutations(nil, links)); err != nil {
+		return nil, fmt.Errorf("write doc links: %w", err)
+	}
+	return links, nil
+}
+
+// inboundRef is a reference made in an unchanged file to a Go entity declared
+// in a deleted or modified file. Its edge is deleted with that file, and its
+// target is found again after the write by package directory and name, the
+// way the resolvers index Go entities (see goSymbolIndex.add).
+type inboundRef struct {
+	Relation   string // Relation of the edge: "cie_type_ref", "cie_func_ref" or "cie_instantiation"
+	FromID     string // Referencing function or type
+	Kind       string // Kind of the reference; "function" or "type" for instantiations
+	TypeArgs   string // Type arguments of instantiations
+	FilePath   string // File containing the reference
+	Line       int    // Line number of the reference
+	TargetDir  string // Package directory of the referenced entity
+	TargetName string // Name of the referenced entity
+}
+
+// inboundRefsOf returns the references made in other files to the Go
+// entities declared in the given files.
+func (p *LocalPipeline) inboundRefsOf(ctx context.Context, paths []string) ([]inboundRef, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	quoted := make([]string, len(paths))
+	changed := make(map[string]bool, len(paths))
+	for i, filePath := range paths {
+		quoted[i] = quoteString(filePath)
+		changed[filePath] = true
+	}
+	list := "[" + strings.Join(quoted, ", ") + "]"
+
+	var refs []inboundRef
+	for _, q := range []struct{ relation, script string }{
+		{"cie_type_ref", fmt.Sprintf(`
+		?[from_id, kind, type_args, file_path, line, name, target_path] := *cie_type_ref { from_id, type_id, kind, file_path, line },
+			*cie_type { id: type_id, name, file_path: target_path }, is_in(target_path, %s), type_args = ""`, list)},
+		{"cie_func_ref", fmt.Sprintf(`
+		?[from_id, kind, type_args, file_path, line, name, target_path] := *cie_func_ref { from_id, to_id, kind, file_path, line },
+			*cie_function { id: to_id, name, file_path: target_path }, is_in(target_path, %s), type_args = ""`, list)},
+		{"cie_instantiation", fmt.Sprintf(`
+		?[from_id, kind, type_args, file_path, line, name, target_path] := *cie_instantiation { from_id, target_id, target_kind: kind, type_args, file_path, line },
+			*cie_function { id: target_id, name, file_path: target_path }, is_in(target_path, %[1]s)
+		?[from_id, kind, type_args, file_path, line, name, target_path] := *cie_instantiation { from_id, target_id, target_kind: kind, type_args, file_path, line },
+			*cie_type { id: target_id, name, file_path: target_path }, is_in(target_path, %[1]s)`, list)},
+	} {
+		result, err := p.backend.Query(ctx, q.script)
+		if err != nil {
+			return nil, fmt.Errorf("query %s: %w", q.relation, err)
+		}
+		for _, row := range result.Rows {
+			if len(row) < 7 {
+				continue
+			}
+			ref := inboundRef{Relation: q.relation}
+			ref.FromID, _ = row[0].(string)
+			ref.Kind, _ = row[1].(string)
+			ref.TypeArgs, _ = row[2].(string)
+			ref.FilePath, _ = row[3].(string)
+			ref.Line = rowLine(row[4])
+			ref.TargetName, _ = row[5].(string)
+			targetPath, _ := row[6].(string)
+			ref.TargetDir = path.Dir(targetPath)
+			if !changed[ref.FilePath] {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs, nil
+}
+
+// storedGoSymbols holds the indexed files, imports, package names, types,
+// functions and struct fields the Go reference resolvers need, read back
+// after an incremental write.
+type storedGoSymbols struct {
+	files        []FileEntity
+	imports      []ImportEntity
+	packageNames map[string]string // File path -> package name
+	types        []TypeEntity
+	functions    []FunctionEntity
+	fields       []FieldEntity
+}
+
+// loadStoredGoSymbols reads the symbols Go references resolve against from
+// the index. An incremental run parses only changed files, which neither
+// declare every type and function they reference nor hold the references
+// to them.
+func (p *LocalPipeline) loadStoredGoSymbols(ctx context.Context) (*storedGoSymbols, error) {
+	fileRows, err := p.backend.Query(ctx, `?[id, path, language] := *cie_file { id, path, language }`)
+	if err != nil {
+		return nil, fmt.Errorf("query files: %w", err)
+	}
+	importRows, err := p.backend.Query(ctx, `?[file_path, import_path, alias] := *cie_import { file_path, import_path, alias }`)
+	if err != nil {
+		return nil, fmt.Errorf("query imports: %w", err)
+	}
+	packageRows, err := p.backend.Query(ctx, `?[path, name] := *cie_package { path, name }`)
+	if err != nil {
+		return nil, fmt.Errorf("query packages: %w", err)
+	}
+	typeRows, err := p.backend.Query(ctx, `?[id, name, file_path] := *cie_type { id, name, file_path }`)
+	if err != nil {
+		return nil, fmt.Errorf("query types: %w", err)
+	}
+	fnRows, err := p.backend.Query(ctx, `?[id, name, signature, file_path] := *cie_function { id, name, signature, file_path }`)
+	if err != nil {
+		return nil, fmt.Errorf("query functions: %w", err)
+	}
+	fieldRows, err := p.backend.Query(ctx, `?[struct_name, field_name, field_type, file_path] := *cie_field { struct_name, field_name, field_type, file_path }`)
+	if err != nil {
+		return nil, fmt.Errorf("query fields: %w", err)
+	}
+
+	syms := &storedGoSymbols{packageNames: make(map[string]string)}
+	dirFiles := make(map[string][]string)
+	for _, row := range fileRows.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		f := FileEntity{}
+		f.ID, _ = row[0].(string)
+		f.Path, _ = row[1].(string)
+		f.Language, _ = row[2].(string)
+		syms.files = append(syms.files, f)
+		dirFiles[path.Dir(f.Path)] = append(dirFiles[path.Dir(f.Path)], f.Path)
+	}
+	for _, row := range packageRows.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		dir, _ := row[0].(string)
+		name, _ := row[1].(string)
+		for _, filePath := range dirFiles[dir] {
+			syms.packageNames[filePath] = name
+		}
+	}
+	for _, row := range importRows.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		imp := ImportEntity{}
+		imp.FilePath, _ = row[0].(string)
+		imp.ImportPath, _ = row[1].(string)
+		imp.Alias, _ = row[2].(string)
+		syms.imports = append(syms.imports, imp)
+	}
+	for _, row := range typeRows.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		typ := TypeEntity{}
+		typ.ID, _ = row[0].(string)
+		typ.Name, _ = row[1].(string)
+		typ.FilePath, _ = row[2].(string)
+		syms.types = append(syms.types, typ)
+	}
+	for _, row := range fnRows.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		fn := FunctionEntity{}
+		fn.ID, _ = row[0].(string)
+		fn.Name, _ = row[1].(string)
+		fn.Signature, _ = row[2].(string)
+		fn.FilePath, _ = row[3].(string)
+		syms.functions = append(syms.functions, fn)
+	}
+	for _, row := range fieldRows.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		f := FieldEntity{}
+		f.StructName, _ = row[0].(string)
+		f.FieldName, _ = row[1].(string)
+		f.FieldType, _ = row[2].(string)
+		f.FilePath, _ = row[3].(string)
+		syms.fields = append(syms.fields, f)
+	}
+	return syms, nil
+}
+
+// relinkTypeRefs resolves the type references of the changed files and the
+// inbound references to the types they declare against all indexed types,
+// and writes their edges.
+func (p *LocalPipeline) relinkTypeRefs(ctx context.Context, syms *storedGoSymbols, refs []UnresolvedTypeRef, inbound []inboundRef) ([]TypeRefEdge, error) {
+	edges := ResolveTypeRefs(refs, syms.files, syms.types, syms.imports, syms.packageNames)
+	index := newGoSymbolIndex(syms.files, nil, nil)
+	typeIDs := make(map[string]string) // dir + "." + name -> type ID
+	for _, t := range syms.types {
+		index.add(typeIDs, t.FilePath, t.Name, t.ID)
+	}
+	for _, ref := range inbound {
+		typeID := typeIDs[ref.TargetDir+"."+ref.TargetName]
+		if ref.Relation != "cie_type_ref" || typeID == "" {
+			continue
+		}
+		edges = append(edges, TypeRefEdge{
+			ID:       GenerateTypeRefID(ref.FromID, typeID, ref.Kind, ref.Line),
+			FromID:   ref.FromID,
+			TypeID:   typeID,
+			Kind:     ref.Kind,
+			FilePath: ref.FilePath,
+			Line:     ref.Line,
+		})
+	}
+	if len(edges) == 0 {
+		return nil, nil
+	}
+	if err := p.backend.Execute(ctx, p.datalogBuild.BuildTypeRefMutations(edges)); err != nil {
+		return nil, fmt.Errorf("write type refs: %w", err)
+	}
+	return edges, nil
+}
+
+// relinkFuncRefs resolves the function references of the changed files and
+// the inbound references to the functions they declare against all indexed
+// functions, and writes their edges.
+func (p *LocalPipeline) relinkFuncRefs(ctx context.Context, syms *storedGoSymbols, refs []UnresolvedFuncRef, inbound []inboundRef) ([]FuncRefEdge, error) {
+	edges := ResolveFuncRefs(refs, syms.files, syms.functions, syms.fields, syms.imports, syms.packageNames)
+	index := newGoSymbolIndex(syms.files, nil, nil)
+	funcIDs := make(map[string]string) // dir + "." + name -> function ID, methods as "Type.Method"
+	for _, fn := range syms.functions {
+		index.add(funcIDs, fn.FilePath, fn.Name, fn.ID)
+	}
+	for _, ref := range inbound {
+		toID := funcIDs[ref.TargetDir+"."+ref.TargetName]
+		if ref.Relation != "cie_func_ref" || toID == "" {
+			continue
+		}
+		edges = append(edges, FuncRefEdge{
+			ID:       GenerateFuncRefID(ref.FromID, toID, ref.Kind, ref.Line),
+			FromID:   ref.FromID,
+			ToID:     toID,
+			Kind:     ref.Kind,
+			FilePath: ref.FilePath,
+			Line:     ref.Line,
+		})
+	}
+	if len(edges) == 0 {
+		return nil, nil
+	}
+	if err := p.backend.Execute(ctx, p.datalogBuild.BuildFuncRefMutations(edges)); err != nil {
+		return nil, fmt.Errorf("write function refs: %w", err)
+	}
+	return edges, nil
+}
+
+// relinkInstantiations resolves the instantiations of the changed files and
+// the inbound instantiations of the generics they declare against all
+// indexed functions and types, and writes their edges.
+func (p *LocalPipeline) relinkInstantiations(ctx context.Context, syms *storedGoSymbols, insts []UnresolvedInstantiation, inbound []inboundRef) ([]InstantiationEdge, error) {
+	edges := ResolveInstantiations(insts, syms.files, syms.functions, syms.types, syms.imports, syms.packageNames)
+	index := newGoSymbolIndex(syms.files, nil, nil)
+	targetIDs := map[string]map[string]string{ // target kind -> dir + "." + name -> ID
+		"function": make(map[string]string),
+		"type":     make(map[string]string),
+	}
+	for _, fn := range syms.functions {
+		index.add(targetIDs["function"], fn.FilePath, fn.Name, fn.ID)
+	}
+	for _, t := range syms.types {
+		index.add(targetIDs["type"], t.FilePath, t.Name, t.ID)
+	}
+	for _, ref := range inbound {
+		targetID := targetIDs[ref.Kind][ref.TargetDir+"."+ref.TargetName]
+		if ref.Relation != "cie_instantiation" || targetID == "" {
+			continue
+		}
+		edges = append(edges, InstantiationEdge{
+			ID:         GenerateInstantiationID(ref.FromID, targetID, ref.TypeArgs, ref.Line),
+			FromID:     ref.FromID,
+			TargetID:   targetID,
+			TargetKind: ref.Kind,
+			TypeArgs:   ref.TypeArgs,
+			FilePath:   ref.FilePath,
+			Line:       ref.Line,
+		})
+	}
+	if len(edges) == 0 {
+		return nil, nil
+	}
+	if err := p.backend.Execute(ctx, p.datalogBuild.BuildGenericMutations(nil, edges)); err != nil {
+		return nil, fmt.Errorf("write instantiations: %w", err)
+	}
+	return edges, nil
+}
+
+// goRefEdges holds the Go reference edges written by relinkGoRefs.
+type goRefEdges struct {
+	typeRefs       []TypeRefEdge
+	funcRefs       []FuncRefEdge
+	instantiations []InstantiationEdge
+}
+
+// relinkGoRefs relinks the Go type references, function references and
+// instantiations of an incremental run (see relinkTypeRefs, relinkFuncRefs
+// and relinkInstantiations).
+func (p *LocalPipeline) relinkGoRefs(ctx context.Context, parsed *parseFilesResult, inbound []inboundRef) (goRefEdges, error) {
+	var edges goRefEdges
+	if len(parsed.typeRefs) == 0 && len(parsed.funcRefs) == 0 && len(parsed.instantiations) == 0 && len(inbound) == 0 {
+		return edges, nil
+	}
+	syms, err := p.loadStoredGoSymbols(ctx)
+	if err != nil {
+		return edges, err
+	}
+	if edges.typeRefs, err = p.relinkTypeRefs(ctx, syms, parsed.typeRefs, inbound); err != nil {
+		return edges, err
+	}
+	if edges.funcRefs, err = p.relinkFuncRefs(ctx, syms, parsed.funcRefs, inbound); err != nil {
+		return edges, err
+	}
+	edges.instantiations, err = p.relinkInstantiations(ctx, syms, parsed.instantiations, inbound)
+	return edges, err
+}
+
+// rowLine converts a line number column to an int.
+func rowLine(v any) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case int64:
+		return int(n)
+	}
+	return 0
+}
+
 // getFilesToProcess returns files from loadResult that are in the delta.
 func (p *LocalPipeline) getFilesToProcess(delta *GitDelta, allFiles []FileInfo) []FileInfo {
 	filesToProcess := make(map[string]bool)
@@ -980,8 +1585,20 @@ func (p *LocalPipeline) getFilesToProcess(delta *GitDelta, allFiles []FileInfo)
 }
 
 // handleDeletionsOnly returns a result when only deletions occurred.
-func (p *LocalPipeline) handleDeletionsOnly(incCtx *incrementalContext, deletedCount int) (*IngestionResult, error) {
+func (p *LocalPipeline) handleDeletionsOnly(ctx context.Context, incCtx *incrementalContext, deletedCount int) (*IngestionResult, error) {
 	p.logger.Info("local.ingestion.incremental.deletions_only", "deleted", deletedCount)
+	if err := p.rebuildPackageGraph(ctx, nil, nil); err != nil {
+		p.logger.Warn("local.ingestion.incremental.package_graph.error", "err", err)
+	}
+	if _, err := p.relinkGraphQLResolvers(ctx, nil); err != nil {
+		p.logger.Warn("local.ingestion.incremental.graphql_resolvers.error", "err", err)
+	}
+	if _, err := p.relinkDocMentions(ctx, incCtx.docMentions); err != nil {
+		p.logger.Warn("local.ingestion.incremental.doc_links.error", "err", err)
+	}
+	if _, err := p.relinkGoRefs(ctx, &parseFilesResult{}, incCtx.inboundRefs); err != nil {
+		p.logger.Warn("local.ingestion.incremental.go_refs.error", "err", err)
+	}
 	if err := p.backend.SetLastIndexedSHA(incCtx.headSHA); err != nil {
 		p.logger.Warn("local.ingestion.incremental.update_sha.error", "err", err)
 	}
@@ -993,6 +1610,150 @@ func (p *LocalPipeline) handleDeletionsOnly(incCtx *incrementalContext, deletedC
 	}, nil
 }
 
+// rebuildPackageGraph recomputes the package graph from the indexed files and
+// imports after an incremental run. Only changed files were parsed, so the
+// package names and docs of other directories are taken from the previous graph.
+func (p *LocalPipeline) rebuildPackageGraph(ctx context.Context, packageNames, packageDocs map[string]string) error {
+	fileRows, err := p.backend.Query(ctx, `?[path, language] := *cie_file { path, language }`)
+	if err != nil {
+		return fmt.Errorf("query files: %w", err)
+	}
+	importRows, err := p.backend.Query(ctx, `?[file_path, import_path] := *cie_import { file_path, import_path }`)
+	if err != nil {
+		return fmt.Errorf("query imports: %w", err)
+	}
+	packageRows, err := p.backend.Query(ctx, `?[path, name, doc] := *cie_package { path, name, doc }`)
+	if err != nil {
+		return fmt.Errorf("query packages: %w", err)
+	}
+
+	names := make(map[string]string, len(packageNames))
+	docs := make(map[string]string, len(packageDocs))
+	namedDirs := make(map[string]bool)
+	documentedDirs := make(map[string]bool)
+	for file, name := range packageNames {
+		names[file] = name
+		namedDirs[path.Dir(file)] = true
+	}
+	for file, doc := range packageDocs {
+		docs[file] = doc
+		documentedDirs[path.Dir(file)] = true
+	}
+
+	var files []FileEntity
+	for _, row := range fileRows.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		filePath, _ := row[0].(string)
+		language, _ := row[1].(string)
+		files = append(files, FileEntity{Path: filePath, Language: language})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	firstFile := make(map[string]string) // dir -> first file, which carries the previous name and doc
+	for _, f := range files {
+		if dir := path.Dir(f.Path); firstFile[dir] == "" {
+			firstFile[dir] = f.Path
+		}
+	}
+	for _, row := range packageRows.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		dir, _ := row[0].(string)
+		name, _ := row[1].(string)
+		doc, _ := row[2].(string)
+		file := firstFile[dir]
+		if file == "" {
+			continue
+		}
+		if !namedDirs[dir] && name != path.Base(dir) {
+			names[file] = name
+		}
+		if !documentedDirs[dir] && doc != "" {
+			docs[file] = doc
+		}
+	}
+
+	var imports []ImportEntity
+	for _, row := range importRows.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		filePath, _ := row[0].(string)
+		importPath, _ := row[1].(string)
+		imports = append(imports, ImportEntity{FilePath: filePath, ImportPath: importPath})
+	}
+
+	packages, edges := BuildPackageGraph(files, imports, names, docs)
+	return p.backend.Execute(ctx, p.datalogBuild.BuildPackageMutations(packages, edges))
+}
+
+// relinkGraphQLResolvers links GraphQL resolvers after an incremental write.
+//
+// Changed files alone cannot be linked: a changed schema must be matched
+// against unchanged resolvers, and a changed resolver against the unchanged
+// schema. gqlgen edges are therefore rebuilt from the stored schema and all
+// stored Go resolver methods, and the Apollo edges of the changed files are
+// resolved against all stored JavaScript/TypeScript functions. Apollo edges
+// of unchanged files do not depend on the schema and are kept.
+func (p *LocalPipeline) relinkGraphQLResolvers(ctx context.Context, apollo []GraphQLResolverEdge) ([]GraphQLResolverEdge, error) {
+	fieldRows, err := p.backend.Query(ctx, `?[type_name, name] := *cie_graphql_field { type_name, name }`)
+	if err != nil {
+		return nil, fmt.Errorf("query graphql fields: %w", err)
+	}
+	fnRows, err := p.backend.Query(ctx, `?[id, name, file_path, start_line] := *cie_function { id, name, file_path, start_line },
+		ends_with(file_path, ".go"), regex_matches(name, "Resolver[.]")`)
+	if err != nil {
+		return nil, fmt.Errorf("query resolver methods: %w", err)
+	}
+
+	var names []string
+	for _, e := range apollo {
+		if e.FunctionID == "" && e.FunctionName != "" {
+			names = append(names, quoteString(e.FunctionName))
+		}
+	}
+	if len(names) > 0 {
+		jsRows, err := p.backend.Query(ctx, fmt.Sprintf(`?[id, name, file_path, start_line] := *cie_function { id, name, file_path, start_line }, is_in(name, [%s])`, strings.Join(names, ", ")))
+		if err != nil {
+			return nil, fmt.Errorf("query resolver functions: %w", err)
+		}
+		fnRows.Rows = append(fnRows.Rows, jsRows.Rows...)
+	}
+
+	var fields []GraphQLFieldEntity
+	for _, row := range fieldRows.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		typeName, _ := row[0].(string)
+		name, _ := row[1].(string)
+		fields = append(fields, GraphQLFieldEntity{TypeName: typeName, Name: name})
+	}
+	var functions []FunctionEntity
+	for _, row := range fnRows.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		fn := FunctionEntity{}
+		fn.ID, _ = row[0].(string)
+		fn.Name, _ = row[1].(string)
+		fn.FilePath, _ = row[2].(string)
+		fn.StartLine = rowLine(row[3])
+		functions = append(functions, fn)
+	}
+
+	edges := LinkGraphQLResolvers(fields, functions, apollo)
+	mutations := fmt.Sprintf(`{ ?[id] := *cie_graphql_resolver { id, framework }, framework = %s :rm cie_graphql_resolver { id } }`, quoteString(GraphQLFrameworkGqlgen)) + "\n" +
+		p.datalogBuild.BuildGraphQLMutations(nil, edges)
+	if err := p.backend.Execute(ctx, mutations); err != nil {
+		return nil, fmt.Errorf("write graphql resolvers: %w", err)
+	}
+	return edges, nil
+}
+
 // processIncrementalFiles parses, embeds, and writes changed files.
 func (p *LocalPipeline) processIncrementalFiles(ctx context.Context, incCtx *incrementalContext, changedFiles []FileInfo) (*IngestionResult, error) {
 	// Parse
@@ -1009,6 +1770,7 @@ func (p *LocalPipeline) processIncrementalFiles(ctx context.Context, incCtx *inc
 
 	// Build implements index and resolve cross-package calls
 	incImplements := BuildImplementsIndex(parseResult.types, parseResult.functions)
+	incImplements = append(incImplements, parseResult.implements...)
 
 	if len(parseResult.unresolvedCalls) > 0 {
 		resolver := NewCallResolver()
@@ -1024,6 +1786,8 @@ func (p *LocalPipeline) processIncrementalFiles(ctx context.Context, incCtx *inc
 		}
 	}
 
+	parseResult.topology.Edges = LinkTopology(parseResult.topology, parseResult.functions, parseResult.packageNames)
+
 	// Embed
 	p.logger.Info("local.ingestion.incremental.embed", "function_count", len(parseResult.functions))
 	embedStart := time.Now()
@@ -1043,6 +1807,15 @@ func (p *LocalPipeline) processIncrementalFiles(ctx context.Context, incCtx *inc
 		parseResult.types = typeEmbedResult.Types
 		embeddingErrors += typeEmbedResult.ErrorCount
 	}
+
+	if len(parseResult.docChunks) > 0 {
+		docEmbedResult, err := p.embeddingGen.EmbedDocChunks(ctx, parseResult.docChunks)
+		if err != nil {
+			return nil, fmt.Errorf("generate doc embeddings: %w", err)
+		}
+		parseResult.docChunks = docEmbedResult.Chunks
+		embeddingErrors += docEmbedResult.ErrorCount
+	}
 	embedDuration := time.Since(embedStart)
 
 	// Write
@@ -1061,10 +1834,35 @@ func (p *LocalPipeline) processIncrementalFiles(ctx context.Context, incCtx *inc
 	// Add field and implements mutations
 	fieldImplMutations := p.datalogBuild.BuildFieldAndImplementsMutations(parseResult.fields, incImplements)
 	mutations += fieldImplMutations
+	mutations += p.datalogBuild.BuildVariableMutations(parseResult.variables)
+	mutations += p.datalogBuild.BuildGenericMutations(parseResult.typeParams, nil)
+	mutations += p.datalogBuild.BuildConcurrencyMutations(parseResult.concurrency)
+	mutations += p.datalogBuild.BuildErrorSiteMutations(parseResult.errorSites)
+	mutations += p.datalogBuild.BuildTestMutations(parseResult.tests)
+	mutations += p.datalogBuild.BuildFunctionMetricsMutations(parseResult.functionMetrics)
+	mutations += p.datalogBuild.BuildSQLMutations(parseResult.sqlSchema, parseResult.tableAccesses)
+	mutations += p.datalogBuild.BuildGraphQLMutations(parseResult.graphQLFields, nil)
+	mutations += p.datalogBuild.BuildTopologyMutations(parseResult.topology)
+	mutations += p.datalogBuild.BuildDocMutations(parseResult.docChunks, nil)
 
 	if err := p.backend.Execute(ctx, mutations); err != nil {
 		return nil, fmt.Errorf("write to local db: %w", err)
 	}
+	if err := p.rebuildPackageGraph(ctx, parseResult.packageNames, parseResult.packageDocs); err != nil {
+		p.logger.Warn("local.ingestion.incremental.package_graph.error", "err", err)
+	}
+	incGraphQLResolvers, err := p.relinkGraphQLResolvers(ctx, parseResult.graphQLResolvers)
+	if err != nil {
+		p.logger.Warn("local.ingestion.incremental.graphql_resolvers.error", "err", err)
+	}
+	incDocLinks, err := p.relinkDocMentions(ctx, append(parseResult.docLinks, incCtx.docMentions...))
+	if err != nil {
+		p.logger.Warn("local.ingestion.incremental.doc_links.error", "err", err)
+	}
+	incRefs, err := p.relinkGoRefs(ctx, parseResult, incCtx.inboundRefs)
+	if err != nil {
+		p.logger.Warn("local.ingestion.incremental.go_refs.error", "err", err)
+	}
 	writeDuration := time.Since(writeStart)
 
 	if p.config.IngestionConfig.CheckpointPath != "" {
@@ -1082,7 +1880,12 @@ func (p *LocalPipeline) processIncrementalFiles(ctx context.Context, incCtx *inc
 	totalDuration := time.Since(incCtx.startTime)
 	entitiesSent := len(parseResult.files) + len(parseResult.functions) + len(parseResult.types) +
 		len(parseResult.defines) + len(parseResult.definesTypes) + len(parseResult.calls) + len(parseResult.imports) +
-		len(parseResult.fields) + len(incImplements)
+		len(parseResult.fields) + len(incImplements) + len(parseResult.variables) + len(incRefs.funcRefs) + len(incRefs.typeRefs) +
+		len(parseResult.typeParams) + len(incRefs.instantiations) + len(parseResult.concurrency) + len(parseResult.errorSites) + len(parseResult.tests) + len(parseResult.functionMetrics) +
+		parseResult.sqlSchema.Len() + len(parseResult.tableAccesses) +
+		len(parseResult.graphQLFields) + len(incGraphQLResolvers) +
+		parseResult.topology.Len() +
+		len(parseResult.docChunks) + len(incDocLinks)
 
 	result := &IngestionResult{
 		ProjectID:          p.config.ProjectID,
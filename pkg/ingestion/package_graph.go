@@ -0,0 +1,266 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// PACKAGE GRAPH
+// =============================================================================
+
+// packageLanguages are the languages whose directories form packages.
+var packageLanguages = map[string]bool{
+	"go":         true,
+	"php":        true,
+	"python":     true,
+	"javascript": true,
+	"typescript": true,
+}
+
+// packageInfo accumulates the files of one directory.
+type packageInfo struct {
+	files     []string
+	languages map[string]int
+}
+
+// BuildPackageGraph groups source files into packages (one per directory) and
+// aggregates their imports into package → package edges:
+//   - Go imports match the package directory they end with
+//     ("github.com/org/repo/pkg/tools" → pkg/tools); the module path itself
+//     matches the root package
+//   - PHP imports match the directory of the imported class file, else the
+//     only directory declaring the imported namespace
+//
+// packageNames and packageDocs map file paths to the Go package name or PHP
+// namespace and to the Go package doc comment. External imports and imports of
+// a package by itself produce no edge.
+func BuildPackageGraph(files []FileEntity, imports []ImportEntity, packageNames, packageDocs map[string]string) ([]PackageEntity, []PackageImportEdge) {
+	dirs, fileLanguage := groupPackageDirs(files)
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+
+	paths := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		paths = append(paths, dir)
+	}
+	sort.Strings(paths)
+
+	packages := make([]PackageEntity, 0, len(paths))
+	for _, dir := range paths {
+		info := dirs[dir]
+		sort.Strings(info.files)
+		packages = append(packages, PackageEntity{
+			ID:        GeneratePackageID(dir),
+			Path:      dir,
+			Name:      packageName(dir, info.files, packageNames),
+			Language:  majorityLanguage(info.languages),
+			FileCount: len(info.files),
+			Doc:       packageDoc(dir, info.files, packageDocs),
+		})
+	}
+
+	resolve := newPackageImportResolver(dirs, imports, fileLanguage, packageNames)
+	counts := make(map[[2]string]int)
+	for _, imp := range imports {
+		from := path.Dir(imp.FilePath)
+		if dirs[from] == nil {
+			continue
+		}
+		to := resolve(imp, fileLanguage[imp.FilePath])
+		if to == "" || to == from {
+			continue
+		}
+		counts[[2]string{from, to}]++
+	}
+
+	edges := make([]PackageImportEdge, 0, len(counts))
+	for key, count := range counts {
+		edges = append(edges, PackageImportEdge{
+			ID:          GeneratePackageImportID(key[0], key[1]),
+			FromPath:    key[0],
+			ToPath:      key[1],
+			ImportCount: count,
+		})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].FromPath != edges[j].FromPath {
+			return edges[i].FromPath < edges[j].FromPath
+		}
+		return edges[i].ToPath < edges[j].ToPath
+	})
+
+	return packages, edges
+}
+
+// groupPackageDirs groups the source files of package languages by directory
+// and maps each of them to its language.
+func groupPackageDirs(files []FileEntity) (map[string]*packageInfo, map[string]string) {
+	dirs := make(map[string]*packageInfo)
+	fileLanguage := make(map[string]string, len(files))
+	for _, f := range files {
+		if !packageLanguages[f.Language] {
+			continue
+		}
+		dir := path.Dir(f.Path)
+		info := dirs[dir]
+		if info == nil {
+			info = &packageInfo{languages: make(map[string]int)}
+			dirs[dir] = info
+		}
+		info.files = append(info.files, f.Path)
+		info.languages[f.Language]++
+		fileLanguage[f.Path] = f.Language
+	}
+	return dirs, fileLanguage
+}
+
+// newPackageImportResolver returns a function mapping an import to the
+// directory of the package it imports, or "" for external imports.
+func newPackageImportResolver(dirs map[string]*packageInfo, imports []ImportEntity, fileLanguage, packageNames map[string]string) func(ImportEntity, string) string {
+	var goDirs []string
+	phpClassDirs := make(map[string]string)       // "App\Services\UserService" -> dir
+	phpNamespaceDirs := make(map[string][]string) // "App\Services" -> dirs
+	for dir, info := range dirs {
+		if info.languages["go"] > 0 && dir != "." {
+			goDirs = append(goDirs, dir)
+		}
+		seen := make(map[string]bool)
+		for _, file := range info.files {
+			ns, ok := packageNames[file]
+			if !ok || fileLanguage[file] != "php" {
+				continue
+			}
+			class := strings.TrimSuffix(path.Base(file), ".php")
+			phpClassDirs[phpQualify(ns, class)] = dir
+			if !seen[ns] {
+				seen[ns] = true
+				phpNamespaceDirs[ns] = append(phpNamespaceDirs[ns], dir)
+			}
+		}
+	}
+	// Longest directories first so pkg/tools/internal wins over internal
+	sort.Slice(goDirs, func(i, j int) bool {
+		if len(goDirs[i]) != len(goDirs[j]) {
+			return len(goDirs[i]) > len(goDirs[j])
+		}
+		return goDirs[i] < goDirs[j]
+	})
+
+	matchGoDir := func(importPath string) (dir, modulePath string) {
+		for _, d := range goDirs {
+			if strings.HasSuffix(importPath, "/"+d) {
+				return d, strings.TrimSuffix(importPath, "/"+d)
+			}
+		}
+		return "", ""
+	}
+
+	// Module paths are inferred from the imports that match a directory, so
+	// that "github.com/org/repo" can be resolved to the root package.
+	modulePaths := make(map[string]bool)
+	for _, imp := range imports {
+		if fileLanguage[imp.FilePath] != "go" {
+			continue
+		}
+		if _, modulePath := matchGoDir(imp.ImportPath); modulePath != "" {
+			modulePaths[modulePath] = true
+		}
+	}
+	rootIsGo := dirs["."] != nil && dirs["."].languages["go"] > 0
+
+	return func(imp ImportEntity, language string) string {
+		switch language {
+		case "go":
+			if dir, _ := matchGoDir(imp.ImportPath); dir != "" {
+				return dir
+			}
+			if modulePaths[imp.ImportPath] && rootIsGo {
+				return "."
+			}
+			if len(modulePaths) == 0 && dirs[imp.ImportPath] != nil {
+				return imp.ImportPath // Relative module without a path prefix
+			}
+		case "php":
+			if dir, ok := phpClassDirs[imp.ImportPath]; ok {
+				return dir
+			}
+			ns := ""
+			if idx := strings.LastIndex(imp.ImportPath, `\`); idx >= 0 {
+				ns = imp.ImportPath[:idx]
+			}
+			if candidates := phpNamespaceDirs[ns]; ns != "" && len(candidates) == 1 {
+				return candidates[0]
+			}
+		}
+		return ""
+	}
+}
+
+// packageName returns the Go package name or PHP namespace declared by the
+// files of dir, ignoring Go external test packages, else the directory name.
+func packageName(dir string, files []string, packageNames map[string]string) string {
+	fallback := ""
+	for _, file := range files {
+		name := packageNames[file]
+		if name == "" {
+			continue
+		}
+		if !strings.HasSuffix(name, "_test") {
+			return name
+		}
+		if fallback == "" {
+			fallback = name
+		}
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return path.Base(dir)
+}
+
+// packageDoc returns the package doc comment of dir: the one in doc.go when
+// present, else the first one found.
+func packageDoc(dir string, files []string, packageDocs map[string]string) string {
+	if doc := packageDocs[path.Join(dir, "doc.go")]; doc != "" {
+		return doc
+	}
+	for _, file := range files {
+		if doc := packageDocs[file]; doc != "" {
+			return doc
+		}
+	}
+	return ""
+}
+
+// majorityLanguage returns the most common language, breaking ties alphabetically.
+func majorityLanguage(languages map[string]int) string {
+	best, bestCount := "", 0
+	for lang, count := range languages {
+		if count > bestCount || (count == bestCount && lang < best) {
+			best, bestCount = lang, count
+		}
+	}
+	return best
+}
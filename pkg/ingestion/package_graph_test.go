@@ -0,0 +1,114 @@
+package ingestion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildPackageGraph tests package grouping, names, docs and Go/PHP import edges.
+func TestBuildPackageGraph(t *testing.T) {
+	files := []FileEntity{
+		{Path: "main.go", Language: "go"},
+		{Path: "cmd/cie/main.go", Language: "go"},
+		{Path: "cmd/cie/mcp.go", Language: "go"},
+		{Path: "pkg/tools/search.go", Language: "go"},
+		{Path: "pkg/tools/search_test.go", Language: "go"},
+		{Path: "pkg/tools/doc.go", Language: "go"},
+		{Path: "pkg/storage/embedded.go", Language: "go"},
+		{Path: "app/Services/UserService.php", Language: "php"},
+		{Path: "app/Http/UserController.php", Language: "php"},
+		{Path: "web/app.js", Language: "javascript"},
+		{Path: "web/types.ts", Language: "typescript"},
+		{Path: "web/util.ts", Language: "typescript"},
+		{Path: "README.md", Language: "markdown"},
+	}
+	packageNames := map[string]string{
+		"main.go":                      "main",
+		"cmd/cie/main.go":              "main",
+		"cmd/cie/mcp.go":               "main",
+		"pkg/tools/search.go":          "tools",
+		"pkg/tools/search_test.go":     "tools_test",
+		"pkg/tools/doc.go":             "tools",
+		"pkg/storage/embedded.go":      "storage",
+		"app/Services/UserService.php": `App\Services`,
+		"app/Http/UserController.php":  `App\Http`,
+	}
+	packageDocs := map[string]string{
+		"pkg/tools/search.go": "Package tools has an old doc.",
+		"pkg/tools/doc.go":    "Package tools implements the MCP tools.",
+	}
+	imports := []ImportEntity{
+		{FilePath: "cmd/cie/main.go", ImportPath: "github.com/kraklabs/cie/pkg/tools"},
+		{FilePath: "cmd/cie/mcp.go", ImportPath: "github.com/kraklabs/cie/pkg/tools"},
+		{FilePath: "cmd/cie/mcp.go", ImportPath: "github.com/kraklabs/cie"},
+		{FilePath: "cmd/cie/mcp.go", ImportPath: "fmt"},
+		{FilePath: "pkg/tools/search.go", ImportPath: "github.com/kraklabs/cie/pkg/storage"},
+		{FilePath: "pkg/tools/search_test.go", ImportPath: "github.com/kraklabs/cie/pkg/tools"},
+		{FilePath: "pkg/storage/embedded.go", ImportPath: "github.com/kraklabs/cie/pkg/tools"},
+		{FilePath: "app/Http/UserController.php", ImportPath: `App\Services\UserService`},
+		{FilePath: "app/Http/UserController.php", ImportPath: `Illuminate\Http\Request`},
+	}
+
+	packages, edges := BuildPackageGraph(files, imports, packageNames, packageDocs)
+
+	byPath := make(map[string]PackageEntity)
+	for _, pkg := range packages {
+		byPath[pkg.Path] = pkg
+	}
+	assert.Len(t, packages, 7, "README.md does not form a package")
+	assert.Equal(t, PackageEntity{
+		ID: GeneratePackageID("pkg/tools"), Path: "pkg/tools", Name: "tools", Language: "go", FileCount: 3,
+		Doc: "Package tools implements the MCP tools.",
+	}, byPath["pkg/tools"])
+	assert.Equal(t, "main", byPath["."].Name)
+	assert.Equal(t, `App\Http`, byPath["app/Http"].Name)
+	assert.Equal(t, "web", byPath["web"].Name)
+	assert.Equal(t, "typescript", byPath["web"].Language)
+
+	got := make(map[string]int)
+	for _, e := range edges {
+		assert.Equal(t, GeneratePackageImportID(e.FromPath, e.ToPath), e.ID)
+		got[e.FromPath+" -> "+e.ToPath] = e.ImportCount
+	}
+	assert.Equal(t, map[string]int{
+		"cmd/cie -> pkg/tools":     2,
+		"cmd/cie -> .":             1,
+		"pkg/tools -> pkg/storage": 1,
+		"pkg/storage -> pkg/tools": 1,
+		"app/Http -> app/Services": 1,
+	}, got)
+}
+
+// TestBuildPackageGraph_Empty tests that no packages are built without source files.
+func TestBuildPackageGraph_Empty(t *testing.T) {
+	packages, edges := BuildPackageGraph([]FileEntity{{Path: "README.md", Language: "markdown"}}, nil, nil, nil)
+	assert.Empty(t, packages)
+	assert.Empty(t, edges)
+}
+
+// TestExtractGoPackageDoc tests that the comment above the package clause is
+// returned as the package doc.
+func TestExtractGoPackageDoc(t *testing.T) {
+	content := `// Copyright 2025 KrakLabs
+
+// Package tools implements the MCP tools.
+//
+// Tools query the local index.
+package tools
+`
+	tmpFile := filepath.Join(t.TempDir(), "doc.go")
+	require.NoError(t, os.WriteFile(tmpFile, []byte(content), 0600))
+
+	result, err := NewTreeSitterParser(nil).ParseFile(FileInfo{
+		Path:     "pkg/tools/doc.go",
+		FullPath: tmpFile,
+		Size:     int64(len(content)),
+		Language: "go",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Package tools implements the MCP tools.\n\nTools query the local index.", result.PackageDoc)
+}
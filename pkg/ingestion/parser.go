@@ -102,6 +102,9 @@ type ParseResult struct {
 	// Fields contains struct field entities with their types (for interface dispatch resolution).
 	Fields []FieldEntity
 
+	// Variables contains package-level variables and constants.
+	Variables []VariableEntity
+
 	// Defines contains edges connecting the file to its functions.
 	Defines []DefinesEdge
 
@@ -111,16 +114,83 @@ type ParseResult struct {
 	// Calls contains function-to-function call relationships discovered within the file.
 	Calls []CallsEdge
 
-	// Imports contains import statements for cross-package resolution (Go-specific).
+	// Imports contains import statements for cross-package resolution
+	// (Go imports, PHP `use` statements).
 	Imports []ImportEntity
 
+	// Implements contains inheritance edges declared in source (PHP `implements`,
+	// `extends` and trait `use`). Go implementations are inferred from method sets
+	// by BuildImplementsIndex instead.
+	Implements []ImplementsEdge
+
+	// SQLSchema contains tables, columns, indexes and foreign keys declared in .sql files.
+	SQLSchema SQLSchema
+
+	// TableAccesses contains function -> SQL table read/write edges found in function code.
+	TableAccesses []TableAccessEdge
+
+	// GraphQLFields contains fields declared in GraphQL SDL files.
+	GraphQLFields []GraphQLFieldEntity
+
+	// GraphQLResolvers contains Apollo resolver map bindings found in JS/TS files.
+	// Bindings by function name are resolved by LinkGraphQLResolvers.
+	GraphQLResolvers []GraphQLResolverEdge
+
+	// Topology contains deployable units from Dockerfiles, compose files,
+	// Kubernetes manifests and Terraform resources.
+	Topology Topology
+
+	// DocChunks contains the heading-delimited sections of Markdown files.
+	DocChunks []DocChunkEntity
+
+	// DocLinks contains the symbols and file paths mentioned in DocChunks.
+	// Targets are resolved by LinkDocMentions.
+	DocLinks []DocLinkEdge
+
 	// UnresolvedCalls contains function calls that couldn't be resolved within the file.
 	// These will be resolved later during cross-package call resolution.
 	UnresolvedCalls []UnresolvedCall
 
-	// PackageName is the package name for Go files (e.g., "handlers", "main").
+	// FuncRefs contains the function values Go functions pass as arguments,
+	// assign or return. Targets are resolved by ResolveFuncRefs.
+	FuncRefs []UnresolvedFuncRef
+
+	// TypeRefs contains the type names referenced by Go functions and types.
+	// Targets are resolved by ResolveTypeRefs.
+	TypeRefs []UnresolvedTypeRef
+
+	// TypeParams contains the type parameters of generic Go functions and types.
+	TypeParams []TypeParamEntity
+
+	// Instantiations contains the explicit instantiations of generic Go
+	// functions and types. Targets are resolved by ResolveInstantiations.
+	Instantiations []UnresolvedInstantiation
+
+	// Concurrency contains the goroutine spawns, channel sends and receives,
+	// and lock acquisitions of Go functions.
+	Concurrency []ConcurrencyEdge
+
+	// ErrorSites contains the error constructions, wraps and sentinels of Go
+	// files, the exceptions raised in Python and the errors thrown in
+	// JavaScript and TypeScript.
+	ErrorSites []ErrorSiteEntity
+
+	// Tests contains the tests, subtests and test suites of Go, pytest and
+	// Jest/Vitest test files.
+	Tests []TestEntity
+
+	// FunctionMetrics contains the complexity and size metrics of the Go,
+	// Python, JavaScript, TypeScript and PHP functions.
+	FunctionMetrics []FunctionMetricsEntity
+
+	// PackageName is the package name for Go files (e.g., "handlers", "main")
+	// or the namespace for PHP files (e.g., "App\Services").
 	// Empty for other languages.
 	PackageName string
+
+	// PackageDoc is the package doc comment of a Go file (the comment above
+	// the package clause). Empty for other languages.
+	PackageDoc string
 }
 
 // ParseFile parses a source file and extracts functions.
@@ -150,6 +220,10 @@ func (p *Parser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
 	// Extract functions based on language
 	var functions []FunctionEntity
 	var calls []CallsEdge
+	var sqlSchema SQLSchema
+	var graphQL graphQLSchemaResult
+	var topology Topology
+	var docs markdownDocsResult
 
 	switch fileInfo.Language {
 	case "go":
@@ -160,6 +234,14 @@ func (p *Parser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
 		functions, calls = p.parseJSFile(string(content), fileInfo.Path)
 	case "protobuf":
 		functions, calls = parseProtobufContent(string(content), fileInfo.Path, p.truncateCodeText)
+	case "sql":
+		sqlSchema = parseSQLSchema(string(content), fileInfo.Path, p.truncateCodeText)
+	case "graphql":
+		graphQL = parseGraphQLSchema(string(content), fileInfo.Path, p.truncateCodeText)
+	case "dockerfile", "yaml", "terraform":
+		topology = parseTopology(string(content), fileInfo.Path, fileInfo.Language)
+	case "markdown":
+		docs = parseMarkdownDocs(string(content), fileInfo.Path, p.truncateCodeText)
 	default:
 		// For unsupported languages, return empty result
 		p.logger.Debug("parser.skip_unsupported_language",
@@ -177,10 +259,24 @@ func (p *Parser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
 		}
 	}
 
+	var graphQLResolvers []GraphQLResolverEdge
+	if fileInfo.Language == "javascript" || fileInfo.Language == "typescript" {
+		graphQLResolvers = extractApolloResolvers(string(content), fileInfo.Path, functions)
+	}
+
 	return &ParseResult{
-		File:      fileEntity,
-		Functions: functions,
-		Defines:   defines,
-		Calls:     calls,
+		File:             fileEntity,
+		Functions:        functions,
+		Types:            graphQL.Types,
+		Defines:          defines,
+		Calls:            calls,
+		Implements:       graphQL.Implements,
+		SQLSchema:        sqlSchema,
+		TableAccesses:    extractTableAccesses(functions),
+		GraphQLFields:    graphQL.Fields,
+		GraphQLResolvers: graphQLResolvers,
+		Topology:         topology,
+		DocChunks:        docs.Chunks,
+		DocLinks:         docs.Links,
 	}, nil
 }
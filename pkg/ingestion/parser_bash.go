@@ -0,0 +1,313 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// SHELL PARSER
+// =============================================================================
+
+// shellPathPrefixPattern matches a leading variable or command substitution used
+// as a base directory: "$SCRIPT_DIR/", "${ROOT}/", "$(dirname "$0")/".
+var shellPathPrefixPattern = regexp.MustCompile(`^(\$\{?[A-Za-z_][A-Za-z0-9_]*\}?|\$\([^)]*\))/`)
+
+// shellWrapperCommands run their first argument as a command (exec ./x.sh, bash x.sh).
+var shellWrapperCommands = map[string]bool{
+	"bash": true, "sh": true, "zsh": true, "exec": true, "sudo": true,
+	"nohup": true, "time": true, "env": true, "xargs": true,
+}
+
+// shellBuiltinCommands are builtins and ubiquitous utilities that can never
+// resolve to repository code; skipping them keeps unresolved calls small.
+var shellBuiltinCommands = map[string]bool{
+	"echo": true, "printf": true, "cd": true, "pwd": true, "export": true,
+	"set": true, "unset": true, "shift": true, "exit": true, "return": true,
+	"read": true, "test": true, "[": true, "[[": true, "true": true, "false": true,
+	"eval": true, "trap": true, "wait": true, "local": true, "declare": true,
+	"readonly": true, "command": true, "type": true, "dirname": true, "basename": true,
+	"cat": true, "grep": true, "sed": true, "awk": true, "mkdir": true, "rm": true,
+	"cp": true, "mv": true, "ls": true, "touch": true, "chmod": true, "sleep": true,
+	"go": true, "make": true, "docker": true, "git": true,
+}
+
+// shellFunctionWithNodes pairs a function entity with the AST nodes whose commands belong to it.
+type shellFunctionWithNodes struct {
+	entity FunctionEntity
+	nodes  []*sitter.Node
+}
+
+// parseBashAST extracts functions, `source` imports and command invocations from shell scripts.
+//
+// Extracts:
+//   - Functions (both `name() { ... }` and `function name { ... }`)
+//   - Top-level commands as a synthetic "$main" function (the script entry point)
+//   - `source file` / `. file` includes (as ImportEntity)
+//   - Invocations of local functions (as CallsEdge)
+//   - Other command invocations (as UnresolvedCall): repository scripts and
+//     binaries are resolved later by the CallResolver
+func (p *TreeSitterParser) parseBashAST(parser *sitter.Parser, content []byte, filePath string) ([]FunctionEntity, []CallsEdge, []ImportEntity, []UnresolvedCall, error) {
+	tree, err := parser.ParseCtx(context.Background(), nil, content)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("tree-sitter parse: %w", err)
+	}
+	defer tree.Close()
+
+	rootNode := tree.RootNode()
+	if rootNode.HasError() {
+		if errorCount := countErrors(rootNode); errorCount > 0 {
+			p.logger.Warn("parser.treesitter.bash.syntax_errors",
+				"path", filePath,
+				"error_count", errorCount,
+			)
+		}
+	}
+
+	var functions []shellFunctionWithNodes
+	var topLevel []*sitter.Node
+	funcNameToID := make(map[string]string)
+
+	for i := 0; i < int(rootNode.NamedChildCount()); i++ {
+		child := rootNode.NamedChild(i)
+		switch child.Type() {
+		case "comment":
+			continue
+		case "function_definition":
+			if fn := p.extractBashFunction(child, content, filePath); fn != nil {
+				functions = append(functions, shellFunctionWithNodes{entity: *fn, nodes: []*sitter.Node{child.ChildByFieldName("body")}})
+				funcNameToID[fn.Name] = fn.ID
+			}
+		default:
+			topLevel = append(topLevel, child)
+		}
+	}
+
+	if len(topLevel) > 0 {
+		mainFn := p.buildBashMainFunction(topLevel, content, filePath)
+		functions = append(functions, shellFunctionWithNodes{entity: mainFn, nodes: topLevel})
+	}
+
+	var calls []CallsEdge
+	var imports []ImportEntity
+	var unresolved []UnresolvedCall
+	seenImports := make(map[string]bool)
+
+	for _, fn := range functions {
+		seenLocal := make(map[string]bool)
+		seenUnresolved := make(map[string]bool)
+		for _, node := range fn.nodes {
+			p.walkBashCommands(node, content, func(cmd *sitter.Node, name string, args []string) {
+				line := int(cmd.StartPoint().Row) + 1
+
+				if name == "source" || name == "." {
+					if len(args) > 0 && !seenImports[args[0]] {
+						seenImports[args[0]] = true
+						imports = append(imports, ImportEntity{
+							ID:         GenerateImportID(filePath, args[0]),
+							FilePath:   filePath,
+							ImportPath: args[0],
+							StartLine:  line,
+						})
+					}
+					return
+				}
+
+				if calleeID, ok := funcNameToID[name]; ok {
+					if calleeID != fn.entity.ID && !seenLocal[calleeID] {
+						seenLocal[calleeID] = true
+						calls = append(calls, CallsEdge{CallerID: fn.entity.ID, CalleeID: calleeID, CallLine: line})
+					}
+					return
+				}
+
+				target := shellInvocationTarget(name, args)
+				if target == "" || seenUnresolved[target] {
+					return
+				}
+				seenUnresolved[target] = true
+				unresolved = append(unresolved, UnresolvedCall{
+					CallerID:   fn.entity.ID,
+					CalleeName: target,
+					FilePath:   filePath,
+					Line:       line,
+				})
+			})
+		}
+	}
+
+	entities := make([]FunctionEntity, len(functions))
+	for i, fn := range functions {
+		entities[i] = fn.entity
+	}
+
+	return entities, calls, imports, unresolved, nil
+}
+
+// extractBashFunction extracts a shell function definition.
+func (p *TreeSitterParser) extractBashFunction(node *sitter.Node, content []byte, filePath string) *FunctionEntity {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nodeText(nameNode, content)
+
+	startLine := int(node.StartPoint().Row) + 1
+	endLine := int(node.EndPoint().Row) + 1
+	startCol := int(node.StartPoint().Column) + 1
+	endCol := int(node.EndPoint().Column) + 1
+
+	signature := name + "()"
+	codeText := p.truncateCodeText(nodeText(node, content))
+
+	return &FunctionEntity{
+		ID:        GenerateFunctionID(filePath, name, signature, startLine, endLine, startCol, endCol),
+		Name:      name,
+		Signature: signature,
+		FilePath:  filePath,
+		CodeText:  codeText,
+		StartLine: startLine,
+		EndLine:   endLine,
+		StartCol:  startCol,
+		EndCol:    endCol,
+	}
+}
+
+// buildBashMainFunction wraps a script's top-level commands into a synthetic
+// "$main" function. Invocations of the script from other scripts link to it.
+func (p *TreeSitterParser) buildBashMainFunction(topLevel []*sitter.Node, content []byte, filePath string) FunctionEntity {
+	first := topLevel[0]
+	last := topLevel[len(topLevel)-1]
+
+	startLine := int(first.StartPoint().Row) + 1
+	endLine := int(last.EndPoint().Row) + 1
+	startCol := int(first.StartPoint().Column) + 1
+	endCol := int(last.EndPoint().Column) + 1
+
+	signature := scriptMainFunctionName + "()"
+	codeText := p.truncateCodeText(string(content[first.StartByte():last.EndByte()]))
+
+	return FunctionEntity{
+		ID:        GenerateFunctionID(filePath, scriptMainFunctionName, signature, startLine, endLine, startCol, endCol),
+		Name:      scriptMainFunctionName,
+		Signature: signature,
+		FilePath:  filePath,
+		CodeText:  codeText,
+		StartLine: startLine,
+		EndLine:   endLine,
+		StartCol:  startCol,
+		EndCol:    endCol,
+	}
+}
+
+// walkBashCommands calls visit for every simple command under node, including
+// commands inside pipelines, conditionals and command substitutions.
+// Nested function definitions are skipped; they are extracted separately.
+func (p *TreeSitterParser) walkBashCommands(node *sitter.Node, content []byte, visit func(cmd *sitter.Node, name string, args []string)) {
+	if node == nil {
+		return
+	}
+	if node.Type() == "function_definition" {
+		return
+	}
+
+	if node.Type() == "command" {
+		if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+			var args []string
+			for i := 0; i < int(node.ChildCount()); i++ {
+				if node.FieldNameForChild(i) == "argument" {
+					args = append(args, shellWordText(node.Child(i), content))
+				}
+			}
+			visit(node, shellWordText(nameNode, content), args)
+		}
+	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		p.walkBashCommands(node.Child(i), content, visit)
+	}
+}
+
+// shellWordText returns a word with surrounding quotes removed.
+func shellWordText(node *sitter.Node, content []byte) string {
+	text := nodeText(node, content)
+	if len(text) >= 2 && (text[0] == '"' || text[0] == '\'') && text[len(text)-1] == text[0] {
+		text = text[1 : len(text)-1]
+	}
+	return text
+}
+
+// shellInvocationTarget returns the name under which an invocation is recorded
+// as an unresolved call, or "" if it cannot refer to repository code.
+//
+//	./scripts/migrate.sh --up        → "./scripts/migrate.sh"
+//	bash "$DIR/notify.sh"            → "$DIR/notify.sh"
+//	go run ./cmd/worker              → "./cmd/worker"
+//	worker --once                    → "worker"
+func shellInvocationTarget(name string, args []string) string {
+	// Unwrap interpreters and wrappers: bash x.sh, exec ./bin/worker, sudo -E cie
+	for shellWrapperCommands[name] {
+		next := ""
+		for len(args) > 0 {
+			arg := args[0]
+			args = args[1:]
+			if !strings.HasPrefix(arg, "-") && !strings.Contains(arg, "=") {
+				next = arg
+				break
+			}
+		}
+		if next == "" {
+			return ""
+		}
+		name = next
+	}
+
+	if name == "go" && len(args) >= 2 && args[0] == "run" {
+		for _, arg := range args[1:] {
+			if !strings.HasPrefix(arg, "-") {
+				return arg
+			}
+		}
+		return ""
+	}
+
+	if name == "" || shellBuiltinCommands[name] {
+		return ""
+	}
+	// Dynamic command names ("$CMD", "$(which x)") can't be resolved unless they
+	// are a path under a directory variable ("$ROOT/scripts/x.sh")
+	if strings.ContainsAny(name, "$`(") && !shellPathPrefixPattern.MatchString(name) {
+		return ""
+	}
+	return name
+}
+
+// normalizeShellPath strips a leading directory variable and "./" from a
+// script path: "$SCRIPT_DIR/lib/common.sh" → "lib/common.sh".
+func normalizeShellPath(path string) string {
+	path = shellPathPrefixPattern.ReplaceAllString(path, "")
+	return strings.TrimPrefix(path, "./")
+}
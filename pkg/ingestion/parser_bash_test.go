@@ -0,0 +1,116 @@
+package ingestion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseBashTestFile is a helper that reads a shell test fixture and parses it.
+func parseBashTestFile(t *testing.T, fixturePath string) *ParseResult {
+	t.Helper()
+
+	code, err := os.ReadFile(fixturePath)
+	require.NoError(t, err, "Failed to read test fixture: %s", fixturePath)
+
+	tmpFile := filepath.Join(t.TempDir(), filepath.Base(fixturePath))
+	err = os.WriteFile(tmpFile, code, 0644)
+	require.NoError(t, err, "Failed to write temp file")
+
+	parser := NewTreeSitterParser(nil)
+	result, err := parser.ParseFile(FileInfo{
+		Path:     filepath.Base(fixturePath),
+		FullPath: tmpFile,
+		Size:     int64(len(code)),
+		Language: "bash",
+	})
+	require.NoError(t, err, "Parser should not error on valid shell code")
+
+	return result
+}
+
+// TestBashParser_Functions tests both function syntaxes and the synthetic $main.
+func TestBashParser_Functions(t *testing.T) {
+	result := parseBashTestFile(t, "testdata/bash/deploy.sh")
+
+	build := findParsedFunction(result, "build")
+	require.NotNil(t, build, "Should find name() { } function")
+	assert.Equal(t, "build()", build.Signature)
+	assert.Equal(t, 8, build.StartLine)
+
+	require.NotNil(t, findParsedFunction(result, "deploy"), "Should find `function name { }` function")
+
+	main := findParsedFunction(result, scriptMainFunctionName)
+	require.NotNil(t, main, "Top-level commands should produce a $main function")
+	assert.Contains(t, main.CodeText, `deploy "${1:-staging}"`)
+}
+
+// TestBashParser_SourceImports tests that `source` and `.` become imports.
+func TestBashParser_SourceImports(t *testing.T) {
+	result := parseBashTestFile(t, "testdata/bash/deploy.sh")
+
+	var paths []string
+	for _, imp := range result.Imports {
+		paths = append(paths, imp.ImportPath)
+	}
+	assert.ElementsMatch(t, []string{"$SCRIPT_DIR/lib/common.sh", "./lib/env.sh"}, paths)
+}
+
+// TestBashParser_Calls tests local function calls and unresolved invocation targets.
+func TestBashParser_Calls(t *testing.T) {
+	result := parseBashTestFile(t, "testdata/bash/deploy.sh")
+
+	build := findParsedFunction(result, "build")
+	deploy := findParsedFunction(result, "deploy")
+	main := findParsedFunction(result, scriptMainFunctionName)
+	require.NotNil(t, build)
+	require.NotNil(t, deploy)
+	require.NotNil(t, main)
+
+	local := make(map[string]bool)
+	for _, c := range result.Calls {
+		local[c.CallerID+"->"+c.CalleeID] = true
+	}
+	assert.True(t, local[deploy.ID+"->"+build.ID], "deploy should call build")
+	assert.True(t, local[main.ID+"->"+deploy.ID], "$main should call deploy")
+
+	unresolved := make(map[string]string)
+	for _, c := range result.UnresolvedCalls {
+		unresolved[c.CalleeName] = c.CallerID
+	}
+	assert.Equal(t, build.ID, unresolved["log_info"], "Function from a sourced file is resolved cross-file")
+	assert.Equal(t, deploy.ID, unresolved["./scripts/migrate.sh"])
+	assert.Equal(t, deploy.ID, unresolved["$SCRIPT_DIR/notify.sh"], "bash <script> should unwrap the interpreter")
+	assert.Equal(t, deploy.ID, unresolved["kubectl"])
+	assert.Equal(t, deploy.ID, unresolved["worker"])
+	assert.Equal(t, main.ID, unresolved["./cmd/cie"], "go run <pkg> should target the package")
+
+	for _, skipped := range []string{"set", "local", "go", "cd", "source"} {
+		_, ok := unresolved[skipped]
+		assert.False(t, ok, "%s should not be recorded as a call", skipped)
+	}
+}
+
+// TestShellInvocationTarget tests wrapper unwrapping and dynamic command filtering.
+func TestShellInvocationTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"./scripts/migrate.sh", []string{"--up"}, "./scripts/migrate.sh"},
+		{"sudo", []string{"-E", "bash", "x.sh"}, "x.sh"},
+		{"env", []string{"FOO=1", "./bin/worker"}, "./bin/worker"},
+		{"go", []string{"run", "-race", "./cmd/worker"}, "./cmd/worker"},
+		{"go", []string{"test", "./..."}, ""},
+		{"$CMD", nil, ""},
+		{"${ROOT}/scripts/x.sh", nil, "${ROOT}/scripts/x.sh"},
+		{"echo", []string{"hi"}, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, shellInvocationTarget(tt.name, tt.args), "%s %v", tt.name, tt.args)
+	}
+}
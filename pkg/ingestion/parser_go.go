@@ -51,10 +51,20 @@ type goParseResult struct {
 	Functions       []FunctionEntity
 	Types           []TypeEntity
 	Fields          []FieldEntity
+	Variables       []VariableEntity
 	Calls           []CallsEdge
 	Imports         []ImportEntity
 	UnresolvedCalls []UnresolvedCall
+	FuncRefs        []UnresolvedFuncRef
+	TypeRefs        []UnresolvedTypeRef
+	TypeParams      []TypeParamEntity
+	Instantiations  []UnresolvedInstantiation
+	Concurrency     []ConcurrencyEdge
+	ErrorSites      []ErrorSiteEntity
+	Tests           []TestEntity
+	Metrics         []FunctionMetricsEntity
 	PackageName     string
+	PackageDoc      string
 }
 
 // parseGoAST extracts functions, types, and call relationships from Go source using Tree-sitter.
@@ -64,9 +74,14 @@ type goParseResult struct {
 //   - Methods (func with receivers)
 //   - Function literals / closures
 //   - Types (structs, interfaces)
+//   - Package-level var and const declarations
 //   - Function calls within the file
 //   - Unresolved calls (for cross-package resolution)
-//   - Package name
+//   - Function values passed, assigned or returned (resolved later by ResolveFuncRefs)
+//   - Type references (resolved later by ResolveTypeRefs)
+//   - Type parameters, and instantiations (resolved later by ResolveInstantiations)
+//   - Goroutine spawns, channel sends/receives and lock acquisitions
+//   - Package name and package doc comment
 //
 // This is the primary parser for Go code, providing the most accurate results.
 func (p *TreeSitterParser) parseGoAST(parser *sitter.Parser, content []byte, filePath string) (*goParseResult, error) {
@@ -89,6 +104,7 @@ func (p *TreeSitterParser) parseGoAST(parser *sitter.Parser, content []byte, fil
 
 	// Extract package name
 	packageName := p.extractGoPackageName(rootNode, content)
+	packageDoc := extractGoPackageDoc(rootNode, content)
 
 	// Extract imports (before function extraction)
 	imports := p.extractGoImports(rootNode, content, filePath)
@@ -105,13 +121,19 @@ func (p *TreeSitterParser) parseGoAST(parser *sitter.Parser, content []byte, fil
 	// First pass: extract all functions with their AST nodes
 	p.walkGoAST(rootNode, ctx)
 
-	// Second pass: extract calls within each function using V2 (returns unresolved calls)
+	// Extract types (structs, interfaces, type aliases) and struct fields
+	types, fields := p.extractGoTypesAndFields(rootNode, content, filePath)
+
+	// Second pass: extract calls within each function using V2 (returns unresolved calls),
+	// along with goroutine spawns, channel operations and lock acquisitions
 	var calls []CallsEdge
 	var unresolvedCalls []UnresolvedCall
+	conc := newGoConcurrencyCollector(ctx, fields)
 	for _, fnWithNode := range ctx.functions {
+		conc.enter(fnWithNode)
 		localCalls, unresolved := p.extractGoCallsFromNodeV2(
 			fnWithNode.node, content, fnWithNode.entity.ID,
-			ctx.funcNameToID, filePath)
+			ctx.funcNameToID, filePath, conc)
 		calls = append(calls, localCalls...)
 		unresolvedCalls = append(unresolvedCalls, unresolved...)
 	}
@@ -122,17 +144,45 @@ func (p *TreeSitterParser) parseGoAST(parser *sitter.Parser, content []byte, fil
 		functions[i] = fn.entity
 	}
 
-	// Extract types (structs, interfaces, type aliases) and struct fields
-	types, fields := p.extractGoTypesAndFields(rootNode, content, filePath)
+	// Extract package-level var and const declarations
+	variables := p.extractGoVariables(rootNode, content, filePath)
+
+	// Extract function values used as callbacks and handlers
+	funcRefs := p.extractGoFuncRefs(ctx)
+
+	// Extract type names referenced and generics instantiated by functions and types
+	typeRefs, instantiations := p.extractGoTypeRefs(rootNode, ctx, types)
+
+	// Extract type parameters of generic functions and types
+	typeParams := p.extractGoTypeParams(rootNode, ctx, types)
+
+	// Extract error constructions, wraps, sentinels and error types
+	errorSites := extractGoErrorSites(rootNode, content, filePath, functions)
+
+	// Extract test functions and subtests of _test.go files
+	tests := extractGoTests(rootNode, content, filePath, functions)
+
+	// Compute complexity and size metrics of each function
+	metrics := extractFunctionMetrics(rootNode, content, "go", functions)
 
 	return &goParseResult{
 		Functions:       functions,
 		Types:           types,
 		Fields:          fields,
+		Variables:       variables,
 		Calls:           calls,
 		Imports:         imports,
 		UnresolvedCalls: unresolvedCalls,
+		FuncRefs:        funcRefs,
+		TypeRefs:        typeRefs,
+		TypeParams:      typeParams,
+		Instantiations:  instantiations,
+		Concurrency:     conc.edges,
+		ErrorSites:      errorSites,
+		Tests:           tests,
+		Metrics:         metrics,
 		PackageName:     packageName,
+		PackageDoc:      packageDoc,
 	}, nil
 }
 
@@ -342,16 +392,23 @@ func (p *TreeSitterParser) createGoFunctionEntity(node *sitter.Node, ctx *goFunc
 	// Generate deterministic ID
 	id := GenerateFunctionID(ctx.filePath, name, signature, startLine, endLine, startCol, endCol)
 
+	// Doc comment: only declarations are documented, not closures
+	var docComment string
+	if node.Type() != "func_literal" {
+		docComment = goDocComment(node, ctx.content)
+	}
+
 	return &FunctionEntity{
-		ID:        id,
-		Name:      name,
-		Signature: signature,
-		FilePath:  ctx.filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Signature:  signature,
+		FilePath:   ctx.filePath,
+		DocComment: docComment,
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -509,6 +566,19 @@ func (p *TreeSitterParser) extractGoCalleeNameFull(node *sitter.Node, content []
 	return ""
 }
 
+// extractGoPackageDoc returns the doc comment above the package clause of a Go file.
+func extractGoPackageDoc(rootNode *sitter.Node, content []byte) string {
+	if rootNode == nil {
+		return ""
+	}
+	for i := 0; i < int(rootNode.ChildCount()); i++ {
+		if child := rootNode.Child(i); child.Type() == "package_clause" {
+			return goDocComment(child, content)
+		}
+	}
+	return ""
+}
+
 // extractGoPackageName extracts the package name from a Go source file.
 func (p *TreeSitterParser) extractGoPackageName(rootNode *sitter.Node, content []byte) string {
 	if rootNode == nil {
@@ -642,10 +712,12 @@ func (p *TreeSitterParser) extractGoImportSpec(node *sitter.Node, content []byte
 }
 
 // extractGoCallsFromNodeV2 extracts calls from a function, returning both
-// resolved (same-file) calls and unresolved (cross-package) calls.
+// resolved (same-file) calls and unresolved (cross-package) calls. The
+// function's concurrency operations are recorded in conc.
 func (p *TreeSitterParser) extractGoCallsFromNodeV2(
 	fnNode *sitter.Node, content []byte, callerID string,
 	funcNameToID map[string]string, filePath string,
+	conc *goConcurrencyCollector,
 ) ([]CallsEdge, []UnresolvedCall) {
 	var localCalls []CallsEdge
 	var unresolvedCalls []UnresolvedCall
@@ -676,32 +748,50 @@ func (p *TreeSitterParser) extractGoCallsFromNodeV2(
 
 	// Walk to find call expressions
 	p.walkGoCallExpressionsV2(bodyNode, content, callerID, funcNameToID, filePath,
-		&localCalls, &unresolvedCalls, seenLocal, seenUnresolved)
+		&localCalls, &unresolvedCalls, seenLocal, seenUnresolved, conc)
 
 	return localCalls, unresolvedCalls
 }
 
 // walkGoCallExpressionsV2 finds call expressions and categorizes them as local or unresolved.
+// Concurrency operations are passed to conc, which is nil inside nested
+// function literals: their operations belong to the literal.
 func (p *TreeSitterParser) walkGoCallExpressionsV2(
 	node *sitter.Node, content []byte, callerID string,
 	funcNameToID map[string]string, filePath string,
 	localCalls *[]CallsEdge, unresolvedCalls *[]UnresolvedCall,
 	seenLocal, seenUnresolved map[string]bool,
+	conc *goConcurrencyCollector,
 ) {
 	if node == nil {
 		return
 	}
 
-	if node.Type() == "call_expression" {
+	if conc != nil {
+		if node.Type() == "func_literal" {
+			conc = nil
+		} else {
+			conc.visit(node)
+		}
+	}
+
+	switch node.Type() {
+	case "call_expression":
 		p.processGoCallExpression(node, content, callerID, funcNameToID, filePath,
 			localCalls, unresolvedCalls, seenLocal, seenUnresolved)
+	case "type_conversion_expression":
+		// pkg.Map[int](x) parses as a conversion to the generic type pkg.Map[int]
+		if simpleName, fullName := goGenericCalleeNames(node, content); simpleName != "" {
+			p.recordGoCall(node, simpleName, fullName, callerID, funcNameToID, filePath,
+				localCalls, unresolvedCalls, seenLocal, seenUnresolved)
+		}
 	}
 
 	// Recurse into children
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		p.walkGoCallExpressionsV2(child, content, callerID, funcNameToID, filePath,
-			localCalls, unresolvedCalls, seenLocal, seenUnresolved)
+			localCalls, unresolvedCalls, seenLocal, seenUnresolved, conc)
 	}
 }
 
@@ -725,6 +815,18 @@ func (p *TreeSitterParser) processGoCallExpression(
 		return
 	}
 
+	p.recordGoCall(node, simpleName, fullName, callerID, funcNameToID, filePath,
+		localCalls, unresolvedCalls, seenLocal, seenUnresolved)
+}
+
+// recordGoCall records a call to simpleName (fullName as written) as a local
+// call when a function of the file has that name, else as an unresolved call.
+func (p *TreeSitterParser) recordGoCall(
+	node *sitter.Node, simpleName, fullName, callerID string,
+	funcNameToID map[string]string, filePath string,
+	localCalls *[]CallsEdge, unresolvedCalls *[]UnresolvedCall,
+	seenLocal, seenUnresolved map[string]bool,
+) {
 	calleeID, exists := funcNameToID[simpleName]
 	if exists {
 		if calleeID != callerID {
@@ -749,6 +851,31 @@ func (p *TreeSitterParser) processGoCallExpression(
 	}
 }
 
+// goGenericCalleeNames returns the simple and full names of the generic
+// function called by a type_conversion_expression such as pkg.Map[int](x) or
+// Map[int, string](x): "Map" and "pkg.Map". Returns empty names for
+// conversions to non-generic types.
+func goGenericCalleeNames(node *sitter.Node, content []byte) (simpleName, fullName string) {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil || typeNode.Type() != "generic_type" {
+		return "", ""
+	}
+	nameNode := typeNode.ChildByFieldName("type")
+	if nameNode == nil {
+		return "", ""
+	}
+	switch nameNode.Type() {
+	case "type_identifier":
+		name := nodeText(nameNode, content)
+		return name, name
+	case "qualified_type":
+		if nameField := nameNode.ChildByFieldName("name"); nameField != nil {
+			return nodeText(nameField, content), nodeText(nameNode, content)
+		}
+	}
+	return "", ""
+}
+
 // addUnresolvedCall stores an unresolved call if not already seen.
 func (p *TreeSitterParser) addUnresolvedCall(
 	node *sitter.Node, callerID, calleeName, filePath string,
@@ -1245,15 +1372,16 @@ func (p *TreeSitterParser) extractGoTypeSpec(node *sitter.Node, content []byte,
 	id := GenerateTypeID(filePath, name, startLine, endLine)
 
 	return &TypeEntity{
-		ID:        id,
-		Name:      name,
-		Kind:      kind,
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Kind:       kind,
+		FilePath:   filePath,
+		DocComment: goDocComment(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -0,0 +1,400 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"strings"
+)
+
+// =============================================================================
+// GRAPHQL SDL PARSER (simplified, no tree-sitter)
+// =============================================================================
+
+// graphQLTypeKinds maps SDL definition keywords to cie_type kinds.
+var graphQLTypeKinds = map[string]string{
+	"type":      "graphql_object",
+	"interface": "graphql_interface",
+	"input":     "graphql_input",
+	"enum":      "graphql_enum",
+	"union":     "graphql_union",
+	"scalar":    "graphql_scalar",
+}
+
+// graphQLDefaultRoots maps the default root type names to operation kinds.
+var graphQLDefaultRoots = map[string]string{
+	"Query":        "query",
+	"Mutation":     "mutation",
+	"Subscription": "subscription",
+}
+
+// graphQLSchemaResult holds entities extracted from a GraphQL SDL file.
+type graphQLSchemaResult struct {
+	Types      []TypeEntity
+	Fields     []GraphQLFieldEntity
+	Implements []ImplementsEdge
+}
+
+// graphQLToken is a lexical token of GraphQL SDL.
+type graphQLToken struct {
+	kind   byte // 'n' name, 's' string, 'p' punctuator, 'v' other value
+	text   string
+	offset int
+	line   int
+}
+
+// parseGraphQLSchema extracts types, fields and interface implementations from
+// GraphQL SDL (.graphql, .graphqls).
+//
+// Extracts:
+//   - type, interface, input, enum, union, scalar definitions (and extensions) as types
+//   - fields of object, interface and input types, with arguments and return type
+//   - "implements A & B" as implements edges
+//
+// Fields of Query, Mutation and Subscription (or the roots declared in a
+// schema block in the same file) are marked with their operation kind.
+func parseGraphQLSchema(content, filePath string, truncate func(string) string) graphQLSchemaResult {
+	var result graphQLSchemaResult
+	tokens := lexGraphQL(content)
+
+	roots := make(map[string]string, len(graphQLDefaultRoots))
+	for name, op := range graphQLDefaultRoots {
+		roots[name] = op
+	}
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].kind == 'n' && tokens[i].text == "schema" && (i == 0 || tokens[i-1].text != "extend") {
+			parseGraphQLSchemaBlock(tokens[i+1:], roots)
+		}
+	}
+
+	depth := 0
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.text {
+		case "{", "(":
+			depth++
+		case "}", ")":
+			depth--
+		}
+		// Definitions are top-level; a field or enum value may be named "type"
+		kind, ok := graphQLTypeKinds[tok.text]
+		if tok.kind != 'n' || !ok || depth != 0 || i+1 >= len(tokens) || tokens[i+1].kind != 'n' {
+			continue
+		}
+
+		start := i
+		if i > 0 && tokens[i-1].text == "extend" {
+			start = i - 1
+		}
+		name := tokens[i+1].text
+		j := i + 2
+
+		if (tok.text == "type" || tok.text == "interface") && j < len(tokens) && tokens[j].text == "implements" {
+			for j++; j < len(tokens) && (tokens[j].kind == 'n' || tokens[j].text == "&"); j++ {
+				if tokens[j].kind == 'n' {
+					if j+1 < len(tokens) && tokens[j+1].kind == 'n' && tokens[j-1].text != "&" && tokens[j-1].text != "implements" {
+						break // next definition starts
+					}
+					result.Implements = append(result.Implements, ImplementsEdge{
+						TypeName:      name,
+						InterfaceName: tokens[j].text,
+						FilePath:      filePath,
+					})
+				}
+			}
+		}
+		j = skipGraphQLDirectives(tokens, j)
+		end := j - 1
+
+		if tok.text == "union" && j < len(tokens) && tokens[j].text == "=" {
+			j++
+			if j < len(tokens) && tokens[j].text == "|" {
+				j++
+			}
+			for j < len(tokens) && tokens[j].kind == 'n' {
+				end = j
+				j++
+				if j >= len(tokens) || tokens[j].text != "|" {
+					break
+				}
+				j++
+			}
+		}
+		if j < len(tokens) && tokens[j].text == "{" {
+			close := matchGraphQLBrace(tokens, j)
+			if tok.text != "enum" {
+				operation := ""
+				if tok.text == "type" {
+					operation = roots[name]
+				}
+				result.Fields = append(result.Fields, parseGraphQLFields(tokens[j+1:close], content, name, operation, filePath)...)
+			}
+			end = close
+		}
+
+		startLine := tokens[start].line
+		endLine := tokens[end].line
+		codeText := content[tokens[start].offset : tokens[end].offset+len(tokens[end].text)]
+		result.Types = append(result.Types, TypeEntity{
+			ID:        GenerateTypeID(filePath, name, startLine, endLine),
+			Name:      name,
+			Kind:      kind,
+			FilePath:  filePath,
+			CodeText:  truncate(codeText),
+			StartLine: startLine,
+			EndLine:   endLine,
+			StartCol:  1,
+			EndCol:    1,
+		})
+		i = end
+	}
+
+	return result
+}
+
+// parseGraphQLSchemaBlock reads "schema { query: RootQuery mutation: RootMutation }"
+// and maps the named root types to their operation kinds.
+func parseGraphQLSchemaBlock(tokens []graphQLToken, roots map[string]string) {
+	j := skipGraphQLDirectives(tokens, 0)
+	if j >= len(tokens) || tokens[j].text != "{" {
+		return
+	}
+	close := matchGraphQLBrace(tokens, j)
+	for k := j + 1; k+2 <= close; k++ {
+		if tokens[k].kind == 'n' && tokens[k+1].text == ":" && tokens[k+2].kind == 'n' {
+			switch tokens[k].text {
+			case "query", "mutation", "subscription":
+				roots[tokens[k+2].text] = tokens[k].text
+			}
+			k += 2
+		}
+	}
+}
+
+// parseGraphQLFields parses field definitions between the braces of a type:
+//
+//	"Description" name(arg: Type = default): ReturnType @directive
+func parseGraphQLFields(tokens []graphQLToken, content, typeName, operation, filePath string) []GraphQLFieldEntity {
+	var fields []GraphQLFieldEntity
+
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].kind != 'n' {
+			continue
+		}
+		nameTok := tokens[i]
+		j := i + 1
+
+		var args string
+		if j < len(tokens) && tokens[j].text == "(" {
+			close := matchGraphQLParen(tokens, j)
+			if close > j+1 {
+				args = strings.Join(strings.Fields(content[tokens[j+1].offset:tokens[close].offset]), " ")
+			}
+			j = close + 1
+		}
+		if j >= len(tokens) || tokens[j].text != ":" {
+			continue
+		}
+		j++
+
+		typeStart := j
+		j = scanGraphQLTypeRef(tokens, j)
+		if j == typeStart {
+			continue
+		}
+		var fieldType strings.Builder
+		for _, t := range tokens[typeStart:j] {
+			fieldType.WriteString(t.text)
+		}
+
+		fields = append(fields, GraphQLFieldEntity{
+			ID:        GenerateGraphQLFieldID(filePath, typeName, nameTok.text),
+			TypeName:  typeName,
+			Name:      nameTok.text,
+			FieldType: fieldType.String(),
+			Arguments: args,
+			Operation: operation,
+			FilePath:  filePath,
+			Line:      nameTok.line,
+		})
+
+		// Skip default value (input fields) and directives up to the next field
+		j = skipGraphQLDefault(tokens, j)
+		j = skipGraphQLDirectives(tokens, j)
+		i = j - 1
+	}
+
+	return fields
+}
+
+// scanGraphQLTypeRef returns the index after the type reference starting at
+// tokens[i] (Name, [Type], optionally followed by "!"), or i if there is none.
+func scanGraphQLTypeRef(tokens []graphQLToken, i int) int {
+	if i >= len(tokens) {
+		return i
+	}
+	j := i
+	switch {
+	case tokens[j].text == "[":
+		inner := scanGraphQLTypeRef(tokens, j+1)
+		if inner == j+1 || inner >= len(tokens) || tokens[inner].text != "]" {
+			return i
+		}
+		j = inner + 1
+	case tokens[j].kind == 'n':
+		j++
+	default:
+		return i
+	}
+	if j < len(tokens) && tokens[j].text == "!" {
+		j++
+	}
+	return j
+}
+
+// skipGraphQLDirectives skips "@name" and "@name(args)" starting at tokens[i].
+func skipGraphQLDirectives(tokens []graphQLToken, i int) int {
+	for i+1 < len(tokens) && tokens[i].text == "@" {
+		i += 2
+		if i < len(tokens) && tokens[i].text == "(" {
+			i = matchGraphQLParen(tokens, i) + 1
+		}
+	}
+	return i
+}
+
+// skipGraphQLDefault skips an input value default ("= 10", "= [A, B]", "= {a: 1}").
+func skipGraphQLDefault(tokens []graphQLToken, i int) int {
+	if i >= len(tokens) || tokens[i].text != "=" {
+		return i
+	}
+	i++
+	if i >= len(tokens) {
+		return i
+	}
+	switch tokens[i].text {
+	case "[", "{":
+		depth := 0
+		for ; i < len(tokens); i++ {
+			switch tokens[i].text {
+			case "[", "{":
+				depth++
+			case "]", "}":
+				depth--
+			}
+			if depth == 0 {
+				return i + 1
+			}
+		}
+		return i
+	}
+	return i + 1
+}
+
+// matchGraphQLBrace returns the index of the "}" closing the "{" at tokens[open],
+// or the last token index if unbalanced.
+func matchGraphQLBrace(tokens []graphQLToken, open int) int {
+	return matchGraphQLPair(tokens, open, "{", "}")
+}
+
+// matchGraphQLParen returns the index of the ")" closing the "(" at tokens[open].
+func matchGraphQLParen(tokens []graphQLToken, open int) int {
+	return matchGraphQLPair(tokens, open, "(", ")")
+}
+
+func matchGraphQLPair(tokens []graphQLToken, open int, left, right string) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case left:
+			depth++
+		case right:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// lexGraphQL splits SDL into tokens. Comments, commas and whitespace are
+// dropped; strings and block strings (descriptions) are single tokens.
+func lexGraphQL(content string) []graphQLToken {
+	var tokens []graphQLToken
+	line := 1
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == ',' || c >= 0x80:
+			// Non-ASCII outside strings and comments is only a byte order mark
+			i++
+		case c == '#':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			start, startLine := i, line
+			if strings.HasPrefix(content[i:], `"""`) {
+				end := strings.Index(content[i+3:], `"""`)
+				if end < 0 {
+					end = len(content) - i - 3
+				}
+				i += 3 + end + 3
+			} else {
+				i++
+				for i < len(content) && content[i] != '"' && content[i] != '\n' {
+					if content[i] == '\\' {
+						i++
+					}
+					i++
+				}
+				i++
+			}
+			if i > len(content) {
+				i = len(content)
+			}
+			line += strings.Count(content[start:i], "\n")
+			tokens = append(tokens, graphQLToken{kind: 's', text: content[start:i], offset: start, line: startLine})
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			start := i
+			for i < len(content) && (content[i] == '_' || (content[i] >= 'a' && content[i] <= 'z') || (content[i] >= 'A' && content[i] <= 'Z') || (content[i] >= '0' && content[i] <= '9')) {
+				i++
+			}
+			tokens = append(tokens, graphQLToken{kind: 'n', text: content[start:i], offset: start, line: line})
+		case c == '-' || (c >= '0' && c <= '9'):
+			start := i
+			for i++; i < len(content) && strings.IndexByte("0123456789.eE+-", content[i]) >= 0; i++ {
+			}
+			tokens = append(tokens, graphQLToken{kind: 'v', text: content[start:i], offset: start, line: line})
+		case strings.HasPrefix(content[i:], "..."):
+			tokens = append(tokens, graphQLToken{kind: 'p', text: "...", offset: i, line: line})
+			i += 3
+		default:
+			tokens = append(tokens, graphQLToken{kind: 'p', text: string(c), offset: i, line: line})
+			i++
+		}
+	}
+
+	return tokens
+}
@@ -0,0 +1,122 @@
+package ingestion
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseGraphQLTestFile is a helper that reads a GraphQL SDL fixture and parses it.
+func parseGraphQLTestFile(t *testing.T, fixturePath string) graphQLSchemaResult {
+	t.Helper()
+
+	code, err := os.ReadFile(fixturePath)
+	require.NoError(t, err, "Failed to read test fixture: %s", fixturePath)
+
+	return parseGraphQLSchema(string(code), fixturePath, func(s string) string { return s })
+}
+
+// findGraphQLField returns the field with the given type and name or nil.
+func findGraphQLField(fields []GraphQLFieldEntity, typeName, name string) *GraphQLFieldEntity {
+	for i := range fields {
+		if fields[i].TypeName == typeName && fields[i].Name == name {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// TestGraphQLParser_Types tests type definitions, kinds and implements edges.
+func TestGraphQLParser_Types(t *testing.T) {
+	result := parseGraphQLTestFile(t, "testdata/graphql/schema.graphqls")
+
+	kinds := make(map[string]string)
+	var names []string
+	for _, typ := range result.Types {
+		kinds[typ.Name] = typ.Kind
+		names = append(names, typ.Name)
+	}
+	assert.Equal(t, []string{"Order", "Node", "OrderStatus", "CreateOrderInput", "SearchResult", "Time", "Query", "Mutation", "Query"}, names,
+		"Enum values and field names like \"type\" must not start definitions; extensions are types too")
+	assert.Equal(t, "graphql_object", kinds["Order"])
+	assert.Equal(t, "graphql_interface", kinds["Node"])
+	assert.Equal(t, "graphql_enum", kinds["OrderStatus"])
+	assert.Equal(t, "graphql_input", kinds["CreateOrderInput"])
+	assert.Equal(t, "graphql_union", kinds["SearchResult"])
+	assert.Equal(t, "graphql_scalar", kinds["Time"])
+
+	order := result.Types[0]
+	assert.Equal(t, 10, order.StartLine)
+	assert.Equal(t, 17, order.EndLine)
+	assert.Contains(t, order.CodeText, "created_at: Time!")
+
+	union := result.Types[4]
+	assert.Equal(t, "union SearchResult = | Order | User", union.CodeText)
+
+	extension := result.Types[8]
+	assert.Equal(t, "extend type Query {\n  me: User\n}", extension.CodeText)
+
+	var interfaces []string
+	for _, impl := range result.Implements {
+		assert.Equal(t, "Order", impl.TypeName)
+		interfaces = append(interfaces, impl.InterfaceName)
+	}
+	assert.Equal(t, []string{"Node", "Timestamped"}, interfaces)
+}
+
+// TestGraphQLParser_Fields tests field arguments, return types and operations.
+func TestGraphQLParser_Fields(t *testing.T) {
+	result := parseGraphQLTestFile(t, "testdata/graphql/schema.graphqls")
+
+	items := findGraphQLField(result.Fields, "Order", "items")
+	require.NotNil(t, items)
+	assert.Equal(t, "[LineItem!]!", items.FieldType)
+	assert.Equal(t, "first: Int = 10, after: String", items.Arguments)
+	assert.Empty(t, items.Operation, "Object fields are not operations")
+	assert.Equal(t, 14, items.Line)
+
+	status := findGraphQLField(result.Fields, "Order", "status")
+	require.NotNil(t, status, "Field descriptions should be skipped")
+	assert.Equal(t, "OrderStatus!", status.FieldType)
+
+	orders := findGraphQLField(result.Fields, "Query", "orders")
+	require.NotNil(t, orders)
+	assert.Equal(t, "query", orders.Operation)
+	assert.Equal(t, "status: OrderStatus first: Int = 20", orders.Arguments, "Multi-line arguments are collapsed")
+	assert.Equal(t, "[Order!]!", orders.FieldType, "Directives are not part of the type")
+
+	me := findGraphQLField(result.Fields, "Query", "me")
+	require.NotNil(t, me, "extend type fields should be extracted")
+	assert.Equal(t, "query", me.Operation)
+
+	create := findGraphQLField(result.Fields, "Mutation", "createOrder")
+	require.NotNil(t, create)
+	assert.Equal(t, "mutation", create.Operation)
+	assert.Equal(t, "input: CreateOrderInput!", create.Arguments)
+
+	input := findGraphQLField(result.Fields, "CreateOrderInput", "items")
+	require.NotNil(t, input)
+	assert.Equal(t, "[LineItemInput!]!", input.FieldType)
+	assert.NotNil(t, findGraphQLField(result.Fields, "CreateOrderInput", "note"), "Default values should be skipped")
+
+	for _, f := range result.Fields {
+		assert.NotEqual(t, "OrderStatus", f.TypeName, "Enum values are not fields")
+	}
+}
+
+// TestGraphQLParser_SchemaRoots tests custom root type names from a schema block.
+func TestGraphQLParser_SchemaRoots(t *testing.T) {
+	sdl := `schema { query: RootQuery subscription: Events }
+type RootQuery { ping: String }
+type Events { orderShipped(id: ID!): Order }
+type Query { notRoot: String }`
+
+	result := parseGraphQLSchema(sdl, "schema.graphql", func(s string) string { return s })
+
+	assert.Equal(t, "query", findGraphQLField(result.Fields, "RootQuery", "ping").Operation)
+	assert.Equal(t, "subscription", findGraphQLField(result.Fields, "Events", "orderShipped").Operation)
+	assert.Equal(t, "query", findGraphQLField(result.Fields, "Query", "notRoot").Operation,
+		"Default root names remain operations")
+}
@@ -31,6 +31,17 @@ import (
 // JAVASCRIPT PARSER
 // =============================================================================
 
+// jsParseResult holds the extraction results of a JavaScript file.
+type jsParseResult struct {
+	Functions  []FunctionEntity
+	Types      []TypeEntity
+	Variables  []VariableEntity
+	Calls      []CallsEdge
+	ErrorSites []ErrorSiteEntity
+	Tests      []TestEntity
+	Metrics    []FunctionMetricsEntity
+}
+
 // parseJavaScriptAST extracts functions, classes, and call relationships from JavaScript source using Tree-sitter.
 //
 // Extracts:
@@ -40,13 +51,17 @@ import (
 //   - Classes (class Foo {})
 //   - Methods (within classes)
 //   - Async functions
+//   - Exported const/let/var declarations (variables)
 //   - Function calls within the file
+//   - Errors thrown (throw new Error("..."))
+//   - Jest/Vitest describe blocks and it/test cases of test files
+//   - Complexity and size metrics of each function
 //
 // Handles ES6+ syntax including arrow functions and class methods.
-func (p *TreeSitterParser) parseJavaScriptAST(parser *sitter.Parser, content []byte, filePath string) ([]FunctionEntity, []TypeEntity, []CallsEdge, error) {
+func (p *TreeSitterParser) parseJavaScriptAST(parser *sitter.Parser, content []byte, filePath string) (*jsParseResult, error) {
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("tree-sitter parse: %w", err)
+		return nil, fmt.Errorf("tree-sitter parse: %w", err)
 	}
 	defer tree.Close()
 
@@ -69,6 +84,9 @@ func (p *TreeSitterParser) parseJavaScriptAST(parser *sitter.Parser, content []b
 	// Extract types (classes in JavaScript)
 	types := p.extractJSTypes(rootNode, content, filePath)
 
+	// Extract exported const/let/var declarations
+	variables := p.extractJSVariables(rootNode, content, filePath)
+
 	// Extract calls
 	var calls []CallsEdge
 	for _, fn := range functions {
@@ -76,7 +94,15 @@ func (p *TreeSitterParser) parseJavaScriptAST(parser *sitter.Parser, content []b
 		calls = append(calls, fnCalls...)
 	}
 
-	return functions, types, calls, nil
+	return &jsParseResult{
+		Functions:  functions,
+		Types:      types,
+		Variables:  variables,
+		Calls:      calls,
+		ErrorSites: extractJSErrorSites(rootNode, content, filePath, functions),
+		Tests:      extractJSTests(rootNode, content, filePath, "javascript", functions),
+		Metrics:    extractFunctionMetrics(rootNode, content, "javascript", functions),
+	}, nil
 }
 
 // walkJSFunctions recursively walks the AST to find JavaScript function declarations.
@@ -168,15 +194,16 @@ func (p *TreeSitterParser) extractJSFunction(node *sitter.Node, content []byte,
 	id := GenerateFunctionID(filePath, name, signature, startLine, endLine, startCol, endCol)
 
 	return &FunctionEntity{
-		ID:        id,
-		Name:      name,
-		Signature: signature,
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Signature:  signature,
+		FilePath:   filePath,
+		DocComment: jsDocComment(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -227,15 +254,16 @@ func (p *TreeSitterParser) extractJSArrowOrExpressionFunction(nameNode, valueNod
 	id := GenerateFunctionID(filePath, name, signature, startLine, endLine, startCol, endCol)
 
 	return &FunctionEntity{
-		ID:        id,
-		Name:      name,
-		Signature: signature,
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Signature:  signature,
+		FilePath:   filePath,
+		DocComment: jsDocComment(valueNode, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -266,15 +294,16 @@ func (p *TreeSitterParser) extractJSMethod(node *sitter.Node, content []byte, fi
 	id := GenerateFunctionID(filePath, name, signature, startLine, endLine, startCol, endCol)
 
 	return &FunctionEntity{
-		ID:        id,
-		Name:      name,
-		Signature: signature,
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Signature:  signature,
+		FilePath:   filePath,
+		DocComment: jsDocComment(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -434,15 +463,16 @@ func (p *TreeSitterParser) extractJSClass(node *sitter.Node, content []byte, fil
 	id := GenerateTypeID(filePath, name, startLine, endLine)
 
 	return &TypeEntity{
-		ID:        id,
-		Name:      name,
-		Kind:      "class",
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Kind:       "class",
+		FilePath:   filePath,
+		DocComment: jsDocComment(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
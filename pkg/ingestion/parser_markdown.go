@@ -0,0 +1,263 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// =============================================================================
+// MARKDOWN PARSER (line-based, no tree-sitter)
+// =============================================================================
+
+var (
+	mdHeadingPattern    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?[ \t]*$`)
+	mdFencePattern      = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	mdCodeSpanPattern   = regexp.MustCompile("`([^`\n]+)`")
+	mdLinkPattern       = regexp.MustCompile(`\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	mdIdentifierPattern = regexp.MustCompile(`^[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*$`)
+	mdPathPattern       = regexp.MustCompile(`^[\w.\-/]+$`)
+	mdLineSuffixPattern = regexp.MustCompile(`:\d+(?:-\d+)?$`)
+)
+
+// markdownDocsResult contains the chunks of a Markdown file and the symbol and
+// file mentions found in them.
+type markdownDocsResult struct {
+	Chunks []DocChunkEntity
+	Links  []DocLinkEdge
+}
+
+// markdownSection is a chunk under construction.
+type markdownSection struct {
+	heading   string
+	level     int
+	startLine int
+	lines     []string
+}
+
+// parseMarkdownDocs splits a Markdown file into one chunk per ATX heading
+// ("#" to "######") and collects the mentions of code in each chunk:
+//   - backticked identifiers (`Parser`, `Parser.ParseFile()`, `ingestion.NewParser`)
+//   - backticked file paths (`pkg/ingestion/parser.go`, `parser.go:42`)
+//   - relative links ([parser](../pkg/ingestion/parser.go)), resolved against the doc's directory
+//
+// Headings and mentions inside fenced code blocks and YAML front matter are
+// ignored. Mentions are left unresolved (TargetID empty); LinkDocMentions
+// matches them against the indexed functions, types and files.
+func parseMarkdownDocs(content, filePath string, truncateFunc func(string) string) markdownDocsResult {
+	var result markdownDocsResult
+	anchors := make(map[string]int)
+
+	lines := strings.Split(content, "\n")
+	start := skipFrontMatter(lines)
+
+	current := &markdownSection{startLine: start + 1}
+	flush := func() {
+		text := strings.TrimRight(strings.Join(current.lines, "\n"), " \t\r\n")
+		if current.level == 0 && strings.TrimSpace(text) == "" {
+			return
+		}
+		anchor := markdownAnchor(current.heading, anchors)
+		chunk := DocChunkEntity{
+			ID:        GenerateDocChunkID(filePath, anchor, current.startLine),
+			FilePath:  filePath,
+			Heading:   current.heading,
+			Anchor:    anchor,
+			Level:     current.level,
+			Text:      truncateFunc(text),
+			StartLine: current.startLine,
+			EndLine:   current.startLine + strings.Count(text, "\n"),
+		}
+		result.Chunks = append(result.Chunks, chunk)
+		result.Links = append(result.Links, markdownMentions(current, chunk.ID, filePath)...)
+	}
+
+	var fence string
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		if current.level == 0 && len(current.lines) == 0 && strings.TrimSpace(line) == "" {
+			current.startLine++ // Preamble starts at its first non-blank line
+			continue
+		}
+		var isFence bool
+		if fence, isFence = updateFence(line, fence); !isFence && fence == "" {
+			if m := mdHeadingPattern.FindStringSubmatch(line); m != nil {
+				flush()
+				current = &markdownSection{
+					heading:   trimClosingHashes(m[2]),
+					level:     len(m[1]),
+					startLine: i + 1,
+				}
+			}
+		}
+		current.lines = append(current.lines, line)
+	}
+	flush()
+
+	return result
+}
+
+// skipFrontMatter returns the index of the first line after YAML front matter.
+func skipFrontMatter(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if t := strings.TrimSpace(lines[i]); t == "---" || t == "..." {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// updateFence tracks fenced code blocks. It returns the fence that is open
+// after line ("" outside code blocks) and whether line is a fence delimiter.
+func updateFence(line, fence string) (string, bool) {
+	m := mdFencePattern.FindStringSubmatch(line)
+	if m == nil {
+		return fence, false
+	}
+	switch {
+	case fence == "":
+		return m[1], true
+	case m[1][0] == fence[0] && len(m[1]) >= len(fence) && strings.TrimSpace(line) == m[1]:
+		return "", true
+	}
+	return fence, true
+}
+
+// trimClosingHashes removes the optional closing sequence of an ATX heading ("## Title ##").
+func trimClosingHashes(heading string) string {
+	trimmed := strings.TrimRight(heading, "#")
+	if trimmed == "" || trimmed != heading && strings.HasSuffix(trimmed, " ") {
+		heading = trimmed
+	}
+	return strings.TrimSpace(heading)
+}
+
+// markdownAnchor returns the GitHub-style anchor of a heading: lowercased,
+// punctuation removed, spaces replaced by hyphens, and "-1", "-2" appended to
+// repeated headings. The preamble has an empty anchor.
+func markdownAnchor(heading string, seen map[string]int) string {
+	if heading == "" {
+		return ""
+	}
+	var sb strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('-')
+		}
+	}
+	anchor := sb.String()
+	n := seen[anchor]
+	seen[anchor] = n + 1
+	if n > 0 {
+		anchor = fmt.Sprintf("%s-%d", anchor, n)
+	}
+	return anchor
+}
+
+// markdownMentions extracts the code mentions of a section, once per symbol.
+func markdownMentions(section *markdownSection, chunkID, filePath string) []DocLinkEdge {
+	var links []DocLinkEdge
+	seen := make(map[string]bool)
+	add := func(symbol, kind string, line int) {
+		if symbol == "" || seen[kind+"|"+symbol] {
+			return
+		}
+		seen[kind+"|"+symbol] = true
+		links = append(links, DocLinkEdge{
+			ChunkID:    chunkID,
+			TargetKind: kind,
+			Symbol:     symbol,
+			FilePath:   filePath,
+			Line:       line,
+		})
+	}
+
+	var fence string
+	for i, line := range section.lines {
+		var isFence bool
+		if fence, isFence = updateFence(line, fence); isFence || fence != "" {
+			continue
+		}
+		lineNum := section.startLine + i
+		for _, m := range mdCodeSpanPattern.FindAllStringSubmatch(line, -1) {
+			symbol, kind := classifyMention(m[1])
+			add(symbol, kind, lineNum)
+		}
+		for _, m := range mdLinkPattern.FindAllStringSubmatch(line, -1) {
+			add(resolveDocLink(m[1], filePath), DocTargetFile, lineNum)
+		}
+	}
+	return links
+}
+
+// classifyMention normalizes the content of a code span and reports whether
+// it names a file (DocTargetFile) or a symbol (""). Anything else, such as
+// shell commands or expressions, returns an empty symbol.
+func classifyMention(span string) (symbol, kind string) {
+	s := strings.TrimSpace(span)
+	s = strings.TrimPrefix(s, "func ")
+	s = strings.TrimLeft(s, "*&")
+	if open := strings.Index(s, "("); open > 0 && strings.HasSuffix(s, ")") {
+		s = s[:open] // Call or signature: ParseFile() / ParseFile(fileInfo)
+	}
+
+	if p := strings.TrimPrefix(mdLineSuffixPattern.ReplaceAllString(s, ""), "./"); mdPathPattern.MatchString(p) {
+		if detectLanguageFromPath(p) != "" || strings.Contains(p, "/") && path.Ext(p) != "" {
+			return path.Clean(p), DocTargetFile
+		}
+	}
+	if mdIdentifierPattern.MatchString(s) && len(s) >= 3 {
+		return s, ""
+	}
+	return "", ""
+}
+
+// resolveDocLink returns the repository path of a relative Markdown link, or
+// "" for URLs, in-page anchors and links leaving the repository.
+func resolveDocLink(target, docPath string) string {
+	if strings.Contains(target, "://") || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "mailto:") {
+		return ""
+	}
+	target, _, _ = strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+	if target == "" {
+		return ""
+	}
+	var resolved string
+	if strings.HasPrefix(target, "/") {
+		resolved = path.Clean(strings.TrimPrefix(target, "/"))
+	} else {
+		resolved = path.Join(path.Dir(docPath), target)
+	}
+	if resolved == "." || strings.HasPrefix(resolved, "../") {
+		return ""
+	}
+	return resolved
+}
@@ -0,0 +1,81 @@
+package ingestion
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseMarkdownTestFile is a helper that reads the Markdown fixture and parses it
+// as docs/architecture.md.
+func parseMarkdownTestFile(t *testing.T) markdownDocsResult {
+	t.Helper()
+
+	code, err := os.ReadFile("testdata/markdown/docs/architecture.md")
+	require.NoError(t, err, "Failed to read test fixture")
+
+	return parseMarkdownDocs(string(code), "docs/architecture.md", func(s string) string { return s })
+}
+
+// TestMarkdownParser_Chunks tests heading-based chunking, anchors and line ranges.
+func TestMarkdownParser_Chunks(t *testing.T) {
+	result := parseMarkdownTestFile(t)
+	require.Len(t, result.Chunks, 5, "Preamble, 4 headings; the heading inside the code block is ignored")
+
+	type chunk struct {
+		Heading, Anchor string
+		Level           int
+		Start, End      int
+	}
+	var got []chunk
+	for _, c := range result.Chunks {
+		got = append(got, chunk{c.Heading, c.Anchor, c.Level, c.StartLine, c.EndLine})
+	}
+	assert.Equal(t, []chunk{
+		{"", "", 0, 5, 5},
+		{"Architecture", "architecture", 1, 7, 9},
+		{"Ingestion", "ingestion", 2, 11, 19},
+		{"Ingestion", "ingestion-1", 2, 21, 23},
+		{"Embeddings", "embeddings", 3, 25, 27},
+	}, got, "Front matter is skipped, closing #'s are trimmed and repeated anchors are numbered")
+
+	assert.Equal(t, "CIE indexes a repository into a local CozoDB database.", result.Chunks[0].Text)
+	assert.Contains(t, result.Chunks[2].Text, "p := NewParser(logger)", "Code blocks stay in the chunk text")
+	assert.Equal(t, GenerateDocChunkID("docs/architecture.md", "ingestion", 11), result.Chunks[2].ID)
+}
+
+// TestMarkdownParser_Mentions tests backticked identifiers, file paths and relative links.
+func TestMarkdownParser_Mentions(t *testing.T) {
+	result := parseMarkdownTestFile(t)
+
+	chunkHeading := make(map[string]string)
+	for _, c := range result.Chunks {
+		chunkHeading[c.ID] = c.Anchor
+	}
+	var got []string
+	for _, l := range result.Links {
+		assert.Empty(t, l.TargetID, "Mentions are resolved by LinkDocMentions")
+		got = append(got, chunkHeading[l.ChunkID]+" "+l.TargetKind+":"+l.Symbol)
+	}
+
+	assert.Equal(t, []string{
+		"architecture file:cmd/cie/main.go",
+		"architecture file:README.md",
+		"ingestion :Parser.ParseFile",
+		"ingestion :ingestion.NewDatalogBuilder",
+		"ingestion :DatalogBuilder",
+		"ingestion file:datalog.go",
+		"ingestion-1 :ParseFile",
+		"embeddings file:pkg/ingestion/embedding.go",
+	}, got, "Commands, code blocks and URLs are not mentions; calls and line suffixes are normalized")
+}
+
+// TestMarkdownAnchor tests GitHub-style anchor generation.
+func TestMarkdownAnchor(t *testing.T) {
+	seen := make(map[string]int)
+	assert.Equal(t, "getting-started-v2", markdownAnchor("Getting Started (v2)", seen))
+	assert.Equal(t, "cie_semantic_search", markdownAnchor("`cie_semantic_search`", seen))
+	assert.Equal(t, "getting-started-v2-1", markdownAnchor("Getting started: v2", seen))
+}
@@ -0,0 +1,685 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// PHP PARSER
+// =============================================================================
+
+// phpParseResult contains all extracted data from PHP parsing.
+type phpParseResult struct {
+	Functions       []FunctionEntity
+	Types           []TypeEntity
+	Fields          []FieldEntity
+	Calls           []CallsEdge
+	Imports         []ImportEntity
+	Implements      []ImplementsEdge
+	UnresolvedCalls []UnresolvedCall
+	Metrics         []FunctionMetricsEntity
+	Namespace       string
+}
+
+// phpFunctionWithNode pairs a function entity with the AST nodes whose calls belong to it.
+type phpFunctionWithNode struct {
+	entity    FunctionEntity
+	className string
+	nodes     []*sitter.Node
+}
+
+// phpParseContext holds state while walking a PHP file.
+type phpParseContext struct {
+	content      []byte
+	filePath     string
+	namespace    string
+	result       *phpParseResult
+	functions    []phpFunctionWithNode
+	funcNameToID map[string]string
+	// parents maps a class name to its parent class as written, namespace
+	// included (used to resolve parent::).
+	parents map[string]string
+	// topLevel collects executable statements outside any declaration.
+	topLevel []*sitter.Node
+}
+
+// parsePHPAST extracts namespaces, types, functions, imports and calls from PHP source using Tree-sitter.
+//
+// Extracts:
+//   - Namespace (stored as the package name for cross-file resolution)
+//   - Classes, interfaces, traits and enums (as TypeEntity)
+//   - Functions and methods (methods prefixed with class name, e.g., "UserService.create")
+//   - Top-level statements as a synthetic "$main" function (route files, scripts)
+//   - `use` statements (as ImportEntity, keyed by alias)
+//   - `implements`, `extends` and trait `use` clauses (as ImplementsEdge)
+//   - Typed properties and promoted constructor parameters (as FieldEntity)
+//   - Function, method, static and constructor calls
+//   - Complexity and size metrics of each function
+func (p *TreeSitterParser) parsePHPAST(parser *sitter.Parser, content []byte, filePath string) (*phpParseResult, error) {
+	tree, err := parser.ParseCtx(context.Background(), nil, content)
+	if err != nil {
+		return nil, fmt.Errorf("tree-sitter parse: %w", err)
+	}
+	defer tree.Close()
+
+	rootNode := tree.RootNode()
+	if rootNode.HasError() {
+		if errorCount := countErrors(rootNode); errorCount > 0 {
+			p.logger.Warn("parser.treesitter.php.syntax_errors",
+				"path", filePath,
+				"error_count", errorCount,
+			)
+		}
+		// Continue parsing - Tree-sitter is error-tolerant
+	}
+
+	ctx := &phpParseContext{
+		content:      content,
+		filePath:     filePath,
+		result:       &phpParseResult{},
+		funcNameToID: make(map[string]string),
+		parents:      make(map[string]string),
+	}
+
+	// First pass: declarations, imports and top-level statements
+	p.walkPHPStatements(rootNode, ctx)
+	if fn := p.buildPHPMainFunction(ctx); fn != nil {
+		ctx.functions = append(ctx.functions, *fn)
+		ctx.funcNameToID[fn.entity.Name] = fn.entity.ID
+	}
+
+	// Second pass: calls within each function
+	for _, fn := range ctx.functions {
+		local, unresolved := p.extractPHPCalls(fn, ctx)
+		ctx.result.Calls = append(ctx.result.Calls, local...)
+		ctx.result.UnresolvedCalls = append(ctx.result.UnresolvedCalls, unresolved...)
+	}
+
+	for _, fn := range ctx.functions {
+		ctx.result.Functions = append(ctx.result.Functions, fn.entity)
+	}
+	ctx.result.Metrics = extractFunctionMetrics(rootNode, content, "php", ctx.result.Functions)
+	ctx.result.Namespace = ctx.namespace
+
+	return ctx.result, nil
+}
+
+// walkPHPStatements walks statement-level nodes of a program or namespace block.
+func (p *TreeSitterParser) walkPHPStatements(node *sitter.Node, ctx *phpParseContext) {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "php_tag", "text", "text_interpolation", "comment", "empty_statement":
+			// Not code
+		case "namespace_definition":
+			if nameNode := child.ChildByFieldName("name"); nameNode != nil {
+				ctx.namespace = nodeText(nameNode, ctx.content)
+			}
+			// Braced form: namespace Foo { ... }
+			if body := child.ChildByFieldName("body"); body != nil {
+				p.walkPHPStatements(body, ctx)
+			}
+		case "namespace_use_declaration":
+			ctx.result.Imports = append(ctx.result.Imports, p.extractPHPUseDeclaration(child, ctx)...)
+		case "class_declaration", "interface_declaration", "trait_declaration", "enum_declaration":
+			p.extractPHPTypeDeclaration(child, ctx)
+		case "function_definition":
+			if fn := p.extractPHPFunction(child, ctx, ""); fn != nil {
+				ctx.functions = append(ctx.functions, phpFunctionWithNode{entity: *fn, nodes: []*sitter.Node{child.ChildByFieldName("body")}})
+				ctx.funcNameToID[fn.Name] = fn.ID
+			}
+		case "const_declaration", "declare_statement":
+			// Declarations without executable calls
+		default:
+			ctx.topLevel = append(ctx.topLevel, child)
+		}
+	}
+}
+
+// extractPHPUseDeclaration converts a `use` statement into import entities.
+// Handles aliases (use Foo\Bar as Baz), function/const imports and group
+// imports (use App\Models\{User, Post}). The alias is always populated so
+// the resolver can map short names back to fully qualified names.
+func (p *TreeSitterParser) extractPHPUseDeclaration(node *sitter.Node, ctx *phpParseContext) []ImportEntity {
+	var imports []ImportEntity
+	line := int(node.StartPoint().Row) + 1
+
+	groupPrefix := ""
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "namespace_name":
+			groupPrefix = nodeText(child, ctx.content) + `\`
+		case "namespace_use_clause":
+			if imp := p.extractPHPUseClause(child, ctx, "", line); imp != nil {
+				imports = append(imports, *imp)
+			}
+		case "namespace_use_group":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				clause := child.NamedChild(j)
+				if clause.Type() != "namespace_use_group_clause" && clause.Type() != "namespace_use_clause" {
+					continue
+				}
+				if imp := p.extractPHPUseClause(clause, ctx, groupPrefix, line); imp != nil {
+					imports = append(imports, *imp)
+				}
+			}
+		}
+	}
+
+	return imports
+}
+
+// extractPHPUseClause extracts a single imported name and its alias.
+func (p *TreeSitterParser) extractPHPUseClause(clause *sitter.Node, ctx *phpParseContext, prefix string, line int) *ImportEntity {
+	var importPath, alias string
+	for i := 0; i < int(clause.NamedChildCount()); i++ {
+		child := clause.NamedChild(i)
+		switch child.Type() {
+		case "qualified_name", "name", "namespace_name":
+			if importPath == "" {
+				importPath = strings.TrimPrefix(nodeText(child, ctx.content), `\`)
+			}
+		case "namespace_aliasing_clause":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				if child.NamedChild(j).Type() == "name" {
+					alias = nodeText(child.NamedChild(j), ctx.content)
+				}
+			}
+		}
+	}
+	if importPath == "" {
+		return nil
+	}
+	importPath = prefix + importPath
+	if alias == "" {
+		alias = phpShortName(importPath)
+	}
+
+	return &ImportEntity{
+		ID:         GenerateImportID(ctx.filePath, importPath),
+		FilePath:   ctx.filePath,
+		ImportPath: importPath,
+		Alias:      alias,
+		StartLine:  line,
+	}
+}
+
+// extractPHPTypeDeclaration extracts a class, interface, trait or enum along with
+// its methods, typed properties and inheritance edges.
+func (p *TreeSitterParser) extractPHPTypeDeclaration(node *sitter.Node, ctx *phpParseContext) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+	name := nodeText(nameNode, ctx.content)
+
+	kind := strings.TrimSuffix(node.Type(), "_declaration")
+
+	startLine := int(node.StartPoint().Row) + 1
+	endLine := int(node.EndPoint().Row) + 1
+	codeText := p.truncateCodeText(nodeText(node, ctx.content))
+
+	ctx.result.Types = append(ctx.result.Types, TypeEntity{
+		ID:         GenerateTypeID(ctx.filePath, name, startLine, endLine),
+		Name:       name,
+		Kind:       kind,
+		FilePath:   ctx.filePath,
+		DocComment: phpDocComment(node, ctx.content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   int(node.StartPoint().Column) + 1,
+		EndCol:     int(node.EndPoint().Column) + 1,
+	})
+
+	// Inheritance clauses: extends (base_clause) and implements (class_interface_clause)
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != "base_clause" && child.Type() != "class_interface_clause" {
+			continue
+		}
+		for j := 0; j < int(child.NamedChildCount()); j++ {
+			ref := child.NamedChild(j)
+			if ref.Type() != "name" && ref.Type() != "qualified_name" {
+				continue
+			}
+			written := strings.TrimPrefix(nodeText(ref, ctx.content), `\`)
+			if child.Type() == "base_clause" && kind == "class" {
+				ctx.parents[name] = written
+			}
+			p.addPHPImplements(ctx, name, phpShortName(written))
+		}
+	}
+
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return
+	}
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		member := body.NamedChild(i)
+		switch member.Type() {
+		case "use_declaration":
+			// Trait usage is recorded as a mixin edge: the class gains the trait's methods
+			for j := 0; j < int(member.NamedChildCount()); j++ {
+				ref := member.NamedChild(j)
+				if ref.Type() == "name" || ref.Type() == "qualified_name" {
+					p.addPHPImplements(ctx, name, phpShortName(nodeText(ref, ctx.content)))
+				}
+			}
+		case "property_declaration":
+			ctx.result.Fields = append(ctx.result.Fields, p.extractPHPProperties(member, ctx, name)...)
+		case "method_declaration":
+			fn := p.extractPHPFunction(member, ctx, name)
+			if fn == nil {
+				continue
+			}
+			var nodes []*sitter.Node
+			if methodBody := member.ChildByFieldName("body"); methodBody != nil {
+				nodes = append(nodes, methodBody)
+			}
+			ctx.functions = append(ctx.functions, phpFunctionWithNode{entity: *fn, className: name, nodes: nodes})
+			ctx.funcNameToID[fn.Name] = fn.ID
+			if params := member.ChildByFieldName("parameters"); params != nil {
+				ctx.result.Fields = append(ctx.result.Fields, p.extractPHPPromotedFields(params, ctx, name)...)
+			}
+		}
+	}
+}
+
+// addPHPImplements records a declared inheritance edge from typeName to parent.
+func (p *TreeSitterParser) addPHPImplements(ctx *phpParseContext, typeName, parent string) {
+	if parent == "" {
+		return
+	}
+	ctx.result.Implements = append(ctx.result.Implements, ImplementsEdge{
+		TypeName:      typeName,
+		InterfaceName: parent,
+		FilePath:      ctx.filePath,
+	})
+}
+
+// extractPHPFunction extracts a function definition or method declaration.
+func (p *TreeSitterParser) extractPHPFunction(node *sitter.Node, ctx *phpParseContext, className string) *FunctionEntity {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nodeText(nameNode, ctx.content)
+
+	fullName := name
+	if className != "" {
+		fullName = className + "." + name
+	}
+
+	var params string
+	if paramsNode := node.ChildByFieldName("parameters"); paramsNode != nil {
+		params = nodeText(paramsNode, ctx.content)
+	}
+	signature := "function " + name + params
+	if returnNode := node.ChildByFieldName("return_type"); returnNode != nil {
+		signature += ": " + nodeText(returnNode, ctx.content)
+	}
+
+	startLine := int(node.StartPoint().Row) + 1
+	endLine := int(node.EndPoint().Row) + 1
+	startCol := int(node.StartPoint().Column) + 1
+	endCol := int(node.EndPoint().Column) + 1
+
+	codeText := p.truncateCodeText(nodeText(node, ctx.content))
+
+	return &FunctionEntity{
+		ID:         GenerateFunctionID(ctx.filePath, fullName, signature, startLine, endLine, startCol, endCol),
+		Name:       fullName,
+		Signature:  signature,
+		FilePath:   ctx.filePath,
+		DocComment: phpDocComment(node, ctx.content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
+	}
+}
+
+// buildPHPMainFunction wraps the file's top-level statements into a synthetic
+// "$main" function so that calls made at file scope (route registrations,
+// bootstrap code) are part of the call graph and visible to endpoint discovery.
+func (p *TreeSitterParser) buildPHPMainFunction(ctx *phpParseContext) *phpFunctionWithNode {
+	if len(ctx.topLevel) == 0 {
+		return nil
+	}
+	first := ctx.topLevel[0]
+	last := ctx.topLevel[len(ctx.topLevel)-1]
+
+	startLine := int(first.StartPoint().Row) + 1
+	endLine := int(last.EndPoint().Row) + 1
+	startCol := int(first.StartPoint().Column) + 1
+	endCol := int(last.EndPoint().Column) + 1
+
+	codeText := p.truncateCodeText(string(ctx.content[first.StartByte():last.EndByte()]))
+	signature := scriptMainFunctionName + "()"
+
+	return &phpFunctionWithNode{
+		entity: FunctionEntity{
+			ID:        GenerateFunctionID(ctx.filePath, scriptMainFunctionName, signature, startLine, endLine, startCol, endCol),
+			Name:      scriptMainFunctionName,
+			Signature: signature,
+			FilePath:  ctx.filePath,
+			CodeText:  codeText,
+			StartLine: startLine,
+			EndLine:   endLine,
+			StartCol:  startCol,
+			EndCol:    endCol,
+		},
+		nodes: ctx.topLevel,
+	}
+}
+
+// extractPHPProperties extracts typed class properties (private Repo $repo;).
+// Untyped properties and builtin types are skipped, mirroring Go struct fields.
+func (p *TreeSitterParser) extractPHPProperties(node *sitter.Node, ctx *phpParseContext, className string) []FieldEntity {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return nil
+	}
+	fieldType := phpBaseTypeName(typeNode, ctx.content)
+	if fieldType == "" {
+		return nil
+	}
+
+	var fields []FieldEntity
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		elem := node.NamedChild(i)
+		if elem.Type() != "property_element" {
+			continue
+		}
+		for j := 0; j < int(elem.NamedChildCount()); j++ {
+			if v := elem.NamedChild(j); v.Type() == "variable_name" {
+				fields = append(fields, FieldEntity{
+					StructName: className,
+					FieldName:  strings.TrimPrefix(nodeText(v, ctx.content), "$"),
+					FieldType:  fieldType,
+					FilePath:   ctx.filePath,
+					Line:       int(elem.StartPoint().Row) + 1,
+				})
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// extractPHPPromotedFields extracts constructor-promoted properties
+// (public function __construct(private Repo $repo)).
+func (p *TreeSitterParser) extractPHPPromotedFields(params *sitter.Node, ctx *phpParseContext, className string) []FieldEntity {
+	var fields []FieldEntity
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		if param.Type() != "property_promotion_parameter" {
+			continue
+		}
+		typeNode := param.ChildByFieldName("type")
+		nameNode := param.ChildByFieldName("name")
+		if typeNode == nil || nameNode == nil {
+			continue
+		}
+		fieldType := phpBaseTypeName(typeNode, ctx.content)
+		if fieldType == "" {
+			continue
+		}
+		fields = append(fields, FieldEntity{
+			StructName: className,
+			FieldName:  strings.TrimPrefix(nodeText(nameNode, ctx.content), "$"),
+			FieldType:  fieldType,
+			FilePath:   ctx.filePath,
+			Line:       int(param.StartPoint().Row) + 1,
+		})
+	}
+	return fields
+}
+
+// phpBaseTypeName returns the class name of a type hint (?Foo → Foo, \App\Foo → Foo).
+// Returns "" for builtin, union and intersection types.
+func phpBaseTypeName(typeNode *sitter.Node, content []byte) string {
+	switch typeNode.Type() {
+	case "optional_type":
+		for i := 0; i < int(typeNode.NamedChildCount()); i++ {
+			if name := phpBaseTypeName(typeNode.NamedChild(i), content); name != "" {
+				return name
+			}
+		}
+		return ""
+	case "named_type":
+		name := phpShortName(nodeText(typeNode, content))
+		switch strings.ToLower(name) {
+		case "self", "static", "parent", "mixed", "iterable", "callable", "object":
+			return ""
+		}
+		return name
+	default:
+		// primitive_type, union_type, intersection_type
+		return ""
+	}
+}
+
+// =============================================================================
+// PHP CALL EXTRACTION
+// =============================================================================
+
+// extractPHPCalls extracts calls from a PHP function's body nodes.
+// Same-file targets become local edges; everything else is recorded as an
+// unresolved call using dotted callee names understood by the resolver:
+//
+//	helper()               → "helper"
+//	$this->save()          → "this.save"
+//	$this->repo->save()    → "this.repo.save"
+//	UserRepo::find()       → "UserRepo::find"
+//	parent::boot()         → "parent::boot"
+//	new User()             → "User::__construct"
+//
+// Static calls use "::" so the resolver can tell class names from variables.
+func (p *TreeSitterParser) extractPHPCalls(fn phpFunctionWithNode, ctx *phpParseContext) ([]CallsEdge, []UnresolvedCall) {
+	var localCalls []CallsEdge
+	var unresolvedCalls []UnresolvedCall
+	seenLocal := make(map[string]bool)
+	seenUnresolved := make(map[string]bool)
+
+	for _, node := range fn.nodes {
+		p.walkPHPCallExpressions(node, fn, ctx, &localCalls, &unresolvedCalls, seenLocal, seenUnresolved)
+	}
+	return localCalls, unresolvedCalls
+}
+
+// walkPHPCallExpressions finds call expressions and categorizes them as local or unresolved.
+func (p *TreeSitterParser) walkPHPCallExpressions(
+	node *sitter.Node, fn phpFunctionWithNode, ctx *phpParseContext,
+	localCalls *[]CallsEdge, unresolvedCalls *[]UnresolvedCall,
+	seenLocal, seenUnresolved map[string]bool,
+) {
+	if node == nil {
+		return
+	}
+
+	switch node.Type() {
+	case "function_call_expression", "member_call_expression", "nullsafe_member_call_expression",
+		"scoped_call_expression", "object_creation_expression":
+		calleeName := p.extractPHPCalleeName(node, fn.className, ctx)
+		if calleeName != "" {
+			p.addPHPCall(node, fn, ctx, calleeName, localCalls, unresolvedCalls, seenLocal, seenUnresolved)
+		}
+	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		p.walkPHPCallExpressions(node.Child(i), fn, ctx, localCalls, unresolvedCalls, seenLocal, seenUnresolved)
+	}
+}
+
+// addPHPCall records a call as a local edge when the target is defined in the
+// same file, otherwise as an unresolved call.
+func (p *TreeSitterParser) addPHPCall(
+	node *sitter.Node, fn phpFunctionWithNode, ctx *phpParseContext, calleeName string,
+	localCalls *[]CallsEdge, unresolvedCalls *[]UnresolvedCall,
+	seenLocal, seenUnresolved map[string]bool,
+) {
+	callerID := fn.entity.ID
+	callLine := int(node.StartPoint().Row) + 1
+
+	localName := strings.Replace(calleeName, "::", ".", 1)
+	if rest, ok := strings.CutPrefix(calleeName, "this."); ok && !strings.Contains(rest, ".") && fn.className != "" {
+		localName = fn.className + "." + rest
+	}
+	if calleeID, exists := ctx.funcNameToID[localName]; exists {
+		if calleeID == callerID {
+			return
+		}
+		edgeKey := callerID + "->" + calleeID
+		if !seenLocal[edgeKey] {
+			seenLocal[edgeKey] = true
+			*localCalls = append(*localCalls, CallsEdge{CallerID: callerID, CalleeID: calleeID, CallLine: callLine})
+		}
+		return
+	}
+
+	key := callerID + "->" + calleeName
+	if !seenUnresolved[key] {
+		seenUnresolved[key] = true
+		*unresolvedCalls = append(*unresolvedCalls, UnresolvedCall{
+			CallerID:   callerID,
+			CalleeName: calleeName,
+			FilePath:   ctx.filePath,
+			Line:       callLine,
+		})
+	}
+}
+
+// extractPHPCalleeName builds the dotted callee name for a PHP call node.
+// self:: and static:: are resolved to the enclosing class.
+func (p *TreeSitterParser) extractPHPCalleeName(node *sitter.Node, className string, ctx *phpParseContext) string {
+	switch node.Type() {
+	case "function_call_expression":
+		funcNode := node.ChildByFieldName("function")
+		if funcNode == nil || (funcNode.Type() != "name" && funcNode.Type() != "qualified_name") {
+			return "" // Dynamic call: $fn(), $obj()
+		}
+		return strings.TrimPrefix(nodeText(funcNode, ctx.content), `\`)
+
+	case "member_call_expression", "nullsafe_member_call_expression":
+		nameNode := node.ChildByFieldName("name")
+		if nameNode == nil || nameNode.Type() != "name" {
+			return ""
+		}
+		receiver := phpReceiverChain(node.ChildByFieldName("object"), ctx.content)
+		if receiver == "" {
+			return ""
+		}
+		return receiver + "." + nodeText(nameNode, ctx.content)
+
+	case "scoped_call_expression":
+		nameNode := node.ChildByFieldName("name")
+		scopeNode := node.ChildByFieldName("scope")
+		if nameNode == nil || scopeNode == nil || nameNode.Type() != "name" {
+			return ""
+		}
+		scope := p.phpClassReference(scopeNode, className, ctx)
+		if scope == "" {
+			return ""
+		}
+		return scope + "::" + nodeText(nameNode, ctx.content)
+
+	case "object_creation_expression":
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			child := node.NamedChild(i)
+			if child.Type() == "name" || child.Type() == "qualified_name" {
+				scope := p.phpClassReference(child, className, ctx)
+				if scope == "" || scope == "parent" {
+					return ""
+				}
+				return scope + "::__construct"
+			}
+		}
+	}
+	return ""
+}
+
+// phpClassReference normalizes a class reference used in a static call or `new`.
+// self/static map to the enclosing class; parent is kept as "parent" for the resolver.
+func (p *TreeSitterParser) phpClassReference(node *sitter.Node, className string, ctx *phpParseContext) string {
+	if node.Type() != "name" && node.Type() != "qualified_name" && node.Type() != "relative_scope" {
+		return "" // Dynamic: $class::method(), new $class()
+	}
+	ref := strings.TrimPrefix(nodeText(node, ctx.content), `\`)
+	switch strings.ToLower(ref) {
+	case "self", "static":
+		return className
+	case "parent":
+		if className == "" {
+			return ""
+		}
+		if parent, ok := ctx.parents[className]; ok {
+			return parent
+		}
+		return "parent"
+	}
+	return ref
+}
+
+// phpReceiverChain converts a call receiver into a dotted chain:
+// $this → "this", $this->repo → "this.repo", $user → "user".
+// Returns "" for receivers that are not plain variables or property accesses.
+func phpReceiverChain(node *sitter.Node, content []byte) string {
+	if node == nil {
+		return ""
+	}
+	switch node.Type() {
+	case "variable_name":
+		return strings.TrimPrefix(nodeText(node, content), "$")
+	case "member_access_expression", "nullsafe_member_access_expression":
+		nameNode := node.ChildByFieldName("name")
+		if nameNode == nil || nameNode.Type() != "name" {
+			return ""
+		}
+		object := phpReceiverChain(node.ChildByFieldName("object"), content)
+		if object == "" {
+			return ""
+		}
+		return object + "." + nodeText(nameNode, content)
+	}
+	return ""
+}
+
+// phpShortName strips the namespace from a PHP name: "App\Models\User" → "User".
+func phpShortName(name string) string {
+	if idx := strings.LastIndex(name, `\`); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+// nodeText returns the source text covered by a node.
+func nodeText(node *sitter.Node, content []byte) string {
+	return string(content[node.StartByte():node.EndByte()])
+}
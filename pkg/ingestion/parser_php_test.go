@@ -0,0 +1,184 @@
+package ingestion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parsePHPTestFile is a helper that reads a PHP test fixture and parses it.
+func parsePHPTestFile(t *testing.T, fixturePath string) *ParseResult {
+	t.Helper()
+
+	code, err := os.ReadFile(fixturePath)
+	require.NoError(t, err, "Failed to read test fixture: %s", fixturePath)
+
+	tmpFile := filepath.Join(t.TempDir(), filepath.Base(fixturePath))
+	err = os.WriteFile(tmpFile, code, 0644)
+	require.NoError(t, err, "Failed to write temp file")
+
+	parser := NewTreeSitterParser(nil)
+	result, err := parser.ParseFile(FileInfo{
+		Path:     filepath.Base(fixturePath),
+		FullPath: tmpFile,
+		Size:     int64(len(code)),
+		Language: "php",
+	})
+	require.NoError(t, err, "Parser should not error on valid PHP code")
+
+	return result
+}
+
+// findParsedFunction returns the parsed function with the given name or nil.
+func findParsedFunction(result *ParseResult, name string) *FunctionEntity {
+	for i := range result.Functions {
+		if result.Functions[i].Name == name {
+			return &result.Functions[i]
+		}
+	}
+	return nil
+}
+
+// TestPHPParser_NamespaceAndTypes tests namespace, class, interface and trait extraction.
+func TestPHPParser_NamespaceAndTypes(t *testing.T) {
+	result := parsePHPTestFile(t, "testdata/php/user_service.php")
+
+	assert.Equal(t, `App\Services`, result.PackageName, "Namespace should be stored as package name")
+
+	kinds := make(map[string]string)
+	for _, typ := range result.Types {
+		kinds[typ.Name] = typ.Kind
+	}
+	assert.Equal(t, "interface", kinds["ServiceInterface"])
+	assert.Equal(t, "trait", kinds["Loggable"])
+	assert.Equal(t, "class", kinds["UserService"])
+}
+
+// TestPHPParser_FunctionsAndMethods tests function and method extraction with class prefixes.
+func TestPHPParser_FunctionsAndMethods(t *testing.T) {
+	result := parsePHPTestFile(t, "testdata/php/user_service.php")
+
+	for _, name := range []string{
+		"normalize",
+		"Loggable.log",
+		"UserService.__construct",
+		"UserService.create",
+		"UserService.validate",
+		"UserService.make",
+		"ServiceInterface.create",
+	} {
+		assert.NotNil(t, findParsedFunction(result, name), "Should find %s", name)
+	}
+
+	create := findParsedFunction(result, "UserService.create")
+	require.NotNil(t, create)
+	assert.Equal(t, "function create(string $name): User", create.Signature)
+	assert.Contains(t, create.CodeText, "$this->repo->save($user)")
+
+	assert.Nil(t, findParsedFunction(result, scriptMainFunctionName), "Declaration-only file should have no $main")
+}
+
+// TestPHPParser_Imports tests `use` statements including aliases and function imports.
+func TestPHPParser_Imports(t *testing.T) {
+	result := parsePHPTestFile(t, "testdata/php/user_service.php")
+
+	aliases := make(map[string]string)
+	for _, imp := range result.Imports {
+		aliases[imp.Alias] = imp.ImportPath
+	}
+	assert.Equal(t, `App\Repositories\UserRepository`, aliases["UserRepository"])
+	assert.Equal(t, `App\Models\User`, aliases["User"])
+	assert.Equal(t, `App\Support\Str`, aliases["StrHelper"], "Aliased import should use the alias")
+	assert.Equal(t, `App\Support\format_name`, aliases["format_name"], "Function import should be recorded")
+}
+
+// TestPHPParser_ImplementsAndTraits tests implements clauses and trait mixins.
+func TestPHPParser_ImplementsAndTraits(t *testing.T) {
+	result := parsePHPTestFile(t, "testdata/php/user_service.php")
+
+	edges := make(map[string]bool)
+	for _, e := range result.Implements {
+		edges[e.TypeName+"->"+e.InterfaceName] = true
+	}
+	assert.True(t, edges["UserService->ServiceInterface"], "Should record implements edge")
+	assert.True(t, edges["UserService->Loggable"], "Should record trait mixin edge")
+}
+
+// TestPHPParser_Fields tests typed property and promoted constructor parameter extraction.
+func TestPHPParser_Fields(t *testing.T) {
+	result := parsePHPTestFile(t, "testdata/php/user_service.php")
+
+	fieldTypes := make(map[string]string)
+	for _, f := range result.Fields {
+		assert.Equal(t, "UserService", f.StructName)
+		fieldTypes[f.FieldName] = f.FieldType
+	}
+	assert.Equal(t, "UserRepository", fieldTypes["repo"])
+	assert.Equal(t, "Mailer", fieldTypes["mailer"], "Nullable promoted parameter should unwrap to class name")
+}
+
+// TestPHPParser_Calls tests local call edges and unresolved call naming.
+func TestPHPParser_Calls(t *testing.T) {
+	result := parsePHPTestFile(t, "testdata/php/user_service.php")
+
+	create := findParsedFunction(result, "UserService.create")
+	validate := findParsedFunction(result, "UserService.validate")
+	normalize := findParsedFunction(result, "normalize")
+	require.NotNil(t, create)
+	require.NotNil(t, validate)
+	require.NotNil(t, normalize)
+
+	local := make(map[string]bool)
+	for _, c := range result.Calls {
+		local[c.CallerID+"->"+c.CalleeID] = true
+	}
+	assert.True(t, local[create.ID+"->"+validate.ID], "$this->validate() should resolve locally")
+	assert.True(t, local[validate.ID+"->"+normalize.ID], "normalize() should resolve locally")
+
+	unresolved := make(map[string]bool)
+	for _, c := range result.UnresolvedCalls {
+		if c.CallerID == create.ID {
+			unresolved[c.CalleeName] = true
+		}
+	}
+	assert.True(t, unresolved["User::__construct"], "new User() should be a constructor call")
+	assert.True(t, unresolved["format_name"])
+	assert.True(t, unresolved["this.repo.save"], "Property call should keep the receiver chain")
+	assert.True(t, unresolved["StrHelper::slug"], "Static call should use :: separator")
+	assert.True(t, unresolved["this.log"], "Trait method is resolved cross-file")
+}
+
+// TestPHPParser_TopLevelRoutes tests that file-scope statements become a $main function.
+func TestPHPParser_TopLevelRoutes(t *testing.T) {
+	result := parsePHPTestFile(t, "testdata/php/routes.php")
+
+	main := findParsedFunction(result, scriptMainFunctionName)
+	require.NotNil(t, main, "Route file should produce a $main function")
+	assert.Contains(t, main.CodeText, "Route::get('/users'")
+	assert.Contains(t, main.CodeText, "Route::post('users'")
+
+	unresolved := make(map[string]bool)
+	for _, c := range result.UnresolvedCalls {
+		unresolved[c.CalleeName] = true
+	}
+	assert.True(t, unresolved["Route::get"])
+	assert.True(t, unresolved["response"], "Calls inside closures belong to $main")
+}
+
+// TestPHPParser_Attributes tests that method attributes are part of the method code.
+func TestPHPParser_Attributes(t *testing.T) {
+	result := parsePHPTestFile(t, "testdata/php/product_controller.php")
+
+	show := findParsedFunction(result, "ProductController.show")
+	require.NotNil(t, show)
+	assert.Contains(t, show.CodeText, "#[Route('/products/{id}'")
+
+	edges := make(map[string]bool)
+	for _, e := range result.Implements {
+		edges[e.TypeName+"->"+e.InterfaceName] = true
+	}
+	assert.True(t, edges["ProductController->AbstractController"], "extends should be recorded")
+}
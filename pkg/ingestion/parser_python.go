@@ -31,20 +31,38 @@ import (
 // PYTHON PARSER
 // =============================================================================
 
+// pythonParseResult contains all extracted data from Python parsing.
+type pythonParseResult struct {
+	Functions       []FunctionEntity
+	Types           []TypeEntity
+	Variables       []VariableEntity
+	Calls           []CallsEdge
+	Implements      []ImplementsEdge
+	UnresolvedCalls []UnresolvedCall
+	ErrorSites      []ErrorSiteEntity
+	Metrics         []FunctionMetricsEntity
+}
+
 // parsePythonAST extracts functions, classes, methods, and call relationships from Python source using Tree-sitter.
 //
 // Extracts:
 //   - Functions (def statements)
 //   - Classes (class definitions)
+//   - Base classes of each class (as ImplementsEdge, in declaration order)
 //   - Methods (functions within classes, with class prefix)
 //   - Lambda functions (anonymous functions)
+//   - Module-level assignments (variables)
 //   - Function calls within the file
+//   - Exceptions raised (raise ValueError("..."))
+//   - Complexity and size metrics of each function
+//   - Unresolved self/super() calls and calls on annotated parameters
+//     (resolved later through the class hierarchy)
 //
 // Method names are prefixed with class name (e.g., "ClassName.method_name").
-func (p *TreeSitterParser) parsePythonAST(parser *sitter.Parser, content []byte, filePath string) ([]FunctionEntity, []TypeEntity, []CallsEdge, error) {
+func (p *TreeSitterParser) parsePythonAST(parser *sitter.Parser, content []byte, filePath string) (*pythonParseResult, error) {
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("tree-sitter parse: %w", err)
+		return nil, fmt.Errorf("tree-sitter parse: %w", err)
 	}
 	defer tree.Close()
 
@@ -64,17 +82,32 @@ func (p *TreeSitterParser) parsePythonAST(parser *sitter.Parser, content []byte,
 
 	p.walkPythonFunctions(rootNode, content, filePath, &functions, funcNameToID, "", &anonCounter)
 
-	// Extract types (classes in Python)
+	// Extract types (classes in Python) and their base classes
 	types := p.extractPythonTypes(rootNode, content, filePath)
+	implements := extractPythonBaseClasses(rootNode, content, filePath)
+
+	// Extract module-level assignments
+	variables := p.extractPythonVariables(rootNode, content, filePath)
 
 	// Extract calls using stored functions
 	var calls []CallsEdge
+	var unresolvedCalls []UnresolvedCall
 	for _, fn := range functions {
-		fnCalls := p.extractPythonCalls(rootNode, content, fn, funcNameToID)
+		fnCalls, fnUnresolved := p.extractPythonCalls(rootNode, content, fn, funcNameToID)
 		calls = append(calls, fnCalls...)
-	}
-
-	return functions, types, calls, nil
+		unresolvedCalls = append(unresolvedCalls, fnUnresolved...)
+	}
+
+	return &pythonParseResult{
+		Functions:       functions,
+		Types:           types,
+		Variables:       variables,
+		Calls:           calls,
+		Implements:      implements,
+		UnresolvedCalls: unresolvedCalls,
+		ErrorSites:      extractPythonErrorSites(rootNode, content, filePath, functions),
+		Metrics:         extractFunctionMetrics(rootNode, content, "python", functions),
+	}, nil
 }
 
 // walkPythonFunctions recursively walks the AST to find function definitions.
@@ -174,15 +207,16 @@ func (p *TreeSitterParser) extractPythonFunction(node *sitter.Node, content []by
 	id := GenerateFunctionID(filePath, fullName, signature, startLine, endLine, startCol, endCol)
 
 	return &FunctionEntity{
-		ID:        id,
-		Name:      fullName,
-		Signature: signature,
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       fullName,
+		Signature:  signature,
+		FilePath:   filePath,
+		DocComment: pythonDocstring(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -218,45 +252,176 @@ func (p *TreeSitterParser) extractPythonLambda(node *sitter.Node, content []byte
 	}
 }
 
-// extractPythonCalls extracts function calls within a Python function.
-func (p *TreeSitterParser) extractPythonCalls(root *sitter.Node, content []byte, caller FunctionEntity, funcNameToID map[string]string) []CallsEdge {
-	var calls []CallsEdge
+// pythonCallContext holds the state of call extraction for one Python function.
+type pythonCallContext struct {
+	content      []byte
+	filePath     string
+	callerID     string
+	className    string            // Class of the calling method, "" for functions
+	typedParams  map[string]bool   // Annotated parameters of the caller
+	funcNameToID map[string]string // Functions and methods of the file
+	calls        []CallsEdge
+	unresolved   []UnresolvedCall
+	seen         map[string]bool
+}
 
+// extractPythonCalls extracts function calls within a Python function.
+//
+// Calls to functions of the file are resolved directly, as are self.method()
+// calls to a method of the caller's own class. Calls that depend on the class
+// hierarchy are returned as unresolved calls for the resolver:
+//   - "self.method" (or cls.method): method inherited from a base class
+//   - "super().method": method of a base class
+//   - "param.method": method called on an annotated parameter
+func (p *TreeSitterParser) extractPythonCalls(root *sitter.Node, content []byte, caller FunctionEntity, funcNameToID map[string]string) ([]CallsEdge, []UnresolvedCall) {
 	fnNode := findNodeAtPosition(root, uint32(caller.StartLine-1), uint32(caller.StartCol-1)) //nolint:gosec // G115: line/col from parsed source are bounded
+	// findNodeAtPosition returns the deepest node (the def keyword): climb to the definition
+	for fnNode != nil && fnNode.Type() != "function_definition" && fnNode.Type() != "lambda" {
+		fnNode = fnNode.Parent()
+	}
 	if fnNode == nil {
-		return calls
+		return nil, nil
 	}
 
-	p.walkPythonCallExpressions(fnNode, content, caller.ID, funcNameToID, &calls)
-	return calls
+	ctx := &pythonCallContext{
+		content:      content,
+		filePath:     caller.FilePath,
+		callerID:     caller.ID,
+		typedParams:  pythonTypedParams(fnNode, content),
+		funcNameToID: funcNameToID,
+		seen:         make(map[string]bool),
+	}
+	if className, _, ok := strings.Cut(caller.Name, "."); ok {
+		ctx.className = className
+	}
+
+	p.walkPythonCallExpressions(fnNode, ctx)
+	return ctx.calls, ctx.unresolved
 }
 
 // walkPythonCallExpressions finds call expressions in Python.
-func (p *TreeSitterParser) walkPythonCallExpressions(node *sitter.Node, content []byte, callerID string, funcNameToID map[string]string, calls *[]CallsEdge) {
+func (p *TreeSitterParser) walkPythonCallExpressions(node *sitter.Node, ctx *pythonCallContext) {
 	if node == nil {
 		return
 	}
 
 	if node.Type() == "call" {
-		funcNode := node.ChildByFieldName("function")
-		if funcNode != nil {
-			calleeName := p.extractPythonCalleeName(funcNode, content)
-			if calleeName != "" {
-				if calleeID, exists := funcNameToID[calleeName]; exists && calleeID != callerID {
-					*calls = append(*calls, CallsEdge{
-						CallerID: callerID,
-						CalleeID: calleeID,
-						CallLine: int(node.StartPoint().Row) + 1,
-					})
-				}
-			}
+		if funcNode := node.ChildByFieldName("function"); funcNode != nil {
+			p.processPythonCall(node, funcNode, ctx)
 		}
 	}
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
-		p.walkPythonCallExpressions(child, content, callerID, funcNameToID, calls)
+		p.walkPythonCallExpressions(child, ctx)
+	}
+}
+
+// processPythonCall records one call: a local edge when the callee is known in
+// the file, an unresolved call when it depends on the class hierarchy.
+func (p *TreeSitterParser) processPythonCall(node, funcNode *sitter.Node, ctx *pythonCallContext) {
+	if funcNode.Type() == "attribute" {
+		object := funcNode.ChildByFieldName("object")
+		attr := funcNode.ChildByFieldName("attribute")
+		if object != nil && attr != nil {
+			method := nodeText(attr, ctx.content)
+			switch receiver := pythonCallReceiver(object, ctx.content); {
+			case receiver == "self" && ctx.className != "":
+				if calleeID, ok := ctx.funcNameToID[ctx.className+"."+method]; ok {
+					ctx.addCall(calleeID, node)
+				} else {
+					ctx.addUnresolved("self."+method, node)
+				}
+				return
+			case receiver == "super()" && ctx.className != "":
+				ctx.addUnresolved("super()."+method, node)
+				return
+			case ctx.typedParams[receiver]:
+				ctx.addUnresolved(receiver+"."+method, node)
+				return
+			}
+		}
+	}
+
+	calleeName := p.extractPythonCalleeName(funcNode, ctx.content)
+	if calleeName == "" {
+		return
+	}
+	if calleeID, exists := ctx.funcNameToID[calleeName]; exists {
+		ctx.addCall(calleeID, node)
+	}
+}
+
+// addCall records a call edge to a function of the file.
+func (ctx *pythonCallContext) addCall(calleeID string, node *sitter.Node) {
+	if calleeID == ctx.callerID {
+		return
+	}
+	ctx.calls = append(ctx.calls, CallsEdge{
+		CallerID: ctx.callerID,
+		CalleeID: calleeID,
+		CallLine: int(node.StartPoint().Row) + 1,
+	})
+}
+
+// addUnresolved records a call resolved later through the class hierarchy.
+func (ctx *pythonCallContext) addUnresolved(calleeName string, node *sitter.Node) {
+	key := ctx.callerID + "->" + calleeName
+	if ctx.seen[key] {
+		return
 	}
+	ctx.seen[key] = true
+	ctx.unresolved = append(ctx.unresolved, UnresolvedCall{
+		CallerID:   ctx.callerID,
+		CalleeName: calleeName,
+		FilePath:   ctx.filePath,
+		Line:       int(node.StartPoint().Row) + 1,
+	})
+}
+
+// pythonCallReceiver names the object a method is called on: "self" for self
+// and cls, "super()" for super(), the identifier for other names, else "".
+func pythonCallReceiver(object *sitter.Node, content []byte) string {
+	switch object.Type() {
+	case "identifier":
+		name := nodeText(object, content)
+		if name == "cls" {
+			return "self"
+		}
+		return name
+	case "call":
+		if fn := object.ChildByFieldName("function"); fn != nil && fn.Type() == "identifier" && nodeText(fn, content) == "super" {
+			return "super()"
+		}
+	}
+	return ""
+}
+
+// pythonTypedParams returns the names of the annotated parameters of a
+// function_definition (repo in `def save(self, repo: Repo)`).
+func pythonTypedParams(fnNode *sitter.Node, content []byte) map[string]bool {
+	params := make(map[string]bool)
+	if fnNode.Type() != "function_definition" {
+		return params
+	}
+	list := fnNode.ChildByFieldName("parameters")
+	if list == nil {
+		return params
+	}
+	for i := 0; i < int(list.NamedChildCount()); i++ {
+		param := list.NamedChild(i)
+		switch param.Type() {
+		case "typed_parameter":
+			if param.NamedChildCount() > 0 && param.NamedChild(0).Type() == "identifier" {
+				params[nodeText(param.NamedChild(0), content)] = true
+			}
+		case "typed_default_parameter":
+			if name := param.ChildByFieldName("name"); name != nil {
+				params[nodeText(name, content)] = true
+			}
+		}
+	}
+	return params
 }
 
 // extractPythonCalleeName extracts the function name from a Python call.
@@ -336,16 +501,69 @@ func (p *TreeSitterParser) extractPythonClass(node *sitter.Node, content []byte,
 	id := GenerateTypeID(filePath, name, startLine, endLine)
 
 	return &TypeEntity{
-		ID:        id,
-		Name:      name,
-		Kind:      "class",
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Kind:       "class",
+		FilePath:   filePath,
+		DocComment: pythonDocstring(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
+	}
+}
+
+// extractPythonBaseClasses records the base classes of every class as
+// ImplementsEdge, in declaration order: `class A(B, mixins.Mixin, Generic[T])`
+// yields A → B, A → Mixin and A → Generic. Keyword arguments such as
+// metaclass=ABCMeta and the implicit object base are skipped.
+func extractPythonBaseClasses(node *sitter.Node, content []byte, filePath string) []ImplementsEdge {
+	var edges []ImplementsEdge
+	if node == nil {
+		return edges
 	}
+
+	if node.Type() == "class_definition" {
+		nameNode := node.ChildByFieldName("name")
+		bases := node.ChildByFieldName("superclasses")
+		if nameNode != nil && bases != nil {
+			for i := 0; i < int(bases.NamedChildCount()); i++ {
+				base := pythonBaseClassName(bases.NamedChild(i), content)
+				if base == "" || base == "object" {
+					continue
+				}
+				edges = append(edges, ImplementsEdge{
+					TypeName:      nodeText(nameNode, content),
+					InterfaceName: base,
+					FilePath:      filePath,
+				})
+			}
+		}
+	}
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		edges = append(edges, extractPythonBaseClasses(node.NamedChild(i), content, filePath)...)
+	}
+	return edges
+}
+
+// pythonBaseClassName returns the class named by a base class expression:
+// "Base", "Mixin" for mixins.Mixin, "Generic" for Generic[T].
+func pythonBaseClassName(node *sitter.Node, content []byte) string {
+	switch node.Type() {
+	case "identifier":
+		return nodeText(node, content)
+	case "attribute":
+		if attr := node.ChildByFieldName("attribute"); attr != nil {
+			return nodeText(attr, content)
+		}
+	case "subscript":
+		if value := node.ChildByFieldName("value"); value != nil {
+			return pythonBaseClassName(value, content)
+		}
+	}
+	return ""
 }
 
 // parsePythonFile extracts functions from Python source code.
@@ -182,6 +182,58 @@ func TestPythonParser_Inheritance(t *testing.T) {
 	assert.GreaterOrEqual(t, len(result.Functions), 5, "Should extract at least 5 methods")
 }
 
+// TestPythonParser_BaseClasses tests that base classes are recorded as
+// implements edges in declaration order.
+func TestPythonParser_BaseClasses(t *testing.T) {
+	result := parsePythonTestFile(t, "testdata/python/dispatch.py")
+
+	var bases []string
+	for _, e := range result.Implements {
+		assert.Equal(t, "dispatch.py", e.FilePath)
+		bases = append(bases, e.TypeName+" -> "+e.InterfaceName)
+	}
+	assert.Equal(t, []string{
+		"Repository -> ABC",
+		"SqlRepository -> Repository",
+		"SqlRepository -> Mixin",
+		"MemoryRepository -> Repository",
+		"Service -> Base",
+		"Service -> Mixin",
+	}, bases)
+}
+
+// TestPythonParser_HierarchyCalls tests that self calls to the caller's own
+// class resolve in the file, and that inherited, super() and parameter calls
+// are left to the resolver.
+func TestPythonParser_HierarchyCalls(t *testing.T) {
+	result := parsePythonTestFile(t, "testdata/python/dispatch.py")
+
+	names := make(map[string]string)
+	for _, fn := range result.Functions {
+		names[fn.ID] = fn.Name
+	}
+
+	var calls []string
+	for _, c := range result.Calls {
+		calls = append(calls, names[c.CallerID]+" -> "+names[c.CalleeID])
+	}
+	assert.Equal(t, []string{"MemoryRepository.save -> MemoryRepository.save_all"}, calls)
+
+	var unresolved []string
+	for _, c := range result.UnresolvedCalls {
+		unresolved = append(unresolved, names[c.CallerID]+" -> "+c.CalleeName)
+	}
+	assert.Equal(t, []string{
+		"SqlRepository.save -> self.log",
+		"SqlRepository.save -> super().greet",
+		"Service.run -> self.setup",
+		"Service.run -> self.greet",
+		"Service.run -> super().greet",
+		"Service.run -> repo.save",
+		"Service.run -> name.upper",
+	}, unresolved)
+}
+
 // TestPythonParser_Lambda tests lambda expression extraction.
 func TestPythonParser_Lambda(t *testing.T) {
 	result := parsePythonTestFile(t, "testdata/python/lambda_expr.py")
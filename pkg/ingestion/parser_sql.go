@@ -0,0 +1,473 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"regexp"
+	"strings"
+)
+
+// =============================================================================
+// SQL SCHEMA PARSER (simplified, no tree-sitter)
+// =============================================================================
+
+// sqlIdentPattern matches a possibly schema-qualified SQL identifier in any
+// quoting style: orders, public.orders, "Orders", `orders`, [dbo].[orders].
+const sqlIdentPattern = `(?:[A-Za-z_][\w$]*|"[^"]+"|` + "`[^`]+`" + `|\[[^\]]+\])(?:\s*\.\s*(?:[A-Za-z_][\w$]*|"[^"]+"|` + "`[^`]+`" + `|\[[^\]]+\]))*`
+
+var (
+	sqlCreateTablePattern = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(` + sqlIdentPattern + `)\s*\(`)
+	sqlAlterTablePattern  = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(` + sqlIdentPattern + `)\s+`)
+	sqlCreateIndexPattern = regexp.MustCompile(`(?is)^CREATE\s+(UNIQUE\s+)?(?:CLUSTERED\s+|NONCLUSTERED\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(` + sqlIdentPattern + `\s+)?ON\s+(?:ONLY\s+)?(` + sqlIdentPattern + `)\s*(?:USING\s+\w+\s*)?\(`)
+
+	sqlAddPattern        = regexp.MustCompile(`(?is)^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?`)
+	sqlConstraintPattern = regexp.MustCompile(`(?is)^CONSTRAINT\s+(` + sqlIdentPattern + `)\s+`)
+	sqlPrimaryKeyPattern = regexp.MustCompile(`(?is)^PRIMARY\s+KEY\s*(?:\w+\s*)?\(`)
+	sqlForeignKeyPattern = regexp.MustCompile(`(?is)^FOREIGN\s+KEY\s*(?:` + sqlIdentPattern + `\s+)?\(`)
+	sqlUniqueKeyPattern  = regexp.MustCompile(`(?is)^(UNIQUE|KEY|INDEX|UNIQUE\s+KEY|UNIQUE\s+INDEX)\s*(?:(` + sqlIdentPattern + `)\s+)?\(`)
+	sqlReferencesPattern = regexp.MustCompile(`(?is)\bREFERENCES\s+(` + sqlIdentPattern + `)\s*(\([^)]*\))?`)
+	sqlNotNullPattern    = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	sqlColumnPKPattern   = regexp.MustCompile(`(?i)\bPRIMARY\s+KEY\b`)
+	sqlColumnUniqPattern = regexp.MustCompile(`(?i)\bUNIQUE\b`)
+	sqlColumnNamePattern = regexp.MustCompile(`(?s)^(` + sqlIdentPattern + `)\s*`)
+)
+
+// sqlSkippedTableItems are CREATE TABLE body items that are neither columns nor
+// constraints we track.
+var sqlSkippedTableItems = map[string]bool{
+	"check": true, "exclude": true, "like": true, "period": true, "fulltext": true, "spatial": true,
+}
+
+// sqlColumnConstraintKeywords end the data type of a column definition.
+var sqlColumnConstraintKeywords = map[string]bool{
+	"not": true, "null": true, "default": true, "primary": true, "references": true,
+	"unique": true, "check": true, "constraint": true, "collate": true, "generated": true,
+	"auto_increment": true, "autoincrement": true, "comment": true, "identity": true,
+	"on": true, "as": true,
+}
+
+// sqlStatement is a single statement with comments blanked out. Blanking keeps
+// byte offsets so lines can be computed from positions within Text.
+type sqlStatement struct {
+	Text      string
+	StartLine int
+}
+
+// parseSQLSchema extracts tables, columns, indexes and foreign keys from a .sql file.
+//
+// Understands the DDL found in migrations and schema dumps of PostgreSQL,
+// MySQL and SQLite:
+//   - CREATE TABLE with column definitions, inline PRIMARY KEY/UNIQUE/REFERENCES
+//     and table constraints (PRIMARY KEY, FOREIGN KEY, UNIQUE, KEY/INDEX)
+//   - ALTER TABLE ... ADD COLUMN / ADD CONSTRAINT
+//   - CREATE [UNIQUE] INDEX ... ON table (columns)
+//
+// Other statements (DML, functions, grants) are ignored. Identifiers are
+// normalized to lowercase without schema prefix or quotes so that they match
+// table names extracted from SQL literals in code.
+func parseSQLSchema(content, filePath string, truncateFunc func(string) string) SQLSchema {
+	var schema SQLSchema
+
+	for _, stmt := range splitSQLStatements(content) {
+		text := stmt.Text
+		switch {
+		case sqlCreateTablePattern.MatchString(text):
+			parseSQLCreateTable(text, stmt.StartLine, filePath, truncateFunc, &schema)
+		case sqlAlterTablePattern.MatchString(text):
+			parseSQLAlterTable(text, stmt.StartLine, filePath, &schema)
+		case sqlCreateIndexPattern.MatchString(text):
+			m := sqlCreateIndexPattern.FindStringSubmatchIndex(text)
+			body, _ := sqlParenBody(text, m[1]-1)
+			schema.Indexes = append(schema.Indexes, SQLIndexEntity{
+				Name:      normalizeSQLIdent(strings.TrimSpace(sqlSubmatch(text, m, 2))),
+				TableName: normalizeSQLIdent(sqlSubmatch(text, m, 3)),
+				Columns:   sqlColumnList(body),
+				Unique:    m[2] >= 0,
+				FilePath:  filePath,
+				Line:      stmt.StartLine,
+			})
+		}
+	}
+
+	return schema
+}
+
+// parseSQLCreateTable extracts a table and its columns and constraints.
+func parseSQLCreateTable(text string, startLine int, filePath string, truncateFunc func(string) string, schema *SQLSchema) {
+	m := sqlCreateTablePattern.FindStringSubmatchIndex(text)
+	tableName := normalizeSQLIdent(sqlSubmatch(text, m, 1))
+	body, bodyStart := sqlParenBody(text, m[1]-1)
+
+	schema.Tables = append(schema.Tables, SQLTableEntity{
+		ID:        GenerateSQLTableID(filePath, tableName),
+		Name:      tableName,
+		FilePath:  filePath,
+		CodeText:  truncateFunc(text),
+		StartLine: startLine,
+		EndLine:   startLine + strings.Count(text, "\n"),
+	})
+
+	for _, item := range splitSQLTopLevel(body, ',') {
+		line := startLine + strings.Count(text[:bodyStart+item.offset], "\n")
+		parseSQLTableItem(tableName, item.text, line, filePath, schema)
+	}
+}
+
+// parseSQLAlterTable extracts columns and constraints added by ALTER TABLE.
+func parseSQLAlterTable(text string, startLine int, filePath string, schema *SQLSchema) {
+	m := sqlAlterTablePattern.FindStringSubmatchIndex(text)
+	tableName := normalizeSQLIdent(sqlSubmatch(text, m, 1))
+	actionsStart := m[1]
+
+	for _, action := range splitSQLTopLevel(text[actionsStart:], ',') {
+		add := sqlAddPattern.FindStringIndex(action.text)
+		if add == nil {
+			continue
+		}
+		line := startLine + strings.Count(text[:actionsStart+action.offset], "\n")
+		parseSQLTableItem(tableName, action.text[add[1]:], line, filePath, schema)
+	}
+}
+
+// parseSQLTableItem handles one column definition or table constraint.
+func parseSQLTableItem(tableName, item string, line int, filePath string, schema *SQLSchema) {
+	item = strings.TrimSpace(item)
+	if item == "" {
+		return
+	}
+
+	// Named constraints: the name is only kept for indexes
+	constraintName := ""
+	if m := sqlConstraintPattern.FindStringSubmatch(item); m != nil {
+		constraintName = normalizeSQLIdent(m[1])
+		item = strings.TrimSpace(item[len(m[0]):])
+	}
+
+	if m := sqlPrimaryKeyPattern.FindStringIndex(item); m != nil {
+		body, _ := sqlParenBody(item, m[1]-1)
+		pk := make(map[string]bool)
+		for _, col := range sqlColumnList(body) {
+			pk[col] = true
+		}
+		for i := range schema.Columns {
+			c := &schema.Columns[i]
+			if c.TableName == tableName && c.FilePath == filePath && pk[c.Name] {
+				c.PrimaryKey = true
+				c.Nullable = false
+			}
+		}
+		return
+	}
+
+	if m := sqlForeignKeyPattern.FindStringIndex(item); m != nil {
+		body, _ := sqlParenBody(item, m[1]-1)
+		if ref := sqlReferencesPattern.FindStringSubmatch(item); ref != nil {
+			schema.ForeignKeys = append(schema.ForeignKeys, SQLForeignKeyEntity{
+				TableName:  tableName,
+				Columns:    sqlColumnList(body),
+				RefTable:   normalizeSQLIdent(ref[1]),
+				RefColumns: sqlColumnList(strings.Trim(ref[2], "()")),
+				FilePath:   filePath,
+				Line:       line,
+			})
+		}
+		return
+	}
+
+	if m := sqlUniqueKeyPattern.FindStringSubmatchIndex(item); m != nil {
+		body, _ := sqlParenBody(item, m[1]-1)
+		name := constraintName
+		if name == "" {
+			name = normalizeSQLIdent(strings.TrimSpace(sqlSubmatch(item, m, 2)))
+		}
+		schema.Indexes = append(schema.Indexes, SQLIndexEntity{
+			Name:      name,
+			TableName: tableName,
+			Columns:   sqlColumnList(body),
+			Unique:    strings.HasPrefix(strings.ToLower(item), "unique"),
+			FilePath:  filePath,
+			Line:      line,
+		})
+		return
+	}
+
+	firstWord := strings.ToLower(strings.Fields(item)[0])
+	if constraintName != "" || sqlSkippedTableItems[firstWord] {
+		return
+	}
+
+	parseSQLColumn(tableName, item, line, filePath, schema)
+}
+
+// parseSQLColumn handles a column definition: name, data type and inline constraints.
+func parseSQLColumn(tableName, item string, line int, filePath string, schema *SQLSchema) {
+	m := sqlColumnNamePattern.FindStringSubmatch(item)
+	if m == nil {
+		return
+	}
+	columnName := normalizeSQLIdent(m[1])
+	rest := item[len(m[0]):]
+
+	// The data type runs until the first constraint keyword outside parentheses
+	var typeParts []string
+	for _, word := range splitSQLTopLevel(rest, ' ') {
+		w := strings.TrimSpace(word.text)
+		if w == "" {
+			continue
+		}
+		if sqlColumnConstraintKeywords[strings.ToLower(w)] {
+			break
+		}
+		typeParts = append(typeParts, w)
+	}
+
+	primaryKey := sqlColumnPKPattern.MatchString(rest)
+	schema.Columns = append(schema.Columns, SQLColumnEntity{
+		TableName:  tableName,
+		Name:       columnName,
+		DataType:   strings.Join(typeParts, " "),
+		Nullable:   !primaryKey && !sqlNotNullPattern.MatchString(rest),
+		PrimaryKey: primaryKey,
+		FilePath:   filePath,
+		Line:       line,
+	})
+
+	if sqlColumnUniqPattern.MatchString(rest) && !primaryKey {
+		schema.Indexes = append(schema.Indexes, SQLIndexEntity{
+			TableName: tableName,
+			Columns:   []string{columnName},
+			Unique:    true,
+			FilePath:  filePath,
+			Line:      line,
+		})
+	}
+
+	if ref := sqlReferencesPattern.FindStringSubmatch(rest); ref != nil {
+		schema.ForeignKeys = append(schema.ForeignKeys, SQLForeignKeyEntity{
+			TableName:  tableName,
+			Columns:    []string{columnName},
+			RefTable:   normalizeSQLIdent(ref[1]),
+			RefColumns: sqlColumnList(strings.Trim(ref[2], "()")),
+			FilePath:   filePath,
+			Line:       line,
+		})
+	}
+}
+
+// splitSQLStatements splits SQL on semicolons, ignoring semicolons inside
+// strings, quoted identifiers, comments and dollar-quoted bodies ($$ ... $$).
+// Comments are replaced with spaces (newlines are kept).
+func splitSQLStatements(content string) []sqlStatement {
+	var statements []sqlStatement
+	var buf strings.Builder
+	line := 1
+	stmtLine := 0
+
+	flush := func() {
+		text := buf.String()
+		trimmed := strings.TrimLeft(text, " \t\r\n")
+		if strings.TrimSpace(trimmed) != "" {
+			lead := text[:len(text)-len(trimmed)]
+			statements = append(statements, sqlStatement{
+				Text:      strings.TrimRight(trimmed, " \t\r\n"),
+				StartLine: stmtLine + strings.Count(lead, "\n"),
+			})
+		}
+		buf.Reset()
+		stmtLine = line
+	}
+	stmtLine = line
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '-' && i+1 < len(content) && content[i+1] == '-':
+			for i < len(content) && content[i] != '\n' {
+				buf.WriteByte(' ')
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			commentEnd := len(content)
+			if end := strings.Index(content[i+2:], "*/"); end >= 0 {
+				commentEnd = i + 2 + end + 2
+			}
+			comment := content[i:commentEnd]
+			for _, r := range comment {
+				if r == '\n' {
+					buf.WriteByte('\n')
+					line++
+				} else {
+					buf.WriteByte(' ')
+				}
+			}
+			i += len(comment) - 1
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(content) && content[end] != c {
+				if content[end] == '\\' && c == '\'' {
+					end++
+				}
+				end++
+			}
+			quoted := content[i:min(end+1, len(content))]
+			buf.WriteString(quoted)
+			line += strings.Count(quoted, "\n")
+			i += len(quoted) - 1
+		case c == '$':
+			tag := sqlDollarTag(content[i:])
+			if tag == "" {
+				buf.WriteByte(c)
+				continue
+			}
+			end := strings.Index(content[i+len(tag):], tag)
+			if end < 0 {
+				end = len(content) - i - len(tag)
+			} else {
+				end += len(tag)
+			}
+			body := content[i : i+len(tag)+end]
+			buf.WriteString(body)
+			line += strings.Count(body, "\n")
+			i += len(body) - 1
+		case c == ';':
+			flush()
+		default:
+			if c == '\n' {
+				line++
+			}
+			buf.WriteByte(c)
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// sqlDollarTag returns the dollar-quote tag at the start of s ("$$", "$body$") or "".
+func sqlDollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+// sqlItem is a piece of text produced by splitSQLTopLevel with its offset in the input.
+type sqlItem struct {
+	text   string
+	offset int
+}
+
+// splitSQLTopLevel splits s on sep, ignoring separators inside parentheses and quotes.
+// Leading whitespace is trimmed from each item and excluded from its offset.
+func splitSQLTopLevel(s string, sep byte) []sqlItem {
+	var items []sqlItem
+	depth := 0
+	var quote byte
+	start := 0
+
+	add := func(end int) {
+		text := strings.TrimLeft(s[start:end], " \t\r\n")
+		items = append(items, sqlItem{text: text, offset: end - len(text)})
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && (c == sep || sep == ' ' && (c == '\t' || c == '\n' || c == '\r')):
+			add(i)
+			start = i + 1
+		}
+	}
+	add(len(s))
+	return items
+}
+
+// sqlParenBody returns the text between the parenthesis at openIdx and its
+// matching closing parenthesis, and the offset where the body starts.
+func sqlParenBody(s string, openIdx int) (string, int) {
+	depth := 0
+	for i := openIdx; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[openIdx+1 : i], openIdx + 1
+			}
+		}
+	}
+	return s[openIdx+1:], openIdx + 1
+}
+
+// sqlColumnList parses "a, b DESC, lower(c)" into normalized column names.
+// Expressions are kept as written.
+func sqlColumnList(s string) []string {
+	var columns []string
+	for _, item := range splitSQLTopLevel(s, ',') {
+		col := strings.TrimSpace(item.text)
+		if col == "" {
+			continue
+		}
+		if m := sqlColumnNamePattern.FindStringSubmatch(col); m != nil && !strings.HasPrefix(col[len(m[0]):], "(") {
+			col = normalizeSQLIdent(m[1])
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// normalizeSQLIdent lowercases an identifier and strips quotes and schema
+// qualifiers: `public."Orders"` → "orders".
+func normalizeSQLIdent(ident string) string {
+	ident = strings.TrimSpace(ident)
+	if ident == "" {
+		return ""
+	}
+	parts := splitSQLTopLevel(ident, '.')
+	last := strings.TrimSpace(parts[len(parts)-1].text)
+	last = strings.Trim(last, "\"`[]")
+	return strings.ToLower(last)
+}
+
+// sqlSubmatch returns the text of submatch n from FindStringSubmatchIndex output.
+func sqlSubmatch(s string, m []int, n int) string {
+	if m[2*n] < 0 {
+		return ""
+	}
+	return s[m[2*n]:m[2*n+1]]
+}
@@ -0,0 +1,132 @@
+package ingestion
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseSQLTestFile is a helper that reads a SQL test fixture and parses its schema.
+func parseSQLTestFile(t *testing.T, fixturePath string) SQLSchema {
+	t.Helper()
+
+	code, err := os.ReadFile(fixturePath)
+	require.NoError(t, err, "Failed to read test fixture: %s", fixturePath)
+
+	return parseSQLSchema(string(code), fixturePath, func(s string) string { return s })
+}
+
+// findSQLColumn returns the column with the given table and name or nil.
+func findSQLColumn(schema SQLSchema, table, name string) *SQLColumnEntity {
+	for i := range schema.Columns {
+		if schema.Columns[i].TableName == table && schema.Columns[i].Name == name {
+			return &schema.Columns[i]
+		}
+	}
+	return nil
+}
+
+// TestSQLParser_Tables tests CREATE TABLE extraction and identifier normalization.
+func TestSQLParser_Tables(t *testing.T) {
+	schema := parseSQLTestFile(t, "testdata/sql/001_create_orders.sql")
+
+	var names []string
+	for _, tbl := range schema.Tables {
+		names = append(names, tbl.Name)
+	}
+	assert.Equal(t, []string{"users", "orders"}, names, "Commented-out DDL should be ignored; quoted, schema-qualified names normalized")
+
+	orders := schema.Tables[1]
+	assert.Equal(t, 11, orders.StartLine, "Start line should skip the preceding block comment")
+	assert.Equal(t, 19, orders.EndLine)
+	assert.Contains(t, orders.CodeText, "CONSTRAINT orders_pkey PRIMARY KEY (id)")
+}
+
+// TestSQLParser_Columns tests column types, nullability and primary keys.
+func TestSQLParser_Columns(t *testing.T) {
+	schema := parseSQLTestFile(t, "testdata/sql/001_create_orders.sql")
+
+	id := findSQLColumn(schema, "users", "id")
+	require.NotNil(t, id)
+	assert.Equal(t, "BIGSERIAL", id.DataType)
+	assert.True(t, id.PrimaryKey)
+	assert.False(t, id.Nullable)
+	assert.Equal(t, 3, id.Line)
+
+	createdAt := findSQLColumn(schema, "users", "created_at")
+	require.NotNil(t, createdAt)
+	assert.Equal(t, "TIMESTAMP WITH TIME ZONE", createdAt.DataType)
+
+	displayName := findSQLColumn(schema, "users", "display_name")
+	require.NotNil(t, displayName)
+	assert.True(t, displayName.Nullable)
+
+	total := findSQLColumn(schema, "orders", "total")
+	require.NotNil(t, total)
+	assert.Equal(t, "NUMERIC(10, 2)", total.DataType)
+	assert.False(t, total.Nullable)
+
+	orderID := findSQLColumn(schema, "orders", "id")
+	require.NotNil(t, orderID)
+	assert.True(t, orderID.PrimaryKey, "Table-level PRIMARY KEY constraint should mark the column")
+
+	coupon := findSQLColumn(schema, "orders", "coupon_id")
+	require.NotNil(t, coupon, "ALTER TABLE ADD COLUMN should add a column")
+	assert.Equal(t, 32, coupon.Line)
+
+	assert.Nil(t, findSQLColumn(schema, "orders", "orders_status_check"), "CHECK constraints are not columns")
+	assert.Len(t, schema.Tables, 2, "Dollar-quoted function bodies and DML are ignored")
+}
+
+// TestSQLParser_IndexesAndForeignKeys tests index and foreign key extraction.
+func TestSQLParser_IndexesAndForeignKeys(t *testing.T) {
+	schema := parseSQLTestFile(t, "testdata/sql/001_create_orders.sql")
+
+	indexes := make(map[string]SQLIndexEntity)
+	for _, idx := range schema.Indexes {
+		indexes[idx.TableName+":"+idx.Name+":"+strings.Join(idx.Columns, ",")] = idx
+	}
+	assert.True(t, indexes["users::email"].Unique, "Inline UNIQUE should create a unique index")
+	assert.True(t, indexes["orders:idx_orders_user_status:user_id,status"].Unique)
+	_, ok := indexes["orders::lower(status)"]
+	assert.True(t, ok, "Unnamed expression index should be recorded")
+
+	fks := make(map[string]SQLForeignKeyEntity)
+	for _, fk := range schema.ForeignKeys {
+		fks[fk.TableName+"."+strings.Join(fk.Columns, ",")] = fk
+	}
+	require.Contains(t, fks, "orders.user_id")
+	assert.Equal(t, "users", fks["orders.user_id"].RefTable)
+	assert.Equal(t, []string{"id"}, fks["orders.user_id"].RefColumns)
+	require.Contains(t, fks, "orders.coupon_id", "ALTER TABLE ADD CONSTRAINT FOREIGN KEY should be recorded")
+	assert.Equal(t, "coupons", fks["orders.coupon_id"].RefTable)
+}
+
+// TestSQLParser_MySQLDump tests backtick identifiers and MySQL KEY syntax.
+func TestSQLParser_MySQLDump(t *testing.T) {
+	schema := parseSQLTestFile(t, "testdata/sql/mysql_dump.sql")
+
+	require.Len(t, schema.Tables, 1)
+	assert.Equal(t, "order_items", schema.Tables[0].Name)
+
+	key := findSQLColumn(schema, "order_items", "key")
+	require.NotNil(t, key, "A column named `key` is not an index")
+	assert.Equal(t, "varchar(64)", key.DataType)
+
+	id := findSQLColumn(schema, "order_items", "id")
+	require.NotNil(t, id)
+	assert.Equal(t, "int unsigned", id.DataType)
+	assert.True(t, id.PrimaryKey)
+
+	names := make(map[string]bool)
+	for _, idx := range schema.Indexes {
+		names[idx.Name] = idx.Unique
+	}
+	assert.Equal(t, map[string]bool{"uniq_order_key": true, "idx_order": false}, names)
+
+	require.Len(t, schema.ForeignKeys, 1)
+	assert.Equal(t, "orders", schema.ForeignKeys[0].RefTable)
+}
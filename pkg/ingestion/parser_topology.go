@@ -0,0 +1,901 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// INFRASTRUCTURE TOPOLOGY PARSER (Dockerfile, compose, Kubernetes, Terraform)
+// =============================================================================
+
+// parseTopology extracts deployable units from an infrastructure-as-code file.
+// YAML files that are neither compose files nor Kubernetes manifests (CI
+// configs, Helm templates, ...) yield an empty topology.
+func parseTopology(content, filePath, language string) Topology {
+	switch language {
+	case "dockerfile":
+		return parseDockerfile(content, filePath)
+	case "yaml":
+		return parseTopologyYAML(content, filePath)
+	case "terraform":
+		return parseTerraform(content, filePath)
+	}
+	return Topology{}
+}
+
+// isDockerfileName reports whether a file name is a Dockerfile:
+// Dockerfile, Dockerfile.worker, worker.Dockerfile or Containerfile.
+func isDockerfileName(name string) bool {
+	lower := strings.ToLower(path.Base(name))
+	return lower == "dockerfile" || lower == "containerfile" ||
+		strings.HasPrefix(lower, "dockerfile.") || strings.HasSuffix(lower, ".dockerfile")
+}
+
+// =============================================================================
+// COMMAND LINES
+// =============================================================================
+
+// topologyInitWrappers start the real entrypoint given as their arguments.
+var topologyInitWrappers = map[string]bool{
+	"tini": true, "dumb-init": true, "exec": true, "env": true, "--": true,
+}
+
+// commandBinary returns the program started by a command line: a Go package
+// path for "go run ./cmd/worker", otherwise the program's base name
+// ("/usr/local/bin/worker" → "worker"). Shells ("sh -c '...'"), init wrappers
+// and leading VAR=value assignments are unwrapped.
+func commandBinary(args []string) string {
+	for len(args) > 0 {
+		prog := args[0]
+		base := path.Base(prog)
+		switch {
+		case topologyInitWrappers[base]:
+			args = args[1:]
+		case strings.Contains(prog, "=") && !strings.Contains(prog, "/"):
+			args = args[1:]
+		case (base == "sh" || base == "bash" || base == "ash") && len(args) >= 3 && args[1] == "-c":
+			args = shellFields(args[2])
+		case base == "go" && len(args) >= 3 && args[1] == "run":
+			for _, arg := range args[2:] {
+				if !strings.HasPrefix(arg, "-") {
+					return normalizeGoPackagePath(arg)
+				}
+			}
+			return ""
+		case strings.HasPrefix(prog, "$"):
+			return ""
+		default:
+			return base
+		}
+	}
+	return ""
+}
+
+// shellFields splits the first command of a shell command line into words,
+// honoring single and double quotes. Stops at &&, ||, ; and |.
+func shellFields(s string) []string {
+	var fields []string
+	var cur strings.Builder
+	inWord := false
+	var quote byte
+
+	flush := func() {
+		if inWord {
+			fields = append(fields, cur.String())
+			cur.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == '\\' && i+1 < len(s):
+			i++
+			cur.WriteByte(s[i])
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+		case c == ';' || c == '|' || c == '&':
+			flush()
+			return fields
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	flush()
+	return fields
+}
+
+// normalizeGoPackagePath turns a go build/run/install package argument into a
+// repository-relative package path: "./cmd/worker/" → "cmd/worker",
+// "github.com/acme/app/cmd/worker" → "cmd/worker".
+func normalizeGoPackagePath(pkg string) string {
+	pkg = strings.TrimSuffix(strings.TrimSuffix(pkg, "/..."), "/")
+	if strings.HasSuffix(pkg, ".go") {
+		pkg = path.Dir(pkg)
+	}
+	if first, _, _ := strings.Cut(pkg, "/"); strings.Contains(first, ".") && first != "." && first != ".." {
+		// Module import path: keep the cmd/... suffix, which mirrors the repository layout
+		if idx := strings.LastIndex(pkg, "/cmd/"); idx >= 0 {
+			return pkg[idx+1:]
+		}
+		return path.Base(pkg)
+	}
+	return path.Clean(pkg)
+}
+
+// =============================================================================
+// DOCKERFILE
+// =============================================================================
+
+// dockerGoBuildPattern finds go build/install invocations in RUN instructions.
+var dockerGoBuildPattern = regexp.MustCompile(`\bgo\s+(build|install)\s`)
+
+// goBuildValueFlags are go build flags that take a separate value.
+var goBuildValueFlags = map[string]bool{
+	"-ldflags": true, "-gcflags": true, "-asmflags": true, "-tags": true, "-mod": true,
+	"-modfile": true, "-buildmode": true, "-p": true, "-pkgdir": true, "-overlay": true, "-pgo": true,
+}
+
+// dockerInstruction is a Dockerfile instruction with continuation lines joined.
+type dockerInstruction struct {
+	Keyword string // Uppercase instruction (FROM, RUN, ENTRYPOINT, ...)
+	Args    string
+	Line    int
+}
+
+// parseDockerfile extracts the image built by a Dockerfile. The entrypoint
+// binary is traced back through multi-stage builds: "go build -o /out/app
+// ./cmd/worker", "COPY --from=build /out/app /app" and "ENTRYPOINT
+// ["/app"]" give Binary "cmd/worker".
+func parseDockerfile(content, filePath string) Topology {
+	node := TopologyNodeEntity{
+		Kind:     TopologyKindDockerfile,
+		FilePath: filePath,
+		Line:     1,
+	}
+	builtBinaries := make(map[string]string) // binary base name -> Go package path
+	stageImages := make(map[string]string)
+	var entrypoint, cmd []string
+	var env []TopologyEnvEntity
+	var ports []string
+
+	for _, inst := range splitDockerInstructions(content) {
+		switch inst.Keyword {
+		case "FROM":
+			fields := shellFields(inst.Args)
+			var image, stage string
+			for i := 0; i < len(fields); i++ {
+				switch {
+				case strings.HasPrefix(fields[i], "--"):
+				case strings.EqualFold(fields[i], "as") && i+1 < len(fields):
+					stage = fields[i+1]
+					i++
+				case image == "":
+					image = fields[i]
+				}
+			}
+			if base, ok := stageImages[image]; ok {
+				image = base
+			}
+			if stage != "" {
+				stageImages[stage] = image
+			}
+			// Only the final stage's runtime settings describe the image
+			node.Image = image
+			entrypoint, cmd, env, ports = nil, nil, nil, nil
+		case "RUN":
+			collectGoBuilds(inst.Args, builtBinaries)
+		case "COPY":
+			var args []string
+			for _, f := range shellFields(inst.Args) {
+				if !strings.HasPrefix(f, "--") {
+					args = append(args, f)
+				}
+			}
+			if len(args) == 2 {
+				dest := args[1]
+				if strings.HasSuffix(dest, "/") {
+					dest += path.Base(args[0])
+				}
+				if pkg, ok := builtBinaries[path.Base(args[0])]; ok {
+					builtBinaries[path.Base(dest)] = pkg
+				}
+			}
+		case "ENTRYPOINT":
+			entrypoint = dockerCommand(inst.Args)
+			cmd = nil
+		case "CMD":
+			cmd = dockerCommand(inst.Args)
+		case "ENV":
+			env = append(env, dockerEnv(inst.Args, filePath, inst.Line)...)
+		case "EXPOSE":
+			ports = append(ports, shellFields(inst.Args)...)
+		}
+	}
+
+	command := append(append([]string{}, entrypoint...), cmd...)
+	node.Command = strings.Join(command, " ")
+	node.Binary = commandBinary(command)
+	if pkg, ok := builtBinaries[node.Binary]; ok {
+		node.Binary = pkg
+	}
+	node.Ports = strings.Join(ports, ",")
+	node.Name = dockerfileNodeName(filePath, node.Binary)
+	node.ID = GenerateTopologyNodeID(filePath, node.Kind, node.Name)
+
+	topology := Topology{Nodes: []TopologyNodeEntity{node}}
+	for _, e := range env {
+		e.NodeID = node.ID
+		topology.Env = append(topology.Env, e)
+	}
+	return topology
+}
+
+// splitDockerInstructions splits a Dockerfile into instructions, joining
+// backslash continuations and dropping comment lines.
+func splitDockerInstructions(content string) []dockerInstruction {
+	var out []dockerInstruction
+	var cur strings.Builder
+	startLine := 0
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || (trimmed == "" && cur.Len() == 0) {
+			continue
+		}
+		if cur.Len() == 0 {
+			startLine = i + 1
+		}
+		if strings.HasSuffix(trimmed, "\\") {
+			cur.WriteString(strings.TrimSuffix(trimmed, "\\"))
+			cur.WriteByte(' ')
+			continue
+		}
+		cur.WriteString(trimmed)
+
+		keyword, args, _ := strings.Cut(strings.TrimSpace(cur.String()), " ")
+		out = append(out, dockerInstruction{
+			Keyword: strings.ToUpper(keyword),
+			Args:    strings.TrimSpace(args),
+			Line:    startLine,
+		})
+		cur.Reset()
+	}
+	return out
+}
+
+// collectGoBuilds records the binaries produced by go build/install commands
+// in a RUN instruction, keyed by binary base name.
+func collectGoBuilds(run string, builtBinaries map[string]string) {
+	for _, loc := range dockerGoBuildPattern.FindAllStringSubmatchIndex(run, -1) {
+		install := run[loc[2]:loc[3]] == "install"
+		fields := shellFields(run[loc[1]:])
+
+		var output, pkg string
+		for i := 0; i < len(fields); i++ {
+			f := fields[i]
+			switch {
+			case f == "-o" && i+1 < len(fields):
+				output = fields[i+1]
+				i++
+			case strings.HasPrefix(f, "-o="):
+				output = strings.TrimPrefix(f, "-o=")
+			case strings.HasPrefix(f, "-"):
+				if goBuildValueFlags[f] {
+					i++
+				}
+			default:
+				pkg = f
+			}
+		}
+		if pkg == "" {
+			pkg = "."
+		}
+		pkg, _, _ = strings.Cut(pkg, "@")
+		pkg = normalizeGoPackagePath(pkg)
+
+		name := path.Base(pkg)
+		if output != "" && !install {
+			if strings.HasSuffix(output, "/") {
+				output += name
+			}
+			name = path.Base(output)
+		}
+		if name != "." && name != "/" {
+			builtBinaries[name] = pkg
+		}
+	}
+}
+
+// dockerCommand parses an ENTRYPOINT/CMD in exec form (JSON array) or shell form.
+func dockerCommand(args string) []string {
+	if strings.HasPrefix(args, "[") {
+		var exec []string
+		if err := json.Unmarshal([]byte(args), &exec); err == nil {
+			return exec
+		}
+	}
+	return []string{"/bin/sh", "-c", args}
+}
+
+// dockerEnv parses ENV KEY=value ... and the legacy ENV KEY value form.
+func dockerEnv(args, filePath string, line int) []TopologyEnvEntity {
+	fields := shellFields(args)
+	if len(fields) == 0 {
+		return nil
+	}
+	if !strings.Contains(fields[0], "=") {
+		return []TopologyEnvEntity{{Name: fields[0], Value: strings.Join(fields[1:], " "), FilePath: filePath, Line: line}}
+	}
+	var env []TopologyEnvEntity
+	for _, f := range fields {
+		if name, value, ok := strings.Cut(f, "="); ok {
+			env = append(env, TopologyEnvEntity{Name: name, Value: value, FilePath: filePath, Line: line})
+		}
+	}
+	return env
+}
+
+// dockerfileNodeName names the image built by a Dockerfile after its suffix
+// (Dockerfile.worker), prefix (worker.Dockerfile) or directory; a Dockerfile
+// at the repository root is named after its binary.
+func dockerfileNodeName(filePath, binary string) string {
+	base := path.Base(filePath)
+	lower := strings.ToLower(base)
+	switch {
+	case strings.HasPrefix(lower, "dockerfile."):
+		return base[len("dockerfile."):]
+	case strings.HasSuffix(lower, ".dockerfile"):
+		return base[:len(base)-len(".dockerfile")]
+	}
+	if dir := path.Dir(filePath); dir != "." {
+		return path.Base(dir)
+	}
+	if binary != "" {
+		return path.Base(binary)
+	}
+	return base
+}
+
+// =============================================================================
+// COMPOSE AND KUBERNETES (YAML)
+// =============================================================================
+
+// k8sWorkloadKinds are the Kubernetes kinds whose pod template runs containers.
+var k8sWorkloadKinds = map[string]bool{
+	"Deployment": true, "StatefulSet": true, "DaemonSet": true, "ReplicaSet": true,
+	"Job": true, "CronJob": true, "Pod": true,
+}
+
+// parseTopologyYAML extracts compose services or Kubernetes workloads and
+// Services from a YAML file. Multi-document manifests are supported.
+func parseTopologyYAML(content, filePath string) Topology {
+	var topology Topology
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if !errors.Is(err, io.EOF) {
+				// Templated YAML (Helm, Kustomize patches with placeholders) is not parseable
+				return topology
+			}
+			break
+		}
+		if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+			continue
+		}
+		root := doc.Content[0]
+		if root.Kind != yaml.MappingNode {
+			continue
+		}
+		if yamlString(yamlChild(root, "apiVersion")) != "" && yamlString(yamlChild(root, "kind")) != "" {
+			parseK8sObject(root, filePath, &topology)
+		} else if services := yamlChild(root, "services"); services != nil && services.Kind == yaml.MappingNode {
+			parseComposeServices(services, filePath, &topology)
+		}
+	}
+	return topology
+}
+
+// parseComposeServices extracts docker-compose services.
+func parseComposeServices(services *yaml.Node, filePath string, topology *Topology) {
+	composeDir := path.Dir(filePath)
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		key, svc := services.Content[i], services.Content[i+1]
+		if svc.Kind != yaml.MappingNode {
+			continue
+		}
+		node := TopologyNodeEntity{
+			Kind:     TopologyKindCompose,
+			Name:     key.Value,
+			Image:    yamlString(yamlChild(svc, "image")),
+			FilePath: filePath,
+			Line:     key.Line,
+		}
+		node.ID = GenerateTopologyNodeID(filePath, node.Kind, node.Name)
+
+		command := append(yamlCommand(yamlChild(svc, "entrypoint")), yamlCommand(yamlChild(svc, "command"))...)
+		node.Command = strings.Join(command, " ")
+		node.Binary = commandBinary(command)
+
+		var ports []string
+		if p := yamlChild(svc, "ports"); p != nil {
+			for _, item := range p.Content {
+				if item.Kind == yaml.MappingNode {
+					port := yamlString(yamlChild(item, "target"))
+					if published := yamlString(yamlChild(item, "published")); published != "" {
+						port = published + ":" + port
+					}
+					ports = append(ports, port)
+				} else {
+					ports = append(ports, item.Value)
+				}
+			}
+		}
+		node.Ports = strings.Join(ports, ",")
+		topology.Nodes = append(topology.Nodes, node)
+
+		if build := yamlChild(svc, "build"); build != nil {
+			context, dockerfile := build.Value, "Dockerfile"
+			if build.Kind == yaml.MappingNode {
+				context = yamlString(yamlChild(build, "context"))
+				if df := yamlString(yamlChild(build, "dockerfile")); df != "" {
+					dockerfile = df
+				}
+			}
+			if context == "" {
+				context = "."
+			}
+			topology.Edges = append(topology.Edges, TopologyEdge{
+				FromID:   node.ID,
+				Target:   path.Join(composeDir, context, dockerfile),
+				Kind:     TopologyEdgeBuilds,
+				FilePath: filePath,
+				Line:     build.Line,
+			})
+		} else if node.Image != "" {
+			topology.Edges = append(topology.Edges, TopologyEdge{
+				FromID: node.ID, Target: node.Image, Kind: TopologyEdgeImage, FilePath: filePath, Line: node.Line,
+			})
+		}
+
+		if deps := yamlChild(svc, "depends_on"); deps != nil {
+			var names []*yaml.Node
+			if deps.Kind == yaml.MappingNode {
+				for j := 0; j < len(deps.Content); j += 2 {
+					names = append(names, deps.Content[j])
+				}
+			} else {
+				names = deps.Content
+			}
+			for _, dep := range names {
+				topology.Edges = append(topology.Edges, TopologyEdge{
+					FromID:   node.ID,
+					ToID:     GenerateTopologyNodeID(filePath, TopologyKindCompose, dep.Value),
+					Kind:     TopologyEdgeDependsOn,
+					FilePath: filePath,
+					Line:     dep.Line,
+				})
+			}
+		}
+
+		if envNode := yamlChild(svc, "environment"); envNode != nil {
+			if envNode.Kind == yaml.MappingNode {
+				for j := 0; j+1 < len(envNode.Content); j += 2 {
+					topology.Env = append(topology.Env, TopologyEnvEntity{
+						NodeID: node.ID, Name: envNode.Content[j].Value, Value: envNode.Content[j+1].Value,
+						FilePath: filePath, Line: envNode.Content[j].Line,
+					})
+				}
+			} else {
+				for _, item := range envNode.Content {
+					name, value, _ := strings.Cut(item.Value, "=")
+					topology.Env = append(topology.Env, TopologyEnvEntity{
+						NodeID: node.ID, Name: name, Value: value, FilePath: filePath, Line: item.Line,
+					})
+				}
+			}
+		}
+		if envFile := yamlChild(svc, "env_file"); envFile != nil {
+			files := []*yaml.Node{envFile}
+			if envFile.Kind == yaml.SequenceNode {
+				files = envFile.Content
+			}
+			for _, f := range files {
+				name := f.Value
+				if f.Kind == yaml.MappingNode {
+					name = yamlString(yamlChild(f, "path"))
+				}
+				topology.Env = append(topology.Env, TopologyEnvEntity{
+					NodeID: node.ID, Name: "*", Value: "env_file:" + name, FilePath: filePath, Line: f.Line,
+				})
+			}
+		}
+	}
+}
+
+// parseK8sObject extracts a Kubernetes workload (one node per container) or Service.
+func parseK8sObject(root *yaml.Node, filePath string, topology *Topology) {
+	kind := yamlString(yamlChild(root, "kind"))
+	metadata := yamlChild(root, "metadata")
+	name := yamlString(yamlChild(metadata, "name"))
+	spec := yamlChild(root, "spec")
+	if name == "" || spec == nil {
+		return
+	}
+
+	if kind == "Service" {
+		node := TopologyNodeEntity{
+			Kind:     TopologyKindK8sService,
+			Name:     name,
+			Labels:   yamlLabels(yamlChild(spec, "selector")),
+			FilePath: filePath,
+			Line:     root.Line,
+		}
+		node.ID = GenerateTopologyNodeID(filePath, node.Kind, node.Name)
+		var ports []string
+		if p := yamlChild(spec, "ports"); p != nil {
+			for _, item := range p.Content {
+				port := yamlString(yamlChild(item, "port"))
+				if target := yamlString(yamlChild(item, "targetPort")); target != "" && target != port {
+					port += ":" + target
+				}
+				ports = append(ports, port)
+			}
+		}
+		node.Ports = strings.Join(ports, ",")
+		topology.Nodes = append(topology.Nodes, node)
+		if node.Labels != "" {
+			topology.Edges = append(topology.Edges, TopologyEdge{
+				FromID: node.ID, Target: node.Labels, Kind: TopologyEdgeExposes, FilePath: filePath, Line: node.Line,
+			})
+		}
+		return
+	}
+
+	if !k8sWorkloadKinds[kind] {
+		return
+	}
+	podMeta, podSpec := metadata, spec
+	if kind == "CronJob" {
+		spec = yamlChild(yamlChild(spec, "jobTemplate"), "spec")
+	}
+	if kind != "Pod" {
+		template := yamlChild(spec, "template")
+		podMeta, podSpec = yamlChild(template, "metadata"), yamlChild(template, "spec")
+	}
+	containers := yamlChild(podSpec, "containers")
+	if containers == nil {
+		return
+	}
+	labels := yamlLabels(yamlChild(podMeta, "labels"))
+
+	for _, c := range containers.Content {
+		node := TopologyNodeEntity{
+			Kind:     "k8s_" + strings.ToLower(kind),
+			Name:     name,
+			Image:    yamlString(yamlChild(c, "image")),
+			Labels:   labels,
+			FilePath: filePath,
+			Line:     c.Line,
+		}
+		if len(containers.Content) > 1 {
+			node.Name = name + "/" + yamlString(yamlChild(c, "name"))
+		}
+		node.ID = GenerateTopologyNodeID(filePath, node.Kind, node.Name)
+
+		command := yamlCommand(yamlChild(c, "command"))
+		args := yamlCommand(yamlChild(c, "args"))
+		node.Command = strings.Join(append(append([]string{}, command...), args...), " ")
+		if len(command) > 0 {
+			// Without command the image entrypoint runs; it comes from the Dockerfile
+			node.Binary = commandBinary(append(command, args...))
+		}
+
+		var ports []string
+		if p := yamlChild(c, "ports"); p != nil {
+			for _, item := range p.Content {
+				port := yamlString(yamlChild(item, "containerPort"))
+				if proto := yamlString(yamlChild(item, "protocol")); proto != "" && proto != "TCP" {
+					port += "/" + strings.ToLower(proto)
+				}
+				ports = append(ports, port)
+			}
+		}
+		node.Ports = strings.Join(ports, ",")
+		topology.Nodes = append(topology.Nodes, node)
+
+		if node.Image != "" {
+			topology.Edges = append(topology.Edges, TopologyEdge{
+				FromID: node.ID, Target: node.Image, Kind: TopologyEdgeImage, FilePath: filePath, Line: node.Line,
+			})
+		}
+		topology.Env = append(topology.Env, k8sContainerEnv(c, node.ID, filePath)...)
+	}
+}
+
+// k8sContainerEnv extracts env and envFrom entries of a container. Values from
+// secrets, config maps and fields are recorded by source ("secret:db/password").
+func k8sContainerEnv(container *yaml.Node, nodeID, filePath string) []TopologyEnvEntity {
+	var env []TopologyEnvEntity
+	if list := yamlChild(container, "env"); list != nil {
+		for _, item := range list.Content {
+			value := yamlString(yamlChild(item, "value"))
+			if from := yamlChild(item, "valueFrom"); from != nil {
+				value = k8sValueSource(from)
+			}
+			env = append(env, TopologyEnvEntity{
+				NodeID: nodeID, Name: yamlString(yamlChild(item, "name")), Value: value,
+				FilePath: filePath, Line: item.Line,
+			})
+		}
+	}
+	if list := yamlChild(container, "envFrom"); list != nil {
+		for _, item := range list.Content {
+			env = append(env, TopologyEnvEntity{
+				NodeID: nodeID, Name: "*", Value: k8sValueSource(item), FilePath: filePath, Line: item.Line,
+			})
+		}
+	}
+	return env
+}
+
+// k8sValueSource describes a valueFrom/envFrom source.
+func k8sValueSource(from *yaml.Node) string {
+	for _, src := range []struct{ key, prefix string }{
+		{"secretKeyRef", "secret:"}, {"configMapKeyRef", "configmap:"},
+		{"secretRef", "secret:"}, {"configMapRef", "configmap:"},
+	} {
+		if ref := yamlChild(from, src.key); ref != nil {
+			value := src.prefix + yamlString(yamlChild(ref, "name"))
+			if key := yamlString(yamlChild(ref, "key")); key != "" {
+				value += "/" + key
+			}
+			return value
+		}
+	}
+	if ref := yamlChild(from, "fieldRef"); ref != nil {
+		return "field:" + yamlString(yamlChild(ref, "fieldPath"))
+	}
+	return ""
+}
+
+// yamlChild returns the value of key in a mapping node, or nil.
+func yamlChild(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlString returns the value of a scalar node, or "".
+func yamlString(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+		return ""
+	}
+	return node.Value
+}
+
+// yamlCommand returns a command given as a list or a shell string.
+func yamlCommand(node *yaml.Node) []string {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.ScalarNode {
+		return shellFields(node.Value)
+	}
+	var args []string
+	for _, item := range node.Content {
+		args = append(args, item.Value)
+	}
+	return args
+}
+
+// yamlLabels formats a label map as sorted, comma-separated key=value pairs.
+func yamlLabels(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return ""
+	}
+	var pairs []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, node.Content[i].Value+"="+node.Content[i+1].Value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// =============================================================================
+// TERRAFORM
+// =============================================================================
+
+var (
+	tfResourcePattern = regexp.MustCompile(`(?m)^[ \t]*resource\s+"([\w-]+)"\s+"([\w-]+)"\s*\{`)
+	tfImagePattern    = regexp.MustCompile(`\bimage"?\s*[=:]\s*"([^"]+)"`)
+	tfCommandPattern  = regexp.MustCompile(`\b(?:command|entry_?[pP]oint)"?\s*[=:]\s*\[([^\]]*)\]`)
+	tfPortPattern     = regexp.MustCompile(`\b(?:container_port|containerPort|port)"?\s*[=:]\s*(\d+)`)
+	tfQuotedPattern   = regexp.MustCompile(`"([^"]*)"`)
+	tfRefPattern      = regexp.MustCompile(`\b([a-z][a-z0-9]*_[a-z0-9_]+)\.([A-Za-z_][\w-]*)\b`)
+
+	// tfEnvPairPattern matches name/value pairs of env blocks (Cloud Run,
+	// kubernetes provider) and ECS container definitions (environment, secrets).
+	tfEnvPairPattern = regexp.MustCompile(`\{\s*"?name"?\s*[=:]\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*,?\s*"?(?:value|valueFrom)"?\s*[=:]\s*("[^"\n]*"|[^\s,}]+)`)
+	// tfVariablesPattern matches environment { variables = { ... } } maps (Lambda).
+	tfVariablesPattern = regexp.MustCompile(`\bvariables\s*=\s*\{([^}]*)\}`)
+	tfAssignPattern    = regexp.MustCompile(`(?m)^\s*"?([A-Za-z_][A-Za-z0-9_]*)"?\s*=\s*(.+?)\s*$`)
+)
+
+// parseTerraform extracts resource blocks from a .tf file. Container images,
+// commands, ports and env vars are read from the common attribute shapes of
+// ECS, Cloud Run, Lambda and the kubernetes provider; references to other
+// resources ("aws_ecs_task_definition.worker.arn") become depends_on edges.
+func parseTerraform(content, filePath string) Topology {
+	var topology Topology
+	for _, m := range tfResourcePattern.FindAllStringSubmatchIndex(content, -1) {
+		openIdx := m[1] - 1
+		closeIdx := matchTerraformBrace(content, openIdx)
+		if closeIdx < 0 {
+			continue
+		}
+		body := content[openIdx+1 : closeIdx]
+		line := strings.Count(content[:m[0]], "\n") + 1
+		bodyLine := strings.Count(content[:openIdx], "\n") + 1
+
+		node := TopologyNodeEntity{
+			Kind:     TopologyKindTerraform,
+			Name:     content[m[2]:m[3]] + "." + content[m[4]:m[5]],
+			FilePath: filePath,
+			Line:     line,
+		}
+		node.ID = GenerateTopologyNodeID(filePath, node.Kind, node.Name)
+		if im := tfImagePattern.FindStringSubmatch(body); im != nil {
+			node.Image = im[1]
+		}
+		if cm := tfCommandPattern.FindStringSubmatch(body); cm != nil {
+			var command []string
+			for _, q := range tfQuotedPattern.FindAllStringSubmatch(cm[1], -1) {
+				command = append(command, q[1])
+			}
+			node.Command = strings.Join(command, " ")
+			node.Binary = commandBinary(command)
+		}
+		var ports []string
+		seenPorts := make(map[string]bool)
+		for _, pm := range tfPortPattern.FindAllStringSubmatch(body, -1) {
+			if !seenPorts[pm[1]] {
+				seenPorts[pm[1]] = true
+				ports = append(ports, pm[1])
+			}
+		}
+		node.Ports = strings.Join(ports, ",")
+		topology.Nodes = append(topology.Nodes, node)
+
+		if node.Image != "" {
+			topology.Edges = append(topology.Edges, TopologyEdge{
+				FromID: node.ID, Target: node.Image, Kind: TopologyEdgeImage, FilePath: filePath, Line: line,
+			})
+		}
+
+		lineAt := func(offset int) int { return bodyLine + strings.Count(body[:offset], "\n") }
+		for _, em := range tfEnvPairPattern.FindAllStringSubmatchIndex(body, -1) {
+			topology.Env = append(topology.Env, TopologyEnvEntity{
+				NodeID: node.ID, Name: body[em[2]:em[3]], Value: strings.Trim(body[em[4]:em[5]], `"`),
+				FilePath: filePath, Line: lineAt(em[2]),
+			})
+		}
+		for _, vm := range tfVariablesPattern.FindAllStringSubmatchIndex(body, -1) {
+			vars := body[vm[2]:vm[3]]
+			for _, am := range tfAssignPattern.FindAllStringSubmatchIndex(vars, -1) {
+				topology.Env = append(topology.Env, TopologyEnvEntity{
+					NodeID: node.ID, Name: vars[am[2]:am[3]], Value: strings.Trim(vars[am[4]:am[5]], `"`),
+					FilePath: filePath, Line: lineAt(vm[2] + am[2]),
+				})
+			}
+		}
+
+		seenRefs := make(map[string]bool)
+		for _, rm := range tfRefPattern.FindAllStringSubmatchIndex(body, -1) {
+			ref := body[rm[2]:rm[3]] + "." + body[rm[4]:rm[5]]
+			if seenRefs[ref] || ref == node.Name {
+				continue
+			}
+			seenRefs[ref] = true
+			topology.Edges = append(topology.Edges, TopologyEdge{
+				FromID: node.ID, Target: ref, Kind: TopologyEdgeDependsOn, FilePath: filePath, Line: lineAt(rm[2]),
+			})
+		}
+	}
+	return topology
+}
+
+// matchTerraformBrace returns the index of the brace closing the one at
+// openIdx, skipping strings, comments and heredocs; -1 if unbalanced.
+func matchTerraformBrace(s string, openIdx int) int {
+	depth := 0
+	for i := openIdx; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case c == '#' || (c == '/' && i+1 < len(s) && s[i+1] == '/'):
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return -1
+			}
+			i += end + 3
+		case c == '<' && strings.HasPrefix(s[i:], "<<"):
+			marker := strings.TrimLeft(s[i+2:], "-")
+			nl := strings.IndexByte(marker, '\n')
+			if nl < 0 {
+				return -1
+			}
+			marker = strings.TrimSpace(marker[:nl])
+			if marker == "" {
+				continue
+			}
+			end := regexp.MustCompile(`(?m)^\s*` + regexp.QuoteMeta(marker) + `\s*$`).FindStringIndex(s[i:])
+			if end == nil {
+				return -1
+			}
+			i += end[1] - 1
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
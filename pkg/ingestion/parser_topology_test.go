@@ -0,0 +1,175 @@
+package ingestion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseTopologyTestFile is a helper that reads an infrastructure fixture and parses it.
+// relPath is relative to testdata/topology and used as the indexed file path.
+func parseTopologyTestFile(t *testing.T, relPath, language string) Topology {
+	t.Helper()
+
+	code, err := os.ReadFile(filepath.Join("testdata/topology", relPath))
+	require.NoError(t, err, "Failed to read test fixture: %s", relPath)
+
+	return parseTopology(string(code), relPath, language)
+}
+
+// findTopologyNode returns the node with the given kind and name or nil.
+func findTopologyNode(nodes []TopologyNodeEntity, kind, name string) *TopologyNodeEntity {
+	for i := range nodes {
+		if nodes[i].Kind == kind && nodes[i].Name == name {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+// topologyEnv returns the env vars of a node as name -> value.
+func topologyEnv(topology Topology, nodeID string) map[string]string {
+	env := make(map[string]string)
+	for _, e := range topology.Env {
+		if e.NodeID == nodeID {
+			env[e.Name] = e.Value
+		}
+	}
+	return env
+}
+
+// TestTopologyParser_Dockerfile tests multi-stage binary tracing, env and ports.
+func TestTopologyParser_Dockerfile(t *testing.T) {
+	topology := parseTopologyTestFile(t, "deploy/worker/Dockerfile", "dockerfile")
+
+	require.Len(t, topology.Nodes, 1)
+	node := topology.Nodes[0]
+	assert.Equal(t, TopologyKindDockerfile, node.Kind)
+	assert.Equal(t, "worker", node.Name, "Dockerfiles are named after their directory")
+	assert.Equal(t, "gcr.io/distroless/static", node.Image, "Image is the final stage's base image")
+	assert.Equal(t, "/app/orders-worker --concurrency 4", node.Command)
+	assert.Equal(t, "cmd/worker", node.Binary, "Entrypoint should be traced through COPY --from to go build")
+	assert.Equal(t, "9090", node.Ports)
+
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "info", "QUEUE": "orders high"}, topologyEnv(topology, node.ID))
+}
+
+// TestTopologyParser_Compose tests compose services, build contexts and env.
+func TestTopologyParser_Compose(t *testing.T) {
+	topology := parseTopologyTestFile(t, "docker-compose.yml", "yaml")
+	require.Len(t, topology.Nodes, 3)
+
+	worker := findTopologyNode(topology.Nodes, TopologyKindCompose, "worker")
+	require.NotNil(t, worker)
+	assert.Equal(t, 2, worker.Line)
+	assert.Equal(t, "ghcr.io/acme/orders-worker:dev", worker.Image)
+	assert.Equal(t, map[string]string{
+		"DATABASE_URL": "postgres://orders@db/orders",
+		"LOG_LEVEL":    "",
+		"*":            "env_file:.env",
+	}, topologyEnv(topology, worker.ID))
+
+	api := findTopologyNode(topology.Nodes, TopologyKindCompose, "api")
+	require.NotNil(t, api)
+	assert.Equal(t, "cmd/api", api.Binary, "go run commands should give the package path")
+	assert.Equal(t, "8080:8080,9001:9000", api.Ports)
+	assert.Equal(t, map[string]string{"API_TOKEN": "${API_TOKEN}"}, topologyEnv(topology, api.ID))
+
+	targets := make(map[string]string)
+	for _, e := range topology.Edges {
+		if e.FromID == worker.ID {
+			targets[e.Kind] = e.Target + e.ToID
+		}
+	}
+	assert.Equal(t, "deploy/worker/Dockerfile", targets[TopologyEdgeBuilds])
+	assert.Equal(t, GenerateTopologyNodeID("docker-compose.yml", TopologyKindCompose, "db"), targets[TopologyEdgeDependsOn])
+}
+
+// TestTopologyParser_Kubernetes tests workloads, Services and env sources in a multi-document manifest.
+func TestTopologyParser_Kubernetes(t *testing.T) {
+	topology := parseTopologyTestFile(t, "k8s.yaml", "yaml")
+
+	worker := findTopologyNode(topology.Nodes, "k8s_deployment", "orders-worker/worker")
+	require.NotNil(t, worker, "Multi-container pods get one node per container")
+	assert.NotNil(t, findTopologyNode(topology.Nodes, "k8s_deployment", "orders-worker/proxy"))
+	assert.Equal(t, "app=orders-worker,tier=backend", worker.Labels)
+	assert.Equal(t, "9090", worker.Ports)
+	assert.Empty(t, worker.Binary, "Without command the image entrypoint runs")
+	assert.Equal(t, map[string]string{
+		"DATABASE_URL": "secret:orders-db/url",
+		"LOG_LEVEL":    "debug",
+		"*":            "configmap:orders-config",
+	}, topologyEnv(topology, worker.ID))
+
+	svc := findTopologyNode(topology.Nodes, TopologyKindK8sService, "orders-worker")
+	require.NotNil(t, svc)
+	assert.Equal(t, "app=orders-worker", svc.Labels)
+	assert.Equal(t, "80:9090", svc.Ports)
+	assert.Equal(t, 31, svc.Line)
+
+	cleanup := findTopologyNode(topology.Nodes, "k8s_cronjob", "cleanup")
+	require.NotNil(t, cleanup)
+	assert.Equal(t, "cleanup", cleanup.Binary, "Shell wrappers should be unwrapped")
+}
+
+// TestTopologyParser_Terraform tests resource blocks, container attributes and references.
+func TestTopologyParser_Terraform(t *testing.T) {
+	topology := parseTopologyTestFile(t, "main.tf", "terraform")
+	require.Len(t, topology.Nodes, 3, "Heredocs and comments must not break brace matching")
+
+	task := findTopologyNode(topology.Nodes, TopologyKindTerraform, "aws_ecs_task_definition.worker")
+	require.NotNil(t, task)
+	assert.Equal(t, 1, task.Line)
+	assert.Equal(t, "ghcr.io/acme/orders-worker:${var.tag}", task.Image)
+	assert.Equal(t, "orders-worker", task.Binary)
+	assert.Equal(t, "9090", task.Ports)
+	assert.Equal(t, map[string]string{
+		"LOG_LEVEL":    "warn",
+		"DATABASE_URL": "aws_secretsmanager_secret.db.arn",
+	}, topologyEnv(topology, task.ID))
+
+	lambda := findTopologyNode(topology.Nodes, TopologyKindTerraform, "aws_lambda_function.reporter")
+	require.NotNil(t, lambda)
+	assert.Equal(t, map[string]string{"REPORT_BUCKET": "acme-reports", "STAGE": "var.stage"}, topologyEnv(topology, lambda.ID))
+
+	svc := findTopologyNode(topology.Nodes, TopologyKindTerraform, "aws_ecs_service.worker")
+	require.NotNil(t, svc)
+	var refs []string
+	for _, e := range topology.Edges {
+		if e.FromID == svc.ID && e.Kind == TopologyEdgeDependsOn {
+			refs = append(refs, e.Target)
+		}
+	}
+	assert.Equal(t, []string{"aws_ecs_task_definition.worker"}, refs)
+}
+
+// TestTopologyParser_UnrelatedYAML tests that other YAML files yield no topology.
+func TestTopologyParser_UnrelatedYAML(t *testing.T) {
+	ci := "name: CI\non:\n  push:\njobs:\n  test:\n    runs-on: ubuntu-latest\n"
+	assert.Empty(t, parseTopology(ci, ".github/workflows/ci.yml", "yaml").Nodes)
+
+	helm := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: {{ .Values.name }}\n"
+	assert.Empty(t, parseTopology(helm, "chart/templates/deploy.yaml", "yaml").Nodes)
+}
+
+// TestCommandBinary tests entrypoint unwrapping.
+func TestCommandBinary(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"/usr/local/bin/worker", "--flag"}, "worker"},
+		{[]string{"/sbin/tini", "--", "/app/server"}, "server"},
+		{[]string{"sh", "-c", "./migrate && exec /app/api"}, "migrate"},
+		{[]string{"env", "GOMAXPROCS=2", "/app/api"}, "api"},
+		{[]string{"GOMAXPROCS=2", "/app/api"}, "api"},
+		{[]string{"go", "run", "-race", "./cmd/api/"}, "cmd/api"},
+		{[]string{"$BINARY"}, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, commandBinary(tt.args), "%v", tt.args)
+	}
+}
@@ -29,12 +29,18 @@ import (
 	"log/slog"
 
 	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/bash"
 	"github.com/smacker/go-tree-sitter/golang"
 	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/php"
 	"github.com/smacker/go-tree-sitter/python"
 	"github.com/smacker/go-tree-sitter/typescript/typescript"
 )
 
+// scriptMainFunctionName is the synthetic function holding a file's top-level
+// statements for languages executed as scripts (e.g., PHP route files).
+const scriptMainFunctionName = "$main"
+
 // TreeSitterParser uses Tree-sitter for accurate AST-based code parsing.
 // This provides:
 //   - Precise function extraction with correct ranges
@@ -42,7 +48,7 @@ import (
 //   - Call graph extraction (same-file)
 //   - Proper handling of nested functions, closures, methods
 //
-// Supported languages: Go, Python, JavaScript, TypeScript
+// Supported languages: Go, Python, JavaScript, TypeScript, PHP, shell (bash/sh/zsh)
 type TreeSitterParser struct {
 	logger          *slog.Logger
 	maxCodeTextSize int64
@@ -54,6 +60,8 @@ type TreeSitterParser struct {
 	pyPool     sync.Pool
 	jsPool     sync.Pool
 	tsPool     sync.Pool
+	phpPool    sync.Pool
+	bashPool   sync.Pool
 	parserInit sync.Once
 }
 
@@ -91,6 +99,16 @@ func (p *TreeSitterParser) initParsers() {
 			parser.SetLanguage(typescript.GetLanguage())
 			return parser
 		}
+		p.phpPool.New = func() any {
+			parser := sitter.NewParser()
+			parser.SetLanguage(php.GetLanguage())
+			return parser
+		}
+		p.bashPool.New = func() any {
+			parser := sitter.NewParser()
+			parser.SetLanguage(bash.GetLanguage())
+			return parser
+		}
 	})
 }
 
@@ -126,13 +144,18 @@ func (p *TreeSitterParser) truncateCodeText(codeText string) string {
 
 // ParseFile parses a source file and extracts functions using Tree-sitter.
 func (p *TreeSitterParser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
-	p.initParsers()
-
 	// Read file content
 	content, err := os.ReadFile(fileInfo.FullPath)
 	if err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
 	}
+	return p.ParseContent(fileInfo, content)
+}
+
+// ParseContent parses source code held in memory, such as a file read from a
+// git commit, as if it were the file at fileInfo.Path. FullPath is not read.
+func (p *TreeSitterParser) ParseContent(fileInfo FileInfo, content []byte) (*ParseResult, error) {
+	p.initParsers()
 
 	// Compute content hash
 	hash := sha256.Sum256(content)
@@ -152,10 +175,27 @@ func (p *TreeSitterParser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
 	var functions []FunctionEntity
 	var types []TypeEntity
 	var fields []FieldEntity
+	var variables []VariableEntity
 	var calls []CallsEdge
 	var imports []ImportEntity
+	var implements []ImplementsEdge
 	var unresolvedCalls []UnresolvedCall
+	var funcRefs []UnresolvedFuncRef
+	var typeRefs []UnresolvedTypeRef
+	var typeParams []TypeParamEntity
+	var instantiations []UnresolvedInstantiation
+	var concurrency []ConcurrencyEdge
+	var errorSites []ErrorSiteEntity
+	var tests []TestEntity
+	var metrics []FunctionMetricsEntity
+	var sqlSchema SQLSchema
+	var graphQLFields []GraphQLFieldEntity
+	var graphQLResolvers []GraphQLResolverEdge
+	var topology Topology
+	var docs markdownDocsResult
 	var packageName string
+	var packageDoc string
+	var err error
 
 	switch fileInfo.Language {
 	case "go":
@@ -172,10 +212,20 @@ func (p *TreeSitterParser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
 		functions = goResult.Functions
 		types = goResult.Types
 		fields = goResult.Fields
+		variables = goResult.Variables
 		calls = goResult.Calls
 		imports = goResult.Imports
 		unresolvedCalls = goResult.UnresolvedCalls
+		funcRefs = goResult.FuncRefs
+		typeRefs = goResult.TypeRefs
+		typeParams = goResult.TypeParams
+		instantiations = goResult.Instantiations
+		concurrency = goResult.Concurrency
+		errorSites = goResult.ErrorSites
+		tests = goResult.Tests
+		metrics = goResult.Metrics
 		packageName = goResult.PackageName
+		packageDoc = goResult.PackageDoc
 	case "python":
 		parserObj := p.pyPool.Get()
 		parser, ok := parserObj.(*sitter.Parser)
@@ -183,7 +233,19 @@ func (p *TreeSitterParser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
 			return nil, fmt.Errorf("invalid parser type from python pool")
 		}
 		defer p.pyPool.Put(parser)
-		functions, types, calls, err = p.parsePythonAST(parser, content, fileInfo.Path)
+		pyResult, pyErr := p.parsePythonAST(parser, content, fileInfo.Path)
+		if pyErr != nil {
+			return nil, fmt.Errorf("parse python AST: %w", pyErr)
+		}
+		functions = pyResult.Functions
+		types = pyResult.Types
+		variables = pyResult.Variables
+		calls = pyResult.Calls
+		implements = pyResult.Implements
+		unresolvedCalls = pyResult.UnresolvedCalls
+		errorSites = pyResult.ErrorSites
+		tests = extractPythonTests(fileInfo.Path, functions, types)
+		metrics = pyResult.Metrics
 	case "javascript":
 		parserObj := p.jsPool.Get()
 		parser, ok := parserObj.(*sitter.Parser)
@@ -191,7 +253,18 @@ func (p *TreeSitterParser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
 			return nil, fmt.Errorf("invalid parser type from javascript pool")
 		}
 		defer p.jsPool.Put(parser)
-		functions, types, calls, err = p.parseJavaScriptAST(parser, content, fileInfo.Path)
+		jsResult, jsErr := p.parseJavaScriptAST(parser, content, fileInfo.Path)
+		if jsErr != nil {
+			return nil, fmt.Errorf("parse javascript AST: %w", jsErr)
+		}
+		functions = jsResult.Functions
+		types = jsResult.Types
+		variables = jsResult.Variables
+		calls = jsResult.Calls
+		errorSites = jsResult.ErrorSites
+		tests = jsResult.Tests
+		metrics = jsResult.Metrics
+		graphQLResolvers = extractApolloResolvers(string(content), fileInfo.Path, functions)
 	case "typescript":
 		parserObj := p.tsPool.Get()
 		parser, ok := parserObj.(*sitter.Parser)
@@ -199,10 +272,67 @@ func (p *TreeSitterParser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
 			return nil, fmt.Errorf("invalid parser type from typescript pool")
 		}
 		defer p.tsPool.Put(parser)
-		functions, types, calls, err = p.parseTypeScriptAST(parser, content, fileInfo.Path)
+		tsResult, tsErr := p.parseTypeScriptAST(parser, content, fileInfo.Path)
+		if tsErr != nil {
+			return nil, fmt.Errorf("parse typescript AST: %w", tsErr)
+		}
+		functions = tsResult.Functions
+		types = tsResult.Types
+		variables = tsResult.Variables
+		calls = tsResult.Calls
+		fields = tsResult.Fields
+		implements = tsResult.Implements
+		unresolvedCalls = tsResult.UnresolvedCalls
+		errorSites = tsResult.ErrorSites
+		tests = tsResult.Tests
+		metrics = tsResult.Metrics
+		graphQLResolvers = extractApolloResolvers(string(content), fileInfo.Path, functions)
+	case "php":
+		parserObj := p.phpPool.Get()
+		parser, ok := parserObj.(*sitter.Parser)
+		if !ok {
+			return nil, fmt.Errorf("invalid parser type from php pool")
+		}
+		defer p.phpPool.Put(parser)
+		phpResult, phpErr := p.parsePHPAST(parser, content, fileInfo.Path)
+		if phpErr != nil {
+			return nil, fmt.Errorf("parse php AST: %w", phpErr)
+		}
+		functions = phpResult.Functions
+		types = phpResult.Types
+		fields = phpResult.Fields
+		calls = phpResult.Calls
+		imports = phpResult.Imports
+		implements = phpResult.Implements
+		unresolvedCalls = phpResult.UnresolvedCalls
+		metrics = phpResult.Metrics
+		packageName = phpResult.Namespace
+	case "bash":
+		parserObj := p.bashPool.Get()
+		parser, ok := parserObj.(*sitter.Parser)
+		if !ok {
+			return nil, fmt.Errorf("invalid parser type from bash pool")
+		}
+		defer p.bashPool.Put(parser)
+		functions, calls, imports, unresolvedCalls, err = p.parseBashAST(parser, content, fileInfo.Path)
 	case "protobuf":
 		// Use regex-based parsing for protobuf (no tree-sitter grammar bundled)
 		functions, calls = parseProtobufSimplified(content, fileInfo.Path, p)
+	case "sql":
+		// DDL is extracted with a statement splitter; dialects vary too much for one grammar
+		sqlSchema = parseSQLSchema(string(content), fileInfo.Path, p.truncateCodeText)
+	case "graphql":
+		// SDL is small and regular; a hand-written lexer avoids another grammar
+		graphQL := parseGraphQLSchema(string(content), fileInfo.Path, p.truncateCodeText)
+		types = graphQL.Types
+		graphQLFields = graphQL.Fields
+		implements = graphQL.Implements
+	case "dockerfile", "yaml", "terraform":
+		// Infrastructure files are read structurally (YAML decoder, instruction and block scanners)
+		topology = parseTopology(string(content), fileInfo.Path, fileInfo.Language)
+	case "markdown":
+		// Docs are chunked by heading; code mentions are linked after all files are parsed
+		docs = parseMarkdownDocs(string(content), fileInfo.Path, p.truncateCodeText)
 	default:
 		// Unsupported language - return empty result without error
 		p.logger.Debug("parser.treesitter.skip_unsupported",
@@ -240,16 +370,34 @@ func (p *TreeSitterParser) ParseFile(fileInfo FileInfo) (*ParseResult, error) {
 	}
 
 	return &ParseResult{
-		File:            fileEntity,
-		Functions:       functions,
-		Types:           types,
-		Fields:          fields,
-		Defines:         defines,
-		DefinesTypes:    definesTypes,
-		Calls:           calls,
-		Imports:         imports,
-		UnresolvedCalls: unresolvedCalls,
-		PackageName:     packageName,
+		File:             fileEntity,
+		Functions:        functions,
+		Types:            types,
+		Fields:           fields,
+		Variables:        variables,
+		Defines:          defines,
+		DefinesTypes:     definesTypes,
+		Calls:            calls,
+		Imports:          imports,
+		Implements:       implements,
+		SQLSchema:        sqlSchema,
+		TableAccesses:    extractTableAccesses(functions),
+		GraphQLFields:    graphQLFields,
+		GraphQLResolvers: graphQLResolvers,
+		Topology:         topology,
+		DocChunks:        docs.Chunks,
+		DocLinks:         docs.Links,
+		UnresolvedCalls:  unresolvedCalls,
+		FuncRefs:         funcRefs,
+		TypeRefs:         typeRefs,
+		TypeParams:       typeParams,
+		Instantiations:   instantiations,
+		Concurrency:      concurrency,
+		ErrorSites:       errorSites,
+		Tests:            tests,
+		FunctionMetrics:  metrics,
+		PackageName:      packageName,
+		PackageDoc:       packageDoc,
 	}, nil
 }
 
@@ -22,6 +22,7 @@ package ingestion
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
 )
@@ -30,24 +31,41 @@ import (
 // TYPESCRIPT PARSER
 // =============================================================================
 
+// tsParseResult holds everything extracted from a TypeScript file.
+type tsParseResult struct {
+	Functions       []FunctionEntity
+	Types           []TypeEntity
+	Variables       []VariableEntity
+	Calls           []CallsEdge
+	Fields          []FieldEntity
+	Implements      []ImplementsEdge
+	UnresolvedCalls []UnresolvedCall
+	ErrorSites      []ErrorSiteEntity
+	Tests           []TestEntity
+	Metrics         []FunctionMetricsEntity
+}
+
 // parseTypeScriptAST extracts functions, classes, interfaces, and call relationships from TypeScript source using Tree-sitter.
 //
 // Extracts:
 //   - Function declarations (function foo() {})
 //   - Arrow functions (const foo = () => {})
 //   - Function expressions (const foo = function() {})
-//   - Classes (class Foo {})
+//   - Classes, including abstract classes (class Foo {})
 //   - Interfaces (interface Bar {})
 //   - Type aliases (type Baz = ...)
-//   - Methods (within classes)
+//   - Methods (prefixed with class name within classes, e.g., "UserService.create")
 //   - Async functions
-//   - Function calls within the file
+//   - Exported const/let/var declarations (variables)
+//   - `extends` and `implements` clauses (as ImplementsEdge)
+//   - Typed class properties and constructor parameter properties (as FieldEntity)
+//   - Function calls within the file, and this/super calls resolved across files
 //
 // Handles TypeScript-specific syntax including interfaces and type aliases.
-func (p *TreeSitterParser) parseTypeScriptAST(parser *sitter.Parser, content []byte, filePath string) ([]FunctionEntity, []TypeEntity, []CallsEdge, error) {
+func (p *TreeSitterParser) parseTypeScriptAST(parser *sitter.Parser, content []byte, filePath string) (*tsParseResult, error) {
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("tree-sitter parse: %w", err)
+		return nil, fmt.Errorf("tree-sitter parse: %w", err)
 	}
 	defer tree.Close()
 
@@ -61,23 +79,39 @@ func (p *TreeSitterParser) parseTypeScriptAST(parser *sitter.Parser, content []b
 		}
 	}
 
-	var functions []FunctionEntity
+	result := &tsParseResult{}
 	funcNameToID := make(map[string]string)
 	anonCounter := 0
 
-	p.walkTSFunctions(rootNode, content, filePath, &functions, funcNameToID, &anonCounter)
+	p.walkTSFunctions(rootNode, content, filePath, &result.Functions, funcNameToID, &anonCounter)
 
 	// Extract types (interfaces, classes, type aliases)
-	types := p.extractTSTypes(rootNode, content, filePath)
+	result.Types = p.extractTSTypes(rootNode, content, filePath)
+
+	// Extract exported const/let/var declarations
+	result.Variables = p.extractJSVariables(rootNode, content, filePath)
+
+	// Extract class hierarchy and class properties
+	result.Implements = extractTSHeritage(rootNode, content, filePath)
+	result.Fields = extractTSFields(rootNode, content, filePath)
 
 	// Extract calls
-	var calls []CallsEdge
-	for _, fn := range functions {
-		fnCalls := p.extractJSCalls(rootNode, content, fn, funcNameToID)
-		calls = append(calls, fnCalls...)
+	for _, fn := range result.Functions {
+		fnCalls, fnUnresolved := p.extractTSCalls(rootNode, content, fn, funcNameToID)
+		result.Calls = append(result.Calls, fnCalls...)
+		result.UnresolvedCalls = append(result.UnresolvedCalls, fnUnresolved...)
 	}
 
-	return functions, types, calls, nil
+	// Extract errors thrown
+	result.ErrorSites = extractJSErrorSites(rootNode, content, filePath, result.Functions)
+
+	// Extract Jest/Vitest suites and cases of test files
+	result.Tests = extractJSTests(rootNode, content, filePath, "typescript", result.Functions)
+
+	// Compute complexity and size metrics of each function
+	result.Metrics = extractFunctionMetrics(rootNode, content, "typescript", result.Functions)
+
+	return result, nil
 }
 
 // tsWalkContext holds context for TypeScript AST walking.
@@ -158,13 +192,22 @@ func (p *TreeSitterParser) handleTSVariableDeclarator(node *sitter.Node, ctx *ts
 	}
 }
 
-// handleTSMethodDef handles a method definition node.
+// handleTSMethodDef handles a method definition node. Class methods are
+// named "Class.method"; they stay reachable by their simple name for calls
+// within the file.
 func (p *TreeSitterParser) handleTSMethodDef(node *sitter.Node, ctx *tsWalkContext) {
 	fn := p.extractJSMethod(node, ctx.content, ctx.filePath)
-	if fn != nil {
-		*ctx.functions = append(*ctx.functions, *fn)
-		ctx.funcNameToID[fn.Name] = fn.ID
+	if fn == nil {
+		return
 	}
+	simpleName := fn.Name
+	if className := tsEnclosingClass(node, ctx.content); className != "" {
+		fn.Name = className + "." + fn.Name
+		fn.ID = GenerateFunctionID(ctx.filePath, fn.Name, fn.Signature, fn.StartLine, fn.EndLine, fn.StartCol, fn.EndCol)
+	}
+	*ctx.functions = append(*ctx.functions, *fn)
+	ctx.funcNameToID[fn.Name] = fn.ID
+	ctx.funcNameToID[simpleName] = fn.ID
 }
 
 // handleTSMethodSig handles a method signature node (TypeScript interface method).
@@ -220,15 +263,16 @@ func (p *TreeSitterParser) extractTSMethodSignature(node *sitter.Node, content [
 	id := GenerateFunctionID(filePath, name, signature, startLine, endLine, startCol, endCol)
 
 	return &FunctionEntity{
-		ID:        id,
-		Name:      name,
-		Signature: signature,
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Signature:  signature,
+		FilePath:   filePath,
+		DocComment: jsDocComment(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -253,15 +297,16 @@ func (p *TreeSitterParser) extractTSFunctionSignature(node *sitter.Node, content
 	id := GenerateFunctionID(filePath, name, signature, startLine, endLine, startCol, endCol)
 
 	return &FunctionEntity{
-		ID:        id,
-		Name:      name,
-		Signature: signature,
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Signature:  signature,
+		FilePath:   filePath,
+		DocComment: jsDocComment(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -297,7 +342,7 @@ func (p *TreeSitterParser) walkTSTypesAST(node *sitter.Node, content []byte, fil
 		if te != nil {
 			*types = append(*types, *te)
 		}
-	case "class_declaration":
+	case "class_declaration", "abstract_class_declaration":
 		te := p.extractTSClass(node, content, filePath)
 		if te != nil {
 			*types = append(*types, *te)
@@ -335,15 +380,16 @@ func (p *TreeSitterParser) extractTSInterface(node *sitter.Node, content []byte,
 	id := GenerateTypeID(filePath, name, startLine, endLine)
 
 	return &TypeEntity{
-		ID:        id,
-		Name:      name,
-		Kind:      "interface",
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Kind:       "interface",
+		FilePath:   filePath,
+		DocComment: jsDocComment(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -366,15 +412,16 @@ func (p *TreeSitterParser) extractTSClass(node *sitter.Node, content []byte, fil
 	id := GenerateTypeID(filePath, name, startLine, endLine)
 
 	return &TypeEntity{
-		ID:        id,
-		Name:      name,
-		Kind:      "class",
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Kind:       "class",
+		FilePath:   filePath,
+		DocComment: jsDocComment(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
 	}
 }
 
@@ -397,14 +444,350 @@ func (p *TreeSitterParser) extractTSTypeAlias(node *sitter.Node, content []byte,
 	id := GenerateTypeID(filePath, name, startLine, endLine)
 
 	return &TypeEntity{
-		ID:        id,
-		Name:      name,
-		Kind:      "type_alias",
-		FilePath:  filePath,
-		CodeText:  codeText,
-		StartLine: startLine,
-		EndLine:   endLine,
-		StartCol:  startCol,
-		EndCol:    endCol,
+		ID:         id,
+		Name:       name,
+		Kind:       "type_alias",
+		FilePath:   filePath,
+		DocComment: jsDocComment(node, content),
+		CodeText:   codeText,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		StartCol:   startCol,
+		EndCol:     endCol,
+	}
+}
+
+// =============================================================================
+// TYPESCRIPT CLASS HIERARCHY
+// =============================================================================
+
+// tsEnclosingClass returns the name of the class declaring a method_definition,
+// or "" for methods of object literals.
+func tsEnclosingClass(node *sitter.Node, content []byte) string {
+	body := node.Parent()
+	if body == nil || body.Type() != "class_body" {
+		return ""
+	}
+	class := body.Parent()
+	if class == nil {
+		return ""
+	}
+	if nameNode := class.ChildByFieldName("name"); nameNode != nil {
+		return nodeText(nameNode, content)
+	}
+	return ""
+}
+
+// extractTSHeritage records the `extends` and `implements` clauses of classes
+// and the `extends` clause of interfaces as ImplementsEdge, so that
+// `class UserRepo extends BaseRepo implements Repo` yields two edges.
+func extractTSHeritage(node *sitter.Node, content []byte, filePath string) []ImplementsEdge {
+	var edges []ImplementsEdge
+	if node == nil {
+		return edges
+	}
+
+	addEdge := func(typeName string, parent *sitter.Node) {
+		if name := tsTypeName(parent, content); name != "" {
+			edges = append(edges, ImplementsEdge{TypeName: typeName, InterfaceName: name, FilePath: filePath})
+		}
+	}
+
+	switch node.Type() {
+	case "class_declaration", "abstract_class_declaration":
+		nameNode := node.ChildByFieldName("name")
+		for i := 0; nameNode != nil && i < int(node.NamedChildCount()); i++ {
+			heritage := node.NamedChild(i)
+			if heritage.Type() != "class_heritage" {
+				continue
+			}
+			for j := 0; j < int(heritage.NamedChildCount()); j++ {
+				clause := heritage.NamedChild(j)
+				switch clause.Type() {
+				case "extends_clause":
+					if value := clause.ChildByFieldName("value"); value != nil {
+						addEdge(nodeText(nameNode, content), value)
+					}
+				case "implements_clause":
+					for k := 0; k < int(clause.NamedChildCount()); k++ {
+						addEdge(nodeText(nameNode, content), clause.NamedChild(k))
+					}
+				}
+			}
+		}
+	case "interface_declaration":
+		nameNode := node.ChildByFieldName("name")
+		for i := 0; nameNode != nil && i < int(node.NamedChildCount()); i++ {
+			clause := node.NamedChild(i)
+			if clause.Type() != "extends_type_clause" {
+				continue
+			}
+			for j := 0; j < int(clause.NamedChildCount()); j++ {
+				addEdge(nodeText(nameNode, content), clause.NamedChild(j))
+			}
+		}
+	}
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		edges = append(edges, extractTSHeritage(node.NamedChild(i), content, filePath)...)
+	}
+	return edges
+}
+
+// extractTSFields records the typed properties of classes and the parameter
+// properties of their constructors (`constructor(private repo: Repo)`).
+// Properties without an annotation are typed by their `new` initializer.
+func extractTSFields(node *sitter.Node, content []byte, filePath string) []FieldEntity {
+	var fields []FieldEntity
+	if node == nil {
+		return fields
+	}
+
+	if node.Type() == "class_body" {
+		className := ""
+		if class := node.Parent(); class != nil {
+			if nameNode := class.ChildByFieldName("name"); nameNode != nil {
+				className = nodeText(nameNode, content)
+			}
+		}
+		for i := 0; className != "" && i < int(node.NamedChildCount()); i++ {
+			member := node.NamedChild(i)
+			switch member.Type() {
+			case "public_field_definition":
+				if field, ok := tsField(className, member, member.ChildByFieldName("name"), content, filePath); ok {
+					fields = append(fields, field)
+				}
+			case "method_definition":
+				nameNode := member.ChildByFieldName("name")
+				params := member.ChildByFieldName("parameters")
+				if nameNode == nil || params == nil || nodeText(nameNode, content) != "constructor" {
+					continue
+				}
+				for j := 0; j < int(params.NamedChildCount()); j++ {
+					param := params.NamedChild(j)
+					if !isTSParameterProperty(param) {
+						continue
+					}
+					if field, ok := tsField(className, param, param.ChildByFieldName("pattern"), content, filePath); ok {
+						fields = append(fields, field)
+					}
+				}
+			}
+		}
+	}
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		fields = append(fields, extractTSFields(node.NamedChild(i), content, filePath)...)
+	}
+	return fields
+}
+
+// tsField builds the FieldEntity of a property or parameter property, typed by
+// its annotation or else by the class it is initialized with.
+func tsField(className string, node, nameNode *sitter.Node, content []byte, filePath string) (FieldEntity, bool) {
+	if nameNode == nil {
+		return FieldEntity{}, false
+	}
+	fieldType := ""
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		fieldType = tsTypeName(typeNode, content)
+	} else if value := node.ChildByFieldName("value"); value != nil && value.Type() == "new_expression" {
+		if ctor := value.ChildByFieldName("constructor"); ctor != nil {
+			fieldType = tsTypeName(ctor, content)
+		}
+	}
+	if fieldType == "" {
+		return FieldEntity{}, false
+	}
+	return FieldEntity{
+		StructName: className,
+		FieldName:  nodeText(nameNode, content),
+		FieldType:  fieldType,
+		FilePath:   filePath,
+		Line:       int(node.StartPoint().Row) + 1,
+	}, true
+}
+
+// isTSParameterProperty reports whether a constructor parameter declares a
+// property: it carries an accessibility modifier or readonly.
+func isTSParameterProperty(param *sitter.Node) bool {
+	if param.Type() != "required_parameter" && param.Type() != "optional_parameter" {
+		return false
+	}
+	for i := 0; i < int(param.ChildCount()); i++ {
+		switch param.Child(i).Type() {
+		case "accessibility_modifier", "readonly":
+			return true
+		}
+	}
+	return false
+}
+
+// tsTypeName returns the class or interface named by a type expression:
+// "Repo" for Repo, Repo<User>, Repo | null, db.Repo and `: Repo` annotations.
+// Returns "" for predefined, literal, array and object types.
+func tsTypeName(node *sitter.Node, content []byte) string {
+	switch node.Type() {
+	case "type_identifier", "identifier":
+		return nodeText(node, content)
+	case "nested_type_identifier", "member_expression":
+		for _, field := range []string{"name", "property"} {
+			if name := node.ChildByFieldName(field); name != nil {
+				return nodeText(name, content)
+			}
+		}
+	case "generic_type":
+		if name := node.ChildByFieldName("name"); name != nil {
+			return tsTypeName(name, content)
+		}
+	case "type_annotation", "parenthesized_type", "union_type":
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if name := tsTypeName(node.NamedChild(i), content); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// =============================================================================
+// TYPESCRIPT CALL EXTRACTION
+// =============================================================================
+
+// tsFunctionNodeTypes are the nodes whose body holds the calls of a function.
+var tsFunctionNodeTypes = map[string]bool{
+	"function_declaration":           true,
+	"generator_function_declaration": true,
+	"method_definition":              true,
+	"arrow_function":                 true,
+	"function_expression":            true,
+	"function":                       true,
+}
+
+// tsCallContext holds the state of call extraction for one TypeScript function.
+type tsCallContext struct {
+	content      []byte
+	filePath     string
+	callerID     string
+	className    string            // Class of the calling method, "" for functions
+	funcNameToID map[string]string // Functions and methods of the file
+	calls        []CallsEdge
+	unresolved   []UnresolvedCall
+	seen         map[string]bool
+}
+
+// extractTSCalls extracts function calls within a TypeScript function.
+//
+// Calls to functions of the file are resolved directly, as are this.method()
+// calls to a method of the caller's own class. Calls that depend on the class
+// hierarchy are returned as unresolved calls for the resolver:
+//   - "this.method": method inherited from a parent class
+//   - "super.method": method of the parent class
+//   - "this.field.method": method called on a typed property
+func (p *TreeSitterParser) extractTSCalls(root *sitter.Node, content []byte, caller FunctionEntity, funcNameToID map[string]string) ([]CallsEdge, []UnresolvedCall) {
+	fnNode := findNodeAtPosition(root, uint32(caller.StartLine-1), uint32(caller.StartCol-1)) //nolint:gosec // G115: line/col from parsed source are bounded
+	// findNodeAtPosition returns the deepest node (a keyword or the name): climb to the function
+	for fnNode != nil && !tsFunctionNodeTypes[fnNode.Type()] {
+		fnNode = fnNode.Parent()
+	}
+	if fnNode == nil {
+		return nil, nil
+	}
+
+	ctx := &tsCallContext{
+		content:      content,
+		filePath:     caller.FilePath,
+		callerID:     caller.ID,
+		funcNameToID: funcNameToID,
+		seen:         make(map[string]bool),
+	}
+	if className, _, ok := strings.Cut(caller.Name, "."); ok {
+		ctx.className = className
+	}
+
+	p.walkTSCallExpressions(fnNode, ctx)
+	return ctx.calls, ctx.unresolved
+}
+
+// walkTSCallExpressions finds call expressions in TypeScript.
+func (p *TreeSitterParser) walkTSCallExpressions(node *sitter.Node, ctx *tsCallContext) {
+	if node == nil {
+		return
+	}
+
+	if node.Type() == "call_expression" {
+		if funcNode := node.ChildByFieldName("function"); funcNode != nil {
+			p.processTSCall(node, funcNode, ctx)
+		}
+	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		p.walkTSCallExpressions(node.Child(i), ctx)
+	}
+}
+
+// processTSCall records one call: a local edge when the callee is known in the
+// file, an unresolved call when it depends on the class hierarchy.
+func (p *TreeSitterParser) processTSCall(node, funcNode *sitter.Node, ctx *tsCallContext) {
+	if funcNode.Type() == "member_expression" && ctx.className != "" {
+		object := funcNode.ChildByFieldName("object")
+		property := funcNode.ChildByFieldName("property")
+		if object != nil && property != nil {
+			method := nodeText(property, ctx.content)
+			switch object.Type() {
+			case "this":
+				if calleeID, ok := ctx.funcNameToID[ctx.className+"."+method]; ok {
+					ctx.addCall(calleeID, node)
+				} else {
+					ctx.addUnresolved("this."+method, node)
+				}
+				return
+			case "super":
+				ctx.addUnresolved("super."+method, node)
+				return
+			case "member_expression":
+				inner := object.ChildByFieldName("object")
+				field := object.ChildByFieldName("property")
+				if inner != nil && field != nil && inner.Type() == "this" {
+					ctx.addUnresolved("this."+nodeText(field, ctx.content)+"."+method, node)
+					return
+				}
+			}
+		}
+	}
+
+	calleeName := p.extractJSCalleeName(funcNode, ctx.content)
+	if calleeName == "" {
+		return
+	}
+	if calleeID, exists := ctx.funcNameToID[calleeName]; exists {
+		ctx.addCall(calleeID, node)
+	}
+}
+
+// addCall records a call edge to a function of the file.
+func (ctx *tsCallContext) addCall(calleeID string, node *sitter.Node) {
+	if calleeID == ctx.callerID {
+		return
+	}
+	ctx.calls = append(ctx.calls, CallsEdge{
+		CallerID: ctx.callerID,
+		CalleeID: calleeID,
+		CallLine: int(node.StartPoint().Row) + 1,
+	})
+}
+
+// addUnresolved records a call resolved later through the class hierarchy.
+func (ctx *tsCallContext) addUnresolved(calleeName string, node *sitter.Node) {
+	key := ctx.callerID + "->" + calleeName
+	if ctx.seen[key] {
+		return
 	}
+	ctx.seen[key] = true
+	ctx.unresolved = append(ctx.unresolved, UnresolvedCall{
+		CallerID:   ctx.callerID,
+		CalleeName: calleeName,
+		FilePath:   ctx.filePath,
+		Line:       int(node.StartPoint().Row) + 1,
+	})
 }
@@ -79,6 +79,82 @@ func TestTypeScriptParser_Classes(t *testing.T) {
 	assert.GreaterOrEqual(t, len(result.Functions), 2, "Should extract methods")
 }
 
+// TestTypeScriptParser_Heritage tests that extends and implements clauses of
+// classes and interfaces become implements edges.
+func TestTypeScriptParser_Heritage(t *testing.T) {
+	result := parseTypeScriptTestFile(t, "testdata/typescript/dispatch.ts", "typescript")
+
+	var edges []string
+	for _, e := range result.Implements {
+		assert.Equal(t, "dispatch.ts", e.FilePath)
+		edges = append(edges, e.TypeName+" -> "+e.InterfaceName)
+	}
+	assert.Equal(t, []string{
+		"AuditedRepo -> Repo",
+		"BaseRepo -> Repo",
+		"SqlRepo -> BaseRepo",
+		"SqlRepo -> AuditedRepo",
+		"MemoryRepo -> BaseRepo",
+	}, edges)
+
+	kinds := make(map[string]string)
+	for _, typ := range result.Types {
+		kinds[typ.Name] = typ.Kind
+	}
+	assert.Equal(t, "class", kinds["BaseRepo"], "Should extract abstract classes")
+}
+
+// TestTypeScriptParser_Fields tests that typed properties and constructor
+// parameter properties become fields.
+func TestTypeScriptParser_Fields(t *testing.T) {
+	result := parseTypeScriptTestFile(t, "testdata/typescript/dispatch.ts", "typescript")
+
+	var fields []string
+	for _, f := range result.Fields {
+		fields = append(fields, f.StructName+"."+f.FieldName+" "+f.FieldType)
+	}
+	assert.Equal(t, []string{
+		"MemoryRepo.users Map",
+		"UserService.cache MemoryRepo",
+		"UserService.logger Logger",
+		"UserService.repo Repo",
+		"UserService.audit AuditedRepo",
+	}, fields)
+}
+
+// TestTypeScriptParser_HierarchyCalls tests that class methods are qualified,
+// that this calls to the caller's own class resolve in the file, and that
+// inherited, super and property calls are left to the resolver.
+func TestTypeScriptParser_HierarchyCalls(t *testing.T) {
+	result := parseTypeScriptTestFile(t, "testdata/typescript/dispatch.ts", "typescript")
+
+	names := make(map[string]string)
+	for _, fn := range result.Functions {
+		names[fn.ID] = fn.Name
+	}
+	assert.Contains(t, names, GenerateFunctionID("dispatch.ts", "SqlRepo.save", "save(user: User)", 18, 21, 5, 6))
+
+	var calls []string
+	for _, c := range result.Calls {
+		calls = append(calls, names[c.CallerID]+" -> "+names[c.CalleeID])
+	}
+	assert.Equal(t, []string{
+		"SqlRepo.save -> SqlRepo.audit",
+		"UserService.create -> UserService.validate",
+	}, calls)
+
+	var unresolved []string
+	for _, c := range result.UnresolvedCalls {
+		unresolved = append(unresolved, names[c.CallerID]+" -> "+c.CalleeName)
+	}
+	assert.Equal(t, []string{
+		"SqlRepo.save -> this.log",
+		"MemoryRepo.save -> super.log",
+		"UserService.create -> this.repo.save",
+		"UserService.create -> this.cache.save",
+	}, unresolved)
+}
+
 // TestTypeScriptParser_Interfaces tests interface extraction.
 func TestTypeScriptParser_Interfaces(t *testing.T) {
 	result := parseTypeScriptTestFile(t, "testdata/typescript/interface.ts", "typescript")
@@ -701,7 +701,8 @@ func matchCharClass(c byte, class string) bool {
 	return matched
 }
 
-// detectLanguageFromPath detects programming language from file extension.
+// detectLanguageFromPath detects programming language from file extension
+// (or file name, for Dockerfiles).
 func detectLanguageFromPath(path string) string {
 	ext := strings.ToLower(filepath.Ext(path))
 
@@ -732,6 +733,24 @@ func detectLanguageFromPath(path string) string {
 		".zsh":   "bash",
 		".fish":  "bash",
 		".proto": "protobuf",
+		".sql":   "sql",
+
+		// GraphQL SDL
+		".graphql":  "graphql",
+		".graphqls": "graphql",
+
+		// Infrastructure-as-code (compose files and Kubernetes manifests are YAML)
+		".yaml": "yaml",
+		".yml":  "yaml",
+		".tf":   "terraform",
+
+		// Documentation (chunked by heading, linked to the code it mentions)
+		".md":       "markdown",
+		".markdown": "markdown",
+	}
+
+	if isDockerfileName(path) {
+		return "dockerfile"
 	}
 
 	if lang, ok := langMap[ext]; ok {
@@ -61,6 +61,41 @@ type CallResolver struct {
 
 	// stubFunctions: synthetic entries for external type methods (e.g., sql.DB.Query)
 	stubFunctions []FunctionEntity
+
+	// PHP resolution indexes
+	// phpSymbols: fully qualified name → function_id (e.g., "App\Services\UserService.create")
+	phpSymbols map[string]string
+	// phpNamespaces: file_path → namespace declared in the file
+	phpNamespaces map[string]string
+	// phpClasses: short class name → fully qualified names of the classes,
+	// interfaces and traits declaring it (e.g., "User" → ["App\Models\User"])
+	phpClasses map[string][]string
+	// phpParents: fully qualified class name → fully qualified parent classes and used traits
+	phpParents map[string][]string
+	// phpImplementors: fully qualified parent → fully qualified classes declaring it
+	phpImplementors map[string][]string
+	// phpFields: fully qualified class name → property name → declared type, as written
+	phpFields map[string]map[string]string
+
+	// Python resolution indexes
+	// pyBases: class name → base classes in declaration order
+	pyBases map[string][]string
+	// pySubclasses: class name → classes declaring it as a base
+	pySubclasses map[string][]string
+
+	// TypeScript resolution indexes
+	// tsParents: class name → extended class and implemented interfaces
+	tsParents map[string][]string
+
+	// Shell resolution indexes
+	// shellFunctions: file_path → function name → function_id (scripts map "$main")
+	shellFunctions map[string]map[string]string
+	// shellFunctionsByName: function name → function_ids across all scripts
+	shellFunctionsByName map[string][]string
+	// shellFilesByBase: script file name → file paths (e.g., "deploy.sh" → ["scripts/deploy.sh"])
+	shellFilesByBase map[string][]string
+	// binaryMains: binary name or cmd package path → main function_id (e.g., "worker" → cmd/worker main)
+	binaryMains map[string]string
 }
 
 // NewCallResolver creates a new call resolver.
@@ -75,6 +110,19 @@ func NewCallResolver() *CallResolver {
 		qualifiedFunctions:      make(map[string]string),
 		functionIDToName:        make(map[string]string),
 		functionIDToSignature:   make(map[string]string),
+		phpSymbols:              make(map[string]string),
+		phpNamespaces:           make(map[string]string),
+		phpClasses:              make(map[string][]string),
+		phpParents:              make(map[string][]string),
+		phpImplementors:         make(map[string][]string),
+		phpFields:               make(map[string]map[string]string),
+		pyBases:                 make(map[string][]string),
+		pySubclasses:            make(map[string][]string),
+		tsParents:               make(map[string][]string),
+		shellFunctions:          make(map[string]map[string]string),
+		shellFunctionsByName:    make(map[string][]string),
+		shellFilesByBase:        make(map[string][]string),
+		binaryMains:             make(map[string]string),
 	}
 }
 
@@ -88,6 +136,9 @@ func (r *CallResolver) BuildIndex(
 ) {
 	// 1. Build package index from file paths
 	for _, f := range files {
+		if f.Language == "php" {
+			r.phpNamespaces[f.Path] = packageNames[f.Path]
+		}
 		if f.Language != "go" {
 			continue
 		}
@@ -106,6 +157,18 @@ func (r *CallResolver) BuildIndex(
 
 	// 2. Build global function registry and qualified function index
 	for _, fn := range functions {
+		if strings.HasSuffix(fn.FilePath, ".php") {
+			r.indexPHPFunction(fn, packageNames[fn.FilePath])
+			continue
+		}
+		if detectLanguageFromPath(fn.FilePath) == "bash" {
+			r.indexShellFunction(fn)
+			continue
+		}
+		if lang := detectLanguageFromPath(fn.FilePath); lang == "python" || lang == "typescript" {
+			r.indexClassFunction(fn)
+			continue
+		}
 		if !strings.HasSuffix(fn.FilePath, ".go") {
 			continue
 		}
@@ -156,6 +219,9 @@ func (r *CallResolver) BuildIndex(
 	// 4. Build import path to package path mapping
 	// This maps import paths to our local package directories
 	r.buildImportPathMapping()
+
+	// 5. Map cmd/* binaries to their main function for shell invocations
+	r.buildBinaryIndex()
 }
 
 // buildImportPathMapping creates a mapping from Go import paths to local package paths.
@@ -230,6 +296,18 @@ func (r *CallResolver) resolveCallsSequential(unresolvedCalls []UnresolvedCall)
 
 // resolveCall attempts to resolve a single unresolved call.
 func (r *CallResolver) resolveCall(call UnresolvedCall) string {
+	if strings.HasSuffix(call.FilePath, ".php") {
+		return r.resolvePHPCall(call)
+	}
+	if detectLanguageFromPath(call.FilePath) == "bash" {
+		return r.resolveShellCall(call)
+	}
+	if strings.HasSuffix(call.FilePath, ".py") {
+		return r.resolvePythonCall(call)
+	}
+	if detectLanguageFromPath(call.FilePath) == "typescript" {
+		return r.resolveTSCall(call)
+	}
 	if strings.Contains(call.CalleeName, ".") {
 		if id := r.resolveQualifiedCall(call); id != "" {
 			return id
@@ -336,6 +414,10 @@ func (r *CallResolver) findPackageByImportPath(importPath string) string {
 func (r *CallResolver) SetInterfaceIndex(fields []FieldEntity, implements []ImplementsEdge) {
 	// Build fieldIndex: structName → fieldName → fieldType
 	for _, f := range fields {
+		if strings.HasSuffix(f.FilePath, ".php") {
+			r.indexPHPField(f)
+			continue
+		}
 		if r.fieldIndex[f.StructName] == nil {
 			r.fieldIndex[f.StructName] = make(map[string]string)
 		}
@@ -344,10 +426,27 @@ func (r *CallResolver) SetInterfaceIndex(fields []FieldEntity, implements []Impl
 
 	// Build implementsIndex: interfaceName → []typeName
 	implMap := make(map[string][]string)
+	var phpEdges []ImplementsEdge
 	for _, e := range implements {
+		if strings.HasSuffix(e.FilePath, ".php") {
+			r.addPHPClass(phpQualify(r.phpNamespaces[e.FilePath], e.TypeName))
+			phpEdges = append(phpEdges, e)
+			continue
+		}
 		implMap[e.InterfaceName] = append(implMap[e.InterfaceName], e.TypeName)
+		if strings.HasSuffix(e.FilePath, ".py") {
+			r.indexPythonBase(e.TypeName, e.InterfaceName)
+		}
+		if detectLanguageFromPath(e.FilePath) == "typescript" {
+			r.tsParents[e.TypeName] = append(r.tsParents[e.TypeName], e.InterfaceName)
+		}
 	}
 	r.implementsIndex = implMap
+
+	// PHP parents are resolved once every class is known
+	for _, e := range phpEdges {
+		r.indexPHPParent(e)
+	}
 }
 
 // resolveInterfaceCall resolves a call like "field.Method" through interface dispatch.
@@ -366,10 +465,21 @@ func (r *CallResolver) resolveInterfaceCall(call UnresolvedCall) []CallsEdge {
 		return nil
 	}
 
-	// Interface dispatch only applies to Go files — skip TypeScript, Python, etc.
-	// These calls would always miss and create useless external stubs, causing
-	// stubMu.Lock contention that serializes all parallel workers.
-	if !strings.HasSuffix(call.FilePath, ".go") {
+	// Python dispatches through the class hierarchy, without external stubs
+	if strings.HasSuffix(call.FilePath, ".py") {
+		return r.resolvePythonParamCall(call)
+	}
+
+	// PHP dispatches through classes keyed by namespace
+	if strings.HasSuffix(call.FilePath, ".php") {
+		return r.resolvePHPPropertyCall(call)
+	}
+
+	// Interface dispatch only applies to Go and TypeScript files — skip
+	// JavaScript, etc. These calls would always miss and create useless
+	// external stubs.
+	isClassBased := detectLanguageFromPath(call.FilePath) == "typescript"
+	if !strings.HasSuffix(call.FilePath, ".go") && !isClassBased {
 		return nil
 	}
 
@@ -383,6 +493,12 @@ func (r *CallResolver) resolveInterfaceCall(call UnresolvedCall) []CallsEdge {
 		}
 	}
 
+	// Signatures are parsed with Go syntax; TypeScript only supports typed
+	// properties
+	if isClassBased {
+		return nil
+	}
+
 	// Fall back to param-based resolution (standalone functions and method params)
 	return r.resolveInterfaceCallViaParams(call)
 }
@@ -500,6 +616,27 @@ func (r *CallResolver) resolveToImplementations(callerID, methodName, fieldType
 	return []CallsEdge{{CallerID: callerID, CalleeID: stubID}}
 }
 
+// indexClassFunction registers a Python or TypeScript function in the
+// qualified and name indexes. Methods are registered as "Class.method".
+func (r *CallResolver) indexClassFunction(fn FunctionEntity) {
+	if strings.Contains(fn.Name, ".") {
+		r.qualifiedFunctions[fn.Name] = fn.ID
+	}
+	r.functionIDToName[fn.ID] = fn.Name
+	if fn.Signature != "" {
+		r.functionIDToSignature[fn.ID] = fn.Signature
+	}
+}
+
+// callerClass returns the class of the calling method ("UserService.create" → "UserService").
+func (r *CallResolver) callerClass(call UnresolvedCall) string {
+	callerName := r.functionIDToName[call.CallerID]
+	if className, _, ok := strings.Cut(callerName, "."); ok {
+		return className
+	}
+	return ""
+}
+
 // StubFunctions returns synthetic function entries generated for external type methods.
 func (r *CallResolver) StubFunctions() []FunctionEntity {
 	return r.stubFunctions
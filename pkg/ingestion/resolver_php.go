@@ -0,0 +1,239 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"slices"
+	"strings"
+)
+
+// indexPHPFunction registers a PHP function under its fully qualified name
+// (namespace + name). Methods are registered as "Namespace\Class.method" in
+// the qualified index too, so same-named classes in different namespaces do
+// not collide.
+func (r *CallResolver) indexPHPFunction(fn FunctionEntity, namespace string) {
+	r.phpNamespaces[fn.FilePath] = namespace
+	fqn := phpQualify(namespace, fn.Name)
+	r.phpSymbols[fqn] = fn.ID
+
+	if className, _, ok := strings.Cut(fn.Name, "."); ok {
+		r.qualifiedFunctions[fqn] = fn.ID
+		r.addPHPClass(phpQualify(namespace, className))
+	}
+	r.functionIDToName[fn.ID] = fn.Name
+	if fn.Signature != "" {
+		r.functionIDToSignature[fn.ID] = fn.Signature
+	}
+}
+
+// addPHPClass registers a fully qualified class, interface or trait name.
+func (r *CallResolver) addPHPClass(fqcn string) {
+	short := phpShortName(fqcn)
+	if !slices.Contains(r.phpClasses[short], fqcn) {
+		r.phpClasses[short] = append(r.phpClasses[short], fqcn)
+	}
+}
+
+// indexPHPParent records a parent class, interface or trait declared by a
+// PHP class, both resolved to fully qualified names in the class's file.
+func (r *CallResolver) indexPHPParent(e ImplementsEdge) {
+	class := phpQualify(r.phpNamespaces[e.FilePath], e.TypeName)
+	parent := r.phpClassName(e.FilePath, e.InterfaceName, class)
+	if parent == "" || slices.Contains(r.phpParents[class], parent) {
+		return
+	}
+	r.phpParents[class] = append(r.phpParents[class], parent)
+	r.phpImplementors[parent] = append(r.phpImplementors[parent], class)
+}
+
+// indexPHPField records the declared type of a PHP property under the fully
+// qualified name of its class.
+func (r *CallResolver) indexPHPField(f FieldEntity) {
+	class := phpQualify(r.phpNamespaces[f.FilePath], f.StructName)
+	if r.phpFields[class] == nil {
+		r.phpFields[class] = make(map[string]string)
+	}
+	r.phpFields[class][f.FieldName] = f.FieldType
+}
+
+// resolvePHPCall resolves a PHP call using the callee naming produced by extractPHPCalls:
+//   - "helper"          → function in the current namespace, an imported function, or global
+//   - "Class::method"   → static/constructor call through `use` aliases or the current namespace
+//   - "this.method"     → method inherited from a parent class or trait
+//   - "parent::method"  → method on a parent class or trait
+//
+// Chains on variables or properties ("this.repo.save") are left to resolvePHPPropertyCall.
+func (r *CallResolver) resolvePHPCall(call UnresolvedCall) string {
+	name := call.CalleeName
+	namespace := r.phpNamespaces[call.FilePath]
+
+	if className, method, ok := strings.Cut(name, "::"); ok {
+		if className == "parent" {
+			return r.resolvePHPInheritedMethod(r.phpCallerClass(call), method, map[string]bool{})
+		}
+		for _, fqcn := range r.phpCandidateNames(call.FilePath, namespace, className) {
+			if id, ok := r.phpSymbols[fqcn+"."+method]; ok {
+				return id
+			}
+		}
+		return r.resolvePHPInheritedMethod(r.phpClassName(call.FilePath, className, ""), method, map[string]bool{})
+	}
+
+	if method, ok := strings.CutPrefix(name, "this."); ok {
+		if strings.Contains(method, ".") {
+			return ""
+		}
+		return r.resolvePHPInheritedMethod(r.phpCallerClass(call), method, map[string]bool{})
+	}
+
+	if strings.Contains(name, ".") {
+		return "" // Method call on a variable
+	}
+
+	for _, fqn := range r.phpCandidateNames(call.FilePath, namespace, name) {
+		if id, ok := r.phpSymbols[fqn]; ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// resolvePHPPropertyCall resolves a call on a typed property of the caller's
+// class ("this.repo.save") to the implementations of the property's type, or
+// to the method of the type itself. Types outside the index get external
+// stubs, as in Go.
+func (r *CallResolver) resolvePHPPropertyCall(call UnresolvedCall) []CallsEdge {
+	parts := strings.Split(call.CalleeName, ".")
+	method := parts[len(parts)-1]
+
+	fields := r.phpFields[r.phpCallerClass(call)]
+	var fieldType string
+	for i := len(parts) - 2; i >= 0 && fieldType == ""; i-- {
+		fieldType = fields[parts[i]]
+	}
+	if fieldType == "" {
+		return nil
+	}
+
+	class := r.phpClassName(call.FilePath, fieldType, "")
+	if class == "" {
+		return r.resolveToImplementations(call.CallerID, method, fieldType)
+	}
+	var edges []CallsEdge
+	for _, impl := range r.phpImplementors[class] {
+		if id := r.resolvePHPMethod(impl, method); id != "" {
+			edges = append(edges, CallsEdge{CallerID: call.CallerID, CalleeID: id})
+		}
+	}
+	if len(edges) > 0 {
+		return edges
+	}
+	if id := r.resolvePHPMethod(class, method); id != "" {
+		return []CallsEdge{{CallerID: call.CallerID, CalleeID: id}}
+	}
+	return nil
+}
+
+// phpCallerClass returns the fully qualified class of the calling method.
+func (r *CallResolver) phpCallerClass(call UnresolvedCall) string {
+	className := r.callerClass(call)
+	if className == "" {
+		return ""
+	}
+	return phpQualify(r.phpNamespaces[call.FilePath], className)
+}
+
+// phpClassName resolves a class reference made in filePath to the fully
+// qualified name of an indexed class other than self: through `use`
+// aliases, the file's namespace, then the global namespace. References the
+// parser shortened ("\App\Models\User" → "User") fall back to the only
+// other indexed class of that short name. Returns "" when none matches.
+func (r *CallResolver) phpClassName(filePath, ref, self string) string {
+	for _, fqcn := range r.phpCandidateNames(filePath, r.phpNamespaces[filePath], ref) {
+		if fqcn != self && slices.Contains(r.phpClasses[phpShortName(fqcn)], fqcn) {
+			return fqcn
+		}
+	}
+	var others []string
+	for _, fqcn := range r.phpClasses[phpShortName(ref)] {
+		if fqcn != self {
+			others = append(others, fqcn)
+		}
+	}
+	if len(others) == 1 {
+		return others[0]
+	}
+	return ""
+}
+
+// phpCandidateNames returns fully qualified names a PHP reference may point to,
+// in PHP's resolution order: imported alias, current namespace, then global.
+func (r *CallResolver) phpCandidateNames(filePath, namespace, ref string) []string {
+	var candidates []string
+
+	head, rest, qualified := strings.Cut(ref, `\`)
+	if importPath, ok := r.fileImports[filePath][head]; ok {
+		if qualified {
+			candidates = append(candidates, importPath+`\`+rest)
+		} else {
+			candidates = append(candidates, importPath)
+		}
+	}
+	if namespace != "" {
+		candidates = append(candidates, namespace+`\`+ref)
+	}
+	return append(candidates, ref)
+}
+
+// resolvePHPMethod returns a method declared by a class or inherited from its
+// parents and traits.
+func (r *CallResolver) resolvePHPMethod(class, method string) string {
+	if id, ok := r.phpSymbols[class+"."+method]; ok {
+		return id
+	}
+	return r.resolvePHPInheritedMethod(class, method, map[string]bool{})
+}
+
+// resolvePHPInheritedMethod looks a method up on the parents and traits of a
+// class, by fully qualified name, walking the declared hierarchy depth-first.
+func (r *CallResolver) resolvePHPInheritedMethod(class, method string, visited map[string]bool) string {
+	if class == "" || visited[class] {
+		return ""
+	}
+	visited[class] = true
+
+	for _, parent := range r.phpParents[class] {
+		if id, ok := r.phpSymbols[parent+"."+method]; ok {
+			return id
+		}
+		if id := r.resolvePHPInheritedMethod(parent, method, visited); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// phpQualify joins a namespace and a name with PHP's separator.
+func phpQualify(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + `\` + name
+}
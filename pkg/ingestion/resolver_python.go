@@ -0,0 +1,202 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"strings"
+)
+
+// indexPythonBase records a base class declared by `class A(B, C)`, keeping
+// the declaration order the MRO depends on.
+func (r *CallResolver) indexPythonBase(className, base string) {
+	r.pyBases[className] = append(r.pyBases[className], base)
+	r.pySubclasses[base] = append(r.pySubclasses[base], className)
+}
+
+// resolvePythonCall resolves a Python call using the callee naming produced by
+// extractPythonCalls:
+//   - "self.method"     → first definition along the MRO of the caller's class
+//   - "super().method"  → first definition along that MRO after the caller's class
+//
+// Calls on parameters ("repo.save") are left to resolvePythonParamCall.
+func (r *CallResolver) resolvePythonCall(call UnresolvedCall) string {
+	className := r.callerClass(call)
+	if className == "" {
+		return ""
+	}
+	if method, ok := strings.CutPrefix(call.CalleeName, "self."); ok {
+		return r.resolvePythonMethod(r.pythonMRO(className), method)
+	}
+	if method, ok := strings.CutPrefix(call.CalleeName, "super()."); ok {
+		return r.resolvePythonMethod(r.pythonMRO(className)[1:], method)
+	}
+	return ""
+}
+
+// resolvePythonParamCall resolves a method called on an annotated parameter,
+// mirroring resolveInterfaceCallViaParams for Go: a call on `repo: Repository`
+// dispatches to every subclass of Repository overriding the method (ABCs and
+// Protocols), else to the definition found along the MRO of Repository.
+func (r *CallResolver) resolvePythonParamCall(call UnresolvedCall) []CallsEdge {
+	receiver, method, ok := strings.Cut(call.CalleeName, ".")
+	if !ok || strings.Contains(method, ".") {
+		return nil
+	}
+
+	for _, param := range ParsePythonSignatureParams(r.functionIDToSignature[call.CallerID]) {
+		if param.Name != receiver {
+			continue
+		}
+
+		var edges []CallsEdge
+		for _, subclass := range r.pythonSubclasses(param.Type) {
+			if calleeID, ok := r.qualifiedFunctions[subclass+"."+method]; ok {
+				edges = append(edges, CallsEdge{CallerID: call.CallerID, CalleeID: calleeID})
+			}
+		}
+		if len(edges) > 0 {
+			return edges
+		}
+		if calleeID := r.resolvePythonMethod(r.pythonMRO(param.Type), method); calleeID != "" {
+			return []CallsEdge{{CallerID: call.CallerID, CalleeID: calleeID}}
+		}
+		return nil
+	}
+	return nil
+}
+
+// resolvePythonMethod returns the first definition of method along classes.
+func (r *CallResolver) resolvePythonMethod(classes []string, method string) string {
+	for _, className := range classes {
+		if id, ok := r.qualifiedFunctions[className+"."+method]; ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// pythonMRO returns the method resolution order of a class, starting with the
+// class itself, using Python's C3 linearization. Hierarchies C3 rejects (or
+// that merge same-named classes of different modules) fall back to a
+// depth-first, left-to-right order without duplicates.
+func (r *CallResolver) pythonMRO(className string) []string {
+	if mro := r.pythonC3(className, map[string]bool{}); mro != nil {
+		return mro
+	}
+
+	var order []string
+	seen := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		order = append(order, name)
+		for _, base := range r.pyBases[name] {
+			visit(base)
+		}
+	}
+	visit(className)
+	return order
+}
+
+// pythonC3 computes the C3 linearization of a class, or nil when the
+// hierarchy is cyclic or inconsistent.
+func (r *CallResolver) pythonC3(className string, visiting map[string]bool) []string {
+	if visiting[className] {
+		return nil
+	}
+	visiting[className] = true
+	defer delete(visiting, className)
+
+	bases := r.pyBases[className]
+	sequences := make([][]string, 0, len(bases)+1)
+	for _, base := range bases {
+		mro := r.pythonC3(base, visiting)
+		if mro == nil {
+			return nil
+		}
+		sequences = append(sequences, mro)
+	}
+	sequences = append(sequences, append([]string(nil), bases...))
+
+	mro := []string{className}
+	for {
+		remaining := sequences[:0]
+		for _, seq := range sequences {
+			if len(seq) > 0 {
+				remaining = append(remaining, seq)
+			}
+		}
+		sequences = remaining
+		if len(sequences) == 0 {
+			return mro
+		}
+
+		head := ""
+		for _, seq := range sequences {
+			if !inPythonMROTail(seq[0], sequences) {
+				head = seq[0]
+				break
+			}
+		}
+		if head == "" {
+			return nil
+		}
+		mro = append(mro, head)
+		for i, seq := range sequences {
+			if seq[0] == head {
+				sequences[i] = seq[1:]
+			}
+		}
+	}
+}
+
+// inPythonMROTail reports whether name appears in the tail of any sequence.
+func inPythonMROTail(name string, sequences [][]string) bool {
+	for _, seq := range sequences {
+		for _, other := range seq[1:] {
+			if other == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pythonSubclasses returns the direct and indirect subclasses of a class.
+func (r *CallResolver) pythonSubclasses(className string) []string {
+	var subclasses []string
+	seen := map[string]bool{className: true}
+	queue := []string{className}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, sub := range r.pySubclasses[current] {
+			if !seen[sub] {
+				seen[sub] = true
+				subclasses = append(subclasses, sub)
+				queue = append(queue, sub)
+			}
+		}
+	}
+	return subclasses
+}
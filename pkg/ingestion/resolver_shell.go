@@ -0,0 +1,120 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// indexShellFunction registers a shell function (or a script's "$main") by file.
+func (r *CallResolver) indexShellFunction(fn FunctionEntity) {
+	if r.shellFunctions[fn.FilePath] == nil {
+		r.shellFunctions[fn.FilePath] = make(map[string]string)
+		base := filepath.Base(fn.FilePath)
+		r.shellFilesByBase[base] = append(r.shellFilesByBase[base], fn.FilePath)
+	}
+	r.shellFunctions[fn.FilePath][fn.Name] = fn.ID
+	if fn.Name != scriptMainFunctionName {
+		r.shellFunctionsByName[fn.Name] = append(r.shellFunctionsByName[fn.Name], fn.ID)
+	}
+	r.functionIDToName[fn.ID] = fn.Name
+}
+
+// buildBinaryIndex maps binaries built from Go main packages under a cmd/
+// directory to their main function: "worker" and "cmd/worker" → main() of cmd/worker.
+func (r *CallResolver) buildBinaryIndex() {
+	for pkgPath, pkgInfo := range r.packageIndex {
+		if pkgInfo.PackageName != "main" {
+			continue
+		}
+		if pkgPath != "cmd" && !strings.HasPrefix(pkgPath, "cmd/") && !strings.Contains(pkgPath, "/cmd/") {
+			continue
+		}
+		mainID, ok := r.globalFunctions[pkgPath]["main"]
+		if !ok {
+			continue
+		}
+		r.binaryMains[pkgPath] = mainID
+		r.binaryMains[filepath.Base(pkgPath)] = mainID
+	}
+}
+
+// resolveShellCall resolves a command invoked from a shell script to:
+//   - a function defined in a sourced script ("log_info")
+//   - the "$main" of another repository script ("./scripts/migrate.sh")
+//   - the main function of a Go binary built from cmd/* ("worker", "bin/worker", "./cmd/worker")
+func (r *CallResolver) resolveShellCall(call UnresolvedCall) string {
+	name := call.CalleeName
+
+	if !strings.Contains(name, "/") && !isShellScriptName(name) {
+		// Functions from sourced files take precedence over binaries
+		for _, importPath := range r.fileImports[call.FilePath] {
+			if file := r.resolveShellScriptPath(call.FilePath, importPath); file != "" {
+				if id, ok := r.shellFunctions[file][name]; ok {
+					return id
+				}
+			}
+		}
+		if ids := r.shellFunctionsByName[name]; len(ids) == 1 {
+			return ids[0]
+		}
+		return r.binaryMains[name]
+	}
+
+	if file := r.resolveShellScriptPath(call.FilePath, name); file != "" {
+		return r.shellFunctions[file][scriptMainFunctionName]
+	}
+
+	path := strings.TrimSuffix(normalizeShellPath(name), "/")
+	if id, ok := r.binaryMains[path]; ok {
+		return id
+	}
+	return r.binaryMains[filepath.Base(path)]
+}
+
+// resolveShellScriptPath maps a script reference to an indexed shell file.
+// Tries the path relative to the calling script, then relative to the
+// repository root, then a unique file name match.
+func (r *CallResolver) resolveShellScriptPath(fromFile, ref string) string {
+	path := normalizeShellPath(ref)
+	if path == "" {
+		return ""
+	}
+
+	for _, candidate := range []string{
+		filepath.ToSlash(filepath.Join(filepath.Dir(fromFile), path)),
+		filepath.ToSlash(filepath.Clean(path)),
+	} {
+		if _, ok := r.shellFunctions[candidate]; ok {
+			return candidate
+		}
+	}
+
+	if files := r.shellFilesByBase[filepath.Base(path)]; len(files) == 1 {
+		return files[0]
+	}
+	return ""
+}
+
+// isShellScriptName reports whether a command name refers to a script file.
+func isShellScriptName(name string) bool {
+	return detectLanguageFromPath(name) == "bash"
+}
@@ -791,3 +791,360 @@ func TestCallResolver_NoDuplicates(t *testing.T) {
 		t.Errorf("expected 1 deduplicated call, got %d", len(resolvedCalls))
 	}
 }
+
+func TestCallResolver_ResolvePHPCalls(t *testing.T) {
+	// Setup: UserService (App\Services) calls into a repository, a namespaced
+	// helper function and a trait method defined in other files.
+	files := []FileEntity{
+		{ID: "file:UserService.php", Path: "app/Services/UserService.php", Language: "php"},
+		{ID: "file:UserRepository.php", Path: "app/Repositories/UserRepository.php", Language: "php"},
+		{ID: "file:helpers.php", Path: "app/Support/helpers.php", Language: "php"},
+		{ID: "file:Loggable.php", Path: "app/Support/Loggable.php", Language: "php"},
+	}
+
+	functions := []FunctionEntity{
+		{ID: "fn:create", Name: "UserService.create", FilePath: "app/Services/UserService.php"},
+		{ID: "fn:find", Name: "UserRepository.find", FilePath: "app/Repositories/UserRepository.php"},
+		{ID: "fn:ctor", Name: "UserRepository.__construct", FilePath: "app/Repositories/UserRepository.php"},
+		{ID: "fn:save", Name: "UserRepository.save", FilePath: "app/Repositories/UserRepository.php"},
+		{ID: "fn:format", Name: "format_name", FilePath: "app/Support/helpers.php"},
+		{ID: "fn:log", Name: "Loggable.log", FilePath: "app/Support/Loggable.php"},
+	}
+
+	imports := []ImportEntity{
+		{FilePath: "app/Services/UserService.php", ImportPath: `App\Repositories\UserRepository`, Alias: "Repo"},
+		{FilePath: "app/Services/UserService.php", ImportPath: `App\Support\format_name`, Alias: "format_name"},
+	}
+
+	packageNames := map[string]string{
+		"app/Services/UserService.php":        `App\Services`,
+		"app/Repositories/UserRepository.php": `App\Repositories`,
+		"app/Support/helpers.php":             `App\Support`,
+		"app/Support/Loggable.php":            `App\Support`,
+	}
+
+	fields := []FieldEntity{
+		{StructName: "UserService", FieldName: "repo", FieldType: "UserRepository", FilePath: "app/Services/UserService.php"},
+	}
+	implements := []ImplementsEdge{
+		{TypeName: "UserService", InterfaceName: "Loggable", FilePath: "app/Services/UserService.php"},
+	}
+
+	unresolvedCalls := []UnresolvedCall{
+		{CallerID: "fn:create", CalleeName: "Repo::find", FilePath: "app/Services/UserService.php", Line: 10},
+		{CallerID: "fn:create", CalleeName: "Repo::__construct", FilePath: "app/Services/UserService.php", Line: 11},
+		{CallerID: "fn:create", CalleeName: "format_name", FilePath: "app/Services/UserService.php", Line: 12},
+		{CallerID: "fn:create", CalleeName: "this.log", FilePath: "app/Services/UserService.php", Line: 13},
+		{CallerID: "fn:create", CalleeName: "this.repo.save", FilePath: "app/Services/UserService.php", Line: 14},
+		{CallerID: "fn:create", CalleeName: "user.save", FilePath: "app/Services/UserService.php", Line: 15},
+	}
+
+	resolver := NewCallResolver()
+	resolver.BuildIndex(files, functions, imports, packageNames)
+	resolver.SetInterfaceIndex(fields, implements)
+
+	resolvedCalls := resolver.ResolveCalls(unresolvedCalls)
+
+	callees := make(map[string]int)
+	for _, c := range resolvedCalls {
+		callees[c.CalleeID] = c.CallLine
+	}
+
+	expected := map[string]int{
+		"fn:find":   10, // static call through aliased `use`
+		"fn:ctor":   11, // new Repo()
+		"fn:format": 12, // `use function` import
+		"fn:log":    13, // trait method via $this
+		"fn:save":   14, // typed property dispatch
+	}
+	for calleeID, line := range expected {
+		if got, ok := callees[calleeID]; !ok {
+			t.Errorf("expected call to %s to be resolved", calleeID)
+		} else if got != line {
+			t.Errorf("expected call to %s at line %d, got %d", calleeID, line, got)
+		}
+	}
+	if len(resolvedCalls) != len(expected) {
+		t.Errorf("expected %d resolved calls, got %d: %+v", len(expected), len(resolvedCalls), resolvedCalls)
+	}
+}
+
+func TestCallResolver_ResolvePHPSameNamedClasses(t *testing.T) {
+	// Setup: App\Models\User and App\Http\Resources\User both declare
+	// toArray; the resource extends the model by its fully qualified name,
+	// and each has a subclass in its own namespace. UserController imports
+	// both, one under an alias.
+	files := []FileEntity{
+		{ID: "file:Models/User.php", Path: "app/Models/User.php", Language: "php"},
+		{ID: "file:Models/Admin.php", Path: "app/Models/Admin.php", Language: "php"},
+		{ID: "file:Resources/User.php", Path: "app/Http/Resources/User.php", Language: "php"},
+		{ID: "file:Resources/AdminResource.php", Path: "app/Http/Resources/AdminResource.php", Language: "php"},
+		{ID: "file:UserController.php", Path: "app/Http/Controllers/UserController.php", Language: "php"},
+	}
+
+	functions := []FunctionEntity{
+		{ID: "fn:model_find", Name: "User.find", FilePath: "app/Models/User.php"},
+		{ID: "fn:model_save", Name: "User.save", FilePath: "app/Models/User.php"},
+		{ID: "fn:model_array", Name: "User.toArray", FilePath: "app/Models/User.php"},
+		{ID: "fn:promote", Name: "Admin.promote", FilePath: "app/Models/Admin.php"},
+		{ID: "fn:res_make", Name: "User.make", FilePath: "app/Http/Resources/User.php"},
+		{ID: "fn:res_save", Name: "User.save", FilePath: "app/Http/Resources/User.php"},
+		{ID: "fn:res_array", Name: "User.toArray", FilePath: "app/Http/Resources/User.php"},
+		{ID: "fn:render", Name: "AdminResource.render", FilePath: "app/Http/Resources/AdminResource.php"},
+		{ID: "fn:show", Name: "UserController.show", FilePath: "app/Http/Controllers/UserController.php"},
+	}
+
+	imports := []ImportEntity{
+		{FilePath: "app/Http/Controllers/UserController.php", ImportPath: `App\Models\User`, Alias: "User"},
+		{FilePath: "app/Http/Controllers/UserController.php", ImportPath: `App\Http\Resources\User`, Alias: "UserResource"},
+	}
+
+	packageNames := map[string]string{
+		"app/Models/User.php":                     `App\Models`,
+		"app/Models/Admin.php":                    `App\Models`,
+		"app/Http/Resources/User.php":             `App\Http\Resources`,
+		"app/Http/Resources/AdminResource.php":    `App\Http\Resources`,
+		"app/Http/Controllers/UserController.php": `App\Http\Controllers`,
+	}
+
+	fields := []FieldEntity{
+		{StructName: "UserController", FieldName: "resource", FieldType: "UserResource", FilePath: "app/Http/Controllers/UserController.php"},
+	}
+	implements := []ImplementsEdge{
+		{TypeName: "Admin", InterfaceName: "User", FilePath: "app/Models/Admin.php"},
+		{TypeName: "User", InterfaceName: "User", FilePath: "app/Http/Resources/User.php"}, // extends \App\Models\User
+		{TypeName: "AdminResource", InterfaceName: "User", FilePath: "app/Http/Resources/AdminResource.php"},
+	}
+
+	unresolvedCalls := []UnresolvedCall{
+		{CallerID: "fn:promote", CalleeName: "this.toArray", FilePath: "app/Models/Admin.php", Line: 10},
+		{CallerID: "fn:res_save", CalleeName: `App\Models\User::save`, FilePath: "app/Http/Resources/User.php", Line: 20},
+		{CallerID: "fn:render", CalleeName: "this.toArray", FilePath: "app/Http/Resources/AdminResource.php", Line: 30},
+		{CallerID: "fn:render", CalleeName: "this.save", FilePath: "app/Http/Resources/AdminResource.php", Line: 31},
+		{CallerID: "fn:show", CalleeName: "User::find", FilePath: "app/Http/Controllers/UserController.php", Line: 40},
+		{CallerID: "fn:show", CalleeName: "UserResource::make", FilePath: "app/Http/Controllers/UserController.php", Line: 41},
+		{CallerID: "fn:show", CalleeName: "this.resource.toArray", FilePath: "app/Http/Controllers/UserController.php", Line: 42},
+	}
+
+	resolver := NewCallResolver()
+	resolver.BuildIndex(files, functions, imports, packageNames)
+	resolver.SetInterfaceIndex(fields, implements)
+
+	got := make(map[string]bool)
+	for _, c := range resolver.ResolveCalls(unresolvedCalls) {
+		got[c.CallerID+"->"+c.CalleeID] = true
+	}
+
+	expected := map[string]bool{
+		"fn:promote->fn:model_array": true, // inherited from App\Models\User
+		"fn:res_save->fn:model_save": true, // parent:: by fully qualified name
+		"fn:render->fn:res_array":    true, // inherited from App\Http\Resources\User
+		"fn:render->fn:res_save":     true,
+		"fn:show->fn:model_find":     true, // `use App\Models\User`
+		"fn:show->fn:res_make":       true, // `use App\Http\Resources\User as UserResource`
+		"fn:show->fn:res_array":      true, // typed property of the aliased class
+	}
+	for edge := range expected {
+		if !got[edge] {
+			t.Errorf("expected call %s to be resolved", edge)
+		}
+	}
+	if len(got) != len(expected) {
+		t.Errorf("expected %d resolved calls, got %v", len(expected), got)
+	}
+}
+
+func TestCallResolver_ResolvePythonCalls(t *testing.T) {
+	// Setup: Service(Base, Mixin) and SqlRepository(Repository, Mixin) call
+	// inherited methods through self and super(), and Service.run dispatches
+	// on a parameter annotated with the Repository ABC.
+	files := []FileEntity{
+		{ID: "file:dispatch.py", Path: "app/dispatch.py", Language: "python"},
+	}
+
+	functions := []FunctionEntity{
+		{ID: "fn:repo.save", Name: "Repository.save", FilePath: "app/dispatch.py"},
+		{ID: "fn:repo.log", Name: "Repository.log", FilePath: "app/dispatch.py"},
+		{ID: "fn:base.setup", Name: "Base.setup", FilePath: "app/dispatch.py"},
+		{ID: "fn:base.greet", Name: "Base.greet", FilePath: "app/dispatch.py"},
+		{ID: "fn:mixin.greet", Name: "Mixin.greet", FilePath: "app/dispatch.py"},
+		{ID: "fn:sql.save", Name: "SqlRepository.save", FilePath: "app/dispatch.py"},
+		{ID: "fn:mem.save", Name: "MemoryRepository.save", FilePath: "app/dispatch.py"},
+		{ID: "fn:run", Name: "Service.run", FilePath: "app/dispatch.py",
+			Signature: `def run(self, repo: Repository, name: str = "x")`},
+	}
+
+	implements := []ImplementsEdge{
+		{TypeName: "Repository", InterfaceName: "ABC", FilePath: "app/dispatch.py"},
+		{TypeName: "SqlRepository", InterfaceName: "Repository", FilePath: "app/dispatch.py"},
+		{TypeName: "SqlRepository", InterfaceName: "Mixin", FilePath: "app/dispatch.py"},
+		{TypeName: "MemoryRepository", InterfaceName: "Repository", FilePath: "app/dispatch.py"},
+		{TypeName: "Service", InterfaceName: "Base", FilePath: "app/dispatch.py"},
+		{TypeName: "Service", InterfaceName: "Mixin", FilePath: "app/dispatch.py"},
+	}
+
+	unresolvedCalls := []UnresolvedCall{
+		{CallerID: "fn:sql.save", CalleeName: "self.log", FilePath: "app/dispatch.py", Line: 10},
+		{CallerID: "fn:sql.save", CalleeName: "super().greet", FilePath: "app/dispatch.py", Line: 11},
+		{CallerID: "fn:run", CalleeName: "self.setup", FilePath: "app/dispatch.py", Line: 20},
+		{CallerID: "fn:run", CalleeName: "super().greet", FilePath: "app/dispatch.py", Line: 21},
+		{CallerID: "fn:run", CalleeName: "repo.save", FilePath: "app/dispatch.py", Line: 22},
+		{CallerID: "fn:run", CalleeName: "name.upper", FilePath: "app/dispatch.py", Line: 23},
+	}
+
+	resolver := NewCallResolver()
+	resolver.BuildIndex(files, functions, nil, nil)
+	resolver.SetInterfaceIndex(nil, implements)
+
+	resolvedCalls := resolver.ResolveCalls(unresolvedCalls)
+
+	got := make(map[string]bool)
+	for _, c := range resolvedCalls {
+		got[c.CallerID+" -> "+c.CalleeID] = true
+	}
+
+	expected := []string{
+		"fn:sql.save -> fn:repo.log",    // inherited through self
+		"fn:sql.save -> fn:mixin.greet", // super() follows the MRO past Repository
+		"fn:run -> fn:base.setup",
+		"fn:run -> fn:base.greet",
+		"fn:run -> fn:sql.save", // ABC parameter dispatches to subclasses
+		"fn:run -> fn:mem.save",
+	}
+	for _, edge := range expected {
+		if !got[edge] {
+			t.Errorf("expected call %s to be resolved", edge)
+		}
+	}
+	if len(resolvedCalls) != len(expected) {
+		t.Errorf("expected %d resolved calls, got %d: %+v", len(expected), len(resolvedCalls), resolvedCalls)
+	}
+}
+
+func TestCallResolver_ResolveTypeScriptCalls(t *testing.T) {
+	// Setup: UserService holds a Repo property implemented by SqlRepo, and
+	// SqlRepo calls a method inherited from the abstract BaseRepo.
+	files := []FileEntity{
+		{ID: "file:repo.ts", Path: "src/repo.ts", Language: "typescript"},
+		{ID: "file:service.ts", Path: "src/service.ts", Language: "typescript"},
+	}
+
+	functions := []FunctionEntity{
+		{ID: "fn:base.log", Name: "BaseRepo.log", FilePath: "src/repo.ts"},
+		{ID: "fn:sql.save", Name: "SqlRepo.save", FilePath: "src/repo.ts"},
+		{ID: "fn:mem.save", Name: "MemoryRepo.save", FilePath: "src/repo.ts"},
+		{ID: "fn:create", Name: "UserService.create", FilePath: "src/service.ts"},
+	}
+
+	fields := []FieldEntity{
+		{StructName: "UserService", FieldName: "repo", FieldType: "Repo", FilePath: "src/service.ts"},
+		{StructName: "UserService", FieldName: "cache", FieldType: "MemoryRepo", FilePath: "src/service.ts"},
+	}
+	implements := []ImplementsEdge{
+		{TypeName: "BaseRepo", InterfaceName: "Repo", FilePath: "src/repo.ts"},
+		{TypeName: "SqlRepo", InterfaceName: "BaseRepo", FilePath: "src/repo.ts"},
+		{TypeName: "SqlRepo", InterfaceName: "Repo", FilePath: "src/repo.ts"},
+		{TypeName: "MemoryRepo", InterfaceName: "BaseRepo", FilePath: "src/repo.ts"},
+	}
+
+	unresolvedCalls := []UnresolvedCall{
+		{CallerID: "fn:sql.save", CalleeName: "this.log", FilePath: "src/repo.ts", Line: 10},
+		{CallerID: "fn:mem.save", CalleeName: "super.log", FilePath: "src/repo.ts", Line: 20},
+		{CallerID: "fn:create", CalleeName: "this.repo.save", FilePath: "src/service.ts", Line: 30},
+		{CallerID: "fn:create", CalleeName: "this.cache.save", FilePath: "src/service.ts", Line: 31},
+		{CallerID: "fn:create", CalleeName: "this.unknown.save", FilePath: "src/service.ts", Line: 32},
+	}
+
+	resolver := NewCallResolver()
+	resolver.BuildIndex(files, functions, nil, nil)
+	resolver.SetInterfaceIndex(fields, implements)
+
+	resolvedCalls := resolver.ResolveCalls(unresolvedCalls)
+
+	got := make(map[string]int)
+	for _, c := range resolvedCalls {
+		got[c.CallerID+" -> "+c.CalleeID] = c.CallLine
+	}
+
+	expected := map[string]int{
+		"fn:sql.save -> fn:base.log": 10, // inherited through this
+		"fn:mem.save -> fn:base.log": 20, // super call
+		"fn:create -> fn:sql.save":   30, // interface-typed property
+		"fn:create -> fn:mem.save":   31, // class-typed property
+	}
+	for edge, line := range expected {
+		if gotLine, ok := got[edge]; !ok {
+			t.Errorf("expected call %s to be resolved", edge)
+		} else if gotLine != line {
+			t.Errorf("expected call %s at line %d, got %d", edge, line, gotLine)
+		}
+	}
+	if len(resolvedCalls) != len(expected) {
+		t.Errorf("expected %d resolved calls, got %d: %+v", len(expected), len(resolvedCalls), resolvedCalls)
+	}
+}
+
+func TestCallResolver_ResolveShellCalls(t *testing.T) {
+	// Setup: a CI script sources a library, invokes a sibling script and
+	// runs a Go binary built from cmd/worker.
+	files := []FileEntity{
+		{ID: "file:ci.sh", Path: "scripts/ci.sh", Language: "bash"},
+		{ID: "file:common.sh", Path: "scripts/lib/common.sh", Language: "bash"},
+		{ID: "file:migrate.sh", Path: "scripts/migrate.sh", Language: "bash"},
+		{ID: "file:main.go", Path: "cmd/worker/main.go", Language: "go"},
+	}
+
+	functions := []FunctionEntity{
+		{ID: "fn:ci_main", Name: scriptMainFunctionName, FilePath: "scripts/ci.sh"},
+		{ID: "fn:log_info", Name: "log_info", FilePath: "scripts/lib/common.sh"},
+		{ID: "fn:migrate_main", Name: scriptMainFunctionName, FilePath: "scripts/migrate.sh"},
+		{ID: "fn:worker_main", Name: "main", FilePath: "cmd/worker/main.go"},
+	}
+
+	imports := []ImportEntity{
+		{FilePath: "scripts/ci.sh", ImportPath: "$SCRIPT_DIR/lib/common.sh"},
+	}
+
+	packageNames := map[string]string{
+		"cmd/worker/main.go": "main",
+	}
+
+	unresolvedCalls := []UnresolvedCall{
+		{CallerID: "fn:ci_main", CalleeName: "log_info", FilePath: "scripts/ci.sh", Line: 3},
+		{CallerID: "fn:ci_main", CalleeName: "./migrate.sh", FilePath: "scripts/ci.sh", Line: 4},
+		{CallerID: "fn:ci_main", CalleeName: "./bin/worker", FilePath: "scripts/ci.sh", Line: 5},
+		{CallerID: "fn:ci_main", CalleeName: "./cmd/worker", FilePath: "scripts/ci.sh", Line: 6},
+		{CallerID: "fn:ci_main", CalleeName: "kubectl", FilePath: "scripts/ci.sh", Line: 7},
+	}
+
+	resolver := NewCallResolver()
+	resolver.BuildIndex(files, functions, imports, packageNames)
+
+	resolvedCalls := resolver.ResolveCalls(unresolvedCalls)
+
+	callees := make(map[string]int)
+	for _, c := range resolvedCalls {
+		callees[c.CalleeID] = c.CallLine
+	}
+
+	expected := map[string]int{
+		"fn:log_info":     3, // function from sourced library
+		"fn:migrate_main": 4, // sibling script relative to caller
+		"fn:worker_main":  5, // binary built from cmd/worker (first edge wins)
+	}
+	for calleeID, line := range expected {
+		if got, ok := callees[calleeID]; !ok {
+			t.Errorf("expected call to %s to be resolved", calleeID)
+		} else if got != line {
+			t.Errorf("expected call to %s at line %d, got %d", calleeID, line, got)
+		}
+	}
+	if len(resolvedCalls) != len(expected) {
+		t.Errorf("expected %d resolved calls, got %d: %+v", len(expected), len(resolvedCalls), resolvedCalls)
+	}
+
+	// go run ./cmd/worker resolves through the package path
+	if id := resolver.resolveShellCall(unresolvedCalls[3]); id != "fn:worker_main" {
+		t.Errorf("expected ./cmd/worker to resolve to fn:worker_main, got %q", id)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"strings"
+)
+
+// resolveTSCall resolves a TypeScript call using the callee naming produced by
+// extractTSCalls: "this.method" and "super.method" resolve to the first
+// definition found along the parent classes of the caller's class (methods of
+// the class itself are resolved at parse time).
+//
+// Calls on properties ("this.repo.save") are left to resolveInterfaceCall.
+func (r *CallResolver) resolveTSCall(call UnresolvedCall) string {
+	className := r.callerClass(call)
+	if className == "" {
+		return ""
+	}
+	method, ok := strings.CutPrefix(call.CalleeName, "this.")
+	if !ok {
+		method, ok = strings.CutPrefix(call.CalleeName, "super.")
+	}
+	if !ok || strings.Contains(method, ".") {
+		return ""
+	}
+	return r.resolveTSInheritedMethod(className, method, make(map[string]bool))
+}
+
+// resolveTSInheritedMethod walks the parents of a class depth-first looking
+// for a method definition.
+func (r *CallResolver) resolveTSInheritedMethod(className, method string, visited map[string]bool) string {
+	if className == "" || visited[className] {
+		return ""
+	}
+	visited[className] = true
+
+	for _, parent := range r.tsParents[className] {
+		if id, ok := r.qualifiedFunctions[parent+"."+method]; ok {
+			return id
+		}
+		if id := r.resolveTSInheritedMethod(parent, method, visited); id != "" {
+			return id
+		}
+	}
+	return ""
+}
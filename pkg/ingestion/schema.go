@@ -29,10 +29,34 @@ package ingestion
 //   - cie_type: Type metadata (lightweight)
 //   - cie_type_code: Type code text (lazy loaded)
 //   - cie_type_embedding: Type embeddings (for HNSW only)
+//   - cie_variable: Package-level variables and constants
 //   - cie_defines: Edge from file to function
 //   - cie_defines_type: Edge from file to type
 //   - cie_calls: Edge from caller function to callee function
+//   - cie_func_ref: Edge from function to a function it uses as a value (callbacks, handlers)
+//   - cie_type_ref: Edge from function or type to a type it references
+//   - cie_type_param: Type parameters of generic functions and types
+//   - cie_instantiation: Edge from function or type to a generic function or type it instantiates
+//   - cie_concurrency: Goroutine spawns, channel operations and lock acquisitions of Go functions
+//   - cie_error_site: Error construction, wrapping and raising sites of functions
+//   - cie_test: Tests, subtests and test suites, linked to the function running them
+//   - cie_function_metrics: Complexity and size metrics of functions, computed from the syntax tree
+//   - cie_coverage: Line coverage of functions, imported from coverage profiles
+//   - cie_function_churn: Commits, authors and lines changed per function, from the git history pass
+//   - cie_change_coupling: Pairs of functions changed in the same commits, from the git history pass
 //   - cie_import: Import statements for cross-package call resolution
+//   - cie_sql_table, cie_sql_column, cie_sql_index, cie_sql_foreign_key: SQL schema from .sql files
+//   - cie_table_access: Edge from function to the SQL table it reads or writes
+//   - cie_graphql_field: Fields declared in GraphQL SDL types
+//   - cie_graphql_resolver: Edge from GraphQL field to its resolver function
+//   - cie_topology_node: Deployable units from Dockerfiles, compose, Kubernetes and Terraform
+//   - cie_topology_env: Environment variables passed to a topology node
+//   - cie_topology_edge: Edges between topology nodes and to the main functions they run
+//   - cie_doc_chunk: Heading-delimited sections of Markdown files
+//   - cie_doc_chunk_embedding: Doc chunk embeddings (for HNSW only)
+//   - cie_doc_link: Edge from doc chunk to the function, type or file it mentions
+//   - cie_package: Directories of source files (Go/PHP packages, Python and JS/TS modules)
+//   - cie_package_import: Package -> package edges aggregated from cie_import
 //
 // All IDs are deterministic and stable across re-runs for idempotency.
 
@@ -40,6 +64,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"strings"
 )
 
 // FileEntity represents a source file in the repository.
@@ -56,16 +81,17 @@ type FileEntity struct {
 // (cie_function_code, cie_function_embedding) for query performance.
 // The struct keeps all fields for use in the ingestion pipeline.
 type FunctionEntity struct {
-	ID        string    // Deterministic: hash(file_path + name + range) - signature excluded for stability
-	Name      string    // Function name
-	Signature string    // Full signature if available, else empty (metadata only, not used in ID)
-	FilePath  string    // Path to containing file
-	CodeText  string    // Raw code snippet (stored in cie_function_code)
-	Embedding []float32 // Embedding vector (stored in cie_function_embedding)
-	StartLine int       // Start line (1-indexed)
-	EndLine   int       // End line (1-indexed)
-	StartCol  int       // Start column (1-indexed)
-	EndCol    int       // End column (1-indexed)
+	ID         string    // Deterministic: hash(file_path + name + range) - signature excluded for stability
+	Name       string    // Function name
+	Signature  string    // Full signature if available, else empty (metadata only, not used in ID)
+	FilePath   string    // Path to containing file
+	DocComment string    // Leading doc comment, docstring or JSDoc with comment markers stripped
+	CodeText   string    // Raw code snippet (stored in cie_function_code)
+	Embedding  []float32 // Embedding vector (stored in cie_function_embedding)
+	StartLine  int       // Start line (1-indexed)
+	EndLine    int       // End line (1-indexed)
+	StartCol   int       // Start column (1-indexed)
+	EndCol     int       // End column (1-indexed)
 }
 
 // DefinesEdge represents a "file defines function" relationship.
@@ -84,16 +110,32 @@ type DefinesEdge struct {
 // Note: In the database, CodeText and Embedding are stored in separate tables
 // (cie_type_code, cie_type_embedding) for query performance.
 type TypeEntity struct {
-	ID        string    // Deterministic: hash(file_path + name + range)
-	Name      string    // Type name (e.g., "UserService", "Handler")
-	Kind      string    // "struct", "interface", "class", "type_alias"
-	FilePath  string    // Path to containing file
-	CodeText  string    // Raw code snippet (stored in cie_type_code)
-	Embedding []float32 // Embedding vector (stored in cie_type_embedding)
-	StartLine int       // Start line (1-indexed)
-	EndLine   int       // End line (1-indexed)
-	StartCol  int       // Start column (1-indexed)
-	EndCol    int       // End column (1-indexed)
+	ID         string    // Deterministic: hash(file_path + name + range)
+	Name       string    // Type name (e.g., "UserService", "Handler")
+	Kind       string    // "struct", "interface", "class", "type_alias"
+	FilePath   string    // Path to containing file
+	DocComment string    // Leading doc comment, docstring or JSDoc with comment markers stripped
+	CodeText   string    // Raw code snippet (stored in cie_type_code)
+	Embedding  []float32 // Embedding vector (stored in cie_type_embedding)
+	StartLine  int       // Start line (1-indexed)
+	EndLine    int       // End line (1-indexed)
+	StartCol   int       // Start column (1-indexed)
+	EndCol     int       // End column (1-indexed)
+}
+
+// VariableEntity represents a package-level variable or constant: Go `var` and
+// `const` declarations, Python module-level assignments and exported JS/TS
+// `const`/`let`/`var` declarations.
+type VariableEntity struct {
+	ID        string // Deterministic: hash(file_path + name + line)
+	Name      string // Variable name (e.g., "DefaultTimeout")
+	Kind      string // "var", "const", "let"
+	Type      string // Declared type ("time.Duration", "dict[str, int]"), "" if inferred
+	Value     string // Initializer snippet, whitespace-collapsed and truncated
+	FilePath  string // Path to containing file
+	CodeText  string // Source of the declaration
+	StartLine int    // Start line (1-indexed)
+	EndLine   int    // End line (1-indexed)
 }
 
 // DefinesTypeEdge represents a "file defines type" relationship.
@@ -110,6 +152,160 @@ type CallsEdge struct {
 	CallLine int    // Line number where the call occurs in the caller (0 = unknown)
 }
 
+// FuncRefEdge represents a "function references function" relationship: the
+// function value is passed as an argument (r.GET("/users", h.ListUsers)),
+// assigned or returned instead of being called.
+type FuncRefEdge struct {
+	ID       string // Deterministic: hash(from_id + to_id + kind + line)
+	FromID   string // Reference to FunctionEntity.ID of the referencing function
+	ToID     string // Reference to FunctionEntity.ID of the referenced function
+	Kind     string // "argument", "assignment", "return"
+	FilePath string // File containing the reference
+	Line     int    // Line number of the reference
+}
+
+// TypeRefEdge represents a "function or type references type" relationship:
+// a parameter, return value, struct field, embedding, composite literal,
+// conversion or type assertion naming the type.
+type TypeRefEdge struct {
+	ID       string // Deterministic: hash(from_id + type_id + kind + line)
+	FromID   string // Reference to FunctionEntity.ID or TypeEntity.ID
+	TypeID   string // Reference to TypeEntity.ID of the referenced type
+	Kind     string // "param", "return", "receiver", "field", "embed", "literal", "conversion", "assertion", "use"
+	FilePath string // File containing the reference
+	Line     int    // Line number of the reference
+}
+
+// TypeParamEntity represents a type parameter of a generic function or type,
+// e.g. K in `func Keys[K comparable, V any](m map[K]V) []K`.
+type TypeParamEntity struct {
+	ID         string // Deterministic: hash(owner_id + name)
+	OwnerID    string // Reference to FunctionEntity.ID or TypeEntity.ID
+	Name       string // Parameter name (e.g., "K")
+	Constraint string // Constraint as written (e.g., "comparable", "~int | ~float64")
+	Position   int    // 0-based position in the type parameter list
+	FilePath   string // File declaring the owner
+}
+
+// InstantiationEdge represents a "function or type instantiates generic
+// function or type" relationship: Map[int, string](...) or List[int].
+type InstantiationEdge struct {
+	ID         string // Deterministic: hash(from_id + target_id + type_args + line)
+	FromID     string // Reference to FunctionEntity.ID or TypeEntity.ID
+	TargetID   string // Reference to FunctionEntity.ID or TypeEntity.ID of the generic
+	TargetKind string // "function" or "type"
+	TypeArgs   string // Type arguments as written (e.g., "int, string")
+	FilePath   string // File containing the instantiation
+	Line       int    // Line number of the instantiation
+}
+
+// ConcurrencyEdge represents a concurrency operation of a Go function: a
+// goroutine spawn (go s.worker(ctx)), a channel send or receive (s.jobs <- j,
+// <-done) or a mutex acquisition or release (s.mu.Lock()).
+//
+// Targets rooted at the receiver or a parameter are named after its type
+// ("Server.jobs", "Cache.mu") so the same channel or mutex matches across
+// functions; other targets are kept as written.
+type ConcurrencyEdge struct {
+	ID         string // Deterministic: hash(function_id + kind + target + line)
+	FunctionID string // Reference to FunctionEntity.ID of the function performing the operation
+	Kind       string // "spawn", "send", "receive", "lock", "rlock", "unlock", "runlock"
+	Target     string // Spawned callee, channel or mutex (e.g., "s.worker", "Server.jobs", "Server.mu")
+	TargetID   string // For spawns of a function declared in the same file: its FunctionEntity.ID
+	FilePath   string // File containing the operation
+	Line       int    // Line number of the operation
+}
+
+// ErrorSiteEntity represents a place where an error is created, wrapped or
+// raised:
+//   - Go: errors.New("..."), fmt.Errorf("...") and fmt.Errorf("...: %w", err),
+//     package-level sentinels (var ErrNotFound = errors.New("not found")),
+//     returns of a sentinel (return ErrNotFound), construction of custom error
+//     types (&NotFoundError{...}) and the Error() methods declaring them
+//   - Python: raise ValueError("...")
+//   - JavaScript/TypeScript: throw new Error("...")
+//
+// Sentinel declarations have no function.
+type ErrorSiteEntity struct {
+	ID         string // Deterministic: hash(file_path + function_id + kind + name + line)
+	FunctionID string // Reference to FunctionEntity.ID of the enclosing function, empty for sentinels
+	Kind       string // "new", "errorf", "wrap", "sentinel", "return", "custom", "type", "raise", "throw"
+	Name       string // Sentinel, error type or exception class (e.g., "ErrNotFound", "ValueError"), or the sentinel wrapped by %w
+	Message    string // Message or format string as written, without quotes
+	FilePath   string // File containing the site
+	Line       int    // Line number of the site
+}
+
+// TestEntity represents a test, subtest or test suite:
+//   - Go: TestX, BenchmarkX, FuzzX and ExampleX functions of _test.go files,
+//     and t.Run subtests (named "TestX/sub_name" as go test reports them)
+//   - Python: pytest test_* functions and Test* classes with their test_* methods
+//   - JavaScript/TypeScript: Jest/Vitest describe blocks and it/test cases
+//
+// Tests are linked to the production code they exercise through the call
+// graph of their function.
+type TestEntity struct {
+	ID         string // Deterministic: hash(file_path + name + line)
+	FunctionID string // Reference to FunctionEntity.ID of the test body, empty for pytest classes and unindexed callbacks
+	Name       string // Test name (e.g., "TestParse/empty_input", "TestUser.test_create", "returns 404")
+	Kind       string // "test", "benchmark", "fuzz", "example", "subtest", "suite"
+	Suite      string // Enclosing test or suite (e.g., "TestParse", "TestUser", "UserService > create"), empty at top level
+	Framework  string // "go", "pytest", "jest", "vitest"
+	FilePath   string // File containing the test
+	Line       int    // Line number of the test
+}
+
+// FunctionMetricsEntity holds the complexity and size metrics of a function,
+// computed from its tree-sitter syntax tree while parsing.
+type FunctionMetricsEntity struct {
+	FunctionID   string  // Reference to FunctionEntity.ID
+	FilePath     string  // File containing the function
+	Complexity   int     // Cyclomatic complexity: 1 + decision points (if, loops, cases, catch, &&, ||)
+	MaxNesting   int     // Deepest nesting of control structures
+	Params       int     // Declared parameters, without receiver or self/cls
+	LOC          int     // Non-blank lines
+	CommentLines int     // Non-blank lines holding a comment or docstring
+	CommentRatio float64 // CommentLines / LOC
+}
+
+// CoverageEntity represents the line coverage of a function, mapped from a
+// coverage profile (go test -coverprofile, coverage.py XML, lcov) onto the
+// function's line range. Statements are counted as the profile reports them:
+// Go counts statements, coverage.py and lcov count lines.
+type CoverageEntity struct {
+	FunctionID string  // Reference to FunctionEntity.ID
+	FilePath   string  // File containing the function
+	Covered    int     // Statements executed at least once
+	Total      int     // Statements instrumented in the function
+	Percent    float64 // Covered / Total, in percent
+	Uncovered  string  // Uncovered line ranges (e.g., "12-14, 20")
+	Source     string  // Profile the coverage was imported from
+	ImportedAt int64   // Unix time of the import
+}
+
+// FunctionChurnEntity holds the change history of a function over the
+// commits walked by the git history pass (cie history).
+type FunctionChurnEntity struct {
+	FunctionID   string // Reference to FunctionEntity.ID
+	FilePath     string // File containing the function
+	Commits      int    // Commits changing the function
+	Authors      int    // Distinct authors of those commits
+	LinesChanged int    // Lines of the function touched, summed over commits
+	FirstChanged int64  // Unix time of the oldest commit walked changing the function
+	LastChanged  int64  // Unix time of the newest commit changing the function
+}
+
+// ChangeCouplingEntity records that two functions changed in the same
+// commits. Each pair is stored in both directions, since the degree depends
+// on the commits of FunctionID.
+type ChangeCouplingEntity struct {
+	FunctionID string  // Reference to FunctionEntity.ID
+	CoupledID  string  // Function changed together with FunctionID
+	FilePath   string  // File containing FunctionID
+	CoChanges  int     // Commits changing both functions
+	Degree     float64 // CoChanges / commits changing FunctionID
+}
+
 // ImportEntity represents an import statement in a source file.
 type ImportEntity struct {
 	ID         string // Deterministic: hash(file_path + import_path)
@@ -128,6 +324,40 @@ type UnresolvedCall struct {
 	Line       int    // Line number of the call
 }
 
+// UnresolvedFuncRef represents a function value referenced in a file,
+// collected during parsing and resolved by ResolveFuncRefs once all functions
+// are known.
+type UnresolvedFuncRef struct {
+	FromID       string // Reference to FunctionEntity.ID
+	Name         string // Name as written: "cleanup", "handlers.List" or "h.ListUsers"
+	ReceiverType string // Type of the variable a method value is taken from (h: *Handler → "Handler"), "" if unknown
+	ReceiverInit string // Function whose result initializes that variable (h := handlers.New() → "handlers.New")
+	Kind         string // See FuncRefEdge.Kind
+	FilePath     string // File containing the reference (for import resolution)
+	Line         int    // Line number of the reference
+}
+
+// UnresolvedTypeRef represents a type name referenced in a file, collected
+// during parsing and resolved by ResolveTypeRefs once all types are known.
+type UnresolvedTypeRef struct {
+	FromID   string // Reference to FunctionEntity.ID or TypeEntity.ID
+	TypeName string // Name as written: "QueryResult" or "tools.QueryResult"
+	Kind     string // See TypeRefEdge.Kind
+	FilePath string // File containing the reference (for import resolution)
+	Line     int    // Line number of the reference
+}
+
+// UnresolvedInstantiation represents an explicit instantiation of a generic
+// function or type, collected during parsing and resolved by
+// ResolveInstantiations once all functions and types are known.
+type UnresolvedInstantiation struct {
+	FromID   string // Reference to FunctionEntity.ID or TypeEntity.ID
+	Name     string // Generic name as written: "Map" or "slices.Map"
+	TypeArgs string // Type arguments as written (e.g., "int, string")
+	FilePath string // File containing the instantiation (for import resolution)
+	Line     int    // Line number of the instantiation
+}
+
 // PackageInfo represents a Go package with its files.
 type PackageInfo struct {
 	PackagePath string   // Directory path (e.g., "internal/http/handlers")
@@ -155,6 +385,232 @@ type ImplementsEdge struct {
 	FilePath      string // File containing the concrete type
 }
 
+// SQLTableEntity represents a table declared with CREATE TABLE in a .sql file
+// (migration or schema dump). The same table may be declared in several files.
+type SQLTableEntity struct {
+	ID        string // Deterministic: hash(file_path + name)
+	Name      string // Normalized table name: lowercase, without schema or quotes (e.g., "orders")
+	FilePath  string
+	CodeText  string // CREATE TABLE statement
+	StartLine int
+	EndLine   int
+}
+
+// SQLColumnEntity represents a table column from CREATE TABLE or ALTER TABLE ... ADD COLUMN.
+type SQLColumnEntity struct {
+	TableName  string // Normalized table name
+	Name       string // Normalized column name
+	DataType   string // Declared type as written (e.g., "VARCHAR(255)", "bigint")
+	Nullable   bool
+	PrimaryKey bool
+	FilePath   string
+	Line       int
+}
+
+// SQLIndexEntity represents an index from CREATE INDEX or an inline UNIQUE/KEY constraint.
+type SQLIndexEntity struct {
+	Name      string // Index name; empty for unnamed indexes
+	TableName string
+	Columns   []string
+	Unique    bool
+	FilePath  string
+	Line      int
+}
+
+// SQLForeignKeyEntity represents a foreign key from a REFERENCES clause or FOREIGN KEY constraint.
+type SQLForeignKeyEntity struct {
+	TableName  string   // Referencing table
+	Columns    []string // Referencing columns
+	RefTable   string   // Referenced table
+	RefColumns []string // Referenced columns (empty = primary key)
+	FilePath   string
+	Line       int
+}
+
+// SQLSchema groups the schema objects declared in .sql files.
+type SQLSchema struct {
+	Tables      []SQLTableEntity
+	Columns     []SQLColumnEntity
+	Indexes     []SQLIndexEntity
+	ForeignKeys []SQLForeignKeyEntity
+}
+
+// Merge appends the objects of another schema.
+func (s *SQLSchema) Merge(other SQLSchema) {
+	s.Tables = append(s.Tables, other.Tables...)
+	s.Columns = append(s.Columns, other.Columns...)
+	s.Indexes = append(s.Indexes, other.Indexes...)
+	s.ForeignKeys = append(s.ForeignKeys, other.ForeignKeys...)
+}
+
+// Len returns the total number of schema objects.
+func (s *SQLSchema) Len() int {
+	return len(s.Tables) + len(s.Columns) + len(s.Indexes) + len(s.ForeignKeys)
+}
+
+// TableAccessEdge represents that a function reads or writes a SQL table.
+// Extracted from SQL string literals and ORM table calls in the function's code.
+type TableAccessEdge struct {
+	FunctionID string // Reference to FunctionEntity.ID
+	TableName  string // Normalized table name
+	Access     string // "read" or "write"
+	FilePath   string // File containing the function
+	Line       int    // Line of the SQL literal or ORM call
+}
+
+// GraphQLFieldEntity represents a field declared on a GraphQL SDL type.
+// Fields of the root types (Query, Mutation, Subscription) are operations.
+type GraphQLFieldEntity struct {
+	ID        string // Deterministic: hash(file_path + type_name + name)
+	TypeName  string // Declaring type (e.g., "Query", "Order")
+	Name      string // Field name (e.g., "orders")
+	FieldType string // Return type as written (e.g., "[Order!]!")
+	Arguments string // Argument list as written, without parentheses (e.g., "id: ID!")
+	Operation string // "query", "mutation", "subscription", or "" for object fields
+	FilePath  string
+	Line      int
+}
+
+// GraphQLResolverEdge links a GraphQL field to the function that resolves it.
+// Stored by type and field name so resolvers survive re-indexing of the SDL file.
+type GraphQLResolverEdge struct {
+	TypeName     string // GraphQL type (e.g., "Query")
+	FieldName    string // GraphQL field (e.g., "orders")
+	FunctionID   string // Reference to FunctionEntity.ID
+	FunctionName string // Set instead of FunctionID for resolvers referenced by name; resolved after parsing
+	Framework    string // "gqlgen" or "apollo"
+	FilePath     string // File containing the resolver
+	Line         int    // Line of the resolver binding
+}
+
+// Topology node kinds. Kubernetes workloads use "k8s_" + the lowercase
+// resource kind (k8s_deployment, k8s_statefulset, k8s_cronjob, ...).
+const (
+	TopologyKindDockerfile = "dockerfile"
+	TopologyKindCompose    = "compose_service"
+	TopologyKindK8sService = "k8s_service"
+	TopologyKindTerraform  = "terraform_resource"
+)
+
+// Topology edge kinds.
+const (
+	TopologyEdgeRuns      = "runs"       // node -> main function of the Go binary it starts
+	TopologyEdgeBuilds    = "builds"     // compose service -> Dockerfile from its build context
+	TopologyEdgeImage     = "image"      // node -> Dockerfile building the image it runs
+	TopologyEdgeExposes   = "exposes"    // Kubernetes Service -> workload matched by selector
+	TopologyEdgeDependsOn = "depends_on" // compose service -> compose service
+)
+
+// TopologyNodeEntity represents a deployable unit declared in infrastructure-as-code:
+// a Dockerfile, a docker-compose service, a Kubernetes workload container or Service,
+// or a Terraform resource.
+type TopologyNodeEntity struct {
+	ID       string // Deterministic: hash(file_path + kind + name)
+	Kind     string // See TopologyKind*
+	Name     string // Service/resource name (e.g., "worker", "aws_ecs_service.worker")
+	Image    string // Image run by the node; for Dockerfiles, the final base image
+	Command  string // Entrypoint and arguments as written
+	Binary   string // Go binary started by the command: a package path ("cmd/worker") or binary name ("worker")
+	Ports    string // Comma-separated ports (container ports, "published:target" or Service "port:targetPort")
+	Labels   string // Comma-separated key=value pod labels; for Kubernetes Services, the selector
+	FilePath string
+	Line     int
+}
+
+// TopologyEnvEntity represents an environment variable passed to a topology node.
+type TopologyEnvEntity struct {
+	NodeID   string // Reference to TopologyNodeEntity.ID
+	Name     string // Variable name; "*" for whole env sources (envFrom, env_file)
+	Value    string // Literal value, or the source ("secret:db/password", "configmap:app", "${DB_URL}")
+	FilePath string
+	Line     int
+}
+
+// TopologyEdge links a topology node to another node or to a function.
+// Target holds a reference that could not be resolved within a single file
+// (binary, Dockerfile path, image or selector); it is resolved by LinkTopology.
+type TopologyEdge struct {
+	FromID   string // Reference to TopologyNodeEntity.ID
+	ToID     string // Reference to TopologyNodeEntity.ID or FunctionEntity.ID (runs)
+	Target   string // Unresolved reference; set instead of ToID
+	Kind     string // See TopologyEdge*
+	FilePath string // File declaring the edge
+	Line     int
+}
+
+// Topology groups the infrastructure-as-code objects declared in a file.
+type Topology struct {
+	Nodes []TopologyNodeEntity
+	Env   []TopologyEnvEntity
+	Edges []TopologyEdge
+}
+
+// Merge appends the objects of another topology.
+func (t *Topology) Merge(other Topology) {
+	t.Nodes = append(t.Nodes, other.Nodes...)
+	t.Env = append(t.Env, other.Env...)
+	t.Edges = append(t.Edges, other.Edges...)
+}
+
+// Len returns the total number of topology objects.
+func (t *Topology) Len() int {
+	return len(t.Nodes) + len(t.Env) + len(t.Edges)
+}
+
+// Doc link target kinds.
+const (
+	DocTargetFunction = "function"
+	DocTargetType     = "type"
+	DocTargetFile     = "file"
+)
+
+// DocChunkEntity represents a section of a Markdown file, from a heading to the
+// next heading. Text before the first heading is a chunk with Level 0.
+// Note: In the database, Embedding is stored in cie_doc_chunk_embedding.
+type DocChunkEntity struct {
+	ID        string    // Deterministic: hash(file_path + anchor + start_line)
+	FilePath  string    // Markdown file
+	Heading   string    // Heading text without the leading #'s ("" for the preamble)
+	Anchor    string    // GitHub-style heading anchor (e.g., "ingestion-pipeline")
+	Level     int       // Heading level (1-6, 0 for the preamble)
+	Text      string    // Section text including the heading line
+	Embedding []float32 // Embedding vector (stored in cie_doc_chunk_embedding)
+	StartLine int       // Start line (1-indexed)
+	EndLine   int       // End line (1-indexed)
+}
+
+// DocLinkEdge links a doc chunk to a code symbol or file it mentions.
+// Symbol holds the mention as written (`Parser.ParseFile`, pkg/ingestion/parser.go);
+// TargetID is resolved by LinkDocMentions.
+type DocLinkEdge struct {
+	ChunkID    string // Reference to DocChunkEntity.ID
+	TargetID   string // Reference to FunctionEntity.ID, TypeEntity.ID or FileEntity.ID
+	TargetKind string // See DocTarget*
+	Symbol     string // Mention as written, without backticks or trailing "()"
+	FilePath   string // Markdown file
+	Line       int    // Line of the mention
+}
+
+// PackageEntity represents a directory of source files: a Go package, a PHP
+// namespace directory, a Python package or a JavaScript/TypeScript module directory.
+type PackageEntity struct {
+	ID        string // Deterministic: hash(path)
+	Path      string // Directory relative to the repository root ("." for the root)
+	Name      string // Go package name, PHP namespace, else the directory name
+	Language  string // Language of most source files in the directory
+	FileCount int    // Number of source files
+	Doc       string // Package doc comment (Go `// Package foo ...`), "" if none
+}
+
+// PackageImportEdge represents a "package imports package" relationship
+// between two indexed packages, aggregated from the imports of their files.
+type PackageImportEdge struct {
+	ID          string // Deterministic: hash(from_path + to_path)
+	FromPath    string // Reference to PackageEntity.Path of the importer
+	ToPath      string // Reference to PackageEntity.Path of the imported package
+	ImportCount int    // Number of import statements behind the edge
+}
+
 // GenerateFieldID generates a deterministic ID for a field entity.
 func GenerateFieldID(filePath, structName, fieldName string) string {
 	h := sha256.New()
@@ -175,6 +631,118 @@ func GenerateImplementsID(typeName, interfaceName string) string {
 	return "impl:" + hex.EncodeToString(h.Sum(nil))[:16]
 }
 
+// GenerateSQLTableID generates a deterministic ID for a SQL table declaration.
+func GenerateSQLTableID(filePath, tableName string) string {
+	return generateEntityID("tbl:", filePath, tableName)
+}
+
+// GenerateSQLColumnID generates a deterministic ID for a SQL column declaration.
+func GenerateSQLColumnID(filePath, tableName, columnName string) string {
+	return generateEntityID("col:", filePath, tableName, columnName)
+}
+
+// GenerateSQLIndexID generates a deterministic ID for a SQL index.
+// Unnamed indexes are identified by their columns.
+func GenerateSQLIndexID(filePath, tableName, indexName string, columns []string) string {
+	return generateEntityID("idx:", filePath, tableName, indexName, strings.Join(columns, ","))
+}
+
+// GenerateSQLForeignKeyID generates a deterministic ID for a SQL foreign key.
+func GenerateSQLForeignKeyID(filePath, tableName string, columns []string, refTable string) string {
+	return generateEntityID("fk:", filePath, tableName, strings.Join(columns, ","), refTable)
+}
+
+// GenerateTableAccessID generates a deterministic ID for a function -> table access edge.
+func GenerateTableAccessID(functionID, tableName, access string) string {
+	return generateEntityID("tacc:", functionID, tableName, access)
+}
+
+// GenerateGraphQLFieldID generates a deterministic ID for a GraphQL field.
+func GenerateGraphQLFieldID(filePath, typeName, fieldName string) string {
+	return generateEntityID("gqlf:", filePath, typeName, fieldName)
+}
+
+// GenerateGraphQLResolverID generates a deterministic ID for a field -> resolver edge.
+func GenerateGraphQLResolverID(typeName, fieldName, functionID string) string {
+	return generateEntityID("gqlr:", typeName, fieldName, functionID)
+}
+
+// GenerateTopologyNodeID generates a deterministic ID for a topology node.
+func GenerateTopologyNodeID(filePath, kind, name string) string {
+	return generateEntityID("topo:", filePath, kind, name)
+}
+
+// GenerateTopologyEnvID generates a deterministic ID for a topology env var.
+func GenerateTopologyEnvID(nodeID, name, value string) string {
+	return generateEntityID("tenv:", nodeID, name, value)
+}
+
+// GenerateTopologyEdgeID generates a deterministic ID for a topology edge.
+func GenerateTopologyEdgeID(fromID, toID, kind string) string {
+	return generateEntityID("tedge:", fromID, toID, kind)
+}
+
+// GenerateDocChunkID generates a deterministic ID for a doc chunk.
+func GenerateDocChunkID(filePath, anchor string, startLine int) string {
+	return generateEntityID("doc:", filePath, anchor, fmt.Sprintf("%d", startLine))
+}
+
+// GenerateDocLinkID generates a deterministic ID for a doc chunk -> target edge.
+func GenerateDocLinkID(chunkID, targetID string) string {
+	return generateEntityID("dlink:", chunkID, targetID)
+}
+
+// GeneratePackageID generates a deterministic ID for a package.
+func GeneratePackageID(path string) string {
+	return generateEntityID("pkg:", path)
+}
+
+// GeneratePackageImportID generates a deterministic ID for a package -> package edge.
+func GeneratePackageImportID(fromPath, toPath string) string {
+	return generateEntityID("pimp:", fromPath, toPath)
+}
+
+// GenerateFuncRefID generates a deterministic ID for a function reference edge.
+func GenerateFuncRefID(fromID, toID, kind string, line int) string {
+	return generateEntityID("fref:", fromID, toID, kind, fmt.Sprintf("%d", line))
+}
+
+// GenerateTypeRefID generates a deterministic ID for a type reference edge.
+func GenerateTypeRefID(fromID, typeID, kind string, line int) string {
+	return generateEntityID("tref:", fromID, typeID, kind, fmt.Sprintf("%d", line))
+}
+
+// GenerateTypeParamID generates a deterministic ID for a type parameter.
+func GenerateTypeParamID(ownerID, name string) string {
+	return generateEntityID("tparam:", ownerID, name)
+}
+
+// GenerateInstantiationID generates a deterministic ID for an instantiation edge.
+func GenerateInstantiationID(fromID, targetID, typeArgs string, line int) string {
+	return generateEntityID("inst:", fromID, targetID, typeArgs, fmt.Sprintf("%d", line))
+}
+
+// GenerateConcurrencyID generates a deterministic ID for a concurrency edge.
+func GenerateConcurrencyID(functionID, kind, target string, line int) string {
+	return generateEntityID("conc:", functionID, kind, target, fmt.Sprintf("%d", line))
+}
+
+// GenerateErrorSiteID generates a deterministic ID for an error site.
+func GenerateErrorSiteID(filePath, functionID, kind, name string, line int) string {
+	return generateEntityID("errsite:", filePath, functionID, kind, name, fmt.Sprintf("%d", line))
+}
+
+// GenerateTestID generates a deterministic ID for a test entity.
+func GenerateTestID(filePath, name string, line int) string {
+	return generateEntityID("test:", filePath, name, fmt.Sprintf("%d", line))
+}
+
+func generateEntityID(prefix string, parts ...string) string {
+	h := sha256.New()
+	h.Write([]byte(strings.Join(parts, "|")))
+	return prefix + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
 // DatalogSchema returns the Datalog schema definition for all ingestion tables.
 // Schema v3: Vertically partitioned for performance on large datasets.
 func DatalogSchema() string {
@@ -199,7 +767,8 @@ func DatalogSchema() string {
 	start_line: Int,
 	end_line: Int,
 	start_col: Int,
-	end_col: Int
+	end_col: Int,
+	doc_comment: String default ""
 }
 
 // Function code text: lazy loaded only when displaying source
@@ -229,6 +798,125 @@ func DatalogSchema() string {
 	call_line: Int default 0,
 }
 
+// Function references: function -> function used as a value
+:create cie_func_ref {
+	id: String =>
+	from_id: String,
+	to_id: String,
+	kind: String,
+	file_path: String,
+	line: Int
+}
+
+// Type references: function or type -> referenced type
+:create cie_type_ref {
+	id: String =>
+	from_id: String,
+	type_id: String,
+	kind: String,
+	file_path: String,
+	line: Int
+}
+
+// Type parameters of generic functions and types
+:create cie_type_param {
+	id: String =>
+	owner_id: String,
+	name: String,
+	constraint: String,
+	position: Int,
+	file_path: String
+}
+
+// Instantiations: function or type -> generic function or type it instantiates
+:create cie_instantiation {
+	id: String =>
+	from_id: String,
+	target_id: String,
+	target_kind: String,
+	type_args: String,
+	file_path: String,
+	line: Int
+}
+
+// Concurrency operations: goroutine spawns, channel sends/receives, lock acquisitions
+:create cie_concurrency {
+	id: String =>
+	function_id: String,
+	kind: String,
+	target: String,
+	target_id: String,
+	file_path: String,
+	line: Int
+}
+
+// Error sites: error construction, wrapping and raising per function
+:create cie_error_site {
+	id: String =>
+	function_id: String,
+	kind: String,
+	name: String,
+	message: String,
+	file_path: String,
+	line: Int
+}
+
+// Tests: test functions, subtests and suites with the function running them
+:create cie_test {
+	id: String =>
+	function_id: String,
+	name: String,
+	kind: String,
+	suite: String,
+	framework: String,
+	file_path: String,
+	line: Int
+}
+
+// Function metrics: complexity and size of functions from the syntax tree
+:create cie_function_metrics {
+	function_id: String =>
+	file_path: String,
+	complexity: Int,
+	max_nesting: Int,
+	params: Int,
+	loc: Int,
+	comment_lines: Int,
+	comment_ratio: Float
+}
+
+// Coverage: line coverage of functions imported from coverage profiles
+:create cie_coverage {
+	function_id: String =>
+	file_path: String,
+	covered: Int,
+	total: Int,
+	percent: Float,
+	uncovered: String,
+	source: String,
+	imported_at: Int
+}
+
+// Function churn: change history of functions from the git history pass
+:create cie_function_churn {
+	function_id: String =>
+	file_path: String,
+	commits: Int,
+	authors: Int,
+	lines_changed: Int,
+	first_changed: Int,
+	last_changed: Int
+}
+
+// Change coupling: functions changed in the same commits, in both directions
+:create cie_change_coupling {
+	function_id: String,
+	coupled_id: String =>
+	file_path: String,
+	co_changes: Int,
+	degree: Float
+}
+
 // Import entities: represents import statements in source files
 :create cie_import {
 	id: String =>
@@ -248,7 +936,8 @@ func DatalogSchema() string {
 	start_line: Int,
 	end_line: Int,
 	start_col: Int,
-	end_col: Int
+	end_col: Int,
+	doc_comment: String default ""
 }
 
 // Type code text: lazy loaded only when displaying source
@@ -287,6 +976,177 @@ func DatalogSchema() string {
 	interface_name: String,
 	file_path: String
 }
+
+// SQL tables declared in .sql migrations and schema dumps
+:create cie_sql_table {
+	id: String =>
+	name: String,
+	file_path: String,
+	start_line: Int,
+	end_line: Int,
+	code_text: String
+}
+
+// SQL columns (CREATE TABLE and ALTER TABLE ... ADD COLUMN)
+:create cie_sql_column {
+	id: String =>
+	table_name: String,
+	name: String,
+	data_type: String,
+	nullable: Bool,
+	primary_key: Bool,
+	file_path: String,
+	line: Int
+}
+
+// SQL indexes; columns is a comma-separated list
+:create cie_sql_index {
+	id: String =>
+	name: String,
+	table_name: String,
+	columns: String,
+	is_unique: Bool,
+	file_path: String,
+	line: Int
+}
+
+// SQL foreign keys: table(columns) -> ref_table(ref_columns)
+:create cie_sql_foreign_key {
+	id: String =>
+	table_name: String,
+	columns: String,
+	ref_table: String,
+	ref_columns: String,
+	file_path: String,
+	line: Int
+}
+
+// Table access edges: function -> SQL table (access is "read" or "write")
+:create cie_table_access {
+	id: String =>
+	function_id: String,
+	table_name: String,
+	access: String,
+	file_path: String,
+	line: Int
+}
+
+// GraphQL fields from .graphql/.graphqls SDL (operation is set for Query/Mutation/Subscription fields)
+:create cie_graphql_field {
+	id: String =>
+	type_name: String,
+	name: String,
+	field_type: String,
+	arguments: String,
+	operation: String,
+	file_path: String,
+	line: Int
+}
+
+// GraphQL resolver edges: (type_name, name) field -> resolver function
+:create cie_graphql_resolver {
+	id: String =>
+	type_name: String,
+	field_name: String,
+	function_id: String,
+	framework: String,
+	file_path: String,
+	line: Int
+}
+
+// Topology nodes: Dockerfiles, compose services, Kubernetes workloads/Services, Terraform resources
+:create cie_topology_node {
+	id: String =>
+	kind: String,
+	name: String,
+	image: String,
+	command: String,
+	binary: String,
+	ports: String,
+	labels: String,
+	file_path: String,
+	line: Int
+}
+
+// Environment variables passed to topology nodes (value is a literal or its source)
+:create cie_topology_env {
+	id: String =>
+	node_id: String,
+	name: String,
+	value: String,
+	file_path: String,
+	line: Int
+}
+
+// Topology edges: runs (-> main function), builds, image, exposes, depends_on
+:create cie_topology_edge {
+	id: String =>
+	from_id: String,
+	to_id: String,
+	kind: String,
+	file_path: String,
+	line: Int
+}
+
+// Doc chunks: Markdown sections split by heading
+:create cie_doc_chunk {
+	id: String =>
+	file_path: String,
+	heading: String,
+	anchor: String,
+	level: Int,
+	start_line: Int,
+	end_line: Int,
+	text: String
+}
+
+// Doc chunk embeddings: used only for HNSW semantic search
+:create cie_doc_chunk_embedding {
+	chunk_id: String =>
+	embedding: <F32; 1536>
+}
+
+// Doc links: doc chunk -> function, type or file mentioned in it
+:create cie_doc_link {
+	id: String =>
+	chunk_id: String,
+	target_id: String,
+	target_kind: String,
+	symbol: String,
+	file_path: String,
+	line: Int
+}
+
+// Variables: package-level variables and constants
+:create cie_variable {
+	id: String =>
+	name: String,
+	kind: String,
+	type: String,
+	value: String,
+	file_path: String,
+	code_text: String,
+	start_line: Int,
+	end_line: Int
+}
+
+// Packages: directories of source files, rebuilt after every indexing run
+:create cie_package {
+	id: String =>
+	path: String,
+	name: String,
+	language: String,
+	file_count: Int,
+	doc: String
+}
+
+// Package imports: package -> package edges aggregated from cie_import
+:create cie_package_import {
+	id: String =>
+	from_path: String,
+	to_path: String,
+	import_count: Int
+}
 `
 }
 
@@ -299,6 +1159,11 @@ func GenerateImportID(filePath, importPath string) string {
 	return "imp:" + hex.EncodeToString(h.Sum(nil))[:16]
 }
 
+// GenerateVariableID generates a deterministic ID for a variable entity.
+func GenerateVariableID(filePath, name string, startLine int) string {
+	return generateEntityID("var:", filePath, name, fmt.Sprintf("%d", startLine))
+}
+
 // GenerateTypeID generates a deterministic ID for a type entity.
 func GenerateTypeID(filePath, name string, startLine, endLine int) string {
 	h := sha256.New()
@@ -32,3 +32,247 @@ func TestDatalogSchema_ContainsImplementsTable(t *testing.T) {
 		}
 	}
 }
+
+// TestBuildMutations checks the row each builder writes for a sample entity,
+// the rows it skips, and that DatalogSchema creates the relations it writes.
+func TestBuildMutations(t *testing.T) {
+	b := NewDatalogBuilder()
+	nodeID := GenerateTopologyNodeID("k8s.yaml", "k8s_deployment", "worker")
+	chunkID := GenerateDocChunkID("docs/architecture.md", "ingestion", 11)
+
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+		absent []string
+		tables []string
+	}{
+		{
+			name: "sql",
+			script: b.BuildSQLMutations(SQLSchema{
+				Tables:      []SQLTableEntity{{ID: GenerateSQLTableID("db/001.sql", "orders"), Name: "orders", FilePath: "db/001.sql", CodeText: "CREATE TABLE orders (note TEXT DEFAULT 'x')", StartLine: 1, EndLine: 3}},
+				Columns:     []SQLColumnEntity{{TableName: "orders", Name: "id", DataType: "BIGINT", PrimaryKey: true, FilePath: "db/001.sql", Line: 2}},
+				Indexes:     []SQLIndexEntity{{TableName: "orders", Columns: []string{"user_id", "status"}, Unique: true, FilePath: "db/001.sql", Line: 5}},
+				ForeignKeys: []SQLForeignKeyEntity{{TableName: "orders", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}, FilePath: "db/001.sql", Line: 3}},
+			}, []TableAccessEdge{{FunctionID: "fn:1", TableName: "orders", Access: TableAccessWrite, FilePath: "store.go", Line: 12}}),
+			want: []string{
+				"'CREATE TABLE orders (note TEXT DEFAULT \\'x\\')'",
+				"'orders', 'id', 'BIGINT', false, true, 'db/001.sql', 2",
+				"'user_id,status', true",
+				"'orders', 'user_id', 'users', 'id'",
+				"'fn:1', 'orders', 'write', 'store.go', 12",
+			},
+			tables: []string{"cie_sql_table", "cie_sql_column", "cie_sql_index", "cie_sql_foreign_key", "cie_table_access"},
+		},
+		{
+			name: "graphql skips unresolved resolvers",
+			script: b.BuildGraphQLMutations([]GraphQLFieldEntity{{
+				ID: GenerateGraphQLFieldID("schema.graphqls", "Query", "orders"), TypeName: "Query", Name: "orders",
+				FieldType: "[Order!]!", Arguments: "first: Int = 20", Operation: "query", FilePath: "schema.graphqls", Line: 4,
+			}}, []GraphQLResolverEdge{
+				{TypeName: "Query", FieldName: "orders", FunctionID: "fn:1", Framework: GraphQLFrameworkGqlgen, FilePath: "graph/schema.resolvers.go", Line: 10},
+				{TypeName: "Query", FieldName: "users", FunctionName: "listUsers", Framework: GraphQLFrameworkApollo, FilePath: "src/resolvers.ts", Line: 3},
+			}),
+			want: []string{
+				"'Query', 'orders', '[Order!]!', 'first: Int = 20', 'query', 'schema.graphqls', 4",
+				"'Query', 'orders', 'fn:1', 'gqlgen', 'graph/schema.resolvers.go', 10",
+			},
+			absent: []string{"listUsers", "'users'"},
+			tables: []string{"cie_graphql_field", "cie_graphql_resolver"},
+		},
+		{
+			name: "topology skips unresolved edges",
+			script: b.BuildTopologyMutations(Topology{
+				Nodes: []TopologyNodeEntity{{
+					ID: nodeID, Kind: "k8s_deployment", Name: "worker", Image: "ghcr.io/acme/worker:1.0",
+					Ports: "9090", Labels: "app=worker", FilePath: "k8s.yaml", Line: 12,
+				}},
+				Env: []TopologyEnvEntity{{NodeID: nodeID, Name: "DATABASE_URL", Value: "secret:db/url", FilePath: "k8s.yaml", Line: 20}},
+				Edges: []TopologyEdge{
+					{FromID: nodeID, ToID: "topo:dockerfile", Kind: TopologyEdgeImage, FilePath: "k8s.yaml", Line: 12},
+					{FromID: nodeID, Target: "unresolved/image", Kind: TopologyEdgeImage, FilePath: "k8s.yaml", Line: 12},
+				},
+			}),
+			want: []string{
+				"'k8s_deployment', 'worker', 'ghcr.io/acme/worker:1.0', '', '', '9090', 'app=worker', 'k8s.yaml', 12",
+				"'" + nodeID + "', 'DATABASE_URL', 'secret:db/url', 'k8s.yaml', 20",
+				"'" + nodeID + "', 'topo:dockerfile', 'image', 'k8s.yaml', 12",
+			},
+			absent: []string{"unresolved/image"},
+			tables: []string{"cie_topology_node", "cie_topology_env", "cie_topology_edge"},
+		},
+		{
+			name: "docs skip empty embeddings and unresolved links",
+			script: b.BuildDocMutations([]DocChunkEntity{
+				{ID: chunkID, FilePath: "docs/architecture.md", Heading: "Ingestion", Anchor: "ingestion", Level: 2,
+					Text: "## Ingestion\n\nParsed by `Parser.ParseFile`.", Embedding: []float32{0.5, 0.25}, StartLine: 11, EndLine: 13},
+				{ID: "doc:empty", FilePath: "README.md", Text: "CIE", StartLine: 1, EndLine: 1},
+			}, []DocLinkEdge{
+				{ChunkID: chunkID, TargetID: "fn:1", TargetKind: DocTargetFunction, Symbol: "Parser.ParseFile", FilePath: "docs/architecture.md", Line: 13},
+				{ChunkID: chunkID, TargetKind: DocTargetFunction, Symbol: "Unknown", FilePath: "docs/architecture.md", Line: 13},
+			}),
+			want: []string{
+				"'docs/architecture.md', 'Ingestion', 'ingestion', 2, 11, 13, '## Ingestion\n\nParsed by `Parser.ParseFile`.'",
+				"'" + chunkID + "', [0.5, 0.25]",
+				"'" + chunkID + "', 'fn:1', 'function', 'Parser.ParseFile', 'docs/architecture.md', 13",
+			},
+			absent: []string{"'doc:empty', [", "Unknown"},
+			tables: []string{"cie_doc_chunk", "cie_doc_chunk_embedding", "cie_doc_link"},
+		},
+		{
+			name: "packages",
+			script: b.BuildPackageMutations(
+				[]PackageEntity{{ID: GeneratePackageID("pkg/tools"), Path: "pkg/tools", Name: "tools", Language: "go", FileCount: 3, Doc: "Package tools implements the MCP tools."}},
+				[]PackageImportEdge{{ID: GeneratePackageImportID("cmd/cie", "pkg/tools"), FromPath: "cmd/cie", ToPath: "pkg/tools", ImportCount: 2}},
+			),
+			want: []string{
+				"'pkg/tools', 'tools', 'go', 3, 'Package tools implements the MCP tools.'",
+				"'cmd/cie', 'pkg/tools', 2",
+			},
+			tables: []string{"cie_package", "cie_package_import"},
+		},
+		{
+			name: "variables",
+			script: b.BuildVariableMutations([]VariableEntity{
+				{ID: GenerateVariableID("pkg/llm/client.go", "DefaultTimeout", 31), Name: "DefaultTimeout", Kind: "const", Type: "time.Duration",
+					Value: "30 * time.Second", FilePath: "pkg/llm/client.go", CodeText: "DefaultTimeout time.Duration = 30 * time.Second", StartLine: 31, EndLine: 31},
+			}),
+			want:   []string{"'DefaultTimeout', 'const', 'time.Duration', '30 * time.Second', 'pkg/llm/client.go'"},
+			tables: []string{"cie_variable"},
+		},
+		{
+			name: "type refs",
+			script: b.BuildTypeRefMutations([]TypeRefEdge{
+				{ID: GenerateTypeRefID("fn:run", "type:QueryResult", "param", 11), FromID: "fn:run", TypeID: "type:QueryResult", Kind: "param", FilePath: "cmd/cie/main.go", Line: 11},
+			}),
+			want:   []string{"'fn:run', 'type:QueryResult', 'param', 'cmd/cie/main.go', 11]] :put cie_type_ref { id, from_id, type_id, kind, file_path, line } }\n"},
+			tables: []string{"cie_type_ref"},
+		},
+		{
+			name: "generics",
+			script: b.BuildGenericMutations(
+				[]TypeParamEntity{{ID: GenerateTypeParamID("fn:Sum", "N"), OwnerID: "fn:Sum", Name: "N", Constraint: "~int | ~float64", Position: 0, FilePath: "pkg/coll/coll.go"}},
+				[]InstantiationEdge{{ID: GenerateInstantiationID("fn:run", "fn:Sum", "float64", 16), FromID: "fn:run", TargetID: "fn:Sum", TargetKind: "function", TypeArgs: "float64", FilePath: "cmd/app/main.go", Line: 16}},
+			),
+			want: []string{
+				"'fn:Sum', 'N', '~int | ~float64', 0, 'pkg/coll/coll.go']] :put cie_type_param { id, owner_id, name, constraint, position, file_path } }\n",
+				"'fn:run', 'fn:Sum', 'function', 'float64', 'cmd/app/main.go', 16]] :put cie_instantiation { id, from_id, target_id, target_kind, type_args, file_path, line } }\n",
+			},
+			tables: []string{"cie_type_param", "cie_instantiation"},
+		},
+		{
+			name: "func refs",
+			script: b.BuildFuncRefMutations([]FuncRefEdge{
+				{ID: GenerateFuncRefID("fn:Register", "fn:ListUsers", "argument", 22), FromID: "fn:Register", ToID: "fn:ListUsers", Kind: "argument", FilePath: "internal/server/server.go", Line: 22},
+			}),
+			want:   []string{"'fn:Register', 'fn:ListUsers', 'argument', 'internal/server/server.go', 22]] :put cie_func_ref { id, from_id, to_id, kind, file_path, line } }\n"},
+			tables: []string{"cie_func_ref"},
+		},
+		{
+			name: "concurrency",
+			script: b.BuildConcurrencyMutations([]ConcurrencyEdge{
+				{ID: GenerateConcurrencyID("fn:run", "lock", "Pool.mu", 26), FunctionID: "fn:run", Kind: "lock", Target: "Pool.mu", FilePath: "internal/worker/pool.go", Line: 26},
+			}),
+			want:   []string{"'fn:run', 'lock', 'Pool.mu', '', 'internal/worker/pool.go', 26]] :put cie_concurrency { id, function_id, kind, target, target_id, file_path, line } }\n"},
+			tables: []string{"cie_concurrency"},
+		},
+		{
+			name: "error sites",
+			script: b.BuildErrorSiteMutations([]ErrorSiteEntity{
+				{ID: GenerateErrorSiteID("internal/store/store.go", "fn:get", "return", "ErrNotFound", 25), FunctionID: "fn:get", Kind: "return", Name: "ErrNotFound", FilePath: "internal/store/store.go", Line: 25},
+				{ID: GenerateErrorSiteID("internal/store/store.go", "", "sentinel", "ErrNotFound", 9), Kind: "sentinel", Name: "ErrNotFound", Message: "project 'x' not found", FilePath: "internal/store/store.go", Line: 9},
+			}),
+			want: []string{
+				"'fn:get', 'return', 'ErrNotFound', '', 'internal/store/store.go', 25]] :put cie_error_site { id, function_id, kind, name, message, file_path, line } }\n",
+				"'', 'sentinel', 'ErrNotFound', 'project \\'x\\' not found', 'internal/store/store.go', 9]]",
+			},
+			tables: []string{"cie_error_site"},
+		},
+		{
+			name: "tests",
+			script: b.BuildTestMutations([]TestEntity{
+				{ID: GenerateTestID("store_test.go", "TestGet/missing_key", 6), FunctionID: "fn:anon", Name: "TestGet/missing_key", Kind: "subtest", Suite: "TestGet", Framework: "go", FilePath: "store_test.go", Line: 6},
+			}),
+			want:   []string{"'fn:anon', 'TestGet/missing_key', 'subtest', 'TestGet', 'go', 'store_test.go', 6]] :put cie_test { id, function_id, name, kind, suite, framework, file_path, line } }\n"},
+			tables: []string{"cie_test"},
+		},
+		{
+			name: "coverage",
+			script: b.BuildCoverageMutations([]CoverageEntity{
+				{FunctionID: "fn:get", FilePath: "store.go", Covered: 3, Total: 4, Percent: 75, Uncovered: "12-13", Source: "cover.out", ImportedAt: 1760000000},
+			}),
+			want:   []string{"[['fn:get', 'store.go', 3, 4, 75.0, '12-13', 'cover.out', 1760000000]] :put cie_coverage { function_id, file_path, covered, total, percent, uncovered, source, imported_at } }\n"},
+			tables: []string{"cie_coverage"},
+		},
+		{
+			name: "function metrics",
+			script: b.BuildFunctionMetricsMutations([]FunctionMetricsEntity{
+				{FunctionID: "fn:get", FilePath: "store.go", Complexity: 4, MaxNesting: 2, Params: 1, LOC: 12, CommentLines: 3, CommentRatio: 0.25},
+			}),
+			want:   []string{"[['fn:get', 'store.go', 4, 2, 1, 12, 3, 0.250]] :put cie_function_metrics { function_id, file_path, complexity, max_nesting, params, loc, comment_lines, comment_ratio } }\n"},
+			tables: []string{"cie_function_metrics"},
+		},
+		{
+			name: "churn and change coupling",
+			script: b.BuildFunctionChurnMutations([]FunctionChurnEntity{
+				{FunctionID: "fn:get", FilePath: "store.go", Commits: 5, Authors: 2, LinesChanged: 40, FirstChanged: 1750000000, LastChanged: 1760000000},
+			}) + b.BuildChangeCouplingMutations([]ChangeCouplingEntity{
+				{FunctionID: "fn:get", CoupledID: "fn:put", FilePath: "store.go", CoChanges: 3, Degree: 0.6},
+			}),
+			want: []string{
+				"[['fn:get', 'store.go', 5, 2, 40, 1750000000, 1760000000]] :put cie_function_churn { function_id, file_path, commits, authors, lines_changed, first_changed, last_changed } }\n",
+				"[['fn:get', 'fn:put', 'store.go', 3, 0.600]] :put cie_change_coupling { function_id, coupled_id, file_path, co_changes, degree } }\n",
+			},
+			tables: []string{"cie_function_churn", "cie_change_coupling"},
+		},
+	}
+
+	schema := DatalogSchema()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range tt.want {
+				if !strings.Contains(tt.script, want) {
+					t.Errorf("script should contain %q, got:\n%s", want, tt.script)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(tt.script, absent) {
+					t.Errorf("script should not contain %q, got:\n%s", absent, tt.script)
+				}
+			}
+			for _, table := range tt.tables {
+				if !strings.Contains(schema, ":create "+table+" {") {
+					t.Errorf("DatalogSchema() should contain %s table", table)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildPackageMutations_ReplacesGraph(t *testing.T) {
+	script := NewDatalogBuilder().BuildPackageMutations(
+		[]PackageEntity{{ID: GeneratePackageID("pkg/tools"), Path: "pkg/tools", Name: "tools", Language: "go", FileCount: 3}}, nil)
+
+	rm := strings.Index(script, ":rm cie_package {id}")
+	if rm == -1 || !strings.Contains(script, ":rm cie_package_import {id}") {
+		t.Fatalf("BuildPackageMutations() should remove the previous graph, got:\n%s", script)
+	}
+	if rm > strings.Index(script, ":put cie_package {") {
+		t.Error("BuildPackageMutations() should remove the previous graph before writing")
+	}
+}
+
+func TestBuildMutations_Empty(t *testing.T) {
+	b := NewDatalogBuilder()
+	for name, script := range map[string]string{
+		"variables": b.BuildVariableMutations(nil),
+		"type refs": b.BuildTypeRefMutations(nil),
+		"func refs": b.BuildFuncRefMutations(nil),
+		"tests":     b.BuildTestMutations(nil),
+		"coverage":  b.BuildCoverageMutations(nil),
+	} {
+		if script != "" {
+			t.Errorf("%s: empty input should build no script, got %q", name, script)
+		}
+	}
+}
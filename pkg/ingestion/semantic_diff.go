@@ -0,0 +1,456 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// functionLanguages are the languages the parser extracts functions from.
+var functionLanguages = map[string]bool{
+	"go": true, "python": true, "javascript": true, "typescript": true,
+	"php": true, "bash": true, "protobuf": true,
+}
+
+// changedPath is a file changed between two refs, from git diff --name-status.
+type changedPath struct {
+	OldPath string // "" when the file is added
+	NewPath string // "" when the file is deleted
+}
+
+// parseNameStatus returns the files listed by git diff --name-status.
+// Copies are additions: the copied file keeps its own symbols.
+func parseNameStatus(out string) []changedPath {
+	var paths []changedPath
+	for _, line := range strings.Split(out, "\n") {
+		status, files := parseGitDiffLine(strings.TrimRight(line, "\r"))
+		if status == "" || len(files) == 0 {
+			continue
+		}
+		switch status[0] {
+		case 'A':
+			paths = append(paths, changedPath{NewPath: files[0]})
+		case 'D':
+			paths = append(paths, changedPath{OldPath: files[0]})
+		case 'R':
+			if len(files) >= 2 {
+				paths = append(paths, changedPath{OldPath: files[0], NewPath: files[1]})
+			}
+		case 'C':
+			if len(files) >= 2 {
+				paths = append(paths, changedPath{NewPath: files[1]})
+			}
+		default:
+			paths = append(paths, changedPath{OldPath: files[0], NewPath: files[0]})
+		}
+	}
+	return paths
+}
+
+// DiffSymbols compares the functions of the files changed between two git
+// refs, or between base and the working tree when head is empty. Both
+// versions of each file are read from git and parsed, nothing is checked out
+// and the index is not used.
+//
+// Functions are first matched by ID through ComputeFileDiff, then by name
+// within each file, then by body hash across files: a removed and an added
+// function with the same body, ignoring the name in the declaration, are a
+// rename, or a move when the name is kept. Call edges are compared by caller
+// and callee name, following renames and moves.
+func DiffSymbols(ctx context.Context, git tools.GitRunner, base, head string) (*tools.SemanticDiffReport, error) {
+	for _, ref := range []string{base, head} {
+		if strings.HasPrefix(ref, "-") {
+			return nil, fmt.Errorf("invalid git ref %q", ref)
+		}
+	}
+	if base == "" {
+		return nil, fmt.Errorf("base ref is required")
+	}
+	args := []string{"diff", "--name-status", "-M", "--no-color", base}
+	if head != "" {
+		args = append(args, head)
+	}
+	out, err := git.Run(ctx, append(args, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	paths := parseNameStatus(out)
+
+	parser := NewTreeSitterParser(nil)
+	report := &tools.SemanticDiffReport{Base: base, Head: head, FilesChanged: len(paths)}
+	d := newSymbolDiff()
+	for _, p := range paths {
+		path := p.NewPath
+		if path == "" {
+			path = p.OldPath
+		}
+		language := detectLanguageFromPath(path)
+		if !functionLanguages[language] {
+			continue
+		}
+		var oldResult, newResult *ParseResult
+		if p.OldPath != "" {
+			content, err := git.Run(ctx, "show", base+":"+p.OldPath)
+			if err != nil {
+				return nil, err
+			}
+			if oldResult, err = parseRevision(parser, p.OldPath, language, []byte(content)); err != nil {
+				return nil, err
+			}
+		}
+		if p.NewPath != "" {
+			var content []byte
+			if head == "" {
+				content, err = os.ReadFile(filepath.Join(git.RepoPath(), p.NewPath))
+			} else {
+				var out string
+				out, err = git.Run(ctx, "show", head+":"+p.NewPath)
+				content = []byte(out)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if newResult, err = parseRevision(parser, p.NewPath, language, content); err != nil {
+				return nil, err
+			}
+		}
+		report.FilesParsed++
+		d.addFile(path, oldResult, newResult)
+	}
+	d.matchAcrossFiles()
+
+	report.Changes = d.changes
+	sort.SliceStable(report.Changes, func(i, j int) bool {
+		a, b := report.Changes[i], report.Changes[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Line < b.Line
+	})
+	report.AddedCalls, report.RemovedCalls = d.callChanges()
+	return report, nil
+}
+
+// parseRevision parses one version of a file from its content.
+func parseRevision(parser *TreeSitterParser, path, language string, content []byte) (*ParseResult, error) {
+	result, err := parser.ParseContent(FileInfo{Path: path, Size: int64(len(content)), Language: language}, content)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// callEdge is a call from a named function, keyed by file, caller and callee.
+type callEdge struct {
+	FilePath string
+	Caller   string
+	Callee   string
+	Line     int
+}
+
+// symbolDiff accumulates the function changes of the changed files.
+type symbolDiff struct {
+	changes []tools.SymbolChange
+
+	// removed and added are the functions left unmatched within their file.
+	removed, added []FunctionEntity
+
+	// renamed maps the file and name of a function at base to its file and
+	// name at head, for functions renamed or moved.
+	renamed map[[2]string][2]string
+
+	// oldCalls and newCalls are the call edges of the changed files.
+	oldCalls, newCalls []callEdge
+}
+
+func newSymbolDiff() *symbolDiff {
+	return &symbolDiff{renamed: make(map[[2]string][2]string)}
+}
+
+// addFile compares the two versions of a changed file; either may be nil
+// when the file is added or deleted.
+func (d *symbolDiff) addFile(path string, oldResult, newResult *ParseResult) {
+	var oldEntry, newEntry *FileManifestEntry
+	oldByID := make(map[string]FunctionEntity)
+	newByID := make(map[string]FunctionEntity)
+	if oldResult != nil {
+		oldEntry = CreateFileManifestEntry(oldResult.File, oldResult.Functions)
+		for _, fn := range oldResult.Functions {
+			oldByID[fn.ID] = fn
+		}
+		d.oldCalls = append(d.oldCalls, fileCallEdges(oldResult)...)
+	}
+	if newResult != nil {
+		newEntry = CreateFileManifestEntry(newResult.File, newResult.Functions)
+		for _, fn := range newResult.Functions {
+			newByID[fn.ID] = fn
+		}
+		d.newCalls = append(d.newCalls, fileCallEdges(newResult)...)
+	}
+
+	fd := ComputeFileDiff(path, oldEntry, newEntry)
+	for _, m := range fd.ModifiedFunctions {
+		if !isAnonymous(m.Name) {
+			d.compare(oldByID[m.ID], newByID[m.ID])
+		}
+	}
+
+	// Functions whose lines moved get a new ID: match them by name
+	var removed []FunctionEntity
+	for _, r := range fd.RemovedFunctions {
+		if !isAnonymous(r.Name) {
+			removed = append(removed, oldByID[r.ID])
+		}
+	}
+	var candidates []FunctionEntity
+	for _, a := range fd.AddedFunctions {
+		if !isAnonymous(a.Name) {
+			candidates = append(candidates, newByID[a.ID])
+		}
+	}
+	sortFunctions(removed)
+	sortFunctions(candidates)
+	var added []FunctionEntity
+	for _, fn := range candidates {
+		if i := indexFunction(removed, func(r FunctionEntity) bool { return r.Name == fn.Name }); i >= 0 {
+			d.compare(removed[i], fn)
+			removed = append(removed[:i], removed[i+1:]...)
+			continue
+		}
+		added = append(added, fn)
+	}
+	d.removed = append(d.removed, removed...)
+	d.added = append(d.added, added...)
+}
+
+// compare records the change between two versions of a function matched by
+// ID or name: a new signature, a new body, or a move when only its file
+// changed. Identical functions are not changes.
+func (d *symbolDiff) compare(oldFn, newFn FunctionEntity) {
+	change := tools.SymbolChange{Name: newFn.Name, FilePath: newFn.FilePath, Line: newFn.StartLine, Signature: newFn.Signature}
+	if oldFn.FilePath != newFn.FilePath {
+		change.OldFilePath = oldFn.FilePath
+		d.renamed[[2]string{oldFn.FilePath, oldFn.Name}] = [2]string{newFn.FilePath, newFn.Name}
+	}
+	switch {
+	case oldFn.Signature != newFn.Signature:
+		change.Kind = tools.SymbolSignatureChanged
+		change.OldSignature = oldFn.Signature
+	case computeBodyHash(oldFn.CodeText) != computeBodyHash(newFn.CodeText):
+		change.Kind = tools.SymbolBodyChanged
+	case change.OldFilePath != "":
+		change.Kind = tools.SymbolMoved
+	default:
+		return
+	}
+	d.changes = append(d.changes, change)
+}
+
+// matchAcrossFiles pairs the functions left unmatched in their files by body
+// hash, ignoring the declared name: same name is a move, another name a
+// rename. The rest are additions and removals.
+func (d *symbolDiff) matchAcrossFiles() {
+	sortFunctions(d.removed)
+	sortFunctions(d.added)
+	removed := d.removed
+	for _, fn := range d.added {
+		hash := renameHash(fn)
+		i := indexFunction(removed, func(r FunctionEntity) bool { return renameHash(r) == hash })
+		if i < 0 {
+			d.changes = append(d.changes, tools.SymbolChange{
+				Kind: tools.SymbolAdded, Name: fn.Name, FilePath: fn.FilePath, Line: fn.StartLine, Signature: fn.Signature,
+			})
+			continue
+		}
+		oldFn := removed[i]
+		removed = append(removed[:i], removed[i+1:]...)
+		if oldFn.Name == fn.Name {
+			d.compare(oldFn, fn)
+			continue
+		}
+		change := tools.SymbolChange{
+			Kind: tools.SymbolRenamed, Name: fn.Name, FilePath: fn.FilePath, Line: fn.StartLine,
+			Signature: fn.Signature, OldName: oldFn.Name,
+		}
+		if oldFn.FilePath != fn.FilePath {
+			change.OldFilePath = oldFn.FilePath
+		}
+		if oldFn.Signature != fn.Signature {
+			change.OldSignature = oldFn.Signature
+		}
+		d.renamed[[2]string{oldFn.FilePath, oldFn.Name}] = [2]string{fn.FilePath, fn.Name}
+		d.changes = append(d.changes, change)
+	}
+	for _, fn := range removed {
+		d.changes = append(d.changes, tools.SymbolChange{
+			Kind: tools.SymbolRemoved, Name: fn.Name, FilePath: fn.FilePath, Line: fn.StartLine, Signature: fn.Signature,
+		})
+	}
+}
+
+// callChanges returns the call edges found only at head and only at base.
+// Callers renamed or moved are compared under their name at head.
+func (d *symbolDiff) callChanges() (added, removed []tools.CallEdgeChange) {
+	key := func(e callEdge) string { return e.FilePath + "\x00" + e.Caller + "\x00" + e.Callee }
+	oldKeys := make(map[string]bool, len(d.oldCalls))
+	for i, e := range d.oldCalls {
+		if to, ok := d.renamed[[2]string{e.FilePath, e.Caller}]; ok {
+			d.oldCalls[i].FilePath, d.oldCalls[i].Caller = to[0], to[1]
+		}
+		oldKeys[key(d.oldCalls[i])] = true
+	}
+	newKeys := make(map[string]bool, len(d.newCalls))
+	for _, e := range d.newCalls {
+		newKeys[key(e)] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range d.newCalls {
+		if k := key(e); !oldKeys[k] && !seen[k] {
+			seen[k] = true
+			added = append(added, tools.CallEdgeChange{Caller: e.Caller, Callee: e.Callee, FilePath: e.FilePath, Line: e.Line})
+		}
+	}
+	for _, e := range d.oldCalls {
+		if k := key(e); !newKeys[k] && !seen[k] {
+			seen[k] = true
+			removed = append(removed, tools.CallEdgeChange{Caller: e.Caller, Callee: e.Callee, FilePath: e.FilePath, Line: e.Line})
+		}
+	}
+	sortCallEdges(added)
+	sortCallEdges(removed)
+	return added, removed
+}
+
+// fileCallEdges returns the calls made by the functions of a parsed file.
+// Calls from closures are attributed to the named function enclosing them;
+// callees outside the file keep the name written in the call. Go builtins
+// and conversions (len, append, int) are left out.
+func fileCallEdges(result *ParseResult) []callEdge {
+	byID := make(map[string]FunctionEntity, len(result.Functions))
+	for _, fn := range result.Functions {
+		byID[fn.ID] = fn
+	}
+	caller := func(id string) string {
+		fn, ok := byID[id]
+		if !ok {
+			return ""
+		}
+		if !isAnonymous(fn.Name) {
+			return fn.Name
+		}
+		return enclosingFunction(result.Functions, fn)
+	}
+
+	var edges []callEdge
+	for _, c := range result.Calls {
+		callee, ok := byID[c.CalleeID]
+		if name := caller(c.CallerID); name != "" && ok && !isAnonymous(callee.Name) {
+			edges = append(edges, callEdge{FilePath: result.File.Path, Caller: name, Callee: callee.Name, Line: c.CallLine})
+		}
+	}
+	for _, c := range result.UnresolvedCalls {
+		if result.File.Language == "go" && isGoPredeclared(c.CalleeName) {
+			continue
+		}
+		if name := caller(c.CallerID); name != "" && c.CalleeName != "" {
+			edges = append(edges, callEdge{FilePath: result.File.Path, Caller: name, Callee: c.CalleeName, Line: c.Line})
+		}
+	}
+	return edges
+}
+
+// enclosingFunction returns the name of the innermost named function
+// containing fn, or "" for closures outside any function.
+func enclosingFunction(functions []FunctionEntity, fn FunctionEntity) string {
+	name, size := "", 0
+	for _, outer := range functions {
+		if isAnonymous(outer.Name) || outer.StartLine > fn.StartLine || outer.EndLine < fn.EndLine {
+			continue
+		}
+		if span := outer.EndLine - outer.StartLine; name == "" || span < size {
+			name, size = outer.Name, span
+		}
+	}
+	return name
+}
+
+// renameHash is the body hash of a function with its declared name left
+// out, so that a renamed function matches its previous version.
+func renameHash(fn FunctionEntity) string {
+	name := fn.Name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return computeBodyHash(strings.Replace(fn.CodeText, name, "", 1))
+}
+
+// isAnonymous reports whether a function name is the positional name of a
+// closure ($anon_1, $arrow_1, $lambda_1): closures change with the function
+// enclosing them. The top-level code of scripts ($main) is named.
+func isAnonymous(name string) bool {
+	for _, prefix := range []string{"$anon_", "$arrow_", "$lambda_"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexFunction returns the index of the first function matching match, or -1.
+func indexFunction(functions []FunctionEntity, match func(FunctionEntity) bool) int {
+	for i, fn := range functions {
+		if match(fn) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortFunctions orders functions by file and start line.
+func sortFunctions(functions []FunctionEntity) {
+	sort.SliceStable(functions, func(i, j int) bool {
+		if functions[i].FilePath != functions[j].FilePath {
+			return functions[i].FilePath < functions[j].FilePath
+		}
+		return functions[i].StartLine < functions[j].StartLine
+	})
+}
+
+// sortCallEdges orders call edges by file, caller and callee.
+func sortCallEdges(edges []tools.CallEdgeChange) {
+	sort.SliceStable(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.Caller != b.Caller {
+			return a.Caller < b.Caller
+		}
+		return a.Callee < b.Callee
+	})
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// treeGitRunner serves git diff --name-status and git show from fixed
+// outputs keyed by the joined arguments.
+type treeGitRunner map[string]string
+
+func (g treeGitRunner) Run(_ context.Context, args ...string) (string, error) {
+	out, ok := g[strings.Join(args, " ")]
+	if !ok {
+		return "", fmt.Errorf("unexpected git %s", strings.Join(args, " "))
+	}
+	return out, nil
+}
+
+func (g treeGitRunner) RepoPath() string { return "/repo" }
+
+func TestDiffSymbols(t *testing.T) {
+	git := treeGitRunner{
+		"diff --name-status -M --no-color main HEAD --": "M\tstore.go\nR087\tutil.go\thelpers.go\nA\tREADME.md\nA\tcache.go\n",
+		"show main:store.go": `package store
+
+func Get(id string) string {
+	return load(id)
+}
+
+func Put(id string) {
+	save(id)
+}
+
+func legacy() {}
+
+func scan() int {
+	return 1
+}
+`,
+		"show HEAD:store.go": `package store
+
+// Get was moved down by this comment.
+func Get(id string) string {
+	return load(id)
+}
+
+func Put(id, value string) {
+	save(id)
+}
+
+func fetch() int {
+	return 1
+}
+`,
+		"show main:util.go": `package store
+
+func clean(s string) string {
+	return s
+}
+
+func trim(s string) string {
+	return s[1:]
+}
+`,
+		"show HEAD:helpers.go": `package store
+
+func clean(s string) string {
+	return s
+}
+
+func trim(s string) string {
+	log.Print(s)
+	return Get(s)[1:]
+}
+`,
+		"show HEAD:cache.go": `package store
+
+func warm() {
+	Get("a")
+}
+`,
+	}
+
+	report, err := DiffSymbols(context.Background(), git, "main", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.FilesChanged)
+	assert.Equal(t, 3, report.FilesParsed)
+
+	assert.Equal(t, []tools.SymbolChange{
+		{Kind: tools.SymbolAdded, Name: "warm", FilePath: "cache.go", Line: 3, Signature: "func warm()"},
+		{Kind: tools.SymbolMoved, Name: "clean", FilePath: "helpers.go", Line: 3, Signature: "func clean(s string) string", OldFilePath: "util.go"},
+		{Kind: tools.SymbolBodyChanged, Name: "trim", FilePath: "helpers.go", Line: 7, Signature: "func trim(s string) string", OldFilePath: "util.go"},
+		{Kind: tools.SymbolSignatureChanged, Name: "Put", FilePath: "store.go", Line: 8, Signature: "func Put(id, value string)", OldSignature: "func Put(id string)"},
+		{Kind: tools.SymbolRemoved, Name: "legacy", FilePath: "store.go", Line: 11, Signature: "func legacy()"},
+		{Kind: tools.SymbolRenamed, Name: "fetch", FilePath: "store.go", Line: 12, Signature: "func fetch() int", OldName: "scan", OldSignature: "func scan() int"},
+	}, report.Changes)
+
+	assert.Equal(t, []tools.CallEdgeChange{
+		{Caller: "warm", Callee: "Get", FilePath: "cache.go", Line: 4},
+		{Caller: "trim", Callee: "Get", FilePath: "helpers.go", Line: 9},
+		{Caller: "trim", Callee: "log.Print", FilePath: "helpers.go", Line: 8},
+	}, report.AddedCalls)
+	assert.Empty(t, report.RemovedCalls)
+}
+
+func TestDiffSymbols_ScriptTopLevel(t *testing.T) {
+	git := treeGitRunner{
+		"diff --name-status -M --no-color main HEAD --": "M\tdeploy.sh\n",
+		"show main:deploy.sh":                           "#!/usr/bin/env bash\n\nbuild() {\n    go build ./...\n}\n\nbuild\n",
+		"show HEAD:deploy.sh":                           "#!/usr/bin/env bash\n\nbuild() {\n    go build ./...\n}\n\nbuild\nkubectl apply -f k8s/\n",
+	}
+
+	report, err := DiffSymbols(context.Background(), git, "main", "HEAD")
+	require.NoError(t, err)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, tools.SymbolBodyChanged, report.Changes[0].Kind)
+	assert.Equal(t, scriptMainFunctionName, report.Changes[0].Name)
+	assert.Equal(t, []tools.CallEdgeChange{
+		{Caller: scriptMainFunctionName, Callee: "kubectl", FilePath: "deploy.sh", Line: 8},
+	}, report.AddedCalls)
+	assert.Empty(t, report.RemovedCalls)
+}
+
+func TestDiffSymbols_InvalidRef(t *testing.T) {
+	_, err := DiffSymbols(context.Background(), treeGitRunner{}, "--output=/tmp/x", "")
+	assert.Error(t, err)
+	_, err = DiffSymbols(context.Background(), treeGitRunner{}, "", "HEAD")
+	assert.Error(t, err)
+}
+
+func TestParseNameStatus(t *testing.T) {
+	assert.Equal(t, []changedPath{
+		{NewPath: "a.go"},
+		{OldPath: "b.go", NewPath: "b.go"},
+		{OldPath: "c.go"},
+		{OldPath: "d.go", NewPath: "e.go"},
+		{NewPath: "g.go"},
+	}, parseNameStatus("A\ta.go\nM\tb.go\nD\tc.go\nR100\td.go\te.go\nC075\tf.go\tg.go\n"))
+}
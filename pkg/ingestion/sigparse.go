@@ -30,3 +30,10 @@ type ParamInfo = sigparse.ParamInfo
 func ParseGoSignatureParams(signature string) []ParamInfo {
 	return sigparse.ParseGoParams(signature)
 }
+
+// ParsePythonSignatureParams parses a Python function signature string and
+// returns the annotated parameter names and their base types. Delegates to
+// sigparse.ParsePythonParams.
+func ParsePythonSignatureParams(signature string) []ParamInfo {
+	return sigparse.ParsePythonParams(signature)
+}
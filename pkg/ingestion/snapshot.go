@@ -0,0 +1,350 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kraklabs/cie/pkg/storage"
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// snapshotBatchRows is the number of rows written per :put statement.
+const snapshotBatchRows = 500
+
+// SnapshotResult summarizes a snapshot build.
+type SnapshotResult struct {
+	Seq       int    // Validity of the snapshot rows in the history relations
+	SHA       string // Commit indexed
+	Ref       string // Ref as given
+	Existing  bool   // The commit already had a snapshot; nothing was written
+	Index     *IngestionResult
+	Asserted  int // Rows new or changed since the previous snapshot
+	Retracted int // Rows gone since the previous snapshot
+	Shared    int // Rows unchanged since the previous snapshot, stored once
+
+	TotalDuration time.Duration
+}
+
+// IndexSnapshot builds a snapshot of the index at a commit, next to the
+// index of HEAD, without checking the commit out: the commit's tree is
+// extracted with git archive into a temporary directory, indexed into an
+// in-memory database, and its rows are written to the history relations as
+// a delta from the previous snapshot. Rows of files unchanged between the
+// snapshots are not written again.
+//
+// Embeddings, coverage and git history are not part of snapshots. The HEAD
+// index and ProjectMeta.LastIndexedSHA are left untouched. A commit that
+// already has a snapshot is not indexed again.
+func (p *LocalPipeline) IndexSnapshot(ctx context.Context, ref string) (*SnapshotResult, error) {
+	startTime := time.Now()
+	if p.config.RepoSource.Type != "local_path" {
+		return nil, fmt.Errorf("snapshots need a local repository, got %s", p.config.RepoSource.Type)
+	}
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid git ref %q", ref)
+	}
+	repoPath, err := filepath.Abs(p.config.RepoSource.Value)
+	if err != nil {
+		return nil, fmt.Errorf("resolve repository path: %w", err)
+	}
+	git, err := tools.NewGitExecutor(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	out, err := git.Run(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown commit %q", ref)
+	}
+	sha := strings.TrimSpace(out)
+
+	snapshots, err := tools.ListSnapshots(ctx, tools.NewEmbeddedQuerier(p.backend))
+	if err != nil {
+		return nil, err
+	}
+	seq := 1
+	for _, s := range snapshots {
+		if s.SHA == sha {
+			return &SnapshotResult{Seq: s.Seq, SHA: sha, Ref: s.Ref, Existing: true, TotalDuration: time.Since(startTime)}, nil
+		}
+		seq = max(seq, s.Seq+1)
+	}
+	p.logger.Info("snapshot.start", "ref", ref, "sha", sha[:min(8, len(sha))], "seq", seq)
+
+	tmpDir, err := os.MkdirTemp("", "cie-snapshot-")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// Index the tree of the commit under the same relative paths as HEAD
+	prefix, err := filepath.Rel(git.RepoPath(), repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolve repository path: %w", err)
+	}
+	treeDir := filepath.Join(tmpDir, "tree")
+	if err := exportTree(ctx, git.RepoPath(), sha, filepath.ToSlash(prefix), treeDir); err != nil {
+		return nil, err
+	}
+
+	config := p.config
+	config.RepoSource = RepoSource{Type: "local_path", Value: treeDir}
+	config.IngestionConfig.LocalEngine = "mem"
+	config.IngestionConfig.LocalDataDir = filepath.Join(tmpDir, "db")
+	config.IngestionConfig.CheckpointPath = ""
+	config.IngestionConfig.ForceReindex = true
+	// Snapshots keep no embeddings: use the mock provider, not the configured one
+	config.IngestionConfig.EmbeddingProvider = "mock"
+	config.IngestionConfig.EmbeddingDimensions = mockEmbeddingDimensions
+	sub, err := NewLocalPipeline(config, p.logger)
+	if err != nil {
+		return nil, fmt.Errorf("create snapshot pipeline: %w", err)
+	}
+	defer func() { _ = sub.Close() }()
+	sub.SetProgressCallback(p.onProgress)
+
+	index, err := sub.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("index %s: %w", ref, err)
+	}
+
+	result := &SnapshotResult{Seq: seq, SHA: sha, Ref: ref, Index: index}
+	if err := p.writeSnapshot(ctx, sub.backend, seq, result); err != nil {
+		return nil, err
+	}
+	catalog := fmt.Sprintf(`?[seq, sha, ref, indexed_at, files, functions] <- [[%d, %s, %s, %d, %d, %d]]
+:put cie_snapshot { seq => sha, ref, indexed_at, files, functions }`,
+		seq, quoteString(sha), quoteString(ref), time.Now().Unix(), index.FilesProcessed, index.FunctionsExtracted)
+	if err := p.backend.Execute(ctx, catalog); err != nil {
+		return nil, fmt.Errorf("write snapshot catalog: %w", err)
+	}
+
+	result.TotalDuration = time.Since(startTime)
+	p.logger.Info("snapshot.complete",
+		"sha", sha[:min(8, len(sha))],
+		"seq", seq,
+		"asserted", result.Asserted,
+		"retracted", result.Retracted,
+		"shared", result.Shared,
+		"duration_ms", result.TotalDuration.Milliseconds(),
+	)
+	return result, nil
+}
+
+// writeSnapshot writes the relations indexed in src to the history
+// relations at validity seq: rows new or changed since snapshot seq-1 are
+// asserted, rows gone are retracted, unchanged rows stay as they are.
+func (p *LocalPipeline) writeSnapshot(ctx context.Context, src *storage.EmbeddedBackend, seq int, result *SnapshotResult) error {
+	for _, rel := range storage.HistoryRelations() {
+		cols := strings.Join(rel.Columns(), ", ")
+		current, err := src.Query(ctx, fmt.Sprintf(`?[%s] := *%s { %s }`, cols, rel.Name, cols))
+		if err != nil {
+			return fmt.Errorf("read %s: %w", rel.Name, err)
+		}
+		var previous [][]any
+		if seq > 1 {
+			prev, err := p.backend.Query(ctx, fmt.Sprintf(`?[%s] := *%s { %s @ %d }`, cols, rel.History(), cols, seq-1))
+			if err != nil {
+				return fmt.Errorf("read %s: %w", rel.History(), err)
+			}
+			previous = prev.Rows
+		}
+
+		asserted, retracted, shared := snapshotDelta(len(rel.Keys), previous, current.Rows)
+		result.Asserted += len(asserted)
+		result.Retracted += len(retracted)
+		result.Shared += shared
+		for _, batch := range []struct {
+			rows   [][]any
+			assert bool
+		}{{asserted, true}, {retracted, false}} {
+			for start := 0; start < len(batch.rows); start += snapshotBatchRows {
+				rows := batch.rows[start:min(start+snapshotBatchRows, len(batch.rows))]
+				if err := p.backend.Execute(ctx, buildHistoryPut(rel, rows, seq, batch.assert)); err != nil {
+					return fmt.Errorf("write %s: %w", rel.History(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotDelta compares the rows of a relation at the previous snapshot
+// with its rows at the new one, the first keyCols columns being the key. It
+// returns the rows to assert (new or changed), the previous rows to retract
+// (key gone) and the number of unchanged rows.
+func snapshotDelta(keyCols int, previous, current [][]any) (asserted, retracted [][]any, shared int) {
+	before := make(map[string]string, len(previous))
+	previousByKey := make(map[string][]any, len(previous))
+	for _, row := range previous {
+		key := formatCozoRow(row[:keyCols])
+		before[key] = formatCozoRow(row)
+		previousByKey[key] = row
+	}
+	for _, row := range current {
+		key := formatCozoRow(row[:keyCols])
+		if old, ok := before[key]; ok {
+			delete(previousByKey, key)
+			if old == formatCozoRow(row) {
+				shared++
+				continue
+			}
+		}
+		asserted = append(asserted, row)
+	}
+	for _, row := range previous {
+		if _, gone := previousByKey[formatCozoRow(row[:keyCols])]; gone {
+			retracted = append(retracted, row)
+		}
+	}
+	return asserted, retracted, shared
+}
+
+// buildHistoryPut builds the :put of rows into the history relation of rel,
+// asserted or retracted at validity seq.
+func buildHistoryPut(rel storage.HistoryRelation, rows [][]any, seq int, assert bool) string {
+	validity := fmt.Sprintf("[%d, %t]", seq, assert)
+	keys := len(rel.Keys)
+
+	var sb strings.Builder
+	head := append(append(append([]string{}, rel.Keys...), storage.ValidityColumn), rel.Values...)
+	sb.WriteString("?[" + strings.Join(head, ", ") + "] <- [")
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(",\n")
+		}
+		values := make([]string, 0, len(row)+1)
+		for j, v := range row {
+			if j == keys {
+				values = append(values, validity)
+			}
+			values = append(values, formatCozoValue(v))
+		}
+		if len(row) == keys {
+			values = append(values, validity)
+		}
+		sb.WriteString("[" + strings.Join(values, ", ") + "]")
+	}
+	sb.WriteString("]\n:put " + rel.History() + " { " + strings.Join(rel.Keys, ", ") + ", " + storage.ValidityColumn)
+	if len(rel.Values) > 0 {
+		sb.WriteString(" => " + strings.Join(rel.Values, ", "))
+	}
+	sb.WriteString(" }")
+	return sb.String()
+}
+
+// formatCozoRow formats a query row as a CozoScript list literal.
+func formatCozoRow(row []any) string {
+	values := make([]string, len(row))
+	for i, v := range row {
+		values[i] = formatCozoValue(v)
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+// formatCozoValue formats a value read from CozoDB as a CozoScript literal.
+func formatCozoValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quoteString(val)
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case []any:
+		return formatCozoRow(val)
+	default:
+		return quoteString(fmt.Sprint(val))
+	}
+}
+
+// exportTree extracts the tree of a commit, or its subdirectory prefix, into
+// dir with git archive. Only regular files are extracted.
+func exportTree(ctx context.Context, gitRoot, sha, prefix, dir string) error {
+	treeish := sha
+	if prefix != "" && prefix != "." {
+		treeish += ":" + prefix
+	}
+	cmd := exec.CommandContext(ctx, "git", "archive", "--format=tar", treeish)
+	cmd.Dir = gitRoot
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("git archive: %w", err)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("git archive: %w", err)
+	}
+
+	extractErr := extractTar(stdout, dir)
+	_, _ = io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git archive %s: %s", treeish, strings.TrimSpace(stderr.String()))
+	}
+	return extractErr
+}
+
+// extractTar writes the regular files of a tar stream under dir.
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !filepath.IsLocal(hdr.Name) {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return fmt.Errorf("extract %s: %w", hdr.Name, err)
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600) //nolint:gosec // G304: target is a local path under dir
+		if err != nil {
+			return fmt.Errorf("extract %s: %w", hdr.Name, err)
+		}
+		_, err = io.Copy(f, tr) //nolint:gosec // G110: archive of the repository's own commit
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("extract %s: %w", hdr.Name, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+//go:build cgo
+
+package ingestion
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kraklabs/cie/pkg/tools"
+)
+
+// TestIndexSnapshot_Integration indexes HEAD, then snapshots of an older
+// tag and of HEAD, and checks that each snapshot answers queries as of its
+// commit while the HEAD index is unchanged.
+func TestIndexSnapshot_Integration(t *testing.T) {
+	testDir := t.TempDir()
+	repoDir := filepath.Join(testDir, "testrepo")
+	dataDir := filepath.Join(testDir, "data")
+
+	runGit(t, testDir, "init", "testrepo")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	writeFile(t, filepath.Join(repoDir, "main.go"), `package main
+
+func main() {
+	Hello()
+}
+`)
+	writeFile(t, filepath.Join(repoDir, "hello.go"), `package main
+
+func Hello() {
+	println("hello")
+}
+`)
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "v1")
+	runGit(t, repoDir, "tag", "v1")
+
+	// v2 renames Hello and keeps main.go's function untouched
+	writeFile(t, filepath.Join(repoDir, "hello.go"), `package main
+
+func Greet() {
+	println("hello")
+}
+`)
+	writeFile(t, filepath.Join(repoDir, "main.go"), `package main
+
+func main() {
+	Greet()
+}
+`)
+	runGit(t, repoDir, "commit", "-am", "v2")
+
+	cfg := Config{
+		ProjectID:  "test-snapshot",
+		RepoSource: RepoSource{Type: "local_path", Value: repoDir},
+		IngestionConfig: IngestionConfig{
+			LocalDataDir:        dataDir,
+			LocalEngine:         "mem",
+			EmbeddingProvider:   "mock",
+			EmbeddingDimensions: 384,
+			MaxFileSizeBytes:    1048576,
+			ExcludeGlobs:        []string{".git/**"},
+			Concurrency:         ConcurrencyConfig{ParseWorkers: 1, EmbedWorkers: 1},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	pipeline, err := NewLocalPipeline(cfg, logger)
+	if err != nil {
+		t.Fatalf("failed to create pipeline: %v", err)
+	}
+	defer func() { _ = pipeline.Close() }()
+
+	ctx := context.Background()
+	if _, err := pipeline.Run(ctx); err != nil {
+		t.Fatalf("index HEAD: %v", err)
+	}
+
+	v1, err := pipeline.IndexSnapshot(ctx, "v1")
+	if err != nil {
+		t.Fatalf("snapshot v1: %v", err)
+	}
+	if v1.Seq != 1 || v1.Existing || v1.Asserted == 0 {
+		t.Errorf("snapshot v1 = %+v", v1)
+	}
+	head, err := pipeline.IndexSnapshot(ctx, "HEAD")
+	if err != nil {
+		t.Fatalf("snapshot HEAD: %v", err)
+	}
+	if head.Seq != 2 || head.Shared == 0 || head.Retracted == 0 {
+		t.Errorf("snapshot HEAD should share unchanged rows and retract Hello: %+v", head)
+	}
+	again, err := pipeline.IndexSnapshot(ctx, "v1")
+	if err != nil || !again.Existing || again.Seq != 1 {
+		t.Errorf("snapshot v1 again = %+v, %v", again, err)
+	}
+
+	client := tools.NewEmbeddedQuerier(pipeline.backend)
+	for _, tc := range []struct {
+		client tools.Querier
+		want   string
+	}{
+		{tools.NewSnapshotQuerier(client, v1.Seq), "Hello"},
+		{tools.NewSnapshotQuerier(client, head.Seq), "Greet"},
+		{client, "Greet"},
+	} {
+		result, err := tc.client.Query(ctx, `?[callee] := *cie_function{id: caller, name: "main"}, *cie_calls{caller_id: caller, callee_id}, *cie_function{id: callee_id, name: callee}`)
+		if err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		if len(result.Rows) != 1 || result.Rows[0][0] != tc.want {
+			t.Errorf("main calls %v, want %s", result.Rows, tc.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kraklabs/cie/pkg/storage"
+)
+
+func TestSnapshotDelta(t *testing.T) {
+	previous := [][]any{
+		{"f1", "Get", float64(10)},
+		{"f2", "Put", float64(20)},
+		{"f3", "Del", float64(30)},
+	}
+	current := [][]any{
+		{"f1", "Get", float64(10)},
+		{"f2", "Put", float64(21)},
+		{"f4", "Scan", float64(40)},
+	}
+	asserted, retracted, shared := snapshotDelta(1, previous, current)
+	assert.Equal(t, [][]any{{"f2", "Put", float64(21)}, {"f4", "Scan", float64(40)}}, asserted)
+	assert.Equal(t, [][]any{{"f3", "Del", float64(30)}}, retracted)
+	assert.Equal(t, 1, shared)
+
+	asserted, retracted, shared = snapshotDelta(1, nil, current)
+	assert.Len(t, asserted, 3)
+	assert.Empty(t, retracted)
+	assert.Zero(t, shared)
+}
+
+func TestBuildHistoryPut(t *testing.T) {
+	rel := storage.HistoryRelation{Name: "cie_calls", Keys: []string{"id"}, Values: []string{"caller_id", "callee_id", "call_line"}}
+	script := buildHistoryPut(rel, [][]any{{"c1", "f1", "it's", float64(12)}}, 3, true)
+	assert.Equal(t, `?[id, valid_at, caller_id, callee_id, call_line] <- [['c1', [3, true], 'f1', 'it\'s', 12]]
+:put cie_hist_calls { id, valid_at => caller_id, callee_id, call_line }`, script)
+
+	rel = storage.HistoryRelation{Name: "cie_pair", Keys: []string{"a", "b"}}
+	script = buildHistoryPut(rel, [][]any{{"x", "y"}, {"x", "z"}}, 4, false)
+	assert.Equal(t, `?[a, b, valid_at] <- [['x', 'y', [4, false]],
+['x', 'z', [4, false]]]
+:put cie_hist_pair { a, b, valid_at }`, script)
+}
+
+func TestExtractTar(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range []struct {
+		name, body string
+		typ        byte
+	}{
+		{"pkg/", "", tar.TypeDir},
+		{"pkg/a.go", "package pkg\n", tar.TypeReg},
+		{"../escape.go", "package evil\n", tar.TypeReg},
+		{"link.go", "", tar.TypeSymlink},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: f.name, Typeflag: f.typ, Mode: 0644, Size: int64(len(f.body)), Linkname: "pkg/a.go"}))
+		_, err := tw.Write([]byte(f.body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	dir := filepath.Join(t.TempDir(), "tree")
+	require.NoError(t, extractTar(&buf, dir))
+	content, err := os.ReadFile(filepath.Join(dir, "pkg", "a.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package pkg\n", string(content))
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(dir), "escape.go"))
+	assert.NoFileExists(t, filepath.Join(dir, "link.go"))
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"regexp"
+	"strings"
+)
+
+// =============================================================================
+// TABLE ACCESS EXTRACTION
+// =============================================================================
+
+// Table access values stored in cie_table_access.
+const (
+	TableAccessRead  = "read"
+	TableAccessWrite = "write"
+)
+
+var (
+	// dbCallPattern gates SQL literal scanning: a function must call a database
+	// API for its SQL-looking strings to count (database/sql, sqlx, GORM Raw,
+	// DB-API cursors, node-postgres/mysql, Laravel DB facade, SQLAlchemy text()).
+	dbCallPattern = regexp.MustCompile(`(?i)(?:\.|::|->)\s*(?:query|queryrow|queryx|queryrowx|exec|mustexec|prepare|preparex|select|get|namedexec|namedquery|raw|execute|executemany|statement|insert|update|delete|unprepared)(?:context)?\s*\(|\btext\s*\(`)
+
+	// sqlStatementPattern recognizes string literals that are SQL statements.
+	sqlStatementPattern = regexp.MustCompile(`(?is)^\s*\(?\s*(?:SELECT\b.*\bFROM\b|INSERT\s+(?:IGNORE\s+)?INTO\b|UPDATE\s+\S+.*\bSET\b|DELETE\s+FROM\b|WITH\b.*\bAS\s*\(|MERGE\s+INTO\b|REPLACE\s+INTO\b|TRUNCATE\b|UPSERT\s+INTO\b)`)
+
+	sqlWriteTargetPattern = regexp.MustCompile(`(?is)\b(?:INSERT\s+(?:IGNORE\s+)?INTO|REPLACE\s+INTO|UPSERT\s+INTO|MERGE\s+INTO|DELETE\s+FROM|TRUNCATE(?:\s+TABLE)?|UPDATE(?:\s+ONLY)?)\s+(` + sqlIdentPattern + `)`)
+	sqlReadSourcePattern  = regexp.MustCompile(`(?is)(DELETE\s+)?\b(?:FROM|JOIN|USING)\s+(?:ONLY\s+|LATERAL\s+)?(` + sqlIdentPattern + `)`)
+	sqlCTENamePattern     = regexp.MustCompile(`(?is)(?:\bWITH(?:\s+RECURSIVE)?|,)\s*(` + sqlIdentPattern + `)\s*(?:\([^)]*\)\s*)?AS\s*(?:(?:NOT\s+)?MATERIALIZED\s*)?\(`)
+
+	// ormContextPattern gates ORM scanning: a function must use a query
+	// builder receiver (db./tx./trx., knex, Laravel DB facade) for its table
+	// selectors to count.
+	ormContextPattern = regexp.MustCompile(`(?i)\b(?:db|tx|trx)\s*\.|\bknex\b|\bDB::`)
+
+	// ORM table calls: GORM db.Table("orders"), Laravel DB::table('orders'),
+	// knex('orders') / knex.from('orders') / db.into('orders'), and
+	// .select(...).from('orders') / .insert(...).into('orders') chains.
+	// A bare .from or .into is not enough: Buffer.from('x') and Array.from('x')
+	// are not queries.
+	ormTablePattern = regexp.MustCompile(`(?:\.Table|DB::table|\bknex(?:\.(?:from|into|table))?|\b(?:db|trx)\.(?:from|into|table)|\.(?:select|insert)\([^()]*\)\s*\.(?:from|into))\s*\(\s*["'` + "`" + `]([\w.]+)["'` + "`" + `]`)
+	ormWritePattern = regexp.MustCompile(`(?i)^\s*(?:\.|->)\s*(?:create|createinbatches|save|update|updates|updatecolumn|updatecolumns|delete|insert|insertgetid|insertorignore|upsert|updateorinsert|increment|decrement|truncate|del)\s*\(`)
+)
+
+// sqlNonTableWords are keywords that can follow FROM/JOIN/UPDATE in valid SQL
+// without naming a table (SELECT ... FOR UPDATE, ON CONFLICT DO UPDATE SET).
+var sqlNonTableWords = map[string]bool{
+	"set": true, "of": true, "skip": true, "nowait": true, "select": true,
+	"where": true, "values": true, "dual": true, "unnest": true, "lateral": true,
+	"only": true, "table": true,
+}
+
+// extractTableAccesses finds the SQL tables each function reads or writes.
+//
+// Two sources are scanned in the function's CodeText:
+//   - SQL string literals (adjacent literals joined by + or . are concatenated),
+//     only in functions that call a database API
+//   - ORM table selectors (GORM Table, Laravel DB::table, knex), only in
+//     functions that use a query builder, classified as writes when the call
+//     is an insert ... into or the chained call is create/save/update/delete/insert
+//
+// Table names are normalized like DDL identifiers (lowercase, no schema) so
+// they join against cie_sql_table. Each (function, table, access) is reported once.
+func extractTableAccesses(functions []FunctionEntity) []TableAccessEdge {
+	var edges []TableAccessEdge
+
+	for _, fn := range functions {
+		code := fn.CodeText
+		if code == "" {
+			continue
+		}
+		seen := make(map[string]bool)
+		add := func(table, access string, offset int) {
+			if table == "" || sqlNonTableWords[table] || seen[table+"|"+access] {
+				return
+			}
+			seen[table+"|"+access] = true
+			edges = append(edges, TableAccessEdge{
+				FunctionID: fn.ID,
+				TableName:  table,
+				Access:     access,
+				FilePath:   fn.FilePath,
+				Line:       fn.StartLine + strings.Count(code[:offset], "\n"),
+			})
+		}
+
+		if dbCallPattern.MatchString(code) {
+			for _, lit := range scanSQLStringLiterals(code) {
+				if !sqlStatementPattern.MatchString(lit.text) {
+					continue
+				}
+				for _, ref := range sqlTableReferences(lit.text) {
+					add(ref.table, ref.access, lit.offset)
+				}
+			}
+		}
+
+		if !ormContextPattern.MatchString(code) {
+			continue
+		}
+		for _, m := range ormTablePattern.FindAllStringSubmatchIndex(code, -1) {
+			access := TableAccessRead
+			if strings.Contains(code[m[0]:m[2]], "into") || ormChainWrites(code[m[1]:]) {
+				access = TableAccessWrite
+			}
+			add(normalizeSQLIdent(code[m[2]:m[3]]), access, m[0])
+		}
+	}
+
+	return edges
+}
+
+// sqlTableRef is a table referenced by a SQL statement.
+type sqlTableRef struct {
+	table  string
+	access string
+}
+
+// sqlTableReferences returns the tables a SQL statement writes (INSERT INTO,
+// UPDATE, DELETE FROM, MERGE INTO, TRUNCATE) and reads (FROM, JOIN, USING).
+// Common table expression names and table functions are skipped.
+func sqlTableReferences(sql string) []sqlTableRef {
+	var refs []sqlTableRef
+
+	ctes := make(map[string]bool)
+	for _, m := range sqlCTENamePattern.FindAllStringSubmatch(sql, -1) {
+		ctes[normalizeSQLIdent(m[1])] = true
+	}
+	isTable := func(name string, end int) bool {
+		if name == "" || ctes[name] || sqlNonTableWords[name] {
+			return false
+		}
+		// Table functions and EXTRACT(x FROM col) are followed by a parenthesis
+		rest := strings.TrimLeft(sql[end:], " \t\r\n")
+		return !strings.HasPrefix(rest, "(") && !strings.HasPrefix(rest, ")")
+	}
+
+	for _, m := range sqlWriteTargetPattern.FindAllStringSubmatch(sql, -1) {
+		// No parenthesis check: INSERT INTO t (cols) is followed by "("
+		name := normalizeSQLIdent(m[1])
+		if name != "" && !ctes[name] && !sqlNonTableWords[name] {
+			refs = append(refs, sqlTableRef{table: name, access: TableAccessWrite})
+		}
+	}
+	for _, m := range sqlReadSourcePattern.FindAllStringSubmatchIndex(sql, -1) {
+		if m[2] >= 0 {
+			continue // DELETE FROM target is a write
+		}
+		name := normalizeSQLIdent(sql[m[4]:m[5]])
+		if isTable(name, m[5]) {
+			refs = append(refs, sqlTableRef{table: name, access: TableAccessRead})
+		}
+	}
+
+	return refs
+}
+
+// ormChainWrites reports whether the method chain following an ORM table
+// selector performs a write: DB::table('orders')->insert([...]).
+func ormChainWrites(rest string) bool {
+	// Skip the selector's closing parenthesis, then follow the chain
+	depth := 1
+	for i := 0; i < len(rest) && i < 2000; i++ {
+		switch rest[i] {
+		case '(':
+			depth++
+			continue
+		case ')':
+			depth--
+		case ';':
+			return false
+		default:
+			continue
+		}
+		if depth == 0 {
+			if ormWritePattern.MatchString(rest[i+1:]) {
+				return true
+			}
+			// Continue only while the chain continues: ")->where(...)" or ").Where(...)"
+			next := strings.TrimLeft(rest[i+1:], " \t\r\n")
+			if !strings.HasPrefix(next, ".") && !strings.HasPrefix(next, "->") {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// sqlLiteral is a string literal (or concatenation of literals) found in code.
+type sqlLiteral struct {
+	text   string
+	offset int
+}
+
+// scanSQLStringLiterals returns the string literals in code, joining literals
+// separated only by concatenation operators ("SELECT ..." + "FROM orders").
+// Handles "...", '...', `...` and Python triple-quoted strings. Comments are
+// not recognized; a quote inside a comment may desynchronize the scan for
+// the rest of that line only, since unterminated literals end at a newline.
+func scanSQLStringLiterals(code string) []sqlLiteral {
+	var literals []sqlLiteral
+	lastEnd := -1
+
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if c != '"' && c != '\'' && c != '`' {
+			continue
+		}
+
+		var text string
+		var end int
+		switch {
+		case c != '`' && strings.HasPrefix(code[i:], strings.Repeat(string(c), 3)):
+			delim := strings.Repeat(string(c), 3)
+			close := strings.Index(code[i+3:], delim)
+			if close < 0 {
+				return literals
+			}
+			text = code[i+3 : i+3+close]
+			end = i + 3 + close + 3
+		case c == '`':
+			close := strings.IndexByte(code[i+1:], '`')
+			if close < 0 {
+				return literals
+			}
+			text = code[i+1 : i+1+close]
+			end = i + 1 + close + 1
+		default:
+			j := i + 1
+			for j < len(code) && code[j] != c && code[j] != '\n' {
+				if code[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(code) || code[j] != c {
+				continue
+			}
+			text = code[i+1 : j]
+			end = j + 1
+		}
+
+		if lastEnd >= 0 && len(literals) > 0 && strings.Trim(code[lastEnd:i], " \t\r\n+.") == "" {
+			literals[len(literals)-1].text += text
+		} else {
+			literals = append(literals, sqlLiteral{text: text, offset: i})
+		}
+		lastEnd = end
+		i = end - 1
+	}
+
+	return literals
+}
@@ -0,0 +1,133 @@
+package ingestion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tableAccessSet returns "table:access" keys for the edges of one function.
+func tableAccessSet(edges []TableAccessEdge) map[string]int {
+	set := make(map[string]int)
+	for _, e := range edges {
+		set[e.TableName+":"+e.Access] = e.Line
+	}
+	return set
+}
+
+func TestExtractTableAccesses_GoDatabaseSQL(t *testing.T) {
+	fn := FunctionEntity{
+		ID:        "fn:create",
+		FilePath:  "internal/store/orders.go",
+		StartLine: 10,
+		CodeText: "func (s *Store) CreateOrder(ctx context.Context, o Order) error {\n" +
+			"\tvar exists bool\n" +
+			"\tif err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM public.users u WHERE u.id = $1)`, o.UserID).Scan(&exists); err != nil {\n" +
+			"\t\treturn err\n" +
+			"\t}\n" +
+			"\t_, err := s.db.ExecContext(ctx, \"INSERT INTO orders (id, user_id) \" +\n" +
+			"\t\t\"VALUES ($1, $2)\", o.ID, o.UserID)\n" +
+			"\treturn err\n" +
+			"}",
+	}
+
+	got := tableAccessSet(extractTableAccesses([]FunctionEntity{fn}))
+	assert.Equal(t, map[string]int{"users:read": 12, "orders:write": 15}, got)
+}
+
+func TestExtractTableAccesses_SQLStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want map[string]bool
+	}{
+		{"update with join", "UPDATE orders o SET status = 'paid' FROM payments p WHERE p.order_id = o.id",
+			map[string]bool{"orders:write": true, "payments:read": true}},
+		{"delete", "DELETE FROM sessions WHERE expires_at < now()",
+			map[string]bool{"sessions:write": true}},
+		{"cte", "WITH recent AS (SELECT * FROM orders WHERE created_at > $1) SELECT * FROM recent JOIN users ON users.id = recent.user_id",
+			map[string]bool{"orders:read": true, "users:read": true}},
+		{"upsert", "INSERT INTO counters (k, v) VALUES ($1, 1) ON CONFLICT (k) DO UPDATE SET v = counters.v + 1",
+			map[string]bool{"counters:write": true}},
+		{"for update and extract", "SELECT EXTRACT(YEAR FROM created_at) FROM `Orders` FOR UPDATE SKIP LOCKED",
+			map[string]bool{"orders:read": true}},
+		{"insert select", "INSERT INTO archive SELECT * FROM orders",
+			map[string]bool{"archive:write": true, "orders:read": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]bool)
+			for _, ref := range sqlTableReferences(tt.sql) {
+				got[ref.table+":"+ref.access] = true
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExtractTableAccesses_PythonAndORM(t *testing.T) {
+	functions := []FunctionEntity{
+		{
+			ID: "fn:py", FilePath: "app/repo.py", StartLine: 1,
+			CodeText: "def load(cur, user_id):\n" +
+				"    cur.execute(\"\"\"\n        SELECT id FROM orders\n        WHERE user_id = %s\n    \"\"\", (user_id,))\n" +
+				"    return cur.fetchall()",
+		},
+		{
+			ID: "fn:gorm", FilePath: "internal/repo.go", StartLine: 1,
+			CodeText: "func (r *Repo) Archive(id int) error {\n" +
+				"\treturn r.db.Table(\"orders\").Where(\"id = ?\", id).Delete(&Order{}).Error\n}",
+		},
+		{
+			ID: "fn:laravel", FilePath: "app/Repo.php", StartLine: 1,
+			CodeText: "public function all() {\n    return DB::table('orders')\n        ->where('paid', true)\n        ->get();\n}",
+		},
+		{
+			// SQL-looking text without a database call is not an access
+			ID: "fn:log", FilePath: "cmd/help.go", StartLine: 1,
+			CodeText: "func usage() {\n\tfmt.Println(\"select a file from the list\")\n}",
+		},
+	}
+
+	byFunc := make(map[string][]TableAccessEdge)
+	for _, e := range extractTableAccesses(functions) {
+		byFunc[e.FunctionID] = append(byFunc[e.FunctionID], e)
+	}
+
+	assert.Equal(t, map[string]int{"orders:read": 2}, tableAccessSet(byFunc["fn:py"]))
+	assert.Equal(t, map[string]int{"orders:write": 2}, tableAccessSet(byFunc["fn:gorm"]), "GORM chain ending in Delete is a write")
+	assert.Equal(t, map[string]int{"orders:read": 2}, tableAccessSet(byFunc["fn:laravel"]))
+	assert.Empty(t, byFunc["fn:log"])
+}
+
+func TestExtractTableAccesses_Knex(t *testing.T) {
+	functions := []FunctionEntity{
+		{
+			ID: "fn:knex", FilePath: "src/repo.js", StartLine: 1,
+			CodeText: "async function archive(id) {\n" +
+				"  const rows = await knex.select('*').from('orders').where({ id });\n" +
+				"  await knex.insert(rows).into('orders_archive');\n" +
+				"  return knex('users').where({ id }).first();\n}",
+		},
+		{
+			// Buffer.from and Array.from take strings too but are not queries
+			ID: "fn:buffer", FilePath: "src/encode.js", StartLine: 1,
+			CodeText: "function encode() {\n  const b = Buffer.from('hello');\n  return Array.from('x').concat([b]);\n}",
+		},
+		{
+			// Not even next to a query builder call
+			ID: "fn:mixed", FilePath: "src/mixed.js", StartLine: 1,
+			CodeText: "async function load(db) {\n  const key = Buffer.from('secrets');\n  return db.query('SELECT 1');\n}",
+		},
+	}
+
+	byFunc := make(map[string][]TableAccessEdge)
+	for _, e := range extractTableAccesses(functions) {
+		byFunc[e.FunctionID] = append(byFunc[e.FunctionID], e)
+	}
+
+	assert.Equal(t, map[string]int{"orders:read": 2, "orders_archive:write": 3, "users:read": 4}, tableAccessSet(byFunc["fn:knex"]))
+	assert.Empty(t, byFunc["fn:buffer"])
+	assert.Empty(t, byFunc["fn:mixed"])
+}
@@ -0,0 +1,325 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"path/filepath"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// TEST ENTITIES
+// =============================================================================
+
+// goTestPrefixes maps the name prefixes of Go test functions to their kind.
+var goTestPrefixes = []struct {
+	prefix, kind string
+}{
+	{"Test", "test"},
+	{"Benchmark", "benchmark"},
+	{"Fuzz", "fuzz"},
+	{"Example", "example"},
+}
+
+// isTestFile reports whether a file holds tests by the conventions of its
+// language's test runner: _test.go files, test_*.py and *_test.py modules,
+// and *.test.* / *.spec.* files or files under __tests__/ for JavaScript and
+// TypeScript.
+func isTestFile(path, language string) bool {
+	base := filepath.Base(path)
+	switch language {
+	case "go":
+		return strings.HasSuffix(base, "_test.go")
+	case "python":
+		return strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py")
+	case "javascript", "typescript":
+		return strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+			strings.Contains(filepath.ToSlash(path), "__tests__/")
+	}
+	return false
+}
+
+// goTestKind returns the kind of a Go test function by the go test naming
+// rules (TestXxx, where Xxx does not start with a lower-case letter), or ""
+// for other functions.
+func goTestKind(name string) string {
+	for _, p := range goTestPrefixes {
+		if !strings.HasPrefix(name, p.prefix) {
+			continue
+		}
+		rest := name[len(p.prefix):]
+		if rest == "" || rest[0] < 'a' || rest[0] > 'z' {
+			return p.kind
+		}
+	}
+	return ""
+}
+
+// extractGoTests extracts the test functions of a _test.go file, the test
+// methods of testify suites and their t.Run subtests, nested at any depth.
+// Subtests are named as go test reports them: "TestParse/empty_input".
+func extractGoTests(root *sitter.Node, content []byte, filePath string, functions []FunctionEntity) []TestEntity {
+	if !isTestFile(filePath, "go") {
+		return nil
+	}
+
+	var tests []TestEntity
+	var walk func(node *sitter.Node, parent string)
+	walk = func(node *sitter.Node, parent string) {
+		switch node.Type() {
+		case "function_declaration":
+			nameNode := node.ChildByFieldName("name")
+			if nameNode == nil {
+				return
+			}
+			name := nodeText(nameNode, content)
+			kind := goTestKind(name)
+			if kind == "" {
+				return
+			}
+			tests = append(tests, newTestEntity(filePath, functionIDAt(functions, node), name, kind, "", "go", node))
+			if body := node.ChildByFieldName("body"); body != nil {
+				walk(body, name)
+			}
+			return
+		case "method_declaration":
+			// Test methods of testify suites: func (s *StoreSuite) TestGet()
+			nameNode := node.ChildByFieldName("name")
+			if nameNode == nil || goTestKind(nodeText(nameNode, content)) != "test" {
+				return
+			}
+			functionID := functionIDAt(functions, node)
+			for _, fn := range functions {
+				if fn.ID == functionID {
+					suite, _, _ := strings.Cut(fn.Name, ".")
+					tests = append(tests, newTestEntity(filePath, functionID, fn.Name, "test", suite, "go", node))
+					if body := node.ChildByFieldName("body"); body != nil {
+						walk(body, fn.Name)
+					}
+				}
+			}
+			return
+		case "call_expression":
+			if fn, ok := goSubtestFunc(node, content); ok && parent != "" {
+				args := node.ChildByFieldName("arguments")
+				name := parent + "/" + goSubtestName(args.NamedChild(0), content)
+				tests = append(tests, newTestEntity(filePath, functionIDAt(functions, fn), name, "subtest", parent, "go", node))
+				walk(fn, name)
+				return
+			}
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i), parent)
+		}
+	}
+	walk(root, "")
+	return tests
+}
+
+// goSubtestFunc returns the function literal of a x.Run(name, func(...) {...})
+// call.
+func goSubtestFunc(call *sitter.Node, content []byte) (*sitter.Node, bool) {
+	fn, args := call.ChildByFieldName("function"), call.ChildByFieldName("arguments")
+	if fn == nil || fn.Type() != "selector_expression" || args == nil || args.NamedChildCount() != 2 {
+		return nil, false
+	}
+	if field := fn.ChildByFieldName("field"); field == nil || nodeText(field, content) != "Run" {
+		return nil, false
+	}
+	lit := args.NamedChild(1)
+	if lit.Type() != "func_literal" {
+		return nil, false
+	}
+	return lit, true
+}
+
+// goSubtestName returns the name of a subtest as go test reports it, with
+// spaces replaced by underscores. Names computed at runtime (table-driven
+// tests) are kept as written in braces: "{tt.name}".
+func goSubtestName(arg *sitter.Node, content []byte) string {
+	if s, ok := goStringLiteral(arg, content); ok {
+		return strings.ReplaceAll(s, " ", "_")
+	}
+	return "{" + nodeText(arg, content) + "}"
+}
+
+// extractPythonTests extracts the pytest tests of a test module: test_*
+// functions, Test* classes and their test_* methods.
+func extractPythonTests(filePath string, functions []FunctionEntity, types []TypeEntity) []TestEntity {
+	if !isTestFile(filePath, "python") {
+		return nil
+	}
+
+	var tests []TestEntity
+	suites := make(map[string]bool)
+	for _, t := range types {
+		if strings.HasPrefix(t.Name, "Test") {
+			suites[t.Name] = true
+			tests = append(tests, TestEntity{
+				ID:        GenerateTestID(filePath, t.Name, t.StartLine),
+				Name:      t.Name,
+				Kind:      "suite",
+				Framework: "pytest",
+				FilePath:  filePath,
+				Line:      t.StartLine,
+			})
+		}
+	}
+	for _, fn := range functions {
+		class, method, isMethod := strings.Cut(fn.Name, ".")
+		if !isMethod {
+			class, method = "", fn.Name
+		}
+		if !strings.HasPrefix(method, "test") || strings.Contains(method, ".") || (isMethod && !suites[class]) {
+			continue
+		}
+		tests = append(tests, TestEntity{
+			ID:         GenerateTestID(filePath, fn.Name, fn.StartLine),
+			FunctionID: fn.ID,
+			Name:       fn.Name,
+			Kind:       "test",
+			Suite:      class,
+			Framework:  "pytest",
+			FilePath:   filePath,
+			Line:       fn.StartLine,
+		})
+	}
+	return tests
+}
+
+// jsTestBlocks maps the Jest/Vitest globals to the kind of block they declare.
+var jsTestBlocks = map[string]string{
+	"describe": "suite",
+	"it":       "test",
+	"test":     "test",
+}
+
+// extractJSTests extracts the Jest or Vitest describe blocks and it/test
+// cases of a JavaScript or TypeScript test file, including their .only,
+// .skip and .concurrent variants. Suites are named by their describe path:
+// "UserService > create".
+func extractJSTests(root *sitter.Node, content []byte, filePath, language string, functions []FunctionEntity) []TestEntity {
+	if !isTestFile(filePath, language) {
+		return nil
+	}
+	framework := "jest"
+	if strings.Contains(string(content), `from "vitest"`) || strings.Contains(string(content), `from 'vitest'`) {
+		framework = "vitest"
+	}
+
+	var tests []TestEntity
+	var walk func(node *sitter.Node, suite string)
+	walk = func(node *sitter.Node, suite string) {
+		if node.Type() == "call_expression" {
+			if kind, title, callback, ok := jsTestBlock(node, content); ok {
+				functionID := ""
+				if callback != nil {
+					functionID = functionIDAt(functions, callback)
+				}
+				tests = append(tests, newTestEntity(filePath, functionID, title, kind, suite, framework, node))
+				if kind == "suite" && callback != nil {
+					inner := title
+					if suite != "" {
+						inner = suite + " > " + title
+					}
+					walk(callback, inner)
+				}
+				return
+			}
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i), suite)
+		}
+	}
+	walk(root, "")
+	return tests
+}
+
+// jsTestBlock recognizes describe("title", fn), it("title", fn) and
+// test("title", fn) calls and returns the kind, title and callback.
+func jsTestBlock(call *sitter.Node, content []byte) (kind, title string, callback *sitter.Node, ok bool) {
+	fn, args := call.ChildByFieldName("function"), call.ChildByFieldName("arguments")
+	if fn == nil || args == nil || args.NamedChildCount() == 0 {
+		return "", "", nil, false
+	}
+	if fn.Type() == "member_expression" {
+		property := fn.ChildByFieldName("property")
+		if property == nil {
+			return "", "", nil, false
+		}
+		switch nodeText(property, content) {
+		case "only", "skip", "concurrent", "todo":
+			fn = fn.ChildByFieldName("object")
+		default:
+			return "", "", nil, false
+		}
+	}
+	if fn == nil || fn.Type() != "identifier" {
+		return "", "", nil, false
+	}
+	if kind, ok = jsTestBlocks[nodeText(fn, content)]; !ok {
+		return "", "", nil, false
+	}
+
+	switch arg := args.NamedChild(0); arg.Type() {
+	case "string":
+		title = strings.Trim(nodeText(arg, content), `"'`)
+	case "template_string":
+		title = strings.Trim(nodeText(arg, content), "`")
+	default:
+		return "", "", nil, false
+	}
+	if args.NamedChildCount() > 1 {
+		switch cb := args.NamedChild(1); cb.Type() {
+		case "arrow_function", "function_expression", "function":
+			callback = cb
+		}
+	}
+	return kind, title, callback, true
+}
+
+// functionIDAt returns the ID of the function of functions declared by node,
+// or "" if it is not indexed (e.g., JavaScript function expressions).
+func functionIDAt(functions []FunctionEntity, node *sitter.Node) string {
+	line, col := int(node.StartPoint().Row)+1, int(node.StartPoint().Column)+1
+	for _, fn := range functions {
+		if fn.StartLine == line && fn.StartCol == col {
+			return fn.ID
+		}
+	}
+	return ""
+}
+
+// newTestEntity creates a test entity declared at node.
+func newTestEntity(filePath, functionID, name, kind, suite, framework string, node *sitter.Node) TestEntity {
+	line := int(node.StartPoint().Row) + 1
+	return TestEntity{
+		ID:         GenerateTestID(filePath, name, line),
+		FunctionID: functionID,
+		Name:       name,
+		Kind:       kind,
+		Suite:      suite,
+		Framework:  framework,
+		FilePath:   filePath,
+		Line:       line,
+	}
+}
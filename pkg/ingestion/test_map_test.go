@@ -0,0 +1,142 @@
+package ingestion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseTests parses a source file and returns its tests as
+// "kind name [suite] -> function :line".
+func parseTests(t *testing.T, path, language, content string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+	require.NoError(t, os.WriteFile(fullPath, []byte(content), 0600))
+
+	result, err := NewTreeSitterParser(nil).ParseFile(FileInfo{Path: path, FullPath: fullPath, Size: int64(len(content)), Language: language})
+	require.NoError(t, err)
+
+	names := make(map[string]string)
+	for _, fn := range result.Functions {
+		names[fn.ID] = fn.Name
+	}
+	var got []string
+	for _, e := range result.Tests {
+		assert.Equal(t, GenerateTestID(e.FilePath, e.Name, e.Line), e.ID)
+		assert.NotEmpty(t, e.Framework)
+		got = append(got, fmt.Sprintf("%s %s [%s] -> %s :%d", e.Kind, e.Name, e.Suite, names[e.FunctionID], e.Line))
+	}
+	return got
+}
+
+// TestGoTests tests test, benchmark, fuzz and example functions, testify
+// suite methods and nested t.Run subtests.
+func TestGoTests(t *testing.T) {
+	got := parseTests(t, "store_test.go", "go", `package store
+
+import "testing"
+
+func TestGet(t *testing.T) {
+	t.Run("missing key", func(t *testing.T) {
+		t.Run("empty", func(t *testing.T) {})
+	})
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {})
+	}
+}
+
+func BenchmarkGet(b *testing.B) {}
+
+func FuzzParse(f *testing.F) {}
+
+func ExampleStore_Get() {}
+
+func Testify(t *testing.T) {}
+
+func helper(t *testing.T) {
+	t.Run("not a subtest", func(t *testing.T) {})
+}
+
+func (s *StoreSuite) TestPut() {
+	s.Run("overwrite", func() {})
+}
+`)
+
+	assert.ElementsMatch(t, []string{
+		"test TestGet [] -> TestGet :5",
+		"subtest TestGet/missing_key [TestGet] -> $anon_1 :6",
+		"subtest TestGet/missing_key/empty [TestGet/missing_key] -> $anon_2 :7",
+		"subtest TestGet/{tt.name} [TestGet] -> $anon_3 :10",
+		"benchmark BenchmarkGet [] -> BenchmarkGet :14",
+		"fuzz FuzzParse [] -> FuzzParse :16",
+		"example ExampleStore_Get [] -> ExampleStore_Get :18",
+		"test StoreSuite.TestPut [StoreSuite] -> StoreSuite.TestPut :26",
+		"subtest StoreSuite.TestPut/overwrite [StoreSuite.TestPut] -> $anon_5 :27",
+	}, got)
+
+	assert.Empty(t, parseTests(t, "store.go", "go", "package store\n\nfunc TestHelper() {}\n"))
+}
+
+// TestPythonTests tests pytest functions, Test classes and their methods.
+func TestPythonTests(t *testing.T) {
+	got := parseTests(t, "tests/test_users.py", "python", `import pytest
+
+
+def test_create_user(db):
+    assert create_user(db, "a")
+
+
+class TestUserService:
+    def setup_method(self):
+        self.svc = UserService()
+
+    def test_rename(self):
+        self.svc.rename("b")
+
+
+class Helper:
+    def test_like(self):
+        pass
+`)
+
+	assert.ElementsMatch(t, []string{
+		"suite TestUserService [] ->  :8",
+		"test test_create_user [] -> test_create_user :4",
+		"test TestUserService.test_rename [TestUserService] -> TestUserService.test_rename :12",
+	}, got)
+}
+
+// TestJSTests tests Jest describe blocks, it/test cases and their variants in
+// JavaScript and TypeScript.
+func TestJSTests(t *testing.T) {
+	source := `import { describe, it, expect } from "vitest";
+
+describe("UserService", () => {
+  describe("create", () => {
+    it("stores the user", async () => {
+      await service.create(user);
+    });
+    it.skip("rejects duplicates", () => {});
+  });
+  test("lists users", function () {});
+});
+`
+	want := []string{
+		"suite UserService [] -> $arrow_1 :3",
+		"suite create [UserService] -> $arrow_2 :4",
+		"test stores the user [UserService > create] -> $arrow_3 :5",
+		"test rejects duplicates [UserService > create] -> $arrow_4 :8",
+		"test lists users [UserService] ->  :10",
+	}
+
+	assert.ElementsMatch(t, want, parseTests(t, "src/users.test.ts", "typescript", source))
+	assert.ElementsMatch(t, want, parseTests(t, "src/__tests__/users.js", "javascript", source))
+	assert.Empty(t, parseTests(t, "src/users.ts", "typescript", source))
+}
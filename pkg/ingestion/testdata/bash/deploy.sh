@@ -0,0 +1,23 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+source "$SCRIPT_DIR/lib/common.sh"
+. ./lib/env.sh
+
+build() {
+    log_info "building"
+    go build -o bin/worker ./cmd/worker
+}
+
+function deploy {
+    local target="$1"
+    build
+    ./scripts/migrate.sh --up
+    bash "$SCRIPT_DIR/notify.sh" "$target"
+    kubectl apply -f k8s/
+    worker --once
+}
+
+deploy "${1:-staging}"
+go run ./cmd/cie index
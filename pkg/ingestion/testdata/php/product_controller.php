@@ -0,0 +1,22 @@
+<?php
+
+namespace App\Controller;
+
+use App\Services\UserService;
+use Symfony\Component\Routing\Attribute\Route;
+
+#[Route('/api')]
+final class ProductController extends AbstractController
+{
+    public function __construct(
+        private readonly UserService $users,
+    ) {
+    }
+
+    #[Route('/products/{id}', name: 'product_show', methods: ['GET', 'HEAD'])]
+    public function show(int $id): Response
+    {
+        $this->users->create('viewer');
+        return $this->json(['id' => $id]);
+    }
+}
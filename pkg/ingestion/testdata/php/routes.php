@@ -0,0 +1,11 @@
+<?php
+
+use App\Http\Controllers\UserController;
+use Illuminate\Support\Facades\Route;
+
+Route::get('/users', [UserController::class, 'index']);
+Route::post('users', 'UserController@store');
+
+Route::get('/health', function () {
+    return response()->json(['ok' => true]);
+});
@@ -0,0 +1,57 @@
+<?php
+
+namespace App\Services;
+
+use App\Repositories\UserRepository;
+use App\Models\User;
+use App\Support\Str as StrHelper;
+use function App\Support\format_name;
+
+interface ServiceInterface
+{
+    public function create(string $name): User;
+}
+
+trait Loggable
+{
+    public function log(string $message): void
+    {
+        error_log($message);
+    }
+}
+
+class UserService implements ServiceInterface
+{
+    use Loggable;
+
+    private UserRepository $repo;
+
+    public function __construct(UserRepository $repo, private ?Mailer $mailer = null)
+    {
+        $this->repo = $repo;
+    }
+
+    public function create(string $name): User
+    {
+        $this->validate($name);
+        $user = new User(format_name($name));
+        $this->repo->save($user);
+        $this->log("created " . StrHelper::slug($name));
+        return $user;
+    }
+
+    private function validate(string $name): bool
+    {
+        return normalize($name) !== '';
+    }
+
+    public static function make(): static
+    {
+        return new static(new UserRepository());
+    }
+}
+
+function normalize(string $value): string
+{
+    return trim($value);
+}
@@ -0,0 +1,47 @@
+"""Sample class hierarchy with self, super() and parameter dispatch."""
+from abc import ABC, abstractmethod
+
+
+class Repository(ABC):
+    @abstractmethod
+    def save(self, item):
+        ...
+
+    def log(self, msg):
+        pass
+
+
+class Base:
+    def setup(self):
+        pass
+
+    def greet(self):
+        pass
+
+
+class Mixin:
+    def greet(self):
+        pass
+
+
+class SqlRepository(Repository, Mixin):
+    def save(self, item):
+        self.log("saving")
+        super().greet()
+
+
+class MemoryRepository(Repository, metaclass=Meta):
+    def save(self, item):
+        self.save_all()
+
+    def save_all(self):
+        pass
+
+
+class Service(Base, Mixin):
+    def run(self, repo: Repository, name: str = "x"):
+        self.setup()
+        self.greet()
+        super().greet()
+        repo.save(name)
+        name.upper()
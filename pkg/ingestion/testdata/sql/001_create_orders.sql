@@ -0,0 +1,35 @@
+-- Orders schema; statements in comments are ignored: CREATE TABLE ignored (id int);
+CREATE TABLE IF NOT EXISTS public."Users" (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    display_name TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+/* Multi-line comment;
+   with a semicolon */
+CREATE TABLE orders (
+    id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    total NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(32),
+    note TEXT DEFAULT 'pending; review',
+    CONSTRAINT orders_pkey PRIMARY KEY (id),
+    CONSTRAINT orders_status_check CHECK (status IN ('new', 'paid'))
+);
+
+CREATE UNIQUE INDEX idx_orders_user_status ON orders USING btree (user_id, status);
+CREATE INDEX ON orders (lower(status));
+
+CREATE FUNCTION touch_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE orders
+    ADD COLUMN coupon_id BIGINT,
+    ADD CONSTRAINT fk_orders_coupon FOREIGN KEY (coupon_id) REFERENCES coupons (id);
+
+INSERT INTO orders (id, user_id) VALUES (1, 1);
@@ -0,0 +1,9 @@
+CREATE TABLE `order_items` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `order_id` bigint NOT NULL,
+  `key` varchar(64) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_order_key` (`order_id`,`key`),
+  KEY `idx_order` (`order_id`),
+  CONSTRAINT `fk_items_order` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,39 @@
+resource "aws_ecs_task_definition" "worker" {
+  family = "orders-worker"
+
+  # Images are pushed by CI; "{" in comments must not break brace matching
+  container_definitions = jsonencode([
+    {
+      name    = "worker"
+      image   = "ghcr.io/acme/orders-worker:${var.tag}"
+      command = ["/app/orders-worker", "--concurrency", "8"]
+      portMappings = [{ containerPort = 9090 }]
+      environment = [
+        { name = "LOG_LEVEL", value = "warn" },
+      ]
+      secrets = [
+        { name = "DATABASE_URL", valueFrom = aws_secretsmanager_secret.db.arn },
+      ]
+    }
+  ])
+}
+
+resource "aws_ecs_service" "worker" {
+  name            = "orders-worker"
+  task_definition = aws_ecs_task_definition.worker.arn
+  desired_count   = 2
+}
+
+resource "aws_lambda_function" "reporter" {
+  function_name = "reporter"
+  policy        = <<EOT
+{ "Statement": [ }
+EOT
+
+  environment {
+    variables = {
+      REPORT_BUCKET = "acme-reports"
+      STAGE         = var.stage
+    }
+  }
+}
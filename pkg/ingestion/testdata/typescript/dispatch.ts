@@ -0,0 +1,50 @@
+export interface Repo {
+    save(user: User): void;
+}
+
+export interface AuditedRepo extends Repo {
+    audit(): void;
+}
+
+export abstract class BaseRepo implements Repo {
+    abstract save(user: User): void;
+
+    log(message: string): void {
+        console.log(message);
+    }
+}
+
+export class SqlRepo extends BaseRepo implements AuditedRepo {
+    save(user: User): void {
+        this.log("save");
+        this.audit();
+    }
+
+    audit(): void {}
+}
+
+export class MemoryRepo extends BaseRepo {
+    private readonly users: Map<string, User> = new Map();
+
+    save(user: User): void {
+        super.log("memory");
+    }
+}
+
+export class UserService {
+    private cache = new MemoryRepo();
+    logger?: Logger | null;
+    count: number = 0;
+
+    constructor(private readonly repo: Repo, public audit: AuditedRepo, name: string) {}
+
+    create(user: User): void {
+        this.repo.save(user);
+        this.cache.save(user);
+        this.validate(user);
+    }
+
+    validate(user: User): boolean {
+        return true;
+    }
+}
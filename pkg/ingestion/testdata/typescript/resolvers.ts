@@ -0,0 +1,38 @@
+import { listOrders } from "./orders";
+import { withFilter } from "graphql-subscriptions";
+
+const pubsub = new PubSub();
+
+export const resolvers: Resolvers = {
+  Query: {
+    order: async (_parent, { id }, ctx) => ctx.orders.get(id),
+    orders: listOrders,
+    search(_parent, args, ctx) {
+      // a { brace } in a comment
+      return ctx.search.run(args.text, "}");
+    },
+  },
+  Mutation: {
+    createOrder: requireAuth(async (_parent, { input }, ctx) => {
+      return ctx.orders.create(input);
+    }),
+  },
+  Subscription: {
+    orderShipped: {
+      subscribe: withFilter(() => pubsub.asyncIterator("SHIPPED"), (payload, vars) => payload.id === vars.id),
+    },
+  },
+  Order: {
+    __resolveType: () => "Order",
+    customer: (order, _args, ctx) => ctx.users.get(order.customerId),
+  },
+  Time: TimeScalar,
+};
+
+export const Mutation: MutationResolvers = {
+  cancelOrder: (_parent, { id }, ctx) => ctx.orders.cancel(id),
+};
+
+function requireAuth(fn) {
+  return fn;
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"path"
+	"strings"
+)
+
+// =============================================================================
+// TOPOLOGY LINKING
+// =============================================================================
+
+// LinkTopology resolves the cross-file references of infrastructure topology
+// edges and returns the edges with a target:
+//   - runs: node binary ("cmd/worker", "worker") → main function of the Go
+//     main package in that directory (by path, then by cmd/* base name)
+//   - builds: compose build context → Dockerfile node at that path
+//   - image: image reference → Dockerfile building it, found through compose
+//     services that both build and tag the image, else by Dockerfile name
+//   - exposes: Kubernetes Service selector → workloads whose pod labels match
+//   - depends_on: Terraform "type.name" references → resource nodes
+//
+// Unresolvable references are dropped.
+func LinkTopology(topology Topology, functions []FunctionEntity, packageNames map[string]string) []TopologyEdge {
+	if len(topology.Nodes) == 0 {
+		return nil
+	}
+
+	nodes := make(map[string]*TopologyNodeEntity, len(topology.Nodes))
+	dockerfiles := make(map[string]string)         // file path -> node ID
+	dockerfilesByName := make(map[string][]string) // node name -> node IDs
+	terraform := make(map[string][]string)         // "type.name" -> node IDs
+	for i := range topology.Nodes {
+		n := &topology.Nodes[i]
+		nodes[n.ID] = n
+		switch n.Kind {
+		case TopologyKindDockerfile:
+			dockerfiles[n.FilePath] = n.ID
+			dockerfilesByName[n.Name] = append(dockerfilesByName[n.Name], n.ID)
+		case TopologyKindTerraform:
+			terraform[n.Name] = append(terraform[n.Name], n.ID)
+		}
+	}
+
+	// Compose services with both build and image tag the image they build
+	builtImages := make(map[string]string) // image repository -> Dockerfile node ID
+	for _, e := range topology.Edges {
+		if e.Kind != TopologyEdgeBuilds {
+			continue
+		}
+		if from := nodes[e.FromID]; from != nil && from.Image != "" {
+			if id, ok := dockerfiles[e.Target]; ok {
+				builtImages[imageRepository(from.Image)] = id
+			}
+		}
+	}
+
+	mains := newMainPackageIndex(functions, packageNames)
+
+	var edges []TopologyEdge
+	add := func(e TopologyEdge, toID string) {
+		e.ToID = toID
+		e.Target = ""
+		edges = append(edges, e)
+	}
+	for _, e := range topology.Edges {
+		if e.ToID != "" {
+			if e.Kind != TopologyEdgeDependsOn || nodes[e.ToID] != nil {
+				edges = append(edges, e)
+			}
+			continue
+		}
+		switch e.Kind {
+		case TopologyEdgeBuilds:
+			if id, ok := dockerfiles[e.Target]; ok {
+				add(e, id)
+			}
+		case TopologyEdgeImage:
+			repo := imageRepository(e.Target)
+			if id, ok := builtImages[repo]; ok {
+				add(e, id)
+			} else if ids := dockerfilesByName[path.Base(repo)]; len(ids) == 1 {
+				add(e, ids[0])
+			}
+		case TopologyEdgeExposes:
+			for _, n := range topology.Nodes {
+				if isK8sWorkloadKind(n.Kind) && labelsMatch(e.Target, n.Labels) {
+					add(e, n.ID)
+				}
+			}
+		case TopologyEdgeDependsOn:
+			if ids := terraform[e.Target]; len(ids) == 1 {
+				add(e, ids[0])
+			}
+		}
+	}
+
+	for _, n := range topology.Nodes {
+		if n.Binary == "" {
+			continue
+		}
+		if id := mains.resolve(n.Binary); id != "" {
+			edges = append(edges, TopologyEdge{
+				FromID:   n.ID,
+				ToID:     id,
+				Kind:     TopologyEdgeRuns,
+				FilePath: n.FilePath,
+				Line:     n.Line,
+			})
+		}
+	}
+
+	return edges
+}
+
+// mainPackageIndex maps Go main packages to their main function.
+type mainPackageIndex struct {
+	byDir  map[string]string   // package directory -> main function ID
+	byBase map[string][]string // base name of cmd/* directories -> main function IDs
+}
+
+// newMainPackageIndex indexes the main functions of Go main packages.
+func newMainPackageIndex(functions []FunctionEntity, packageNames map[string]string) *mainPackageIndex {
+	idx := &mainPackageIndex{
+		byDir:  make(map[string]string),
+		byBase: make(map[string][]string),
+	}
+	for _, fn := range functions {
+		if fn.Name != "main" || !strings.HasSuffix(fn.FilePath, ".go") {
+			continue
+		}
+		if pkg, ok := packageNames[fn.FilePath]; ok && pkg != "main" {
+			continue
+		}
+		dir := path.Dir(fn.FilePath)
+		idx.byDir[dir] = fn.ID
+		if dir == "cmd" || strings.HasPrefix(dir, "cmd/") || strings.Contains(dir, "/cmd/") {
+			idx.byBase[path.Base(dir)] = append(idx.byBase[path.Base(dir)], fn.ID)
+		}
+	}
+	return idx
+}
+
+// resolve returns the main function run by a binary reference: a package path
+// ("cmd/worker", "services/api/cmd/worker") or a binary name ("worker").
+func (idx *mainPackageIndex) resolve(binary string) string {
+	if id, ok := idx.byDir[binary]; ok {
+		return id
+	}
+	if strings.Contains(binary, "/") {
+		// Package path relative to a nested module or build context
+		var match string
+		for dir, id := range idx.byDir {
+			if strings.HasSuffix(dir, "/"+binary) {
+				if match != "" {
+					return ""
+				}
+				match = id
+			}
+		}
+		if match != "" {
+			return match
+		}
+	}
+	if ids := idx.byBase[path.Base(binary)]; len(ids) == 1 {
+		return ids[0]
+	}
+	return ""
+}
+
+// imageRepository strips the tag and digest from an image reference:
+// "ghcr.io/acme/worker:1.2" → "ghcr.io/acme/worker".
+func imageRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+		image = image[:colon]
+	}
+	return image
+}
+
+// isK8sWorkloadKind reports whether a topology node is a Kubernetes workload container.
+func isK8sWorkloadKind(kind string) bool {
+	return strings.HasPrefix(kind, "k8s_") && kind != TopologyKindK8sService
+}
+
+// labelsMatch reports whether every key=value pair of selector is in labels.
+func labelsMatch(selector, labels string) bool {
+	if selector == "" || labels == "" {
+		return false
+	}
+	have := make(map[string]bool)
+	for _, pair := range strings.Split(labels, ",") {
+		have[pair] = true
+	}
+	for _, pair := range strings.Split(selector, ",") {
+		if !have[pair] {
+			return false
+		}
+	}
+	return true
+}
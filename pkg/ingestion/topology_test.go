@@ -0,0 +1,67 @@
+package ingestion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLinkTopology tests binary, Dockerfile, image, selector and Terraform resolution
+// across the fixture files of one repository.
+func TestLinkTopology(t *testing.T) {
+	var topology Topology
+	topology.Merge(parseTopologyTestFile(t, "deploy/worker/Dockerfile", "dockerfile"))
+	topology.Merge(parseTopologyTestFile(t, "docker-compose.yml", "yaml"))
+	topology.Merge(parseTopologyTestFile(t, "k8s.yaml", "yaml"))
+	topology.Merge(parseTopologyTestFile(t, "main.tf", "terraform"))
+
+	functions := []FunctionEntity{
+		{ID: "fn:worker", Name: "main", FilePath: "cmd/worker/main.go"},
+		{ID: "fn:api", Name: "main", FilePath: "cmd/api/main.go"},
+		{ID: "fn:cleanup", Name: "main", FilePath: "tools/cmd/cleanup/main.go"},
+		{ID: "fn:lib", Name: "main", FilePath: "internal/lib/main.go"},
+	}
+	packageNames := map[string]string{
+		"cmd/worker/main.go":        "main",
+		"cmd/api/main.go":           "main",
+		"tools/cmd/cleanup/main.go": "main",
+		"internal/lib/main.go":      "lib",
+	}
+
+	edges := LinkTopology(topology, functions, packageNames)
+
+	name := func(id string) string {
+		for _, n := range topology.Nodes {
+			if n.ID == id {
+				return n.Kind + " " + n.Name
+			}
+		}
+		return id
+	}
+	var got []string
+	for _, e := range edges {
+		require.Empty(t, e.Target, "Linked edges should not keep unresolved targets")
+		got = append(got, name(e.FromID)+" -"+e.Kind+"-> "+name(e.ToID))
+	}
+
+	assert.ElementsMatch(t, []string{
+		"compose_service worker -builds-> dockerfile worker",
+		"compose_service worker -depends_on-> compose_service db",
+		"k8s_deployment orders-worker/worker -image-> dockerfile worker",
+		"k8s_service orders-worker -exposes-> k8s_deployment orders-worker/worker",
+		"k8s_service orders-worker -exposes-> k8s_deployment orders-worker/proxy",
+		"terraform_resource aws_ecs_task_definition.worker -image-> dockerfile worker",
+		"terraform_resource aws_ecs_service.worker -depends_on-> terraform_resource aws_ecs_task_definition.worker",
+		"dockerfile worker -runs-> fn:worker",
+		"compose_service api -runs-> fn:api",
+		"k8s_cronjob cleanup -runs-> fn:cleanup",
+	}, got, "Images are matched through compose build+image tags; missing Dockerfiles and external images are dropped")
+}
+
+// TestImageRepository tests tag and digest stripping.
+func TestImageRepository(t *testing.T) {
+	assert.Equal(t, "ghcr.io/acme/worker", imageRepository("ghcr.io/acme/worker:1.2"))
+	assert.Equal(t, "localhost:5000/worker", imageRepository("localhost:5000/worker"))
+	assert.Equal(t, "worker", imageRepository("worker@sha256:abc"))
+}
@@ -0,0 +1,393 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// GO TYPE REFERENCES
+// =============================================================================
+
+// goTypeRefCollector accumulates the type names referenced, and the generics
+// instantiated, by one file.
+type goTypeRefCollector struct {
+	content    []byte
+	filePath   string
+	fromID     string            // Function or type currently walked
+	localFuncs map[string]string // Functions of the file: calls to them are not conversions
+	seen       map[string]bool
+	refs       []UnresolvedTypeRef
+	insts      []UnresolvedInstantiation
+}
+
+// extractGoTypeRefs collects the type names referenced by the functions and
+// types of a Go file:
+//   - functions: receiver, parameters, results, and in the body composite
+//     literals, conversions, type assertions and any other type position
+//     (var declarations, new/make, generic arguments)
+//   - types: struct fields, embedded structs and interfaces, interface method
+//     signatures, and the underlying type of type definitions
+//
+// Function literals are walked as their own functions. Calls `Name(x)` and
+// `pkg.Name(x)` are recorded as candidate conversions; ResolveTypeRefs drops
+// those that do not name a type.
+//
+// Explicit instantiations, `Map[int, string](x)` and `List[int]` outside a
+// receiver, are collected alongside for ResolveInstantiations.
+func (p *TreeSitterParser) extractGoTypeRefs(rootNode *sitter.Node, ctx *goFunctionContext, types []TypeEntity) ([]UnresolvedTypeRef, []UnresolvedInstantiation) {
+	c := &goTypeRefCollector{
+		content:    ctx.content,
+		filePath:   ctx.filePath,
+		localFuncs: ctx.funcNameToID,
+		seen:       make(map[string]bool),
+	}
+
+	for _, fn := range ctx.functions {
+		c.fromID = fn.entity.ID
+		c.walkField(fn.node, "receiver", "receiver")
+		c.walkField(fn.node, "type_parameters", "use")
+		c.walkField(fn.node, "parameters", "param")
+		c.walkField(fn.node, "result", "return")
+		c.walkField(fn.node, "body", "use")
+	}
+
+	typeIDs := make(map[string]bool, len(types))
+	for _, t := range types {
+		typeIDs[t.ID] = true
+	}
+	walkGoTypeSpecs(rootNode, c.content, c.filePath, typeIDs, func(node *sitter.Node, id string) {
+		c.fromID = id
+		c.walkField(node, "type_parameters", "use")
+		c.walkTypeDefinition(node.ChildByFieldName("type"))
+	})
+
+	return c.refs, c.insts
+}
+
+// walkTypeDefinition collects the references of the type on the right-hand
+// side of a type_spec.
+func (c *goTypeRefCollector) walkTypeDefinition(typeNode *sitter.Node) {
+	if typeNode == nil {
+		return
+	}
+	switch typeNode.Type() {
+	case "struct_type":
+		for i := 0; i < int(typeNode.NamedChildCount()); i++ {
+			list := typeNode.NamedChild(i)
+			if list.Type() != "field_declaration_list" {
+				continue
+			}
+			for j := 0; j < int(list.NamedChildCount()); j++ {
+				field := list.NamedChild(j)
+				if field.Type() != "field_declaration" {
+					continue
+				}
+				kind := "embed"
+				if field.ChildByFieldName("name") != nil {
+					kind = "field"
+				}
+				c.walkField(field, "type", kind)
+			}
+		}
+	case "interface_type":
+		for i := 0; i < int(typeNode.NamedChildCount()); i++ {
+			elem := typeNode.NamedChild(i)
+			switch elem.Type() {
+			case "method_elem", "method_spec":
+				c.walkField(elem, "parameters", "param")
+				c.walkField(elem, "result", "return")
+			default:
+				c.walk(elem, "embed") // io.Reader, or a constraint such as ~int | Number
+			}
+		}
+	default:
+		c.walk(typeNode, "use")
+	}
+}
+
+// walkField walks the child of node with the given field name.
+func (c *goTypeRefCollector) walkField(node *sitter.Node, field, kind string) {
+	if child := node.ChildByFieldName(field); child != nil {
+		c.walk(child, kind)
+	}
+}
+
+// walk collects the type names under node. kind applies to type positions
+// that no more specific construct (literal, conversion, assertion) claims.
+func (c *goTypeRefCollector) walk(node *sitter.Node, kind string) {
+	switch node.Type() {
+	case "type_identifier", "qualified_type":
+		c.add(nodeText(node, c.content), kind, node)
+		return
+	case "func_literal":
+		return // Walked as its own function
+	case "composite_literal":
+		c.walkField(node, "type", "literal")
+		c.walkField(node, "body", kind)
+		return
+	case "type_assertion_expression":
+		c.walkField(node, "operand", kind)
+		c.walkField(node, "type", "assertion")
+		return
+	case "type_conversion_expression":
+		c.walkField(node, "type", "conversion")
+		c.walkField(node, "operand", kind)
+		return
+	case "type_case":
+		for i := 0; i < int(node.ChildCount()); i++ {
+			child := node.Child(i)
+			if !child.IsNamed() {
+				continue
+			}
+			if node.FieldNameForChild(i) == "type" {
+				c.walk(child, "assertion")
+			} else {
+				c.walk(child, kind)
+			}
+		}
+		return
+	case "generic_type":
+		if kind != "receiver" { // (l *List[T]) declares a method, it does not instantiate List
+			c.addInstantiation(node.ChildByFieldName("type"), node.ChildByFieldName("type_arguments"), node)
+		}
+	case "call_expression":
+		c.addConversionCandidate(node.ChildByFieldName("function"), node)
+		if typeArgs := node.ChildByFieldName("type_arguments"); typeArgs != nil {
+			c.addInstantiation(node.ChildByFieldName("function"), typeArgs, node)
+		}
+	}
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		c.walk(node.NamedChild(i), kind)
+	}
+}
+
+// addConversionCandidate records `Name(x)` and `pkg.Name(x)` calls, which are
+// conversions when Name is a type. Calls to functions of the file are skipped.
+func (c *goTypeRefCollector) addConversionCandidate(funcNode, callNode *sitter.Node) {
+	if funcNode == nil {
+		return
+	}
+	switch funcNode.Type() {
+	case "identifier":
+		name := nodeText(funcNode, c.content)
+		if _, local := c.localFuncs[name]; !local {
+			c.add(name, "conversion", callNode)
+		}
+	case "selector_expression":
+		operand := funcNode.ChildByFieldName("operand")
+		field := funcNode.ChildByFieldName("field")
+		if operand == nil || field == nil || operand.Type() != "identifier" {
+			return
+		}
+		if name := nodeText(field, c.content); isExportedName(name) {
+			c.add(nodeText(operand, c.content)+"."+name, "conversion", callNode)
+		}
+	}
+}
+
+// addInstantiation records the instantiation of the generic named by nameNode
+// (Map, pkg.Map or pkg.List) with the given type arguments.
+func (c *goTypeRefCollector) addInstantiation(nameNode, typeArgsNode, node *sitter.Node) {
+	if nameNode == nil || typeArgsNode == nil {
+		return
+	}
+	switch nameNode.Type() {
+	case "identifier", "type_identifier", "qualified_type":
+	case "selector_expression":
+		if operand := nameNode.ChildByFieldName("operand"); operand == nil || operand.Type() != "identifier" {
+			return
+		}
+	default:
+		return
+	}
+
+	name := nodeText(nameNode, c.content)
+	typeArgs := goTypeArgs(typeArgsNode, c.content)
+	line := int(node.StartPoint().Row) + 1
+	key := fmt.Sprintf("inst|%s|%s|%s|%d", c.fromID, name, typeArgs, line)
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.insts = append(c.insts, UnresolvedInstantiation{
+		FromID:   c.fromID,
+		Name:     name,
+		TypeArgs: typeArgs,
+		FilePath: c.filePath,
+		Line:     line,
+	})
+}
+
+// add records a reference unless it names a builtin type or was already seen
+// on the same line.
+func (c *goTypeRefCollector) add(name, kind string, node *sitter.Node) {
+	if name == "" || isGoBuiltinType(name) {
+		return
+	}
+	line := int(node.StartPoint().Row) + 1
+	key := fmt.Sprintf("%s|%s|%s|%d", c.fromID, name, kind, line)
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.refs = append(c.refs, UnresolvedTypeRef{
+		FromID:   c.fromID,
+		TypeName: name,
+		Kind:     kind,
+		FilePath: c.filePath,
+		Line:     line,
+	})
+}
+
+// =============================================================================
+// RESOLUTION
+// =============================================================================
+
+// goSymbolIndex resolves the identifiers of Go files, plain or
+// package-qualified, to entities declared in the repository's packages.
+type goSymbolIndex struct {
+	fileLanguage map[string]string
+	qualified    map[string]map[string]string // file -> qualifier -> dir
+	dotImports   map[string][]string          // file -> dirs
+}
+
+// newGoSymbolIndex maps the imports of each Go file to package directories:
+// by alias, else by the imported package's name, and dot imports apart.
+func newGoSymbolIndex(files []FileEntity, imports []ImportEntity, packageNames map[string]string) *goSymbolIndex {
+	dirs, fileLanguage := groupPackageDirs(files)
+	x := &goSymbolIndex{
+		fileLanguage: fileLanguage,
+		qualified:    make(map[string]map[string]string),
+		dotImports:   make(map[string][]string),
+	}
+
+	resolveImport := newPackageImportResolver(dirs, imports, fileLanguage, packageNames)
+	for _, imp := range imports {
+		if fileLanguage[imp.FilePath] != "go" {
+			continue
+		}
+		dir := resolveImport(imp, "go")
+		if dir == "" {
+			continue
+		}
+		switch imp.Alias {
+		case "_":
+		case ".":
+			x.dotImports[imp.FilePath] = append(x.dotImports[imp.FilePath], dir)
+		default:
+			qualifier := imp.Alias
+			if qualifier == "" {
+				qualifier = packageName(dir, dirs[dir].files, packageNames)
+			}
+			if x.qualified[imp.FilePath] == nil {
+				x.qualified[imp.FilePath] = make(map[string]string)
+			}
+			x.qualified[imp.FilePath][qualifier] = dir
+		}
+	}
+	return x
+}
+
+// add indexes a Go entity by directory and name into ids, keeping the first
+// entity declared under a name.
+func (x *goSymbolIndex) add(ids map[string]string, filePath, name, id string) {
+	if x.fileLanguage[filePath] != "go" {
+		return
+	}
+	key := path.Dir(filePath) + "." + name
+	if _, exists := ids[key]; !exists {
+		ids[key] = id
+	}
+}
+
+// resolve returns the ID in ids of the entity that name refers to in filePath:
+//   - "Name" matches an entity declared in the directory of filePath, else in
+//     a package it dot-imports
+//   - "pkg.Name" matches an entity declared in the package filePath imports
+//     as pkg
+func (x *goSymbolIndex) resolve(ids map[string]string, filePath, name string) string {
+	if qualifier, base, ok := strings.Cut(name, "."); ok {
+		if dir, imported := x.qualified[filePath][qualifier]; imported {
+			return ids[dir+"."+base]
+		}
+		return ""
+	}
+	if id := ids[path.Dir(filePath)+"."+name]; id != "" {
+		return id
+	}
+	for _, dir := range x.dotImports[filePath] {
+		if id := ids[dir+"."+name]; id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// ResolveTypeRefs resolves the type names referenced in Go files to the types
+// they name, through the package of the referencing file and its imports
+// (see goSymbolIndex.resolve).
+//
+// References to external types, and candidate conversions naming a function,
+// produce no edge.
+func ResolveTypeRefs(refs []UnresolvedTypeRef, files []FileEntity, types []TypeEntity, imports []ImportEntity, packageNames map[string]string) []TypeRefEdge {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	index := newGoSymbolIndex(files, imports, packageNames)
+	typeIDs := make(map[string]string) // dir + "." + name -> type ID
+	for _, t := range types {
+		index.add(typeIDs, t.FilePath, t.Name, t.ID)
+	}
+	if len(typeIDs) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var edges []TypeRefEdge
+	for _, ref := range refs {
+		typeID := index.resolve(typeIDs, ref.FilePath, ref.TypeName)
+		if typeID == "" {
+			continue
+		}
+
+		id := GenerateTypeRefID(ref.FromID, typeID, ref.Kind, ref.Line)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		edges = append(edges, TypeRefEdge{
+			ID:       id,
+			FromID:   ref.FromID,
+			TypeID:   typeID,
+			Kind:     ref.Kind,
+			FilePath: ref.FilePath,
+			Line:     ref.Line,
+		})
+	}
+	return edges
+}
@@ -0,0 +1,139 @@
+package ingestion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resolveTypeRefSources parses Go sources keyed by path and resolves their
+// type references, returned as "from -> type (kind) :line".
+func resolveTypeRefSources(t *testing.T, sources map[string]string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	parser := NewTreeSitterParser(nil)
+	var files []FileEntity
+	var types []TypeEntity
+	var imports []ImportEntity
+	var refs []UnresolvedTypeRef
+	names := make(map[string]string)
+	packageNames := make(map[string]string)
+	for path, content := range sources {
+		fullPath := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), 0600))
+
+		result, err := parser.ParseFile(FileInfo{Path: path, FullPath: fullPath, Size: int64(len(content)), Language: "go"})
+		require.NoError(t, err)
+		files = append(files, result.File)
+		types = append(types, result.Types...)
+		imports = append(imports, result.Imports...)
+		refs = append(refs, result.TypeRefs...)
+		packageNames[path] = result.PackageName
+		for _, fn := range result.Functions {
+			names[fn.ID] = fn.Name
+		}
+		for _, typ := range result.Types {
+			names[typ.ID] = "type " + typ.Name
+		}
+	}
+
+	var got []string
+	for _, e := range ResolveTypeRefs(refs, files, types, imports, packageNames) {
+		assert.Equal(t, GenerateTypeRefID(e.FromID, e.TypeID, e.Kind, e.Line), e.ID)
+		got = append(got, names[e.FromID]+" -> "+names[e.TypeID]+" ("+e.Kind+") :"+fmt.Sprint(e.Line))
+	}
+	return got
+}
+
+// TestResolveTypeRefs tests the reference kinds recorded for functions and
+// types, and resolution through imports and within a package.
+func TestResolveTypeRefs(t *testing.T) {
+	got := resolveTypeRefSources(t, map[string]string{
+		"pkg/tools/types.go": `package tools
+
+type QueryResult struct {
+	Rows [][]any
+}
+
+type Querier interface {
+	Query(q string) (*QueryResult, error)
+}
+
+type Client struct {
+	Querier
+	last *QueryResult
+}
+`,
+		"cmd/cie/main.go": `package main
+
+import (
+	"fmt"
+
+	cie "github.com/kraklabs/cie/pkg/tools"
+)
+
+type Alias = int
+
+func run(q cie.Querier) (*cie.QueryResult, error) {
+	r := &cie.QueryResult{}
+	if c, ok := q.(*cie.Client); ok {
+		_ = c
+	}
+	var x any = r
+	switch x.(type) {
+	case cie.Querier:
+	}
+	_ = cie.QueryResult(*r)
+	fmt.Println(r)
+	go func(r *cie.QueryResult) {}(r)
+	return r, nil
+}
+`,
+	})
+
+	assert.ElementsMatch(t, []string{
+		"type Querier -> type QueryResult (return) :8",
+		"type Client -> type Querier (embed) :12",
+		"type Client -> type QueryResult (field) :13",
+		"run -> type Querier (param) :11",
+		"run -> type QueryResult (return) :11",
+		"run -> type QueryResult (literal) :12",
+		"run -> type Client (assertion) :13",
+		"run -> type Querier (assertion) :18",
+		"run -> type QueryResult (conversion) :20",
+		"$anon_1 -> type QueryResult (param) :22",
+	}, got)
+}
+
+// TestResolveTypeRefs_Unresolved tests that external types, builtins and
+// calls to functions produce no edge.
+func TestResolveTypeRefs_Unresolved(t *testing.T) {
+	got := resolveTypeRefSources(t, map[string]string{
+		"main.go": `package main
+
+import "strings"
+
+type Config struct{}
+
+func NewConfig() *Config { return &Config{} }
+
+func main() {
+	var b strings.Builder
+	_ = NewConfig()
+	_ = string("x")
+	_ = b
+}
+`,
+	})
+
+	assert.Equal(t, []string{
+		"NewConfig -> type Config (return) :7",
+		"NewConfig -> type Config (literal) :7",
+	}, got)
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package ingestion
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// =============================================================================
+// PACKAGE-LEVEL VARIABLE EXTRACTION
+// =============================================================================
+
+// maxVariableValueLen caps the stored initializer snippet of a variable.
+const maxVariableValueLen = 200
+
+// jsFunctionValues are initializers already indexed as functions or types.
+var jsFunctionValues = map[string]bool{
+	"arrow_function":      true,
+	"function":            true,
+	"function_expression": true,
+	"generator_function":  true,
+	"class":               true,
+}
+
+// extractGoVariables extracts the top-level `var` and `const` declarations of
+// a Go file. A spec declaring several names yields one variable per name; a
+// const spec without a type or value repeats the type of the previous spec,
+// as in iota blocks.
+func (p *TreeSitterParser) extractGoVariables(rootNode *sitter.Node, content []byte, filePath string) []VariableEntity {
+	var variables []VariableEntity
+	for i := 0; i < int(rootNode.NamedChildCount()); i++ {
+		decl := rootNode.NamedChild(i)
+		var kind string
+		switch decl.Type() {
+		case "var_declaration":
+			kind = "var"
+		case "const_declaration":
+			kind = "const"
+		default:
+			continue
+		}
+
+		var specs []*sitter.Node
+		for j := 0; j < int(decl.NamedChildCount()); j++ {
+			child := decl.NamedChild(j)
+			switch child.Type() {
+			case "var_spec", "const_spec":
+				specs = append(specs, child)
+			case "var_spec_list":
+				for k := 0; k < int(child.NamedChildCount()); k++ {
+					if spec := child.NamedChild(k); spec.Type() == "var_spec" {
+						specs = append(specs, spec)
+					}
+				}
+			}
+		}
+
+		prevType := ""
+		for _, spec := range specs {
+			typ := ""
+			if typeNode := spec.ChildByFieldName("type"); typeNode != nil {
+				typ = nodeText(typeNode, content)
+			}
+			var values []*sitter.Node
+			if valueNode := spec.ChildByFieldName("value"); valueNode != nil {
+				for k := 0; k < int(valueNode.NamedChildCount()); k++ {
+					values = append(values, valueNode.NamedChild(k))
+				}
+			}
+			if kind == "const" {
+				if typ == "" && len(values) == 0 {
+					typ = prevType
+				}
+				prevType = typ
+			}
+
+			var names []*sitter.Node
+			for k := 0; k < int(spec.ChildCount()); k++ {
+				if spec.FieldNameForChild(k) == "name" {
+					names = append(names, spec.Child(k))
+				}
+			}
+			for k, nameNode := range names {
+				name := nodeText(nameNode, content)
+				if name == "_" {
+					continue
+				}
+				value := ""
+				if len(values) == len(names) {
+					value = nodeText(values[k], content)
+				} else if len(values) > 0 {
+					value = nodeText(spec.ChildByFieldName("value"), content) // var a, b = f()
+				}
+				variables = append(variables, p.newVariable(spec, content, filePath, name, kind, typ, value))
+			}
+		}
+	}
+	return variables
+}
+
+// extractPythonVariables extracts module-level assignments to a single name
+// (`TIMEOUT = 30`, `REGISTRY: dict[str, Handler] = {}`). Assignments nested in
+// `if` blocks or functions, and tuple unpacking, are not indexed.
+func (p *TreeSitterParser) extractPythonVariables(rootNode *sitter.Node, content []byte, filePath string) []VariableEntity {
+	var variables []VariableEntity
+	for i := 0; i < int(rootNode.NamedChildCount()); i++ {
+		stmt := rootNode.NamedChild(i)
+		if stmt.Type() != "expression_statement" || stmt.NamedChildCount() == 0 {
+			continue
+		}
+		assign := stmt.NamedChild(0)
+		if assign.Type() != "assignment" {
+			continue
+		}
+		left := assign.ChildByFieldName("left")
+		if left == nil || left.Type() != "identifier" {
+			continue
+		}
+		typ, value := "", ""
+		if typeNode := assign.ChildByFieldName("type"); typeNode != nil {
+			typ = nodeText(typeNode, content)
+		}
+		if right := assign.ChildByFieldName("right"); right != nil {
+			value = nodeText(right, content)
+		}
+		variables = append(variables, p.newVariable(stmt, content, filePath, nodeText(left, content), "var", typ, value))
+	}
+	return variables
+}
+
+// extractJSVariables extracts exported top-level `const`, `let` and `var`
+// declarations of a JavaScript or TypeScript file. Declarations initialized
+// with a function or class are skipped: they are indexed as such.
+func (p *TreeSitterParser) extractJSVariables(rootNode *sitter.Node, content []byte, filePath string) []VariableEntity {
+	var variables []VariableEntity
+	for i := 0; i < int(rootNode.NamedChildCount()); i++ {
+		export := rootNode.NamedChild(i)
+		if export.Type() != "export_statement" {
+			continue
+		}
+		decl := export.ChildByFieldName("declaration")
+		if decl == nil || (decl.Type() != "lexical_declaration" && decl.Type() != "variable_declaration") {
+			continue
+		}
+		kind := "var"
+		if decl.Type() == "lexical_declaration" && decl.ChildCount() > 0 {
+			kind = decl.Child(0).Type() // "const" or "let"
+		}
+
+		for j := 0; j < int(decl.NamedChildCount()); j++ {
+			declarator := decl.NamedChild(j)
+			if declarator.Type() != "variable_declarator" {
+				continue
+			}
+			nameNode := declarator.ChildByFieldName("name")
+			if nameNode == nil || nameNode.Type() != "identifier" {
+				continue // Destructuring: export const { a, b } = obj
+			}
+			valueNode := declarator.ChildByFieldName("value")
+			if valueNode != nil && jsFunctionValues[valueNode.Type()] {
+				continue
+			}
+			typ, value := "", ""
+			if typeNode := declarator.ChildByFieldName("type"); typeNode != nil {
+				typ = strings.TrimSpace(strings.TrimPrefix(nodeText(typeNode, content), ":"))
+			}
+			if valueNode != nil {
+				value = nodeText(valueNode, content)
+			}
+			variables = append(variables, p.newVariable(declarator, content, filePath, nodeText(nameNode, content), kind, typ, value))
+		}
+	}
+	return variables
+}
+
+// newVariable builds a VariableEntity located at node.
+func (p *TreeSitterParser) newVariable(node *sitter.Node, content []byte, filePath, name, kind, typ, value string) VariableEntity {
+	startLine := int(node.StartPoint().Row) + 1
+	return VariableEntity{
+		ID:        GenerateVariableID(filePath, name, startLine),
+		Name:      name,
+		Kind:      kind,
+		Type:      typ,
+		Value:     variableSnippet(value),
+		FilePath:  filePath,
+		CodeText:  p.truncateCodeText(nodeText(node, content)),
+		StartLine: startLine,
+		EndLine:   int(node.EndPoint().Row) + 1,
+	}
+}
+
+// variableSnippet collapses whitespace and caps an initializer at
+// maxVariableValueLen bytes on a rune boundary.
+func variableSnippet(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if len(value) <= maxVariableValueLen {
+		return value
+	}
+	cut := maxVariableValueLen
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut] + "..."
+}
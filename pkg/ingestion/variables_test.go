@@ -0,0 +1,127 @@
+package ingestion
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// variablesByName maps variable names to their parsed entities.
+func variablesByName(result *ParseResult) map[string]VariableEntity {
+	vars := make(map[string]VariableEntity)
+	for _, v := range result.Variables {
+		vars[v.Name] = v
+	}
+	return vars
+}
+
+// TestExtractGoVariables tests var/const specs, multi-name specs and iota
+// blocks inheriting the type of the previous spec.
+func TestExtractGoVariables(t *testing.T) {
+	result := parseDocSource(t, "config.go", "go", `package config
+
+import "time"
+
+const DefaultTimeout time.Duration = 30 * time.Second
+
+var (
+	Registry = map[string]int{}
+	host, port = "localhost", 8080
+	_ = time.Now
+)
+
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+)
+
+func f() {
+	var local = 1
+	_ = local
+}
+`)
+
+	vars := variablesByName(result)
+	assert.Len(t, result.Variables, 7, "locals and blank identifiers are not indexed")
+
+	assert.Equal(t, VariableEntity{
+		ID:        GenerateVariableID("config.go", "DefaultTimeout", 5),
+		Name:      "DefaultTimeout",
+		Kind:      "const",
+		Type:      "time.Duration",
+		Value:     "30 * time.Second",
+		FilePath:  "config.go",
+		CodeText:  "DefaultTimeout time.Duration = 30 * time.Second",
+		StartLine: 5,
+		EndLine:   5,
+	}, vars["DefaultTimeout"])
+	assert.Equal(t, "var", vars["Registry"].Kind)
+	assert.Equal(t, "map[string]int{}", vars["Registry"].Value)
+	assert.Equal(t, `"localhost"`, vars["host"].Value)
+	assert.Equal(t, "8080", vars["port"].Value)
+	assert.Equal(t, "Level", vars["Debug"].Type)
+	assert.Equal(t, "iota", vars["Debug"].Value)
+	assert.Equal(t, "Level", vars["Warn"].Type)
+	assert.Empty(t, vars["Warn"].Value)
+}
+
+// TestExtractPythonVariables tests module-level assignments, with and without
+// annotations; nested assignments and tuple unpacking are skipped.
+func TestExtractPythonVariables(t *testing.T) {
+	result := parseDocSource(t, "settings.py", "python", `TIMEOUT = 30
+REGISTRY: dict[str, int] = {}
+a, b = 1, 2
+
+if DEBUG:
+    LEVEL = "debug"
+
+def handler():
+    local = 1
+`)
+
+	vars := variablesByName(result)
+	assert.Len(t, result.Variables, 2)
+	assert.Equal(t, "30", vars["TIMEOUT"].Value)
+	assert.Equal(t, "var", vars["TIMEOUT"].Kind)
+	assert.Equal(t, "dict[str, int]", vars["REGISTRY"].Type)
+	assert.Equal(t, 2, vars["REGISTRY"].StartLine)
+}
+
+// TestExtractJSVariables tests exported const/let declarations; functions,
+// destructuring and non-exported declarations are skipped.
+func TestExtractJSVariables(t *testing.T) {
+	result := parseDocSource(t, "config.ts", "typescript", `export const API_URL: string = "https://api.example.com";
+export let retries = 3;
+export const handler = () => {};
+export const { a, b } = obj;
+const internal = 1;
+`)
+
+	vars := variablesByName(result)
+	assert.Len(t, result.Variables, 2)
+	assert.Equal(t, "const", vars["API_URL"].Kind)
+	assert.Equal(t, "string", vars["API_URL"].Type)
+	assert.Equal(t, `"https://api.example.com"`, vars["API_URL"].Value)
+	assert.Equal(t, "let", vars["retries"].Kind)
+
+	result = parseDocSource(t, "config.js", "javascript", "export var mode = 'prod';\n")
+	if assert.Len(t, result.Variables, 1) {
+		assert.Equal(t, "mode", result.Variables[0].Name)
+		assert.Equal(t, "var", result.Variables[0].Kind)
+	}
+}
+
+// TestVariableSnippet tests whitespace collapsing and rune-safe truncation.
+func TestVariableSnippet(t *testing.T) {
+	assert.Equal(t, "map[string]int{ \"a\": 1, }", variableSnippet("map[string]int{\n\t\"a\": 1,\n}"))
+
+	long := variableSnippet(strings.Repeat("é", 150))
+	assert.Equal(t, maxVariableValueLen+len("..."), len(long))
+	assert.True(t, utf8.ValidString(long))
+	assert.True(t, strings.HasSuffix(long, "..."))
+}
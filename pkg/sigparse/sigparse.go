@@ -17,7 +17,7 @@
 //
 // SPDX-License-Identifier: AGPL-3.0-or-later
 
-// Package sigparse provides Go function signature parsing utilities.
+// Package sigparse provides Go and Python function signature parsing utilities.
 // It is a dependency-free package that can be imported by both
 // pkg/ingestion (for ingestion-time dispatch) and pkg/tools (for query-time dispatch).
 package sigparse
@@ -91,12 +91,93 @@ func ParseGoParams(signature string) []ParamInfo {
 	return params
 }
 
+// ParsePythonParams parses a Python function signature string and returns
+// the annotated parameters with their base types. Unannotated parameters,
+// self/cls and *args/**kwargs are skipped.
+//
+//	"def save(self, repo: Repo, n: int = 1)" → [{repo, Repo}, {n, int}]
+//	"def run(store: Optional[db.Store])"      → [{store, Store}]
+//	"def run(store: 'Store | None' = None)"   → [{store, Store}]
+func ParsePythonParams(signature string) []ParamInfo {
+	open := strings.Index(signature, "(")
+	if open == -1 {
+		return nil
+	}
+	end := findMatchingParen(signature, open)
+	if end == -1 {
+		return nil
+	}
+
+	var params []ParamInfo
+	for _, part := range splitAtTopLevelCommas(signature[open+1 : end]) {
+		name, annotation, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.HasPrefix(name, "*") {
+			continue
+		}
+		annotation, _, _ = strings.Cut(annotation, "=")
+		if baseType := NormalizePythonType(annotation); baseType != "" {
+			params = append(params, ParamInfo{Name: name, Type: baseType})
+		}
+	}
+	return params
+}
+
+// NormalizePythonType extracts the base class name from a Python annotation.
+//
+//	"Repo" → "Repo"
+//	"'Repo'" → "Repo"
+//	"db.Repo" → "Repo"
+//	"Optional[Repo]" → "Repo"
+//	"Repo | None" → "Repo"
+//	"List[Repo]" → "List"
+func NormalizePythonType(t string) string {
+	t = strings.Trim(strings.TrimSpace(t), `"'`)
+	if inner, ok := strings.CutPrefix(t, "Optional["); ok {
+		t = strings.TrimSuffix(inner, "]")
+	}
+	for _, alt := range strings.Split(t, "|") {
+		if alt = strings.TrimSpace(alt); alt != "" && alt != "None" {
+			t = alt
+			break
+		}
+	}
+	if bracket := strings.Index(t, "["); bracket >= 0 {
+		t = t[:bracket]
+	}
+	if dot := strings.LastIndex(t, "."); dot >= 0 {
+		t = t[dot+1:]
+	}
+	return strings.TrimSpace(t)
+}
+
 // ExtractParamString extracts the parameter list from a Go function signature.
 // Given "func (r *Type) Name(ctx Context, q Querier) error", returns "ctx Context, q Querier".
 func ExtractParamString(sig string) string {
+	open, end := paramListBounds(sig)
+	if open == -1 {
+		return ""
+	}
+	return sig[open+1 : end]
+}
+
+// ExtractResultString extracts the result list from a Go function signature.
+// Given "func Name(ctx Context) (int, error)", returns "(int, error)"; returns
+// "" when the function has no result.
+func ExtractResultString(sig string) string {
+	_, end := paramListBounds(sig)
+	if end == -1 {
+		return ""
+	}
+	return strings.TrimSpace(sig[end+1:])
+}
+
+// paramListBounds returns the positions of the parentheses around the
+// parameter list of a Go function signature, or -1, -1.
+func paramListBounds(sig string) (int, int) {
 	idx := strings.Index(sig, "func")
 	if idx == -1 {
-		return ""
+		return -1, -1
 	}
 	pos := idx + 4
 
@@ -106,7 +187,7 @@ func ExtractParamString(sig string) string {
 	if pos < len(sig) && sig[pos] == '(' {
 		end := findMatchingParen(sig, pos)
 		if end == -1 {
-			return ""
+			return -1, -1
 		}
 		pos = end + 1
 	}
@@ -118,15 +199,89 @@ func ExtractParamString(sig string) string {
 	}
 
 	if pos >= len(sig) {
-		return ""
+		return -1, -1
 	}
 
 	end := findMatchingParen(sig, pos)
 	if end == -1 {
-		return ""
+		return -1, -1
 	}
+	return pos, end
+}
 
-	return sig[pos+1 : end]
+// ParseGoParamTypes returns the parameter types of a Go function signature as
+// written, one per parameter:
+//
+//	"func F(a, b int, opts ...Option)" → ["int", "int", "...Option"]
+//	"func F(*Querier, []string)" → ["*Querier", "[]string"]
+//
+// Unlike ParseGoParams, pointer, slice, variadic and package qualifiers are
+// kept and unnamed parameters are listed, so two signatures are compatible
+// for callers when their parameter types are equal.
+func ParseGoParamTypes(signature string) []string {
+	open, end := paramListBounds(signature)
+	if open == -1 {
+		return nil
+	}
+	return fieldListTypes(signature[open+1 : end])
+}
+
+// ParseGoResults returns the result types of a Go function signature as
+// written, one per result:
+//
+//	"func F() error" → ["error"]
+//	"func F() (n int, err error)" → ["int", "error"]
+func ParseGoResults(signature string) []string {
+	results := ExtractResultString(signature)
+	if results == "" {
+		return nil
+	}
+	if results[0] != '(' {
+		return []string{strings.Join(strings.Fields(results), " ")}
+	}
+	end := findMatchingParen(results, 0)
+	if end == -1 {
+		return nil
+	}
+	return fieldListTypes(results[1:end])
+}
+
+// fieldListTypes returns the types of a Go parameter or result list as
+// written, whitespace collapsed. Lists either name every field or none.
+func fieldListTypes(list string) []string {
+	parts := splitAtTopLevelCommas(list)
+	named := false
+	for _, p := range parts {
+		tokens := splitParamTokens(p)
+		if len(tokens) > 1 && tokens[0] != "chan" && !strings.HasPrefix(tokens[0], "<-") {
+			named = true
+			break
+		}
+	}
+
+	// Process right-to-left for Go grouped-param semantics.
+	var types []string
+	pendingType := ""
+	for i := len(parts) - 1; i >= 0; i-- {
+		p := strings.TrimSpace(parts[i])
+		if p == "" {
+			continue
+		}
+		if named {
+			if tokens := splitParamTokens(p); len(tokens) > 1 {
+				p = strings.TrimSpace(p[len(tokens[0]):])
+			} else {
+				p = pendingType
+			}
+		}
+		pendingType = strings.Join(strings.Fields(p), " ")
+		types = append(types, pendingType)
+	}
+
+	for i, j := 0, len(types)-1; i < j; i, j = i+1, j-1 {
+		types[i], types[j] = types[j], types[i]
+	}
+	return types
 }
 
 // NormalizeType extracts the base type name from a Go type expression.
@@ -180,9 +335,9 @@ func splitAtTopLevelCommas(s string) []string {
 	start := 0
 	for i := 0; i < len(s); i++ {
 		switch s[i] {
-		case '(':
+		case '(', '[':
 			depth++
-		case ')':
+		case ')', ']':
 			depth--
 		case ',':
 			if depth == 0 {
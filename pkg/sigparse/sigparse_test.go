@@ -5,6 +5,7 @@
 package sigparse
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -181,4 +182,68 @@ func TestExtractParamString(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestParsePythonParams(t *testing.T) {
+	tests := []struct {
+		sig  string
+		want []ParamInfo
+	}{
+		{"def save(self, repo: Repo, n: int = 1) -> None", []ParamInfo{{"repo", "Repo"}, {"n", "int"}}},
+		{"def run(store: Optional[db.Store], *args: Any, **kwargs)", []ParamInfo{{"store", "Store"}}},
+		{"def run(store: 'Store | None' = None, opts: Dict[str, int] = {})", []ParamInfo{{"store", "Store"}, {"opts", "Dict"}}},
+		{"def plain(a, b)", nil},
+		{"not a signature", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sig, func(t *testing.T) {
+			got := ParsePythonParams(tt.sig)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParsePythonParams(%q) = %v, want %v", tt.sig, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParsePythonParams(%q)[%d] = %v, want %v", tt.sig, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseGoParamTypes(t *testing.T) {
+	tests := []struct {
+		sig  string
+		want []string
+	}{
+		{"func F(a, b int, opts ...Option) error", []string{"int", "int", "...Option"}},
+		{"func (s *Server) Run(ctx context.Context, q *tools.Querier)", []string{"context.Context", "*tools.Querier"}},
+		{"func F(*Querier, []string, func(int) error)", []string{"*Querier", "[]string", "func(int) error"}},
+		{"func F(ch chan int, m map[string]int)", []string{"chan int", "map[string]int"}},
+		{"func F()", nil},
+		{"var x int", nil},
+	}
+	for _, tt := range tests {
+		got := ParseGoParamTypes(tt.sig)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("ParseGoParamTypes(%q) = %q, want %q", tt.sig, got, tt.want)
+		}
+	}
+}
+
+func TestParseGoResults(t *testing.T) {
+	tests := []struct {
+		sig  string
+		want []string
+	}{
+		{"func F() error", []string{"error"}},
+		{"func F() (n int, err error)", []string{"int", "error"}},
+		{"func F() (a, b int)", []string{"int", "int"}},
+		{"func (s *S) F(x int) (*Result, error)", []string{"*Result", "error"}},
+		{"func F(fn func() error)", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseGoResults(tt.sig); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("ParseGoResults(%q) = %q, want %q", tt.sig, got, tt.want)
+		}
+	}
+}
@@ -184,25 +184,8 @@ func (b *EmbeddedBackend) EnsureSchema() error {
 	}
 
 	// Create each table individually, ignoring "already exists" errors
-	tables := []string{
-		`:create cie_file { id: String => path: String, hash: String, language: String, size: Int }`,
-		`:create cie_function { id: String => name: String, signature: String, file_path: String, start_line: Int, end_line: Int, start_col: Int, end_col: Int }`,
-		`:create cie_function_code { function_id: String => code_text: String }`,
-		fmt.Sprintf(`:create cie_function_embedding { function_id: String => embedding: <F32; %d> }`, dim),
-		`:create cie_defines { id: String => file_id: String, function_id: String }`,
-		`:create cie_calls { id: String => caller_id: String, callee_id: String, call_line: Int default 0 }`,
-		`:create cie_import { id: String => file_path: String, import_path: String, alias: String, start_line: Int }`,
-		`:create cie_type { id: String => name: String, kind: String, file_path: String, start_line: Int, end_line: Int, start_col: Int, end_col: Int }`,
-		`:create cie_type_code { type_id: String => code_text: String }`,
-		fmt.Sprintf(`:create cie_type_embedding { type_id: String => embedding: <F32; %d> }`, dim),
-		`:create cie_defines_type { id: String => file_id: String, type_id: String }`,
-		// Struct field entities for interface dispatch resolution
-		`:create cie_field { id: String => struct_name: String, field_name: String, field_type: String, file_path: String, line: Int }`,
-		// Implements edges: concrete type -> interface
-		`:create cie_implements { id: String => type_name: String, interface_name: String, file_path: String }`,
-		// Project metadata for incremental indexing
-		`:create cie_project_meta { key: String => value: String }`,
-	}
+	tables := schemaTables(dim)
+	tables = append(tables, snapshotTables(tables)...)
 
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -224,10 +207,71 @@ func (b *EmbeddedBackend) EnsureSchema() error {
 	// Schema migrations: add columns introduced in newer versions.
 	// CozoDB doesn't support ALTER TABLE, so we migrate by copying data.
 	b.migrateCallsCallLine()
+	b.migrateDocComment("cie_function", "id, name, signature, file_path, start_line, end_line, start_col, end_col",
+		"id: String => name: String, signature: String, file_path: String, start_line: Int, end_line: Int, start_col: Int, end_col: Int")
+	b.migrateDocComment("cie_type", "id, name, kind, file_path, start_line, end_line, start_col, end_col",
+		"id: String => name: String, kind: String, file_path: String, start_line: Int, end_line: Int, start_col: Int, end_col: Int")
 
 	return nil
 }
 
+// schemaTables returns the :create statements of the CIE tables.
+func schemaTables(dim int) []string {
+	return []string{
+		`:create cie_file { id: String => path: String, hash: String, language: String, size: Int }`,
+		`:create cie_function { id: String => name: String, signature: String, file_path: String, start_line: Int, end_line: Int, start_col: Int, end_col: Int, doc_comment: String default "" }`,
+		`:create cie_function_code { function_id: String => code_text: String }`,
+		fmt.Sprintf(`:create cie_function_embedding { function_id: String => embedding: <F32; %d> }`, dim),
+		`:create cie_defines { id: String => file_id: String, function_id: String }`,
+		`:create cie_calls { id: String => caller_id: String, callee_id: String, call_line: Int default 0 }`,
+		`:create cie_import { id: String => file_path: String, import_path: String, alias: String, start_line: Int }`,
+		`:create cie_func_ref { id: String => from_id: String, to_id: String, kind: String, file_path: String, line: Int }`,
+		`:create cie_type_ref { id: String => from_id: String, type_id: String, kind: String, file_path: String, line: Int }`,
+		`:create cie_type_param { id: String => owner_id: String, name: String, constraint: String, position: Int, file_path: String }`,
+		`:create cie_instantiation { id: String => from_id: String, target_id: String, target_kind: String, type_args: String, file_path: String, line: Int }`,
+		`:create cie_concurrency { id: String => function_id: String, kind: String, target: String, target_id: String, file_path: String, line: Int }`,
+		`:create cie_error_site { id: String => function_id: String, kind: String, name: String, message: String, file_path: String, line: Int }`,
+		`:create cie_test { id: String => function_id: String, name: String, kind: String, suite: String, framework: String, file_path: String, line: Int }`,
+		`:create cie_function_metrics { function_id: String => file_path: String, complexity: Int, max_nesting: Int, params: Int, loc: Int, comment_lines: Int, comment_ratio: Float }`,
+		`:create cie_coverage { function_id: String => file_path: String, covered: Int, total: Int, percent: Float, uncovered: String, source: String, imported_at: Int }`,
+		`:create cie_function_churn { function_id: String => file_path: String, commits: Int, authors: Int, lines_changed: Int, first_changed: Int, last_changed: Int }`,
+		`:create cie_change_coupling { function_id: String, coupled_id: String => file_path: String, co_changes: Int, degree: Float }`,
+		`:create cie_type { id: String => name: String, kind: String, file_path: String, start_line: Int, end_line: Int, start_col: Int, end_col: Int, doc_comment: String default "" }`,
+		`:create cie_type_code { type_id: String => code_text: String }`,
+		fmt.Sprintf(`:create cie_type_embedding { type_id: String => embedding: <F32; %d> }`, dim),
+		`:create cie_defines_type { id: String => file_id: String, type_id: String }`,
+		// Struct field entities for interface dispatch resolution
+		`:create cie_field { id: String => struct_name: String, field_name: String, field_type: String, file_path: String, line: Int }`,
+		// Implements edges: concrete type -> interface
+		`:create cie_implements { id: String => type_name: String, interface_name: String, file_path: String }`,
+		// SQL schema from .sql migrations and dumps
+		`:create cie_sql_table { id: String => name: String, file_path: String, start_line: Int, end_line: Int, code_text: String }`,
+		`:create cie_sql_column { id: String => table_name: String, name: String, data_type: String, nullable: Bool, primary_key: Bool, file_path: String, line: Int }`,
+		`:create cie_sql_index { id: String => name: String, table_name: String, columns: String, is_unique: Bool, file_path: String, line: Int }`,
+		`:create cie_sql_foreign_key { id: String => table_name: String, columns: String, ref_table: String, ref_columns: String, file_path: String, line: Int }`,
+		// Table access edges: function -> SQL table (read/write)
+		`:create cie_table_access { id: String => function_id: String, table_name: String, access: String, file_path: String, line: Int }`,
+		// GraphQL SDL fields and field -> resolver edges
+		`:create cie_graphql_field { id: String => type_name: String, name: String, field_type: String, arguments: String, operation: String, file_path: String, line: Int }`,
+		`:create cie_graphql_resolver { id: String => type_name: String, field_name: String, function_id: String, framework: String, file_path: String, line: Int }`,
+		// Infrastructure topology: deployable units, their env vars and edges
+		`:create cie_topology_node { id: String => kind: String, name: String, image: String, command: String, binary: String, ports: String, labels: String, file_path: String, line: Int }`,
+		`:create cie_topology_env { id: String => node_id: String, name: String, value: String, file_path: String, line: Int }`,
+		`:create cie_topology_edge { id: String => from_id: String, to_id: String, kind: String, file_path: String, line: Int }`,
+		// Markdown doc chunks, their embeddings and doc -> symbol links
+		`:create cie_doc_chunk { id: String => file_path: String, heading: String, anchor: String, level: Int, start_line: Int, end_line: Int, text: String }`,
+		fmt.Sprintf(`:create cie_doc_chunk_embedding { chunk_id: String => embedding: <F32; %d> }`, dim),
+		`:create cie_doc_link { id: String => chunk_id: String, target_id: String, target_kind: String, symbol: String, file_path: String, line: Int }`,
+		// Package-level variables and constants
+		`:create cie_variable { id: String => name: String, kind: String, type: String, value: String, file_path: String, code_text: String, start_line: Int, end_line: Int }`,
+		// Packages and package -> package import edges
+		`:create cie_package { id: String => path: String, name: String, language: String, file_count: Int, doc: String }`,
+		`:create cie_package_import { id: String => from_path: String, to_path: String, import_count: Int }`,
+		// Project metadata for incremental indexing
+		`:create cie_project_meta { key: String => value: String }`,
+	}
+}
+
 // migrateCallsCallLine adds the call_line column to cie_calls if it was created
 // with an older schema (pre-v0.7.9). CozoDB doesn't support ALTER TABLE, so we
 // copy data to a temp table, recreate with the new schema, and copy back.
@@ -260,6 +304,37 @@ func (b *EmbeddedBackend) migrateCallsCallLine() {
 	_, _ = b.db.Run(`::remove cie_calls_mig`, nil)
 }
 
+// migrateDocComment adds the doc_comment column to cie_function or cie_type if
+// the relation was created before doc comments were indexed. columns lists the
+// existing columns and spec their declaration. Same copy/recreate approach as
+// migrateCallsCallLine. Caller must hold b.mu.
+func (b *EmbeddedBackend) migrateDocComment(relation, columns, spec string) {
+	// Probe: try reading doc_comment — if it works, no migration needed.
+	_, err := b.db.Run(fmt.Sprintf(`?[id] := *%s { id, doc_comment } :limit 1`, relation), nil)
+	if err == nil {
+		return
+	}
+
+	tmp := relation + "_mig"
+	_, err = b.db.Run(fmt.Sprintf(`?[%s] := *%s { %s } :replace %s { %s }`, columns, relation, columns, tmp, spec), nil)
+	if err != nil {
+		return // can't migrate, doc comments stay unavailable
+	}
+
+	_, _ = b.db.Run(`::remove `+relation, nil)
+	_, err = b.db.Run(fmt.Sprintf(`:create %s { %s, doc_comment: String default "" }`, relation, spec), nil)
+	if err != nil {
+		// Restore from temp if create fails
+		_, _ = b.db.Run(fmt.Sprintf(`?[%s] := *%s { %s } :replace %s { %s }`, columns, tmp, columns, relation, spec), nil)
+		_, _ = b.db.Run(`::remove `+tmp, nil)
+		return
+	}
+
+	// Copy data back; doc_comment takes its default until the files are re-indexed
+	_, _ = b.db.Run(fmt.Sprintf(`?[%s] := *%s { %s } :put %s { %s }`, columns, tmp, columns, relation, columns), nil)
+	_, _ = b.db.Run(`::remove `+tmp, nil)
+}
+
 // CreateHNSWIndex creates HNSW indexes for semantic search.
 // Should be called after schema creation.
 // dimensions: embedding vector size (768 for nomic-embed-text, 1536 for OpenAI)
@@ -271,6 +346,7 @@ func (b *EmbeddedBackend) CreateHNSWIndex(dimensions int) error {
 	indexes := []string{
 		fmt.Sprintf(`::hnsw create cie_function_embedding:embedding_idx { dim: %d, m: 16, ef_construction: 200, distance: Cosine, fields: [embedding] }`, dimensions),
 		fmt.Sprintf(`::hnsw create cie_type_embedding:embedding_idx { dim: %d, m: 16, ef_construction: 200, distance: Cosine, fields: [embedding] }`, dimensions),
+		fmt.Sprintf(`::hnsw create cie_doc_chunk_embedding:embedding_idx { dim: %d, m: 16, ef_construction: 200, distance: Cosine, fields: [embedding] }`, dimensions),
 	}
 
 	b.mu.Lock()
@@ -343,6 +419,53 @@ func (b *EmbeddedBackend) DeleteEntitiesForFile(filePath string) error {
 		 :rm cie_calls {id}`,
 		`?[id] := *cie_calls{id, callee_id}, *cie_function{id: callee_id, file_path}, file_path = $path
 		 :rm cie_calls {id}`,
+		// Delete function references made in this file or to functions declared in it
+		`?[id] := *cie_func_ref{id, file_path}, file_path = $path
+		 :rm cie_func_ref {id}`,
+		`?[id] := *cie_func_ref{id, to_id}, *cie_function{id: to_id, file_path}, file_path = $path
+		 :rm cie_func_ref {id}`,
+		// Delete doc links to functions and types declared in this file or to the file itself
+		`?[id] := *cie_doc_link{id, target_id}, *cie_function{id: target_id, file_path}, file_path = $path
+		 :rm cie_doc_link {id}`,
+		`?[id] := *cie_doc_link{id, target_id}, *cie_type{id: target_id, file_path}, file_path = $path
+		 :rm cie_doc_link {id}`,
+		`?[id] := *cie_doc_link{id, target_id}, *cie_file{id: target_id, path}, path = $path
+		 :rm cie_doc_link {id}`,
+		// Delete type references made in this file or to types declared in it
+		`?[id] := *cie_type_ref{id, file_path}, file_path = $path
+		 :rm cie_type_ref {id}`,
+		`?[id] := *cie_type_ref{id, type_id}, *cie_type{id: type_id, file_path}, file_path = $path
+		 :rm cie_type_ref {id}`,
+		// Delete type parameters declared in this file, and instantiations
+		// made in it or of generics declared in it
+		`?[id] := *cie_type_param{id, file_path}, file_path = $path
+		 :rm cie_type_param {id}`,
+		`?[id] := *cie_instantiation{id, file_path}, file_path = $path
+		 :rm cie_instantiation {id}`,
+		`?[id] := *cie_instantiation{id, target_id}, *cie_function{id: target_id, file_path}, file_path = $path
+		 :rm cie_instantiation {id}`,
+		`?[id] := *cie_instantiation{id, target_id}, *cie_type{id: target_id, file_path}, file_path = $path
+		 :rm cie_instantiation {id}`,
+		// Delete concurrency operations in this file
+		`?[id] := *cie_concurrency{id, file_path}, file_path = $path
+		 :rm cie_concurrency {id}`,
+		// Delete error sites in this file
+		`?[id] := *cie_error_site{id, file_path}, file_path = $path
+		 :rm cie_error_site {id}`,
+		// Delete tests in this file
+		`?[id] := *cie_test{id, file_path}, file_path = $path
+		 :rm cie_test {id}`,
+		// Delete metrics of functions in this file
+		`?[function_id] := *cie_function_metrics{function_id, file_path}, file_path = $path
+		 :rm cie_function_metrics {function_id}`,
+		// Delete coverage of functions in this file: their lines may have moved
+		`?[function_id] := *cie_coverage{function_id, file_path}, file_path = $path
+		 :rm cie_coverage {function_id}`,
+		// Delete churn and change coupling of functions in this file: their IDs change with their lines
+		`?[function_id] := *cie_function_churn{function_id, file_path}, file_path = $path
+		 :rm cie_function_churn {function_id}`,
+		`?[function_id, coupled_id] := *cie_change_coupling{function_id, coupled_id, file_path}, file_path = $path
+		 :rm cie_change_coupling {function_id, coupled_id}`,
 		// Delete defines edges for this file
 		`?[id] := *cie_defines{id, file_id}, *cie_file{id: file_id, path}, path = $path
 		 :rm cie_defines {id}`,
@@ -370,6 +493,44 @@ func (b *EmbeddedBackend) DeleteEntitiesForFile(filePath string) error {
 		// Delete imports for this file
 		`?[id] := *cie_import{id, file_path}, file_path = $path
 		 :rm cie_import {id}`,
+		// Delete table access edges from functions in this file
+		`?[id] := *cie_table_access{id, file_path}, file_path = $path
+		 :rm cie_table_access {id}`,
+		// Delete SQL schema objects declared in this file
+		`?[id] := *cie_sql_table{id, file_path}, file_path = $path
+		 :rm cie_sql_table {id}`,
+		`?[id] := *cie_sql_column{id, file_path}, file_path = $path
+		 :rm cie_sql_column {id}`,
+		`?[id] := *cie_sql_index{id, file_path}, file_path = $path
+		 :rm cie_sql_index {id}`,
+		`?[id] := *cie_sql_foreign_key{id, file_path}, file_path = $path
+		 :rm cie_sql_foreign_key {id}`,
+		// Delete variables declared in this file
+		`?[id] := *cie_variable{id, file_path}, file_path = $path
+		 :rm cie_variable {id}`,
+		// Delete GraphQL fields declared in this file and resolvers defined in it.
+		// gqlgen resolvers bound to its fields go too: they are matched against the
+		// schema and relinked after the write.
+		`?[id] := *cie_graphql_resolver{id, type_name, field_name, framework}, framework = 'gqlgen',
+		   *cie_graphql_field{type_name, name: field_name, file_path}, file_path = $path
+		 :rm cie_graphql_resolver {id}`,
+		`?[id] := *cie_graphql_field{id, file_path}, file_path = $path
+		 :rm cie_graphql_field {id}`,
+		`?[id] := *cie_graphql_resolver{id, file_path}, file_path = $path
+		 :rm cie_graphql_resolver {id}`,
+		`?[id] := *cie_topology_node{id, file_path}, file_path = $path
+		 :rm cie_topology_node {id}`,
+		`?[id] := *cie_topology_env{id, file_path}, file_path = $path
+		 :rm cie_topology_env {id}`,
+		`?[id] := *cie_topology_edge{id, file_path}, file_path = $path
+		 :rm cie_topology_edge {id}`,
+		// Delete doc chunks of this Markdown file, their embeddings and links
+		`?[chunk_id] := *cie_doc_chunk{id: chunk_id, file_path}, file_path = $path
+		 :rm cie_doc_chunk_embedding {chunk_id}`,
+		`?[id] := *cie_doc_chunk{id, file_path}, file_path = $path
+		 :rm cie_doc_chunk {id}`,
+		`?[id] := *cie_doc_link{id, file_path}, file_path = $path
+		 :rm cie_doc_link {id}`,
 		// Delete the file itself
 		`?[id] := *cie_file{id, path}, path = $path
 		 :rm cie_file {id}`,
@@ -0,0 +1,144 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package storage
+
+import (
+	"strings"
+)
+
+// Snapshots keep the index of past commits next to the index of HEAD.
+//
+// Each relation written by the indexer has a history counterpart,
+// cie_hist_<name>, with the same columns plus a Validity key column. Snapshot
+// N asserts at validity N the rows that are new or changed since snapshot
+// N-1 and retracts the rows that disappeared, so the rows of files unchanged
+// between snapshots are stored once. cie_hist_<name>{... @ N} returns the
+// relation as it was at snapshot N. cie_snapshot lists the snapshots by
+// commit.
+
+const (
+	// HistoryPrefix replaces the "cie_" prefix in history relation names.
+	HistoryPrefix = "cie_hist_"
+
+	// ValidityColumn is the Validity key column of history relations.
+	ValidityColumn = "valid_at"
+)
+
+// snapshotCatalog creates the snapshot catalog: one row per snapshot, seq
+// being the validity of its rows in the history relations.
+const snapshotCatalog = `:create cie_snapshot { seq: Int => sha: String, ref: String, indexed_at: Int, files: Int, functions: Int }`
+
+// unsnapshotted lists the relations without history: embeddings, whose HNSW
+// indexes only cover HEAD, data imported from coverage profiles and the git
+// history, which describe HEAD, and project metadata.
+var unsnapshotted = map[string]bool{
+	"cie_function_embedding":  true,
+	"cie_type_embedding":      true,
+	"cie_doc_chunk_embedding": true,
+	"cie_coverage":            true,
+	"cie_function_churn":      true,
+	"cie_change_coupling":     true,
+	"cie_project_meta":        true,
+}
+
+// HistoryRelation is a relation kept in snapshots.
+type HistoryRelation struct {
+	Name   string   // Relation at HEAD: "cie_function"
+	Keys   []string // Key columns, without the validity
+	Values []string // Value columns
+	spec   string   // Column declarations: "id: String => name: String, ..."
+}
+
+// History returns the name of the history relation: "cie_hist_function".
+func (r HistoryRelation) History() string {
+	return HistoryPrefix + strings.TrimPrefix(r.Name, "cie_")
+}
+
+// Columns returns the key then value columns.
+func (r HistoryRelation) Columns() []string {
+	return append(append([]string{}, r.Keys...), r.Values...)
+}
+
+// historyRelations are the relations kept in snapshots, in schema order.
+var historyRelations = parseHistoryRelations(schemaTables(768))
+
+// HistoryRelations returns the relations kept in snapshots.
+func HistoryRelations() []HistoryRelation {
+	return historyRelations
+}
+
+// IsHistoryRelation reports whether a relation is kept in snapshots.
+func IsHistoryRelation(name string) bool {
+	for _, r := range historyRelations {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotTables returns the :create statements of the history relations
+// of tables, followed by the snapshot catalog.
+func snapshotTables(tables []string) []string {
+	var stmts []string
+	for _, r := range parseHistoryRelations(tables) {
+		keys, values, _ := strings.Cut(r.spec, "=>")
+		stmts = append(stmts, ":create "+r.History()+" { "+strings.TrimSpace(keys)+", "+ValidityColumn+": Validity => "+strings.TrimSpace(values)+" }")
+	}
+	return append(stmts, snapshotCatalog)
+}
+
+// parseHistoryRelations parses the :create statements of the relations kept
+// in snapshots.
+func parseHistoryRelations(tables []string) []HistoryRelation {
+	var relations []HistoryRelation
+	for _, stmt := range tables {
+		rest, ok := strings.CutPrefix(stmt, ":create ")
+		if !ok {
+			continue
+		}
+		name, spec, ok := strings.Cut(rest, "{")
+		name = strings.TrimSpace(name)
+		if !ok || unsnapshotted[name] {
+			continue
+		}
+		spec = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(spec), "}"))
+		keys, values, _ := strings.Cut(spec, "=>")
+		relations = append(relations, HistoryRelation{
+			Name:   name,
+			Keys:   columnNames(keys),
+			Values: columnNames(values),
+			spec:   spec,
+		})
+	}
+	return relations
+}
+
+// columnNames returns the names of column declarations: "id: String,
+// line: Int default 0" → [id line].
+func columnNames(decls string) []string {
+	var names []string
+	for _, decl := range strings.Split(decls, ",") {
+		if name, _, ok := strings.Cut(decl, ":"); ok {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	return names
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+//go:build cgo
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHistoryRelations(t *testing.T) {
+	byName := make(map[string]HistoryRelation)
+	for _, r := range HistoryRelations() {
+		byName[r.Name] = r
+	}
+
+	calls, ok := byName["cie_calls"]
+	if !ok {
+		t.Fatal("cie_calls should be kept in snapshots")
+	}
+	if calls.History() != "cie_hist_calls" {
+		t.Errorf("History() = %q, want cie_hist_calls", calls.History())
+	}
+	if got := strings.Join(calls.Columns(), ","); got != "id,caller_id,callee_id,call_line" {
+		t.Errorf("Columns() = %q", got)
+	}
+
+	for _, name := range []string{"cie_function_embedding", "cie_coverage", "cie_function_churn", "cie_change_coupling", "cie_project_meta"} {
+		if IsHistoryRelation(name) {
+			t.Errorf("%s should not be kept in snapshots", name)
+		}
+	}
+	if !IsHistoryRelation("cie_function") || IsHistoryRelation("cie_hist_function") {
+		t.Error("IsHistoryRelation should match HEAD relation names")
+	}
+}
+
+func TestSnapshotTables(t *testing.T) {
+	stmts := snapshotTables([]string{
+		`:create cie_calls { id: String => caller_id: String, callee_id: String, call_line: Int default 0 }`,
+		`:create cie_change_coupling { function_id: String, coupled_id: String => file_path: String, co_changes: Int, degree: Float }`,
+		`:create cie_coverage { function_id: String => file_path: String }`,
+	})
+	want := []string{
+		`:create cie_hist_calls { id: String, valid_at: Validity => caller_id: String, callee_id: String, call_line: Int default 0 }`,
+		snapshotCatalog,
+	}
+	if strings.Join(stmts, "\n") != strings.Join(want, "\n") {
+		t.Errorf("snapshotTables() =\n%s\nwant\n%s", strings.Join(stmts, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestEmbeddedBackend_SnapshotTimeTravel checks that history relations
+// return each key as of the latest assertion or retraction before a snapshot.
+func TestEmbeddedBackend_SnapshotTimeTravel(t *testing.T) {
+	backend := setupTestStorage(t)
+	defer func() {
+		_ = backend.Close()
+	}()
+	if err := backend.EnsureSchema(); err != nil {
+		t.Fatalf("EnsureSchema failed: %v", err)
+	}
+
+	ctx := context.Background()
+	writes := []string{
+		`?[id, valid_at, path, hash, language, size] <- [['a', [1, true], 'a.go', 'h1', 'go', 10], ['b', [1, true], 'b.go', 'h1', 'go', 10]]
+		:put cie_hist_file { id, valid_at => path, hash, language, size }`,
+		`?[id, valid_at, path, hash, language, size] <- [['a', [2, true], 'a.go', 'h2', 'go', 12], ['b', [2, false], 'b.go', 'h1', 'go', 10]]
+		:put cie_hist_file { id, valid_at => path, hash, language, size }`,
+	}
+	for _, w := range writes {
+		if err := backend.Execute(ctx, w); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+
+	for seq, want := range map[int]string{1: "a.go:h1,b.go:h1", 2: "a.go:h2", 3: "a.go:h2"} {
+		result, err := backend.Query(ctx, fmt.Sprintf(`?[path, hash] := *cie_hist_file{path, hash @ %d}`, seq))
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		var got []string
+		for _, row := range result.Rows {
+			got = append(got, row[0].(string)+":"+row[1].(string))
+		}
+		if strings.Join(got, ",") != want {
+			t.Errorf("snapshot %d = %v, want %s", seq, got, want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultChangeCouplingLimit is how many coupled functions
+	// cie_change_coupling lists by default.
+	defaultChangeCouplingLimit = 15
+
+	// defaultChangeCouplingMinDegree is the lowest degree listed by default.
+	defaultChangeCouplingMinDegree = 0.2
+)
+
+// ChangeCouplingArgs holds arguments for the change_coupling tool.
+type ChangeCouplingArgs struct {
+	// Function is the function whose co-changing functions are listed
+	// (e.g., "TracePath", "Server.HandleOrder").
+	Function string
+
+	// PathPattern disambiguates functions with the same name.
+	PathPattern string
+
+	// MinDegree drops functions changed in fewer than this share of the
+	// commits changing Function (default 0.2).
+	MinDegree float64
+
+	// Limit caps the functions listed (default 15).
+	Limit int
+}
+
+// coupledFunction is a function changed together with the one asked about.
+type coupledFunction struct {
+	ID        string
+	Name      string
+	File      string
+	Line      int
+	CoChanges int
+	Degree    float64
+}
+
+// ChangeCoupling lists the functions that usually change in the same
+// commits as a function, from the git history imported with cie history.
+// Pairs without a call between them point at coupling the call graph does
+// not show.
+func ChangeCoupling(ctx context.Context, client Querier, args ChangeCouplingArgs) (*ToolResult, error) {
+	if args.Function == "" {
+		return NewError("Error: 'function' is required"), nil
+	}
+	minDegree := args.MinDegree
+	if minDegree <= 0 {
+		minDegree = defaultChangeCouplingMinDegree
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultChangeCouplingLimit
+	}
+
+	countResult, err := client.Query(ctx, `?[count(function_id)] := *cie_function_churn { function_id }`)
+	if err != nil {
+		if strings.Contains(err.Error(), "cie_function_churn") {
+			return NewResult("Git history is not available in this index: run `cie history` to import churn and change coupling.\n"), nil
+		}
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	if len(countResult.Rows) == 0 || rowLine(countResult.Rows[0][0]) == 0 {
+		return NewResult("No git history imported. Run `cie history` (optionally `--since \"1 year ago\"`) after `cie index`.\n"), nil
+	}
+
+	condition := fmt.Sprintf("(name = %q or ends_with(name, %q))", args.Function, "."+args.Function)
+	if args.PathPattern != "" {
+		condition += fmt.Sprintf(", regex_matches(file_path, %s)", QuoteCozoPattern(EscapeRegex(args.PathPattern)))
+	}
+	script := fmt.Sprintf(`?[id, name, file_path, start_line, end_line] := *cie_function { id, name, file_path, start_line, end_line }, %s :limit 10`, condition)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, script)), nil
+	}
+	switch {
+	case len(result.Rows) == 0:
+		return NewResult(fmt.Sprintf("No function named `%s` found.\n\nUse **cie_find_function** to check the name.\n", args.Function)), nil
+	case len(result.Rows) > 1:
+		locations := make([]FunctionLocation, 0, len(result.Rows))
+		for _, row := range result.Rows {
+			locations = append(locations, FunctionLocation{
+				Name:      AnyToString(row[1]),
+				FilePath:  AnyToString(row[2]),
+				StartLine: rowLine(row[3]),
+				EndLine:   rowLine(row[4]),
+			})
+		}
+		return NewResult(formatAmbiguousFunctions(locations)), nil
+	}
+	row := result.Rows[0]
+	id, name, file, line := AnyToString(row[0]), AnyToString(row[1]), AnyToString(row[2]), rowLine(row[3])
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Change coupling for `%s`\n\n", name)
+	churn, err := client.Query(ctx, fmt.Sprintf(`?[commits, authors, last_changed] := *cie_function_churn { function_id: %q, commits, authors, last_changed }`, id))
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	if len(churn.Rows) == 0 {
+		fmt.Fprintf(&sb, "`%s` (%s:%d) did not change in the commits walked by `cie history`. Widen the range with `cie history --since` or `--max-commits`.\n", name, file, line)
+		return NewResult(sb.String()), nil
+	}
+	lastChanged, _ := strconv.ParseInt(AnyToString(churn.Rows[0][2]), 10, 64)
+	fmt.Fprintf(&sb, "`%s` (%s:%d) changed in %d commits by %d authors, last on %s.\n\n",
+		name, file, line, rowLine(churn.Rows[0][0]), rowLine(churn.Rows[0][1]), time.Unix(lastChanged, 0).UTC().Format("2006-01-02"))
+
+	script = fmt.Sprintf(`?[coupled_id, name, file_path, start_line, co_changes, degree] :=
+  *cie_change_coupling { function_id: %q, coupled_id, co_changes, degree }, degree >= %g,
+  *cie_function { id: coupled_id, name, file_path, start_line }
+:order -degree, -co_changes, name :limit %d`, id, minDegree, limit)
+	result, err = client.Query(ctx, script)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, script)), nil
+	}
+	var coupled []coupledFunction
+	for _, row := range result.Rows {
+		if len(row) < 6 {
+			continue
+		}
+		degree, _ := strconv.ParseFloat(AnyToString(row[5]), 64)
+		coupled = append(coupled, coupledFunction{
+			ID:        AnyToString(row[0]),
+			Name:      AnyToString(row[1]),
+			File:      AnyToString(row[2]),
+			Line:      rowLine(row[3]),
+			CoChanges: rowLine(row[4]),
+			Degree:    degree,
+		})
+	}
+	if len(coupled) == 0 {
+		fmt.Fprintf(&sb, "No function changed with it in %.0f%% or more of its commits (pairs need at least 2 shared commits).\n", minDegree*100)
+		return NewResult(sb.String()), nil
+	}
+
+	links := queryCallLinks(ctx, client, id)
+	hidden := 0
+	sb.WriteString("| # | Function | Changed together | Degree | Call link |\n")
+	sb.WriteString("|---|----------|------------------|--------|-----------|\n")
+	for i, c := range coupled {
+		link := links[c.ID]
+		if link == "" {
+			link = "none"
+			hidden++
+		}
+		fmt.Fprintf(&sb, "| %d | `%s` (%s:%d) | %d | %.0f%% | %s |\n", i+1, c.Name, c.File, c.Line, c.CoChanges, c.Degree*100, link)
+	}
+	fmt.Fprintf(&sb, "\n_Degree is the share of the commits changing `%s` that also changed the function.", name)
+	if hidden > 0 {
+		fmt.Fprintf(&sb, " %d functions change with it without calling it or being called by it: shared data, duplicated logic or a protocol the call graph does not show.", hidden)
+	}
+	sb.WriteString("_\n")
+	return NewResult(sb.String()), nil
+}
+
+// queryCallLinks returns how the direct callers and callees of a function
+// relate to it: "calls" for callees, "called by" for callers.
+func queryCallLinks(ctx context.Context, client Querier, id string) map[string]string {
+	links := make(map[string]string)
+	script := fmt.Sprintf(`?[other, direction] := *cie_calls { caller_id: %q, callee_id: other }, direction = "calls"
+?[other, direction] := *cie_calls { caller_id: other, callee_id: %q }, direction = "called by"`, id, id)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return links
+	}
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		other, direction := AnyToString(row[0]), AnyToString(row[1])
+		if links[other] != "" && links[other] != direction {
+			direction = "calls, called by"
+		}
+		links[other] = direction
+	}
+	return links
+}
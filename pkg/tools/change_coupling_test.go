@@ -0,0 +1,107 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// changeCouplingMockClient serves the history of TracePath: formatTrace,
+// which it calls, changes with it in most commits; loadConfig changes with
+// it without any call between them.
+func changeCouplingMockClient(scripts *[]string) *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			*scripts = append(*scripts, script)
+			switch {
+			case strings.Contains(script, "count(function_id)"):
+				return &QueryResult{Rows: [][]any{{float64(120)}}}, nil
+			case strings.Contains(script, "*cie_function_churn"):
+				return &QueryResult{Rows: [][]any{{float64(10), float64(3), float64(1790000000)}}}, nil
+			case strings.Contains(script, "*cie_change_coupling"):
+				return &QueryResult{Rows: [][]any{
+					{"fn:format", "formatTrace", "pkg/tools/trace.go", float64(300), float64(8), 0.8},
+					{"fn:config", "loadConfig", "cmd/cie/config.go", float64(40), float64(4), 0.4},
+				}}, nil
+			case strings.Contains(script, "*cie_calls"):
+				return &QueryResult{Rows: [][]any{{"fn:format", "calls"}}}, nil
+			case strings.Contains(script, "*cie_function"):
+				return &QueryResult{Rows: [][]any{{"fn:trace", "TracePath", "pkg/tools/trace.go", float64(120), float64(200)}}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestChangeCoupling(t *testing.T) {
+	var scripts []string
+	result, err := ChangeCoupling(context.Background(), changeCouplingMockClient(&scripts), ChangeCouplingArgs{Function: "TracePath", MinDegree: 0.3})
+	if err != nil {
+		t.Fatalf("ChangeCoupling: %v", err)
+	}
+	for _, want := range []string{
+		"## Change coupling for `TracePath`",
+		"`TracePath` (pkg/tools/trace.go:120) changed in 10 commits by 3 authors, last on 2026-09-21.",
+		"| 1 | `formatTrace` (pkg/tools/trace.go:300) | 8 | 80% | calls |",
+		"| 2 | `loadConfig` (cmd/cie/config.go:40) | 4 | 40% | none |",
+		"1 functions change with it without calling it",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("output missing %q:\n%s", want, result.Text)
+		}
+	}
+	joined := strings.Join(scripts, "\n")
+	for _, want := range []string{`function_id: "fn:trace"`, "degree >= 0.3", `caller_id: "fn:trace"`, `callee_id: "fn:trace"`} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("scripts missing %q:\n%s", want, joined)
+		}
+	}
+}
+
+func TestChangeCoupling_NoHistory(t *testing.T) {
+	result, err := ChangeCoupling(context.Background(), &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			return &QueryResult{Rows: [][]any{{float64(0)}}}, nil
+		},
+	}, ChangeCouplingArgs{Function: "TracePath"})
+	if err != nil {
+		t.Fatalf("ChangeCoupling: %v", err)
+	}
+	if !strings.Contains(result.Text, "No git history imported") {
+		t.Errorf("expected a cie history hint, got:\n%s", result.Text)
+	}
+
+	result, _ = ChangeCoupling(context.Background(), &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			return nil, errors.New("relation cie_function_churn not found")
+		},
+	}, ChangeCouplingArgs{Function: "TracePath"})
+	if result.IsError || !strings.Contains(result.Text, "cie history") {
+		t.Errorf("expected a cie history hint for old indexes, got:\n%s", result.Text)
+	}
+
+	result, _ = ChangeCoupling(context.Background(), &MockCIEClient{}, ChangeCouplingArgs{})
+	if !result.IsError {
+		t.Errorf("missing function should fail, got:\n%s", result.Text)
+	}
+}
@@ -29,6 +29,7 @@ import (
 type GetFunctionCodeArgs struct {
 	FunctionName string
 	FullCode     bool // If true, return complete code without truncation
+	AsOf         bool // client reads a snapshot: HEAD coverage is left out
 }
 
 // GetFunctionCode retrieves the full source code of a function.
@@ -82,7 +83,17 @@ func GetFunctionCode(ctx context.Context, client Querier, args GetFunctionCodeAr
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("**Function**: %s\n", name))
 	sb.WriteString(fmt.Sprintf("**File**: %s:%v-%v\n", filePath, startLine, endLine))
-	sb.WriteString(fmt.Sprintf("**Signature**: %s\n\n", signature))
+	sb.WriteString(fmt.Sprintf("**Signature**: %s\n", signature))
+	docCondition := fmt.Sprintf("name = %q, fn_file = %q", name, filePath)
+	if docs := queryFunctionDocs(ctx, client, docCondition)[name]; len(docs) > 0 {
+		sb.WriteString(fmt.Sprintf("**Documented in**: %s\n", strings.Join(docs, ", ")))
+	}
+	if !args.AsOf {
+		if coverage := queryFunctionCoverage(ctx, client, docCondition); len(coverage) > 0 {
+			sb.WriteString(fmt.Sprintf("**Coverage**: %s (%s)\n", coverage[0].summary(), coverageOrigin(coverage[0])))
+		}
+	}
+	sb.WriteString("\n")
 	sb.WriteString(fmt.Sprintf("```%s\n%s\n```", lang, codeText))
 
 	if truncated {
@@ -287,13 +298,13 @@ func GetFileSummary(ctx context.Context, client Querier, args GetFileSummaryArgs
 
 func queryFileSummaryEntities(ctx context.Context, client Querier, filePath string) (*QueryResult, *QueryResult, error) {
 	escapedPath := EscapeRegex(filePath)
-	typeScript := fmt.Sprintf(`?[name, kind, start_line] := *cie_type { name, kind, file_path, start_line }, regex_matches(file_path, "(?i)%s") :order start_line :limit 100`, escapedPath)
+	typeScript := fmt.Sprintf(`?[name, kind, start_line, doc_comment] := *cie_type { name, kind, file_path, start_line, doc_comment }, regex_matches(file_path, "(?i)%s") :order start_line :limit 100`, escapedPath)
 	typeResult, _ := client.Query(ctx, typeScript)
 	if typeResult == nil {
 		typeResult = &QueryResult{}
 	}
 
-	funcScript := fmt.Sprintf(`?[name, signature, start_line] := *cie_function { name, signature, file_path, start_line }, regex_matches(file_path, "(?i)%s") :order start_line :limit 100`, escapedPath)
+	funcScript := fmt.Sprintf(`?[name, signature, start_line, doc_comment] := *cie_function { name, signature, file_path, start_line, doc_comment }, regex_matches(file_path, "(?i)%s") :order start_line :limit 100`, escapedPath)
 	funcResult, err := client.Query(ctx, funcScript)
 	return typeResult, funcResult, err
 }
@@ -317,6 +328,7 @@ func formatFileSummaryTypes(sb *strings.Builder, rows [][]any) {
 	_, _ = fmt.Fprintf(sb, "## Types (%d)\n\n", len(rows))
 	for _, row := range rows {
 		_, _ = fmt.Fprintf(sb, "• **Line %v**: `%s` (%s)\n", row[2], anyToStr(row[0]), anyToStr(row[1]))
+		writeDocSummary(sb, row)
 	}
 	sb.WriteString("\n")
 }
@@ -348,6 +360,17 @@ func formatFuncSection(sb *strings.Builder, title string, rows [][]any) {
 		if len(signature) > 0 && len(signature) < 100 {
 			_, _ = fmt.Fprintf(sb, "  `%s`\n", signature)
 		}
+		writeDocSummary(sb, row)
 	}
 	sb.WriteString("\n")
 }
+
+// writeDocSummary writes the first sentence of the doc comment in row[3], if any.
+func writeDocSummary(sb *strings.Builder, row []any) {
+	if len(row) < 4 {
+		return
+	}
+	if summary := docSummary(anyToStr(row[3])); summary != "" {
+		_, _ = fmt.Fprintf(sb, "  _%s_\n", summary)
+	}
+}
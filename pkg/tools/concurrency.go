@@ -0,0 +1,563 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxConcurrencyRows caps the rows fetched per concurrency query.
+	maxConcurrencyRows = 2000
+
+	// maxSpawnDepth is how many levels of goroutines spawned by goroutines
+	// are followed from a function.
+	maxSpawnDepth = 3
+)
+
+// ConcurrencyMapArgs holds arguments for the concurrency_map tool.
+type ConcurrencyMapArgs struct {
+	// Function is the function or method to map (e.g., "Pool.Start").
+	Function string
+
+	// Path restricts the map to the functions of a package directory and its
+	// subpackages (e.g., "internal/worker"). Used when Function is empty.
+	Path string
+}
+
+// concurrencyOp is one row of cie_concurrency with its function.
+type concurrencyOp struct {
+	Func   string
+	File   string
+	Kind   string // spawn, send, receive, lock, rlock, unlock, runlock
+	Target string
+	Line   int
+}
+
+// spawnEdge is a goroutine started by a function.
+type spawnEdge struct {
+	Parent string
+	Line   int
+	Target string // Callee as written (e.g., "p.run")
+	Child  string // Resolved function name, empty for external functions
+	File   string // File of the resolved function
+}
+
+// lockOrder is a mutex acquired while another one is held.
+type lockOrder struct {
+	Held, Acquired string
+	Func, File     string
+	Line           int
+}
+
+// ConcurrencyMap reports the Go concurrency structure of a function or a
+// package:
+//   - Spawn tree: goroutines started with `go`, and for a function those they
+//     start in turn; their operations are included in the maps below
+//   - Channels: the functions sending to (producers) and receiving from
+//     (consumers) each channel used in scope, across the repository
+//   - Locks: the order in which each function acquires mutexes, and potential
+//     lock-order inversions: two functions acquiring the same pair of mutexes
+//     in opposite orders
+//
+// Channel and mutex fields are matched by type (`Pool.jobs`, `Pool.mu`). Lock
+// order follows source lines and ignores branches, so inversions are
+// candidates to review rather than proven deadlocks.
+func ConcurrencyMap(ctx context.Context, client Querier, args ConcurrencyMapArgs) (*ToolResult, error) {
+	path := strings.TrimSuffix(strings.TrimPrefix(args.Path, "./"), "/")
+	var scopeCond, title string
+	switch {
+	case args.Function != "":
+		scopeCond = fmt.Sprintf("(fn_name = %q or ends_with(fn_name, %q))", args.Function, "."+args.Function)
+		title = fmt.Sprintf("`%s`", args.Function)
+	case path != "" && path != ".":
+		scopeCond = fmt.Sprintf("starts_with(fn_file, %q)", path+"/")
+		title = fmt.Sprintf("`%s`", path)
+	default:
+		return NewError("Error: 'function' or 'path' is required"), nil
+	}
+
+	opsScript := fmt.Sprintf(`?[fn_name, fn_file, kind, target, line] := *cie_concurrency { function_id, kind, target, file_path: fn_file, line },
+  *cie_function { id: function_id, name: fn_name }, %s
+:order fn_file, line :limit %d`, scopeCond, maxConcurrencyRows)
+	opsResult, err := client.Query(ctx, opsScript)
+	if err != nil {
+		if strings.Contains(err.Error(), "cie_concurrency") {
+			return NewResult("Concurrency operations are not indexed: re-index the project (`cie index`) to record goroutines, channels and locks.\n"), nil
+		}
+		return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, opsScript)), nil
+	}
+	ops := concurrencyOps(opsResult)
+
+	spawns, err := querySpawns(ctx, client, scopeCond)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	if args.Function != "" {
+		spawns = expandSpawns(ctx, client, spawns)
+		spawnedOps, err := querySpawnedOps(ctx, client, spawns)
+		if err != nil {
+			return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+		}
+		ops = append(ops, spawnedOps...)
+	}
+	spawns = withUnresolvedSpawns(spawns, ops)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Concurrency Map for %s\n", title)
+	if len(ops) == 0 && len(spawns) == 0 {
+		sb.WriteString("\nNo goroutines, channel operations or locks found.\n\n" +
+			"**Tips:**\n" +
+			"- Concurrency operations are recorded for Go\n" +
+			"- Use **cie_find_function** to check the function name, or set `path` to a package directory\n")
+		return NewResult(sb.String()), nil
+	}
+
+	writeSpawnTree(&sb, spawns)
+
+	channelOps, err := queryTargetOps(ctx, client, ops, []string{"send", "receive"})
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	writeChannels(&sb, channelOps)
+
+	lockOps, err := queryLockOps(ctx, client, ops)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	writeLocks(&sb, ops, lockOps)
+
+	return NewResult(sb.String()), nil
+}
+
+// concurrencyOps converts rows of [fn_name, fn_file, kind, target, line].
+func concurrencyOps(result *QueryResult) []concurrencyOp {
+	var ops []concurrencyOp
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		ops = append(ops, concurrencyOp{
+			Func:   AnyToString(row[0]),
+			File:   AnyToString(row[1]),
+			Kind:   AnyToString(row[2]),
+			Target: AnyToString(row[3]),
+			Line:   rowLine(row[4]),
+		})
+	}
+	return ops
+}
+
+// rowLine converts a line number column of a query row.
+func rowLine(v any) int {
+	line, _ := strconv.Atoi(AnyToString(v))
+	return line
+}
+
+// querySpawns returns the goroutines started by the functions matching
+// scopeCond (on fn_name and fn_file), resolved through target_id for
+// functions of the same file or the call edge on the go statement's line.
+func querySpawns(ctx context.Context, client Querier, scopeCond string) ([]spawnEdge, error) {
+	script := fmt.Sprintf(`?[fn_name, line, target, child, child_file] := *cie_concurrency { function_id, kind: "spawn", target, target_id, line },
+  target_id != "", *cie_function { id: function_id, name: fn_name, file_path: fn_file }, %s,
+  *cie_function { id: target_id, name: child, file_path: child_file }
+?[fn_name, line, target, child, child_file] := *cie_concurrency { function_id, kind: "spawn", target, target_id: "", line },
+  *cie_function { id: function_id, name: fn_name, file_path: fn_file }, %s,
+  *cie_calls { caller_id: function_id, callee_id, call_line: line },
+  *cie_function { id: callee_id, name: child, file_path: child_file }
+:order fn_name, line :limit %d`, scopeCond, scopeCond, maxConcurrencyRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+
+	var spawns []spawnEdge
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		spawns = append(spawns, spawnEdge{
+			Parent: AnyToString(row[0]),
+			Line:   rowLine(row[1]),
+			Target: AnyToString(row[2]),
+			Child:  AnyToString(row[3]),
+			File:   AnyToString(row[4]),
+		})
+	}
+	return spawns, nil
+}
+
+// expandSpawns follows the goroutines started by spawned functions, up to
+// maxSpawnDepth levels.
+func expandSpawns(ctx context.Context, client Querier, spawns []spawnEdge) []spawnEdge {
+	visited := make(map[string]bool)
+	frontier := spawns
+	for depth := 1; depth < maxSpawnDepth && len(frontier) > 0; depth++ {
+		var next []spawnEdge
+		for _, s := range frontier {
+			if s.Child == "" || visited[s.Child+"|"+s.File] {
+				continue
+			}
+			visited[s.Child+"|"+s.File] = true
+			children, err := querySpawns(ctx, client, spawnedFuncCond(s))
+			if err != nil {
+				continue
+			}
+			next = append(next, children...)
+		}
+		spawns = append(spawns, next...)
+		frontier = next
+	}
+	return spawns
+}
+
+// spawnedFuncCond matches the function started by a spawn. Anonymous function
+// names ($anon_1) are only unique within a file, so the file is matched too.
+func spawnedFuncCond(s spawnEdge) string {
+	return fmt.Sprintf("(fn_name = %q and fn_file = %q)", s.Child, s.File)
+}
+
+// querySpawnedOps returns the operations of the functions started by spawns,
+// which run on behalf of the mapped function.
+func querySpawnedOps(ctx context.Context, client Querier, spawns []spawnEdge) ([]concurrencyOp, error) {
+	seen := make(map[string]bool)
+	var conds []string
+	for _, s := range spawns {
+		if cond := spawnedFuncCond(s); s.Child != "" && !seen[cond] {
+			seen[cond] = true
+			conds = append(conds, cond)
+		}
+	}
+	if len(conds) == 0 {
+		return nil, nil
+	}
+
+	script := fmt.Sprintf(`?[fn_name, fn_file, kind, target, line] := *cie_concurrency { function_id, kind, target, file_path: fn_file, line },
+  *cie_function { id: function_id, name: fn_name }, (%s)
+:order fn_file, line :limit %d`, strings.Join(conds, " or "), maxConcurrencyRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	return concurrencyOps(result), nil
+}
+
+// withUnresolvedSpawns adds the spawns of ops that did not resolve to an
+// indexed function (e.g., go http.ListenAndServe(addr, nil)).
+func withUnresolvedSpawns(spawns []spawnEdge, ops []concurrencyOp) []spawnEdge {
+	resolved := make(map[string]bool)
+	for _, s := range spawns {
+		resolved[fmt.Sprintf("%s:%d", s.Parent, s.Line)] = true
+	}
+	for _, op := range ops {
+		if op.Kind == "spawn" && !resolved[fmt.Sprintf("%s:%d", op.Func, op.Line)] {
+			spawns = append(spawns, spawnEdge{Parent: op.Func, Line: op.Line, Target: op.Target})
+		}
+	}
+	return spawns
+}
+
+// writeSpawnTree writes the goroutines as a tree rooted at the functions that
+// are not themselves spawned.
+func writeSpawnTree(sb *strings.Builder, spawns []spawnEdge) {
+	sb.WriteString("\n### Spawn Tree\n\n")
+	if len(spawns) == 0 {
+		sb.WriteString("No goroutines started.\n")
+		return
+	}
+
+	children := make(map[string][]spawnEdge)
+	spawned := make(map[string]bool)
+	var parents []string
+	for _, s := range spawns {
+		if _, ok := children[s.Parent]; !ok {
+			parents = append(parents, s.Parent)
+		}
+		children[s.Parent] = append(children[s.Parent], s)
+		if s.Child != "" {
+			spawned[s.Child] = true
+		}
+	}
+	for _, list := range children {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Line < list[j].Line })
+	}
+
+	var write func(name string, depth int, path map[string]bool)
+	write = func(name string, depth int, path map[string]bool) {
+		for _, s := range children[name] {
+			indent := strings.Repeat("  ", depth)
+			if s.Child == "" {
+				fmt.Fprintf(sb, "%s- go `%s` (line %d, not indexed)\n", indent, s.Target, s.Line)
+				continue
+			}
+			fmt.Fprintf(sb, "%s- go `%s` (line %d) → `%s` — %s\n", indent, s.Target, s.Line, s.Child, s.File)
+			if !path[s.Child] {
+				path[s.Child] = true
+				write(s.Child, depth+1, path)
+				delete(path, s.Child)
+			}
+		}
+	}
+	var roots []string
+	for _, parent := range parents {
+		if !spawned[parent] {
+			roots = append(roots, parent)
+		}
+	}
+	if len(roots) == 0 {
+		roots = parents // Goroutines spawning each other
+	}
+	for _, root := range roots {
+		fmt.Fprintf(sb, "**%s**\n", root)
+		write(root, 0, map[string]bool{root: true})
+	}
+}
+
+// queryTargetOps returns the operations of the given kinds on the targets of
+// ops. Targets named after a type (Pool.jobs) are matched across the
+// repository; names local to a function are only matched within ops.
+func queryTargetOps(ctx context.Context, client Querier, ops []concurrencyOp, kinds []string) ([]concurrencyOp, error) {
+	isKind := make(map[string]bool)
+	var kindConds []string
+	for _, kind := range kinds {
+		isKind[kind] = true
+		kindConds = append(kindConds, fmt.Sprintf("kind = %q", kind))
+	}
+
+	var local []concurrencyOp
+	seen := make(map[string]bool)
+	var targetConds []string
+	for _, op := range ops {
+		if !isKind[op.Kind] {
+			continue
+		}
+		if !strings.Contains(op.Target, ".") {
+			local = append(local, op)
+			continue
+		}
+		if !seen[op.Target] {
+			seen[op.Target] = true
+			targetConds = append(targetConds, fmt.Sprintf("target = %q", op.Target))
+		}
+	}
+	if len(targetConds) == 0 {
+		return local, nil
+	}
+	sort.Strings(targetConds)
+
+	script := fmt.Sprintf(`?[fn_name, fn_file, kind, target, line] := *cie_concurrency { function_id, kind, target, file_path: fn_file, line },
+  (%s), (%s), *cie_function { id: function_id, name: fn_name }
+:order target, fn_file, line :limit %d`,
+		strings.Join(kindConds, " or "), strings.Join(targetConds, " or "), maxConcurrencyRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	return append(concurrencyOps(result), local...), nil
+}
+
+// writeChannels writes the producers and consumers of each channel.
+func writeChannels(sb *strings.Builder, ops []concurrencyOp) {
+	sb.WriteString("\n### Channels\n\n")
+	if len(ops) == 0 {
+		sb.WriteString("No channel operations.\n")
+		return
+	}
+
+	producers := make(map[string][]concurrencyOp)
+	consumers := make(map[string][]concurrencyOp)
+	var targets []string
+	for _, op := range ops {
+		if _, ok := producers[op.Target]; !ok {
+			if _, ok := consumers[op.Target]; !ok {
+				targets = append(targets, op.Target)
+			}
+		}
+		if op.Kind == "send" {
+			producers[op.Target] = append(producers[op.Target], op)
+		} else {
+			consumers[op.Target] = append(consumers[op.Target], op)
+		}
+	}
+	sort.Strings(targets)
+
+	for i, target := range targets {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(sb, "**%s**\n", target)
+		writeChannelSide(sb, "Producers", producers[target])
+		writeChannelSide(sb, "Consumers", consumers[target])
+	}
+}
+
+// writeChannelSide writes the functions sending to or receiving from a channel.
+func writeChannelSide(sb *strings.Builder, title string, ops []concurrencyOp) {
+	if len(ops) == 0 {
+		fmt.Fprintf(sb, "- %s: _none found_\n", title)
+		return
+	}
+	var sites []string
+	for _, op := range ops {
+		sites = append(sites, fmt.Sprintf("`%s` (%s:%d)", op.Func, op.File, op.Line))
+	}
+	fmt.Fprintf(sb, "- %s: %s\n", title, strings.Join(sites, ", "))
+}
+
+// queryLockOps returns every lock operation of the functions acquiring one of
+// the mutexes locked in ops, so that the acquisition order of each function
+// can be replayed.
+func queryLockOps(ctx context.Context, client Querier, ops []concurrencyOp) ([]concurrencyOp, error) {
+	seen := make(map[string]bool)
+	var targetConds []string
+	for _, op := range ops {
+		if (op.Kind == "lock" || op.Kind == "rlock") && !seen[op.Target] {
+			seen[op.Target] = true
+			targetConds = append(targetConds, fmt.Sprintf("t0 = %q", op.Target))
+		}
+	}
+	if len(targetConds) == 0 {
+		return nil, nil
+	}
+	sort.Strings(targetConds)
+
+	script := fmt.Sprintf(`?[fn_name, fn_file, kind, target, line] := *cie_concurrency { function_id, kind: k0, target: t0 },
+  (k0 = "lock" or k0 = "rlock"), (%s),
+  *cie_concurrency { function_id, kind, target, file_path: fn_file, line },
+  (kind = "lock" or kind = "rlock" or kind = "unlock" or kind = "runlock"),
+  *cie_function { id: function_id, name: fn_name }
+:order fn_file, fn_name, line :limit %d`, strings.Join(targetConds, " or "), maxConcurrencyRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	return concurrencyOps(result), nil
+}
+
+// lockOrders replays the lock operations of each function in line order and
+// returns the mutexes acquired while another one is held. Per function, the
+// acquisition sequence is returned too.
+func lockOrders(ops []concurrencyOp) ([]lockOrder, map[string][]string) {
+	byFunc := make(map[string][]concurrencyOp)
+	var funcs []string
+	for _, op := range ops {
+		key := op.Func + "|" + op.File
+		if _, ok := byFunc[key]; !ok {
+			funcs = append(funcs, key)
+		}
+		byFunc[key] = append(byFunc[key], op)
+	}
+
+	var orders []lockOrder
+	sequences := make(map[string][]string)
+	for _, key := range funcs {
+		fnOps := byFunc[key]
+		sort.SliceStable(fnOps, func(i, j int) bool { return fnOps[i].Line < fnOps[j].Line })
+		var held []string
+		for _, op := range fnOps {
+			switch op.Kind {
+			case "lock", "rlock":
+				for _, h := range held {
+					if h != op.Target {
+						orders = append(orders, lockOrder{Held: h, Acquired: op.Target, Func: op.Func, File: op.File, Line: op.Line})
+					}
+				}
+				held = append(held, op.Target)
+				sequences[op.Func] = append(sequences[op.Func], fmt.Sprintf("%s (line %d)", op.Target, op.Line))
+			case "unlock", "runlock":
+				for i := len(held) - 1; i >= 0; i-- {
+					if held[i] == op.Target {
+						held = append(held[:i], held[i+1:]...)
+						break
+					}
+				}
+			}
+		}
+	}
+	return orders, sequences
+}
+
+// writeLocks writes the acquisition order of the functions in scope and the
+// potential lock-order inversions involving their mutexes.
+func writeLocks(sb *strings.Builder, scopeOps, lockOps []concurrencyOp) {
+	sb.WriteString("\n### Locks\n\n")
+	if len(lockOps) == 0 {
+		sb.WriteString("No mutexes acquired.\n")
+		return
+	}
+
+	orders, sequences := lockOrders(lockOps)
+	inScope := make(map[string]bool)
+	var scopeFuncs []string
+	for _, op := range scopeOps {
+		if (op.Kind == "lock" || op.Kind == "rlock") && !inScope[op.Func] {
+			inScope[op.Func] = true
+			scopeFuncs = append(scopeFuncs, op.Func)
+		}
+	}
+	sb.WriteString("Acquisition order (deferred unlocks hold the lock until return):\n")
+	for _, fn := range scopeFuncs {
+		fmt.Fprintf(sb, "- `%s`: %s\n", fn, strings.Join(sequences[fn], " → "))
+	}
+
+	byPair := make(map[string][]lockOrder)
+	for _, o := range orders {
+		key := o.Held + "\x00" + o.Acquired
+		byPair[key] = append(byPair[key], o)
+	}
+	var inversions []string
+	for _, o := range orders {
+		if o.Held > o.Acquired {
+			continue // Report each pair once, from the lexically smaller mutex
+		}
+		reverse := byPair[o.Acquired+"\x00"+o.Held]
+		if len(reverse) == 0 {
+			continue
+		}
+		r := reverse[0]
+		inversions = append(inversions, fmt.Sprintf("- `%s` → `%s` in `%s` (%s:%d), but `%s` → `%s` in `%s` (%s:%d)",
+			o.Held, o.Acquired, o.Func, o.File, o.Line, r.Held, r.Acquired, r.Func, r.File, r.Line))
+	}
+	sort.Strings(inversions)
+	inversions = uniqueSorted(inversions)
+
+	sb.WriteString("\n**Potential lock-order inversions:**\n")
+	if len(inversions) == 0 {
+		sb.WriteString("None found.\n")
+		return
+	}
+	sb.WriteString(strings.Join(inversions, "\n"))
+	sb.WriteString("\n")
+}
+
+// uniqueSorted removes adjacent duplicates from a sorted slice.
+func uniqueSorted(values []string) []string {
+	var out []string
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// concurrencyMockClient serves a worker pool: Pool.Start spawns Pool.run and
+// a closure, Submit produces jobs, and Pool.flush takes Pool.stats before
+// Pool.mu while Pool.run takes them in the opposite order.
+func concurrencyMockClient() *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			switch {
+			case strings.Contains(script, "k0"):
+				return &QueryResult{Rows: [][]any{
+					{"$anon_1", "internal/worker/pool.go", "lock", "Pool.mu", float64(18)},
+					{"$anon_1", "internal/worker/pool.go", "unlock", "Pool.mu", float64(20)},
+					{"Pool.run", "internal/worker/pool.go", "lock", "Pool.mu", float64(26)},
+					{"Pool.run", "internal/worker/pool.go", "rlock", "Pool.stats", float64(28)},
+					{"Pool.run", "internal/worker/pool.go", "runlock", "Pool.stats", float64(30)},
+					{"Pool.flush", "internal/worker/stats.go", "rlock", "Pool.stats", float64(50)},
+					{"Pool.flush", "internal/worker/stats.go", "lock", "Pool.mu", float64(51)},
+				}}, nil
+			case strings.Contains(script, `kind = "send"`):
+				return &QueryResult{Rows: [][]any{
+					{"$anon_1", "internal/worker/pool.go", "send", "Pool.done", float64(19)},
+					{"Pool.run", "internal/worker/pool.go", "receive", "Pool.done", float64(34)},
+					{"Pool.run", "internal/worker/pool.go", "receive", "Pool.jobs", float64(25)},
+					{"Submit", "internal/worker/submit.go", "send", "Pool.jobs", float64(39)},
+				}}, nil
+			case strings.Contains(script, `kind: "spawn"`):
+				if !strings.Contains(script, `".Pool.Start"`) {
+					return &QueryResult{}, nil
+				}
+				return &QueryResult{Rows: [][]any{
+					{"Pool.Start", float64(16), "p.run", "Pool.run", "internal/worker/pool.go"},
+					{"Pool.Start", float64(17), "$anon_1", "$anon_1", "internal/worker/pool.go"},
+				}}, nil
+			case strings.Contains(script, "fn_file = "):
+				return &QueryResult{Rows: [][]any{
+					{"$anon_1", "internal/worker/pool.go", "lock", "Pool.mu", float64(18)},
+					{"$anon_1", "internal/worker/pool.go", "send", "Pool.done", float64(19)},
+					{"$anon_1", "internal/worker/pool.go", "unlock", "Pool.mu", float64(20)},
+					{"Pool.run", "internal/worker/pool.go", "receive", "Pool.jobs", float64(25)},
+					{"Pool.run", "internal/worker/pool.go", "lock", "Pool.mu", float64(26)},
+					{"Pool.run", "internal/worker/pool.go", "rlock", "Pool.stats", float64(28)},
+					{"Pool.run", "internal/worker/pool.go", "runlock", "Pool.stats", float64(30)},
+					{"Pool.run", "internal/worker/pool.go", "receive", "Pool.done", float64(34)},
+					{"Pool.run", "internal/worker/pool.go", "spawn", "http.ListenAndServe", float64(36)},
+				}}, nil
+			case strings.Contains(script, "*cie_concurrency"):
+				return &QueryResult{Rows: [][]any{
+					{"Pool.Start", "internal/worker/pool.go", "spawn", "p.run", float64(16)},
+					{"Pool.Start", "internal/worker/pool.go", "spawn", "$anon_1", float64(17)},
+				}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestConcurrencyMap_Function(t *testing.T) {
+	result, err := ConcurrencyMap(context.Background(), concurrencyMockClient(), ConcurrencyMapArgs{Function: "Pool.Start"})
+	if err != nil {
+		t.Fatalf("ConcurrencyMap() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"## Concurrency Map for `Pool.Start`",
+		"**Pool.Start**\n- go `p.run` (line 16) → `Pool.run` — internal/worker/pool.go\n",
+		"  - go `http.ListenAndServe` (line 36, not indexed)\n",
+		"- go `$anon_1` (line 17) → `$anon_1`",
+		"**Pool.jobs**\n- Producers: `Submit` (internal/worker/submit.go:39)\n- Consumers: `Pool.run` (internal/worker/pool.go:25)\n",
+		"**Pool.done**\n- Producers: `$anon_1` (internal/worker/pool.go:19)\n",
+		"- `Pool.run`: Pool.mu (line 26) → Pool.stats (line 28)\n",
+		"- `Pool.mu` → `Pool.stats` in `Pool.run` (internal/worker/pool.go:28), but `Pool.stats` → `Pool.mu` in `Pool.flush` (internal/worker/stats.go:51)",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("ConcurrencyMap() should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Contains(result.Text, "`$anon_1`: Pool.mu (line 18) →") {
+		t.Errorf("ConcurrencyMap() should not order a lock released before the next, got:\n%s", result.Text)
+	}
+}
+
+func TestConcurrencyMap_Path(t *testing.T) {
+	var scripts []string
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			scripts = append(scripts, script)
+			return &QueryResult{}, nil
+		},
+	}
+
+	result, err := ConcurrencyMap(context.Background(), client, ConcurrencyMapArgs{Path: "./internal/worker/"})
+	if err != nil {
+		t.Fatalf("ConcurrencyMap() error = %v", err)
+	}
+	if !strings.Contains(scripts[0], `starts_with(fn_file, "internal/worker/")`) {
+		t.Errorf("ConcurrencyMap() should scope by package directory, got:\n%s", scripts[0])
+	}
+	if !strings.Contains(result.Text, "No goroutines, channel operations or locks found.") {
+		t.Errorf("ConcurrencyMap() should report an empty map, got:\n%s", result.Text)
+	}
+}
+
+func TestConcurrencyMap_Errors(t *testing.T) {
+	result, err := ConcurrencyMap(context.Background(), &MockCIEClient{}, ConcurrencyMapArgs{})
+	if err != nil || !result.IsError {
+		t.Errorf("ConcurrencyMap() without function or path should return an error result, got %+v, %v", result, err)
+	}
+
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			return nil, errors.New("stored relation 'cie_concurrency' not found")
+		},
+	}
+	result, err = ConcurrencyMap(context.Background(), client, ConcurrencyMapArgs{Function: "Pool.Start"})
+	if err != nil {
+		t.Fatalf("ConcurrencyMap() error = %v", err)
+	}
+	if !strings.Contains(result.Text, "re-index the project") {
+		t.Errorf("ConcurrencyMap() should ask to re-index, got:\n%s", result.Text)
+	}
+}
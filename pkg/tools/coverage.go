@@ -0,0 +1,315 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultCoverageGapsLimit is how many functions cie_coverage_gaps lists
+	// by default.
+	defaultCoverageGapsLimit = 20
+
+	// maxCoverageRows caps the rows fetched per callee or coverage query.
+	maxCoverageRows = 2000
+)
+
+// CoverageGapsArgs holds arguments for the coverage_gaps tool.
+type CoverageGapsArgs struct {
+	// Function is the function the call path starts from (e.g., "main",
+	// "Server.HandleOrder").
+	Function string
+
+	// Target optionally ends the call path: only functions on a path from
+	// Function to Target are listed.
+	Target string
+
+	// MaxDepth is how many call levels are followed (default 4, max 8).
+	MaxDepth int
+
+	// Limit caps the functions listed (default 20).
+	Limit int
+}
+
+// functionCoverage is one row of cie_coverage joined with its function.
+type functionCoverage struct {
+	ID         string
+	Name       string
+	File       string
+	Line       int
+	Covered    int
+	Total      int
+	Percent    float64
+	Uncovered  string
+	Source     string
+	ImportedAt int64
+}
+
+// summary describes the coverage on one line:
+// "75.0% (3/4 statements), uncovered lines 12-13".
+func (c functionCoverage) summary() string {
+	s := fmt.Sprintf("%.1f%% (%d/%d statements)", c.Percent, c.Covered, c.Total)
+	if c.Uncovered != "" {
+		s += ", uncovered lines " + c.Uncovered
+	}
+	return s
+}
+
+// coverageOrigin describes where the coverage comes from:
+// "cover.out, imported 2026-10-18".
+func coverageOrigin(c functionCoverage) string {
+	return fmt.Sprintf("%s, imported %s", c.Source, time.Unix(c.ImportedAt, 0).UTC().Format("2006-01-02"))
+}
+
+// queryFunctionCoverage returns the imported coverage of the functions
+// matching condition (on name and fn_file). It returns nil when no coverage
+// was imported, so callers can leave the section out.
+func queryFunctionCoverage(ctx context.Context, client Querier, condition string) []functionCoverage {
+	script := fmt.Sprintf(`?[function_id, name, fn_file, start_line, covered, total, percent, uncovered, source, imported_at] :=
+  *cie_function { id: function_id, name, file_path: fn_file, start_line }, %s,
+  *cie_coverage { function_id, covered, total, percent, uncovered, source, imported_at }
+:order fn_file, start_line :limit 50`, condition)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil
+	}
+	return coverageRows(result)
+}
+
+// coverageRows converts the rows of a coverage query selecting function_id,
+// name, file, start_line, covered, total, percent, uncovered, source and
+// imported_at.
+func coverageRows(result *QueryResult) []functionCoverage {
+	var rows []functionCoverage
+	for _, row := range result.Rows {
+		if len(row) < 10 {
+			continue
+		}
+		percent, _ := strconv.ParseFloat(AnyToString(row[6]), 64)
+		importedAt, _ := strconv.ParseInt(AnyToString(row[9]), 10, 64)
+		rows = append(rows, functionCoverage{
+			ID:         AnyToString(row[0]),
+			Name:       AnyToString(row[1]),
+			File:       AnyToString(row[2]),
+			Line:       rowLine(row[3]),
+			Covered:    rowLine(row[4]),
+			Total:      rowLine(row[5]),
+			Percent:    percent,
+			Uncovered:  AnyToString(row[7]),
+			Source:     AnyToString(row[8]),
+			ImportedAt: importedAt,
+		})
+	}
+	return rows
+}
+
+// formatFunctionCoverage lists the coverage of each function, or "" if none
+// has coverage.
+func formatFunctionCoverage(coverage []functionCoverage) string {
+	if len(coverage) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n\n**Coverage** (%s):\n", coverageOrigin(coverage[0]))
+	for _, c := range coverage {
+		fmt.Fprintf(&sb, "- `%s` (%s:%d): %s\n", c.Name, c.File, c.Line, c.summary())
+	}
+	return sb.String()
+}
+
+// CoverageGaps lists the least-covered functions on the call paths starting
+// at a function, and optionally ending at a target, from the coverage
+// imported with cie coverage import.
+func CoverageGaps(ctx context.Context, client Querier, args CoverageGapsArgs) (*ToolResult, error) {
+	if args.Function == "" {
+		return NewError("Error: 'function' is required"), nil
+	}
+	depth := args.MaxDepth
+	if depth <= 0 {
+		depth = defaultTestDepth
+	}
+	if depth > maxTestDepth {
+		depth = maxTestDepth
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultCoverageGapsLimit
+	}
+
+	countResult, err := client.Query(ctx, `?[count(function_id)] := *cie_coverage { function_id }`)
+	if err != nil {
+		if strings.Contains(err.Error(), "cie_coverage") {
+			return NewResult("Coverage is not available in this index: re-index the project (`cie index`), then import a profile with `cie coverage import <file>`.\n"), nil
+		}
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	if len(countResult.Rows) == 0 || rowLine(countResult.Rows[0][0]) == 0 {
+		return NewResult("No coverage imported. Generate a profile (`go test -coverprofile=cover.out ./...`, `coverage xml`, lcov) and run `cie coverage import <file>`.\n"), nil
+	}
+
+	roots, err := queryFunctionsNamed(ctx, client, args.Function)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	if len(roots) == 0 {
+		return NewResult(fmt.Sprintf("No function named `%s` found.\n\nUse **cie_find_function** to check the name.\n", args.Function)), nil
+	}
+	onPath, err := queryReachedCallees(ctx, client, roots, depth)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+
+	title := fmt.Sprintf("from `%s`", args.Function)
+	if args.Target != "" {
+		targets, err := queryFunctionsNamed(ctx, client, args.Target)
+		if err != nil {
+			return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+		}
+		if len(targets) == 0 {
+			return NewResult(fmt.Sprintf("No function named `%s` found.\n\nUse **cie_find_function** to check the name.\n", args.Target)), nil
+		}
+		callers, err := queryReachingCallers(ctx, client, targets, depth)
+		if err != nil {
+			return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+		}
+		// A function is on a path when the root reaches it and it reaches the target.
+		for id := range onPath {
+			if _, ok := callers[id]; !ok {
+				delete(onPath, id)
+			}
+		}
+		if len(onPath) == 0 {
+			return NewResult(fmt.Sprintf("No call path from `%s` to `%s` within %d levels.\n\nUse **cie_trace_path** to check how they connect.\n", args.Function, args.Target, depth)), nil
+		}
+		title = fmt.Sprintf("from `%s` to `%s`", args.Function, args.Target)
+	}
+
+	ids := make([]string, 0, len(onPath))
+	for id := range onPath {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	conds := make([]string, len(ids))
+	for i, id := range ids {
+		conds[i] = fmt.Sprintf("function_id = %q", id)
+	}
+	script := fmt.Sprintf(`?[function_id, name, file_path, start_line, covered, total, percent, uncovered, source, imported_at] :=
+  *cie_coverage { function_id, covered, total, percent, uncovered, source, imported_at }, (%s),
+  *cie_function { id: function_id, name, file_path, start_line }
+:limit %d`, strings.Join(conds, " or "), maxCoverageRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, script)), nil
+	}
+	coverage := coverageRows(result)
+	sort.SliceStable(coverage, func(i, j int) bool {
+		if coverage[i].Percent != coverage[j].Percent {
+			return coverage[i].Percent < coverage[j].Percent
+		}
+		if coverage[i].Total-coverage[i].Covered != coverage[j].Total-coverage[j].Covered {
+			return coverage[i].Total-coverage[i].Covered > coverage[j].Total-coverage[j].Covered
+		}
+		return coverage[i].Name < coverage[j].Name
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Least-covered functions on the call path %s\n\n", title)
+	if len(coverage) == 0 {
+		fmt.Fprintf(&sb, "None of the %d functions on the path has coverage data: their files are not in the imported profile, or were re-indexed since the import.\n", len(onPath))
+		return NewResult(sb.String()), nil
+	}
+	withData := len(coverage)
+	fmt.Fprintf(&sb, "%d functions on the path, %d with coverage data (%s).\n\n", len(onPath), withData, coverageOrigin(coverage[0]))
+	if len(coverage) > limit {
+		coverage = coverage[:limit]
+	}
+	for i, c := range coverage {
+		fmt.Fprintf(&sb, "%d. `%s` — %s (%s:%d)\n", i+1, c.Name, c.summary(), c.File, c.Line)
+	}
+	if missing := len(onPath) - withData; missing > 0 {
+		fmt.Fprintf(&sb, "\n_%d functions on the path have no coverage data: their files are not in the profile, or were re-indexed since the import._\n", missing)
+	}
+	return NewResult(sb.String()), nil
+}
+
+// queryFunctionsNamed returns the IDs and names of the functions named name,
+// or of the methods named name on any type.
+func queryFunctionsNamed(ctx context.Context, client Querier, name string) (map[string]string, error) {
+	script := fmt.Sprintf(`?[id, name] := *cie_function { id, name }, (name = %q or ends_with(name, %q)) :limit 20`, name, "."+name)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	functions := make(map[string]string)
+	for _, row := range result.Rows {
+		if len(row) >= 2 {
+			functions[AnyToString(row[0])] = AnyToString(row[1])
+		}
+	}
+	return functions, nil
+}
+
+// queryReachedCallees follows the callees of the roots level by level up to
+// depth, and returns every function reached, the roots included.
+func queryReachedCallees(ctx context.Context, client Querier, roots map[string]string, depth int) (map[string]reachedFunc, error) {
+	reached := make(map[string]reachedFunc)
+	var frontier []string
+	for id, name := range roots {
+		reached[id] = reachedFunc{Name: name}
+		frontier = append(frontier, id)
+	}
+	sort.Strings(frontier)
+
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		conds := make([]string, len(frontier))
+		for i, id := range frontier {
+			conds[i] = fmt.Sprintf("caller_id = %q", id)
+		}
+		script := fmt.Sprintf(`?[caller_id, callee_id, callee_name] := *cie_calls { caller_id, callee_id }, (%s),
+  *cie_function { id: callee_id, name: callee_name }
+:limit %d`, strings.Join(conds, " or "), maxCoverageRows)
+		result, err := client.Query(ctx, script)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for _, row := range result.Rows {
+			if len(row) < 3 {
+				continue
+			}
+			calleeID := AnyToString(row[1])
+			if _, ok := reached[calleeID]; ok {
+				continue
+			}
+			reached[calleeID] = reachedFunc{Name: AnyToString(row[2])}
+			next = append(next, calleeID)
+		}
+		sort.Strings(next)
+		frontier = next
+	}
+	return reached, nil
+}
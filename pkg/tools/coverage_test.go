@@ -0,0 +1,195 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// coverageRow is a row of a coverage query imported on 2026-10-18.
+func coverageRow(id, name, file string, line, covered, total int, percent float64, uncovered string) []any {
+	return []any{id, name, file, float64(line), float64(covered), float64(total), percent, uncovered, "cover.out", float64(1792281600)}
+}
+
+// coverageMockClient serves a call graph main → Server.Handle → {Store.Get,
+// Store.Put}, Store.Get → decode, with coverage for all but decode.
+func coverageMockClient() *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			switch {
+			case strings.Contains(script, "count(function_id)"):
+				return &QueryResult{Rows: [][]any{{float64(3)}}}, nil
+			case strings.Contains(script, "*cie_function { id, name }"):
+				switch {
+				case strings.Contains(script, `name = "main"`):
+					return &QueryResult{Rows: [][]any{{"fn:main", "main"}}}, nil
+				case strings.Contains(script, `name = "decode"`):
+					return &QueryResult{Rows: [][]any{{"fn:decode", "decode"}}}, nil
+				}
+				return &QueryResult{}, nil
+			case strings.Contains(script, "*cie_coverage"):
+				var rows [][]any
+				for _, row := range [][]any{
+					coverageRow("fn:handle", "Server.Handle", "server.go", 10, 9, 10, 90, "15"),
+					coverageRow("fn:get", "Store.Get", "store.go", 20, 1, 4, 25, "22-24"),
+					coverageRow("fn:put", "Store.Put", "store.go", 40, 0, 2, 0, "41-42"),
+				} {
+					if strings.Contains(script, `"`+AnyToString(row[0])+`"`) {
+						rows = append(rows, row)
+					}
+				}
+				return &QueryResult{Rows: rows}, nil
+			case strings.Contains(script, "caller_id = "):
+				var rows [][]any
+				for _, edge := range [][]any{
+					{"fn:main", "fn:handle", "Server.Handle"},
+					{"fn:handle", "fn:get", "Store.Get"},
+					{"fn:handle", "fn:put", "Store.Put"},
+					{"fn:get", "fn:decode", "decode"},
+				} {
+					if strings.Contains(script, `caller_id = "`+AnyToString(edge[0])+`"`) {
+						rows = append(rows, edge)
+					}
+				}
+				return &QueryResult{Rows: rows}, nil
+			case strings.Contains(script, "callee_id = "):
+				var rows [][]any
+				for _, edge := range [][]any{
+					{"fn:decode", "fn:get", "Store.Get"},
+					{"fn:get", "fn:handle", "Server.Handle"},
+					{"fn:handle", "fn:main", "main"},
+				} {
+					if strings.Contains(script, `callee_id = "`+AnyToString(edge[0])+`"`) {
+						rows = append(rows, edge)
+					}
+				}
+				return &QueryResult{Rows: rows}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestCoverageGaps(t *testing.T) {
+	result, err := CoverageGaps(context.Background(), coverageMockClient(), CoverageGapsArgs{Function: "main"})
+	if err != nil {
+		t.Fatalf("CoverageGaps() error = %v", err)
+	}
+	for _, want := range []string{
+		"## Least-covered functions on the call path from `main`",
+		"5 functions on the path, 3 with coverage data (cover.out, imported 2026-10-18).",
+		"1. `Store.Put` — 0.0% (0/2 statements), uncovered lines 41-42 (store.go:40)\n" +
+			"2. `Store.Get` — 25.0% (1/4 statements), uncovered lines 22-24 (store.go:20)\n" +
+			"3. `Server.Handle` — 90.0% (9/10 statements), uncovered lines 15 (server.go:10)\n",
+		"_2 functions on the path have no coverage data",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("CoverageGaps() should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+}
+
+func TestCoverageGaps_Target(t *testing.T) {
+	result, err := CoverageGaps(context.Background(), coverageMockClient(), CoverageGapsArgs{Function: "main", Target: "decode", Limit: 1})
+	if err != nil {
+		t.Fatalf("CoverageGaps() error = %v", err)
+	}
+	if !strings.Contains(result.Text, "from `main` to `decode`") || !strings.Contains(result.Text, "1. `Store.Get`") {
+		t.Errorf("CoverageGaps() should list Store.Get on the path to decode, got:\n%s", result.Text)
+	}
+	if strings.Contains(result.Text, "Store.Put") || strings.Contains(result.Text, "2. `Server.Handle`") {
+		t.Errorf("CoverageGaps() should keep to the path and the limit, got:\n%s", result.Text)
+	}
+}
+
+func TestCoverageGaps_NoCoverage(t *testing.T) {
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			return nil, errors.New("Cannot find requested stored relation 'cie_coverage'")
+		},
+	}
+	result, err := CoverageGaps(context.Background(), client, CoverageGapsArgs{Function: "main"})
+	if err != nil {
+		t.Fatalf("CoverageGaps() error = %v", err)
+	}
+	if !strings.Contains(result.Text, "cie coverage import") {
+		t.Errorf("CoverageGaps() should explain how to import coverage, got:\n%s", result.Text)
+	}
+
+	client = &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			return &QueryResult{Rows: [][]any{{float64(0)}}}, nil
+		},
+	}
+	result, _ = CoverageGaps(context.Background(), client, CoverageGapsArgs{Function: "main"})
+	if !strings.Contains(result.Text, "No coverage imported") {
+		t.Errorf("CoverageGaps() should report missing coverage, got:\n%s", result.Text)
+	}
+}
+
+func TestFormatFunctionCoverage(t *testing.T) {
+	got := formatFunctionCoverage([]functionCoverage{
+		{Name: "Store.Get", File: "store.go", Line: 20, Covered: 3, Total: 4, Percent: 75, Uncovered: "22", Source: "cover.out", ImportedAt: 1792281600},
+		{Name: "Store.Put", File: "store.go", Line: 40, Covered: 2, Total: 2, Percent: 100, Source: "cover.out", ImportedAt: 1792281600},
+	})
+	want := "\n\n**Coverage** (cover.out, imported 2026-10-18):\n" +
+		"- `Store.Get` (store.go:20): 75.0% (3/4 statements), uncovered lines 22\n" +
+		"- `Store.Put` (store.go:40): 100.0% (2/2 statements)\n"
+	if got != want {
+		t.Errorf("formatFunctionCoverage() = %q, want %q", got, want)
+	}
+	if formatFunctionCoverage(nil) != "" {
+		t.Error("formatFunctionCoverage() should be empty without coverage")
+	}
+}
+
+func TestGetFunctionCode_Coverage(t *testing.T) {
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			switch {
+			case strings.Contains(script, "*cie_coverage"):
+				return &QueryResult{Rows: [][]any{coverageRow("fn:get", "Store.Get", "store.go", 20, 1, 4, 25, "22-24")}}, nil
+			case strings.Contains(script, "*cie_function_code"):
+				return &QueryResult{Rows: [][]any{{"Store.Get", "store.go", "func (s *Store) Get()", "func (s *Store) Get() {}", float64(20), float64(25)}}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+	result, err := GetFunctionCode(context.Background(), client, GetFunctionCodeArgs{FunctionName: "Store.Get"})
+	if err != nil {
+		t.Fatalf("GetFunctionCode() error = %v", err)
+	}
+	want := "**Coverage**: 25.0% (1/4 statements), uncovered lines 22-24 (cover.out, imported 2026-10-18)\n"
+	if !strings.Contains(result.Text, want) {
+		t.Errorf("GetFunctionCode() should contain %q, got:\n%s", want, result.Text)
+	}
+
+	// Coverage is HEAD only: a snapshot query must not mix it in
+	result, err = GetFunctionCode(context.Background(), client, GetFunctionCodeArgs{FunctionName: "Store.Get", AsOf: true})
+	if err != nil {
+		t.Fatalf("GetFunctionCode() error = %v", err)
+	}
+	if strings.Contains(result.Text, "**Coverage**") {
+		t.Errorf("GetFunctionCode() as of a snapshot should leave out coverage, got:\n%s", result.Text)
+	}
+}
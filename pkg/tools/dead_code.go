@@ -0,0 +1,407 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// defaultDeadCodeLimit is how many entries cie_find_dead_code lists by
+	// default.
+	defaultDeadCodeLimit = 100
+
+	// maxDeadCodeRows caps the unreachable functions and types fetched.
+	maxDeadCodeRows = 5000
+)
+
+// Confidence levels of a dead code entry, from most to least certain.
+const (
+	DeadCodeHigh   = "high"
+	DeadCodeMedium = "medium"
+	DeadCodeLow    = "low"
+)
+
+// DeadCodeRoots are the built-in reachability roots, all used by default:
+//   - main: entry points (Go/Rust main, Python __main__, JS/TS index/app/server files)
+//   - init: Go init functions, Python dunder methods, JS/TS constructors
+//   - tests: functions in test files and indexed tests
+//   - exported: exported Go API of library packages, Python __init__.py functions
+//   - handlers: HTTP handlers, route registration and GraphQL resolvers
+var DeadCodeRoots = []string{"main", "init", "tests", "exported", "handlers"}
+
+// stdInterfaceMethods matches Go methods satisfying standard library
+// interfaces (error, fmt.Stringer, http.Handler, sort.Interface, io, json,
+// sql). cie_implements only records project interfaces, so these methods
+// are always treated as reachable.
+const stdInterfaceMethods = `[.](Error|String|GoString|Format|ServeHTTP|Len|Less|Swap|Read|Write|Close|Unwrap|Is|As|MarshalJSON|UnmarshalJSON|MarshalText|UnmarshalText|MarshalYAML|UnmarshalYAML|Scan|Value)$`
+
+// FindDeadCodeArgs holds arguments for the find_dead_code tool.
+type FindDeadCodeArgs struct {
+	// Path restricts the report to a directory (e.g., "pkg/tools").
+	// Reachability is always computed over the whole index.
+	Path string
+
+	// Roots are the reachability roots: built-in roots (see DeadCodeRoots)
+	// and custom role names from the project config. Empty means all
+	// built-in roots.
+	Roots []string
+
+	// CustomRoles are the custom roles of the project config, usable as roots.
+	CustomRoles map[string]RolePattern
+
+	// MinConfidence drops entries below this confidence: "high", "medium"
+	// or "low" (default "low", everything).
+	MinConfidence string
+
+	// Limit caps the entries listed (default 100).
+	Limit int
+}
+
+// deadCodeArgError reports an invalid root or confidence level.
+type deadCodeArgError string
+
+func (e deadCodeArgError) Error() string { return string(e) }
+
+// DeadCodeEntry is a function or type no root reaches.
+type DeadCodeEntry struct {
+	Kind       string `json:"kind"` // "function" or "type"
+	Name       string `json:"name"`
+	FilePath   string `json:"file_path"`
+	Line       int    `json:"line"`
+	Confidence string `json:"confidence"`
+	Reason     string `json:"reason"`
+}
+
+// DeadCodeReport is the result of a dead code analysis.
+type DeadCodeReport struct {
+	Roots     []string        `json:"roots"`
+	Functions int             `json:"functions"` // unreachable functions at or above MinConfidence
+	Types     int             `json:"types"`     // unreachable types at or above MinConfidence
+	Entries   []DeadCodeEntry `json:"entries"`   // sorted by confidence, file and line, capped at Limit
+	Truncated bool            `json:"truncated"` // more entries than Limit
+}
+
+// AnalyzeDeadCode computes the functions and types no root reaches.
+//
+// Reachability follows cie_calls and cie_func_ref from the roots. A Go type
+// is used when reachable code or another used type references it; methods
+// of the types implementing a used interface are reachable through
+// cie_implements, so interface dispatch does not produce false positives.
+// Type references are only indexed for Go: methods of JS/TS and Python
+// classes extending or implementing a base are always treated as reachable.
+func AnalyzeDeadCode(ctx context.Context, client Querier, args FindDeadCodeArgs) (*DeadCodeReport, error) {
+	roots := args.Roots
+	if len(roots) == 0 {
+		roots = DeadCodeRoots
+	}
+	minConfidence := args.MinConfidence
+	if minConfidence == "" {
+		minConfidence = DeadCodeLow
+	}
+	minRank, ok := confidenceRank(minConfidence)
+	if !ok {
+		return nil, deadCodeArgError(fmt.Sprintf("unknown confidence %q: use high, medium or low", args.MinConfidence))
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultDeadCodeLimit
+	}
+	dir := strings.TrimSuffix(strings.TrimPrefix(args.Path, "./"), "/")
+
+	var mainDirs []string
+	if result, err := client.Query(ctx, `?[path] := *cie_package { path, name }, name = "main"`); err == nil {
+		for _, row := range result.Rows {
+			if len(row) > 0 {
+				mainDirs = append(mainDirs, AnyToString(row[0]))
+			}
+		}
+	}
+	rootRules, err := deadCodeRootRules(roots, args.CustomRoles, mainDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	reportConds := RoleFilters("source")
+	if dir != "" && dir != "." {
+		reportConds = append(reportConds, fmt.Sprintf("starts_with(file_path, %q)", dir+"/"))
+	}
+	script := fmt.Sprintf(`%s
+root[id] := *cie_function { id, name, file_path }, ends_with(file_path, ".go"), regex_matches(name, %q)
+reached[id] := root[id]
+reached[id] := reached[caller_id], *cie_calls { caller_id, callee_id: id }
+reached[id] := reached[from_id], *cie_func_ref { from_id, to_id: id }
+used_type[type_id] := reached[from_id], *cie_type_ref { from_id, type_id }
+used_type[type_id] := used_type[from_id], *cie_type_ref { from_id, type_id }
+reached[id] := used_type[iface_id], *cie_type { id: iface_id, name: iface },
+  *cie_implements { type_name, interface_name }, (interface_name = iface or ends_with(interface_name, concat(".", iface))),
+  *cie_function { id, name }, starts_with(name, concat(type_name, "."))
+reached[id] := *cie_implements { type_name, file_path: impl_file }, negate(ends_with(impl_file, ".go")),
+  *cie_function { id, name, file_path }, file_path = impl_file, starts_with(name, concat(type_name, "."))
+caller_of[id] := *cie_calls { callee_id: id }
+caller_of[id] := *cie_func_ref { to_id: id }
+type_referrer[id] := *cie_type_ref { type_id: id, kind }, kind != "receiver"
+dead_fn[id, name, file_path, line] := *cie_function { id, name, file_path, start_line: line }, not reached[id],
+  negate(starts_with(name, "$")),
+  %s
+dead_type[id, name, file_path, line] := *cie_type { id, name, file_path, start_line: line }, not used_type[id],
+  ends_with(file_path, ".go"),
+  %s
+?[kind, name, file_path, line, referenced] := dead_fn[id, name, file_path, line], kind = "function", caller_of[id], referenced = "yes"
+?[kind, name, file_path, line, referenced] := dead_fn[id, name, file_path, line], kind = "function", not caller_of[id], referenced = "no"
+?[kind, name, file_path, line, referenced] := dead_type[id, name, file_path, line], kind = "type", type_referrer[id], referenced = "yes"
+?[kind, name, file_path, line, referenced] := dead_type[id, name, file_path, line], kind = "type", not type_referrer[id], referenced = "no"
+:order file_path, line :limit %d`,
+		strings.Join(rootRules, "\n"), stdInterfaceMethods,
+		strings.Join(reportConds, ",\n  "), strings.Join(reportConds, ",\n  "), maxDeadCodeRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+
+	implementing := make(map[string]bool)
+	if implResult, err := client.Query(ctx, `?[type_name] := *cie_implements { type_name }`); err == nil {
+		for _, row := range implResult.Rows {
+			if len(row) > 0 {
+				implementing[AnyToString(row[0])] = true
+			}
+		}
+	}
+
+	report := &DeadCodeReport{Roots: roots}
+	var entries []DeadCodeEntry
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		entry := DeadCodeEntry{
+			Kind:     AnyToString(row[0]),
+			Name:     AnyToString(row[1]),
+			FilePath: AnyToString(row[2]),
+			Line:     rowLine(row[3]),
+		}
+		entry.Confidence, entry.Reason = deadCodeConfidence(entry, AnyToString(row[4]) == "yes", implementing)
+		if rank, _ := confidenceRank(entry.Confidence); rank < minRank {
+			continue
+		}
+		if entry.Kind == "type" {
+			report.Types++
+		} else {
+			report.Functions++
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		ri, _ := confidenceRank(entries[i].Confidence)
+		rj, _ := confidenceRank(entries[j].Confidence)
+		if ri != rj {
+			return ri > rj
+		}
+		if entries[i].FilePath != entries[j].FilePath {
+			return entries[i].FilePath < entries[j].FilePath
+		}
+		return entries[i].Line < entries[j].Line
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+		report.Truncated = true
+	}
+	report.Entries = entries
+	return report, nil
+}
+
+// deadCodeRootRules returns the root[id] rules of the roots, and the
+// used_type[id] rules of the exported root. mainDirs are the directories of Go main packages, whose exported
+// names are not API.
+func deadCodeRootRules(roots []string, customRoles map[string]RolePattern, mainDirs []string) ([]string, error) {
+	fnRule := func(conds []string) string {
+		body := "*cie_function { id, name, file_path, signature }"
+		if strings.Contains(strings.Join(conds, " "), "code_text") {
+			body += ", *cie_function_code { function_id: id, code_text }"
+		}
+		return fmt.Sprintf("root[id] := %s, %s", body, strings.Join(conds, ", "))
+	}
+
+	var rules []string
+	for _, root := range roots {
+		switch root {
+		case "main":
+			for _, p := range entryPointPatterns {
+				rules = append(rules, fnRule([]string{
+					fmt.Sprintf("regex_matches(name, %q)", p.namePattern),
+					fmt.Sprintf("regex_matches(file_path, %q)", p.filePattern),
+					entryPointTestExclusion,
+				}))
+			}
+		case "init":
+			rules = append(rules,
+				fnRule([]string{`name = "init"`, `ends_with(file_path, ".go")`}),
+				fnRule([]string{`regex_matches(name, "(^|[.])__[a-z0-9_]+__$")`, `ends_with(file_path, ".py")`}),
+				fnRule([]string{`ends_with(name, ".constructor")`}),
+			)
+		case "tests":
+			rules = append(rules,
+				fnRule(RoleFilters("test")),
+				`root[id] := *cie_test { function_id: id }, id != ""`,
+			)
+		case "exported":
+			library := libraryPackageConds(mainDirs)
+			rules = append(rules,
+				fnRule(append([]string{`regex_matches(name, "^([A-Za-z0-9_]+[.])?[A-Z]")`}, library...)),
+				fnRule([]string{`ends_with(file_path, "__init__.py")`, `negate(regex_matches(name, "(^|[.])_"))`}),
+				fmt.Sprintf("used_type[id] := *cie_type { id, name, file_path }, %s",
+					strings.Join(append([]string{`regex_matches(name, "^[A-Z]")`}, library...), ", ")),
+			)
+		case "handlers":
+			rules = append(rules,
+				fnRule(RoleFilters("handler")),
+				fnRule(RoleFilters("router")),
+				`root[id] := *cie_graphql_resolver { function_id: id }, id != ""`,
+			)
+		default:
+			role, ok := customRoles[root]
+			if !ok {
+				return nil, deadCodeArgError(fmt.Sprintf("unknown root %q: use %s or a custom role of the project config", root, strings.Join(DeadCodeRoots, ", ")))
+			}
+			conds := RoleFiltersWithCustom(root, map[string]RolePattern{root: role})
+			if len(conds) == 0 {
+				return nil, deadCodeArgError(fmt.Sprintf("custom role %q has no pattern", root))
+			}
+			rules = append(rules, fnRule(conds))
+		}
+	}
+	return rules, nil
+}
+
+// libraryPackageConds matches Go files of library packages: packages other
+// than main, outside internal/ and cmd/ directories.
+func libraryPackageConds(mainDirs []string) []string {
+	conds := []string{
+		`ends_with(file_path, ".go")`,
+		`negate(regex_matches(file_path, "(^|/)(internal|cmd)/"))`,
+	}
+	var alts []string
+	for _, dir := range sortedCopy(mainDirs) {
+		if dir == "" || dir == "." {
+			alts = append(alts, "^[^/]+$")
+		} else {
+			alts = append(alts, "^"+EscapeRegex(dir)+"/[^/]+$")
+		}
+	}
+	if len(alts) > 0 {
+		conds = append(conds, fmt.Sprintf("negate(regex_matches(file_path, %q))", strings.Join(alts, "|")))
+	}
+	return conds
+}
+
+// deadCodeConfidence rates how likely an unreachable entry is really unused,
+// and explains why.
+func deadCodeConfidence(entry DeadCodeEntry, referenced bool, implementing map[string]bool) (string, string) {
+	usage := "never referenced"
+	if referenced {
+		usage = "only referenced from unreachable code"
+	}
+	member := entry.Name
+	owner := ""
+	if i := strings.LastIndex(entry.Name, "."); i >= 0 {
+		owner, member = entry.Name[:i], entry.Name[i+1:]
+	}
+	exported := member != "" && unicode.IsUpper([]rune(member)[0])
+
+	if !strings.HasSuffix(entry.FilePath, ".go") {
+		if strings.HasPrefix(member, "_") || strings.HasPrefix(member, "#") {
+			return DeadCodeMedium, "private, " + usage
+		}
+		return DeadCodeLow, usage + "; may be called by name, through decorators or by framework conventions"
+	}
+	switch {
+	case entry.Kind == "function" && implementing[owner]:
+		return DeadCodeLow, usage + "; " + owner + " implements an interface no reachable code uses"
+	case exported:
+		return DeadCodeMedium, "exported, " + usage + "; may be used through reflection, templates or outside the index"
+	default:
+		return DeadCodeHigh, "unexported, " + usage
+	}
+}
+
+// confidenceRank orders confidence levels: high ranks highest.
+func confidenceRank(confidence string) (int, bool) {
+	switch confidence {
+	case DeadCodeHigh:
+		return 3, true
+	case DeadCodeMedium:
+		return 2, true
+	case DeadCodeLow:
+		return 1, true
+	}
+	return 0, false
+}
+
+// FindDeadCode lists the functions and types no reachability root reaches,
+// grouped by confidence and file.
+func FindDeadCode(ctx context.Context, client Querier, args FindDeadCodeArgs) (*ToolResult, error) {
+	report, err := AnalyzeDeadCode(ctx, client, args)
+	if err != nil {
+		msg := err.Error()
+		for _, relation := range []string{"cie_func_ref", "cie_type_ref", "cie_test", "cie_graphql_resolver"} {
+			if strings.Contains(msg, relation) {
+				return NewResult("Reachability data is missing from this index: re-index the project (`cie index`) to record references, tests and resolvers.\n"), nil
+			}
+		}
+		var argErr deadCodeArgError
+		if errors.As(err, &argErr) {
+			return NewError("Error: " + msg), nil
+		}
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Dead code\n\n")
+	fmt.Fprintf(&sb, "Roots: %s.\n", strings.Join(report.Roots, ", "))
+	if len(report.Entries) == 0 {
+		sb.WriteString("\nNo unreachable function or type found.\n")
+		return NewResult(sb.String()), nil
+	}
+	fmt.Fprintf(&sb, "%d unreachable functions and %d unreachable types", report.Functions, report.Types)
+	if report.Truncated {
+		fmt.Fprintf(&sb, " (showing the first %d; narrow with `path` or `min_confidence`)", len(report.Entries))
+	}
+	sb.WriteString(".\n")
+
+	lastConfidence, lastFile := "", ""
+	for _, e := range report.Entries {
+		if e.Confidence != lastConfidence {
+			fmt.Fprintf(&sb, "\n### %s confidence\n", strings.ToUpper(e.Confidence[:1])+e.Confidence[1:])
+			lastConfidence, lastFile = e.Confidence, ""
+		}
+		if e.FilePath != lastFile {
+			fmt.Fprintf(&sb, "\n**%s**\n", e.FilePath)
+			lastFile = e.FilePath
+		}
+		fmt.Fprintf(&sb, "- `%s` (%s, line %d) — %s\n", e.Name, e.Kind, e.Line, e.Reason)
+	}
+	sb.WriteString("\n_Reachability follows calls, function references and interface dispatch. Code used through reflection, dynamic imports, templates or configuration is not seen: check before deleting._\n")
+	return NewResult(sb.String()), nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// deadCodeMockClient serves main packages in cmd/app and the unreachable
+// rows of the dead code query, recording the analysis script.
+func deadCodeMockClient(script *string) *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, s string) (*QueryResult, error) {
+			switch {
+			case strings.Contains(s, "*cie_package"):
+				return &QueryResult{Rows: [][]any{{"cmd/app"}, {"."}}}, nil
+			case strings.HasPrefix(s, "?[type_name] := *cie_implements"):
+				return &QueryResult{Rows: [][]any{{"fileStore"}}}, nil
+			case strings.Contains(s, "dead_fn"):
+				*script = s
+				return &QueryResult{Rows: [][]any{
+					{"function", "helper", "internal/util/util.go", float64(10), "no"},
+					{"function", "Format", "internal/util/util.go", float64(20), "yes"},
+					{"function", "fileStore.Load", "internal/store/file.go", float64(5), "no"},
+					{"type", "cache", "internal/store/cache.go", float64(3), "no"},
+					{"function", "parse_args", "scripts/run.py", float64(8), "no"},
+					{"function", "_load", "scripts/run.py", float64(30), "yes"},
+				}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestAnalyzeDeadCode_RootsAndConfidence(t *testing.T) {
+	var script string
+	report, err := AnalyzeDeadCode(context.Background(), deadCodeMockClient(&script), FindDeadCodeArgs{})
+	if err != nil {
+		t.Fatalf("AnalyzeDeadCode: %v", err)
+	}
+
+	for _, want := range []string{
+		`regex_matches(name, "^main$")`,                               // main
+		`name = "init"`,                                               // init
+		`*cie_test { function_id: id }`,                               // tests
+		`negate(regex_matches(file_path, "^[^/]+$|^cmd/app/[^/]+$"))`, // exported, main packages excluded
+		`*cie_graphql_resolver { function_id: id }`,                   // handlers
+		`*cie_function_code { function_id: id, code_text }`,
+		`*cie_implements { type_name, interface_name }`,
+		`not reached[id]`,
+		`not used_type[id]`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+
+	if report.Functions != 5 || report.Types != 1 {
+		t.Errorf("got %d functions and %d types, want 5 and 1", report.Functions, report.Types)
+	}
+	want := []struct{ name, confidence string }{
+		{"cache", DeadCodeHigh},
+		{"helper", DeadCodeHigh},
+		{"Format", DeadCodeMedium},
+		{"_load", DeadCodeMedium},
+		{"fileStore.Load", DeadCodeLow},
+		{"parse_args", DeadCodeLow},
+	}
+	if len(report.Entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(report.Entries), len(want), report.Entries)
+	}
+	for i, w := range want {
+		if e := report.Entries[i]; e.Name != w.name || e.Confidence != w.confidence {
+			t.Errorf("entry %d = %s (%s), want %s (%s)", i, e.Name, e.Confidence, w.name, w.confidence)
+		}
+	}
+	if reason := report.Entries[2].Reason; !strings.Contains(reason, "only referenced from unreachable code") {
+		t.Errorf("Format reason = %q", reason)
+	}
+}
+
+func TestAnalyzeDeadCode_CustomRootsAndFilters(t *testing.T) {
+	var script string
+	report, err := AnalyzeDeadCode(context.Background(), deadCodeMockClient(&script), FindDeadCodeArgs{
+		Path:          "./internal/",
+		Roots:         []string{"main", "jobs"},
+		CustomRoles:   map[string]RolePattern{"jobs": {NamePattern: "^Job[A-Z]"}},
+		MinConfidence: DeadCodeMedium,
+		Limit:         2,
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeDeadCode: %v", err)
+	}
+	for _, want := range []string{`regex_matches(name, "^Job[A-Z]")`, `starts_with(file_path, "internal/")`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+	if strings.Contains(script, "*cie_test") || strings.Contains(script, "used_type[id] := *cie_type") {
+		t.Errorf("script uses roots that were not requested:\n%s", script)
+	}
+	if report.Functions != 3 || report.Types != 1 || len(report.Entries) != 2 || !report.Truncated {
+		t.Errorf("got %d functions, %d types, %d entries (truncated %v)", report.Functions, report.Types, len(report.Entries), report.Truncated)
+	}
+}
+
+func TestFindDeadCode(t *testing.T) {
+	var script string
+	client := deadCodeMockClient(&script)
+
+	result, err := FindDeadCode(context.Background(), client, FindDeadCodeArgs{MinConfidence: DeadCodeHigh})
+	if err != nil {
+		t.Fatalf("FindDeadCode: %v", err)
+	}
+	for _, want := range []string{
+		"## Dead code",
+		"Roots: main, init, tests, exported, handlers.",
+		"1 unreachable functions and 1 unreachable types",
+		"### High confidence",
+		"**internal/store/cache.go**",
+		"- `helper` (function, line 10) — unexported, never referenced",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("output missing %q:\n%s", want, result.Text)
+		}
+	}
+
+	for _, args := range []FindDeadCodeArgs{{Roots: []string{"plugins"}}, {MinConfidence: "certain"}} {
+		result, err := FindDeadCode(context.Background(), client, args)
+		if err != nil {
+			t.Fatalf("FindDeadCode: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("FindDeadCode(%+v) should fail, got:\n%s", args, result.Text)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxSemanticDocResults caps the doc chunks listed after code results.
+const maxSemanticDocResults = 5
+
+// searchDocChunks returns the Markdown chunks closest to a query embedding as
+// rows of [file_path, anchor, heading, start_line, distance, text], filtered
+// by the path pattern and exclude paths of args. Role filters do not apply to docs.
+func searchDocChunks(ctx context.Context, client Querier, embedding []float64, args SemanticSearchArgs) ([][]any, error) {
+	queryK, ef := buildHNSWParams(args.Limit, "any", args.PathPattern)
+	script := fmt.Sprintf(`?[file_path, anchor, heading, start_line, distance, text] :=
+		~cie_doc_chunk_embedding:embedding_idx { chunk_id | query: q, k: %d, ef: %d, bind_distance: distance },
+		q = %s,
+		*cie_doc_chunk { id: chunk_id, file_path, anchor, heading, start_line, text }
+		:order distance
+		:limit %d`, queryK, ef, formatEmbeddingForCozoDB(embedding), queryK)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathRegex, excludeRegex *regexp.Regexp
+	if args.PathPattern != "" {
+		pathRegex = regexp.MustCompile("(?i)" + args.PathPattern)
+	}
+	if args.ExcludePaths != "" {
+		excludeRegex = regexp.MustCompile("(?i)" + args.ExcludePaths)
+	}
+	rows := make([][]any, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 6 {
+			continue
+		}
+		filePath := AnyToString(row[0])
+		if pathRegex != nil && !pathRegex.MatchString(filePath) {
+			continue
+		}
+		if excludeRegex != nil && excludeRegex.MatchString(filePath) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	rows = filterByMinSimilarity(rows, args.MinSimilarity)
+	if limit := min(args.Limit, maxSemanticDocResults); len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
+
+// formatDocResults renders doc chunk rows from searchDocChunks.
+func formatDocResults(rows [][]any) string {
+	var sb strings.Builder
+	sb.WriteString("📖 **Documentation**:\n\n")
+	for i, row := range rows {
+		filePath := AnyToString(row[0])
+		anchor := AnyToString(row[1])
+		heading := AnyToString(row[2])
+
+		similarity := 1.0
+		if d, ok := row[4].(float64); ok {
+			similarity = max(1.0-d/2.0, 0)
+		}
+
+		title := heading
+		if title == "" {
+			title = filePath
+		}
+		fmt.Fprintf(&sb, "%d. %s **%s** (%.1f%% match)\n", i+1, getConfidenceIcon(similarity), title, similarity*100)
+		fmt.Fprintf(&sb, "   📄 %s (line %s)\n", docReference(filePath, anchor), AnyToString(row[3]))
+		for _, line := range strings.Split(extractDocSnippet(AnyToString(row[5]), 3), "\n") {
+			if line != "" {
+				sb.WriteString("   > " + line + "\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// extractDocSnippet returns the first lines of prose of a doc chunk, skipping
+// the heading line and blank lines.
+func extractDocSnippet(text string, maxLines int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[0]), "#") {
+		lines = lines[1:]
+	}
+	return extractCodeSnippet(strings.Join(lines, "\n"), maxLines)
+}
+
+// docReference formats a doc chunk location as "docs/architecture.md#ingestion".
+func docReference(filePath, anchor string) string {
+	if anchor == "" {
+		return filePath
+	}
+	return filePath + "#" + anchor
+}
+
+// queryFunctionDocs returns the doc chunks mentioning the functions matched by
+// condition, keyed by function name. The condition may use the variables name
+// and fn_file (the function's file path).
+func queryFunctionDocs(ctx context.Context, client Querier, condition string) map[string][]string {
+	script := fmt.Sprintf(`?[name, doc_path, anchor] := *cie_function { id, name, file_path: fn_file }, %s,
+		*cie_doc_link { chunk_id, target_id: id },
+		*cie_doc_chunk { id: chunk_id, file_path: doc_path, anchor }
+		:order doc_path
+		:limit 50`, condition)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil
+	}
+
+	docs := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, row := range result.Rows {
+		if len(row) != 3 {
+			continue
+		}
+		name := AnyToString(row[0])
+		ref := docReference(AnyToString(row[1]), AnyToString(row[2]))
+		if seen[name+"|"+ref] {
+			continue
+		}
+		seen[name+"|"+ref] = true
+		docs[name] = append(docs[name], ref)
+	}
+	return docs
+}
+
+// docSummary returns the first paragraph of a doc comment on one line, cut
+// after its first sentence, for listings that show one line per symbol.
+func docSummary(doc string) string {
+	doc, _, _ = strings.Cut(strings.TrimSpace(doc), "\n\n")
+	doc = strings.Join(strings.Fields(doc), " ")
+	if i := strings.Index(doc, ". "); i >= 0 {
+		doc = doc[:i+1]
+	}
+	return doc
+}
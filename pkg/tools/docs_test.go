@@ -0,0 +1,214 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// docsMockClient answers code and doc HNSW queries and doc link lookups.
+func docsMockClient() *MockCIEClient {
+	return NewMockClientCustom(
+		func(ctx context.Context, script string) (*QueryResult, error) {
+			switch {
+			case strings.Contains(script, "~cie_doc_chunk_embedding"):
+				return NewMockQueryResult(
+					[]string{"file_path", "anchor", "heading", "start_line", "distance", "text"},
+					[][]any{
+						{"docs/architecture.md", "ingestion", "Ingestion", 11, 0.3, "## Ingestion\n\nFiles are parsed in parallel, then embedded."},
+						{"vendor/lib/README.md", "", "", 1, 0.2, "Vendored library"},
+						{"docs/faq.md", "why", "Why", 3, 1.5, "Unrelated"},
+					},
+				), nil
+			case strings.Contains(script, "~cie_function_embedding"):
+				return NewMockQueryResult(
+					[]string{"name", "file_path", "signature", "start_line", "distance", "code_text"},
+					[][]any{{"LocalPipeline.Run", "pkg/ingestion/local_pipeline.go", "func (p *LocalPipeline) Run()", 340, 0.2, "code"}},
+				), nil
+			case strings.Contains(script, "*cie_doc_link"):
+				return NewMockQueryResult(
+					[]string{"name", "doc_path", "anchor"},
+					[][]any{
+						{"Parser.ParseFile", "README.md", ""},
+						{"Parser.ParseFile", "docs/architecture.md", "ingestion"},
+						{"Parser.ParseFile", "docs/architecture.md", "ingestion"},
+					},
+				), nil
+			case strings.Contains(script, "*cie_function"):
+				return NewMockQueryResult(
+					[]string{"name", "file_path", "signature", "code_text", "start_line", "end_line"},
+					[][]any{{"Parser.ParseFile", "pkg/ingestion/parser.go", "func (p *Parser) ParseFile()", "func (p *Parser) ParseFile() {}", 150, 160}},
+				), nil
+			}
+			return NewMockQueryResult([]string{}, [][]any{}), nil
+		},
+		nil,
+	)
+}
+
+func TestSemanticSearch_IncludeDocs(t *testing.T) {
+	ctx := setupTest(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"embedding": []float64{0.1, 0.2, 0.3}})
+	}))
+	defer server.Close()
+
+	args := SemanticSearchArgs{
+		Query:          "how are files ingested",
+		EmbeddingURL:   server.URL,
+		EmbeddingModel: "nomic-embed-text",
+		ExcludePaths:   "vendor/",
+		MinSimilarity:  0.5,
+	}
+
+	result, err := SemanticSearch(ctx, docsMockClient(), args)
+	assertNoError(t, err)
+	if strings.Contains(result.Text, "Documentation") {
+		t.Errorf("Doc chunks should only be returned with IncludeDocs, got:\n%s", result.Text)
+	}
+
+	args.IncludeDocs = true
+	result, err = SemanticSearch(ctx, docsMockClient(), args)
+	assertNoError(t, err)
+	for _, want := range []string{
+		"LocalPipeline.Run",
+		"📖 **Documentation**",
+		"**Ingestion** (85.0% match)",
+		"📄 docs/architecture.md#ingestion (line 11)",
+		"> Files are parsed in parallel, then embedded.",
+	} {
+		assertContains(t, result.Text, want)
+	}
+	if strings.Contains(result.Text, "Vendored library") || strings.Contains(result.Text, "Unrelated") {
+		t.Errorf("Excluded paths and chunks below min_similarity should be dropped, got:\n%s", result.Text)
+	}
+}
+
+func TestGetFunctionCode_DocumentedIn(t *testing.T) {
+	result, err := GetFunctionCode(setupTest(t), docsMockClient(), GetFunctionCodeArgs{FunctionName: "Parser.ParseFile"})
+	assertNoError(t, err)
+	assertContains(t, result.Text, "**Documented in**: README.md, docs/architecture.md#ingestion\n")
+}
+
+func TestFormatFunctionDocs(t *testing.T) {
+	got := formatFunctionDocs(map[string][]string{
+		"Run":       {"docs/architecture.md#ingestion"},
+		"ParseFile": {"README.md", "docs/parsing.md#go"},
+	})
+	want := "\n\n**Documented in**:\n- `ParseFile`: README.md, docs/parsing.md#go\n- `Run`: docs/architecture.md#ingestion\n"
+	if got != want {
+		t.Errorf("formatFunctionDocs() = %q, want %q", got, want)
+	}
+	if formatFunctionDocs(nil) != "" {
+		t.Error("formatFunctionDocs() should be empty without docs")
+	}
+}
+
+func TestDocSummary(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want string
+	}{
+		{"", ""},
+		{"NewServer creates a Server.", "NewServer creates a Server."},
+		{"Parse reads the file. It returns an error\nif the file is invalid.", "Parse reads the file."},
+		{"Manages users\nacross tenants\n\nDetails.", "Manages users across tenants"},
+		{"Version is v1.2.3 of the API", "Version is v1.2.3 of the API"},
+	}
+	for _, tt := range tests {
+		if got := docSummary(tt.doc); got != tt.want {
+			t.Errorf("docSummary(%q) = %q, want %q", tt.doc, got, tt.want)
+		}
+	}
+}
+
+// docCommentMockClient returns types and functions with doc comments.
+func docCommentMockClient() *MockCIEClient {
+	return NewMockClientCustom(
+		func(ctx context.Context, script string) (*QueryResult, error) {
+			switch {
+			case strings.Contains(script, "*cie_file"):
+				return NewMockQueryResult([]string{"path"}, [][]any{{"pkg/server/server.go"}}), nil
+			case strings.Contains(script, "*cie_type"):
+				return NewMockQueryResult(
+					[]string{"name", "kind", "start_line", "doc_comment"},
+					[][]any{{"Server", "struct", 10, "Server serves the index over HTTP.\n\nIt is safe for concurrent use."}},
+				), nil
+			case strings.Contains(script, "*cie_function"):
+				return NewMockQueryResult(
+					[]string{"name", "signature", "start_line", "doc_comment"},
+					[][]any{
+						{"NewServer", "func NewServer() *Server", 20, "NewServer creates a Server. The server is not started."},
+						{"Server.Start", "func (s *Server) Start() error", 30, ""},
+						{"listen", "func listen() error", 40, "listen binds the port."},
+					},
+				), nil
+			}
+			return NewMockQueryResult([]string{}, [][]any{}), nil
+		},
+		nil,
+	)
+}
+
+func TestGetFileSummary_DocComments(t *testing.T) {
+	ctx := setupTest(t)
+	result, err := GetFileSummary(ctx, docCommentMockClient(), GetFileSummaryArgs{FilePath: "pkg/server/server.go"})
+	assertNoError(t, err)
+	assertContains(t, result.Text, "_Server serves the index over HTTP._")
+	assertContains(t, result.Text, "_NewServer creates a Server._")
+	if strings.Contains(result.Text, "not started") || strings.Contains(result.Text, "__") {
+		t.Errorf("Only the first sentence of non-empty docs should be shown, got:\n%s", result.Text)
+	}
+}
+
+func TestDirectorySummary_DocComments(t *testing.T) {
+	ctx := setupTest(t)
+	result, err := DirectorySummary(ctx, docCommentMockClient(), "pkg/server", 5)
+	assertNoError(t, err)
+	assertContains(t, result.Text, "_NewServer creates a Server._")
+	assertContains(t, result.Text, "_listen binds the port._")
+}
+
+func TestFindFunction_DocComment(t *testing.T) {
+	ctx := setupTest(t)
+	var script string
+	client := NewMockClientCustom(
+		func(ctx context.Context, s string) (*QueryResult, error) {
+			if script == "" {
+				script = s
+			}
+			return NewMockQueryResult(
+				[]string{"file_path", "name", "signature", "start_line", "end_line", "doc_comment"},
+				[][]any{{"pkg/server/server.go", "NewServer", "func NewServer() *Server", 20, 22, "NewServer creates a Server."}},
+			), nil
+		},
+		nil,
+	)
+
+	result, err := FindFunction(ctx, client, FindFunctionArgs{Name: "NewServer", ExactMatch: true})
+	assertNoError(t, err)
+	assertContains(t, script, "doc_comment")
+	assertContains(t, result.Text, "doc_comment: NewServer creates a Server.")
+}
@@ -48,6 +48,23 @@ var httpMethodPatterns = []struct {
 	{regexp.MustCompile(`\.Group\s*\(\s*["'](/[^"']*)["']`), -1, 1},
 }
 
+// PHP route patterns (Laravel route files and Symfony controller attributes).
+// Laravel paths are often written without a leading "/" and are normalized.
+var (
+	// Laravel: Route::get('/users', [UserController::class, 'index']);
+	laravelRoutePattern = regexp.MustCompile(`Route::(get|post|put|patch|delete|options|any)\s*\(\s*["']([^"']*)["']\s*(?:,\s*([^;\n]*))?`)
+	// Laravel: Route::match(['get', 'post'], '/users', ...);
+	laravelMatchPattern = regexp.MustCompile(`Route::match\s*\(\s*\[([^\]]*)\]\s*,\s*["']([^"']*)["']`)
+	// Laravel handlers: [UserController::class, 'index'] or 'UserController@index'
+	laravelArrayHandlerPattern  = regexp.MustCompile(`^\[\s*\\?([\w\\]+)::class\s*,\s*["'](\w+)["']`)
+	laravelStringHandlerPattern = regexp.MustCompile(`^["']\\?([\w\\]+)@(\w+)["']`)
+	// Symfony: #[Route('/products/{id}', name: 'product_show', methods: ['GET'])]
+	symfonyRoutePattern = regexp.MustCompile(`#\[Route\(\s*(?:path:\s*)?["'](/[^"']*)["']([^)]*)\)`)
+	// Symfony methods argument: methods: ['GET', 'HEAD'] or methods: 'GET'
+	symfonyMethodsPattern = regexp.MustCompile(`methods:\s*(\[[^\]]*\]|["']\w+["'])`)
+	quotedWordPattern     = regexp.MustCompile(`["'](\w+)["']`)
+)
+
 // ListEndpointsArgs holds arguments for listing HTTP/REST endpoints.
 type ListEndpointsArgs struct {
 	// PathPattern filters results by file path using regex.
@@ -70,12 +87,14 @@ type ListEndpointsArgs struct {
 
 // ListEndpoints lists HTTP/REST endpoints defined in the codebase.
 //
-// It detects route definitions from multiple popular Go web frameworks:
+// It detects route definitions from multiple popular web frameworks:
 //   - Gin/Echo: r.GET("/path", handler), e.POST("/path", handler)
 //   - Chi: r.Get("/path", handler), r.Post("/path", handler)
 //   - Fiber: app.Get("/path", handler)
 //   - net/http: http.HandleFunc("/path", handler)
 //   - Generic: mux.Handle("/path", handler), r.Group("/api")
+//   - Laravel: Route::get('/path', [Controller::class, 'method']), Route::match([...], '/path', ...)
+//   - Symfony: #[Route('/path', methods: ['GET'])] on controller methods
 //
 // The function searches for HTTP method patterns in function code and extracts
 // the endpoint path, method, and handler information.
@@ -147,7 +166,7 @@ func ListEndpoints(ctx context.Context, client Querier, args ListEndpointsArgs)
 func formatNoEndpointsFound() string {
 	return "No HTTP endpoints found.\n\n" +
 		"**Tips:**\n" +
-		"- Check if the codebase uses Go web frameworks (Gin, Echo, Chi, Fiber)\n" +
+		"- Check if the codebase uses a supported framework (Gin, Echo, Chi, Fiber, Laravel, Symfony)\n" +
 		"- Try a different `path_pattern` to narrow the search\n" +
 		"- Use `cie_grep` with patterns like `.GET(` or `.POST(` for manual search\n"
 }
@@ -199,7 +218,7 @@ type endpoint struct {
 
 // buildEndpointQueryConditions builds query conditions for endpoint search.
 func buildEndpointQueryConditions(args ListEndpointsArgs) string {
-	httpMethodPattern := `([.](GET|POST|PUT|DELETE|PATCH|Get|Post|Put|Delete|Patch|Group|Any)[(]|Handle(Func)?[(])`
+	httpMethodPattern := `([.](GET|POST|PUT|DELETE|PATCH|Get|Post|Put|Delete|Patch|Group|Any)[(]|Handle(Func)?[(]|Route::[a-z]+\s*[(]|#\[Route[(])`
 	var conditions []string
 	conditions = append(conditions, fmt.Sprintf("regex_matches(code_text, %s)", QuoteCozoPattern(httpMethodPattern)))
 	if args.PathPattern != "" {
@@ -225,9 +244,75 @@ func parseEndpointsFromCode(codeText, filePath, funcName, startLine string, args
 			endpoints = append(endpoints, *ep)
 		}
 	}
+	for _, ep := range parsePHPRoutesFromCode(codeText, filePath, funcName, startLine) {
+		if endpointMatchesFilters(&ep, args) {
+			endpoints = append(endpoints, ep)
+		}
+	}
 	return endpoints
 }
 
+// parsePHPRoutesFromCode extracts Laravel and Symfony route definitions.
+// Laravel routes report the controller action (Controller@method) as the handler
+// when it can be read from the route definition; Symfony routes report the
+// annotated controller method. Routes without explicit methods are reported as ANY.
+func parsePHPRoutesFromCode(codeText, filePath, funcName, startLine string) []endpoint {
+	if !strings.Contains(codeText, "Route") {
+		return nil
+	}
+
+	var endpoints []endpoint
+	newEndpoint := func(method, path, handler string) endpoint {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		return endpoint{Method: method, Path: path, Handler: handler, FilePath: filePath, Line: startLine}
+	}
+
+	for _, m := range laravelRoutePattern.FindAllStringSubmatch(codeText, -1) {
+		endpoints = append(endpoints, newEndpoint(strings.ToUpper(m[1]), m[2], laravelHandlerName(m[3], funcName)))
+	}
+	for _, m := range laravelMatchPattern.FindAllStringSubmatch(codeText, -1) {
+		for _, method := range quotedWordPattern.FindAllStringSubmatch(m[1], -1) {
+			endpoints = append(endpoints, newEndpoint(strings.ToUpper(method[1]), m[2], funcName))
+		}
+	}
+
+	for _, m := range symfonyRoutePattern.FindAllStringSubmatch(codeText, -1) {
+		methods := symfonyMethodsPattern.FindStringSubmatch(m[2])
+		if methods == nil {
+			endpoints = append(endpoints, newEndpoint("ANY", m[1], funcName))
+			continue
+		}
+		for _, method := range quotedWordPattern.FindAllStringSubmatch(methods[1], -1) {
+			endpoints = append(endpoints, newEndpoint(strings.ToUpper(method[1]), m[1], funcName))
+		}
+	}
+
+	return endpoints
+}
+
+// laravelHandlerName extracts "Controller@method" from a Laravel route action.
+// Falls back to the enclosing function for closures and unrecognized actions.
+func laravelHandlerName(action, funcName string) string {
+	action = strings.TrimSpace(action)
+	if m := laravelArrayHandlerPattern.FindStringSubmatch(action); m != nil {
+		return phpShortClassName(m[1]) + "@" + m[2]
+	}
+	if m := laravelStringHandlerPattern.FindStringSubmatch(action); m != nil {
+		return phpShortClassName(m[1]) + "@" + m[2]
+	}
+	return funcName
+}
+
+// phpShortClassName strips the namespace from a PHP class name.
+func phpShortClassName(name string) string {
+	if idx := strings.LastIndex(name, `\`); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
 // extractEndpointFromMatch extracts endpoint info from a regex match.
 func extractEndpointFromMatch(match []string, methodIndex, pathIndex int, filePath, funcName, startLine string) *endpoint {
 	var httpMethod, httpPath string
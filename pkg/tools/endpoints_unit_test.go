@@ -57,3 +57,64 @@ func TestListEndpointsArgs_LimitDefault(t *testing.T) {
 		t.Errorf("Limit = %d; want 0 (before function call)", args.Limit)
 	}
 }
+
+func TestParseEndpointsFromCode_Laravel(t *testing.T) {
+	code := `Route::get('/users', [UserController::class, 'index']);
+Route::post('users', 'App\Http\Controllers\UserController@store');
+Route::match(['get', 'put'], '/profile', [ProfileController::class, 'update']);
+Route::any('/health', function () {
+    return 'ok';
+});`
+
+	endpoints := parseEndpointsFromCode(code, "routes/web.php", "$main", "3", ListEndpointsArgs{})
+
+	got := make(map[string]string)
+	for _, ep := range endpoints {
+		got[ep.Method+" "+ep.Path] = ep.Handler
+	}
+
+	want := map[string]string{
+		"GET /users":   "UserController@index",
+		"POST /users":  "UserController@store",
+		"GET /profile": "$main",
+		"PUT /profile": "$main",
+		"ANY /health":  "$main",
+	}
+	for key, handler := range want {
+		if got[key] != handler {
+			t.Errorf("endpoint %q: handler = %q, want %q (all: %v)", key, got[key], handler, got)
+		}
+	}
+	if len(endpoints) != len(want) {
+		t.Errorf("got %d endpoints, want %d: %v", len(endpoints), len(want), got)
+	}
+}
+
+func TestParseEndpointsFromCode_Symfony(t *testing.T) {
+	code := `#[Route('/products/{id}', name: 'product_show', methods: ['GET', 'HEAD'])]
+    public function show(int $id): Response
+    {
+        return $this->json(['id' => $id]);
+    }`
+
+	endpoints := parseEndpointsFromCode(code, "src/Controller/ProductController.php", "ProductController.show", "16", ListEndpointsArgs{Method: "get"})
+
+	if len(endpoints) != 1 {
+		t.Fatalf("got %d endpoints, want 1 (method filter): %+v", len(endpoints), endpoints)
+	}
+	ep := endpoints[0]
+	if ep.Method != "GET" || ep.Path != "/products/{id}" || ep.Handler != "ProductController.show" {
+		t.Errorf("unexpected endpoint: %+v", ep)
+	}
+
+	noMethods := parseEndpointsFromCode(`#[Route('/api/ping')]`, "src/Controller/PingController.php", "PingController.ping", "5", ListEndpointsArgs{})
+	if len(noMethods) != 1 || noMethods[0].Method != "ANY" {
+		t.Errorf("route without methods should be ANY, got %+v", noMethods)
+	}
+}
+
+func TestBuildEndpointQueryConditions_PHP(t *testing.T) {
+	conditions := buildEndpointQueryConditions(ListEndpointsArgs{})
+	assertContains(t, conditions, "Route::")
+	assertContains(t, conditions, `#\[Route`)
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ListGraphQLOperationsArgs holds arguments for listing GraphQL operations.
+type ListGraphQLOperationsArgs struct {
+	// Operation filters by operation kind: "query", "mutation", "subscription".
+	// Leave empty to match all operations.
+	Operation string
+
+	// TypeName lists the fields of an object type (e.g., "Order") instead of
+	// the root operations. Useful for finding field resolvers.
+	TypeName string
+
+	// NameFilter filters by field name using case-insensitive substring match.
+	NameFilter string
+
+	// PathPattern filters by SDL file path using regex.
+	PathPattern string
+
+	// Limit is the maximum number of operations to return (default: 100).
+	Limit int
+}
+
+// graphQLOperation is a GraphQL field with its resolvers.
+type graphQLOperation struct {
+	Operation string
+	TypeName  string
+	Name      string
+	Arguments string
+	FieldType string
+	FilePath  string
+	Line      string
+	Resolvers []string
+}
+
+// ListGraphQLOperations lists GraphQL queries, mutations and subscriptions
+// declared in .graphql/.graphqls SDL files, with the functions resolving them.
+//
+// Resolvers come from cie_graphql_resolver, built at index time from gqlgen
+// resolver methods (queryResolver.Orders) and Apollo resolver maps
+// ({ Query: { orders: ... } }).
+//
+// Returns a ToolResult containing a table with columns:
+// [Operation] [Field] [Arguments] [Returns] [Resolver] [Schema]
+func ListGraphQLOperations(ctx context.Context, client Querier, args ListGraphQLOperationsArgs) (*ToolResult, error) {
+	if args.Limit <= 0 {
+		args.Limit = 100
+	}
+	args.Operation = strings.ToLower(args.Operation)
+
+	conditions := buildGraphQLConditions(args, "name", "file_path")
+	script := fmt.Sprintf(
+		"?[operation, type_name, name, arguments, field_type, file_path, line] := *cie_graphql_field { type_name, name, field_type, arguments, operation, file_path, line }, %s :order operation, type_name, name :limit %d",
+		strings.Join(conditions, ", "), args.Limit+1,
+	)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, fmt.Errorf("query graphql fields: %w", err)
+	}
+	if len(result.Rows) == 0 {
+		return NewResult(formatNoGraphQLOperationsFound(args)), nil
+	}
+
+	truncated := len(result.Rows) > args.Limit
+	if truncated {
+		result.Rows = result.Rows[:args.Limit]
+	}
+
+	var ops []*graphQLOperation
+	byField := make(map[string]*graphQLOperation)
+	for _, row := range result.Rows {
+		op := &graphQLOperation{
+			Operation: AnyToString(row[0]),
+			TypeName:  AnyToString(row[1]),
+			Name:      AnyToString(row[2]),
+			Arguments: AnyToString(row[3]),
+			FieldType: AnyToString(row[4]),
+			FilePath:  AnyToString(row[5]),
+			Line:      AnyToString(row[6]),
+		}
+		key := op.TypeName + "." + op.Name
+		if byField[key] != nil {
+			continue // Same field declared in several SDL files
+		}
+		byField[key] = op
+		ops = append(ops, op)
+	}
+
+	// Resolvers are matched by type and field name; a failed lookup only hides them
+	resolverScript := fmt.Sprintf(
+		"?[type_name, field_name, name, file_path, start_line] := *cie_graphql_resolver { type_name, field_name, function_id }, *cie_function { id: function_id, name, file_path, start_line }, *cie_graphql_field { type_name, name: field_name, operation, file_path: sdl_path }, %s",
+		strings.Join(buildGraphQLConditions(args, "field_name", "sdl_path"), ", "),
+	)
+	if resolvers, err := client.Query(ctx, resolverScript); err == nil {
+		for _, row := range resolvers.Rows {
+			if op := byField[AnyToString(row[0])+"."+AnyToString(row[1])]; op != nil {
+				op.Resolvers = append(op.Resolvers, fmt.Sprintf("%s (%s:%s)",
+					AnyToString(row[2]), ExtractFileName(AnyToString(row[3])), AnyToString(row[4])))
+			}
+		}
+	}
+
+	var sb strings.Builder
+	if args.TypeName != "" {
+		fmt.Fprintf(&sb, "## GraphQL fields of `%s` (%d found)\n\n", args.TypeName, len(ops))
+	} else {
+		fmt.Fprintf(&sb, "## GraphQL Operations (%d found)\n\n", len(ops))
+	}
+	sb.WriteString("| Operation | Field | Arguments | Returns | Resolver | Schema |\n")
+	sb.WriteString("|-----------|-------|-----------|---------|----------|--------|\n")
+	counts := make(map[string]int)
+	unresolved := 0
+	for _, op := range ops {
+		kind := op.Operation
+		if kind == "" {
+			kind = op.TypeName
+		}
+		counts[kind]++
+		args := "—"
+		if op.Arguments != "" {
+			args = "`" + op.Arguments + "`"
+		}
+		resolver := "—"
+		if len(op.Resolvers) > 0 {
+			resolver = strings.Join(op.Resolvers, ", ")
+		} else {
+			unresolved++
+		}
+		fmt.Fprintf(&sb, "| %s | `%s` | %s | `%s` | %s | %s:%s |\n",
+			kind, op.Name, args, op.FieldType, resolver, ExtractFileName(op.FilePath), op.Line)
+	}
+
+	if args.TypeName == "" {
+		var parts []string
+		for _, kind := range []string{"query", "mutation", "subscription"} {
+			if counts[kind] > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+			}
+		}
+		fmt.Fprintf(&sb, "\n**Summary:** %s", strings.Join(parts, ", "))
+		if unresolved > 0 {
+			fmt.Fprintf(&sb, "; %d without a linked resolver", unresolved)
+		}
+		sb.WriteString("\n")
+	}
+	if truncated {
+		fmt.Fprintf(&sb, "\n⚠️ **Warning:** Results truncated to %d operations. Use `limit` or filters to see more.\n", args.Limit)
+	}
+
+	return NewResult(sb.String()), nil
+}
+
+// buildGraphQLConditions builds the field filters for ListGraphQLOperations.
+// nameVar and pathVar name the variables bound to the field name and SDL file path.
+func buildGraphQLConditions(args ListGraphQLOperationsArgs, nameVar, pathVar string) []string {
+	var conditions []string
+	switch {
+	case args.TypeName != "":
+		conditions = append(conditions, fmt.Sprintf("type_name == %q", args.TypeName))
+	case args.Operation != "":
+		conditions = append(conditions, fmt.Sprintf("operation == %q", args.Operation))
+	default:
+		conditions = append(conditions, `operation != ""`)
+	}
+	if args.NameFilter != "" {
+		conditions = append(conditions, fmt.Sprintf(`regex_matches(%s, "(?i)%s")`, nameVar, EscapeRegex(args.NameFilter)))
+	}
+	if args.PathPattern != "" {
+		conditions = append(conditions, fmt.Sprintf("regex_matches(%s, %q)", pathVar, args.PathPattern))
+	}
+	return conditions
+}
+
+// formatNoGraphQLOperationsFound returns the message when no operations are found.
+func formatNoGraphQLOperationsFound(args ListGraphQLOperationsArgs) string {
+	if args.TypeName != "" {
+		return fmt.Sprintf("No GraphQL fields found for type `%s`.\n\n"+
+			"**Tips:**\n"+
+			"- Type names are case-sensitive (e.g., `Order`)\n"+
+			"- Use `cie_find_type` with kind `graphql_object` to list GraphQL types\n", args.TypeName)
+	}
+	return "No GraphQL operations found.\n\n" +
+		"**Tips:**\n" +
+		"- Operations come from `.graphql`/`.graphqls` SDL files; schemas built in code (e.g., `gql` template literals) are not indexed\n" +
+		"- Try without `name_filter` or `path_pattern`\n" +
+		"- Use `cie_grep` with `type Query` for manual search\n"
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// graphQLMockClient answers the queries issued by ListGraphQLOperations by relation.
+func graphQLMockClient(queries *[]string, fields [][]any) *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			*queries = append(*queries, script)
+			switch {
+			case strings.Contains(script, "*cie_graphql_resolver"):
+				return &QueryResult{Rows: [][]any{
+					{"Query", "orders", "queryResolver.Orders", "graph/schema.resolvers.go", float64(12)},
+					{"Query", "orders", "listOrders", "web/src/resolvers.ts", float64(5)},
+				}}, nil
+			case strings.Contains(script, "*cie_graphql_field"):
+				return &QueryResult{Rows: fields}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestListGraphQLOperations(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	fields := [][]any{
+		{"mutation", "Mutation", "createOrder", "input: CreateOrderInput!", "Order!", "graph/schema.graphqls", float64(30)},
+		{"query", "Query", "orders", "first: Int = 20", "[Order!]!", "graph/schema.graphqls", float64(20)},
+		{"query", "Query", "orders", "first: Int = 20", "[Order!]!", "web/schema.graphql", float64(3)},
+	}
+	result, err := ListGraphQLOperations(context.Background(), graphQLMockClient(&queries, fields), ListGraphQLOperationsArgs{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %s", result.Text)
+	}
+
+	for _, want := range []string{
+		"## GraphQL Operations (2 found)",
+		"| mutation | `createOrder` | `input: CreateOrderInput!` | `Order!` | — | schema.graphqls:30 |",
+		"queryResolver.Orders (schema.resolvers.go:12), listOrders (resolvers.ts:5)",
+		"**Summary:** 1 query, 1 mutation; 1 without a linked resolver",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Contains(result.Text, "schema.graphql:3") {
+		t.Errorf("duplicate field declarations should be collapsed, got:\n%s", result.Text)
+	}
+	if len(queries) != 2 || !strings.Contains(queries[0], `operation != ""`) {
+		t.Errorf("expected root operations filter, got queries: %v", queries)
+	}
+}
+
+func TestListGraphQLOperations_Filters(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	fields := [][]any{{"", "Order", "customer", "", "User!", "graph/schema.graphqls", float64(12)}}
+	result, err := ListGraphQLOperations(context.Background(), graphQLMockClient(&queries, fields), ListGraphQLOperationsArgs{
+		TypeName:   "Order",
+		NameFilter: "cust",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result.Text, "## GraphQL fields of `Order` (1 found)") {
+		t.Errorf("expected type heading, got:\n%s", result.Text)
+	}
+	if strings.Contains(result.Text, "**Summary:**") {
+		t.Errorf("type listings should not include the operation summary, got:\n%s", result.Text)
+	}
+	if !strings.Contains(queries[0], `type_name == "Order"`) || !strings.Contains(queries[0], `regex_matches(name, "(?i)cust")`) {
+		t.Errorf("unexpected field query: %s", queries[0])
+	}
+	if !strings.Contains(queries[1], `regex_matches(field_name, "(?i)cust")`) {
+		t.Errorf("resolver query should filter on the field name: %s", queries[1])
+	}
+}
+
+func TestListGraphQLOperations_NoResults(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	result, err := ListGraphQLOperations(context.Background(), graphQLMockClient(&queries, nil), ListGraphQLOperationsArgs{Operation: "Mutation"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result.Text, "No GraphQL operations found") {
+		t.Errorf("expected no-results message, got:\n%s", result.Text)
+	}
+	if !strings.Contains(queries[0], `operation == "mutation"`) {
+		t.Errorf("operation filter should be lowercased: %s", queries[0])
+	}
+}
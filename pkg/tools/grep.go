@@ -53,6 +53,7 @@ type GrepMatch struct {
 
 // Grep performs ultra-fast literal text search with optional context
 // Schema v3: code_text is in separate cie_function_code table
+// Package-level variables and constants (cie_variable) are searched too
 // Supports multiple patterns via 'texts' parameter for batch searches
 func Grep(ctx context.Context, client Querier, args GrepArgs) (*ToolResult, error) {
 	if len(args.Texts) > 0 {
@@ -96,8 +97,9 @@ func buildGrepQuery(args GrepArgs, needsCode bool) string {
 	}
 
 	return fmt.Sprintf(
-		"?[%s] := *cie_function { id, file_path, name, start_line, end_line }, *cie_function_code { function_id: id, code_text }, %s :limit %d",
-		selectFields, strings.Join(conditions, ", "), args.Limit,
+		"?[%s] := *cie_function { id, file_path, name, start_line, end_line }, *cie_function_code { function_id: id, code_text }, %s "+
+			"?[%s] := *cie_variable { file_path, name, start_line, end_line, code_text }, %s :limit %d",
+		selectFields, strings.Join(conditions, ", "), selectFields, strings.Join(conditions, ", "), args.Limit,
 	)
 }
 
@@ -267,8 +269,9 @@ func buildGrepMultiQuery(args GrepArgs) string {
 	}
 
 	return fmt.Sprintf(
-		"?[file_path, name, start_line, code_text] := *cie_function { id, file_path, name, start_line }, *cie_function_code { function_id: id, code_text }, %s :limit %d",
-		strings.Join(conditions, ", "), args.Limit*len(args.Texts),
+		"?[file_path, name, start_line, code_text] := *cie_function { id, file_path, name, start_line }, *cie_function_code { function_id: id, code_text }, %s "+
+			"?[file_path, name, start_line, code_text] := *cie_variable { file_path, name, start_line, code_text }, %s :limit %d",
+		strings.Join(conditions, ", "), strings.Join(conditions, ", "), args.Limit*len(args.Texts),
 	)
 }
 
@@ -859,6 +859,19 @@ func TestBuildGrepQuery(t *testing.T) {
 			},
 			// Note: code_text is always in query for matching, but not in select fields
 		},
+		{
+			name: "query includes package-level variables",
+			args: GrepArgs{
+				Text:  "DefaultTimeout",
+				Path:  "pkg/config",
+				Limit: 100,
+			},
+			needsCode: false,
+			wantContains: []string{
+				"*cie_function_code",
+				"?[file_path, name, start_line, end_line] := *cie_variable { file_path, name, start_line, end_line, code_text }, regex_matches(code_text,",
+			},
+		},
 		{
 			name: "query with code for context",
 			args: GrepArgs{
@@ -0,0 +1,250 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultHotspotsLimit is how many functions cie_hotspots lists by default.
+	defaultHotspotsLimit = 20
+
+	// maxHotspotRows caps the functions fetched, the most complex first.
+	maxHotspotRows = 5000
+)
+
+// HotspotsArgs holds arguments for the hotspots tool.
+type HotspotsArgs struct {
+	// Path restricts the ranking to a directory (e.g., "pkg/tools").
+	Path string
+
+	// SortBy is the ranking key: "score" (default), "complexity", "fan_in",
+	// "nesting", "loc" or "churn" (complexity × commits, from cie history).
+	SortBy string
+
+	// MinComplexity drops functions below this cyclomatic complexity
+	// (default 1, all functions).
+	MinComplexity int
+
+	// Limit caps the functions listed (default 20).
+	Limit int
+}
+
+// hotspot is a function with its metrics and fan-in.
+type hotspot struct {
+	Name         string
+	File         string
+	Line         int
+	Complexity   int
+	MaxNesting   int
+	Params       int
+	LOC          int
+	CommentRatio float64
+	FanIn        int
+	Commits      int // Commits changing the function (cie history)
+	Authors      int // Distinct authors of those commits
+	Score        float64
+}
+
+// hotspotScore weighs complexity by how many functions depend on it:
+// complexity × (1 + log2(1 + fan-in)). The logarithm keeps small helpers
+// called from everywhere below complex functions with a few callers.
+func hotspotScore(complexity, fanIn int) float64 {
+	return float64(complexity) * (1 + math.Log2(1+float64(fanIn)))
+}
+
+// hotspotLess orders hotspots by the sort key, then by score and name.
+func hotspotLess(sortBy string) (func(a, b hotspot) bool, bool) {
+	key := map[string]func(h hotspot) float64{
+		"score":      func(h hotspot) float64 { return h.Score },
+		"complexity": func(h hotspot) float64 { return float64(h.Complexity) },
+		"fan_in":     func(h hotspot) float64 { return float64(h.FanIn) },
+		"nesting":    func(h hotspot) float64 { return float64(h.MaxNesting) },
+		"loc":        func(h hotspot) float64 { return float64(h.LOC) },
+		"churn":      func(h hotspot) float64 { return float64(h.Complexity * h.Commits) },
+	}[sortBy]
+	if key == nil {
+		return nil, false
+	}
+	return func(a, b hotspot) bool {
+		if key(a) != key(b) {
+			return key(a) > key(b)
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Name < b.Name
+	}, true
+}
+
+// Hotspots ranks functions by cyclomatic complexity combined with fan-in
+// (distinct callers in cie_calls), from the metrics computed at indexing.
+// When git history was imported (cie history), commits and authors are
+// listed too and sort_by=churn ranks by commits × complexity. Test files,
+// generated code and anonymous functions are excluded.
+func Hotspots(ctx context.Context, client Querier, args HotspotsArgs) (*ToolResult, error) {
+	sortBy := args.SortBy
+	if sortBy == "" {
+		sortBy = "score"
+	}
+	less, ok := hotspotLess(sortBy)
+	if !ok {
+		return NewError(fmt.Sprintf("Error: unknown sort_by %q: use score, complexity, fan_in, nesting, loc or churn", args.SortBy)), nil
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultHotspotsLimit
+	}
+	minComplexity := args.MinComplexity
+	if minComplexity < 1 {
+		minComplexity = 1
+	}
+	dir := strings.TrimSuffix(strings.TrimPrefix(args.Path, "./"), "/")
+
+	pathConds := RoleFilters("source")
+	title := "the repository"
+	if dir != "" && dir != "." {
+		pathConds = append(pathConds, fmt.Sprintf("starts_with(file_path, %q)", dir+"/"))
+		title = fmt.Sprintf("`%s`", dir)
+	}
+	conds := append(pathConds, `negate(starts_with(name, "$"))`, fmt.Sprintf("complexity >= %d", minComplexity))
+	script := fmt.Sprintf(`fan_in[callee_id, count_unique(caller_id)] := *cie_calls { caller_id, callee_id }
+called[callee_id] := *cie_calls { callee_id }
+measured[function_id, name, file_path, start_line, complexity, max_nesting, params, loc, comment_ratio] :=
+  *cie_function_metrics { function_id, complexity, max_nesting, params, loc, comment_ratio },
+  *cie_function { id: function_id, name, file_path, start_line },
+  %s
+?[name, file_path, start_line, complexity, max_nesting, params, loc, comment_ratio, callers] :=
+  measured[function_id, name, file_path, start_line, complexity, max_nesting, params, loc, comment_ratio], fan_in[function_id, callers]
+?[name, file_path, start_line, complexity, max_nesting, params, loc, comment_ratio, callers] :=
+  measured[function_id, name, file_path, start_line, complexity, max_nesting, params, loc, comment_ratio], not called[function_id], callers = 0
+:order -complexity :limit %d`, strings.Join(conds, ",\n  "), maxHotspotRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		if strings.Contains(err.Error(), "cie_function_metrics") {
+			return NewResult("Function metrics are not available in this index: re-index the project (`cie index`) to compute complexity and size metrics.\n"), nil
+		}
+		return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, script)), nil
+	}
+
+	var hotspots []hotspot
+	for _, row := range result.Rows {
+		if len(row) < 9 {
+			continue
+		}
+		ratio, _ := strconv.ParseFloat(AnyToString(row[7]), 64)
+		h := hotspot{
+			Name:         AnyToString(row[0]),
+			File:         AnyToString(row[1]),
+			Line:         rowLine(row[2]),
+			Complexity:   rowLine(row[3]),
+			MaxNesting:   rowLine(row[4]),
+			Params:       rowLine(row[5]),
+			LOC:          rowLine(row[6]),
+			CommentRatio: ratio,
+			FanIn:        rowLine(row[8]),
+		}
+		h.Score = hotspotScore(h.Complexity, h.FanIn)
+		hotspots = append(hotspots, h)
+	}
+	churn := queryHotspotChurn(ctx, client, pathConds)
+	for i := range hotspots {
+		if c, ok := churn[hotspotKey(hotspots[i].File, hotspots[i].Line, hotspots[i].Name)]; ok {
+			hotspots[i].Commits, hotspots[i].Authors = c[0], c[1]
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Hotspots in %s\n\n", title)
+	if len(hotspots) == 0 {
+		if minComplexity > 1 {
+			fmt.Fprintf(&sb, "No function with complexity %d or more.\n", minComplexity)
+		} else {
+			sb.WriteString("No function metrics found: supported languages are Go, Python, JavaScript, TypeScript and PHP. Re-index (`cie index`) if the index predates metrics.\n")
+		}
+		return NewResult(sb.String()), nil
+	}
+	if sortBy == "churn" && len(churn) == 0 {
+		sb.WriteString("No git history imported: run `cie history` to rank by churn × complexity.\n")
+		return NewResult(sb.String()), nil
+	}
+	sort.SliceStable(hotspots, func(i, j int) bool { return less(hotspots[i], hotspots[j]) })
+	ranking := strings.ReplaceAll(sortBy, "_", "-")
+	if sortBy == "churn" {
+		ranking = "churn (commits × complexity)"
+	}
+	fmt.Fprintf(&sb, "%d functions measured, ranked by %s", len(hotspots), ranking)
+	if len(hotspots) > limit {
+		fmt.Fprintf(&sb, " (showing the top %d)", limit)
+		hotspots = hotspots[:limit]
+	}
+	sb.WriteString(". Score = complexity × (1 + log2(1 + fan-in)).\n\n")
+	if len(churn) == 0 {
+		sb.WriteString("| # | Function | Complexity | Nesting | Fan-in | Params | LOC | Comments | Score |\n")
+		sb.WriteString("|---|----------|------------|---------|--------|--------|-----|----------|-------|\n")
+	} else {
+		sb.WriteString("| # | Function | Complexity | Nesting | Fan-in | Params | LOC | Comments | Commits | Authors | Score |\n")
+		sb.WriteString("|---|----------|------------|---------|--------|--------|-----|----------|---------|---------|-------|\n")
+	}
+	for i, h := range hotspots {
+		fmt.Fprintf(&sb, "| %d | `%s` (%s:%d) | %d | %d | %d | %d | %d | %.0f%% |",
+			i+1, h.Name, h.File, h.Line, h.Complexity, h.MaxNesting, h.FanIn, h.Params, h.LOC, h.CommentRatio*100)
+		if len(churn) > 0 {
+			fmt.Fprintf(&sb, " %d | %d |", h.Commits, h.Authors)
+		}
+		fmt.Fprintf(&sb, " %.1f |\n", h.Score)
+	}
+	sb.WriteString("\n_Complexity is cyclomatic (1 + branches, loops, cases, catch clauses, && and ||). Fan-in counts distinct callers in the call graph; calls through interfaces or callbacks are not counted._\n")
+	return NewResult(sb.String()), nil
+}
+
+// hotspotKey identifies a function across queries by position and name.
+func hotspotKey(file string, line int, name string) string {
+	return fmt.Sprintf("%s:%d:%s", file, line, name)
+}
+
+// queryHotspotChurn returns the commits and authors of the functions
+// matching conds, keyed by hotspotKey. It returns nil when no git history
+// was imported, so the churn columns can be left out.
+func queryHotspotChurn(ctx context.Context, client Querier, conds []string) map[string][2]int {
+	script := fmt.Sprintf(`?[file_path, start_line, name, commits, authors] :=
+  *cie_function_churn { function_id, commits, authors },
+  *cie_function { id: function_id, name, file_path, start_line },
+  %s
+:limit %d`, strings.Join(conds, ",\n  "), maxHotspotRows)
+	result, err := client.Query(ctx, script)
+	if err != nil || len(result.Rows) == 0 {
+		return nil
+	}
+	churn := make(map[string][2]int, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		churn[hotspotKey(AnyToString(row[0]), rowLine(row[1]), AnyToString(row[2]))] = [2]int{rowLine(row[3]), rowLine(row[4])}
+	}
+	return churn
+}
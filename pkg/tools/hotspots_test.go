@@ -0,0 +1,153 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// hotspotsMockClient serves function metrics: a complex handler with two
+// callers, a simple helper with many callers and an uncalled parser.
+func hotspotsMockClient(script *string) *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, s string) (*QueryResult, error) {
+			if strings.Contains(s, "cie_function_churn") {
+				return &QueryResult{}, nil
+			}
+			*script = s
+			return &QueryResult{Rows: [][]any{
+				{"Server.HandleOrder", "internal/http/orders.go", float64(50), float64(18), float64(4), float64(2), float64(120), 0.05, float64(2)},
+				{"parseQuery", "internal/query/parse.go", float64(10), float64(12), float64(3), float64(1), float64(80), 0.2, float64(0)},
+				{"quote", "internal/query/util.go", float64(5), float64(2), float64(0), float64(1), float64(6), 0.0, float64(40)},
+			}}, nil
+		},
+	}
+}
+
+func TestHotspots(t *testing.T) {
+	var script string
+	result, err := Hotspots(context.Background(), hotspotsMockClient(&script), HotspotsArgs{Path: "./internal/"})
+	if err != nil {
+		t.Fatalf("Hotspots: %v", err)
+	}
+	for _, want := range []string{
+		"count_unique(caller_id)",
+		"*cie_function_metrics",
+		`starts_with(file_path, "internal/")`,
+		"complexity >= 1",
+		"not called[function_id], callers = 0",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+
+	// Scores: HandleOrder 18 × (1 + log2 3) = 46.5, parseQuery 12, quote 2 × (1 + log2 41) = 12.7
+	for _, want := range []string{
+		"## Hotspots in `internal`",
+		"3 functions measured, ranked by score.",
+		"| 1 | `Server.HandleOrder` (internal/http/orders.go:50) | 18 | 4 | 2 | 2 | 120 | 5% | 46.5 |",
+		"| 2 | `quote` (internal/query/util.go:5) | 2 | 0 | 40 | 1 | 6 | 0% | 12.7 |",
+		"| 3 | `parseQuery` (internal/query/parse.go:10) | 12 | 3 | 0 | 1 | 80 | 20% | 12.0 |",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("output missing %q:\n%s", want, result.Text)
+		}
+	}
+}
+
+func TestHotspots_SortAndLimit(t *testing.T) {
+	var script string
+	result, err := Hotspots(context.Background(), hotspotsMockClient(&script), HotspotsArgs{SortBy: "fan_in", MinComplexity: 2, Limit: 1})
+	if err != nil {
+		t.Fatalf("Hotspots: %v", err)
+	}
+	if !strings.Contains(result.Text, "ranked by fan-in (showing the top 1).") {
+		t.Errorf("expected the sort key and limit in the summary:\n%s", result.Text)
+	}
+	if !strings.Contains(script, "complexity >= 2") {
+		t.Errorf("script missing min complexity:\n%s", script)
+	}
+	if !strings.Contains(result.Text, "| 1 | `quote`") || strings.Contains(result.Text, "| 2 | `") {
+		t.Errorf("expected only quote, ranked by fan-in:\n%s", result.Text)
+	}
+
+	result, _ = Hotspots(context.Background(), hotspotsMockClient(&script), HotspotsArgs{SortBy: "age"})
+	if !result.IsError {
+		t.Errorf("unknown sort_by should fail, got:\n%s", result.Text)
+	}
+}
+
+func TestHotspots_Churn(t *testing.T) {
+	var script string
+	metrics := hotspotsMockClient(&script)
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, s string) (*QueryResult, error) {
+			if strings.Contains(s, "cie_function_churn") {
+				return &QueryResult{Rows: [][]any{
+					{"internal/query/parse.go", float64(10), "parseQuery", float64(9), float64(3)},
+					{"internal/http/orders.go", float64(50), "Server.HandleOrder", float64(1), float64(1)},
+				}}, nil
+			}
+			return metrics.QueryFunc(ctx, s)
+		},
+	}
+
+	// Churn: parseQuery 12 × 9 = 108, HandleOrder 18 × 1 = 18, quote never changed.
+	result, err := Hotspots(context.Background(), client, HotspotsArgs{SortBy: "churn"})
+	if err != nil {
+		t.Fatalf("Hotspots: %v", err)
+	}
+	for _, want := range []string{
+		"ranked by churn (commits × complexity).",
+		"| Commits | Authors | Score |",
+		"| 1 | `parseQuery` (internal/query/parse.go:10) | 12 | 3 | 0 | 1 | 80 | 20% | 9 | 3 | 12.0 |",
+		"| 2 | `Server.HandleOrder` (internal/http/orders.go:50) | 18 | 4 | 2 | 2 | 120 | 5% | 1 | 1 | 46.5 |",
+		"| 3 | `quote` (internal/query/util.go:5) | 2 | 0 | 40 | 1 | 6 | 0% | 0 | 0 | 12.7 |",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("output missing %q:\n%s", want, result.Text)
+		}
+	}
+
+	// Without imported history, churn ranking points to cie history.
+	result, _ = Hotspots(context.Background(), metrics, HotspotsArgs{SortBy: "churn"})
+	if result.IsError || !strings.Contains(result.Text, "cie history") {
+		t.Errorf("expected a cie history hint, got:\n%s", result.Text)
+	}
+}
+
+func TestHotspots_NotIndexed(t *testing.T) {
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			return nil, errors.New("relation cie_function_metrics not found")
+		},
+	}
+	result, err := Hotspots(context.Background(), client, HotspotsArgs{})
+	if err != nil {
+		t.Fatalf("Hotspots: %v", err)
+	}
+	if result.IsError || !strings.Contains(result.Text, "re-index") {
+		t.Errorf("expected a re-index hint, got:\n%s", result.Text)
+	}
+}
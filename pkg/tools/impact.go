@@ -0,0 +1,891 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultImpactDepth is how many caller levels cie_impact follows from
+	// the changed symbols by default.
+	defaultImpactDepth = 5
+
+	// maxImpactDepth caps the caller levels followed from the changed symbols.
+	maxImpactDepth = 10
+
+	// defaultImpactLimit is how many affected functions cie_impact lists by
+	// default.
+	defaultImpactLimit = 50
+
+	// maxImpactRows caps the rows fetched per symbol, caller or route query.
+	maxImpactRows = 5000
+)
+
+// ChangedFile is a file touched by a diff, with the lines it changes in the
+// new version of the file.
+type ChangedFile struct {
+	Path    string         `json:"path"`               // Path after the change; "" when the file is deleted
+	OldPath string         `json:"old_path,omitempty"` // Path before the change; "" when the file is added
+	Binary  bool           `json:"binary,omitempty"`   // Binary file: no line information
+	Lines   []ChangedLines `json:"lines,omitempty"`    // Changed lines, in file order
+}
+
+// ChangedLines is an inclusive range of changed lines in the new version of
+// a file. A deletion leaves no line behind: it is the pair of lines around
+// the deleted ones, and only changes a symbol holding both.
+type ChangedLines struct {
+	Start    int  `json:"start"`
+	End      int  `json:"end"`
+	Deletion bool `json:"deletion,omitempty"`
+}
+
+// touches reports whether the lines change a symbol spanning start to end.
+func (l ChangedLines) touches(start, end int) bool {
+	if l.Deletion {
+		return l.Start >= start && l.End <= end
+	}
+	return l.Start <= end && l.End >= start
+}
+
+// ImpactArgs holds arguments for the impact tool.
+type ImpactArgs struct {
+	// Changes are the files changed by the diff, parsed from git diff
+	// output or a raw unified diff by the ingestion package.
+	Changes []ChangedFile
+
+	// HeadSHA is the commit the diff ends at, when known. Changed lines are
+	// mapped onto the index, which must be at that commit for them to match.
+	HeadSHA string
+
+	// MaxDepth caps the caller levels followed from the changed symbols
+	// (default 5, at most 10).
+	MaxDepth int
+
+	// Limit caps the affected functions listed (default 50). Endpoints,
+	// services, entry points and tests are always listed in full.
+	Limit int
+}
+
+// ImpactSymbol is a function or type changed by the diff, or a function
+// reaching a changed one.
+type ImpactSymbol struct {
+	Kind     string `json:"kind"` // "function" or "type"
+	Name     string `json:"name"`
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+	Distance int    `json:"distance"`      // Hops from a changed symbol; 0 for the changed ones
+	Via      string `json:"via,omitempty"` // How it reaches the change, e.g. "calls Store.Get"
+}
+
+// ImpactEndpoint is an HTTP route whose handler or registration is affected.
+type ImpactEndpoint struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Handler  string `json:"handler"`
+	FilePath string `json:"file_path"` // File registering the route
+	Line     int    `json:"line"`
+	Distance int    `json:"distance"` // Distance of the handler; 0 when the registration itself changed
+}
+
+// ImpactRPC is a gRPC method whose definition or implementation is affected.
+type ImpactRPC struct {
+	Service  string `json:"service"`
+	Method   string `json:"method"`
+	FilePath string `json:"file_path"` // Proto file defining the method
+	Line     int    `json:"line"`
+	Handler  string `json:"handler,omitempty"` // Implementing function; "" when the proto definition changed
+	Distance int    `json:"distance"`
+}
+
+// ImpactTest is a test reaching a changed symbol.
+type ImpactTest struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+	Distance int    `json:"distance"`
+}
+
+// ImpactReport is the result of an impact analysis, every list ranked by
+// distance from the change.
+type ImpactReport struct {
+	FilesChanged   int              `json:"files_changed"`
+	Depth          int              `json:"depth"`             // Caller levels followed
+	Warning        string           `json:"warning,omitempty"` // Index and diff may not match
+	Changed        []ImpactSymbol   `json:"changed"`           // Functions and types whose lines the diff changes
+	Unmapped       []string         `json:"unmapped"`          // Changed files with no indexed symbol changed
+	Endpoints      []ImpactEndpoint `json:"endpoints"`
+	Services       []ImpactRPC      `json:"services"`
+	EntryPoints    []ImpactSymbol   `json:"entry_points"`
+	Tests          []ImpactTest     `json:"tests"`
+	TestCommands   []string         `json:"test_commands,omitempty"`
+	Affected       []ImpactSymbol   `json:"affected"`       // Functions reaching the change, capped at Limit
+	AffectedTotal  int              `json:"affected_total"` // Functions reaching the change
+	AffectedCapped bool             `json:"affected_capped"`
+}
+
+// impactLink is a function reaching an already reached symbol.
+type impactLink struct {
+	id  string
+	sym ImpactSymbol
+}
+
+// AnalyzeImpact maps the changed lines of a diff onto the indexed functions
+// and types, and follows what depends on them level by level up to
+// MaxDepth: callers through cie_calls, functions holding an interface that
+// a changed method implements through cie_implements, and, for changed
+// types, the functions referencing them. The functions reached are then
+// matched against HTTP routes, gRPC methods, entry points and tests.
+func AnalyzeImpact(ctx context.Context, client Querier, args ImpactArgs) (*ImpactReport, error) {
+	depth := args.MaxDepth
+	if depth <= 0 {
+		depth = defaultImpactDepth
+	}
+	if depth > maxImpactDepth {
+		depth = maxImpactDepth
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultImpactLimit
+	}
+
+	report := &ImpactReport{FilesChanged: len(args.Changes), Depth: depth}
+	report.Warning = impactIndexWarning(ctx, client, args.HeadSHA)
+	functions, types, err := queryChangedSymbols(ctx, client, args.Changes, report)
+	if err != nil {
+		return nil, err
+	}
+
+	reached := make(map[string]*ImpactSymbol, len(functions))
+	var frontier []string
+	for id, sym := range functions {
+		reached[id] = sym
+		frontier = append(frontier, id)
+	}
+	sort.Strings(frontier)
+	for level := 1; level <= depth; level++ {
+		links, err := queryImpactCallers(ctx, client, frontier, reached)
+		if err != nil {
+			return nil, err
+		}
+		ifaceLinks, err := queryInterfaceUsers(ctx, client, frontier, reached)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, ifaceLinks...)
+		if level == 1 {
+			typeLinks, err := queryTypeUsers(ctx, client, types)
+			if err != nil {
+				return nil, err
+			}
+			links = append(links, typeLinks...)
+		}
+
+		var next []string
+		for _, l := range links {
+			if _, ok := reached[l.id]; ok {
+				continue
+			}
+			sym := l.sym
+			sym.Distance = level
+			reached[l.id] = &sym
+			next = append(next, l.id)
+		}
+		if len(next) == 0 {
+			break
+		}
+		sort.Strings(next)
+		frontier = next
+	}
+
+	for _, sym := range reached {
+		if sym.Distance == 0 {
+			continue
+		}
+		report.Affected = append(report.Affected, *sym)
+		if isEntryPoint(sym.Name, sym.FilePath) {
+			report.EntryPoints = append(report.EntryPoints, *sym)
+		}
+	}
+	for _, sym := range functions {
+		if isEntryPoint(sym.Name, sym.FilePath) {
+			report.EntryPoints = append(report.EntryPoints, *sym)
+		}
+	}
+	sortImpactSymbols(report.Affected)
+	sortImpactSymbols(report.EntryPoints)
+	report.AffectedTotal = len(report.Affected)
+	if len(report.Affected) > limit {
+		report.Affected = report.Affected[:limit]
+		report.AffectedCapped = true
+	}
+
+	if report.Tests, report.TestCommands, err = queryImpactTests(ctx, client, reached); err != nil {
+		return nil, err
+	}
+	if report.Endpoints, err = queryImpactEndpoints(ctx, client, reached, args.Changes); err != nil {
+		return nil, err
+	}
+	if report.Services, err = queryImpactServices(ctx, client, reached); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// impactIndexWarning warns when the index was built at another commit than
+// the one the diff ends at, as changed lines are then mapped onto stale
+// line numbers.
+func impactIndexWarning(ctx context.Context, client Querier, headSHA string) string {
+	if headSHA == "" {
+		return ""
+	}
+	result, err := client.Query(ctx, `?[value] := *cie_project_meta { key, value }, key = "last_indexed_sha"`)
+	if err != nil || len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return ""
+	}
+	indexed := AnyToString(result.Rows[0][0])
+	if indexed == "" || strings.HasPrefix(indexed, headSHA) || strings.HasPrefix(headSHA, indexed) {
+		return ""
+	}
+	return fmt.Sprintf("The index is at commit %s but the diff ends at %s: changed lines may map onto the wrong symbols. Check out the head commit and run `cie index` first.",
+		shortSHA(indexed), shortSHA(headSHA))
+}
+
+// shortSHA abbreviates a commit hash to 7 characters.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// queryChangedSymbols returns the functions and types whose lines the diff
+// changes, keyed by ID, and records in the report the changed files where
+// no indexed symbol changed. Anonymous functions are left to the function
+// enclosing them.
+func queryChangedSymbols(ctx context.Context, client Querier, changes []ChangedFile, report *ImpactReport) (functions, types map[string]*ImpactSymbol, err error) {
+	functions = make(map[string]*ImpactSymbol)
+	types = make(map[string]*ImpactSymbol)
+	lines := make(map[string][]ChangedLines)
+	var paths []string
+	for _, f := range changes {
+		switch {
+		case f.Path == "":
+			report.Unmapped = append(report.Unmapped, f.OldPath+" (deleted)")
+		case f.Binary || len(f.Lines) == 0:
+			report.Unmapped = append(report.Unmapped, f.Path)
+		default:
+			lines[f.Path] = f.Lines
+			paths = append(paths, f.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return functions, types, nil
+	}
+
+	mapped := make(map[string]bool)
+	touched := func(file string, start, end int) bool {
+		for _, l := range lines[file] {
+			if l.touches(start, end) {
+				mapped[file] = true
+				return true
+			}
+		}
+		return false
+	}
+	fileCond := impactAnyOf("file_path", paths)
+
+	result, err := client.Query(ctx, fmt.Sprintf(`?[id, name, file_path, start_line, end_line] := *cie_function { id, name, file_path, start_line, end_line }, %s
+:limit %d`, fileCond, maxImpactRows))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		name, file := AnyToString(row[1]), AnyToString(row[2])
+		if strings.HasPrefix(name, "$anon") || !touched(file, rowLine(row[3]), rowLine(row[4])) {
+			continue
+		}
+		functions[AnyToString(row[0])] = &ImpactSymbol{Kind: "function", Name: name, FilePath: file, Line: rowLine(row[3])}
+	}
+
+	result, err = client.Query(ctx, fmt.Sprintf(`?[id, name, file_path, start_line, end_line] := *cie_type { id, name, file_path, start_line, end_line }, %s
+:limit %d`, fileCond, maxImpactRows))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		file := AnyToString(row[2])
+		if !touched(file, rowLine(row[3]), rowLine(row[4])) {
+			continue
+		}
+		types[AnyToString(row[0])] = &ImpactSymbol{Kind: "type", Name: AnyToString(row[1]), FilePath: file, Line: rowLine(row[3])}
+	}
+
+	for _, p := range paths {
+		if !mapped[p] {
+			report.Unmapped = append(report.Unmapped, p)
+		}
+	}
+	for _, sym := range functions {
+		report.Changed = append(report.Changed, *sym)
+	}
+	for _, sym := range types {
+		report.Changed = append(report.Changed, *sym)
+	}
+	sort.Slice(report.Changed, func(i, j int) bool {
+		a, b := report.Changed[i], report.Changed[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Line < b.Line
+	})
+	return functions, types, nil
+}
+
+// queryImpactCallers returns the callers of the frontier functions.
+func queryImpactCallers(ctx context.Context, client Querier, frontier []string, reached map[string]*ImpactSymbol) ([]impactLink, error) {
+	if len(frontier) == 0 {
+		return nil, nil
+	}
+	script := fmt.Sprintf(`?[callee_id, caller_id, name, file_path, start_line] := *cie_calls { caller_id, callee_id }, %s,
+  *cie_function { id: caller_id, name, file_path, start_line }
+:limit %d`, impactAnyOf("callee_id", frontier), maxImpactRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	var links []impactLink
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		links = append(links, impactLink{id: AnyToString(row[1]), sym: ImpactSymbol{
+			Kind:     "function",
+			Name:     AnyToString(row[2]),
+			FilePath: AnyToString(row[3]),
+			Line:     rowLine(row[4]),
+			Via:      "calls " + reached[AnyToString(row[0])].Name,
+		}})
+	}
+	return links, nil
+}
+
+// queryInterfaceUsers returns the functions holding an interface that a
+// frontier method implements, which may call the method through the
+// interface without a cie_calls edge. The interface must declare the
+// method when its method set can be read.
+func queryInterfaceUsers(ctx context.Context, client Querier, frontier []string, reached map[string]*ImpactSymbol) ([]impactLink, error) {
+	methods := make(map[string][]string) // receiver type → frontier methods
+	var receivers []string
+	for _, id := range frontier {
+		name := reached[id].Name
+		i := strings.LastIndex(name, ".")
+		if i <= 0 {
+			continue
+		}
+		if _, ok := methods[name[:i]]; !ok {
+			receivers = append(receivers, name[:i])
+		}
+		methods[name[:i]] = append(methods[name[:i]], name)
+	}
+	if len(receivers) == 0 {
+		return nil, nil
+	}
+
+	script := fmt.Sprintf(`?[type_name, iface_id, iface, code_text] := *cie_implements { type_name, interface_name }, %s,
+  *cie_type { id: iface_id, name: iface }, (interface_name = iface or ends_with(interface_name, concat(".", iface))),
+  *cie_type_code { type_id: iface_id, code_text }
+:limit %d`, impactAnyOf("type_name", receivers), maxImpactRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	via := make(map[string]string) // interface ID → how its users reach the change
+	var ifaces []string
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		ifaceID, iface := AnyToString(row[1]), AnyToString(row[2])
+		declared := extractMethodNames(AnyToString(row[3]))
+		for _, method := range methods[AnyToString(row[0])] {
+			short := method[strings.LastIndex(method, ".")+1:]
+			if len(declared) > 0 && !slices.Contains(declared, short) {
+				continue
+			}
+			if _, ok := via[ifaceID]; !ok {
+				ifaces = append(ifaces, ifaceID)
+				via[ifaceID] = fmt.Sprintf("uses interface %s, implemented by %s", iface, method)
+			}
+			break
+		}
+	}
+	if len(ifaces) == 0 {
+		return nil, nil
+	}
+	return queryTypeReferrers(ctx, client, ifaces, via)
+}
+
+// queryTypeUsers returns the functions referencing the changed types.
+func queryTypeUsers(ctx context.Context, client Querier, types map[string]*ImpactSymbol) ([]impactLink, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(types))
+	via := make(map[string]string, len(types))
+	for id, sym := range types {
+		ids = append(ids, id)
+		via[id] = "uses type " + sym.Name
+	}
+	return queryTypeReferrers(ctx, client, ids, via)
+}
+
+// queryTypeReferrers returns the functions referencing the types, described
+// by the via of the type they reference.
+func queryTypeReferrers(ctx context.Context, client Querier, typeIDs []string, via map[string]string) ([]impactLink, error) {
+	script := fmt.Sprintf(`?[type_id, from_id, name, file_path, start_line] := *cie_type_ref { from_id, type_id }, %s,
+  *cie_function { id: from_id, name, file_path, start_line }
+:limit %d`, impactAnyOf("type_id", typeIDs), maxImpactRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	var links []impactLink
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		links = append(links, impactLink{id: AnyToString(row[1]), sym: ImpactSymbol{
+			Kind:     "function",
+			Name:     AnyToString(row[2]),
+			FilePath: AnyToString(row[3]),
+			Line:     rowLine(row[4]),
+			Via:      via[AnyToString(row[0])],
+		}})
+	}
+	return links, nil
+}
+
+// queryImpactTests returns the tests among the reached functions and the
+// commands running them.
+func queryImpactTests(ctx context.Context, client Querier, reached map[string]*ImpactSymbol) ([]ImpactTest, []string, error) {
+	if len(reached) == 0 {
+		return nil, nil, nil
+	}
+	funcs := make(map[string]reachedFunc, len(reached))
+	for id, sym := range reached {
+		funcs[id] = reachedFunc{Name: sym.Name}
+	}
+	tests, err := queryTestsOf(ctx, client, funcs)
+	if err != nil {
+		if strings.Contains(err.Error(), "cie_test") {
+			return nil, nil, nil // Tests not indexed
+		}
+		return nil, nil, err
+	}
+
+	var out []ImpactTest
+	byFile := make(map[string][]testEntity)
+	var files []string
+	for _, t := range tests {
+		fn, ok := reached[t.FunctionID]
+		if !ok {
+			continue
+		}
+		out = append(out, ImpactTest{Name: t.Name, Kind: t.Kind, FilePath: t.File, Line: t.Line, Distance: fn.Distance})
+		if _, ok := byFile[t.File]; !ok {
+			files = append(files, t.File)
+		}
+		byFile[t.File] = append(byFile[t.File], t)
+	}
+	sort.Strings(files)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Distance != out[j].Distance {
+			return out[i].Distance < out[j].Distance
+		}
+		if out[i].FilePath != out[j].FilePath {
+			return out[i].FilePath < out[j].FilePath
+		}
+		return out[i].Line < out[j].Line
+	})
+	return out, testRunCommands(files, byFile), nil
+}
+
+// routeHandlerPattern reads the handler of a Go route registration from
+// the text following its path: the last argument before the closing
+// parenthesis, possibly wrapped as in http.HandlerFunc(h.Get).
+var routeHandlerPattern = regexp.MustCompile(`^\s*,[^;\n]*?([A-Za-z_][\w.]*)\s*\)`)
+
+// queryImpactEndpoints returns the HTTP routes whose handler was reached or
+// whose registration line changed. Go handlers are read from the route
+// registration, PHP handlers from the Laravel action or Symfony attribute.
+func queryImpactEndpoints(ctx context.Context, client Querier, reached map[string]*ImpactSymbol, changes []ChangedFile) ([]ImpactEndpoint, error) {
+	script := fmt.Sprintf("?[file_path, name, start_line, code_text] := *cie_function { id, file_path, name, start_line }, *cie_function_code { function_id: id, code_text }, %s :limit 500",
+		buildEndpointQueryConditions(ListEndpointsArgs{}))
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+
+	changedLines := make(map[string][]ChangedLines, len(changes))
+	for _, f := range changes {
+		changedLines[f.Path] = f.Lines
+	}
+	byMethod := make(map[string][]*ImpactSymbol)
+	for _, sym := range reached {
+		short := sym.Name[strings.LastIndex(sym.Name, ".")+1:]
+		byMethod[short] = append(byMethod[short], sym)
+	}
+
+	found := make(map[string]*ImpactEndpoint)
+	add := func(ep endpoint, line, distance int) {
+		key := ep.Method + " " + ep.Path + " " + ep.Handler
+		if prev, ok := found[key]; ok && prev.Distance <= distance {
+			return
+		}
+		found[key] = &ImpactEndpoint{Method: ep.Method, Path: ep.Path, Handler: ep.Handler, FilePath: ep.FilePath, Line: line, Distance: distance}
+	}
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		file, fn, code := AnyToString(row[0]), AnyToString(row[1]), AnyToString(row[3])
+		start := rowLine(row[2])
+		for _, p := range httpMethodPatterns {
+			for _, loc := range p.pattern.FindAllStringSubmatchIndex(code, -1) {
+				match := make([]string, len(loc)/2)
+				for i := range match {
+					if loc[2*i] >= 0 {
+						match[i] = code[loc[2*i]:loc[2*i+1]]
+					}
+				}
+				ep := extractEndpointFromMatch(match, p.methodIndex, p.pathIndex, file, fn, strconv.Itoa(start))
+				if ep == nil {
+					continue
+				}
+				line := start + strings.Count(code[:loc[0]], "\n")
+				handler := ""
+				if m := routeHandlerPattern.FindStringSubmatch(code[loc[1]:]); m != nil {
+					handler = m[1]
+					ep.Handler = handler
+				}
+				if linesChange(changedLines[file], line) {
+					add(*ep, line, 0)
+				} else if sym := matchHandler(handler, byMethod); sym != nil {
+					add(*ep, line, sym.Distance)
+				}
+			}
+		}
+		for _, ep := range parsePHPRoutesFromCode(code, file, fn, strconv.Itoa(start)) {
+			if sym := matchHandler(ep.Handler, byMethod); sym != nil {
+				add(ep, start, sym.Distance)
+			}
+		}
+	}
+
+	endpoints := make([]ImpactEndpoint, 0, len(found))
+	for _, ep := range found {
+		endpoints = append(endpoints, *ep)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		a, b := endpoints[i], endpoints[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return endpoints, nil
+}
+
+// linesChange reports whether the changed lines include line.
+func linesChange(lines []ChangedLines, line int) bool {
+	for _, l := range lines {
+		if !l.Deletion && l.touches(line, line) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHandler returns the closest reached function a route handler names:
+// h.ListUsers, handlers.ListUsers or UserController@index. A capitalized
+// qualifier is a type or class the function must be a method of.
+func matchHandler(handler string, byMethod map[string][]*ImpactSymbol) *ImpactSymbol {
+	if handler == "" {
+		return nil
+	}
+	handler = strings.ReplaceAll(handler, "@", ".")
+	qualifier, method := "", handler
+	if i := strings.LastIndex(handler, "."); i >= 0 {
+		qualifier, method = handler[:i], handler[i+1:]
+		qualifier = qualifier[strings.LastIndex(qualifier, ".")+1:]
+	}
+	var best *ImpactSymbol
+	for _, sym := range byMethod[method] {
+		if qualifier != "" && qualifier[:1] == strings.ToUpper(qualifier[:1]) && !strings.HasSuffix(sym.Name, qualifier+"."+method) {
+			continue
+		}
+		if best == nil || sym.Distance < best.Distance {
+			best = sym
+		}
+	}
+	return best
+}
+
+// queryImpactServices returns the gRPC methods whose proto definition
+// changed or whose implementation was reached. A Go method implements an
+// rpc of the same name when its type implements the generated <Service>Server
+// interface, or is named after the service.
+func queryImpactServices(ctx context.Context, client Querier, reached map[string]*ImpactSymbol) ([]ImpactRPC, error) {
+	result, err := client.Query(ctx, fmt.Sprintf(`?[name, file_path, start_line] := *cie_function { name, file_path, start_line }, ends_with(file_path, ".proto"), str_includes(name, ".")
+:limit %d`, maxImpactRows))
+	if err != nil {
+		return nil, err
+	}
+	rpcs := make(map[string][]ImpactRPC) // rpc method → definitions
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		service, method, _ := strings.Cut(AnyToString(row[0]), ".")
+		rpcs[method] = append(rpcs[method], ImpactRPC{Service: service, Method: method, FilePath: AnyToString(row[1]), Line: rowLine(row[2])})
+	}
+	if len(rpcs) == 0 {
+		return nil, nil
+	}
+
+	var candidates []*ImpactSymbol
+	var receivers []string
+	for _, sym := range reached {
+		i := strings.LastIndex(sym.Name, ".")
+		if i <= 0 || len(rpcs[sym.Name[i+1:]]) == 0 {
+			continue
+		}
+		candidates = append(candidates, sym)
+		if !strings.HasSuffix(sym.FilePath, ".proto") {
+			receivers = append(receivers, sym.Name[:i])
+		}
+	}
+	servers := make(map[string][]string) // receiver type → interfaces it implements
+	if len(receivers) > 0 {
+		result, err := client.Query(ctx, fmt.Sprintf(`?[type_name, interface_name] := *cie_implements { type_name, interface_name }, %s`, impactAnyOf("type_name", receivers)))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result.Rows {
+			if len(row) >= 2 {
+				iface := AnyToString(row[1])
+				servers[AnyToString(row[0])] = append(servers[AnyToString(row[0])], iface[strings.LastIndex(iface, ".")+1:])
+			}
+		}
+	}
+
+	found := make(map[string]*ImpactRPC)
+	for _, sym := range candidates {
+		i := strings.LastIndex(sym.Name, ".")
+		receiver, method := sym.Name[:i], sym.Name[i+1:]
+		for _, rpc := range rpcs[method] {
+			switch {
+			case strings.HasSuffix(sym.FilePath, ".proto"):
+				if sym.FilePath != rpc.FilePath || receiver != rpc.Service {
+					continue
+				}
+			case slices.Contains(servers[receiver], rpc.Service+"Server"):
+				rpc.Handler = sym.Name
+			default:
+				name := strings.ToLower(strings.TrimSuffix(rpc.Service, "Service"))
+				if name == "" || !strings.Contains(strings.ToLower(receiver), name) {
+					continue
+				}
+				rpc.Handler = sym.Name
+			}
+			rpc.Distance = sym.Distance
+			key := rpc.FilePath + ":" + rpc.Service + "." + rpc.Method
+			if prev, ok := found[key]; !ok || sym.Distance < prev.Distance {
+				found[key] = &rpc
+			}
+		}
+	}
+
+	services := make([]ImpactRPC, 0, len(found))
+	for _, rpc := range found {
+		services = append(services, *rpc)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		a, b := services[i], services[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Method < b.Method
+	})
+	return services, nil
+}
+
+// entryPointRegexps are the name and file patterns of entryPointPatterns.
+var entryPointRegexps = func() [][2]*regexp.Regexp {
+	out := make([][2]*regexp.Regexp, len(entryPointPatterns))
+	for i, p := range entryPointPatterns {
+		out[i] = [2]*regexp.Regexp{regexp.MustCompile(p.namePattern), regexp.MustCompile(p.filePattern)}
+	}
+	return out
+}()
+
+// entryPointTestRegexp matches the test files excluded from entry points.
+var entryPointTestRegexp = regexp.MustCompile(entryPointTestFiles)
+
+// isEntryPoint reports whether a function is an entry point by the
+// conventions of detectEntryPoints.
+func isEntryPoint(name, file string) bool {
+	if entryPointTestRegexp.MatchString(file) {
+		return false
+	}
+	for _, p := range entryPointRegexps {
+		if p[0].MatchString(name) && p[1].MatchString(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortImpactSymbols orders symbols by distance, file and line.
+func sortImpactSymbols(symbols []ImpactSymbol) {
+	sort.Slice(symbols, func(i, j int) bool {
+		a, b := symbols[i], symbols[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Line < b.Line
+	})
+}
+
+// impactAnyOf matches field against any of values.
+func impactAnyOf(field string, values []string) string {
+	values = uniqueSorted(sortedCopy(values))
+	conds := make([]string, len(values))
+	for i, v := range values {
+		conds[i] = fmt.Sprintf("%s = %q", field, v)
+	}
+	return "(" + strings.Join(conds, " or ") + ")"
+}
+
+// Impact reports what a change could break: the functions and types it
+// changes, then the HTTP endpoints, gRPC methods, entry points, tests and
+// functions reaching them, ranked by distance. See AnalyzeImpact.
+func Impact(ctx context.Context, client Querier, args ImpactArgs) (*ToolResult, error) {
+	if len(args.Changes) == 0 {
+		return NewResult("The diff changes no file.\n"), nil
+	}
+	report, err := AnalyzeImpact(ctx, client, args)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Impact of the change\n\n")
+	if report.Warning != "" {
+		fmt.Fprintf(&sb, "⚠️ %s\n\n", report.Warning)
+	}
+	fmt.Fprintf(&sb, "%d files changed: %d symbols changed, %d functions reach them within %d caller levels.\n",
+		report.FilesChanged, len(report.Changed), report.AffectedTotal, report.Depth)
+	if len(report.Changed) == 0 {
+		sb.WriteString("\nNo indexed function or type is changed: the diff may only touch files that are not indexed, or the index may not match the diff (run `cie index` at its head).\n")
+	} else {
+		sb.WriteString("\n### Changed\n\n")
+		for _, s := range report.Changed {
+			fmt.Fprintf(&sb, "- `%s` (%s, %s:%d)\n", s.Name, s.Kind, s.FilePath, s.Line)
+		}
+	}
+
+	if len(report.Endpoints) > 0 {
+		sb.WriteString("\n### HTTP endpoints\n\n| Distance | Method | Path | Handler | Route |\n|----------|--------|------|---------|-------|\n")
+		for _, ep := range report.Endpoints {
+			fmt.Fprintf(&sb, "| %d | %s | `%s` | %s | %s:%d |\n", ep.Distance, ep.Method, ep.Path, ep.Handler, ep.FilePath, ep.Line)
+		}
+	}
+	if len(report.Services) > 0 {
+		sb.WriteString("\n### gRPC methods\n\n| Distance | Method | Implementation | Proto |\n|----------|--------|----------------|-------|\n")
+		for _, rpc := range report.Services {
+			handler := rpc.Handler
+			if handler == "" {
+				handler = "(definition changed)"
+			}
+			fmt.Fprintf(&sb, "| %d | %s.%s | %s | %s:%d |\n", rpc.Distance, rpc.Service, rpc.Method, handler, rpc.FilePath, rpc.Line)
+		}
+	}
+	if len(report.EntryPoints) > 0 {
+		sb.WriteString("\n### Entry points\n\n")
+		for _, s := range report.EntryPoints {
+			fmt.Fprintf(&sb, "- `%s` (%s:%d) — distance %d\n", s.Name, s.FilePath, s.Line, s.Distance)
+		}
+	}
+	if len(report.Tests) > 0 {
+		fmt.Fprintf(&sb, "\n### Tests (%d)\n\n", len(report.Tests))
+		for _, t := range report.Tests {
+			fmt.Fprintf(&sb, "- `%s` (%s, %s:%d) — distance %d\n", t.Name, t.Kind, t.FilePath, t.Line, t.Distance)
+		}
+		if len(report.TestCommands) > 0 {
+			sb.WriteString("\nRun:\n\n")
+			for _, cmd := range report.TestCommands {
+				fmt.Fprintf(&sb, "- `%s`\n", cmd)
+			}
+		}
+	} else if len(report.Changed) > 0 {
+		sb.WriteString("\n### Tests\n\nNo test reaches the changed symbols within the caller levels followed.\n")
+	}
+	if len(report.Affected) > 0 {
+		sb.WriteString("\n### Affected functions\n\n| Distance | Function | Reached through |\n|----------|----------|-----------------|\n")
+		for _, s := range report.Affected {
+			fmt.Fprintf(&sb, "| %d | `%s` (%s:%d) | %s |\n", s.Distance, s.Name, s.FilePath, s.Line, s.Via)
+		}
+		if report.AffectedCapped {
+			fmt.Fprintf(&sb, "\n_Showing the %d closest of %d functions: raise `limit` to see more._\n", len(report.Affected), report.AffectedTotal)
+		}
+	}
+	if len(report.Unmapped) > 0 {
+		sb.WriteString("\n### Files with no indexed symbol changed\n\n")
+		for _, f := range report.Unmapped {
+			fmt.Fprintf(&sb, "- %s\n", f)
+		}
+	}
+	sb.WriteString("\n_Distance counts the hops from a changed symbol through calls, interfaces and type references. Calls through reflection, callbacks or unresolved dynamic dispatch are not seen._\n")
+	return NewResult(sb.String()), nil
+}
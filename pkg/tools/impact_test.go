@@ -0,0 +1,228 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// impactMockClient serves a change to Store.Get and the Config type:
+// Service.User calls Store.Get, Handler.GetUser (a GET route and the
+// UserService.GetUser rpc) and main call Service.User, TestGetUser calls
+// Handler.GetUser, NewStore uses Config and Cache.Load holds the Getter
+// interface Store implements.
+func impactMockClient(scripts *[]string) *MockCIEClient {
+	callers := map[string][][]any{
+		"fn:get":     {{"fn:get", "fn:svc", "Service.User", "internal/user/service.go", float64(30)}},
+		"fn:svc":     {{"fn:svc", "fn:handler", "Handler.GetUser", "internal/http/handler.go", float64(25)}, {"fn:svc", "fn:main", "main", "cmd/server/main.go", float64(12)}},
+		"fn:handler": {{"fn:handler", "fn:test", "TestGetUser", "internal/http/handler_test.go", float64(31)}},
+	}
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			*scripts = append(*scripts, script)
+			switch {
+			case strings.Contains(script, "*cie_project_meta"):
+				return &QueryResult{Rows: [][]any{{"abc1234def5678"}}}, nil
+			case strings.Contains(script, "*cie_test"):
+				if !strings.Contains(script, `"fn:test"`) {
+					return &QueryResult{}, nil
+				}
+				return &QueryResult{Rows: [][]any{{"fn:test", "TestGetUser", "test", "", "go", "internal/http/handler_test.go", float64(31)}}}, nil
+			case strings.Contains(script, "*cie_function_code"):
+				return &QueryResult{Rows: [][]any{{"internal/http/routes.go", "registerRoutes", float64(10),
+					"func registerRoutes(r *gin.Engine, h *Handler) {\n\tr.GET(\"/users/:id\", h.GetUser)\n\tr.POST(\"/users\", h.CreateUser)\n}"}}}, nil
+			case strings.Contains(script, `".proto"`):
+				return &QueryResult{Rows: [][]any{{"UserService.GetUser", "api/user.proto", float64(8)}, {"UserService.DeleteUser", "api/user.proto", float64(9)}}}, nil
+			case strings.Contains(script, "*cie_type_code"):
+				return &QueryResult{Rows: [][]any{{"Store", "type:getter", "Getter", "type Getter interface {\n\tGet(id string) (User, error)\n}"}}}, nil
+			case strings.Contains(script, "*cie_implements"):
+				return &QueryResult{Rows: [][]any{{"Handler", "pb.UserServiceServer"}}}, nil
+			case strings.Contains(script, "*cie_type_ref"):
+				var rows [][]any
+				if strings.Contains(script, `"type:config"`) {
+					rows = append(rows, []any{"type:config", "fn:newstore", "NewStore", "internal/store/store.go", float64(20)})
+				}
+				if strings.Contains(script, `"type:getter"`) {
+					rows = append(rows, []any{"type:getter", "fn:cache", "Cache.Load", "internal/cache/cache.go", float64(15)})
+				}
+				return &QueryResult{Rows: rows}, nil
+			case strings.Contains(script, "*cie_calls"):
+				var rows [][]any
+				for callee, r := range callers {
+					if strings.Contains(script, `"`+callee+`"`) {
+						rows = append(rows, r...)
+					}
+				}
+				return &QueryResult{Rows: rows}, nil
+			case strings.Contains(script, "*cie_type {"):
+				return &QueryResult{Rows: [][]any{{"type:config", "Config", "internal/store/config.go", float64(10), float64(14)}}}, nil
+			case strings.Contains(script, "*cie_function {"):
+				return &QueryResult{Rows: [][]any{
+					{"fn:new", "NewStore", "internal/store/store.go", float64(20), float64(30)},
+					{"fn:get", "Store.Get", "internal/store/store.go", float64(42), float64(50)},
+					{"fn:anon", "$anon_1", "internal/store/store.go", float64(44), float64(47)},
+				}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+// impactChanges edits Store.Get and Config, deletes a file and touches
+// the README.
+var impactChanges = []ChangedFile{
+	{Path: "internal/store/store.go", OldPath: "internal/store/store.go", Lines: []ChangedLines{{Start: 45, End: 46}, {Start: 35, End: 36, Deletion: true}}},
+	{Path: "internal/store/config.go", OldPath: "internal/store/config.go", Lines: []ChangedLines{{Start: 12, End: 12}}},
+	{Path: "", OldPath: "internal/store/legacy.go"},
+	{Path: "README.md", OldPath: "README.md", Lines: []ChangedLines{{Start: 3, End: 4}}},
+}
+
+func TestAnalyzeImpact(t *testing.T) {
+	var scripts []string
+	report, err := AnalyzeImpact(context.Background(), impactMockClient(&scripts), ImpactArgs{Changes: impactChanges, HeadSHA: "fedcba9876543210"})
+	if err != nil {
+		t.Fatalf("AnalyzeImpact: %v", err)
+	}
+
+	var changed []string
+	for _, s := range report.Changed {
+		changed = append(changed, s.Kind+" "+s.Name)
+	}
+	if got := strings.Join(changed, ", "); got != "type Config, function Store.Get" {
+		t.Errorf("changed = %s", got)
+	}
+	if got := strings.Join(report.Unmapped, ", "); got != "internal/store/legacy.go (deleted), README.md" {
+		t.Errorf("unmapped = %s", got)
+	}
+	if !strings.Contains(report.Warning, "abc1234") || !strings.Contains(report.Warning, "fedcba9") {
+		t.Errorf("warning = %q", report.Warning)
+	}
+
+	var affected []string
+	for _, s := range report.Affected {
+		affected = append(affected, fmt.Sprintf("%s@%d %s", s.Name, s.Distance, s.Via))
+	}
+	want := []string{
+		"Cache.Load@1 uses interface Getter, implemented by Store.Get",
+		"NewStore@1 uses type Config",
+		"Service.User@1 calls Store.Get",
+		"main@2 calls Service.User",
+		"Handler.GetUser@2 calls Service.User",
+		"TestGetUser@3 calls Handler.GetUser",
+	}
+	if got := strings.Join(affected, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("affected:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+
+	if len(report.Endpoints) != 1 || report.Endpoints[0] != (ImpactEndpoint{Method: "GET", Path: "/users/:id", Handler: "h.GetUser", FilePath: "internal/http/routes.go", Line: 11, Distance: 2}) {
+		t.Errorf("endpoints = %+v", report.Endpoints)
+	}
+	if len(report.Services) != 1 || report.Services[0] != (ImpactRPC{Service: "UserService", Method: "GetUser", FilePath: "api/user.proto", Line: 8, Handler: "Handler.GetUser", Distance: 2}) {
+		t.Errorf("services = %+v", report.Services)
+	}
+	if len(report.EntryPoints) != 1 || report.EntryPoints[0].Name != "main" || report.EntryPoints[0].Distance != 2 {
+		t.Errorf("entry points = %+v", report.EntryPoints)
+	}
+	if len(report.Tests) != 1 || report.Tests[0].Name != "TestGetUser" || report.Tests[0].Distance != 3 {
+		t.Errorf("tests = %+v", report.Tests)
+	}
+	if got := strings.Join(report.TestCommands, "\n"); got != "go test ./internal/http -run '^(TestGetUser)$'" {
+		t.Errorf("test commands = %s", got)
+	}
+}
+
+func TestAnalyzeImpact_Depth(t *testing.T) {
+	var scripts []string
+	report, err := AnalyzeImpact(context.Background(), impactMockClient(&scripts), ImpactArgs{Changes: impactChanges, MaxDepth: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("AnalyzeImpact: %v", err)
+	}
+	if report.AffectedTotal != 3 || len(report.Affected) != 2 || !report.AffectedCapped {
+		t.Errorf("affected total %d, listed %d, capped %v", report.AffectedTotal, len(report.Affected), report.AffectedCapped)
+	}
+	if len(report.Endpoints) != 0 || len(report.Tests) != 0 || report.Warning != "" {
+		t.Errorf("nothing past depth 1 expected: %+v", report)
+	}
+}
+
+func TestAnalyzeImpact_RouteLineChanged(t *testing.T) {
+	var scripts []string
+	changes := []ChangedFile{{Path: "internal/http/routes.go", OldPath: "internal/http/routes.go", Lines: []ChangedLines{{Start: 12, End: 12}}}}
+	report, err := AnalyzeImpact(context.Background(), impactMockClient(&scripts), ImpactArgs{Changes: changes})
+	if err != nil {
+		t.Fatalf("AnalyzeImpact: %v", err)
+	}
+	if len(report.Endpoints) != 1 || report.Endpoints[0].Path != "/users" || report.Endpoints[0].Distance != 0 {
+		t.Errorf("endpoints = %+v", report.Endpoints)
+	}
+}
+
+func TestImpact(t *testing.T) {
+	var scripts []string
+	result, err := Impact(context.Background(), impactMockClient(&scripts), ImpactArgs{Changes: impactChanges})
+	if err != nil {
+		t.Fatalf("Impact: %v", err)
+	}
+	for _, want := range []string{
+		"4 files changed: 2 symbols changed, 6 functions reach them within 5 caller levels.",
+		"- `Store.Get` (function, internal/store/store.go:42)",
+		"| 2 | GET | `/users/:id` | h.GetUser | internal/http/routes.go:11 |",
+		"| 2 | UserService.GetUser | Handler.GetUser | api/user.proto:8 |",
+		"- `main` (cmd/server/main.go:12) — distance 2",
+		"- `TestGetUser` (test, internal/http/handler_test.go:31) — distance 3",
+		"| 1 | `Service.User` (internal/user/service.go:30) | calls Store.Get |",
+		"- internal/store/legacy.go (deleted)",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("output missing %q:\n%s", want, result.Text)
+		}
+	}
+
+	empty, _ := Impact(context.Background(), impactMockClient(&scripts), ImpactArgs{})
+	if !strings.Contains(empty.Text, "The diff changes no file.") {
+		t.Errorf("empty diff: %s", empty.Text)
+	}
+}
+
+func TestMatchHandler(t *testing.T) {
+	get := &ImpactSymbol{Name: "Handler.GetUser", Distance: 2}
+	index := &ImpactSymbol{Name: "UserController.index", Distance: 1}
+	byMethod := map[string][]*ImpactSymbol{"GetUser": {get}, "index": {index}}
+	tests := []struct {
+		handler string
+		want    *ImpactSymbol
+	}{
+		{"h.GetUser", get},
+		{"GetUser", get},
+		{"Handler.GetUser", get},
+		{"Other.GetUser", nil},
+		{"UserController@index", index},
+		{"PostController@index", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := matchHandler(tt.handler, byMethod); got != tt.want {
+			t.Errorf("matchHandler(%q) = %v, want %v", tt.handler, got, tt.want)
+		}
+	}
+}
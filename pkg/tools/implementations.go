@@ -35,6 +35,8 @@ type FindImplementationsArgs struct {
 
 // FindImplementations finds types that implement a given interface.
 // For Go: searches for types with methods matching the interface's method signatures.
+// For Go constraints with a type set (~int | ~float64): lists the types of the set
+// recorded in cie_implements at index time.
 // For TypeScript: searches for classes with "implements InterfaceName".
 func FindImplementations(ctx context.Context, client Querier, args FindImplementationsArgs) (*ToolResult, error) {
 	if args.InterfaceName == "" {
@@ -77,6 +79,9 @@ func FindImplementations(ctx context.Context, client Querier, args FindImplement
 
 	// Step 2: Extract method names from interface code
 	methods := extractMethodNames(interfaceCode)
+	if terms := extractTypeSetLines(interfaceCode); len(terms) > 0 {
+		return findConstraintImplementations(ctx, client, args, methods, terms, &sb)
+	}
 	if len(methods) == 0 {
 		sb.WriteString("Could not extract methods from interface definition.\n\n")
 		return findImplementationsByTextSearch(ctx, client, args, &sb)
@@ -136,6 +141,60 @@ func extractMethodNames(code string) []string {
 	return methods
 }
 
+// typeSetLinePattern matches the type set lines of a Go constraint interface,
+// e.g. "~int | ~float64" or "~string".
+var typeSetLinePattern = regexp.MustCompile(`(?m)^\s*(~?\w+(?:\.\w+)?(?:\s*\|\s*~?\w+(?:\.\w+)?)*)\s*$`)
+
+// extractTypeSetLines returns the type set lines of a Go constraint interface.
+// Bare names without ~ or | are embedded interfaces and are skipped.
+func extractTypeSetLines(code string) []string {
+	var lines []string
+	for _, match := range typeSetLinePattern.FindAllStringSubmatch(code, -1) {
+		if strings.ContainsAny(match[1], "~|") {
+			lines = append(lines, match[1])
+		}
+	}
+	return lines
+}
+
+// findConstraintImplementations lists the types satisfying a Go constraint
+// interface, as recorded in cie_implements by the indexer.
+func findConstraintImplementations(ctx context.Context, client Querier, args FindImplementationsArgs, methods, terms []string, sb *strings.Builder) (*ToolResult, error) {
+	fmt.Fprintf(sb, "**Type set**: %s\n\n", strings.Join(terms, ", "))
+	if len(methods) > 0 {
+		fmt.Fprintf(sb, "**Methods**: %s\n\n", strings.Join(methods, ", "))
+	}
+
+	pathCond := ""
+	if args.PathPattern != "" {
+		pathCond = fmt.Sprintf(", regex_matches(file_path, %q)", args.PathPattern)
+	}
+	query := fmt.Sprintf(
+		`?[type_name, kind, file_path, start_line] := *cie_implements { type_name, interface_name }, interface_name == %q,
+		*cie_type { name: type_name, kind, file_path, start_line }%s :order file_path, start_line :limit %d`,
+		args.InterfaceName, pathCond, args.Limit,
+	)
+	result, err := client.Query(ctx, query)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query error: %v", err)), nil
+	}
+
+	if len(result.Rows) == 0 {
+		sb.WriteString("No types satisfying this constraint found.\n\n")
+		sb.WriteString("**Tips:**\n")
+		sb.WriteString("- Predeclared types (int, float64, ...) satisfy it but are not indexed\n")
+		sb.WriteString("- Re-index the project (`cie index`) to record constraint implementations\n")
+		return NewResult(sb.String()), nil
+	}
+
+	fmt.Fprintf(sb, "**Found %d type(s) satisfying the constraint:**\n\n", len(result.Rows))
+	for i, row := range result.Rows {
+		fmt.Fprintf(sb, "%d. **%s** (%s)\n", i+1, AnyToString(row[0]), AnyToString(row[1]))
+		fmt.Fprintf(sb, "   File: %s:%s\n\n", AnyToString(row[2]), AnyToString(row[3]))
+	}
+	return NewResult(sb.String()), nil
+}
+
 // receiverData holds aggregated method data for a receiver type.
 type receiverData struct {
 	methods  []string
@@ -193,6 +252,9 @@ func extractReceiverFromRow(row []any, method string, receivers map[string]*rece
 		return
 	}
 	receiver := strings.Join(parts[:len(parts)-1], ".")
+	if i := strings.IndexAny(receiver, "[<"); i > 0 {
+		receiver = receiver[:i] // Generic receiver: List[T].Get is a method of List
+	}
 	data, exists := receivers[receiver]
 	if !exists {
 		data = &receiverData{filePath: AnyToString(row[1]), line: AnyToString(row[2])}
@@ -0,0 +1,76 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindImplementations_Constraint(t *testing.T) {
+	client := &MockCIEClient{QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+		switch {
+		case strings.Contains(script, `kind == "interface"`):
+			return &QueryResult{Rows: [][]any{
+				{"Number", "interface", "pkg/coll/coll.go", "Number interface {\n\t~int | ~float64\n}", 3},
+			}}, nil
+		case strings.Contains(script, "*cie_implements"):
+			if !strings.Contains(script, `interface_name == "Number"`) {
+				t.Errorf("query should filter on the constraint, got: %s", script)
+			}
+			return &QueryResult{Rows: [][]any{
+				{"Celsius", "type_alias", "pkg/units/temp.go", 5},
+			}}, nil
+		}
+		t.Errorf("unexpected query: %s", script)
+		return &QueryResult{}, nil
+	}}
+
+	result, err := FindImplementations(context.Background(), client, FindImplementationsArgs{InterfaceName: "Number"})
+	if err != nil || result.IsError {
+		t.Fatalf("FindImplementations() error = %v, result = %+v", err, result)
+	}
+	for _, want := range []string{"**Type set**: ~int | ~float64", "**Celsius** (type_alias)", "pkg/units/temp.go:5"} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("result should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+}
+
+func TestExtractTypeSetLines(t *testing.T) {
+	code := "Ordered interface {\n\t~int | ~int64 | MyFloat\n\t~string\n\tfmt.Stringer\n\tString() string\n}"
+
+	got := extractTypeSetLines(code)
+	want := []string{"~int | ~int64 | MyFloat", "~string"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractTypeSetLines() = %q, want %q", got, want)
+	}
+}
+
+func TestExtractReceiverFromRow_GenericReceiver(t *testing.T) {
+	receivers := make(map[string]*receiverData)
+	extractReceiverFromRow([]any{"List[T].Get", "coll/list.go", 12}, "Get", receivers)
+
+	if data := receivers["List"]; data == nil || data.filePath != "coll/list.go" {
+		t.Errorf("receivers = %+v, want a List entry", receivers)
+	}
+}
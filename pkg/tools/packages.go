@@ -0,0 +1,314 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PackageGraphArgs holds arguments for the package dependency report.
+type PackageGraphArgs struct {
+	// Path restricts the report to a package directory and its subpackages
+	// (e.g., "pkg/ingestion"). Leave empty for the whole repository.
+	Path string
+
+	// Limit is the maximum number of packages listed (default: 50).
+	Limit int
+}
+
+// packageNode is a package from cie_package with its edges from cie_package_import.
+type packageNode struct {
+	Path      string
+	Name      string
+	Language  string
+	FileCount int
+	Imports   map[string]int // to_path -> import statements
+	Importers map[string]int // from_path -> import statements
+}
+
+// instability returns Ce / (Ca + Ce): 0 for a package only depended upon, 1
+// for a package only depending on others, -1 for an isolated package.
+func (n *packageNode) instability() float64 {
+	ca, ce := len(n.Importers), len(n.Imports)
+	if ca+ce == 0 {
+		return -1
+	}
+	return float64(ce) / float64(ca+ce)
+}
+
+// PackageGraph reports the package dependency graph under a path: per package
+// fan-in (packages importing it), fan-out (packages it imports) and
+// instability, and the import cycles involving those packages. When Path is a
+// single package, its imports and importers are listed too.
+//
+// Metrics are computed over the whole repository, so a package's fan-in counts
+// importers outside Path.
+func PackageGraph(ctx context.Context, client Querier, args PackageGraphArgs) (*ToolResult, error) {
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+	filter := strings.TrimSuffix(strings.TrimPrefix(args.Path, "./"), "/")
+	if filter == "." {
+		filter = ""
+	}
+
+	packages, err := queryPackageGraph(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if len(packages) == 0 {
+		return NewResult("No packages found.\n\n" +
+			"**Tips:**\n" +
+			"- Packages are built from Go, PHP, Python, JavaScript and TypeScript directories\n" +
+			"- Re-index the project (`cie index`) if it was indexed before package graphs were available\n"), nil
+	}
+
+	var selected []*packageNode
+	for _, pkg := range packages {
+		if filter == "" || pkg.Path == filter || strings.HasPrefix(pkg.Path, filter+"/") {
+			selected = append(selected, pkg)
+		}
+	}
+	if len(selected) == 0 {
+		return NewResult(fmt.Sprintf("No packages under `%s`.\n", args.Path)), nil
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Path < selected[j].Path })
+
+	var sb strings.Builder
+	sb.WriteString("## Package Graph")
+	if filter != "" {
+		fmt.Fprintf(&sb, " for `%s`", filter)
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString("Fan-in: packages importing it. Fan-out: packages it imports. " +
+		"Instability = fan-out / (fan-in + fan-out), from 0 (stable, depended upon) to 1 (depends on others).\n\n")
+
+	sb.WriteString("| Package | Name | Language | Files | Fan-in | Fan-out | Instability |\n")
+	sb.WriteString("|---------|------|----------|-------|--------|---------|-------------|\n")
+	for i, pkg := range selected {
+		if i >= args.Limit {
+			fmt.Fprintf(&sb, "\n_... and %d more packages (increase `limit` or narrow `path`)_\n", len(selected)-args.Limit)
+			break
+		}
+		instability := "-"
+		if v := pkg.instability(); v >= 0 {
+			instability = fmt.Sprintf("%.2f", v)
+		}
+		fmt.Fprintf(&sb, "| `%s` | %s | %s | %d | %d | %d | %s |\n",
+			pkg.Path, pkg.Name, pkg.Language, pkg.FileCount, len(pkg.Importers), len(pkg.Imports), instability)
+	}
+
+	if pkg, ok := packages[filter]; ok && filter != "" {
+		writePackageEdges(&sb, "Imports", pkg.Imports)
+		writePackageEdges(&sb, "Imported by", pkg.Importers)
+	}
+
+	sb.WriteString("\n### Import Cycles\n\n")
+	cycles := 0
+	for _, scc := range packageCycles(packages) {
+		if !anyPackageUnder(scc, filter) {
+			continue
+		}
+		cycles++
+		path := shortestPackageCycle(packages, scc)
+		fmt.Fprintf(&sb, "- `%s` (%d packages in cycle)\n", strings.Join(path, "` → `"), len(scc))
+	}
+	if cycles == 0 {
+		sb.WriteString("No import cycles.\n")
+	}
+
+	return NewResult(sb.String()), nil
+}
+
+// queryPackageGraph loads all packages and package import edges, keyed by path.
+func queryPackageGraph(ctx context.Context, client Querier) (map[string]*packageNode, error) {
+	pkgResult, err := client.Query(ctx, "?[path, name, language, file_count] := *cie_package { path, name, language, file_count }")
+	if err != nil {
+		return nil, fmt.Errorf("query packages: %w", err)
+	}
+	packages := make(map[string]*packageNode, len(pkgResult.Rows))
+	for _, row := range pkgResult.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		fileCount, _ := strconv.Atoi(AnyToString(row[3]))
+		path := AnyToString(row[0])
+		packages[path] = &packageNode{
+			Path:      path,
+			Name:      AnyToString(row[1]),
+			Language:  AnyToString(row[2]),
+			FileCount: fileCount,
+			Imports:   make(map[string]int),
+			Importers: make(map[string]int),
+		}
+	}
+	if len(packages) == 0 {
+		return packages, nil
+	}
+
+	edgeResult, err := client.Query(ctx, "?[from_path, to_path, import_count] := *cie_package_import { from_path, to_path, import_count }")
+	if err != nil {
+		return nil, fmt.Errorf("query package imports: %w", err)
+	}
+	for _, row := range edgeResult.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		from, to := packages[AnyToString(row[0])], packages[AnyToString(row[1])]
+		if from == nil || to == nil {
+			continue
+		}
+		count, _ := strconv.Atoi(AnyToString(row[2]))
+		from.Imports[to.Path] = count
+		to.Importers[from.Path] = count
+	}
+	return packages, nil
+}
+
+// writePackageEdges lists the packages on the other side of a package's edges.
+func writePackageEdges(sb *strings.Builder, title string, edges map[string]int) {
+	fmt.Fprintf(sb, "\n### %s (%d)\n\n", title, len(edges))
+	if len(edges) == 0 {
+		sb.WriteString("_None_\n")
+		return
+	}
+	paths := make([]string, 0, len(edges))
+	for path := range edges {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(sb, "- `%s` (%d imports)\n", path, edges[path])
+	}
+}
+
+// packageCycles returns the strongly connected components of the import
+// graph with more than one package (Tarjan's algorithm), each sorted by path.
+func packageCycles(packages map[string]*packageNode) [][]string {
+	paths := make([]string, 0, len(packages))
+	for path := range packages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	index := make(map[string]int, len(paths))
+	lowlink := make(map[string]int, len(paths))
+	onStack := make(map[string]bool)
+	var stack []string
+	var sccs [][]string
+
+	var visit func(path string)
+	visit = func(path string) {
+		index[path] = len(index)
+		lowlink[path] = index[path]
+		stack = append(stack, path)
+		onStack[path] = true
+
+		for _, next := range sortedKeys(packages[path].Imports) {
+			if _, seen := index[next]; !seen {
+				visit(next)
+				lowlink[path] = min(lowlink[path], lowlink[next])
+			} else if onStack[next] {
+				lowlink[path] = min(lowlink[path], index[next])
+			}
+		}
+
+		if lowlink[path] != index[path] {
+			return
+		}
+		var scc []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == path {
+				break
+			}
+		}
+		if len(scc) > 1 {
+			sort.Strings(scc)
+			sccs = append(sccs, scc)
+		}
+	}
+	for _, path := range paths {
+		if _, seen := index[path]; !seen {
+			visit(path)
+		}
+	}
+
+	sort.Slice(sccs, func(i, j int) bool { return sccs[i][0] < sccs[j][0] })
+	return sccs
+}
+
+// shortestPackageCycle returns the shortest import cycle through the first
+// package of a strongly connected component, starting and ending with it.
+func shortestPackageCycle(packages map[string]*packageNode, scc []string) []string {
+	inSCC := make(map[string]bool, len(scc))
+	for _, path := range scc {
+		inSCC[path] = true
+	}
+
+	start := scc[0]
+	prev := map[string]string{start: ""}
+	queue := []string{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range sortedKeys(packages[cur].Imports) {
+			if next == start {
+				cycle := []string{start}
+				for p := cur; p != start; p = prev[p] {
+					cycle = append([]string{p}, cycle...)
+				}
+				return append([]string{start}, cycle...)
+			}
+			if _, seen := prev[next]; !seen && inSCC[next] {
+				prev[next] = cur
+				queue = append(queue, next)
+			}
+		}
+	}
+	return scc
+}
+
+// anyPackageUnder reports whether any package path is filter or below it.
+func anyPackageUnder(paths []string, filter string) bool {
+	for _, path := range paths {
+		if filter == "" || path == filter || strings.HasPrefix(path, filter+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// packageGraphMockClient serves a small package graph: cmd/cie depends on
+// pkg/tools, which forms a cycle with pkg/storage through pkg/cozodb.
+func packageGraphMockClient() *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			switch {
+			case strings.Contains(script, "*cie_package_import"):
+				return &QueryResult{Rows: [][]any{
+					{"cmd/cie", "pkg/tools", float64(6)},
+					{"cmd/cie", "pkg/storage", float64(1)},
+					{"pkg/tools", "pkg/storage", float64(3)},
+					{"pkg/storage", "pkg/cozodb", float64(1)},
+					{"pkg/cozodb", "pkg/tools", float64(1)},
+				}}, nil
+			case strings.Contains(script, "*cie_package"):
+				return &QueryResult{Rows: [][]any{
+					{"cmd/cie", "main", "go", float64(12)},
+					{"pkg/tools", "tools", "go", float64(30)},
+					{"pkg/storage", "storage", "go", float64(4)},
+					{"pkg/cozodb", "cozodb", "go", float64(2)},
+					{"web", "web", "typescript", float64(3)},
+				}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestPackageGraph_All(t *testing.T) {
+	t.Parallel()
+
+	result, err := PackageGraph(context.Background(), packageGraphMockClient(), PackageGraphArgs{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"## Package Graph\n",
+		"| `cmd/cie` | main | go | 12 | 0 | 2 | 1.00 |",
+		"| `pkg/storage` | storage | go | 4 | 2 | 1 | 0.33 |",
+		"| `pkg/tools` | tools | go | 30 | 2 | 1 | 0.33 |",
+		"| `web` | web | typescript | 3 | 0 | 0 | - |",
+		"- `pkg/cozodb` → `pkg/tools` → `pkg/storage` → `pkg/cozodb` (3 packages in cycle)",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, result.Text)
+		}
+	}
+}
+
+func TestPackageGraph_SinglePackage(t *testing.T) {
+	t.Parallel()
+
+	result, err := PackageGraph(context.Background(), packageGraphMockClient(), PackageGraphArgs{Path: "./pkg/tools/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"## Package Graph for `pkg/tools`",
+		"### Imports (1)\n\n- `pkg/storage` (3 imports)",
+		"### Imported by (2)\n\n- `cmd/cie` (6 imports)\n- `pkg/cozodb` (1 imports)",
+		"(3 packages in cycle)",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Contains(result.Text, "| `cmd/cie` |") {
+		t.Errorf("packages outside path should not be listed, got:\n%s", result.Text)
+	}
+}
+
+func TestPackageGraph_NoCycles(t *testing.T) {
+	t.Parallel()
+
+	result, err := PackageGraph(context.Background(), packageGraphMockClient(), PackageGraphArgs{Path: "cmd"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result.Text, "No import cycles.") {
+		t.Errorf("cycles not involving cmd/ should be omitted, got:\n%s", result.Text)
+	}
+}
+
+func TestPackageGraph_Empty(t *testing.T) {
+	t.Parallel()
+
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			return &QueryResult{}, nil
+		},
+	}
+	result, err := PackageGraph(context.Background(), client, PackageGraphArgs{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Text, "No packages found.") {
+		t.Errorf("expected empty message, got:\n%s", result.Text)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// maxReferenceRows caps the rows fetched per reference kind.
+const maxReferenceRows = 1000
+
+// FindReferencesArgs holds arguments for the find_references tool.
+type FindReferencesArgs struct {
+	// Name is the function, method or type to find references to: "Parse",
+	// "Parser.Parse", "QueryResult", or package-qualified "tools.QueryResult".
+	Name string
+
+	// PathPattern is an optional regex filtering the files containing references.
+	PathPattern string
+
+	// Limit is the maximum number of references listed per kind (default: 50).
+	Limit int
+}
+
+// referenceRow is one reference to the target: a call, a type use or an import.
+type referenceRow struct {
+	File   string
+	From   string // Referencing function or type, or the import path
+	Line   string
+	Kind   string // Type reference kind ("param", "field", ...), empty for calls and imports
+	Target string // Referenced function or type
+}
+
+// FindReferences lists every reference to a function or type, grouped by kind:
+//   - Calls: call sites from cie_calls
+//   - Type uses: parameters, return values, fields, literals, conversions and
+//     type assertions from cie_type_ref
+//   - Embeds: structs and interfaces embedding the type
+//   - Imports: files importing the package that defines the symbol (Go
+//     packages, PHP `use` of the class)
+func FindReferences(ctx context.Context, client Querier, args FindReferencesArgs) (*ToolResult, error) {
+	if args.Name == "" {
+		return NewError("Error: 'name' is required"), nil
+	}
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	fnCond, typeCond := referenceTargetConditions(args.Name)
+	pathCond := ""
+	if args.PathPattern != "" {
+		pathCond = fmt.Sprintf(", regex_matches(file, %q)", args.PathPattern)
+	}
+
+	defScript := fmt.Sprintf(`?[kind, name, file, line] := *cie_function { name, file_path: file, start_line: line }, kind = "function", %s
+?[kind, name, file, line] := *cie_type { name, kind, file_path: file, start_line: line }, %s
+:order file, line`,
+		fnCond("name", "file"), typeCond("name", "file"))
+	defResult, err := client.Query(ctx, defScript)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, defScript)), nil
+	}
+	if len(defResult.Rows) == 0 {
+		return NewResult(fmt.Sprintf("No function or type named `%s` found.\n\n"+
+			"**Tips:**\n"+
+			"- Use `Type.Method` for methods and `pkg.Name` to pick a package\n"+
+			"- Use **cie_find_function** or **cie_find_type** to search by partial name\n", args.Name)), nil
+	}
+
+	callScript := fmt.Sprintf(`?[file, from, line, target] := *cie_calls { caller_id, callee_id, call_line: line },
+  *cie_function { id: callee_id, name: target, file_path: target_file }, %s,
+  *cie_function { id: caller_id, name: from, file_path: file }%s
+:order file, line :limit %d`,
+		fnCond("target", "target_file"), pathCond, maxReferenceRows)
+	callResult, err := client.Query(ctx, callScript)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, callScript)), nil
+	}
+
+	var notes []string
+	refMatch := fmt.Sprintf(`*cie_type_ref { from_id, type_id, kind, file_path: file, line },
+  *cie_type { id: type_id, name: target, file_path: target_file }, %s`, typeCond("target", "target_file"))
+	refScript := fmt.Sprintf(`?[file, from, line, kind, target] := %s, *cie_function { id: from_id, name: from }%s
+?[file, from, line, kind, target] := %s, *cie_type { id: from_id, name: from }%s
+:order file, line :limit %d`,
+		refMatch, pathCond, refMatch, pathCond, maxReferenceRows)
+	refResult, err := client.Query(ctx, refScript)
+	if err != nil {
+		if !strings.Contains(err.Error(), "cie_type_ref") {
+			return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, refScript)), nil
+		}
+		notes = append(notes, "Type uses are unavailable: re-index the project (`cie index`) to record type references.")
+		refResult = &QueryResult{}
+	}
+
+	var imports []referenceRow
+	if importCond := referenceImportCondition(defResult.Rows); importCond != "" {
+		importScript := fmt.Sprintf(`?[file, import_path, line] := *cie_import { file_path: file, import_path, start_line: line }, %s%s
+:order file, line :limit %d`, importCond, pathCond, maxReferenceRows)
+		importResult, err := client.Query(ctx, importScript)
+		if err != nil {
+			return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, importScript)), nil
+		}
+		for _, row := range importResult.Rows {
+			if len(row) >= 3 {
+				imports = append(imports, referenceRow{File: AnyToString(row[0]), From: AnyToString(row[1]), Line: AnyToString(row[2])})
+			}
+		}
+	}
+
+	var calls, uses, embeds []referenceRow
+	for _, row := range callResult.Rows {
+		if len(row) >= 4 {
+			calls = append(calls, referenceRow{File: AnyToString(row[0]), From: AnyToString(row[1]), Line: AnyToString(row[2]), Target: AnyToString(row[3])})
+		}
+	}
+	for _, row := range refResult.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		ref := referenceRow{File: AnyToString(row[0]), From: AnyToString(row[1]), Line: AnyToString(row[2]), Kind: AnyToString(row[3]), Target: AnyToString(row[4])}
+		if ref.Kind == "embed" {
+			embeds = append(embeds, ref)
+		} else {
+			uses = append(uses, ref)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## References to `%s`\n\n", args.Name)
+	sb.WriteString("**Defined in:**\n")
+	targets := make(map[string]bool)
+	for _, row := range defResult.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		targets[AnyToString(row[1])] = true
+		fmt.Fprintf(&sb, "- %s `%s` — %s:%s\n", AnyToString(row[0]), AnyToString(row[1]), AnyToString(row[2]), AnyToString(row[3]))
+	}
+	showTarget := len(targets) > 1
+
+	total := len(calls) + len(uses) + len(embeds) + len(imports)
+	writeReferenceGroup(&sb, "Calls", calls, args.Limit, showTarget)
+	writeReferenceGroup(&sb, "Type uses", uses, args.Limit, showTarget)
+	writeReferenceGroup(&sb, "Embeds", embeds, args.Limit, showTarget)
+	writeReferenceGroup(&sb, "Imports", imports, args.Limit, false)
+	if total == 0 {
+		sb.WriteString("\nNo references found.\n")
+	}
+	for _, note := range notes {
+		fmt.Fprintf(&sb, "\n_Note: %s_\n", note)
+	}
+
+	return NewResult(sb.String()), nil
+}
+
+// referenceTargetConditions returns builders of the Datalog conditions matching
+// the target name in a function name column and in a type name column. A
+// "pkg.Name" target also matches Name declared in a directory named pkg.
+func referenceTargetConditions(name string) (fnCond, typeCond func(nameCol, fileCol string) string) {
+	qualifier, base, qualified := strings.Cut(name, ".")
+	if strings.Contains(base, ".") {
+		qualified = false // Type.Method or a namespaced name: match the name as written
+	}
+	pkgRe := "(^|/)" + EscapeRegex(qualifier) + "/[^/]+$"
+
+	fnCond = func(nameCol, fileCol string) string {
+		cond := fmt.Sprintf("%s = %q or ends_with(%s, %q)", nameCol, name, nameCol, "."+name)
+		if qualified {
+			cond += fmt.Sprintf(" or (%s = %q and regex_matches(%s, %q))", nameCol, base, fileCol, pkgRe)
+		}
+		return "(" + cond + ")"
+	}
+	typeCond = func(nameCol, fileCol string) string {
+		if qualified {
+			return fmt.Sprintf("(%s = %q or (%s = %q and regex_matches(%s, %q)))", nameCol, name, nameCol, base, fileCol, pkgRe)
+		}
+		return fmt.Sprintf("%s = %q", nameCol, name)
+	}
+	return fnCond, typeCond
+}
+
+// referenceImportCondition returns the Datalog condition matching imports of
+// the definitions: Go imports ending with the package directory and PHP `use`
+// statements naming the class. Returns "" when no import can name them.
+func referenceImportCondition(defs [][]any) string {
+	seen := make(map[string]bool)
+	var conds []string
+	for _, row := range defs {
+		if len(row) < 4 {
+			continue
+		}
+		name, file := AnyToString(row[1]), AnyToString(row[2])
+		var cond string
+		switch {
+		case strings.HasSuffix(file, ".go"):
+			if dir := path.Dir(file); dir != "." {
+				cond = fmt.Sprintf("ends_with(import_path, %q)", "/"+dir)
+			}
+		case strings.HasSuffix(file, ".php") && AnyToString(row[0]) != "function":
+			cond = fmt.Sprintf("ends_with(import_path, %q)", `\`+name)
+		}
+		if cond != "" && !seen[cond] {
+			seen[cond] = true
+			conds = append(conds, cond)
+		}
+	}
+	if len(conds) == 0 {
+		return ""
+	}
+	sort.Strings(conds)
+	return "(" + strings.Join(conds, " or ") + ")"
+}
+
+// writeReferenceGroup writes one kind of references, up to limit rows.
+func writeReferenceGroup(sb *strings.Builder, title string, refs []referenceRow, limit int, showTarget bool) {
+	if len(refs) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n### %s (%d)\n\n", title, len(refs))
+	for i, ref := range refs {
+		if i >= limit {
+			fmt.Fprintf(sb, "_... and %d more (increase `limit` or set `path_pattern`)_\n", len(refs)-limit)
+			break
+		}
+		var line string
+		switch {
+		case title == "Imports":
+			line = fmt.Sprintf("%s:%s — `%s`", ref.File, ref.Line, ref.From)
+		case ref.Kind != "":
+			line = fmt.Sprintf("`%s` (%s) — %s:%s", ref.From, ref.Kind, ref.File, ref.Line)
+		default:
+			line = fmt.Sprintf("`%s` — %s:%s", ref.From, ref.File, ref.Line)
+		}
+		if showTarget {
+			line += fmt.Sprintf(" → `%s`", ref.Target)
+		}
+		fmt.Fprintf(sb, "- %s\n", line)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// referencesMockClient serves the definition of tools.QueryResult, one call
+// to its constructor, type uses, an embedding and an import. typeRefErr is
+// returned for cie_type_ref queries.
+func referencesMockClient(typeRefErr error) (*MockCIEClient, *[]string) {
+	var scripts []string
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			scripts = append(scripts, script)
+			switch {
+			case strings.Contains(script, "*cie_calls"):
+				return &QueryResult{Rows: [][]any{
+					{"cmd/cie/mcp.go", "handleQuery", float64(88), "QueryResult"},
+				}}, nil
+			case strings.Contains(script, "*cie_type_ref"):
+				if typeRefErr != nil {
+					return nil, typeRefErr
+				}
+				return &QueryResult{Rows: [][]any{
+					{"pkg/tools/client.go", "Querier", float64(37), "return", "QueryResult"},
+					{"pkg/tools/search.go", "mergeQueryResults", float64(667), "param", "QueryResult"},
+					{"pkg/tools/result.go", "PagedResult", float64(12), "embed", "QueryResult"},
+				}}, nil
+			case strings.Contains(script, "*cie_import"):
+				return &QueryResult{Rows: [][]any{
+					{"cmd/cie/mcp.go", "github.com/kraklabs/cie/pkg/tools", float64(31)},
+				}}, nil
+			case strings.Contains(script, "*cie_type"):
+				return &QueryResult{Rows: [][]any{
+					{"struct", "QueryResult", "pkg/tools/client.go", float64(62)},
+				}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}, &scripts
+}
+
+func TestFindReferences(t *testing.T) {
+	t.Parallel()
+
+	client, scripts := referencesMockClient(nil)
+	result, err := FindReferences(context.Background(), client, FindReferencesArgs{Name: "tools.QueryResult"})
+	if err != nil {
+		t.Fatalf("FindReferences() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("FindReferences() returned error: %s", result.Text)
+	}
+
+	for _, want := range []string{
+		"## References to `tools.QueryResult`",
+		"- struct `QueryResult` — pkg/tools/client.go:62",
+		"### Calls (1)",
+		"- `handleQuery` — cmd/cie/mcp.go:88",
+		"### Type uses (2)",
+		"- `Querier` (return) — pkg/tools/client.go:37",
+		"- `mergeQueryResults` (param) — pkg/tools/search.go:667",
+		"### Embeds (1)",
+		"- `PagedResult` (embed) — pkg/tools/result.go:12",
+		"### Imports (1)",
+		"- cmd/cie/mcp.go:31 — `github.com/kraklabs/cie/pkg/tools`",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+
+	all := strings.Join(*scripts, "\n")
+	for _, want := range []string{
+		`regex_matches(target_file, "(^|/)tools/[^/]+$")`,
+		`ends_with(import_path, "/pkg/tools")`,
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("queries should contain %q, got:\n%s", want, all)
+		}
+	}
+}
+
+func TestFindReferences_OldIndex(t *testing.T) {
+	t.Parallel()
+
+	client, _ := referencesMockClient(errors.New("stored relation cie_type_ref not found"))
+	result, err := FindReferences(context.Background(), client, FindReferencesArgs{Name: "QueryResult"})
+	if err != nil {
+		t.Fatalf("FindReferences() error = %v", err)
+	}
+	if !strings.Contains(result.Text, "### Calls (1)") || !strings.Contains(result.Text, "re-index the project") {
+		t.Errorf("output should list calls and suggest re-indexing, got:\n%s", result.Text)
+	}
+	if strings.Contains(result.Text, "### Type uses") {
+		t.Errorf("output should not list type uses, got:\n%s", result.Text)
+	}
+}
+
+func TestFindReferences_NotFound(t *testing.T) {
+	t.Parallel()
+
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			return &QueryResult{}, nil
+		},
+	}
+	result, err := FindReferences(context.Background(), client, FindReferencesArgs{Name: "Missing"})
+	if err != nil {
+		t.Fatalf("FindReferences() error = %v", err)
+	}
+	if !strings.Contains(result.Text, "No function or type named `Missing` found") {
+		t.Errorf("unexpected output:\n%s", result.Text)
+	}
+
+	result, _ = FindReferences(context.Background(), client, FindReferencesArgs{})
+	if !result.IsError {
+		t.Error("FindReferences() without name should return an error")
+	}
+}
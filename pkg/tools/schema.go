@@ -55,6 +55,7 @@ Stores function/method metadata (lightweight, ~500 bytes/row).
 | end_line   | int    | Ending line number |
 | start_col  | int    | Starting column |
 | end_col    | int    | Ending column |
+| doc_comment | string | Leading doc comment, docstring or JSDoc (markers stripped, "" if none) |
 
 ### cie_function_code
 Stores function source code (JOIN with cie_function when needed).
@@ -82,6 +83,7 @@ Stores type/struct/interface metadata.
 | end_line   | int    | Ending line number |
 | start_col  | int    | Starting column |
 | end_col    | int    | Ending column |
+| doc_comment | string | Leading doc comment, docstring or JSDoc (markers stripped, "" if none) |
 
 ### cie_type_code
 Stores type source code.
@@ -97,6 +99,20 @@ Stores type embeddings for semantic search.
 | type_id  | string     | Type ID (foreign key) |
 | embedding| <F32; 1536> | Vector embedding |
 
+### cie_variable
+Package-level variables and constants (Go var/const, Python module-level assignments, exported JS/TS const/let/var).
+| Field      | Type   | Description |
+|------------|--------|-------------|
+| id         | string | Variable ID |
+| name       | string | Variable name |
+| kind       | string | var, const, let |
+| type       | string | Declared type ("" if inferred) |
+| value      | string | Initializer snippet (whitespace-collapsed, max 200 chars) |
+| file_path  | string | Source file |
+| code_text  | string | Declaration source |
+| start_line | int    | Start line |
+| end_line   | int    | End line |
+
 ## Edge Tables
 
 ### cie_defines
@@ -121,6 +137,136 @@ Function call relationships.
 | callee_id | string | ID of called function |
 | call_line | int    | Line number where the call occurs in the caller (0 = unknown) |
 
+### cie_type_ref
+Type references from Go functions and types.
+| Field     | Type   | Description |
+|-----------|--------|-------------|
+| id        | string | Reference ID |
+| from_id   | string | ID of the referencing function or type |
+| type_id   | string | ID of the referenced type |
+| kind      | string | param, return, receiver, field, embed, literal, conversion, assertion, use |
+| file_path | string | File containing the reference |
+| line      | int    | Line number of the reference |
+
+### cie_type_param
+Type parameters of generic Go functions and types.
+| Field      | Type   | Description |
+|------------|--------|-------------|
+| id         | string | Type parameter ID |
+| owner_id   | string | ID of the generic function or type |
+| name       | string | Parameter name (e.g., K) |
+| constraint | string | Constraint as written (e.g., comparable, ~int \| ~float64) |
+| position   | int    | 0-based position in the type parameter list |
+| file_path  | string | File declaring the owner |
+
+### cie_instantiation
+Explicit instantiations of generic Go functions and types (Map[int, string](...), List[int]).
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| id          | string | Instantiation ID |
+| from_id     | string | ID of the instantiating function or type |
+| target_id   | string | ID of the generic function or type |
+| target_kind | string | function or type |
+| type_args   | string | Type arguments as written (e.g., int, string) |
+| file_path   | string | File containing the instantiation |
+| line        | int    | Line number of the instantiation |
+
+### cie_func_ref
+Functions used as values rather than called (Go callbacks, handler registration).
+| Field     | Type   | Description |
+|-----------|--------|-------------|
+| id        | string | Reference ID |
+| from_id   | string | ID of the referencing function |
+| to_id     | string | ID of the referenced function |
+| kind      | string | argument, assignment, return |
+| file_path | string | File containing the reference |
+| line      | int    | Line number of the reference |
+
+### cie_concurrency
+Goroutine spawns, channel operations and lock acquisitions of Go functions.
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| id          | string | Operation ID |
+| function_id | string | ID of the function performing the operation |
+| kind        | string | spawn, send, receive, lock, rlock, unlock, runlock (deferred unlocks are not recorded) |
+| target      | string | Spawned callee, channel or mutex; fields of the receiver or a parameter are named by type (Pool.mu) |
+| target_id   | string | For spawns of a function in the same file: its ID (else resolve through cie_calls on the line) |
+| file_path   | string | File containing the operation |
+| line        | int    | Line number of the operation |
+
+### cie_error_site
+Error construction, wrapping and raising sites.
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| id          | string | Site ID |
+| function_id | string | ID of the enclosing function (empty for sentinels) |
+| kind        | string | new, errorf, wrap, sentinel, return, custom, type (Go); raise (Python); throw (JS/TS) |
+| name        | string | Sentinel, error type or exception class (ErrNotFound, ValueError); for wrap, the wrapped sentinel if any |
+| message     | string | Message or format string as written |
+| file_path   | string | File containing the site |
+| line        | int    | Line number of the site |
+
+### cie_test
+Tests, subtests and test suites.
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| id          | string | Test ID |
+| function_id | string | ID of the function running the test (empty for pytest classes and unindexed callbacks) |
+| name        | string | Test name as the runner reports it (TestParse/empty_input, TestStore.test_get, "returns the user") |
+| kind        | string | test, benchmark, fuzz, example, subtest, suite |
+| suite       | string | Parent test, testify suite, pytest class or describe path ("api > users") |
+| framework   | string | go, pytest, jest, vitest |
+| file_path   | string | File containing the test |
+| line        | int    | Line number of the test |
+
+### cie_function_metrics
+Complexity and size metrics of functions, computed from the syntax tree at indexing.
+| Field         | Type   | Description |
+|---------------|--------|-------------|
+| function_id   | string | Function ID (key) |
+| file_path     | string | File containing the function |
+| complexity    | int    | Cyclomatic complexity: 1 + if, loops, cases, catch clauses, conditional expressions and boolean operators |
+| max_nesting   | int    | Deepest nesting of control structures (else-if chains count once) |
+| params        | int    | Declared parameters, without receiver or self/cls |
+| loc           | int    | Non-blank lines |
+| comment_lines | int    | Non-blank lines holding a comment or docstring |
+| comment_ratio | float  | comment_lines / loc |
+
+### cie_coverage
+Line coverage of functions, imported with ` + "`cie coverage import`" + `.
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| function_id | string | Function ID (key) |
+| file_path   | string | File containing the function |
+| covered     | int    | Statements executed at least once (lines for coverage.py and lcov) |
+| total       | int    | Statements instrumented in the function |
+| percent     | float  | covered / total, in percent |
+| uncovered   | string | Uncovered line ranges ("12-14, 20") |
+| source      | string | Profile file name |
+| imported_at | int    | Unix time of the import |
+
+### cie_function_churn
+Change history of functions over the commits walked by ` + "`cie history`" + `.
+| Field         | Type   | Description |
+|---------------|--------|-------------|
+| function_id   | string | Function ID (key) |
+| file_path     | string | File containing the function |
+| commits       | int    | Commits changing the function |
+| authors       | int    | Distinct authors of those commits |
+| lines_changed | int    | Lines of the function touched, summed over commits |
+| first_changed | int    | Unix time of the oldest commit walked changing the function |
+| last_changed  | int    | Unix time of the newest commit changing the function |
+
+### cie_change_coupling
+Functions changed in the same commits, from ` + "`cie history`" + `. Each pair is stored in both directions.
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| function_id | string | Function ID (key) |
+| coupled_id  | string | Function changed together with it (key) |
+| file_path   | string | File containing function_id |
+| co_changes  | int    | Commits changing both functions |
+| degree      | float  | co_changes / commits changing function_id |
+
 ### cie_import
 Import statements.
 | Field       | Type   | Description |
@@ -131,6 +277,188 @@ Import statements.
 | alias       | string | Import alias (if any) |
 | start_line  | int    | Line number |
 
+## SQL Schema Tables
+
+Populated from .sql files (migrations, schema dumps). Table names are lowercase without schema prefix.
+
+### cie_sql_table
+| Field      | Type   | Description |
+|------------|--------|-------------|
+| id         | string | Table ID |
+| name       | string | Table name |
+| file_path  | string | File containing CREATE TABLE |
+| start_line | int    | Starting line number |
+| end_line   | int    | Ending line number |
+| code_text  | string | CREATE TABLE statement |
+
+### cie_sql_column
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| table_name  | string | Owning table |
+| name        | string | Column name |
+| data_type   | string | Declared type (e.g., BIGINT, VARCHAR(255)) |
+| nullable    | bool   | False for NOT NULL and primary key columns |
+| primary_key | bool   | Part of the primary key |
+| file_path   | string | Defining file |
+| line        | int    | Line number |
+
+### cie_sql_index
+| Field      | Type   | Description |
+|------------|--------|-------------|
+| name       | string | Index name (empty if unnamed) |
+| table_name | string | Indexed table |
+| columns    | string | Comma-separated column list |
+| is_unique  | bool   | UNIQUE index or constraint |
+
+### cie_sql_foreign_key
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| table_name  | string | Referencing table |
+| columns     | string | Comma-separated referencing columns |
+| ref_table   | string | Referenced table |
+| ref_columns | string | Comma-separated referenced columns (empty = primary key) |
+
+### cie_table_access
+Functions that read or write a table (from SQL literals in DB calls and ORM table selectors).
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| function_id | string | Accessing function ID |
+| table_name  | string | Accessed table |
+| access      | string | "read" or "write" |
+| file_path   | string | File containing the function |
+| line        | int    | Line of the query |
+
+## GraphQL Tables
+
+Populated from .graphql/.graphqls SDL files. GraphQL types are stored in cie_type with kinds graphql_object, graphql_interface, graphql_input, graphql_enum, graphql_union and graphql_scalar.
+
+### cie_graphql_field
+| Field      | Type   | Description |
+|------------|--------|-------------|
+| id         | string | Field ID |
+| type_name  | string | Owning type (e.g., Query, Order) |
+| name       | string | Field name |
+| field_type | string | Return type (e.g., [Order!]!) |
+| arguments  | string | Argument list without parentheses |
+| operation  | string | "query", "mutation", "subscription", or "" for non-root types |
+| file_path  | string | SDL file |
+| line       | int    | Line number |
+
+### cie_graphql_resolver
+Functions resolving a GraphQL field (gqlgen resolver methods, Apollo resolver maps).
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| type_name   | string | GraphQL type |
+| field_name  | string | GraphQL field |
+| function_id | string | Resolver function ID |
+| framework   | string | "gqlgen" or "apollo" |
+| file_path   | string | File containing the resolver binding |
+| line        | int    | Line of the binding |
+
+## Infrastructure Topology Tables
+
+Populated from Dockerfiles, docker-compose files, Kubernetes manifests and Terraform (.tf).
+
+### cie_topology_node
+| Field     | Type   | Description |
+|-----------|--------|-------------|
+| id        | string | Node ID |
+| kind      | string | dockerfile, compose_service, k8s_deployment (k8s_ + lowercase kind), k8s_service, terraform_resource |
+| name      | string | Service/resource name (e.g., worker, aws_ecs_service.worker) |
+| image     | string | Image run (Dockerfile: final base image) |
+| command   | string | Entrypoint and arguments |
+| binary    | string | Go package (cmd/worker) or program name started by the command |
+| ports     | string | Comma-separated ports (Service: port:targetPort) |
+| labels    | string | Pod labels; for Services, the selector |
+| file_path | string | Declaring file |
+| line      | int    | Line number |
+
+### cie_topology_env
+| Field     | Type   | Description |
+|-----------|--------|-------------|
+| node_id   | string | Topology node ID |
+| name      | string | Variable name ("*" for envFrom/env_file) |
+| value     | string | Literal value or source (secret:db/url, configmap:app) |
+| file_path | string | Declaring file |
+| line      | int    | Line number |
+
+### cie_topology_edge
+| Field     | Type   | Description |
+|-----------|--------|-------------|
+| from_id   | string | Source node ID |
+| to_id     | string | Target node ID, or main function ID for runs |
+| kind      | string | runs, builds, image, exposes, depends_on |
+| file_path | string | Declaring file |
+| line      | int    | Line number |
+
+## Documentation Tables
+
+Populated from Markdown files (README.md, ADRs, docs/*.md), split at each heading.
+
+### cie_doc_chunk
+| Field      | Type   | Description |
+|------------|--------|-------------|
+| id         | string | Chunk ID |
+| file_path  | string | Markdown file |
+| heading    | string | Heading text ("" for text before the first heading) |
+| anchor     | string | GitHub-style anchor (docs/architecture.md#ingestion) |
+| level      | int    | Heading level (1-6, 0 before the first heading) |
+| start_line | int    | First line |
+| end_line   | int    | Last line |
+| text       | string | Section text |
+
+### cie_doc_chunk_embedding
+Stores doc chunk embeddings for semantic search (HNSW index here).
+| Field     | Type        | Description |
+|-----------|-------------|-------------|
+| chunk_id  | string      | Doc chunk ID (foreign key) |
+| embedding | <F32; 1536> | Vector embedding |
+
+### cie_doc_link
+Code mentioned in a doc chunk (backticked identifiers and file paths, relative links).
+| Field       | Type   | Description |
+|-------------|--------|-------------|
+| chunk_id    | string | Doc chunk ID |
+| target_id   | string | Function, type or file ID |
+| target_kind | string | function, type, file |
+| symbol      | string | Mention as written (Parser.ParseFile, pkg/ingestion/parser.go) |
+| file_path   | string | Markdown file |
+| line        | int    | Line of the mention |
+
+### cie_package
+A directory of source files (Go package, PHP namespace directory, Python package, JS/TS module directory).
+| Field      | Type   | Description |
+|------------|--------|-------------|
+| id         | string | Package ID |
+| path       | string | Directory relative to the repository root ("." for the root) |
+| name       | string | Go package name or PHP namespace, else the directory name |
+| language   | string | Language of most files |
+| file_count | int    | Number of source files |
+| doc        | string | Package doc comment ("" if none) |
+
+### cie_package_import
+Package -> package imports, aggregated from cie_import. External imports are not included.
+| Field        | Type   | Description |
+|--------------|--------|-------------|
+| id           | string | Edge ID |
+| from_path    | string | Importing package path |
+| to_path      | string | Imported package path |
+| import_count | int    | Import statements behind the edge |
+
+## Snapshot Tables
+
+Built by cie index --ref for past commits. Each table above, except the embedding, coverage, git history and project metadata tables, has a history table cie_hist_<name> with the same fields plus a ` + "`valid_at`" + ` Validity key. Read a snapshot with ` + "`*cie_hist_function { id, name @ 3 }`" + `, or pass as_of to the tools, which rewrites the query and rejects it if it reads a table without history.
+
+### cie_snapshot
+| Field      | Type   | Description |
+|------------|--------|-------------|
+| seq        | int    | Snapshot number, the validity of its rows |
+| sha        | string | Commit SHA |
+| ref        | string | Ref given to cie index --ref |
+| indexed_at | int    | Unix time the snapshot was built |
+| files      | int    | Files in the snapshot |
+| functions  | int    | Functions in the snapshot |
+
 ## CozoScript Operators
 
 ### String Operations
@@ -200,7 +528,7 @@ Import statements.
 4. **No LIKE operator**: Use regex_matches() instead
 5. **No CONTAINS**: Use regex_matches() with pattern
 6. **Limit results**: Always use :limit N for large result sets
-7. **HNSW indices**: Located on cie_function_embedding:embedding_idx and cie_type_embedding:embedding_idx
+7. **HNSW indices**: Located on cie_function_embedding:embedding_idx, cie_type_embedding:embedding_idx and cie_doc_chunk_embedding:embedding_idx
 
 ---
 
@@ -215,6 +543,7 @@ Import statements.
 | ` + "`cie_semantic_search`" + ` | Natural language search | ` + "`query`" + `, ` + "`min_similarity`" + ` |
 | ` + "`cie_find_function`" + ` | Find by function name | ` + "`name`" + `, ` + "`include_code`" + ` |
 | ` + "`cie_find_type`" + ` | Find structs/interfaces | ` + "`name`" + `, ` + "`kind`" + ` |
+| ` + "`cie_find_variable`" + ` | Find global vars/consts | ` + "`name`" + `, ` + "`kind`" + ` |
 
 ### Analysis Tools
 
@@ -222,8 +551,12 @@ Import statements.
 |------|----------|----------------|
 | ` + "`cie_analyze`" + ` | Architecture questions | ` + "`question`" + ` (natural language) |
 | ` + "`cie_list_endpoints`" + ` | HTTP API routes | ` + "`path_pattern`" + `, ` + "`method`" + ` |
+| ` + "`cie_find_table_usage`" + ` | Who reads/writes a DB table? | ` + "`table`" + `, ` + "`access`" + ` |
+| ` + "`cie_list_graphql_operations`" + ` | GraphQL queries/mutations and resolvers | ` + "`operation`" + `, ` + "`type_name`" + ` |
+| ` + "`cie_deployment_topology`" + ` | What deploys a binary, its env, its Service? | ` + "`target`" + ` |
 | ` + "`cie_find_callers`" + ` | Who calls this function? | ` + "`function_name`" + ` |
 | ` + "`cie_find_callees`" + ` | What does this call? | ` + "`function_name`" + ` |
+| ` + "`cie_find_references`" + ` | Every reference to a function/type | ` + "`name`" + ` |
 | ` + "`cie_trace_path`" + ` | Call path from A to B | ` + "`target`" + `, ` + "`source`" + ` |
 | ` + "`cie_find_implementations`" + ` | Interface implementations | ` + "`interface_name`" + ` |
 
@@ -240,6 +573,17 @@ Import statements.
 |------|----------|----------------|
 | ` + "`cie_list_files`" + ` | Browse indexed files | ` + "`path_pattern`" + `, ` + "`language`" + ` |
 | ` + "`cie_directory_summary`" + ` | Module overview | ` + "`path`" + ` |
+| ` + "`cie_package_graph`" + ` | Package coupling, import cycles | ` + "`path`" + ` |
+| ` + "`cie_concurrency_map`" + ` | Goroutines, channels, lock order | ` + "`function`" + `, ` + "`path`" + ` |
+| ` + "`cie_trace_error`" + ` | Error origins and propagation | ` + "`query`" + `, ` + "`max_depth`" + ` |
+| ` + "`cie_find_tests`" + ` | Tests to run after an edit | ` + "`function`" + `, ` + "`max_depth`" + ` |
+| ` + "`cie_find_untested`" + ` | Exported functions without tests | ` + "`path`" + `, ` + "`limit`" + ` |
+| ` + "`cie_coverage_gaps`" + ` | Least-covered code on a call path | ` + "`function`" + `, ` + "`target`" + ` |
+| ` + "`cie_hotspots`" + ` | Complex functions many callers depend on | ` + "`path`" + `, ` + "`sort_by`" + ` |
+| ` + "`cie_change_coupling`" + ` | Functions changed in the same commits | ` + "`function`" + `, ` + "`min_degree`" + ` |
+| ` + "`cie_impact`" + ` | What a diff could break | ` + "`base`" + `, ` + "`head`" + `, ` + "`diff`" + ` |
+| ` + "`cie_semantic_diff`" + ` | Functions a diff adds, renames or changes | ` + "`base`" + `, ` + "`head`" + ` |
+| ` + "`cie_find_dead_code`" + ` | Functions and types nothing reaches | ` + "`path`" + `, ` + "`roots`" + `, ` + "`min_confidence`" + ` |
 | ` + "`cie_get_file_summary`" + ` | File contents summary | ` + "`file_path`" + ` |
 | ` + "`cie_index_status`" + ` | Check indexing health | ` + "`path_pattern`" + ` |
 
@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/kraklabs/cie/pkg/sigparse"
@@ -133,6 +134,7 @@ type FindFunctionArgs struct {
 	Name        string
 	ExactMatch  bool
 	IncludeCode bool
+	AsOf        bool // client reads a snapshot: HEAD coverage is left out
 }
 
 // FindFunction finds functions by name.
@@ -155,9 +157,9 @@ func FindFunction(ctx context.Context, client Querier, args FindFunctionArgs) (*
 	// Schema v3: Join with cie_function_code only when include_code is true
 	var script string
 	if args.IncludeCode {
-		script = fmt.Sprintf("?[file_path, name, signature, start_line, end_line, code_text] := *cie_function { id, file_path, name, signature, start_line, end_line }, *cie_function_code { function_id: id, code_text }, %s", condition)
+		script = fmt.Sprintf("?[file_path, name, signature, start_line, end_line, doc_comment, code_text] := *cie_function { id, file_path, name, signature, start_line, end_line, doc_comment }, *cie_function_code { function_id: id, code_text }, %s", condition)
 	} else {
-		script = fmt.Sprintf("?[file_path, name, signature, start_line, end_line] := *cie_function { file_path, name, signature, start_line, end_line }, %s", condition)
+		script = fmt.Sprintf("?[file_path, name, signature, start_line, end_line, doc_comment] := *cie_function { file_path, name, signature, start_line, end_line, doc_comment }, %s", condition)
 	}
 
 	result, err := client.Query(ctx, script)
@@ -183,13 +185,40 @@ func FindFunction(ctx context.Context, client Querier, args FindFunctionArgs) (*
 		}
 	}
 
-	return NewResult(FormatQueryResult(result, script)), nil
+	output := FormatQueryResult(result, script)
+	if len(result.Rows) > 0 {
+		output += formatFunctionDocs(queryFunctionDocs(ctx, client, condition))
+		if !args.AsOf {
+			output += formatFunctionCoverage(queryFunctionCoverage(ctx, client, condition))
+		}
+	}
+	return NewResult(output), nil
+}
+
+// formatFunctionDocs lists the doc chunks mentioning each function, or "" if none do.
+func formatFunctionDocs(docs map[string][]string) string {
+	if len(docs) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("\n\n**Documented in**:\n")
+	for _, name := range names {
+		fmt.Fprintf(&sb, "- `%s`: %s\n", name, strings.Join(docs[name], ", "))
+	}
+	return sb.String()
 }
 
 // FindCallersArgs holds arguments for finding callers.
 type FindCallersArgs struct {
 	FunctionName    string
 	IncludeIndirect bool
+	IncludeRefs     bool // Also list functions that use it as a value (callbacks, handler registration)
 }
 
 // FindCallers finds all functions that call a specific function.
@@ -250,6 +279,23 @@ func FindCallers(ctx context.Context, client Querier, args FindCallersArgs) (*To
 		}
 	}
 
+	// Functions that pass, assign or return it as a function value:
+	// r.GET("/users", h.ListUsers) registers ListUsers without calling it.
+	if args.IncludeRefs {
+		refScript := fmt.Sprintf(`?[caller_file, caller_name, caller_line, callee_name, call_line] :=
+  *cie_func_ref { from_id, to_id, kind, line: call_line },
+  *cie_function { id: to_id, name: to_name },
+  *cie_function { id: from_id, file_path: caller_file, name: caller_name, start_line: caller_line },
+  not regex_matches(caller_file, "_test[.]go$"),
+  (to_name = %q or ends_with(to_name, %q)),
+  callee_name = concat(to_name, " (", kind, " reference)")`, args.FunctionName, "."+args.FunctionName)
+
+		refResult, refErr := client.Query(ctx, refScript)
+		if refErr == nil && len(refResult.Rows) > 0 {
+			result = mergeQueryResults(result, refResult)
+		}
+	}
+
 	// BFS expansion for transitive callers
 	if args.IncludeIndirect {
 		result = expandCallersIndirect(ctx, client, result)
@@ -891,6 +937,7 @@ func containsCaseInsensitive(s, substr string) bool {
 // RawQueryArgs holds arguments for raw queries.
 type RawQueryArgs struct {
 	Script string
+	AsOf   bool // client reads a snapshot: relations without history are rejected
 }
 
 // RawQuery executes a raw CozoScript query.
@@ -898,6 +945,11 @@ func RawQuery(ctx context.Context, client Querier, args RawQueryArgs) (*ToolResu
 	if args.Script == "" {
 		return NewError("Error: 'script' is required"), nil
 	}
+	if args.AsOf {
+		if head := HeadRelations(args.Script); len(head) > 0 {
+			return NewError(fmt.Sprintf("Error: snapshots keep no history of %s; query without as_of to read HEAD", strings.Join(head, ", "))), nil
+		}
+	}
 
 	result, err := client.Query(ctx, args.Script)
 	if err != nil {
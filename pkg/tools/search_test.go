@@ -1031,6 +1031,39 @@ func TestFindCallers_ExcludesTestFiles(t *testing.T) {
 	assertContains(t, capturedScript, `_test[.]go`)
 }
 
+// Test FindCallers with include_refs=true (functions registering it as a handler)
+func TestFindCallers_IncludeRefs(t *testing.T) {
+	client := NewMockClientCustom(
+		func(ctx context.Context, script string) (*QueryResult, error) {
+			if strings.Contains(script, "cie_func_ref") {
+				return &QueryResult{
+					Headers: []string{"caller_file", "caller_name", "caller_line", "callee_name", "call_line"},
+					Rows: [][]any{
+						{"internal/server/routes.go", "Server.Register", 20, "Handler.ListUsers (argument reference)", 22},
+					},
+				}, nil
+			}
+			return &QueryResult{
+				Headers: []string{"caller_file", "caller_name", "caller_line", "callee_name", "call_line"},
+				Rows:    [][]any{},
+			}, nil
+		},
+		nil,
+	)
+	ctx := setupTest(t)
+
+	// Without include_refs — the registration is not a call
+	result, err := FindCallers(ctx, client, FindCallersArgs{FunctionName: "ListUsers"})
+	assertNoError(t, err)
+	assertNotContains(t, result.Text, "Server.Register")
+
+	// With include_refs — the registering function is listed with the reference kind
+	result, err = FindCallers(ctx, client, FindCallersArgs{FunctionName: "ListUsers", IncludeRefs: true})
+	assertNoError(t, err)
+	assertContains(t, result.Text, "Server.Register")
+	assertContains(t, result.Text, "argument reference")
+}
+
 // Test that test file callees are excluded from FindCallees Phase 1
 func TestFindCallees_ExcludesTestFiles(t *testing.T) {
 	var phase1Script string
@@ -40,6 +40,7 @@ type SemanticSearchArgs struct {
 	ExcludePaths     string  // Optional regex to exclude additional paths (e.g., "metrics|dlq|telemetry")
 	ExcludeAnonymous bool    // Exclude anonymous/arrow functions (default: true when not specified)
 	MinSimilarity    float64 // Minimum similarity threshold (0.0-1.0, e.g., 0.5 = 50%)
+	IncludeDocs      bool    // Also return matching Markdown doc chunks after the code results
 	EmbeddingURL     string
 	EmbeddingModel   string
 }
@@ -106,7 +107,13 @@ func SemanticSearch(ctx context.Context, client Querier, args SemanticSearchArgs
 	if len(result.Rows) > args.Limit {
 		result.Rows = result.Rows[:args.Limit]
 	}
-	return NewResult(formatSemanticResults(result.Rows, args)), nil
+	output := formatSemanticResults(result.Rows, args)
+	if args.IncludeDocs {
+		if docRows, err := searchDocChunks(ctx, client, embedding, args); err == nil && len(docRows) > 0 {
+			output += formatDocResults(docRows)
+		}
+	}
+	return NewResult(output), nil
 }
 
 func normalizeSemanticArgs(args SemanticSearchArgs) SemanticSearchArgs {
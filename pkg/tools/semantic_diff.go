@@ -0,0 +1,170 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSemanticDiffListed caps the symbol changes and call edges listed per
+// section of cie_semantic_diff.
+const maxSemanticDiffListed = 200
+
+// Kinds of symbol changes reported by a semantic diff.
+const (
+	SymbolAdded            = "added"
+	SymbolRemoved          = "removed"
+	SymbolRenamed          = "renamed"
+	SymbolMoved            = "moved"
+	SymbolSignatureChanged = "signature_changed"
+	SymbolBodyChanged      = "body_changed"
+)
+
+// SymbolChange is a function or method changed between two refs. Renamed and
+// moved functions keep their body: they are matched by body hash, ignoring
+// the name in the declaration.
+type SymbolChange struct {
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`                    // Name at head; the removed name for removals
+	FilePath     string `json:"file_path"`               // File at head; the removed file for removals
+	Line         int    `json:"line"`                    // Start line at head; at base for removals
+	Signature    string `json:"signature,omitempty"`     // Signature at head; at base for removals
+	OldName      string `json:"old_name,omitempty"`      // Name at base, for renames
+	OldFilePath  string `json:"old_file_path,omitempty"` // File at base, when it differs
+	OldSignature string `json:"old_signature,omitempty"` // Signature at base, when it differs
+}
+
+// CallEdgeChange is a call added or removed between two refs. Callees
+// outside the caller's file are named as written in the call ("pkg.Foo",
+// "s.store.Get").
+type CallEdgeChange struct {
+	Caller   string `json:"caller"`
+	Callee   string `json:"callee"`
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line,omitempty"` // Line of the call at head; at base for removed calls
+}
+
+// SemanticDiffReport is the symbol-level summary of the changes between two
+// refs, built by parsing the changed files at both refs.
+type SemanticDiffReport struct {
+	Base         string           `json:"base"`
+	Head         string           `json:"head"` // "" for the working tree
+	FilesChanged int              `json:"files_changed"`
+	FilesParsed  int              `json:"files_parsed"` // Changed files in a language functions are extracted from
+	Changes      []SymbolChange   `json:"changes"`
+	AddedCalls   []CallEdgeChange `json:"added_calls"`
+	RemovedCalls []CallEdgeChange `json:"removed_calls"`
+}
+
+// Count returns the number of symbol changes of the given kind.
+func (r *SemanticDiffReport) Count(kind string) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// semanticDiffSections lists the kinds of symbol changes in report order.
+var semanticDiffSections = []struct{ kind, title string }{
+	{SymbolAdded, "Added"},
+	{SymbolRemoved, "Removed"},
+	{SymbolRenamed, "Renamed"},
+	{SymbolMoved, "Moved"},
+	{SymbolSignatureChanged, "Signature changed"},
+	{SymbolBodyChanged, "Body changed"},
+}
+
+// SemanticDiff formats a semantic diff report as markdown: the functions
+// added, removed, renamed, moved, with a new signature or a new body, then
+// the call edges added and removed.
+func SemanticDiff(report *SemanticDiffReport) *ToolResult {
+	head := report.Head
+	if head == "" {
+		head = "working tree"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Semantic diff %s..%s\n\n", report.Base, head)
+	fmt.Fprintf(&sb, "%d files changed, %d parsed: %d functions changed, %d calls added, %d calls removed.\n",
+		report.FilesChanged, report.FilesParsed, len(report.Changes), len(report.AddedCalls), len(report.RemovedCalls))
+	if len(report.Changes) == 0 && len(report.AddedCalls) == 0 && len(report.RemovedCalls) == 0 {
+		sb.WriteString("\nNo function changed: the diff only touches comments, declarations outside functions or files in languages functions are not extracted from.\n")
+		return NewResult(sb.String())
+	}
+
+	for _, section := range semanticDiffSections {
+		var changes []SymbolChange
+		for _, c := range report.Changes {
+			if c.Kind == section.kind {
+				changes = append(changes, c)
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n### %s (%d)\n\n", section.title, len(changes))
+		for i, c := range changes {
+			if i == maxSemanticDiffListed {
+				fmt.Fprintf(&sb, "- … %d more\n", len(changes)-i)
+				break
+			}
+			sb.WriteString(formatSymbolChange(c))
+		}
+	}
+	writeCallEdges(&sb, "Calls added", report.AddedCalls)
+	writeCallEdges(&sb, "Calls removed", report.RemovedCalls)
+	return NewResult(sb.String())
+}
+
+// formatSymbolChange formats a symbol change as a markdown list item.
+func formatSymbolChange(c SymbolChange) string {
+	switch c.Kind {
+	case SymbolRenamed:
+		from := c.OldName
+		if c.OldFilePath != "" {
+			from = fmt.Sprintf("%s (%s)", c.OldName, c.OldFilePath)
+		}
+		return fmt.Sprintf("- `%s` → `%s` (%s:%d)\n", from, c.Name, c.FilePath, c.Line)
+	case SymbolMoved:
+		return fmt.Sprintf("- `%s`: %s → %s:%d\n", c.Name, c.OldFilePath, c.FilePath, c.Line)
+	case SymbolSignatureChanged:
+		return fmt.Sprintf("- `%s` (%s:%d)\n  - before: `%s`\n  - after: `%s`\n", c.Name, c.FilePath, c.Line, c.OldSignature, c.Signature)
+	default:
+		return fmt.Sprintf("- `%s` (%s:%d)\n", c.Name, c.FilePath, c.Line)
+	}
+}
+
+// writeCallEdges writes a section listing call edges.
+func writeCallEdges(sb *strings.Builder, title string, edges []CallEdgeChange) {
+	if len(edges) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n### %s (%d)\n\n", title, len(edges))
+	for i, e := range edges {
+		if i == maxSemanticDiffListed {
+			fmt.Fprintf(sb, "- … %d more\n", len(edges)-i)
+			break
+		}
+		fmt.Fprintf(sb, "- `%s` → `%s` (%s:%d)\n", e.Caller, e.Callee, e.FilePath, e.Line)
+	}
+}
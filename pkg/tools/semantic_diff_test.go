@@ -0,0 +1,68 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSemanticDiff(t *testing.T) {
+	report := &SemanticDiffReport{
+		Base: "main", Head: "HEAD", FilesChanged: 4, FilesParsed: 3,
+		Changes: []SymbolChange{
+			{Kind: SymbolAdded, Name: "warm", FilePath: "cache.go", Line: 3},
+			{Kind: SymbolMoved, Name: "clean", FilePath: "helpers.go", Line: 3, OldFilePath: "util.go"},
+			{Kind: SymbolSignatureChanged, Name: "Put", FilePath: "store.go", Line: 8, Signature: "func Put(id, value string)", OldSignature: "func Put(id string)"},
+			{Kind: SymbolRenamed, Name: "fetch", FilePath: "store.go", Line: 12, OldName: "scan"},
+			{Kind: SymbolRemoved, Name: "legacy", FilePath: "store.go", Line: 11},
+		},
+		AddedCalls:   []CallEdgeChange{{Caller: "warm", Callee: "Get", FilePath: "cache.go", Line: 4}},
+		RemovedCalls: []CallEdgeChange{{Caller: "Get", Callee: "cache.Load", FilePath: "store.go", Line: 5}},
+	}
+	if n := report.Count(SymbolRenamed); n != 1 {
+		t.Errorf("Count(renamed) = %d, want 1", n)
+	}
+
+	text := SemanticDiff(report).Text
+	for _, want := range []string{
+		"## Semantic diff main..HEAD",
+		"4 files changed, 3 parsed: 5 functions changed, 1 calls added, 1 calls removed.",
+		"### Added (1)\n\n- `warm` (cache.go:3)",
+		"### Removed (1)\n\n- `legacy` (store.go:11)",
+		"- `scan` → `fetch` (store.go:12)",
+		"- `clean`: util.go → helpers.go:3",
+		"- `Put` (store.go:8)\n  - before: `func Put(id string)`\n  - after: `func Put(id, value string)`",
+		"### Calls added (1)\n\n- `warm` → `Get` (cache.go:4)",
+		"### Calls removed (1)\n\n- `Get` → `cache.Load` (store.go:5)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Body changed") {
+		t.Errorf("empty section listed:\n%s", text)
+	}
+
+	empty := SemanticDiff(&SemanticDiffReport{Base: "main", FilesChanged: 1}).Text
+	if !strings.Contains(empty, "## Semantic diff main..working tree") || !strings.Contains(empty, "No function changed") {
+		t.Errorf("empty diff: %s", empty)
+	}
+}
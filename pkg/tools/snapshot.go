@@ -0,0 +1,257 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kraklabs/cie/pkg/storage"
+)
+
+// Snapshot is the index of the project at a past commit, built by
+// cie index --ref. Its rows are the rows of the history relations valid at
+// Seq.
+type Snapshot struct {
+	Seq       int       `json:"seq"`
+	SHA       string    `json:"sha"`
+	Ref       string    `json:"ref"` // Ref as given to cie index --ref
+	IndexedAt time.Time `json:"indexed_at"`
+	Files     int       `json:"files"`
+	Functions int       `json:"functions"`
+}
+
+// Label returns the short commit and the ref it was built from.
+func (s Snapshot) Label() string {
+	short := s.SHA[:min(12, len(s.SHA))]
+	if s.Ref == "" || strings.HasPrefix(s.SHA, s.Ref) {
+		return short
+	}
+	return fmt.Sprintf("%s (%s)", short, s.Ref)
+}
+
+// ListSnapshots returns the snapshots of the index, oldest first.
+func ListSnapshots(ctx context.Context, client Querier) ([]Snapshot, error) {
+	result, err := client.Query(ctx, `?[seq, sha, ref, indexed_at, files, functions] := *cie_snapshot { seq, sha, ref, indexed_at, files, functions } :order seq`)
+	if err != nil {
+		return nil, fmt.Errorf("list snapshots: %w", err)
+	}
+	snapshots := make([]Snapshot, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 6 {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{
+			Seq:       rowInt(row[0]),
+			SHA:       AnyToString(row[1]),
+			Ref:       AnyToString(row[2]),
+			IndexedAt: time.Unix(int64(rowInt(row[3])), 0),
+			Files:     rowInt(row[4]),
+			Functions: rowInt(row[5]),
+		})
+	}
+	return snapshots, nil
+}
+
+// FindSnapshot returns the snapshot of a commit, given as a full or
+// abbreviated SHA (at least 4 characters) or as the ref it was built from.
+func FindSnapshot(ctx context.Context, client Querier, asOf string) (*Snapshot, error) {
+	asOf = strings.TrimSpace(asOf)
+	snapshots, err := ListSnapshots(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Snapshot
+	for _, s := range snapshots {
+		if s.SHA == asOf || s.Ref == asOf {
+			return &s, nil
+		}
+		if len(asOf) >= 4 && strings.HasPrefix(s.SHA, strings.ToLower(asOf)) {
+			matches = append(matches, s)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return &matches[0], nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("as_of %q matches %d snapshots; use a longer commit SHA", asOf, len(matches))
+	case len(snapshots) == 0:
+		return nil, fmt.Errorf("no snapshot for %q: the index has no snapshots; build one with 'cie index --ref %s'", asOf, asOf)
+	}
+	labels := make([]string, 0, len(snapshots))
+	for _, s := range snapshots {
+		labels = append(labels, s.Label())
+	}
+	return nil, fmt.Errorf("no snapshot for %q; build one with 'cie index --ref %s'. Available: %s", asOf, asOf, strings.Join(labels, ", "))
+}
+
+// SnapshotQuerier runs queries against a snapshot instead of HEAD, by
+// rewriting them with AsOfScript.
+type SnapshotQuerier struct {
+	client Querier
+	seq    int
+}
+
+// NewSnapshotQuerier creates a Querier reading the snapshot seq through client.
+func NewSnapshotQuerier(client Querier, seq int) *SnapshotQuerier {
+	return &SnapshotQuerier{client: client, seq: seq}
+}
+
+// Query executes a query against the snapshot.
+func (q *SnapshotQuerier) Query(ctx context.Context, script string) (*QueryResult, error) {
+	return q.client.Query(ctx, AsOfScript(script, q.seq))
+}
+
+// QueryRaw executes a query against the snapshot and returns raw results.
+func (q *SnapshotQuerier) QueryRaw(ctx context.Context, script string) (map[string]any, error) {
+	return q.client.QueryRaw(ctx, AsOfScript(script, q.seq))
+}
+
+// AsOfScript rewrites a CozoScript query to read snapshot seq: stored
+// relation atoms of the relations kept in snapshots are redirected to their
+// history relation at validity seq,
+//
+//	*cie_function { id, name }  →  *cie_hist_function { id, name @ 3 }
+//
+// Other relations (embeddings, coverage, git history) and string literals
+// are left as written.
+func AsOfScript(script string, seq int) string {
+	var out strings.Builder
+	out.Grow(len(script) + 64)
+	for i := 0; i < len(script); {
+		c := script[i]
+		if c == '"' || c == '\'' {
+			end := stringLiteralEnd(script, i)
+			out.WriteString(script[i:end])
+			i = end
+			continue
+		}
+		if c != '*' || !strings.HasPrefix(script[i+1:], "cie_") {
+			out.WriteByte(c)
+			i++
+			continue
+		}
+
+		nameEnd := i + 1
+		for nameEnd < len(script) && isIdentByte(script[nameEnd]) {
+			nameEnd++
+		}
+		name := script[i+1 : nameEnd]
+		open := nameEnd
+		for open < len(script) && (script[open] == ' ' || script[open] == '\t' || script[open] == '\n') {
+			open++
+		}
+		if !storage.IsHistoryRelation(name) || open == len(script) || script[open] != '{' {
+			out.WriteString(script[i:nameEnd])
+			i = nameEnd
+			continue
+		}
+		closing := atomEnd(script, open)
+		if closing == -1 {
+			out.WriteString(script[i:])
+			break
+		}
+		out.WriteString("*" + storage.HistoryPrefix + strings.TrimPrefix(name, "cie_"))
+		out.WriteString(strings.TrimRight(script[nameEnd:closing], " \t\n"))
+		out.WriteString(" @ " + strconv.Itoa(seq) + " }")
+		i = closing + 1
+	}
+	return out.String()
+}
+
+// HeadRelations returns the stored relations a query reads that snapshots
+// keep no history of (embeddings, coverage, git history, project
+// metadata), sorted. AsOfScript leaves them reading HEAD, so a query on a
+// snapshot touching them would mix in HEAD rows.
+func HeadRelations(script string) []string {
+	seen := map[string]bool{}
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		if c == '"' || c == '\'' {
+			i = stringLiteralEnd(script, i) - 1
+			continue
+		}
+		if (c != '*' && c != '~') || !strings.HasPrefix(script[i+1:], "cie_") {
+			continue
+		}
+		end := i + 1
+		for end < len(script) && isIdentByte(script[end]) {
+			end++
+		}
+		name := script[i+1 : end]
+		if !storage.IsHistoryRelation(name) && !strings.HasPrefix(name, storage.HistoryPrefix) && name != "cie_snapshot" {
+			seen[name] = true
+		}
+		i = end - 1
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// atomEnd returns the index of the brace closing the atom opened at open,
+// -1 if it is not closed.
+func atomEnd(script string, open int) int {
+	depth := 0
+	for i := open; i < len(script); i++ {
+		switch script[i] {
+		case '"', '\'':
+			i = stringLiteralEnd(script, i) - 1
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// stringLiteralEnd returns the index after the string literal starting at
+// start, honoring backslash escapes.
+func stringLiteralEnd(script string, start int) int {
+	quote := script[start]
+	for i := start + 1; i < len(script); i++ {
+		switch script[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(script)
+}
+
+// isIdentByte reports whether c can be part of a relation name.
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// rowInt converts an integer column of a query row.
+func rowInt(v any) int {
+	n, _ := strconv.Atoi(AnyToString(v))
+	return n
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAsOfScript(t *testing.T) {
+	tests := []struct {
+		name, script, want string
+	}{
+		{
+			"stored relations",
+			`?[name, callee] := *cie_function { id, name, file_path }, *cie_calls{caller_id: id, callee_id}, *cie_function{id: callee_id, name: callee}`,
+			`?[name, callee] := *cie_hist_function { id, name, file_path @ 7 }, *cie_hist_calls{caller_id: id, callee_id @ 7 }, *cie_hist_function{id: callee_id, name: callee @ 7 }`,
+		},
+		{
+			"multiline atom",
+			"?[n] := *cie_type {\n  name: n,\n  kind: \"struct\"\n}",
+			"?[n] := *cie_hist_type {\n  name: n,\n  kind: \"struct\" @ 7 }",
+		},
+		{
+			"braces in string literals",
+			`?[id] := *cie_function{id, name: "}"}, regex_matches(id, '*cie_type{')`,
+			`?[id] := *cie_hist_function{id, name: "}" @ 7 }, regex_matches(id, '*cie_type{')`,
+		},
+		{
+			"relations without history",
+			`?[id, c] := *cie_function_churn{function_id: id, commits: c}, ~cie_function_embedding:embedding_idx { function_id | query: q, k: 5 }`,
+			`?[id, c] := *cie_function_churn{function_id: id, commits: c}, ~cie_function_embedding:embedding_idx { function_id | query: q, k: 5 }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AsOfScript(tt.script, 7); got != tt.want {
+				t.Errorf("AsOfScript() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeadRelations(t *testing.T) {
+	script := `?[id, c] := *cie_function{id}, *cie_function_churn{function_id: id, commits: c}, *cie_coverage{function_id: id}, *cie_hist_calls{caller_id: id @ 2}, ~cie_function_embedding:embedding_idx { function_id | query: q, k: 5 }, regex_matches(id, "*cie_project_meta")`
+	want := []string{"cie_coverage", "cie_function_churn", "cie_function_embedding"}
+	if got := HeadRelations(script); !slices.Equal(got, want) {
+		t.Errorf("HeadRelations() = %v, want %v", got, want)
+	}
+	if got := HeadRelations(`?[n] := *cie_function{name: n}, *cie_snapshot{seq}`); len(got) != 0 {
+		t.Errorf("HeadRelations() = %v, want none", got)
+	}
+}
+
+func TestRawQuery_AsOf(t *testing.T) {
+	client := &MockCIEClient{QueryFunc: func(_ context.Context, script string) (*QueryResult, error) {
+		return &QueryResult{Headers: []string{"id"}}, nil
+	}}
+	script := `?[id, c] := *cie_function_churn{function_id: id, commits: c}`
+	result, err := RawQuery(context.Background(), client, RawQueryArgs{Script: script, AsOf: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || !strings.Contains(result.Text, "no history of cie_function_churn") {
+		t.Errorf("RawQuery() as of a snapshot should reject cie_function_churn, got %+v", result)
+	}
+	if result, _ = RawQuery(context.Background(), client, RawQueryArgs{Script: script}); result.IsError {
+		t.Errorf("RawQuery() at HEAD should read cie_function_churn, got %+v", result)
+	}
+}
+
+func TestSnapshotQuerier(t *testing.T) {
+	var got string
+	client := &MockCIEClient{QueryFunc: func(_ context.Context, script string) (*QueryResult, error) {
+		got = script
+		return &QueryResult{}, nil
+	}}
+	_, err := NewSnapshotQuerier(client, 2).Query(context.Background(), `?[n] := *cie_file{path: n}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != `?[n] := *cie_hist_file{path: n @ 2 }` {
+		t.Errorf("query = %s", got)
+	}
+}
+
+func TestFindSnapshot(t *testing.T) {
+	client := NewMockClientWithResults(
+		[]string{"seq", "sha", "ref", "indexed_at", "files", "functions"},
+		[][]any{
+			{float64(1), "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678", "v2.3.0", float64(1760000000), float64(120), float64(900)},
+			{float64(2), "a1b2ffff0000111122223333444455556666777", "a1b2ffff", float64(1760100000), float64(130), float64(950)},
+		},
+	)
+	ctx := context.Background()
+
+	s, err := FindSnapshot(ctx, client, "v2.3.0")
+	if err != nil || s.Seq != 1 || s.Files != 120 {
+		t.Fatalf("FindSnapshot(ref) = %+v, %v", s, err)
+	}
+	if s.Label() != "a1b2c3d4e5f6 (v2.3.0)" {
+		t.Errorf("Label() = %q", s.Label())
+	}
+	s, err = FindSnapshot(ctx, client, "A1B2FF")
+	if err != nil || s.Seq != 2 {
+		t.Fatalf("FindSnapshot(prefix) = %+v, %v", s, err)
+	}
+	if s.Label() != "a1b2ffff0000" {
+		t.Errorf("Label() = %q", s.Label())
+	}
+
+	if _, err := FindSnapshot(ctx, client, "a1b2"); err == nil || !strings.Contains(err.Error(), "matches 2 snapshots") {
+		t.Errorf("ambiguous prefix: err = %v", err)
+	}
+	if _, err := FindSnapshot(ctx, client, "v1.0.0"); err == nil || !strings.Contains(err.Error(), "cie index --ref v1.0.0") {
+		t.Errorf("unknown ref: err = %v", err)
+	}
+}
@@ -34,7 +34,7 @@ import (
 // If maxFuncsPerFile is 0 or negative, defaults to 5 functions per file.
 //
 // Returns a ToolResult containing a formatted directory listing with file paths and their
-// key functions (name, signature, line number). Returns an error if the query fails.
+// key functions (name, signature, line number, first sentence of the doc comment). Returns an error if the query fails.
 //
 // Example output format:
 //
@@ -42,8 +42,9 @@ import (
 //	Found **3 files**
 //
 //	## internal/cie/client.go
-//	- **NewClient** (line 25): `func NewClient(url string) *Client`
-//	- **Query** (line 45): `func (c *Client) Query(ctx context.Context, script string) (*Result, error)`
+//	- **NewClient** (line 25)
+//	  `func NewClient(url string) *Client`
+//	  _NewClient creates a client for the CIE server at url._
 func DirectorySummary(ctx context.Context, client Querier, path string, maxFuncsPerFile int) (*ToolResult, error) {
 	if path == "" {
 		return NewError("Error: 'path' is required"), nil
@@ -87,12 +88,12 @@ func queryDirFiles(ctx context.Context, client Querier, path string) (*QueryResu
 }
 
 type dirFuncInfo struct {
-	name, signature, line string
-	exported              bool
+	name, signature, line, doc string
+	exported                   bool
 }
 
 func formatFileSummaryEntry(ctx context.Context, client Querier, filePath string, maxFuncs int) string {
-	query := fmt.Sprintf(`?[name, signature, start_line] := *cie_function { name, signature, start_line, file_path }, file_path == %q :order name :limit %d`, filePath, maxFuncs*2)
+	query := fmt.Sprintf(`?[name, signature, start_line, doc_comment] := *cie_function { name, signature, start_line, file_path, doc_comment }, file_path == %q :order name :limit %d`, filePath, maxFuncs*2)
 	result, err := client.Query(ctx, query)
 	if err != nil {
 		return ""
@@ -115,10 +116,15 @@ func parseDirFuncResults(rows [][]any) []dirFuncInfo {
 	var funcs []dirFuncInfo
 	for _, row := range rows {
 		name := AnyToString(row[0])
+		var doc string
+		if len(row) > 3 {
+			doc = docSummary(AnyToString(row[3]))
+		}
 		funcs = append(funcs, dirFuncInfo{
 			name:      name,
 			signature: AnyToString(row[1]),
 			line:      AnyToString(row[2]),
+			doc:       doc,
 			exported:  len(name) > 0 && name[0] >= 'A' && name[0] <= 'Z',
 		})
 	}
@@ -137,6 +143,9 @@ func formatDirFuncs(funcs []dirFuncInfo, maxFuncs int) string {
 	for _, f := range funcs {
 		if !f.exported && shown < maxFuncs {
 			output += fmt.Sprintf("- %s (line %s)\n", f.name, f.line)
+			if f.doc != "" {
+				output += fmt.Sprintf("  _%s_\n", f.doc)
+			}
 			shown++
 		}
 	}
@@ -152,5 +161,8 @@ func formatExportedFunc(f dirFuncInfo) string {
 	if sigShort != "" && sigShort != f.name {
 		output += fmt.Sprintf("  `%s`\n", sigShort)
 	}
+	if f.doc != "" {
+		output += fmt.Sprintf("  _%s_\n", f.doc)
+	}
 	return output
 }
@@ -0,0 +1,313 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// testFileCondition excludes test files from table usage results.
+const testFileCondition = `not regex_matches(file_path, "(?i)(_test[.]go|test[.]ts|test[.]js|_test[.]py|/tests/|/__tests__/)")`
+
+// FindTableUsageArgs holds arguments for finding SQL table usage.
+type FindTableUsageArgs struct {
+	// Table is the table name. Matching ignores case, quotes and schema prefix
+	// ("public.Orders" matches "orders"). Empty lists all tables.
+	Table string
+
+	// Access filters usages: "read", "write", or "" for both.
+	Access string
+
+	// PathPattern filters accessing functions by file path using regex.
+	PathPattern string
+
+	// IncludeTests includes functions in test files.
+	IncludeTests bool
+
+	// Limit is the maximum number of functions per access kind (default: 50).
+	Limit int
+}
+
+// tableUsage is a function that reads or writes a table.
+type tableUsage struct {
+	Name     string
+	FilePath string
+	Line     string
+}
+
+// FindTableUsage reports which functions read or write a SQL table, along with
+// the table's schema (columns, indexes, foreign keys) from indexed .sql files.
+//
+// Usages come from cie_table_access, which is built at index time from SQL
+// string literals passed to database calls (database/sql, sqlx, DB-API,
+// node-postgres, Laravel DB) and ORM table selectors (GORM, Laravel, knex).
+//
+// Without a table name, lists all known tables with reader/writer counts.
+func FindTableUsage(ctx context.Context, client Querier, args FindTableUsageArgs) (*ToolResult, error) {
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+	access := strings.ToLower(args.Access)
+	if access != "" && access != "read" && access != "write" {
+		return NewError("Error: 'access' must be \"read\", \"write\" or empty"), nil
+	}
+
+	if args.Table == "" {
+		return listTables(ctx, client, args)
+	}
+
+	table := normalizeTableName(args.Table)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### Table `%s`\n\n", table))
+
+	writeTableSchema(ctx, client, table, &sb)
+
+	var conditions []string
+	conditions = append(conditions, fmt.Sprintf("table_name == %q", table))
+	if access != "" {
+		conditions = append(conditions, fmt.Sprintf("access == %q", access))
+	}
+	if args.PathPattern != "" {
+		conditions = append(conditions, fmt.Sprintf("regex_matches(file_path, %q)", args.PathPattern))
+	}
+	if !args.IncludeTests {
+		conditions = append(conditions, testFileCondition)
+	}
+
+	script := fmt.Sprintf(
+		`?[access, name, file_path, line] := *cie_table_access { function_id, table_name, access, file_path, line }, *cie_function { id: function_id, name }, %s :order access, file_path, line :limit %d`,
+		strings.Join(conditions, ", "), args.Limit*2,
+	)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query error: %v", err)), nil
+	}
+
+	usages := map[string][]tableUsage{}
+	for _, row := range result.Rows {
+		kind := AnyToString(row[0])
+		if len(usages[kind]) >= args.Limit {
+			continue
+		}
+		usages[kind] = append(usages[kind], tableUsage{
+			Name:     AnyToString(row[1]),
+			FilePath: AnyToString(row[2]),
+			Line:     AnyToString(row[3]),
+		})
+	}
+
+	if len(usages) == 0 {
+		sb.WriteString("No functions found that access this table.\n\n")
+		sb.WriteString("**Tips:**\n")
+		sb.WriteString("- Call without `table` to list tables with known usage\n")
+		sb.WriteString("- Queries built at runtime (e.g., `fmt.Sprintf` table names) are not detected; try `cie_grep`\n")
+		return NewResult(sb.String()), nil
+	}
+
+	for _, kind := range []string{"write", "read"} {
+		list := usages[kind]
+		if len(list) == 0 {
+			continue
+		}
+		title := "Writes"
+		if kind == "read" {
+			title = "Reads"
+		}
+		sb.WriteString(fmt.Sprintf("**%s** (%d):\n", title, len(list)))
+		for _, u := range list {
+			sb.WriteString(fmt.Sprintf("- %s — %s:%s\n", u.Name, u.FilePath, u.Line))
+		}
+		sb.WriteString("\n")
+	}
+
+	return NewResult(sb.String()), nil
+}
+
+// writeTableSchema appends the table's definition, columns, indexes and
+// foreign keys (both directions). Missing schema is reported, not an error:
+// code may use tables created outside indexed .sql files.
+func writeTableSchema(ctx context.Context, client Querier, table string, sb *strings.Builder) {
+	defs, err := client.Query(ctx, fmt.Sprintf(
+		`?[file_path, start_line] := *cie_sql_table { name, file_path, start_line }, name == %q :order file_path`, table))
+	if err != nil || len(defs.Rows) == 0 {
+		sb.WriteString("_No CREATE TABLE found in indexed .sql files._\n\n")
+		return
+	}
+
+	var locations []string
+	for _, row := range defs.Rows {
+		locations = append(locations, fmt.Sprintf("%s:%s", AnyToString(row[0]), AnyToString(row[1])))
+	}
+	sb.WriteString(fmt.Sprintf("**Defined in**: %s\n\n", strings.Join(locations, ", ")))
+
+	columns, err := client.Query(ctx, fmt.Sprintf(
+		`?[name, data_type, nullable, primary_key, file_path, line] := *cie_sql_column { table_name, name, data_type, nullable, primary_key, file_path, line }, table_name == %q :order file_path, line`, table))
+	if err == nil && len(columns.Rows) > 0 {
+		sb.WriteString("**Columns**:\n")
+		for _, row := range columns.Rows {
+			var flags []string
+			if AnyToString(row[3]) == "true" {
+				flags = append(flags, "PK")
+			}
+			if AnyToString(row[2]) == "false" && AnyToString(row[3]) != "true" {
+				flags = append(flags, "NOT NULL")
+			}
+			line := fmt.Sprintf("- `%s` %s", AnyToString(row[0]), AnyToString(row[1]))
+			if len(flags) > 0 {
+				line += " (" + strings.Join(flags, ", ") + ")"
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	indexes, err := client.Query(ctx, fmt.Sprintf(
+		`?[name, columns, is_unique] := *cie_sql_index { name, table_name, columns, is_unique }, table_name == %q :order name`, table))
+	if err == nil && len(indexes.Rows) > 0 {
+		sb.WriteString("**Indexes**:\n")
+		for _, row := range indexes.Rows {
+			name := AnyToString(row[0])
+			if name == "" {
+				name = "(unnamed)"
+			}
+			unique := ""
+			if AnyToString(row[2]) == "true" {
+				unique = " UNIQUE"
+			}
+			sb.WriteString(fmt.Sprintf("- %s (%s)%s\n", name, AnyToString(row[1]), unique))
+		}
+		sb.WriteString("\n")
+	}
+
+	fks, err := client.Query(ctx, fmt.Sprintf(
+		`?[table_name, columns, ref_table, ref_columns] := *cie_sql_foreign_key { table_name, columns, ref_table, ref_columns }, or(table_name == %q, ref_table == %q)`, table, table))
+	if err == nil && len(fks.Rows) > 0 {
+		sb.WriteString("**Foreign keys**:\n")
+		for _, row := range fks.Rows {
+			refCols := AnyToString(row[3])
+			if refCols == "" {
+				refCols = "PK"
+			}
+			sb.WriteString(fmt.Sprintf("- %s(%s) → %s(%s)\n",
+				AnyToString(row[0]), AnyToString(row[1]), AnyToString(row[2]), refCols))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// tableSummary aggregates usage counts for one table.
+type tableSummary struct {
+	name    string
+	defined bool
+	readers int
+	writers int
+}
+
+// listTables lists tables from the schema and from code usage with reader/writer counts.
+func listTables(ctx context.Context, client Querier, args FindTableUsageArgs) (*ToolResult, error) {
+	tables := make(map[string]*tableSummary)
+	get := func(name string) *tableSummary {
+		if tables[name] == nil {
+			tables[name] = &tableSummary{name: name}
+		}
+		return tables[name]
+	}
+
+	defs, err := client.Query(ctx, `?[name] := *cie_sql_table { name }`)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query error: %v", err)), nil
+	}
+	for _, row := range defs.Rows {
+		get(AnyToString(row[0])).defined = true
+	}
+
+	var conditions []string
+	if args.PathPattern != "" {
+		conditions = append(conditions, fmt.Sprintf("regex_matches(file_path, %q)", args.PathPattern))
+	}
+	if !args.IncludeTests {
+		conditions = append(conditions, testFileCondition)
+	}
+	script := `?[table_name, access, count(function_id)] := *cie_table_access { function_id, table_name, access, file_path }`
+	if len(conditions) > 0 {
+		script += ", " + strings.Join(conditions, ", ")
+	}
+	usage, err := client.Query(ctx, script)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query error: %v", err)), nil
+	}
+	for _, row := range usage.Rows {
+		t := get(AnyToString(row[0]))
+		var n int
+		_, _ = fmt.Sscanf(AnyToString(row[2]), "%d", &n)
+		if AnyToString(row[1]) == "write" {
+			t.writers = n
+		} else {
+			t.readers = n
+		}
+	}
+
+	if len(tables) == 0 {
+		return NewResult("No SQL tables found in the index.\n\n**Note:** Tables come from `.sql` migrations/schema dumps and from SQL literals in database calls."), nil
+	}
+
+	list := make([]*tableSummary, 0, len(tables))
+	for _, t := range tables {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		ui, uj := list[i].readers+list[i].writers, list[j].readers+list[j].writers
+		if ui != uj {
+			return ui > uj
+		}
+		return list[i].name < list[j].name
+	})
+	if len(list) > args.Limit {
+		list = list[:args.Limit]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### SQL Tables (%d)\n\n", len(tables)))
+	sb.WriteString("| Table | Schema | Readers | Writers |\n")
+	sb.WriteString("|-------|--------|---------|---------|\n")
+	for _, t := range list {
+		schema := "—"
+		if t.defined {
+			schema = "✓"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d | %d |\n", t.name, schema, t.readers, t.writers))
+	}
+	sb.WriteString("\nUse `table` to see the functions that read or write a table.\n")
+
+	return NewResult(sb.String()), nil
+}
+
+// normalizeTableName lowercases a table name and strips quotes and schema
+// prefix, matching how tables are stored at index time.
+func normalizeTableName(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(strings.Trim(name, "\"`[]"))
+}
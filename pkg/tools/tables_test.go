@@ -0,0 +1,192 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// tableUsageMockClient answers the queries issued by FindTableUsage by relation.
+func tableUsageMockClient(queries *[]string) *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			*queries = append(*queries, script)
+			switch {
+			case strings.Contains(script, "*cie_table_access") && strings.Contains(script, "count(function_id)"):
+				return &QueryResult{Rows: [][]any{
+					{"orders", "read", float64(3)},
+					{"orders", "write", float64(1)},
+					{"audit_log", "write", float64(2)},
+				}}, nil
+			case strings.Contains(script, "*cie_table_access"):
+				return &QueryResult{Rows: [][]any{
+					{"read", "Store.ListOrders", "internal/store/orders.go", float64(40)},
+					{"write", "Store.CreateOrder", "internal/store/orders.go", float64(12)},
+				}}, nil
+			case strings.Contains(script, "*cie_sql_table"):
+				if strings.Contains(script, "start_line") {
+					return &QueryResult{Rows: [][]any{{"db/001_orders.sql", float64(1)}}}, nil
+				}
+				return &QueryResult{Rows: [][]any{{"orders"}, {"users"}}}, nil
+			case strings.Contains(script, "*cie_sql_column"):
+				return &QueryResult{Rows: [][]any{
+					{"id", "BIGSERIAL", false, true, "db/001_orders.sql", float64(2)},
+					{"user_id", "BIGINT", false, false, "db/001_orders.sql", float64(3)},
+					{"note", "TEXT", true, false, "db/001_orders.sql", float64(4)},
+				}}, nil
+			case strings.Contains(script, "*cie_sql_index"):
+				return &QueryResult{Rows: [][]any{{"orders_user_idx", "user_id", false}}}, nil
+			case strings.Contains(script, "*cie_sql_foreign_key"):
+				return &QueryResult{Rows: [][]any{{"orders", "user_id", "users", "id"}}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestFindTableUsage_Table(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	result, err := FindTableUsage(context.Background(), tableUsageMockClient(&queries), FindTableUsageArgs{Table: `public."Orders"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %s", result.Text)
+	}
+
+	for _, want := range []string{
+		"### Table `orders`",
+		"**Defined in**: db/001_orders.sql:1",
+		"- `id` BIGSERIAL (PK)",
+		"- `user_id` BIGINT (NOT NULL)",
+		"- `note` TEXT\n",
+		"- orders_user_idx (user_id)",
+		"- orders(user_id) → users(id)",
+		"**Writes** (1):\n- Store.CreateOrder — internal/store/orders.go:12",
+		"**Reads** (1):\n- Store.ListOrders — internal/store/orders.go:40",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("result should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Index(result.Text, "**Writes**") > strings.Index(result.Text, "**Reads**") {
+		t.Error("writes should be listed before reads")
+	}
+
+	var accessQuery string
+	for _, q := range queries {
+		if strings.Contains(q, "*cie_table_access") {
+			accessQuery = q
+		}
+	}
+	if !strings.Contains(accessQuery, `table_name == "orders"`) {
+		t.Errorf("access query should filter by normalized table name, got: %s", accessQuery)
+	}
+	if !strings.Contains(accessQuery, "not regex_matches(file_path") {
+		t.Errorf("access query should exclude test files by default, got: %s", accessQuery)
+	}
+}
+
+func TestFindTableUsage_Filters(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	_, err := FindTableUsage(context.Background(), tableUsageMockClient(&queries), FindTableUsageArgs{
+		Table:        "orders",
+		Access:       "WRITE",
+		PathPattern:  "internal/store",
+		IncludeTests: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	last := queries[len(queries)-1]
+	for _, want := range []string{`access == "write"`, `regex_matches(file_path, "internal/store")`} {
+		if !strings.Contains(last, want) {
+			t.Errorf("query should contain %q, got: %s", want, last)
+		}
+	}
+	if strings.Contains(last, "not regex_matches") {
+		t.Errorf("query should not exclude tests when IncludeTests is set, got: %s", last)
+	}
+}
+
+func TestFindTableUsage_InvalidAccess(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	result, err := FindTableUsage(context.Background(), tableUsageMockClient(&queries), FindTableUsageArgs{Table: "orders", Access: "delete"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error result for invalid access")
+	}
+	if len(queries) != 0 {
+		t.Errorf("expected no queries, got %d", len(queries))
+	}
+}
+
+func TestFindTableUsage_ListTables(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	result, err := FindTableUsage(context.Background(), tableUsageMockClient(&queries), FindTableUsageArgs{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"### SQL Tables (3)",
+		"| orders | ✓ | 3 | 1 |",
+		"| audit_log | — | 0 | 2 |",
+		"| users | ✓ | 0 | 0 |",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("result should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Index(result.Text, "| orders") > strings.Index(result.Text, "| audit_log") {
+		t.Error("tables should be ordered by usage")
+	}
+}
+
+func TestNormalizeTableName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"orders":              "orders",
+		"Orders":              "orders",
+		`public."Orders"`:     "orders",
+		"`shop`.`orders`":     "orders",
+		"[dbo].[Orders]":      "orders",
+		"  analytics.events ": "events",
+	}
+	for in, want := range tests {
+		if got := normalizeTableName(in); got != want {
+			t.Errorf("normalizeTableName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
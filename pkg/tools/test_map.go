@@ -0,0 +1,391 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// defaultTestDepth is how many caller levels are followed from a function
+	// to the tests reaching it by default.
+	defaultTestDepth = 4
+
+	// maxTestDepth caps the caller levels followed to reach tests.
+	maxTestDepth = 8
+
+	// maxTestRows caps the rows fetched per caller or test query.
+	maxTestRows = 2000
+)
+
+// FindTestsArgs holds arguments for the find_tests tool.
+type FindTestsArgs struct {
+	// Function is the function or method whose tests are searched
+	// (e.g., "Store.Get").
+	Function string
+
+	// MaxDepth is how many caller levels are followed from the function
+	// (default 4, max 8).
+	MaxDepth int
+}
+
+// FindUntestedArgs holds arguments for the find_untested tool.
+type FindUntestedArgs struct {
+	// Path restricts the search to a directory (e.g., "pkg/tools").
+	Path string
+
+	// Limit caps the functions listed (default 100).
+	Limit int
+}
+
+// testEntity is one row of cie_test.
+type testEntity struct {
+	FunctionID string
+	Name       string
+	Kind       string
+	Suite      string
+	Framework  string
+	File       string
+	Line       int
+}
+
+// reachedFunc is a function calling the target, directly or through others.
+type reachedFunc struct {
+	Name string
+	Next string // ID of the function it calls on the way to the target
+}
+
+// FindTests lists the tests exercising a function: the tests, subtests and
+// test cases whose body reaches it through the call graph within MaxDepth
+// caller levels, with the helpers they go through, and the commands running
+// them (go test -run, pytest node IDs, jest/vitest files).
+func FindTests(ctx context.Context, client Querier, args FindTestsArgs) (*ToolResult, error) {
+	if args.Function == "" {
+		return NewError("Error: 'function' is required"), nil
+	}
+	depth := args.MaxDepth
+	if depth <= 0 {
+		depth = defaultTestDepth
+	}
+	if depth > maxTestDepth {
+		depth = maxTestDepth
+	}
+
+	targets, err := queryFunctionsNamed(ctx, client, args.Function)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	if len(targets) == 0 {
+		return NewResult(fmt.Sprintf("No function named `%s` found.\n\nUse **cie_find_function** to check the name.\n", args.Function)), nil
+	}
+
+	reached, err := queryReachingCallers(ctx, client, targets, depth)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	tests, err := queryTestsOf(ctx, client, reached)
+	if err != nil {
+		if strings.Contains(err.Error(), "cie_test") {
+			return NewResult("Tests are not indexed: re-index the project (`cie index`) to record test functions and cases.\n"), nil
+		}
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Tests exercising `%s`\n\n", args.Function)
+	if len(tests) == 0 {
+		fmt.Fprintf(&sb, "No test reaches `%s` within %d caller levels.\n\n", args.Function, depth)
+		sb.WriteString("**Tips:**\n" +
+			"- Increase `max_depth` to follow longer call chains\n" +
+			"- Calls through interfaces, reflection or callbacks may be missing from the call graph\n" +
+			"- Use **cie_find_untested** to list the exported functions no test reaches\n")
+		return NewResult(sb.String()), nil
+	}
+
+	byFile := make(map[string][]testEntity)
+	var files []string
+	for _, t := range tests {
+		if _, ok := byFile[t.File]; !ok {
+			files = append(files, t.File)
+		}
+		byFile[t.File] = append(byFile[t.File], t)
+	}
+	sort.Strings(files)
+	fmt.Fprintf(&sb, "%d tests in %d files reach it within %d caller levels.\n", len(tests), len(files), depth)
+
+	for _, file := range files {
+		fmt.Fprintf(&sb, "\n### %s\n\n", file)
+		for _, t := range byFile[file] {
+			fmt.Fprintf(&sb, "- `%s` (%s, line %d) — %s\n", t.Name, t.Kind, t.Line, testReachPath(reached, targets, t.FunctionID))
+		}
+	}
+
+	if commands := testRunCommands(files, byFile); len(commands) > 0 {
+		sb.WriteString("\n### Run\n\n")
+		for _, cmd := range commands {
+			fmt.Fprintf(&sb, "- `%s`\n", cmd)
+		}
+	}
+	return NewResult(sb.String()), nil
+}
+
+// queryReachingCallers follows the callers of the targets level by level up
+// to depth, and returns every function reached with the function it calls on
+// the way to a target.
+func queryReachingCallers(ctx context.Context, client Querier, targets map[string]string, depth int) (map[string]reachedFunc, error) {
+	reached := make(map[string]reachedFunc)
+	var frontier []string
+	for id, name := range targets {
+		reached[id] = reachedFunc{Name: name}
+		frontier = append(frontier, id)
+	}
+	sort.Strings(frontier)
+
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		conds := make([]string, len(frontier))
+		for i, id := range frontier {
+			conds[i] = fmt.Sprintf("callee_id = %q", id)
+		}
+		script := fmt.Sprintf(`?[callee_id, caller_id, caller_name] := *cie_calls { caller_id, callee_id }, (%s),
+  *cie_function { id: caller_id, name: caller_name }
+:limit %d`, strings.Join(conds, " or "), maxTestRows)
+		result, err := client.Query(ctx, script)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for _, row := range result.Rows {
+			if len(row) < 3 {
+				continue
+			}
+			callerID := AnyToString(row[1])
+			if _, ok := reached[callerID]; ok {
+				continue
+			}
+			reached[callerID] = reachedFunc{Name: AnyToString(row[2]), Next: AnyToString(row[0])}
+			next = append(next, callerID)
+		}
+		frontier = next
+	}
+	return reached, nil
+}
+
+// queryTestsOf returns the tests whose function was reached, ordered by file
+// and line.
+func queryTestsOf(ctx context.Context, client Querier, reached map[string]reachedFunc) ([]testEntity, error) {
+	ids := make([]string, 0, len(reached))
+	for id := range reached {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	conds := make([]string, len(ids))
+	for i, id := range ids {
+		conds[i] = fmt.Sprintf("function_id = %q", id)
+	}
+
+	script := fmt.Sprintf(`?[function_id, name, kind, suite, framework, file_path, line] := *cie_test { function_id, name, kind, suite, framework, file_path, line },
+  kind != "suite", (%s)
+:order file_path, line :limit %d`, strings.Join(conds, " or "), maxTestRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []testEntity
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		tests = append(tests, testEntity{
+			FunctionID: AnyToString(row[0]),
+			Name:       AnyToString(row[1]),
+			Kind:       AnyToString(row[2]),
+			Suite:      AnyToString(row[3]),
+			Framework:  AnyToString(row[4]),
+			File:       AnyToString(row[5]),
+			Line:       rowLine(row[6]),
+		})
+	}
+	return tests, nil
+}
+
+// testReachPath describes how a test function reaches a target: directly, or
+// through the helpers it calls.
+func testReachPath(reached map[string]reachedFunc, targets map[string]string, id string) string {
+	if _, ok := targets[id]; ok {
+		return "is the function itself"
+	}
+	var via []string
+	for next := reached[id].Next; next != ""; next = reached[next].Next {
+		if _, ok := targets[next]; ok {
+			break
+		}
+		via = append(via, "`"+reached[next].Name+"`")
+	}
+	if len(via) == 0 {
+		return "calls it directly"
+	}
+	return "via " + strings.Join(via, " → ")
+}
+
+// testRunCommands returns the commands running the tests of each file:
+// go test per package with a -run pattern of the top-level tests, pytest
+// node IDs, and jest or vitest per file.
+func testRunCommands(files []string, byFile map[string][]testEntity) []string {
+	var commands []string
+	goRuns := make(map[string][]string)
+	goSuites := make(map[string][]string)
+	var goDirs []string
+	for _, file := range files {
+		tests := byFile[file]
+		switch tests[0].Framework {
+		case "go":
+			dir := "."
+			if d := path.Dir(file); d != "." {
+				dir = "./" + d
+			}
+			if _, ok := goRuns[dir]; !ok {
+				goRuns[dir] = nil
+				goDirs = append(goDirs, dir)
+			}
+			for _, t := range tests {
+				top, _, _ := strings.Cut(t.Name, "/")
+				switch {
+				case t.Kind == "benchmark":
+				case strings.Contains(top, "."):
+					_, method, _ := strings.Cut(top, ".")
+					goSuites[dir] = append(goSuites[dir], method)
+				default:
+					goRuns[dir] = append(goRuns[dir], top)
+				}
+			}
+		case "pytest":
+			var nodes []string
+			for _, t := range tests {
+				node := file + "::" + t.Name
+				if t.Suite != "" {
+					node = file + "::" + strings.ReplaceAll(t.Name, ".", "::")
+				}
+				nodes = append(nodes, node)
+			}
+			commands = append(commands, "pytest "+strings.Join(uniqueSorted(sortedCopy(nodes)), " "))
+		case "vitest":
+			commands = append(commands, "npx vitest run "+file)
+		default:
+			commands = append(commands, "npx jest "+file)
+		}
+	}
+
+	var goCommands []string
+	for _, dir := range goDirs {
+		if names := uniqueSorted(sortedCopy(goRuns[dir])); len(names) > 0 {
+			goCommands = append(goCommands, fmt.Sprintf("go test %s -run '^(%s)$'", dir, strings.Join(names, "|")))
+		}
+		if methods := uniqueSorted(sortedCopy(goSuites[dir])); len(methods) > 0 {
+			goCommands = append(goCommands, fmt.Sprintf("go test %s -testify.m '^(%s)$'", dir, strings.Join(methods, "|")))
+		}
+	}
+	return append(goCommands, commands...)
+}
+
+// sortedCopy returns a sorted copy of values.
+func sortedCopy(values []string) []string {
+	out := append([]string(nil), values...)
+	sort.Strings(out)
+	return out
+}
+
+// untestedNameCond matches exported functions: Go names starting with an
+// upper-case letter (the method name for methods), and for other languages
+// names without a leading underscore. Anonymous functions are excluded.
+const untestedNameCond = `negate(starts_with(name, "$")),
+  ((ends_with(file_path, ".go") and regex_matches(name, "(^|[.])[A-Z][A-Za-z0-9_]*$")) or
+   (negate(ends_with(file_path, ".go")) and negate(regex_matches(name, "(^|[.])_"))))`
+
+// FindUntested lists the exported production functions that no test reaches
+// through the call graph, at any depth. Functions only called through
+// interfaces, reflection or callbacks may be listed although a test runs them.
+func FindUntested(ctx context.Context, client Querier, args FindUntestedArgs) (*ToolResult, error) {
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	dir := strings.TrimSuffix(strings.TrimPrefix(args.Path, "./"), "/")
+
+	countResult, err := client.Query(ctx, `?[count(id)] := *cie_test { id }`)
+	if err != nil {
+		if strings.Contains(err.Error(), "cie_test") {
+			return NewResult("Tests are not indexed: re-index the project (`cie index`) to record test functions and cases.\n"), nil
+		}
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	if len(countResult.Rows) == 0 || len(countResult.Rows[0]) == 0 || rowLine(countResult.Rows[0][0]) == 0 {
+		return NewResult("No tests are indexed: Go _test.go files, pytest modules and Jest/Vitest test files are recognized.\n"), nil
+	}
+
+	conds := append(RoleFilters("source"), untestedNameCond)
+	title := "the repository"
+	if dir != "" && dir != "." {
+		conds = append(conds, fmt.Sprintf("starts_with(file_path, %q)", dir+"/"))
+		title = fmt.Sprintf("`%s`", dir)
+	}
+	script := fmt.Sprintf(`reached[id] := *cie_test { function_id: id }, id != ""
+reached[callee_id] := reached[caller_id], *cie_calls { caller_id, callee_id }
+?[name, file_path, start_line] := *cie_function { id, name, file_path, start_line }, not reached[id],
+  %s
+:order file_path, start_line :limit %d`, strings.Join(conds, ",\n  "), limit+1)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, script)), nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Untested exported functions in %s\n\n", title)
+	rows := result.Rows
+	if len(rows) == 0 {
+		sb.WriteString("Every exported function is reached by at least one test.\n")
+		return NewResult(sb.String()), nil
+	}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		fmt.Fprintf(&sb, "More than %d exported functions are not reached by any test (showing the first %d; narrow with `path`).\n", limit, limit)
+	} else {
+		fmt.Fprintf(&sb, "%d exported functions are not reached by any test.\n", len(rows))
+	}
+
+	lastFile := ""
+	for _, row := range rows {
+		if len(row) < 3 {
+			continue
+		}
+		if file := AnyToString(row[1]); file != lastFile {
+			fmt.Fprintf(&sb, "\n### %s\n\n", file)
+			lastFile = file
+		}
+		fmt.Fprintf(&sb, "- `%s` (line %d)\n", AnyToString(row[0]), rowLine(row[2]))
+	}
+	sb.WriteString("\n_Functions called only through interfaces, reflection or callbacks may be listed although a test runs them._\n")
+	return NewResult(sb.String()), nil
+}
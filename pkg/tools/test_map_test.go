@@ -0,0 +1,170 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testMapMockClient serves Store.Get, called by TestGet/missing_key directly
+// and by TestResolve through the newResolver helper, and by a pytest test
+// and a Jest case.
+func testMapMockClient() *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			switch {
+			case strings.Contains(script, "*cie_test"):
+				return &QueryResult{Rows: [][]any{
+					{"fn:sub", "TestGet/missing_key", "subtest", "TestGet", "go", "internal/store/store_test.go", float64(12)},
+					{"fn:resolve_test", "TestResolve", "test", "", "go", "internal/project/resolve_test.go", float64(8)},
+					{"fn:suite", "StoreSuite.TestPut", "test", "StoreSuite", "go", "internal/store/suite_test.go", float64(20)},
+					{"fn:py", "TestStore.test_get", "test", "TestStore", "pytest", "tests/test_store.py", float64(5)},
+					{"fn:jest", "returns the user", "test", "api > users", "jest", "web/users.test.ts", float64(9)},
+				}}, nil
+			case strings.Contains(script, `callee_id = "fn:get"`):
+				return &QueryResult{Rows: [][]any{
+					{"fn:get", "fn:sub", "$anon_1"},
+					{"fn:get", "fn:helper", "newResolver"},
+					{"fn:get", "fn:suite", "StoreSuite.TestPut"},
+					{"fn:get", "fn:py", "TestStore.test_get"},
+					{"fn:get", "fn:jest", "$arrow_3"},
+				}}, nil
+			case strings.Contains(script, `callee_id = "fn:helper"`):
+				return &QueryResult{Rows: [][]any{
+					{"fn:helper", "fn:resolve_test", "TestResolve"},
+				}}, nil
+			case strings.Contains(script, "*cie_function { id, name }"):
+				return &QueryResult{Rows: [][]any{{"fn:get", "Store.Get"}}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestFindTests(t *testing.T) {
+	result, err := FindTests(context.Background(), testMapMockClient(), FindTestsArgs{Function: "Store.Get"})
+	if err != nil {
+		t.Fatalf("FindTests() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"## Tests exercising `Store.Get`",
+		"5 tests in 5 files reach it within 4 caller levels.",
+		"### internal/project/resolve_test.go\n\n- `TestResolve` (test, line 8) — via `newResolver`\n",
+		"- `TestGet/missing_key` (subtest, line 12) — calls it directly\n",
+		"- `go test ./internal/project -run '^(TestResolve)$'`",
+		"- `go test ./internal/store -run '^(TestGet)$'`",
+		"- `go test ./internal/store -testify.m '^(TestPut)$'`",
+		"- `pytest tests/test_store.py::TestStore::test_get`",
+		"- `npx jest web/users.test.ts`",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("FindTests() should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+}
+
+func TestFindTests_Errors(t *testing.T) {
+	result, err := FindTests(context.Background(), &MockCIEClient{}, FindTestsArgs{})
+	if err != nil || !result.IsError {
+		t.Errorf("FindTests() without function should return an error result, got %+v, %v", result, err)
+	}
+
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			if strings.Contains(script, "*cie_test") {
+				return nil, errors.New("stored relation 'cie_test' not found")
+			}
+			if strings.Contains(script, "*cie_function { id, name }") {
+				return &QueryResult{Rows: [][]any{{"fn:get", "Store.Get"}}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+	result, err = FindTests(context.Background(), client, FindTestsArgs{Function: "Store.Get"})
+	if err != nil {
+		t.Fatalf("FindTests() error = %v", err)
+	}
+	if !strings.Contains(result.Text, "re-index the project") {
+		t.Errorf("FindTests() should ask to re-index, got:\n%s", result.Text)
+	}
+}
+
+func TestFindUntested(t *testing.T) {
+	var script string
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, s string) (*QueryResult, error) {
+			if strings.Contains(s, "count(id)") {
+				return &QueryResult{Rows: [][]any{{float64(42)}}}, nil
+			}
+			script = s
+			return &QueryResult{Rows: [][]any{
+				{"Store.Delete", "internal/store/store.go", float64(40)},
+				{"Open", "internal/store/store.go", float64(10)},
+				{"Migrate", "internal/store/migrate.go", float64(5)},
+			}}, nil
+		},
+	}
+
+	result, err := FindUntested(context.Background(), client, FindUntestedArgs{Path: "./internal/store/", Limit: 2})
+	if err != nil {
+		t.Fatalf("FindUntested() error = %v", err)
+	}
+	for _, want := range []string{
+		"reached[callee_id] := reached[caller_id], *cie_calls { caller_id, callee_id }",
+		"not reached[id]",
+		`starts_with(file_path, "internal/store/")`,
+		":limit 3",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("FindUntested() query should contain %q, got:\n%s", want, script)
+		}
+	}
+	for _, want := range []string{
+		"## Untested exported functions in `internal/store`",
+		"More than 2 exported functions",
+		"### internal/store/store.go\n\n- `Store.Delete` (line 40)\n- `Open` (line 10)\n",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("FindUntested() should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Contains(result.Text, "Migrate") {
+		t.Errorf("FindUntested() should stop at the limit, got:\n%s", result.Text)
+	}
+}
+
+func TestFindUntested_NoTests(t *testing.T) {
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, s string) (*QueryResult, error) {
+			return &QueryResult{Rows: [][]any{{float64(0)}}}, nil
+		},
+	}
+	result, err := FindUntested(context.Background(), client, FindUntestedArgs{})
+	if err != nil {
+		t.Fatalf("FindUntested() error = %v", err)
+	}
+	if !strings.Contains(result.Text, "No tests are indexed") {
+		t.Errorf("FindUntested() should report missing tests, got:\n%s", result.Text)
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DeploymentTopologyArgs holds arguments for querying the deployment topology.
+type DeploymentTopologyArgs struct {
+	// Target is what to look up: a Go main package ("cmd/worker"), a binary
+	// name ("worker"), a service/resource name or an image name.
+	// Leave empty to list all deployable units.
+	Target string
+
+	// Kind filters listed units by kind (e.g., "dockerfile", "compose_service",
+	// "k8s_deployment", "k8s_service", "terraform_resource").
+	Kind string
+
+	// PathPattern filters listed units by file path using regex.
+	PathPattern string
+
+	// Limit is the maximum number of units to list (default: 100).
+	Limit int
+}
+
+// topologyNode is a deployable unit from cie_topology_node.
+type topologyNode struct {
+	ID         string
+	Kind       string
+	Name       string
+	Image      string
+	Command    string
+	Binary     string
+	Ports      string
+	Labels     string
+	FilePath   string
+	Line       string
+	EntryPoint string // "main (cmd/worker/main.go:12)" from the runs edge
+	EntryDir   string // Directory of the main function
+}
+
+// topologyEdge is an edge from cie_topology_edge between two nodes.
+type topologyEdge struct {
+	From, To, Kind string
+}
+
+// DeploymentTopology answers how code is deployed: which Dockerfiles, compose
+// services, Kubernetes workloads and Terraform resources run a cmd/* binary,
+// which environment variables they receive and which Services expose them.
+//
+// Units are matched directly (by binary, main package, name or image) and
+// through the image they run: a Kubernetes Deployment running the image built
+// by the Dockerfile whose entrypoint is cmd/worker also runs cmd/worker.
+func DeploymentTopology(ctx context.Context, client Querier, args DeploymentTopologyArgs) (*ToolResult, error) {
+	if args.Limit <= 0 {
+		args.Limit = 100
+	}
+
+	var conditions []string
+	if args.Target == "" {
+		if args.Kind != "" {
+			conditions = append(conditions, fmt.Sprintf("kind == %q", args.Kind))
+		}
+		if args.PathPattern != "" {
+			conditions = append(conditions, fmt.Sprintf("regex_matches(file_path, %q)", args.PathPattern))
+		}
+	}
+	nodes, order, err := queryTopologyNodes(ctx, client, conditions)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 && len(conditions) > 0 {
+		return NewResult("No deployable units match the filters.\n"), nil
+	}
+	if len(nodes) == 0 {
+		return NewResult("No deployment topology found.\n\n" +
+			"**Tips:**\n" +
+			"- Topology comes from Dockerfiles, docker-compose files, Kubernetes manifests (.yaml/.yml) and Terraform (.tf)\n" +
+			"- Check that these files are not excluded in `.cie/project.yaml`\n"), nil
+	}
+
+	if args.Target == "" {
+		return NewResult(formatTopologyList(nodes, order, args.Limit)), nil
+	}
+
+	edgesResult, err := client.Query(ctx, "?[from_id, to_id, kind] := *cie_topology_edge { from_id, to_id, kind }")
+	if err != nil {
+		return nil, fmt.Errorf("query topology edges: %w", err)
+	}
+	var edges []topologyEdge
+	for _, row := range edgesResult.Rows {
+		edges = append(edges, topologyEdge{From: AnyToString(row[0]), To: AnyToString(row[1]), Kind: AnyToString(row[2])})
+	}
+
+	target := strings.TrimSuffix(strings.TrimPrefix(args.Target, "./"), "/")
+	running := make(map[string]bool)
+	for _, id := range order {
+		if topologyNodeMatches(nodes[id], target) {
+			running[id] = true
+		}
+	}
+	if len(running) == 0 {
+		return NewResult(fmt.Sprintf("No deployable unit runs `%s`.\n\n"+
+			"**Tips:**\n"+
+			"- Use a main package path (`cmd/worker`), binary name, service name or image name\n"+
+			"- Call without `target` to list all known units and their binaries\n", args.Target)), nil
+	}
+
+	// Units running the image built by a matched Dockerfile run the same binary;
+	// so do Terraform resources referencing a matched resource (ECS service ->
+	// task definition). A matched unit without its own command runs the
+	// entrypoint of the Dockerfile building its image.
+	for changed := true; changed; {
+		changed = false
+		for _, e := range edges {
+			from, to := nodes[e.From], nodes[e.To]
+			if from == nil || to == nil {
+				continue
+			}
+			switch e.Kind {
+			case "image", "builds":
+				if running[e.To] && !running[e.From] {
+					running[e.From] = true
+					changed = true
+				}
+				if running[e.From] && !running[e.To] && from.Binary == "" && to.Binary != "" {
+					running[e.To] = true
+					changed = true
+				}
+			case "depends_on":
+				if from.Kind == "terraform_resource" && running[e.To] && !running[e.From] {
+					running[e.From] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Deployment of `%s`\n\n", args.Target)
+	entryPoints := make(map[string]bool)
+	for _, id := range order {
+		if running[id] && nodes[id].EntryPoint != "" && !entryPoints[nodes[id].EntryPoint] {
+			entryPoints[nodes[id].EntryPoint] = true
+			fmt.Fprintf(&sb, "**Entry point**: %s\n", nodes[id].EntryPoint)
+		}
+	}
+	if len(entryPoints) > 0 {
+		sb.WriteString("\n")
+	}
+
+	var runningIDs []string
+	for _, id := range order {
+		if running[id] {
+			runningIDs = append(runningIDs, id)
+		}
+	}
+	fmt.Fprintf(&sb, "### Runs it (%d)\n\n", len(runningIDs))
+	writeTopologyTable(&sb, nodes, runningIDs)
+
+	sb.WriteString("\n### Environment\n\n")
+	envFound := false
+	for _, id := range runningIDs {
+		envResult, err := client.Query(ctx, fmt.Sprintf(
+			"?[name, value, line] := *cie_topology_env { node_id, name, value, line }, node_id == %q :order line, name", id))
+		if err != nil {
+			return nil, fmt.Errorf("query topology env: %w", err)
+		}
+		if len(envResult.Rows) == 0 {
+			continue
+		}
+		envFound = true
+		n := nodes[id]
+		fmt.Fprintf(&sb, "**%s `%s`** (%s:%s)\n", n.Kind, n.Name, n.FilePath, n.Line)
+		for _, row := range envResult.Rows {
+			name, value := AnyToString(row[0]), AnyToString(row[1])
+			if name == "*" {
+				fmt.Fprintf(&sb, "- all of `%s`\n", value)
+			} else if value == "" {
+				fmt.Fprintf(&sb, "- `%s` (from host environment)\n", name)
+			} else {
+				fmt.Fprintf(&sb, "- `%s` = `%s`\n", name, value)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	if !envFound {
+		sb.WriteString("_No environment variables declared._\n\n")
+	}
+
+	sb.WriteString("### Exposed by\n\n")
+	exposed := false
+	for _, e := range edges {
+		if e.Kind == "exposes" && running[e.To] && nodes[e.From] != nil {
+			exposed = true
+			svc := nodes[e.From]
+			fmt.Fprintf(&sb, "- %s `%s` ports %s → `%s` (%s:%s)\n", svc.Kind, svc.Name, svc.Ports, nodes[e.To].Name, svc.FilePath, svc.Line)
+		}
+	}
+	for _, id := range runningIDs {
+		if n := nodes[id]; n.Kind == "compose_service" && n.Ports != "" {
+			exposed = true
+			fmt.Fprintf(&sb, "- compose_service `%s` publishes %s (%s:%s)\n", n.Name, n.Ports, n.FilePath, n.Line)
+		}
+	}
+	if !exposed {
+		sb.WriteString("_Not exposed by any Kubernetes Service or published compose port._\n")
+	}
+
+	return NewResult(sb.String()), nil
+}
+
+// queryTopologyNodes loads the topology nodes matching conditions with the main
+// function they run. Returns nodes by ID and the IDs in file order.
+func queryTopologyNodes(ctx context.Context, client Querier, conditions []string) (map[string]*topologyNode, []string, error) {
+	script := "?[id, kind, name, image, command, binary, ports, labels, file_path, line] := *cie_topology_node { id, kind, name, image, command, binary, ports, labels, file_path, line }"
+	if len(conditions) > 0 {
+		script += ", " + strings.Join(conditions, ", ")
+	}
+	result, err := client.Query(ctx, script+" :order file_path, line")
+	if err != nil {
+		return nil, nil, fmt.Errorf("query topology nodes: %w", err)
+	}
+
+	nodes := make(map[string]*topologyNode, len(result.Rows))
+	var order []string
+	for _, row := range result.Rows {
+		n := &topologyNode{
+			ID:       AnyToString(row[0]),
+			Kind:     AnyToString(row[1]),
+			Name:     AnyToString(row[2]),
+			Image:    AnyToString(row[3]),
+			Command:  AnyToString(row[4]),
+			Binary:   AnyToString(row[5]),
+			Ports:    AnyToString(row[6]),
+			Labels:   AnyToString(row[7]),
+			FilePath: AnyToString(row[8]),
+			Line:     AnyToString(row[9]),
+		}
+		nodes[n.ID] = n
+		order = append(order, n.ID)
+	}
+
+	// Entry points are optional: without runs edges units still match by binary
+	if runs, err := client.Query(ctx, `?[from_id, name, file_path, start_line] := *cie_topology_edge { from_id, to_id, kind: "runs" }, *cie_function { id: to_id, name, file_path, start_line }`); err == nil {
+		for _, row := range runs.Rows {
+			if n := nodes[AnyToString(row[0])]; n != nil {
+				file := AnyToString(row[2])
+				n.EntryPoint = fmt.Sprintf("%s (%s:%s)", AnyToString(row[1]), file, AnyToString(row[3]))
+				n.EntryDir = path.Dir(file)
+			}
+		}
+	}
+	return nodes, order, nil
+}
+
+// topologyNodeMatches reports whether a node directly runs or is named by target.
+func topologyNodeMatches(n *topologyNode, target string) bool {
+	if n.EntryDir != "" && (n.EntryDir == target || strings.HasSuffix(n.EntryDir, "/"+target)) {
+		return true
+	}
+	if n.Binary != "" {
+		if n.Binary == target || strings.HasSuffix(n.Binary, "/"+target) || strings.HasSuffix(target, "/"+n.Binary) {
+			return true
+		}
+	}
+	name, _, _ := strings.Cut(n.Name, "/")
+	if n.Name == target || name == target {
+		return true
+	}
+	if n.Image != "" {
+		repo, _, _ := strings.Cut(n.Image, "@")
+		if colon := strings.LastIndex(repo, ":"); colon > strings.LastIndex(repo, "/") {
+			repo = repo[:colon]
+		}
+		return repo == target || path.Base(repo) == target
+	}
+	return false
+}
+
+// formatTopologyList lists deployable units grouped by kind.
+func formatTopologyList(nodes map[string]*topologyNode, order []string, limit int) string {
+	ids := append([]string{}, order...)
+	sort.SliceStable(ids, func(i, j int) bool { return nodes[ids[i]].Kind < nodes[ids[j]].Kind })
+
+	truncated := len(ids) > limit
+	if truncated {
+		ids = ids[:limit]
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Deployment Topology (%d units)\n\n", len(ids))
+	writeTopologyTable(&sb, nodes, ids)
+	if truncated {
+		fmt.Fprintf(&sb, "\n⚠️ **Warning:** Results truncated to %d units. Use `kind`, `path_pattern` or `limit` to see more.\n", limit)
+	}
+	sb.WriteString("\n**Tip:** Pass `target` (e.g., `cmd/worker`) to see what runs a binary, its env vars and which Service exposes it.\n")
+	return sb.String()
+}
+
+// writeTopologyTable writes topology nodes as a markdown table.
+func writeTopologyTable(sb *strings.Builder, nodes map[string]*topologyNode, ids []string) {
+	sb.WriteString("| Kind | Name | Image | Runs | Ports | Defined in |\n")
+	sb.WriteString("|------|------|-------|------|-------|------------|\n")
+	for _, id := range ids {
+		n := nodes[id]
+		runs := n.Binary
+		if n.EntryDir != "" {
+			runs = n.EntryDir
+		}
+		fmt.Fprintf(sb, "| %s | `%s` | %s | %s | %s | %s:%s |\n",
+			n.Kind, n.Name, topologyCell(n.Image), topologyCell(runs), topologyCell(n.Ports), n.FilePath, n.Line)
+	}
+}
+
+// topologyCell formats an optional table cell.
+func topologyCell(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// topologyMockClient answers the queries issued by DeploymentTopology by relation.
+func topologyMockClient(queries *[]string) *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			*queries = append(*queries, script)
+			switch {
+			case strings.Contains(script, `kind: "runs"`):
+				return &QueryResult{Rows: [][]any{{"df", "main", "cmd/worker/main.go", float64(12)}}}, nil
+			case strings.Contains(script, "*cie_topology_node"):
+				return &QueryResult{Rows: [][]any{
+					{"df", "dockerfile", "worker", "gcr.io/distroless/static", "/app/worker", "cmd/worker", "9090", "", "deploy/worker/Dockerfile", float64(1)},
+					{"cs", "compose_service", "api", "", "go run ./cmd/api", "cmd/api", "8080:8080", "", "docker-compose.yml", float64(9)},
+					{"dep", "k8s_deployment", "orders-worker", "ghcr.io/acme/worker:1.4", "", "", "9090", "app=orders-worker", "k8s.yaml", float64(13)},
+					{"svc", "k8s_service", "orders-worker", "", "", "", "80:9090", "app=orders-worker", "k8s.yaml", float64(31)},
+					{"td", "terraform_resource", "aws_ecs_task_definition.worker", "ghcr.io/acme/worker:latest", "", "", "", "", "main.tf", float64(1)},
+					{"ecs", "terraform_resource", "aws_ecs_service.worker", "", "", "", "", "", "main.tf", float64(20)},
+				}}, nil
+			case strings.Contains(script, "*cie_topology_edge"):
+				return &QueryResult{Rows: [][]any{
+					{"dep", "df", "image"},
+					{"td", "df", "image"},
+					{"ecs", "td", "depends_on"},
+					{"svc", "dep", "exposes"},
+					{"df", "fn:main", "runs"},
+				}}, nil
+			case strings.Contains(script, "*cie_topology_env") && strings.Contains(script, `"dep"`):
+				return &QueryResult{Rows: [][]any{
+					{"DATABASE_URL", "secret:orders-db/url", float64(20)},
+					{"*", "configmap:orders-config", float64(26)},
+				}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestDeploymentTopology_Target(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	result, err := DeploymentTopology(context.Background(), topologyMockClient(&queries), DeploymentTopologyArgs{Target: "./cmd/worker"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %s", result.Text)
+	}
+
+	for _, want := range []string{
+		"**Entry point**: main (cmd/worker/main.go:12)",
+		"### Runs it (4)",
+		"| dockerfile | `worker` | `gcr.io/distroless/static` | `cmd/worker` | `9090` | deploy/worker/Dockerfile:1 |",
+		"| k8s_deployment | `orders-worker` |",
+		"| terraform_resource | `aws_ecs_service.worker` |",
+		"- `DATABASE_URL` = `secret:orders-db/url`",
+		"- all of `configmap:orders-config`",
+		"- k8s_service `orders-worker` ports 80:9090 → `orders-worker` (k8s.yaml:31)",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Contains(result.Text, "`api`") {
+		t.Errorf("unrelated units should not be listed, got:\n%s", result.Text)
+	}
+}
+
+func TestDeploymentTopology_ByName(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	result, err := DeploymentTopology(context.Background(), topologyMockClient(&queries), DeploymentTopologyArgs{Target: "orders-worker"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result.Text, "| dockerfile | `worker` |") {
+		t.Errorf("a workload without command should pull in the Dockerfile building its image, got:\n%s", result.Text)
+	}
+}
+
+func TestDeploymentTopology_List(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	result, err := DeploymentTopology(context.Background(), topologyMockClient(&queries), DeploymentTopologyArgs{Kind: "compose_service"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(queries[0], `kind == "compose_service"`) {
+		t.Errorf("kind filter should be pushed into the query: %s", queries[0])
+	}
+	if !strings.Contains(result.Text, "## Deployment Topology (6 units)") {
+		t.Errorf("expected listing heading, got:\n%s", result.Text)
+	}
+}
+
+func TestDeploymentTopology_NoMatch(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	result, err := DeploymentTopology(context.Background(), topologyMockClient(&queries), DeploymentTopologyArgs{Target: "cmd/missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result.Text, "No deployable unit runs `cmd/missing`") {
+		t.Errorf("expected no-match message, got:\n%s", result.Text)
+	}
+}
@@ -36,6 +36,7 @@ type TraceFuncInfo struct {
 	CallLine string // Line in the caller where this function is called (empty = unknown)
 	Code     string // Function source code (populated when include_code=true)
 	ViaIface string // Interface name if dispatched via interface (e.g., "Querier")
+	ViaRef   string // Reference kind if used as a function value rather than called (e.g., "argument")
 }
 
 // TracePathArgs holds arguments for tracing call paths
@@ -50,6 +51,7 @@ type TracePathArgs struct {
 	CodeLines    int      // Max lines of code to show per function (default 10)
 	IncludeTypes bool     // If true, embed interface/struct definitions inline
 	TypeLines    int      // Max lines per type definition (default 15)
+	IncludeRefs  bool     // If true, also follow function references (callbacks, handler registration)
 }
 
 // TracePath traces call paths from source function(s) to a target function.
@@ -136,6 +138,7 @@ func traceWithWaypoints(ctx context.Context, client Querier, args TracePathArgs)
 			PathPattern: args.PathPattern,
 			MaxPaths:    1, // Only need one path per segment
 			MaxDepth:    args.MaxDepth,
+			IncludeRefs: args.IncludeRefs,
 		}
 
 		// Find source functions for this segment
@@ -338,6 +341,9 @@ func searchFromSource(ctx context.Context, client Querier, src TraceFuncInfo, ta
 		callees, cached := calleesCache[current.funcName]
 		if !cached {
 			callees = getCallees(ctx, client, current.funcName)
+			if args.IncludeRefs {
+				callees = append(callees, getFuncRefTargets(ctx, client, current.funcName)...)
+			}
 			calleesCache[current.funcName] = callees
 			queries++
 		}
@@ -562,10 +568,17 @@ func formatTracePath(sb *strings.Builder, path []TraceFuncInfo, includeCode bool
 		if fn.ViaIface != "" {
 			nameStr += fmt.Sprintf("  [via interface %s]", fn.ViaIface)
 		}
+		if fn.ViaRef != "" {
+			nameStr += fmt.Sprintf("  [referenced as %s]", fn.ViaRef)
+		}
 		locInfo := fmt.Sprintf("%s:%s", ExtractFileName(fn.FilePath), fn.Line)
 		if fn.CallLine != "" {
 			prevFile := ExtractFileName(path[j-1].FilePath)
-			locInfo += fmt.Sprintf("  [called at %s:%s]", prevFile, fn.CallLine)
+			verb := "called"
+			if fn.ViaRef != "" {
+				verb = "referenced"
+			}
+			locInfo += fmt.Sprintf("  [%s at %s:%s]", verb, prevFile, fn.CallLine)
 		}
 
 		if richMode {
@@ -638,38 +651,43 @@ func writeInlineCode(sb *strings.Builder, fn TraceFuncInfo, indent string) {
 	fmt.Fprintf(sb, "%s   ```\n", indent)
 }
 
+// entryPointPatterns are the entry point functions of each language:
+// Go/Rust main functions, JS/TS functions in index/app/server files and
+// Python __main__ blocks (represented as functions).
+// Note: Use [.] instead of \. for CozoDB regex compatibility
+var entryPointPatterns = []struct {
+	namePattern string
+	filePattern string
+}{
+	// Go: main function
+	{`^main$`, `[.]go$`},
+	// Rust: main function
+	{`^main$`, `[.]rs$`},
+	// JS/TS: common entry point file patterns
+	{`.*`, `(index|app|server|main)[.](js|ts|mjs|cjs)$`},
+	// Python: module entry points
+	{`^(__main__|main)$`, `[.]py$`},
+}
+
+// entryPointTestFiles matches the test files excluded from entry points.
+const entryPointTestFiles = `_test[.]go|test_|[.]test[.](js|ts)`
+
+// entryPointTestExclusion excludes test files from entry point detection.
+const entryPointTestExclusion = `!regex_matches(file_path, "` + entryPointTestFiles + `")`
+
 // detectEntryPoints finds entry point functions based on language conventions
 func detectEntryPoints(ctx context.Context, client Querier, pathPattern string) []TraceFuncInfo {
 	var results []TraceFuncInfo
 
-	// Entry point patterns for different languages
-	// Go/Rust: main functions
-	// JS/TS: exports in index/app/server files
-	// Python: __main__ blocks (represented as functions)
-	// Note: Use [.] instead of \. for CozoDB regex compatibility
-	patterns := []struct {
-		namePattern string
-		filePattern string
-	}{
-		// Go: main function
-		{`^main$`, `[.]go$`},
-		// Rust: main function
-		{`^main$`, `[.]rs$`},
-		// JS/TS: common entry point file patterns
-		{`.*`, `(index|app|server|main)[.](js|ts|mjs|cjs)$`},
-		// Python: module entry points
-		{`^(__main__|main)$`, `[.]py$`},
-	}
-
-	for _, p := range patterns {
+	for _, p := range entryPointPatterns {
 		var conditions []string
 		conditions = append(conditions, fmt.Sprintf("regex_matches(name, %q)", p.namePattern))
 		conditions = append(conditions, fmt.Sprintf("regex_matches(file_path, %q)", p.filePattern))
 		if pathPattern != "" {
 			conditions = append(conditions, fmt.Sprintf("regex_matches(file_path, %q)", pathPattern))
 		}
-		// Exclude test files (use [.] instead of \. for CozoDB compatibility)
-		conditions = append(conditions, `!regex_matches(file_path, "_test[.]go|test_|[.]test[.](js|ts)")`)
+		// Exclude test files
+		conditions = append(conditions, entryPointTestExclusion)
 
 		script := fmt.Sprintf(
 			"?[name, file_path, start_line] := *cie_function { name, file_path, start_line }, %s :limit 20",
@@ -812,6 +830,45 @@ func getCallees(ctx context.Context, client Querier, funcName string) []TraceFun
 	return ret
 }
 
+// getFuncRefTargets returns the functions the given function uses as values
+// (cie_func_ref): callbacks passed as arguments, handlers assigned or
+// returned. CallLine is the line of the reference. Returns nil for indexes
+// without the cie_func_ref table.
+func getFuncRefTargets(ctx context.Context, client Querier, funcName string) []TraceFuncInfo {
+	script := fmt.Sprintf(
+		`?[to_name, to_file, to_line, ref_line, kind] :=
+			*cie_func_ref { from_id, to_id, kind, line: ref_line },
+			*cie_function { id: from_id, name: from_name },
+			*cie_function { id: to_id, file_path: to_file, name: to_name, start_line: to_line },
+			(from_name = %q or ends_with(from_name, %q))
+		:limit 100`,
+		funcName, "."+funcName,
+	)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil
+	}
+
+	var ret []TraceFuncInfo
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		callLine := AnyToString(row[3])
+		if callLine == "0" {
+			callLine = ""
+		}
+		ret = append(ret, TraceFuncInfo{
+			Name:     AnyToString(row[0]),
+			FilePath: AnyToString(row[1]),
+			Line:     AnyToString(row[2]),
+			CallLine: callLine,
+			ViaRef:   AnyToString(row[4]),
+		})
+	}
+	return ret
+}
+
 // getCalleesViaFields resolves callees through struct field types.
 // Phase 2: interface-typed fields → concrete implementations.
 // Phase 2b: concrete-typed fields → direct method lookup.
@@ -0,0 +1,426 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// defaultErrorTraceDepth is how many caller levels are followed from each
+	// origin by default.
+	defaultErrorTraceDepth = 4
+
+	// maxErrorTraceDepth caps the caller levels followed from each origin.
+	maxErrorTraceDepth = 8
+
+	// maxErrorSiteRows caps the rows fetched per error site or caller query.
+	maxErrorSiteRows = 500
+
+	// maxErrorTraceLines caps the lines of the propagation trees.
+	maxErrorTraceLines = 200
+)
+
+// TraceErrorArgs holds arguments for the trace_error tool.
+type TraceErrorArgs struct {
+	// Query is an error message or a fragment of it (e.g., "failed to resolve
+	// project: not found"), or a sentinel, error type or exception name
+	// (e.g., "ErrNotFound", "ValueError").
+	Query string
+
+	// MaxDepth is how many caller levels are followed from each origin
+	// (default 4, max 8).
+	MaxDepth int
+}
+
+// errorSite is one row of cie_error_site with its function.
+type errorSite struct {
+	FunctionID string
+	Func       string // Empty for sentinels
+	Kind       string
+	Name       string
+	Message    string
+	File       string
+	Line       int
+}
+
+// errorCaller is a call to a function that produces or propagates the error.
+type errorCaller struct {
+	ID, Name, File string
+	Line           int
+}
+
+// TraceError finds where an error is created and how it propagates:
+//   - Declarations: the sentinels (var ErrNotFound = errors.New(...)) and
+//     error types (Error() methods) matching the query
+//   - Origins: the functions creating, wrapping, returning or raising it,
+//     including those returning a matched sentinel or constructing a matched
+//     error type
+//   - Propagation: the callers of each origin through cie_calls, up to
+//     MaxDepth levels, with the message a caller wraps the error with
+//
+// A message is split on ": " so that each wrapping layer of a Go error chain
+// ("failed to resolve project: not found") is matched separately. Matching is
+// case-insensitive against messages and format strings as written, so values
+// interpolated at runtime should be left out of the query. Callers in test
+// files are skipped.
+func TraceError(ctx context.Context, client Querier, args TraceErrorArgs) (*ToolResult, error) {
+	query := strings.TrimSpace(args.Query)
+	if query == "" {
+		return NewError("Error: 'query' is required"), nil
+	}
+	depth := args.MaxDepth
+	if depth <= 0 {
+		depth = defaultErrorTraceDepth
+	}
+	if depth > maxErrorTraceDepth {
+		depth = maxErrorTraceDepth
+	}
+
+	sites, err := queryErrorSites(ctx, client, errorQueryCond(query))
+	if err != nil {
+		if strings.Contains(err.Error(), "cie_error_site") {
+			return NewResult("Error sites are not indexed: re-index the project (`cie index`) to record where errors are created.\n"), nil
+		}
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+
+	var declarations, origins []errorSite
+	var declared []string
+	for _, s := range sites {
+		if s.Kind == "sentinel" || s.Kind == "type" {
+			declarations = append(declarations, s)
+			declared = append(declared, s.Name)
+		} else {
+			origins = append(origins, s)
+		}
+	}
+	if len(declared) > 0 {
+		producers, err := queryErrorSites(ctx, client, fmt.Sprintf(`(kind = "return" or kind = "wrap" or kind = "custom"), %s`, errorNameCond(declared)))
+		if err != nil {
+			return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+		}
+		origins = mergeErrorSites(origins, producers)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Error Trace for `%s`\n", query)
+	if len(sites) == 0 {
+		sb.WriteString("\nNo error sites found.\n\n" +
+			"**Tips:**\n" +
+			"- Leave out values interpolated at runtime (IDs, paths, quoted names)\n" +
+			"- Search a shorter fragment of the message, or the sentinel name (e.g., `ErrNotFound`)\n" +
+			"- Use **cie_grep** to search the literal text in code\n")
+		return NewResult(sb.String()), nil
+	}
+
+	writeErrorDeclarations(&sb, declarations)
+	writeErrorOrigins(&sb, origins)
+	if len(origins) == 0 {
+		return NewResult(sb.String()), nil
+	}
+
+	callers, err := queryErrorCallers(ctx, client, origins, depth)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	wraps, err := queryCallerWraps(ctx, client, callers)
+	if err != nil {
+		return NewError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+	writeErrorPropagation(&sb, origins, callers, wraps, depth)
+	return NewResult(sb.String()), nil
+}
+
+// errorQueryCond matches the error sites of a query: each ": "-separated
+// segment against messages, and an identifier against names.
+func errorQueryCond(query string) string {
+	var conds []string
+	for _, segment := range strings.Split(query, ": ") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			conds = append(conds, fmt.Sprintf("str_includes(lowercase(message), %q)", strings.ToLower(segment)))
+		}
+	}
+	if !strings.ContainsAny(query, " :") {
+		conds = append(conds, errorNameCond([]string{query}))
+	}
+	return "(" + strings.Join(conds, " or ") + ")"
+}
+
+// errorNameCond matches error sites named after one of names, qualified or not.
+func errorNameCond(names []string) string {
+	names = append([]string(nil), names...)
+	sort.Strings(names)
+	var conds []string
+	for _, name := range uniqueSorted(names) {
+		conds = append(conds, fmt.Sprintf("name = %q or ends_with(name, %q)", name, "."+name))
+	}
+	return "(" + strings.Join(conds, " or ") + ")"
+}
+
+// queryErrorSites returns the error sites matching cond, with their function.
+// Sentinels have no function and are matched by a second rule.
+func queryErrorSites(ctx context.Context, client Querier, cond string) ([]errorSite, error) {
+	script := fmt.Sprintf(`?[function_id, fn_name, kind, name, message, file_path, line] := *cie_error_site { function_id, kind, name, message, file_path, line },
+  function_id != "", *cie_function { id: function_id, name: fn_name }, %s
+?[function_id, fn_name, kind, name, message, file_path, line] := *cie_error_site { function_id, kind, name, message, file_path, line },
+  function_id = "", fn_name = "", %s
+:order file_path, line :limit %d`, cond, cond, maxErrorSiteRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+
+	var sites []errorSite
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		sites = append(sites, errorSite{
+			FunctionID: AnyToString(row[0]),
+			Func:       AnyToString(row[1]),
+			Kind:       AnyToString(row[2]),
+			Name:       AnyToString(row[3]),
+			Message:    AnyToString(row[4]),
+			File:       AnyToString(row[5]),
+			Line:       rowLine(row[6]),
+		})
+	}
+	return sites, nil
+}
+
+// mergeErrorSites appends the sites of more not already in sites.
+func mergeErrorSites(sites, more []errorSite) []errorSite {
+	seen := make(map[string]bool)
+	for _, s := range sites {
+		seen[fmt.Sprintf("%s:%d:%s", s.File, s.Line, s.Kind)] = true
+	}
+	for _, s := range more {
+		if key := fmt.Sprintf("%s:%d:%s", s.File, s.Line, s.Kind); !seen[key] {
+			seen[key] = true
+			sites = append(sites, s)
+		}
+	}
+	sort.SliceStable(sites, func(i, j int) bool {
+		if sites[i].File != sites[j].File {
+			return sites[i].File < sites[j].File
+		}
+		return sites[i].Line < sites[j].Line
+	})
+	return sites
+}
+
+// errorSiteAction describes what an error site does with the error.
+func errorSiteAction(s errorSite) string {
+	switch s.Kind {
+	case "new":
+		return "errors.New"
+	case "errorf":
+		return "fmt.Errorf"
+	case "wrap":
+		if s.Name != "" {
+			return fmt.Sprintf("wraps `%s`", s.Name)
+		}
+		return "wraps"
+	case "return":
+		return fmt.Sprintf("returns `%s`", s.Name)
+	case "custom":
+		return fmt.Sprintf("constructs `%s`", s.Name)
+	case "raise":
+		return fmt.Sprintf("raises `%s`", s.Name)
+	case "throw":
+		return fmt.Sprintf("throws `%s`", s.Name)
+	}
+	return s.Kind
+}
+
+func writeErrorDeclarations(sb *strings.Builder, declarations []errorSite) {
+	if len(declarations) == 0 {
+		return
+	}
+	sb.WriteString("\n### Declarations\n\n")
+	for _, s := range declarations {
+		kind := "sentinel"
+		if s.Kind == "type" {
+			kind = "error type"
+		}
+		fmt.Fprintf(sb, "- `%s` %s", s.Name, kind)
+		if s.Message != "" {
+			fmt.Fprintf(sb, " — %q", s.Message)
+		}
+		fmt.Fprintf(sb, " (%s:%d)\n", s.File, s.Line)
+	}
+}
+
+func writeErrorOrigins(sb *strings.Builder, origins []errorSite) {
+	sb.WriteString("\n### Origins\n\n")
+	if len(origins) == 0 {
+		sb.WriteString("No function creates or returns this error.\n")
+		return
+	}
+	for _, s := range origins {
+		fmt.Fprintf(sb, "- `%s` %s", s.Func, errorSiteAction(s))
+		if s.Message != "" {
+			fmt.Fprintf(sb, " — %q", s.Message)
+		}
+		fmt.Fprintf(sb, " (%s:%d)\n", s.File, s.Line)
+	}
+}
+
+// queryErrorCallers returns the callers of the origin functions, level by
+// level up to depth, keyed by callee ID.
+func queryErrorCallers(ctx context.Context, client Querier, origins []errorSite, depth int) (map[string][]errorCaller, error) {
+	callers := make(map[string][]errorCaller)
+	visited := make(map[string]bool)
+	var frontier []string
+	for _, s := range origins {
+		if !visited[s.FunctionID] {
+			visited[s.FunctionID] = true
+			frontier = append(frontier, s.FunctionID)
+		}
+	}
+
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		conds := make([]string, len(frontier))
+		for i, id := range frontier {
+			conds[i] = fmt.Sprintf("callee_id = %q", id)
+		}
+		script := fmt.Sprintf(`?[callee_id, caller_id, caller_name, caller_file, call_line] := *cie_calls { caller_id, callee_id, call_line },
+  (%s), *cie_function { id: caller_id, name: caller_name, file_path: caller_file },
+  negate(regex_matches(caller_file, "(?i)(_test[.]go|test[.]ts|test[.]js|_test[.]py|/tests/|/__tests__/)"))
+:order caller_file, call_line :limit %d`, strings.Join(conds, " or "), maxErrorSiteRows)
+		result, err := client.Query(ctx, script)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for _, row := range result.Rows {
+			if len(row) < 5 {
+				continue
+			}
+			calleeID := AnyToString(row[0])
+			caller := errorCaller{ID: AnyToString(row[1]), Name: AnyToString(row[2]), File: AnyToString(row[3]), Line: rowLine(row[4])}
+			callers[calleeID] = append(callers[calleeID], caller)
+			if !visited[caller.ID] {
+				visited[caller.ID] = true
+				next = append(next, caller.ID)
+			}
+		}
+		frontier = next
+	}
+	return callers, nil
+}
+
+// queryCallerWraps returns the wrap sites of the callers, keyed by function ID.
+func queryCallerWraps(ctx context.Context, client Querier, callers map[string][]errorCaller) (map[string][]errorSite, error) {
+	var ids []string
+	for _, cs := range callers {
+		for _, c := range cs {
+			ids = append(ids, c.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	sort.Strings(ids)
+	conds := make([]string, 0, len(ids))
+	for _, id := range uniqueSorted(ids) {
+		conds = append(conds, fmt.Sprintf("function_id = %q", id))
+	}
+
+	script := fmt.Sprintf(`?[function_id, message, line] := *cie_error_site { function_id, kind: "wrap", message, line }, (%s)
+:order function_id, line :limit %d`, strings.Join(conds, " or "), maxErrorSiteRows)
+	result, err := client.Query(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+
+	wraps := make(map[string][]errorSite)
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		id := AnyToString(row[0])
+		wraps[id] = append(wraps[id], errorSite{FunctionID: id, Kind: "wrap", Message: AnyToString(row[1]), Line: rowLine(row[2])})
+	}
+	return wraps, nil
+}
+
+// callerWrap returns the message a caller wraps the error with: its first
+// wrap site at or after the call, where the returned error is checked.
+func callerWrap(wraps []errorSite, callLine int) string {
+	for _, w := range wraps {
+		if callLine > 0 && w.Line >= callLine {
+			return w.Message
+		}
+	}
+	return ""
+}
+
+// writeErrorPropagation writes, for each origin function, the tree of its
+// callers up to depth levels.
+func writeErrorPropagation(sb *strings.Builder, origins []errorSite, callers map[string][]errorCaller, wraps map[string][]errorSite, depth int) {
+	sb.WriteString("\n### Propagation\n")
+	lines := 0
+	var writeCallers func(id string, level int, path map[string]bool)
+	writeCallers = func(id string, level int, path map[string]bool) {
+		for _, c := range callers[id] {
+			if lines >= maxErrorTraceLines {
+				return
+			}
+			lines++
+			fmt.Fprintf(sb, "%s- ← `%s` (%s:%d)", strings.Repeat("  ", level), c.Name, c.File, c.Line)
+			if msg := callerWrap(wraps[c.ID], c.Line); msg != "" {
+				fmt.Fprintf(sb, " — wraps: %q", msg)
+			}
+			if path[c.ID] {
+				sb.WriteString(" (recursive)\n")
+				continue
+			}
+			sb.WriteString("\n")
+			if level+1 < depth {
+				path[c.ID] = true
+				writeCallers(c.ID, level+1, path)
+				delete(path, c.ID)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range origins {
+		if seen[s.FunctionID] {
+			continue
+		}
+		seen[s.FunctionID] = true
+		fmt.Fprintf(sb, "\n**%s** (%s:%d)\n", s.Func, s.File, s.Line)
+		if len(callers[s.FunctionID]) == 0 {
+			sb.WriteString("- No indexed callers: entry point, or called dynamically\n")
+			continue
+		}
+		writeCallers(s.FunctionID, 0, map[string]bool{s.FunctionID: true})
+	}
+	if lines >= maxErrorTraceLines {
+		fmt.Fprintf(sb, "\n_Propagation truncated at %d callers: lower `max_depth` to narrow it._\n", maxErrorTraceLines)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// errorTraceMockClient serves a sentinel ErrNotFound returned by Store.Get,
+// which Resolve calls and wraps with "failed to resolve project %q: %w", and
+// which runCmd calls in turn.
+func errorTraceMockClient(scripts *[]string) *MockCIEClient {
+	return &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			*scripts = append(*scripts, script)
+			switch {
+			case strings.Contains(script, `kind: "wrap"`):
+				return &QueryResult{Rows: [][]any{
+					{"fn:resolve", "failed to resolve project %q: %w", float64(31)},
+				}}, nil
+			case strings.Contains(script, "*cie_calls"):
+				result := &QueryResult{}
+				if strings.Contains(script, `callee_id = "fn:get"`) {
+					result.Rows = append(result.Rows, []any{"fn:get", "fn:resolve", "Resolve", "internal/project/resolve.go", float64(30)})
+				}
+				if strings.Contains(script, `callee_id = "fn:resolve"`) {
+					result.Rows = append(result.Rows, []any{"fn:resolve", "fn:run", "runCmd", "cmd/app/run.go", float64(12)})
+				}
+				return result, nil
+			case strings.Contains(script, `kind = "return"`):
+				return &QueryResult{Rows: [][]any{
+					{"fn:get", "Store.Get", "return", "ErrNotFound", "", "internal/store/store.go", float64(25)},
+				}}, nil
+			case strings.Contains(script, "*cie_error_site"):
+				return &QueryResult{Rows: [][]any{
+					{"fn:resolve", "Resolve", "wrap", "", "failed to resolve project %q: %w", "internal/project/resolve.go", float64(31)},
+					{"", "", "sentinel", "ErrNotFound", "not found", "internal/store/store.go", float64(9)},
+				}}, nil
+			}
+			return &QueryResult{}, nil
+		},
+	}
+}
+
+func TestTraceError(t *testing.T) {
+	var scripts []string
+	result, err := TraceError(context.Background(), errorTraceMockClient(&scripts), TraceErrorArgs{Query: "failed to resolve project: not found"})
+	if err != nil {
+		t.Fatalf("TraceError() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`str_includes(lowercase(message), "failed to resolve project") or str_includes(lowercase(message), "not found")`,
+		`(name = "ErrNotFound" or ends_with(name, ".ErrNotFound"))`,
+	} {
+		if !strings.Contains(strings.Join(scripts, "\n"), want) {
+			t.Errorf("TraceError() queries should contain %q, got:\n%s", want, strings.Join(scripts, "\n"))
+		}
+	}
+	for _, want := range []string{
+		"### Declarations\n\n- `ErrNotFound` sentinel — \"not found\" (internal/store/store.go:9)\n",
+		"- `Resolve` wraps — \"failed to resolve project %q: %w\" (internal/project/resolve.go:31)\n",
+		"- `Store.Get` returns `ErrNotFound` (internal/store/store.go:25)\n",
+		"**Store.Get** (internal/store/store.go:25)\n- ← `Resolve` (internal/project/resolve.go:30) — wraps: \"failed to resolve project %q: %w\"\n  - ← `runCmd` (cmd/app/run.go:12)\n",
+		"**Resolve** (internal/project/resolve.go:31)\n- ← `runCmd` (cmd/app/run.go:12)\n",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("TraceError() should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+}
+
+func TestTraceError_MaxDepth(t *testing.T) {
+	var scripts []string
+	result, err := TraceError(context.Background(), errorTraceMockClient(&scripts), TraceErrorArgs{Query: "ErrNotFound", MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("TraceError() error = %v", err)
+	}
+	if strings.Contains(result.Text, "  - ← `runCmd`") {
+		t.Errorf("TraceError() should stop at max_depth, got:\n%s", result.Text)
+	}
+	if !strings.Contains(scripts[0], `name = "ErrNotFound"`) {
+		t.Errorf("TraceError() should match the sentinel name, got:\n%s", scripts[0])
+	}
+}
+
+func TestTraceError_Errors(t *testing.T) {
+	result, err := TraceError(context.Background(), &MockCIEClient{}, TraceErrorArgs{})
+	if err != nil || !result.IsError {
+		t.Errorf("TraceError() without query should return an error result, got %+v, %v", result, err)
+	}
+
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			return nil, errors.New("stored relation 'cie_error_site' not found")
+		},
+	}
+	result, err = TraceError(context.Background(), client, TraceErrorArgs{Query: "not found"})
+	if err != nil {
+		t.Fatalf("TraceError() error = %v", err)
+	}
+	if !strings.Contains(result.Text, "re-index the project") {
+		t.Errorf("TraceError() should ask to re-index, got:\n%s", result.Text)
+	}
+
+	empty := &MockCIEClient{QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+		return &QueryResult{}, nil
+	}}
+	result, err = TraceError(context.Background(), empty, TraceErrorArgs{Query: "not found"})
+	if err != nil {
+		t.Fatalf("TraceError() error = %v", err)
+	}
+	if !strings.Contains(result.Text, "No error sites found.") {
+		t.Errorf("TraceError() should report no sites, got:\n%s", result.Text)
+	}
+}
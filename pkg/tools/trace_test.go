@@ -236,6 +236,56 @@ func TestTracePath_Unit_SimplePath(t *testing.T) {
 	}
 }
 
+// Test TracePath with include_refs following a handler registered as a callback
+func TestTracePath_Unit_IncludeRefs(t *testing.T) {
+	functions := map[string]TraceFuncInfo{
+		"main":              {Name: "main", FilePath: "cmd/main.go", Line: "1"},
+		"Server.Register":   {Name: "Server.Register", FilePath: "internal/server/routes.go", Line: "20"},
+		"Handler.ListUsers": {Name: "Handler.ListUsers", FilePath: "internal/handlers/users.go", Line: "30"},
+		"saveToDb":          {Name: "saveToDb", FilePath: "internal/db.go", Line: "40"},
+	}
+	callGraph := map[string][]string{
+		"main":              {"Server.Register"},
+		"Handler.ListUsers": {"saveToDb"},
+	}
+	graph := createMockCallGraph(functions, callGraph)
+	client := NewMockClientCustom(
+		func(ctx context.Context, script string) (*QueryResult, error) {
+			if strings.Contains(script, "cie_func_ref") {
+				if !strings.Contains(script, `"Server.Register"`) {
+					return &QueryResult{Headers: []string{}, Rows: [][]any{}}, nil
+				}
+				return &QueryResult{
+					Headers: []string{"to_name", "to_file", "to_line", "ref_line", "kind"},
+					Rows:    [][]any{{"Handler.ListUsers", "internal/handlers/users.go", "30", "22", "argument"}},
+				}, nil
+			}
+			return graph.Query(ctx, script)
+		},
+		nil,
+	)
+	ctx := context.Background()
+
+	// Without include_refs the handler is unreachable
+	result, err := TracePath(ctx, client, TracePathArgs{Target: "saveToDb", Source: "main", MaxPaths: 3, MaxDepth: 10})
+	if err != nil {
+		t.Fatalf("TracePath() error = %v", err)
+	}
+	if strings.Contains(result.Text, "Handler.ListUsers") {
+		t.Errorf("TracePath() without include_refs should not reach Handler.ListUsers, got:\n%s", result.Text)
+	}
+
+	result, err = TracePath(ctx, client, TracePathArgs{Target: "saveToDb", Source: "main", MaxPaths: 3, MaxDepth: 10, IncludeRefs: true})
+	if err != nil {
+		t.Fatalf("TracePath() error = %v", err)
+	}
+	for _, want := range []string{"Handler.ListUsers  [referenced as argument]", "[referenced at routes.go:22]", "saveToDb"} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("TracePath() with include_refs should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+}
+
 // Test TracePath with disconnected graph (no path exists)
 func TestTracePath_Unit_DisconnectedGraph(t *testing.T) {
 	functions := map[string]TraceFuncInfo{
@@ -0,0 +1,111 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FindVariableArgs holds arguments for the find_variable tool.
+type FindVariableArgs struct {
+	Name        string // Variable name to search for
+	Kind        string // Filter by kind: "any", "var", "const", "let"
+	PathPattern string // Optional file path filter
+	IncludeCode bool   // If true, include the declaration source
+	Limit       int    // Max results (default 20)
+}
+
+// FindVariable searches package-level variables and constants by name: Go
+// var/const declarations, Python module-level assignments and exported JS/TS
+// const/let/var declarations.
+func FindVariable(ctx context.Context, client Querier, args FindVariableArgs) (*ToolResult, error) {
+	if args.Name == "" {
+		return NewError("Error: 'name' is required"), nil
+	}
+
+	if args.Limit <= 0 {
+		args.Limit = 20
+	}
+
+	conditions := []string{fmt.Sprintf("regex_matches(name, %q)", "(?i)"+EscapeRegex(args.Name))}
+	if args.Kind != "" && args.Kind != "any" {
+		conditions = append(conditions, fmt.Sprintf("kind == %q", args.Kind))
+	}
+	if args.PathPattern != "" {
+		conditions = append(conditions, fmt.Sprintf("regex_matches(file_path, %q)", args.PathPattern))
+	}
+
+	query := fmt.Sprintf(
+		"?[name, kind, type, value, file_path, start_line, code_text] := *cie_variable { name, kind, type, value, file_path, start_line, code_text }, %s :order file_path, start_line :limit %d",
+		strings.Join(conditions, ", "),
+		args.Limit,
+	)
+
+	result, err := client.Query(ctx, query)
+	if err != nil {
+		errStr := err.Error()
+		if strings.Contains(errStr, "cie_variable") && strings.Contains(errStr, "not found") {
+			return NewError("Table 'cie_variable' not found. Re-index is required to use this tool.\n\n" +
+				"Run: `cie index --path /path/to/repo` to rebuild the index with variable support."), nil
+		}
+		return NewError(fmt.Sprintf("Query failed: %v\n\nQuery: %s", err, query)), nil
+	}
+
+	if len(result.Rows) == 0 {
+		return NewResult(fmt.Sprintf("No variables or constants found matching '%s'\n\n"+
+			"### Tips:\n"+
+			"- Only package-level declarations are indexed (not locals or struct fields)\n"+
+			"- JS/TS declarations are indexed only when exported\n"+
+			"- Use **cie_grep** to search initializer values", args.Name)), nil
+	}
+
+	output := fmt.Sprintf("### Variables matching '%s'\n\n", args.Name)
+	if args.Kind != "" && args.Kind != "any" {
+		output = fmt.Sprintf("### %s declarations matching '%s'\n\n", args.Kind, args.Name)
+	}
+
+	for i, row := range result.Rows {
+		name := AnyToString(row[0])
+		kind := AnyToString(row[1])
+		typ := AnyToString(row[2])
+		value := AnyToString(row[3])
+		filePath := AnyToString(row[4])
+
+		output += fmt.Sprintf("%d. **%s** (%s", i+1, name, kind)
+		if typ != "" {
+			output += fmt.Sprintf(" `%s`", typ)
+		}
+		output += ")\n"
+		output += fmt.Sprintf("   File: %s:%s\n", filePath, AnyToString(row[5]))
+		if value != "" {
+			output += fmt.Sprintf("   Value: `%s`\n", value)
+		}
+		if args.IncludeCode {
+			if codeText := AnyToString(row[6]); codeText != "" {
+				output += fmt.Sprintf("   ```%s\n   %s\n   ```\n", detectLanguage(filePath), codeText)
+			}
+		}
+		output += "\n"
+	}
+
+	return NewResult(output), nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 KrakLabs
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+//
+// For commercial licensing, contact: licensing@kraklabs.com
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFindVariable(t *testing.T) {
+	t.Parallel()
+
+	var query string
+	client := &MockCIEClient{
+		QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+			query = script
+			return &QueryResult{Rows: [][]any{
+				{"DefaultTimeout", "const", "time.Duration", "30 * time.Second", "pkg/llm/client.go", float64(31), "DefaultTimeout time.Duration = 30 * time.Second"},
+				{"REQUEST_TIMEOUT", "const", "", "5000", "web/config.ts", float64(4), "REQUEST_TIMEOUT = 5000"},
+			}}, nil
+		},
+	}
+
+	result, err := FindVariable(context.Background(), client, FindVariableArgs{Name: "Timeout", Kind: "const", PathPattern: "pkg/"})
+	if err != nil {
+		t.Fatalf("FindVariable() error = %v", err)
+	}
+	for _, want := range []string{"*cie_variable", `kind == "const"`, `regex_matches(file_path, "pkg/")`, ":limit 20"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query should contain %q, got: %s", want, query)
+		}
+	}
+	for _, want := range []string{
+		"### const declarations matching 'Timeout'",
+		"1. **DefaultTimeout** (const `time.Duration`)",
+		"File: pkg/llm/client.go:31",
+		"Value: `30 * time.Second`",
+		"2. **REQUEST_TIMEOUT** (const)",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Contains(result.Text, "```go") {
+		t.Error("output should not include code unless include_code is set")
+	}
+}
+
+func TestFindVariable_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    FindVariableArgs
+		err     error
+		wantErr bool
+		want    string
+	}{
+		{name: "missing name", args: FindVariableArgs{}, wantErr: true, want: "'name' is required"},
+		{name: "no results", args: FindVariableArgs{Name: "Missing"}, want: "No variables or constants found matching 'Missing'"},
+		{name: "old index", args: FindVariableArgs{Name: "X"}, err: errors.New("stored relation cie_variable not found"), wantErr: true, want: "Re-index is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := &MockCIEClient{
+				QueryFunc: func(ctx context.Context, script string) (*QueryResult, error) {
+					return &QueryResult{}, tt.err
+				},
+			}
+			result, err := FindVariable(context.Background(), client, tt.args)
+			if err != nil {
+				t.Fatalf("FindVariable() error = %v", err)
+			}
+			if result.IsError != tt.wantErr {
+				t.Errorf("IsError = %v, want %v", result.IsError, tt.wantErr)
+			}
+			if !strings.Contains(result.Text, tt.want) {
+				t.Errorf("output should contain %q, got:\n%s", tt.want, result.Text)
+			}
+		})
+	}
+}